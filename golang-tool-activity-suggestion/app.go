@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Suggest indoor or outdoor activities that suit the current weather at a location, e.g. "what can I do in Lisbon today?". If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns the current weather, whether it is a day to stay indoors and why, and a few activities.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "activity-suggestion", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xCC}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := SuggestAt(reqCtx, msg.Latitude, msg.Longitude)
+	if err != nil {
+		slog.Warn("[sfn] SuggestAt error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not suggest an activity: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// SuggestAt describes the current weather at lat,lon and the activities that
+// suit it.
+func SuggestAt(ctx context.Context, lat, lon float64) (string, error) {
+	conditions, err := client.Current(ctx, lat, lon)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("The current weather is %s. %s", conditions.Summary(), Suggest(conditions)), nil
+}
+
+// The thresholds of the rules, temperatures use the feels like temperature.
+const (
+	extremeHeatC = 35
+	warmC        = 24
+	mildC        = 12
+	extremeColdC = -15
+	// galeMS is a near gale on the Beaufort scale, too strong to walk
+	// comfortably.
+	galeMS = 14
+	// breezyMS is a fresh breeze, enough to fly a kite.
+	breezyMS = 8
+	// heavyRainMM is heavy rain in the last hour.
+	heavyRainMM = 4
+)
+
+// Suggestion tells whether the weather is for indoors or outdoors, and
+// suggests activities.
+type Suggestion struct {
+	Indoors bool
+	// Reason explains why to stay indoors, or what makes the weather
+	// pleasant.
+	Reason     string
+	Activities []string
+}
+
+func (s Suggestion) String() string {
+	where := "a good time to be outdoors"
+	if s.Indoors {
+		where = "better to stay indoors"
+	}
+	return fmt.Sprintf("It is %s because of %s. Suggested activities: %s.", where, s.Reason, strings.Join(s.Activities, ", "))
+}
+
+// Suggest maps the weather to activities. The first rule that matches wins,
+// hazards come first so that e.g. a hot thunderstorm is a storm.
+func Suggest(c *weather.Conditions) Suggestion {
+	group := c.ConditionID / 100
+	switch {
+	case group == 2:
+		return Suggestion{Indoors: true, Reason: "a thunderstorm", Activities: []string{"visit a museum", "watch a movie", "play board games", "try indoor climbing"}}
+	case c.ConditionID == 771 || c.ConditionID == 781:
+		return Suggestion{Indoors: true, Reason: "squalls or a tornado", Activities: []string{"stay in a sheltered place", "play board games", "read a book"}}
+	case c.WindSpeed >= galeMS:
+		return Suggestion{Indoors: true, Reason: fmt.Sprintf("strong wind of %.1f m/s", c.WindSpeed), Activities: []string{"visit a museum", "go bowling", "cook something new"}}
+	case c.FeelsLike >= extremeHeatC:
+		return Suggestion{Indoors: true, Reason: fmt.Sprintf("extreme heat of %.1f°C", c.FeelsLike), Activities: []string{"swim in an indoor pool", "visit an air-conditioned museum or mall", "go outside only early in the morning or in the evening"}}
+	case c.FeelsLike <= extremeColdC:
+		return Suggestion{Indoors: true, Reason: fmt.Sprintf("extreme cold of %.1f°C", c.FeelsLike), Activities: []string{"go to a sauna or a spa", "visit a museum", "bake something warm"}}
+	case group == 7 && c.ConditionID != 701 && c.ConditionID != 741:
+		// smoke, haze, dust, sand or ash, mist and fog are harmless
+		return Suggestion{Indoors: true, Reason: "poor air: " + c.Description, Activities: []string{"work out at a gym", "visit a museum", "watch a movie"}}
+	case group == 6 || c.Snow1h > 0:
+		return Suggestion{Reason: "snow", Activities: []string{"go sledding", "build a snowman", "go skiing", "have a snowball fight"}}
+	case c.Rain1h >= heavyRainMM:
+		return Suggestion{Indoors: true, Reason: "heavy rain", Activities: []string{"visit a museum", "watch a movie", "go to a café", "try indoor climbing"}}
+	case group == 3 || group == 5 || c.Rain1h > 0:
+		return Suggestion{Indoors: true, Reason: "rain", Activities: []string{"visit a museum or a gallery", "go to a café", "go for a walk with an umbrella if it is light"}}
+	case c.FeelsLike >= warmC:
+		return Suggestion{Reason: fmt.Sprintf("warm and dry weather of %.1f°C", c.FeelsLike), Activities: []string{"go to the beach or a lake", "swim outdoors", "have a picnic in the shade"}}
+	case c.FeelsLike >= mildC && c.WindSpeed >= breezyMS:
+		return Suggestion{Reason: fmt.Sprintf("mild and breezy weather of %.1f°C", c.FeelsLike), Activities: []string{"fly a kite", "go sailing or windsurfing", "take a walk"}}
+	case c.FeelsLike >= mildC:
+		return Suggestion{Reason: fmt.Sprintf("mild and dry weather of %.1f°C", c.FeelsLike), Activities: []string{"go hiking", "ride a bike", "have a picnic", "go for a run"}}
+	case c.FeelsLike >= 0:
+		return Suggestion{Reason: fmt.Sprintf("cool and dry weather of %.1f°C", c.FeelsLike), Activities: []string{"take a brisk walk", "go hiking in warm layers", "visit an outdoor market"}}
+	default:
+		return Suggestion{Reason: fmt.Sprintf("cold and dry weather of %.1f°C", c.FeelsLike), Activities: []string{"go ice skating", "take a winter walk", "enjoy a hot drink outside"}}
+	}
+}
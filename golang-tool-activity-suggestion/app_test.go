@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions weather.Conditions
+		indoors    bool
+		reason     string
+	}{
+		{"thunderstorm", weather.Conditions{ConditionID: 211, FeelsLike: 22, WindSpeed: 9, Rain1h: 12}, true, "a thunderstorm"},
+		{"hot thunderstorm", weather.Conditions{ConditionID: 201, FeelsLike: 38}, true, "a thunderstorm"},
+		{"tornado", weather.Conditions{ConditionID: 781, FeelsLike: 25}, true, "squalls or a tornado"},
+		{"gale", weather.Conditions{ConditionID: 803, FeelsLike: 15, WindSpeed: 18.5}, true, "strong wind of 18.5 m/s"},
+		{"extreme heat", weather.Conditions{ConditionID: 800, FeelsLike: 41.3}, true, "extreme heat of 41.3°C"},
+		{"extreme cold", weather.Conditions{ConditionID: 800, FeelsLike: -24}, true, "extreme cold of -24.0°C"},
+		{"smoke", weather.Conditions{ConditionID: 711, Description: "smoke", FeelsLike: 20}, true, "poor air: smoke"},
+		{"fog", weather.Conditions{ConditionID: 741, FeelsLike: 14}, false, "mild and dry weather of 14.0°C"},
+		{"snow", weather.Conditions{ConditionID: 601, FeelsLike: -3, Snow1h: 1.5}, false, "snow"},
+		{"heavy rain", weather.Conditions{ConditionID: 502, FeelsLike: 16, Rain1h: 8}, true, "heavy rain"},
+		{"light rain", weather.Conditions{ConditionID: 500, FeelsLike: 16, Rain1h: 0.3}, true, "rain"},
+		{"drizzle", weather.Conditions{ConditionID: 300, FeelsLike: 10}, true, "rain"},
+		{"warm", weather.Conditions{ConditionID: 800, FeelsLike: 28}, false, "warm and dry weather of 28.0°C"},
+		{"breezy", weather.Conditions{ConditionID: 801, FeelsLike: 18, WindSpeed: 10}, false, "mild and breezy weather of 18.0°C"},
+		{"mild", weather.Conditions{ConditionID: 802, FeelsLike: 18, WindSpeed: 3}, false, "mild and dry weather of 18.0°C"},
+		{"cool", weather.Conditions{ConditionID: 804, FeelsLike: 5}, false, "cool and dry weather of 5.0°C"},
+		{"cold", weather.Conditions{ConditionID: 800, FeelsLike: -6}, false, "cold and dry weather of -6.0°C"},
+	}
+	for _, tt := range tests {
+		got := Suggest(&tt.conditions)
+		if got.Indoors != tt.indoors || got.Reason != tt.reason || len(got.Activities) == 0 {
+			t.Errorf("%s: Suggest() = %+v, want indoors %v because of %q", tt.name, got, tt.indoors, tt.reason)
+		}
+	}
+}
+
+func TestSuggestAt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Lisbon","sys":{"country":"PT"},"weather":[{"id":800,"description":"clear sky"}],"main":{"temp":27.1,"feels_like":27.5,"humidity":40},"wind":{"speed":3.1},"clouds":{"all":0}}`)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := SuggestAt(context.Background(), 38.72, -9.14)
+	if err != nil {
+		t.Fatalf("SuggestAt() error = %v", err)
+	}
+	want := "The current weather is Lisbon, PT: clear sky, 27.1°C (feels like 27.5°C), humidity 40%, wind 3.1 m/s, clouds 0%. It is a good time to be outdoors because of warm and dry weather of 27.5°C. Suggested activities: go to the beach or a lake, swim outdoors, have a picnic in the shade."
+	if got != want {
+		t.Errorf("SuggestAt() =\n%s\nwant\n%s", got, want)
+	}
+}
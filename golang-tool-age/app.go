@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the exact age of a person or thing from its birthdate, in years, months and days, and how many days remain until the next birthday. The dates are in the format YYYY-MM-DD. By default the age is computed as of today, pass asOf to compute it at another date.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Birthdate string `json:"birthdate" jsonschema:"description=The birthdate in the format YYYY-MM-DD,example=1990-05-17"`
+	AsOf      string `json:"asOf,omitempty" jsonschema:"description=The date to compute the age at in the format YYYY-MM-DD. Defaults to today"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "age", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xCA}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "birthdate", msg.Birthdate, "as_of", msg.AsOf)
+
+	age, err := Compute(msg.Birthdate, msg.AsOf, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] Compute error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the age: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, age.String())
+}
+
+const dateFormat = "2006-01-02"
+
+// Age is the time elapsed since a birthdate.
+type Age struct {
+	Birthdate, AsOf     time.Time
+	Years, Months, Days int
+	NextBirthday        time.Time
+	DaysToNextBirthday  int
+}
+
+func (a *Age) String() string {
+	s := fmt.Sprintf("Born on %s, the age on %s is %s, %s and %s.", a.Birthdate.Format(dateFormat), a.AsOf.Format(dateFormat),
+		plural(a.Years, "year"), plural(a.Months, "month"), plural(a.Days, "day"))
+	if a.DaysToNextBirthday == 0 {
+		return s + " Today is the birthday!"
+	}
+	return s + fmt.Sprintf(" The next birthday is on %s, in %s.", a.NextBirthday.Format("Monday, 2006-01-02"), plural(a.DaysToNextBirthday, "day"))
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// ParseDate parses a date in the format YYYY-MM-DD, or the date part of an
+// RFC 3339 timestamp in its own offset.
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if d, err := time.Parse(dateFormat, value); err == nil {
+		return d, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date in the format YYYY-MM-DD", value)
+	}
+	return date(t.Year(), t.Month(), t.Day()), nil
+}
+
+// Compute returns the age at asOf of someone born on birthdate. An empty
+// asOf is the date of now.
+func Compute(birthdate, asOf string, now time.Time) (*Age, error) {
+	birth, err := ParseDate(birthdate)
+	if err != nil {
+		return nil, err
+	}
+	at := date(now.Year(), now.Month(), now.Day())
+	if asOf != "" {
+		if at, err = ParseDate(asOf); err != nil {
+			return nil, err
+		}
+	}
+	if at.Before(birth) {
+		return nil, fmt.Errorf("the birthdate %s is after %s", birth.Format(dateFormat), at.Format(dateFormat))
+	}
+
+	age := &Age{Birthdate: birth, AsOf: at}
+
+	// count the whole months first, then the days left
+	months := (at.Year()-birth.Year())*12 + int(at.Month()-birth.Month())
+	if addMonths(birth, months).After(at) {
+		months--
+	}
+	age.Years, age.Months = months/12, months%12
+	age.Days = days(addMonths(birth, months), at)
+
+	age.NextBirthday = anniversary(birth, at.Year())
+	if age.NextBirthday.Before(at) {
+		age.NextBirthday = anniversary(birth, at.Year()+1)
+	}
+	age.DaysToNextBirthday = days(at, age.NextBirthday)
+	return age, nil
+}
+
+// addMonths adds n months to d, clamping the day to the end of a shorter
+// month: a month after January 31 is the last day of February.
+func addMonths(d time.Time, n int) time.Time {
+	first := date(d.Year(), d.Month()+time.Month(n), 1)
+	last := first.AddDate(0, 1, -1).Day()
+	return date(first.Year(), first.Month(), min(d.Day(), last))
+}
+
+// anniversary returns the birthday of birth in year. A February 29
+// birthday is celebrated on February 28 in common years.
+func anniversary(birth time.Time, year int) time.Time {
+	return addMonths(birth, (year-birth.Year())*12)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// days returns the number of days from a to b, both at midnight UTC.
+func days(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
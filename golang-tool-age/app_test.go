@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name                string
+		birthdate, asOf     string
+		years, months, days int
+		next                string
+		toNext              int
+	}{
+		{
+			name: "normal", birthdate: "1990-05-17", asOf: "2024-03-10",
+			years: 33, months: 9, days: 22, next: "2024-05-17", toNext: 68,
+		},
+		{
+			name: "birthday passed this year", birthdate: "1990-05-17", asOf: "2024-08-01",
+			years: 34, months: 2, days: 15, next: "2025-05-17", toNext: 289,
+		},
+		{
+			name: "across a month boundary", birthdate: "2000-01-31", asOf: "2000-03-01",
+			years: 0, months: 1, days: 1, next: "2001-01-31", toNext: 336,
+		},
+		{
+			name: "end of a shorter month", birthdate: "2023-08-31", asOf: "2023-09-30",
+			years: 0, months: 1, days: 0, next: "2024-08-31", toNext: 336,
+		},
+		{
+			name: "birthday today", birthdate: "1990-05-17", asOf: "2024-05-17",
+			years: 34, months: 0, days: 0, next: "2024-05-17", toNext: 0,
+		},
+		{
+			name: "leap day in a common year", birthdate: "2000-02-29", asOf: "2023-02-28",
+			years: 23, months: 0, days: 0, next: "2023-02-28", toNext: 0,
+		},
+		{
+			name: "leap day before the birthday", birthdate: "2000-02-29", asOf: "2023-02-27",
+			years: 22, months: 11, days: 29, next: "2023-02-28", toNext: 1,
+		},
+		{
+			name: "leap day in a leap year", birthdate: "2000-02-29", asOf: "2024-03-01",
+			years: 24, months: 0, days: 1, next: "2025-02-28", toNext: 364,
+		},
+		{
+			name: "rfc3339", birthdate: "1990-05-17T23:30:00-05:00", asOf: "2024-05-16T08:00:00Z",
+			years: 33, months: 11, days: 29, next: "2024-05-17", toNext: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compute(tt.birthdate, tt.asOf, time.Now())
+			if err != nil {
+				t.Fatalf("Compute() error = %v", err)
+			}
+			if got.Years != tt.years || got.Months != tt.months || got.Days != tt.days {
+				t.Errorf("Compute() = %dy %dm %dd, want %dy %dm %dd", got.Years, got.Months, got.Days, tt.years, tt.months, tt.days)
+			}
+			if next := got.NextBirthday.Format(dateFormat); next != tt.next || got.DaysToNextBirthday != tt.toNext {
+				t.Errorf("next birthday = %s in %d days, want %s in %d days", next, got.DaysToNextBirthday, tt.next, tt.toNext)
+			}
+		})
+	}
+}
+
+func TestComputeDefaultsToToday(t *testing.T) {
+	now := time.Date(2024, 3, 10, 22, 0, 0, 0, time.UTC)
+	got, err := Compute("1990-05-17", "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Born on 1990-05-17, the age on 2024-03-10 is 33 years, 9 months and 22 days. The next birthday is on Friday, 2024-05-17, in 68 days."
+	if got.String() != want {
+		t.Errorf("Compute() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestComputeErrors(t *testing.T) {
+	for _, args := range [][2]string{
+		{"17/05/1990", ""},
+		{"", ""},
+		{"1990-05-17", "yesterday"},
+		{"2025-01-01", "2024-01-01"},
+	} {
+		if _, err := Compute(args[0], args[1], time.Now()); err == nil {
+			t.Errorf("Compute(%q, %q) should fail", args[0], args[1])
+		}
+	}
+}
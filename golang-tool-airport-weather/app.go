@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/airports"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the current weather at an airport given its 3-letter IATA code, e.g. "what is the weather at LHR?". If the user names the airport instead, e.g. "Heathrow", pass its IATA code. The function returns the airport and its current weather conditions.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	IATA string `json:"iata" jsonschema:"description=The 3-letter IATA code of the airport,example=LHR"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "airport-weather", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC7}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "iata", msg.IATA)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := AirportWeather(reqCtx, msg.IATA)
+	if err != nil {
+		slog.Warn("[sfn] AirportWeather error", "iata", msg.IATA, "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather at the airport %s: %v", msg.IATA, err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// AirportWeather resolves an IATA code to its airport and describes the
+// current weather there.
+func AirportWeather(ctx context.Context, code string) (string, error) {
+	code, err := airports.ParseCode(code)
+	if err != nil {
+		return "", err
+	}
+	airport, ok := airports.Lookup(code)
+	if !ok {
+		return "", fmt.Errorf("%s is not a known airport, ask for the weather of its city instead", code)
+	}
+
+	conditions, err := client.Current(ctx, airport.Latitude, airport.Longitude)
+	if err != nil {
+		return "", err
+	}
+	// the name of the OpenWeatherMap station is not the airport, describe
+	// the airport instead
+	conditions.City, conditions.Country = "", ""
+	return fmt.Sprintf("The current weather at %s is %s", airport, conditions.Summary()), nil
+}
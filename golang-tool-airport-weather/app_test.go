@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestAirportWeather(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("lat")+","+r.URL.Query().Get("lon"))
+		fmt.Fprint(w, `{"name":"Hounslow","sys":{"country":"GB"},"weather":[{"description":"light rain"}],"main":{"temp":11.5,"feels_like":10.2,"humidity":87},"wind":{"speed":6.2},"clouds":{"all":90}}`)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := AirportWeather(context.Background(), " lhr ")
+	if err != nil {
+		t.Fatalf("AirportWeather() error = %v", err)
+	}
+	want := "The current weather at London Heathrow Airport (LHR), London, GB is light rain, 11.5°C (feels like 10.2°C), humidity 87%, wind 6.2 m/s, clouds 90%"
+	if got != want {
+		t.Errorf("AirportWeather() =\n%s\nwant\n%s", got, want)
+	}
+	if len(requested) != 1 || requested[0] != "51.470000,-0.454300" {
+		t.Errorf("requested the weather at %v, want the coordinates of Heathrow", requested)
+	}
+
+	for _, code := range []string{"XYZ", "EGLL", ""} {
+		if _, err := AirportWeather(context.Background(), code); err == nil || !strings.Contains(err.Error(), code) {
+			t.Errorf("AirportWeather(%q) error = %v", code, err)
+		}
+	}
+	if len(requested) != 1 {
+		t.Errorf("an invalid code requested the weather")
+	}
+}
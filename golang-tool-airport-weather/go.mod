@@ -0,0 +1,24 @@
+module github.com/yomorun/llm-function-calling-examples/golang-tool-airport-weather
+
+go 1.22.3
+
+require (
+	github.com/yomorun/llm-function-calling-examples/internal v0.0.0
+	github.com/yomorun/yomo v1.18.12
+)
+
+require (
+	github.com/bahlo/generic-list-go v0.2.0 // indirect
+	github.com/buger/jsonparser v1.1.1 // indirect
+	github.com/caarlos0/env/v6 v6.10.1 // indirect
+	github.com/invopop/jsonschema v0.12.0 // indirect
+	github.com/lmittmann/tint v1.0.4 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
+	github.com/sashabaranov/go-openai v1.27.0 // indirect
+	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
+	golang.org/x/sync v0.7.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/yomorun/llm-function-calling-examples/internal => ../internal
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert an angle between degrees, radians, gradians and turns, e.g. "what is 1.2 radians in degrees?", and optionally normalize it to one turn, e.g. 450° to 90° or 270° to -90°. The function returns the converted angle.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value     float64 `json:"value" jsonschema:"description=The angle to convert"`
+	From      string  `json:"from" jsonschema:"description=The unit of the angle,enum=degrees,enum=radians,enum=gradians,enum=turns"`
+	To        string  `json:"to" jsonschema:"description=The unit to convert the angle to,enum=degrees,enum=radians,enum=gradians,enum=turns"`
+	Normalize string  `json:"normalize,omitempty" jsonschema:"description=Wrap the converted angle into one turn: positive from 0 up to a full turn or signed from minus a half turn up to a half turn. Defaults to none,enum=none,enum=positive,enum=signed"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "angle", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xEB}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	converted, err := Convert(msg.Value, msg.From, msg.To, msg.Normalize)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g %s to %s: %v", msg.Value, msg.From, msg.To, err))
+		return
+	}
+
+	from, _ := lookupUnit(msg.From)
+	to, _ := lookupUnit(msg.To)
+	sfn.WriteText(ctx, fmt.Sprintf("%s is %s", from.format(msg.Value), to.format(converted)))
+}
+
+type unit struct {
+	// perTurn is the size of a full turn in the unit.
+	perTurn float64
+	symbol  string
+	name    string
+}
+
+// format writes the angle with up to 10 significant digits, e.g. "90°" or
+// "1.570796327 rad".
+func (u unit) format(v float64) string {
+	s := strconv.FormatFloat(v, 'g', 10, 64)
+	if u.symbol == "°" {
+		return s + u.symbol
+	}
+	return s + " " + u.symbol
+}
+
+var units = map[string]unit{
+	"degrees":  {perTurn: 360, symbol: "°", name: "degrees"},
+	"radians":  {perTurn: 2 * math.Pi, symbol: "rad", name: "radians"},
+	"gradians": {perTurn: 400, symbol: "gon", name: "gradians"},
+	"turns":    {perTurn: 1, symbol: "turns", name: "turns"},
+}
+
+// aliases maps other common spellings to the keys of units.
+var aliases = map[string]string{
+	"deg":         "degrees",
+	"degree":      "degrees",
+	"°":           "degrees",
+	"rad":         "radians",
+	"radian":      "radians",
+	"grad":        "gradians",
+	"gradian":     "gradians",
+	"gon":         "gradians",
+	"grade":       "gradians",
+	"turn":        "turns",
+	"tr":          "turns",
+	"rev":         "turns",
+	"revolution":  "turns",
+	"revolutions": "turns",
+	"cycle":       "turns",
+	"cycles":      "turns",
+}
+
+func lookupUnit(name string) (unit, bool) {
+	key := strings.ToLower(strings.Join(strings.Fields(name), ""))
+	if alias, ok := aliases[key]; ok {
+		key = alias
+	}
+	u, ok := units[key]
+	return u, ok
+}
+
+// Convert converts an angle from one unit to another, and wraps it into one
+// turn when normalize is "positive", to [0, 1 turn), or "signed", to
+// [-½ turn, ½ turn).
+func Convert(value float64, from, to, normalize string) (float64, error) {
+	f, ok := lookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown angle unit %q, use degrees, radians, gradians or turns", from)
+	}
+	t, ok := lookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown angle unit %q, use degrees, radians, gradians or turns", to)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%v is not an angle", value)
+	}
+
+	turns := value / f.perTurn
+	switch strings.ToLower(normalize) {
+	case "", "none":
+	case "positive":
+		turns -= math.Floor(turns)
+	case "signed":
+		turns -= math.Floor(turns + 0.5)
+	default:
+		return 0, fmt.Errorf("unknown normalization %q, use none, positive or signed", normalize)
+	}
+	return turns * t.perTurn, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	// the same angle in every unit, each pairing is checked
+	points := []map[string]float64{
+		{"degrees": 360, "radians": 2 * math.Pi, "gradians": 400, "turns": 1},
+		{"degrees": 180, "radians": math.Pi, "gradians": 200, "turns": 0.5},
+		{"degrees": 90, "radians": math.Pi / 2, "gradians": 100, "turns": 0.25},
+		{"degrees": -45, "radians": -math.Pi / 4, "gradians": -50, "turns": -0.125},
+		{"degrees": 0, "radians": 0, "gradians": 0, "turns": 0},
+		{"degrees": 720, "radians": 4 * math.Pi, "gradians": 800, "turns": 2},
+	}
+
+	for _, point := range points {
+		for from, value := range point {
+			for to, want := range point {
+				got, err := Convert(value, from, to, "")
+				if err != nil {
+					t.Errorf("Convert(%v, %s, %s) error = %v", value, from, to, err)
+					continue
+				}
+				if math.Abs(got-want) > 1e-9 {
+					t.Errorf("Convert(%v, %s, %s) = %v, want %v", value, from, to, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestConvertFullTurn(t *testing.T) {
+	// a full turn in any unit is 0 once normalized to the positive range
+	for name, u := range units {
+		got, err := Convert(u.perTurn, name, "degrees", "positive")
+		if err != nil || math.Abs(got) > 1e-9 {
+			t.Errorf("Convert(1 turn in %s) = %v, %v, want 0°", name, got, err)
+		}
+	}
+}
+
+func TestConvertNormalize(t *testing.T) {
+	tests := []struct {
+		value     float64
+		from, to  string
+		normalize string
+		want      float64
+	}{
+		{450, "degrees", "degrees", "positive", 90},
+		{-90, "degrees", "degrees", "positive", 270},
+		{270, "degrees", "degrees", "signed", -90},
+		{180, "degrees", "degrees", "signed", -180},
+		{-180, "degrees", "degrees", "signed", -180},
+		{179, "degrees", "degrees", "signed", 179},
+		{3 * math.Pi, "radians", "degrees", "positive", 180},
+		{-50, "gradians", "gradians", "positive", 350},
+		{2.75, "turns", "radians", "signed", -math.Pi / 2},
+		{450, "degrees", "degrees", "none", 450},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to, tt.normalize)
+		if err != nil || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v, %s, %s, %s) = %v, %v, want %v", tt.value, tt.from, tt.to, tt.normalize, got, err, tt.want)
+		}
+	}
+}
+
+func TestConvertAliases(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{180, "deg", "rad", math.Pi},
+		{100, "gon", "°", 90},
+		{1, "Revolution", "Degrees", 360},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to, "")
+		if err != nil || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v, %s, %s) = %v, %v, want %v", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	for _, c := range []struct {
+		value               float64
+		from, to, normalize string
+	}{
+		{1, "mils", "degrees", ""},
+		{1, "degrees", "arcminutes", ""},
+		{1, "degrees", "radians", "wrapped"},
+		{math.NaN(), "degrees", "radians", ""},
+		{math.Inf(1), "degrees", "radians", ""},
+	} {
+		if _, err := Convert(c.value, c.from, c.to, c.normalize); err == nil {
+			t.Errorf("Convert(%v, %s, %s, %s) should fail", c.value, c.from, c.to, c.normalize)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := units["degrees"].format(90); got != "90°" {
+		t.Errorf("format() = %s, want 90°", got)
+	}
+	if got := units["radians"].format(math.Pi / 2); got != "1.570796327 rad" {
+		t.Errorf("format() = %s, want 1.570796327 rad", got)
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Render data as a text table with box-drawing borders, e.g. to show a comparison in a terminal or a monospaced chat. Give the headers and the rows as arrays of strings, or the data as a JSON array of objects whose keys become the headers. Number columns are aligned right and the others left unless the alignments are given. The function returns the table, to be shown in a monospaced font.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Headers []string   `json:"headers,omitempty" jsonschema:"description=The column headers"`
+	Rows    [][]string `json:"rows,omitempty" jsonschema:"description=The rows of cells in the order of the headers"`
+	JSON    string     `json:"json,omitempty" jsonschema:"description=The data as a JSON array of objects instead of headers and rows"`
+	Align   []string   `json:"align,omitempty" jsonschema:"description=The alignment of each column: left or right or center. Defaults to right for numbers and left otherwise"`
+	Style   string     `json:"style,omitempty" jsonschema:"description=The border characters. Defaults to box,enum=box,enum=ascii"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "ascii-table", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x102}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "headers", msg.Headers, "rows", len(msg.Rows), "style", msg.Style)
+
+	result, err := Table(msg)
+	if err != nil {
+		slog.Warn("[sfn] Table error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not render the table: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Table renders the data of p.
+func Table(p Parameter) (string, error) {
+	headers, rows := p.Headers, p.Rows
+	if strings.TrimSpace(p.JSON) != "" {
+		if len(headers) > 0 || len(rows) > 0 {
+			return "", errors.New("give either the headers and rows or the JSON data, not both")
+		}
+		var err error
+		if headers, rows, err = ParseJSON(p.JSON); err != nil {
+			return "", err
+		}
+	}
+	if len(headers) == 0 {
+		return "", errors.New("the table has no headers")
+	}
+
+	style, ok := styles[p.Style]
+	if !ok {
+		return "", fmt.Errorf("unknown style %q, use box or ascii", p.Style)
+	}
+	aligns, err := alignments(p.Align, headers, rows)
+	if err != nil {
+		return "", err
+	}
+	return Render(headers, rows, aligns, style)
+}
+
+// ParseJSON reads a JSON array of objects as a table, the headers being the
+// keys in their order of first appearance.
+func ParseJSON(data string) (headers []string, rows [][]string, err error) {
+	var objects []json.RawMessage
+	if err := json.Unmarshal([]byte(data), &objects); err != nil {
+		return nil, nil, fmt.Errorf("the JSON data must be an array of objects: %w", err)
+	}
+
+	column := make(map[string]int)
+	for i, raw := range objects {
+		// decode the object token by token, a map would lose the key order
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil, nil, fmt.Errorf("item %d of the JSON data is not an object", i+1)
+		}
+		row := make(map[int]string)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, nil, err
+			}
+			key := tok.(string)
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return nil, nil, err
+			}
+			if _, seen := column[key]; !seen {
+				column[key] = len(headers)
+				headers = append(headers, key)
+			}
+			row[column[key]] = cellText(value)
+		}
+		rows = append(rows, make([]string, 0, len(headers)))
+		for c := 0; c < len(headers); c++ {
+			rows[i] = append(rows[i], row[c])
+		}
+	}
+	// the rows before a key appeared are short, Render pads them
+	return headers, rows, nil
+}
+
+// cellText is a JSON value as a cell: a string unquoted, null empty and the
+// other values as their JSON text.
+func cellText(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return string(raw)
+	}
+	return compact.String()
+}
+
+// Alignments of a column.
+const (
+	Left   = "left"
+	Right  = "right"
+	Center = "center"
+)
+
+// alignments returns the alignment of each column: the given one, or right
+// when all the cells of the column are numbers, left otherwise.
+func alignments(given []string, headers []string, rows [][]string) ([]string, error) {
+	if len(given) > len(headers) {
+		return nil, fmt.Errorf("%d alignments for %d columns", len(given), len(headers))
+	}
+	aligns := make([]string, len(headers))
+	for i := range headers {
+		if i < len(given) && given[i] != "" {
+			switch a := strings.ToLower(strings.TrimSpace(given[i])); a {
+			case Left, Right, Center:
+				aligns[i] = a
+				continue
+			default:
+				return nil, fmt.Errorf("unknown alignment %q, use left, right or center", given[i])
+			}
+		}
+		aligns[i] = Left
+		if numeric(rows, i) {
+			aligns[i] = Right
+		}
+	}
+	return aligns, nil
+}
+
+// numeric reports whether the column i has cells and they all are numbers.
+func numeric(rows [][]string, i int) bool {
+	found := false
+	for _, row := range rows {
+		if i >= len(row) || strings.TrimSpace(row[i]) == "" {
+			continue
+		}
+		cell := strings.NewReplacer(",", "", "%", "", "$", "", "€", "").Replace(strings.TrimSpace(row[i]))
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// Style is the set of characters drawing the borders.
+type Style struct {
+	Horizontal, Vertical string
+	// the corners and junctions of the top, middle and bottom lines, from
+	// left to right
+	Top, Middle, Bottom [3]string
+}
+
+var styles = map[string]Style{
+	"":    boxStyle,
+	"box": boxStyle,
+	"ascii": {
+		Horizontal: "-", Vertical: "|",
+		Top: [3]string{"+", "+", "+"}, Middle: [3]string{"+", "+", "+"}, Bottom: [3]string{"+", "+", "+"},
+	},
+}
+
+var boxStyle = Style{
+	Horizontal: "─", Vertical: "│",
+	Top: [3]string{"┌", "┬", "┐"}, Middle: [3]string{"├", "┼", "┤"}, Bottom: [3]string{"└", "┴", "┘"},
+}
+
+// Render draws the table, each column as wide as its widest cell in
+// terminal columns. A row shorter than the headers is padded with empty
+// cells.
+func Render(headers []string, rows [][]string, aligns []string, style Style) (string, error) {
+	for i, row := range rows {
+		if len(row) > len(headers) {
+			return "", fmt.Errorf("row %d has %d cells for %d columns", i+1, len(row), len(headers))
+		}
+	}
+
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = Width(clean(h))
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], Width(clean(cell)))
+		}
+	}
+
+	var b strings.Builder
+	line := func(ends [3]string) {
+		b.WriteString(ends[0])
+		for i, w := range widths {
+			if i > 0 {
+				b.WriteString(ends[1])
+			}
+			b.WriteString(strings.Repeat(style.Horizontal, w+2))
+		}
+		b.WriteString(ends[2] + "\n")
+	}
+	cells := func(row []string, aligns []string) {
+		b.WriteString(style.Vertical)
+		for i, w := range widths {
+			cell := ""
+			if i < len(row) {
+				cell = clean(row[i])
+			}
+			b.WriteString(" " + pad(cell, w, aligns[i]) + " " + style.Vertical)
+		}
+		b.WriteString("\n")
+	}
+
+	headerAligns := make([]string, len(headers))
+	for i := range headerAligns {
+		headerAligns[i] = Center
+	}
+	line(style.Top)
+	cells(headers, headerAligns)
+	line(style.Middle)
+	for _, row := range rows {
+		cells(row, aligns)
+	}
+	line(style.Bottom)
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// clean puts a cell on a single line.
+func clean(cell string) string {
+	return strings.Join(strings.Fields(cell), " ")
+}
+
+// pad aligns s in w terminal columns.
+func pad(s string, w int, align string) string {
+	gap := w - Width(s)
+	switch align {
+	case Right:
+		return strings.Repeat(" ", gap) + s
+	case Center:
+		return strings.Repeat(" ", gap/2) + s + strings.Repeat(" ", gap-gap/2)
+	}
+	return s + strings.Repeat(" ", gap)
+}
+
+// Width is the number of terminal columns s takes: 2 for the East Asian wide
+// and fullwidth characters and most emojis, 0 for the combining marks and
+// the invisible format characters, 1 for the others. A symbol followed by
+// the emoji variation selector, e.g. "☀️", is drawn as a 2 column emoji.
+func Width(s string) int {
+	w, last := 0, 0
+	for _, r := range s {
+		if r == emojiPresentation && last == 1 {
+			w++
+			last = 2
+			continue
+		}
+		last = runeWidth(r)
+		w += last
+	}
+	return w
+}
+
+// emojiPresentation is the variation selector VS16.
+const emojiPresentation = '\uFE0F'
+
+func runeWidth(r rune) int {
+	switch {
+	case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r):
+		return 0
+	case wide(r):
+		return 2
+	}
+	return 1
+}
+
+// wideRanges are the East Asian wide and fullwidth blocks and the emoji
+// blocks, from the Unicode East Asian Width property.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo initials
+	{0x2E80, 0x303E},   // CJK radicals to CJK symbols and punctuation
+	{0x3041, 0x33FF},   // Hiragana to CJK compatibility
+	{0x3400, 0x4DBF},   // CJK unified ideographs extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // fullwidth forms
+	{0xFFE0, 0xFFE6},   // fullwidth signs
+	{0x1F300, 0x1F64F}, // pictographs and emoticons
+	{0x1F900, 0x1F9FF}, // supplemental symbols and pictographs
+	{0x20000, 0x3FFFD}, // CJK extensions B and later
+}
+
+func wide(r rune) bool {
+	for _, rg := range wideRanges {
+		if r >= rg[0] && r <= rg[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableAlignment(t *testing.T) {
+	got, err := Table(Parameter{
+		Headers: []string{"City", "Temp °C", "Sky"},
+		Rows: [][]string{
+			{"Oslo", "12", "cloudy"},
+			{"Lisbon", "24.5", "clear"},
+			{"Reykjavik", "-3"},
+		},
+		Align: []string{"", "", "center"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"┌───────────┬─────────┬────────┐",
+		"│   City    │ Temp °C │  Sky   │",
+		"├───────────┼─────────┼────────┤",
+		"│ Oslo      │      12 │ cloudy │",
+		"│ Lisbon    │    24.5 │ clear  │",
+		"│ Reykjavik │      -3 │        │",
+		"└───────────┴─────────┴────────┘",
+	}, "\n")
+	if got != want {
+		t.Errorf("Table() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTableUnicodeWidth(t *testing.T) {
+	got, err := Table(Parameter{
+		Headers: []string{"Name", "Note"},
+		Rows: [][]string{
+			{"東京", "☀️ sunny"},
+			{"Zoë", "🌧 rain"},
+			{"Cafe\u0301", "ok"},
+		},
+		Style: "ascii",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"+------+----------+",
+		"| Name |   Note   |",
+		"+------+----------+",
+		"| 東京 | ☀️ sunny |",
+		"| Zoë  | 🌧 rain  |",
+		"| Cafe\u0301 | ok       |",
+		"+------+----------+",
+	}, "\n")
+	if got != want {
+		t.Errorf("Table() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"abc", 3},
+		{"東京", 4},
+		{"ｔｅｓｔ", 8},
+		{"한국어", 6},
+		{"é", 1},
+		{"🙂", 2},
+		{"☀️", 2},
+		{"☀", 1},
+		{"a​b", 2},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.s); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTableJSON(t *testing.T) {
+	got, err := Table(Parameter{JSON: `[{"city":"Oslo","temp":12,"rain":null},{"city":"Lima","temp":19.5,"tags":["coast"]}]`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"┌──────┬──────┬──────┬───────────┐",
+		"│ city │ temp │ rain │   tags    │",
+		"├──────┼──────┼──────┼───────────┤",
+		"│ Oslo │   12 │      │           │",
+		"│ Lima │ 19.5 │      │ [\"coast\"] │",
+		"└──────┴──────┴──────┴───────────┘",
+	}, "\n")
+	if got != want {
+		t.Errorf("Table() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTableErrors(t *testing.T) {
+	tests := []Parameter{
+		{},
+		{Headers: []string{"a"}, Rows: [][]string{{"1", "2"}}},
+		{Headers: []string{"a"}, Align: []string{"middle"}},
+		{Headers: []string{"a"}, Align: []string{"left", "right"}},
+		{Headers: []string{"a"}, Style: "fancy"},
+		{JSON: `{"a":1}`},
+		{JSON: `[1, 2]`},
+		{Headers: []string{"a"}, JSON: `[{"a":1}]`},
+	}
+	for _, p := range tests {
+		if _, err := Table(p); err == nil {
+			t.Errorf("Table(%+v) should fail", p)
+		}
+	}
+}
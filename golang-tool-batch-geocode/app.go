@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Geocode a list of places, e.g. cities or towns optionally qualified with their state and country like "Springfield, Illinois, US", to their latitude and longitude in one call. A place that can not be found or matches several places gets its error inline while the others are still answered. Use it instead of calling a geocoder once per place.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Addresses []string `json:"addresses" jsonschema:"description=The names of the places to geocode,example=Lyon, FR"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "batch-geocode", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x10C}
+}
+
+var client = newClient()
+
+// newClient returns an OpenWeatherMap client caching the places it found,
+// so that a place asked twice in a batch, or in the next one, is geocoded
+// once.
+func newClient() *weather.Client {
+	c := weather.NewClient("")
+	c.CityCache = cache.New[weather.Location](24 * time.Hour)
+	return c
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "addresses", len(msg.Addresses))
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	results, err := BatchGeocode(reqCtx, client, msg.Addresses, maxConcurrent)
+	if err != nil {
+		slog.Warn("[sfn] BatchGeocode error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not geocode the addresses: %v", err))
+		return
+	}
+
+	result := Report(results)
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+const (
+	// maxAddresses is the most addresses geocoded in a call.
+	maxAddresses = 50
+	// maxConcurrent is how many geocoding requests are in flight at once,
+	// to stay under the calls per minute of the API key.
+	maxConcurrent = 4
+)
+
+// Geocoder resolves a place name to a single location, e.g. a
+// *weather.Client.
+type Geocoder interface {
+	Locate(ctx context.Context, city string) (weather.Location, error)
+}
+
+// Result is the location of an address, or why it was not found.
+type Result struct {
+	Address  string
+	Location weather.Location
+	Err      error
+}
+
+// BatchGeocode locates the addresses with g, at most workers at a time, and
+// returns their results in the order of addresses. The failure of an
+// address is its Result.Err, it does not stop the others.
+func BatchGeocode(ctx context.Context, g Geocoder, addresses []string, workers int) ([]Result, error) {
+	if len(addresses) == 0 {
+		return nil, errors.New("there are no addresses")
+	}
+	if len(addresses) > maxAddresses {
+		return nil, fmt.Errorf("%d addresses is too many, the most is %d", len(addresses), maxAddresses)
+	}
+
+	results := make([]Result, len(addresses))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(addresses)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = locate(ctx, g, addresses[i])
+			}
+		}()
+	}
+	for i := range addresses {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, nil
+}
+
+func locate(ctx context.Context, g Geocoder, address string) Result {
+	r := Result{Address: strings.TrimSpace(address)}
+	if r.Address == "" {
+		r.Err = errors.New("the address is empty")
+		return r
+	}
+	if err := ctx.Err(); err != nil {
+		r.Err = err
+		return r
+	}
+	r.Location, r.Err = g.Locate(ctx, r.Address)
+	return r
+}
+
+// Report lists the results, one address per line, with the coordinates or
+// the error of each.
+func Report(results []Result) string {
+	found := 0
+	lines := make([]string, len(results))
+	for i, r := range results {
+		if r.Err != nil {
+			lines[i] = fmt.Sprintf("%d. %s: not geocoded, %v", i+1, r.Address, r.Err)
+			continue
+		}
+		found++
+		lines[i] = fmt.Sprintf("%d. %s: %.4f,%.4f (%s)", i+1, r.Address, r.Location.Latitude, r.Location.Longitude, r.Location)
+	}
+	return fmt.Sprintf("Geocoded %d of %d addresses:\n%s", found, len(results), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+// fakeGeocoder knows the places of known and tracks how many lookups are in
+// flight at once.
+type fakeGeocoder struct {
+	known map[string]weather.Location
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	calls    int
+}
+
+func (f *fakeGeocoder) Locate(ctx context.Context, city string) (weather.Location, error) {
+	f.mu.Lock()
+	f.calls++
+	f.inFlight++
+	f.peak = max(f.peak, f.inFlight)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	l, ok := f.known[city]
+	if !ok {
+		return weather.Location{}, fmt.Errorf("%w: %s", weather.ErrCityNotFound, city)
+	}
+	return l, nil
+}
+
+func TestBatchGeocodeConcurrencyBound(t *testing.T) {
+	f := &fakeGeocoder{known: map[string]weather.Location{}}
+	var addresses []string
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("Town %d", i)
+		f.known[name] = weather.Location{Name: name, Latitude: float64(i)}
+		addresses = append(addresses, name)
+	}
+
+	results, err := BatchGeocode(context.Background(), f, addresses, 3)
+	if err != nil {
+		t.Fatalf("BatchGeocode() error = %v", err)
+	}
+	if f.peak > 3 || f.peak < 2 {
+		t.Errorf("%d lookups were in flight at once, want 2 to 3", f.peak)
+	}
+	if f.calls != 20 {
+		t.Errorf("%d lookups, want 20", f.calls)
+	}
+	for i, r := range results {
+		if r.Err != nil || r.Location.Latitude != float64(i) {
+			t.Errorf("results[%d] = %+v, want Town %d in order", i, r, i)
+		}
+	}
+}
+
+func TestBatchGeocodePartialFailure(t *testing.T) {
+	f := &fakeGeocoder{known: map[string]weather.Location{
+		"Lyon":  {Name: "Lyon", Country: "FR", Latitude: 45.7578, Longitude: 4.832},
+		"Kyoto": {Name: "Kyoto", Country: "JP", Latitude: 35.0116, Longitude: 135.7681},
+	}}
+
+	results, err := BatchGeocode(context.Background(), f, []string{"Lyon", "Atlantis", " ", "Kyoto"}, maxConcurrent)
+	if err != nil {
+		t.Fatalf("BatchGeocode() error = %v", err)
+	}
+	want := `Geocoded 2 of 4 addresses:
+1. Lyon: 45.7578,4.8320 (Lyon, FR)
+2. Atlantis: not geocoded, city not found: Atlantis
+3. : not geocoded, the address is empty
+4. Kyoto: 35.0116,135.7681 (Kyoto, JP)`
+	if got := Report(results); got != want {
+		t.Errorf("Report() =\n%s\nwant\n%s", got, want)
+	}
+	if f.calls != 3 {
+		t.Errorf("%d lookups, want the empty address skipped", f.calls)
+	}
+}
+
+func TestBatchGeocodeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f := &fakeGeocoder{}
+	results, err := BatchGeocode(ctx, f, []string{"Lyon", "Kyoto"}, 2)
+	if err != nil {
+		t.Fatalf("BatchGeocode() error = %v", err)
+	}
+	for _, r := range results {
+		if r.Err != context.Canceled {
+			t.Errorf("result %+v, want context.Canceled", r)
+		}
+	}
+	if f.calls != 0 {
+		t.Errorf("%d lookups after the cancellation, want 0", f.calls)
+	}
+}
+
+func TestBatchGeocodeLimits(t *testing.T) {
+	f := &fakeGeocoder{}
+	if _, err := BatchGeocode(context.Background(), f, nil, 2); err == nil {
+		t.Error("BatchGeocode() without addresses error = nil")
+	}
+	if _, err := BatchGeocode(context.Background(), f, make([]string, maxAddresses+1), 2); err == nil || !strings.Contains(err.Error(), "too many") {
+		t.Errorf("BatchGeocode() with too many addresses error = %v", err)
+	}
+}
+
+func TestBatchGeocodeWeatherClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "Porto":
+			w.Write([]byte(`[{"name":"Porto","country":"PT","lat":41.1496,"lon":-8.611}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	old := client
+	t.Cleanup(func() { client = old })
+	client = newClient()
+	client.APIKey = "key"
+	client.BaseURL = srv.URL
+
+	results, err := BatchGeocode(context.Background(), client, []string{"Porto", "Nowhere"}, maxConcurrent)
+	if err != nil {
+		t.Fatalf("BatchGeocode() error = %v", err)
+	}
+	if results[0].Err != nil || results[0].Location.Country != "PT" {
+		t.Errorf("results[0] = %+v, want Porto, PT", results[0])
+	}
+	if results[1].Err == nil {
+		t.Errorf("results[1] = %+v, want an error", results[1])
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the bounding box that encloses a circle around a geo coordinate, e.g. to search a map or a database for places "within 10 km of" a location. If the user gives a city name, convert it to Latitude and Longitude in decimal format. The function returns the south, north, west and east limits of the box in decimal degrees.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the center in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the center in decimal format,minimum=-180,maximum=180"`
+	RadiusKm  float64 `json:"radiusKm" jsonschema:"description=The radius of the circle in kilometers,exclusiveMinimum=0,maximum=20000"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "bbox", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC3}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "radius_km", msg.RadiusKm)
+
+	box, err := BoundingBox(msg.Latitude, msg.Longitude, msg.RadiusKm)
+	if err != nil {
+		slog.Warn("[sfn] BoundingBox error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the bounding box: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("The bounding box of %v km around %v,%v is %s", msg.RadiusKm, msg.Latitude, msg.Longitude, box))
+}
+
+// maxRadiusKm is about half the circumference of the earth, a larger circle
+// would cover it all.
+const maxRadiusKm = 20000
+
+// Box is a latitude and longitude range. West is greater than East when the
+// box crosses the antimeridian.
+type Box struct {
+	South, North, West, East float64
+	// Pole is "North" or "South" when the circle includes that pole, or
+	// "North and South" when it includes both, the box
+	// then spans all longitudes.
+	Pole string
+}
+
+func (b *Box) String() string {
+	s := fmt.Sprintf("south %.6f, north %.6f, west %.6f, east %.6f", b.South, b.North, b.West, b.East)
+	switch {
+	case b.Pole != "":
+		s += fmt.Sprintf(". The circle includes the %s Pole, so the box spans all longitudes", b.Pole)
+	case b.West > b.East:
+		s += ". The box crosses the 180° meridian, so it is made of the longitudes west of 180° and east of -180°"
+	}
+	return s
+}
+
+// BoundingBox returns the smallest latitude and longitude range enclosing
+// the circle of radiusKm around lat,lon on a spherical earth. A degree of
+// longitude shrinks with the cosine of the latitude, so the box widens towards
+// the poles, see J. Matuschek, "Finding Points Within a Distance of a
+// Latitude/Longitude Using Bounding Coordinates".
+func BoundingBox(lat, lon, radiusKm float64) (*Box, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return nil, err
+	}
+	if math.IsNaN(radiusKm) || radiusKm <= 0 || radiusKm > maxRadiusKm {
+		return nil, fmt.Errorf("the radius must be greater than 0 and at most %d km, got %v", maxRadiusKm, radiusKm)
+	}
+
+	// the radius as an angle at the center of the earth
+	r := radiusKm / geo.EarthRadiusKm
+	latR := lat * math.Pi / 180
+	south, north := latR-r, latR+r
+
+	switch {
+	case north > math.Pi/2 && south < -math.Pi/2:
+		return &Box{South: -90, North: 90, West: -180, East: 180, Pole: "North and South"}, nil
+	case north > math.Pi/2:
+		return &Box{South: degrees(south), North: 90, West: -180, East: 180, Pole: "North"}, nil
+	case south < -math.Pi/2:
+		return &Box{South: -90, North: degrees(north), West: -180, East: 180, Pole: "South"}, nil
+	}
+
+	dLon := degrees(math.Asin(math.Sin(r) / math.Cos(latR)))
+	return &Box{
+		South: degrees(south),
+		North: degrees(north),
+		West:  wrap(lon - dLon),
+		East:  wrap(lon + dLon),
+	}, nil
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// wrap brings a longitude back into -180..180.
+func wrap(lon float64) float64 {
+	switch {
+	case lon > 180:
+		return lon - 360
+	case lon < -180:
+		return lon + 360
+	}
+	return lon
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+)
+
+// oneDegreeKm is the length of a degree of latitude on the earth sphere.
+const oneDegreeKm = 2 * math.Pi * geo.EarthRadiusKm / 360
+
+func TestBoundingBox(t *testing.T) {
+	tests := []struct {
+		name   string
+		lat    float64
+		lon    float64
+		radius float64
+		want   Box
+	}{
+		{
+			name: "equator", lat: 0, lon: 0, radius: oneDegreeKm,
+			want: Box{South: -1, North: 1, West: -1, East: 1},
+		},
+		{
+			// asin(sin(1°) / cos(60°)) = 2.0003047799°
+			name: "longitudes widen at 60°", lat: 60, lon: 10, radius: oneDegreeKm,
+			want: Box{South: 59, North: 61, West: 10 - 2.0003047799, East: 10 + 2.0003047799},
+		},
+		{
+			// 50 km is 0.4496601819°, asin(sin(0.4496601819°) / cos(16.5°)) = 0.4689730202°
+			name: "antimeridian", lat: -16.5, lon: 179.9, radius: 50,
+			want: Box{South: -16.9496601819, North: -16.0503398181, West: 179.9 - 0.4689730202, East: 179.9 + 0.4689730202 - 360},
+		},
+		{
+			name: "north pole", lat: 89.5, lon: 45, radius: oneDegreeKm,
+			want: Box{South: 88.5, North: 90, West: -180, East: 180, Pole: "North"},
+		},
+		{
+			name: "south pole", lat: -90, lon: 0, radius: 2 * oneDegreeKm,
+			want: Box{South: -90, North: -88, West: -180, East: 180, Pole: "South"},
+		},
+		{
+			name: "both poles", lat: 0, lon: 0, radius: 91 * oneDegreeKm,
+			want: Box{South: -90, North: 90, West: -180, East: 180, Pole: "North and South"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BoundingBox(tt.lat, tt.lon, tt.radius)
+			if err != nil {
+				t.Fatalf("BoundingBox() error = %v", err)
+			}
+			if !near(got.South, tt.want.South) || !near(got.North, tt.want.North) ||
+				!near(got.West, tt.want.West) || !near(got.East, tt.want.East) || got.Pole != tt.want.Pole {
+				t.Errorf("BoundingBox() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoundingBoxInvalid(t *testing.T) {
+	tests := []struct {
+		lat, lon, radius float64
+	}{
+		{91, 0, 10},
+		{0, -181, 10},
+		{0, 0, 0},
+		{0, 0, -5},
+		{0, 0, 30000},
+		{0, 0, math.NaN()},
+	}
+	for _, tt := range tests {
+		if _, err := BoundingBox(tt.lat, tt.lon, tt.radius); err == nil {
+			t.Errorf("BoundingBox(%v, %v, %v) should fail", tt.lat, tt.lon, tt.radius)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Tell whether a day is a good beach day at a location, from the forecast temperature, UV index, wind and precipitation, e.g. "is tomorrow a good day for the beach in Nice?". If the place name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns a beach-day score from 0 to 10 with a short verdict and its reasons.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the beach in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the beach in decimal format,minimum=-180,maximum=180"`
+	Date      string  `json:"date,omitempty" jsonschema:"description=The local date in YYYY-MM-DD format within the next 7 days. Defaults to today,example=2024-08-10"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "beach-day", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE8}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "date", msg.Date)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := BeachDay(reqCtx, msg, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] BeachDay error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not tell whether it is a beach day: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// BeachDay fetches the daily forecast at the coordinates of p and rates the
+// day of p.Date, today after now when it is empty.
+func BeachDay(ctx context.Context, p Parameter, now time.Time) (string, error) {
+	if err := geo.ValidateCoordinate(p.Latitude, p.Longitude); err != nil {
+		return "", err
+	}
+	if p.Date != "" {
+		if _, err := time.Parse("2006-01-02", p.Date); err != nil {
+			return "", fmt.Errorf("can not understand the date %q, please use YYYY-MM-DD", p.Date)
+		}
+	}
+
+	days, err := client.Daily(ctx, p.Latitude, p.Longitude)
+	if err != nil {
+		return "", err
+	}
+	date := p.Date
+	if date == "" {
+		date = now.In(days[0].Date.Location()).Format("2006-01-02")
+	}
+	for _, d := range days {
+		if d.Date.Format("2006-01-02") == date {
+			r := Rate(Conditions{
+				Temperature:         d.TempMax,
+				UVIndex:             d.UVIndex,
+				WindSpeed:           d.WindSpeed,
+				PrecipitationChance: d.PrecipitationChance,
+				Precipitation:       d.Rain + d.Snow,
+			})
+			return fmt.Sprintf("%s at %v,%v on %s: %s", r, p.Latitude, p.Longitude, date, d.Description), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not in the forecast, it covers %s to %s", date,
+		days[0].Date.Format("2006-01-02"), days[len(days)-1].Date.Format("2006-01-02"))
+}
+
+// Conditions is the weather of a day that matters at the beach.
+type Conditions struct {
+	// Temperature is the maximum of the day in °C.
+	Temperature float64
+	// UVIndex is the maximum of the day.
+	UVIndex float64
+	// WindSpeed is in m/s.
+	WindSpeed float64
+	// PrecipitationChance is the probability of precipitation, 0 to 1.
+	PrecipitationChance float64
+	// Precipitation is the amount of rain and snow of the day in mm.
+	Precipitation float64
+}
+
+// Rating is the beach-day score of a day.
+type Rating struct {
+	// Score is from 0, stay home, to 10, a perfect beach day.
+	Score   int
+	Verdict string
+	Reasons []string
+}
+
+// String returns the rating, e.g. "Beach-day score 9/10, a great beach day
+// (very high UV, wear sunscreen)".
+func (r Rating) String() string {
+	s := fmt.Sprintf("Beach-day score %d/10, %s", r.Score, r.Verdict)
+	if len(r.Reasons) > 0 {
+		s += " (" + strings.Join(r.Reasons, ", ") + ")"
+	}
+	return s
+}
+
+// The points of the score, 10 in total:
+//   - 4 for the temperature, all of them from 26 to 32°C, none below 18 or
+//     above 40°C
+//   - 3 for the chance of precipitation, none with 5 mm or more
+//   - 2 for the wind, all of them up to 4 m/s, none from 12 m/s
+//   - 1 for the sun, all of it for a UV index from 3 to 7 and half of it
+//     from 8, when the sun is harsh
+const (
+	temperaturePoints   = 4
+	precipitationPoints = 3
+	windPoints          = 2
+	sunPoints           = 1
+)
+
+// Rate scores the conditions of a day for the beach.
+func Rate(c Conditions) Rating {
+	var reasons []string
+
+	var temperature float64
+	switch t := c.Temperature; {
+	case t < 26:
+		temperature = temperaturePoints * ramp(t, 18, 26)
+		if t < 22 {
+			reasons = append(reasons, fmt.Sprintf("too cold at %.0f°C", t))
+		}
+	case t > 32:
+		temperature = temperaturePoints * (1 - ramp(t, 32, 40))
+		reasons = append(reasons, fmt.Sprintf("very hot at %.0f°C", t))
+	default:
+		temperature = temperaturePoints
+	}
+
+	precipitation := precipitationPoints * (1 - c.PrecipitationChance)
+	if c.Precipitation >= 5 {
+		precipitation = 0
+	}
+	if c.PrecipitationChance >= 0.5 || c.Precipitation >= 5 {
+		reasons = append(reasons, fmt.Sprintf("%.0f%% chance of rain", c.PrecipitationChance*100))
+	}
+
+	wind := windPoints * (1 - ramp(c.WindSpeed, 4, 12))
+	if c.WindSpeed > 8 {
+		reasons = append(reasons, fmt.Sprintf("windy at %.0f m/s", c.WindSpeed))
+	}
+
+	sun := sunPoints * ramp(c.UVIndex, 0, 3)
+	switch {
+	case c.UVIndex >= 8:
+		sun = sunPoints / 2.0
+		reasons = append(reasons, fmt.Sprintf("very high UV index of %.0f, wear sunscreen and seek shade at midday", c.UVIndex))
+	case c.UVIndex < 2:
+		reasons = append(reasons, "little sun")
+	}
+
+	score := int(math.Round(temperature + precipitation + wind + sun))
+	return Rating{Score: score, Verdict: verdict(score), Reasons: reasons}
+}
+
+func verdict(score int) string {
+	switch {
+	case score >= 8:
+		return "a great beach day"
+	case score >= 6:
+		return "a good beach day"
+	case score >= 4:
+		return "an okay beach day"
+	}
+	return "not a beach day"
+}
+
+// ramp is 0 at lo, 1 at hi and linear in between.
+func ramp(v, lo, hi float64) float64 {
+	return math.Max(0, math.Min(1, (v-lo)/(hi-lo)))
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		c           Conditions
+		wantScore   int
+		wantVerdict string
+		wantReason  string
+	}{
+		{
+			name:        "sunny and calm",
+			c:           Conditions{Temperature: 29, UVIndex: 6, WindSpeed: 2},
+			wantScore:   10,
+			wantVerdict: "a great beach day",
+		},
+		{
+			name:        "cold and windy",
+			c:           Conditions{Temperature: 14, UVIndex: 2, WindSpeed: 13, PrecipitationChance: 0.2},
+			wantScore:   3,
+			wantVerdict: "not a beach day",
+			wantReason:  "too cold at 14°C, windy at 13 m/s",
+		},
+		{
+			name:        "harsh sun",
+			c:           Conditions{Temperature: 31, UVIndex: 10, WindSpeed: 3},
+			wantScore:   10,
+			wantVerdict: "a great beach day",
+			wantReason:  "very high UV index of 10",
+		},
+		{
+			name:        "rainy",
+			c:           Conditions{Temperature: 27, UVIndex: 3, WindSpeed: 5, PrecipitationChance: 0.9, Precipitation: 8},
+			wantScore:   7,
+			wantVerdict: "a good beach day",
+			wantReason:  "90% chance of rain",
+		},
+		{
+			name:        "heat wave",
+			c:           Conditions{Temperature: 39, UVIndex: 9, WindSpeed: 1},
+			wantScore:   6,
+			wantVerdict: "a good beach day",
+			wantReason:  "very hot at 39°C",
+		},
+		{
+			name:        "mild and overcast",
+			c:           Conditions{Temperature: 22, UVIndex: 1, WindSpeed: 6, PrecipitationChance: 0.3},
+			wantScore:   6,
+			wantVerdict: "a good beach day",
+			wantReason:  "little sun",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Rate(tt.c)
+			if r.Score != tt.wantScore || r.Verdict != tt.wantVerdict {
+				t.Errorf("Rate() = %d %s, want %d %s", r.Score, r.Verdict, tt.wantScore, tt.wantVerdict)
+			}
+			if got := strings.Join(r.Reasons, ", "); !strings.Contains(got, tt.wantReason) || (tt.wantReason == "" && got != "") {
+				t.Errorf("Rate() reasons = %q, want %q", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRateBounds(t *testing.T) {
+	worst := Rate(Conditions{Temperature: -5, WindSpeed: 30, PrecipitationChance: 1, Precipitation: 40})
+	if worst.Score != 0 {
+		t.Errorf("the worst day scores %d, want 0", worst.Score)
+	}
+	if got := worst.String(); !strings.HasPrefix(got, "Beach-day score 0/10, not a beach day (too cold at -5°C") {
+		t.Errorf("String() = %s", got)
+	}
+}
+
+func TestBeachDay(t *testing.T) {
+	// noon of 2024-08-07 and 2024-08-08 in UTC+2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/3.0/onecall" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"timezone_offset":7200,"daily":[
+			{"dt":1723024800,"temp":{"min":21,"max":29},"wind_speed":2,"weather":[{"id":800,"description":"clear sky"}],"pop":0,"uvi":6},
+			{"dt":1723111200,"temp":{"min":12,"max":14},"wind_speed":13,"weather":[{"id":501,"description":"moderate rain"}],"pop":1,"rain":9,"uvi":2}
+		]}`)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	// 23:30 UTC is already the next day in UTC+2
+	now := time.Date(2024, 8, 6, 23, 30, 0, 0, time.UTC)
+	got, err := BeachDay(context.Background(), Parameter{Latitude: 43.2965, Longitude: 5.3698}, now)
+	if err != nil {
+		t.Fatalf("BeachDay() error = %v", err)
+	}
+	if want := "Beach-day score 10/10, a great beach day at 43.2965,5.3698 on 2024-08-07: clear sky"; got != want {
+		t.Errorf("BeachDay() =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = BeachDay(context.Background(), Parameter{Latitude: 43.2965, Longitude: 5.3698, Date: "2024-08-08"}, now)
+	if err != nil {
+		t.Fatalf("BeachDay() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "Beach-day score 1/10, not a beach day") {
+		t.Errorf("BeachDay() = %s", got)
+	}
+
+	for _, p := range []Parameter{
+		{Latitude: 43.2965, Longitude: 5.3698, Date: "2024-08-20"},
+		{Latitude: 43.2965, Longitude: 5.3698, Date: "tomorrow"},
+		{Latitude: 95},
+	} {
+		if _, err := BeachDay(context.Background(), p, now); err == nil {
+			t.Errorf("BeachDay(%+v) should fail", p)
+		}
+	}
+}
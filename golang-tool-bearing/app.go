@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the initial compass bearing of the shortest route from a point A to a point B, e.g. "in which direction is Tokyo from San Francisco?". If the user gives city names, convert them to Latitude and Longitude in decimal format. The function returns the bearing in degrees clockwise from north, the compass direction like "NE" and the great circle distance.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	FromLatitude  float64 `json:"fromLatitude" jsonschema:"description=The latitude of point A in decimal format,minimum=-90,maximum=90"`
+	FromLongitude float64 `json:"fromLongitude" jsonschema:"description=The longitude of point A in decimal format,minimum=-180,maximum=180"`
+	ToLatitude    float64 `json:"toLatitude" jsonschema:"description=The latitude of point B in decimal format,minimum=-90,maximum=90"`
+	ToLongitude   float64 `json:"toLongitude" jsonschema:"description=The longitude of point B in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "bearing", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD0}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	result, err := Bearing(msg.FromLatitude, msg.FromLongitude, msg.ToLatitude, msg.ToLongitude)
+	if err != nil {
+		slog.Warn("[sfn] Bearing error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the bearing: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, result.String())
+}
+
+// Result is the bearing and distance from A to B.
+type Result struct {
+	Degrees    float64
+	Cardinal   string
+	DistanceKm float64
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("The initial bearing is %.1f° (%s), the great circle distance is %.1f km. The bearing changes along a great circle, so follow it only at the start.", r.Degrees, r.Cardinal, r.DistanceKm)
+}
+
+// coincident is the distance below which A and B are the same point, or
+// antipodes, and no direction stands out.
+const coincident = 1e-6
+
+// Bearing returns the initial bearing and the distance from lat1,lon1 to
+// lat2,lon2.
+func Bearing(lat1, lon1, lat2, lon2 float64) (*Result, error) {
+	if err := geo.ValidateCoordinate(lat1, lon1); err != nil {
+		return nil, err
+	}
+	if err := geo.ValidateCoordinate(lat2, lon2); err != nil {
+		return nil, err
+	}
+
+	distance := geo.Distance(lat1, lon1, lat2, lon2)
+	switch {
+	case distance < coincident:
+		return nil, fmt.Errorf("A and B are the same point, it has no direction")
+	case math.Pi*geo.EarthRadiusKm-distance < coincident:
+		return nil, fmt.Errorf("A and B are antipodes, every direction leads from one to the other")
+	}
+
+	degrees := geo.Bearing(lat1, lon1, lat2, lon2)
+	return &Result{Degrees: degrees, Cardinal: geo.CompassPoints[geo.CompassPoint(degrees)], DistanceKm: distance}, nil
+}
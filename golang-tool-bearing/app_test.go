@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// The expected values were computed independently with unit vectors: the
+// distance from the angle between A and B, the bearing from the direction of
+// B projected on the plane tangent at A.
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		degrees                float64
+		cardinal               string
+		distanceKm             float64
+	}{
+		{"london to paris", 51.5074, -0.1278, 48.8566, 2.3522, 148.1, "SSE", 343.6},
+		{"new york jfk to london heathrow", 40.6413, -73.7781, 51.47, -0.4543, 51.4, "NE", 5540.0},
+		{"san francisco to tokyo", 37.7749, -122.4194, 35.6762, 139.6503, 303.4, "WNW", 8274.6},
+		{"sydney to santiago", -33.8688, 151.2093, -33.4489, -70.6693, 145.3, "SE", 11346.7},
+		{"quito due east", -0.18, -78.47, -0.18, -70, 90.0, "E", 941.8},
+	}
+	for _, tt := range tests {
+		got, err := Bearing(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+		if err != nil {
+			t.Errorf("%s: Bearing() error = %v", tt.name, err)
+			continue
+		}
+		if math.Abs(got.Degrees-tt.degrees) > 0.1 || got.Cardinal != tt.cardinal || math.Abs(got.DistanceKm-tt.distanceKm) > 0.1 {
+			t.Errorf("%s: Bearing() = %+v, want %.1f° %s %.1f km", tt.name, *got, tt.degrees, tt.cardinal, tt.distanceKm)
+		}
+	}
+}
+
+func TestBearingInvalid(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+	}{
+		{"same point", 48.85, 2.35, 48.85, 2.35},
+		{"antipodes", 10, 20, -10, -160},
+		{"latitude out of range", 91, 0, 0, 0},
+		{"longitude out of range", 0, 0, 0, 181},
+		{"not a number", math.NaN(), 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if _, err := Bearing(tt.lat1, tt.lon1, tt.lat2, tt.lon2); err == nil {
+			t.Errorf("%s: Bearing() should fail", tt.name)
+		}
+	}
+}
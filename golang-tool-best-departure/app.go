@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Recommend the best hour to leave within a time window from the weather forecast: the driest and calmest hour, e.g. "when should I start my bike ride between 2 PM and 8 PM?". If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns the recommended local hour with the reason, and the hour to avoid.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the departure location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the departure location in decimal format,minimum=-180,maximum=180"`
+	Date      string  `json:"date,omitempty" jsonschema:"description=The local date of the window in YYYY-MM-DD format. Defaults to today,example=2024-08-10"`
+	From      string  `json:"from" jsonschema:"description=The earliest local departure time in HH:MM format,example=14:00"`
+	To        string  `json:"to" jsonschema:"description=The latest local departure time in HH:MM format. A time earlier than from is on the next day,example=20:00"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "best-departure", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE5}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "date", msg.Date, "from", msg.From, "to", msg.To)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := BestDeparture(reqCtx, msg, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] BestDeparture error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not recommend a departure time: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// BestDeparture fetches the forecast at the coordinates of p and recommends
+// the best hour of its window after now.
+func BestDeparture(ctx context.Context, p Parameter, now time.Time) (string, error) {
+	if err := geo.ValidateCoordinate(p.Latitude, p.Longitude); err != nil {
+		return "", err
+	}
+	// check the format before spending an API call, the window is only
+	// known in the time zone of the forecast
+	if _, _, err := Window(p.Date, p.From, p.To, time.UTC, time.Time{}); err != nil {
+		return "", err
+	}
+
+	forecast, err := client.Forecast(ctx, p.Latitude, p.Longitude)
+	if err != nil {
+		return "", err
+	}
+	loc := forecast.Location()
+	start, end, err := Window(p.Date, p.From, p.To, loc, now)
+	if err != nil {
+		return "", err
+	}
+	d, err := Best(forecast.Slots, start, end)
+	if err != nil {
+		return "", err
+	}
+	return d.Describe(start, end, loc), nil
+}
+
+// Window resolves the departure window of the HH:MM times from and to on
+// date in loc, today when date is empty. A to earlier than from is on the
+// next day, and a window that began already starts at now.
+func Window(date, from, to string, loc *time.Location, now time.Time) (time.Time, time.Time, error) {
+	now = now.In(loc)
+	if date == "" {
+		date = now.Format("2006-01-02")
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", date+" "+from, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("can not understand the date %q and time %q, please use YYYY-MM-DD and HH:MM", date, from)
+	}
+	end, err := time.ParseInLocation("2006-01-02 15:04", date+" "+to, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("can not understand the time %q, please use HH:MM", to)
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the window from %s to %s is in the past", from, to)
+	}
+	if start.Before(now) {
+		start = now
+	}
+	return start, end, nil
+}
+
+// slotLength is the step of the forecast.
+const slotLength = 3 * time.Hour
+
+// Score rates the weather of a slot for leaving, lower is better. A
+// certain rain weighs as much as 20 m/s of wind, so the driest hour wins
+// and the wind breaks the ties between dry hours:
+//   - 10 per probability of precipitation, from 0 to 1
+//   - 2 per mm of rain or snow in the 3 hours
+//   - 0.5 per m/s of wind
+//   - 5 for thunderstorms
+func Score(s weather.Slot) float64 {
+	score := 10*s.PrecipitationChance + 2*(s.Rain3h+s.Snow3h) + 0.5*s.WindSpeed
+	if s.ConditionID >= 200 && s.ConditionID < 300 {
+		score += 5
+	}
+	return score
+}
+
+// Departure is a scored hour of the window.
+type Departure struct {
+	Time  time.Time
+	Slot  weather.Slot
+	Score float64
+	// Worst is the hour of the window with the highest score.
+	Worst *Departure
+}
+
+// Best scores every whole hour from start to end with the forecast slot
+// covering it and returns the one with the lowest score, the earliest of
+// equal ones. The hours are whole in the location of start, and a window
+// without a whole hour is scored at start.
+func Best(slots []weather.Slot, start, end time.Time) (*Departure, error) {
+	var hours []time.Time
+	first := time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), 0, 0, 0, start.Location())
+	for h := first; !h.After(end); h = h.Add(time.Hour) {
+		if !h.Before(start) {
+			hours = append(hours, h)
+		}
+	}
+	if len(hours) == 0 {
+		hours = append(hours, start)
+	}
+
+	var best, worst *Departure
+	for _, h := range hours {
+		slot, ok := covering(slots, h)
+		if !ok {
+			continue
+		}
+		d := &Departure{Time: h, Slot: slot, Score: Score(slot)}
+		if best == nil || d.Score < best.Score {
+			best = d
+		}
+		if worst == nil || d.Score > worst.Score {
+			worst = d
+		}
+	}
+	if best == nil {
+		return nil, errors.New("the forecast does not cover the window, it only reaches 5 days ahead")
+	}
+	best.Worst = worst
+	return best, nil
+}
+
+// covering returns the slot that t falls in.
+func covering(slots []weather.Slot, t time.Time) (weather.Slot, bool) {
+	for _, s := range slots {
+		if !t.Before(s.Time) && t.Before(s.Time.Add(slotLength)) {
+			return s, true
+		}
+	}
+	return weather.Slot{}, false
+}
+
+// Describe tells the recommended hour with its weather, and the worst hour
+// when it is clearly worse, with the times in loc, e.g. "Leave around 3 PM,
+// the driest and calmest hour between 2 PM and 8 PM: 10% chance of
+// precipitation, wind 2.0 m/s. Avoid 6 PM: 80% chance of precipitation,
+// rain 2.4 mm, wind 8.0 m/s."
+func (d *Departure) Describe(start, end time.Time, loc *time.Location) string {
+	s := fmt.Sprintf("Leave around %s, the driest and calmest hour between %s and %s: %s.",
+		clock(d.Time, loc), clock(start, loc), clock(end, loc), conditions(d.Slot))
+	if d.Worst == nil || d.Worst.Score-d.Score < 1 {
+		return s + " The weather is about the same over the whole window."
+	}
+	return s + fmt.Sprintf(" Avoid %s: %s.", clock(d.Worst.Time, loc), conditions(d.Worst.Slot))
+}
+
+// conditions lists what the score of a slot is made of.
+func conditions(s weather.Slot) string {
+	var parts []string
+	if s.Description != "" {
+		parts = append(parts, s.Description)
+	}
+	parts = append(parts, fmt.Sprintf("%.0f%% chance of precipitation", s.PrecipitationChance*100))
+	if s.Rain3h > 0 {
+		parts = append(parts, fmt.Sprintf("rain %.1f mm", s.Rain3h))
+	}
+	if s.Snow3h > 0 {
+		parts = append(parts, fmt.Sprintf("snow %.1f mm", s.Snow3h))
+	}
+	parts = append(parts, fmt.Sprintf("wind %.1f m/s", s.WindSpeed))
+	return strings.Join(parts, ", ")
+}
+
+// clock is the local time of day, e.g. "3 PM", or "5:30 PM" off the hour.
+func clock(t time.Time, loc *time.Location) string {
+	t = t.In(loc)
+	if t.Minute() != 0 {
+		return t.Format("3:04 PM")
+	}
+	return t.Format("3 PM")
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+// now is 9:30 in UTC+2, the slots begin at 8 AM local.
+var (
+	now   = time.Date(2024, 8, 10, 7, 30, 0, 0, time.UTC)
+	local = time.FixedZone("", 2*3600)
+)
+
+type slotWeather struct {
+	pop, rain, wind float64
+	condition       int
+}
+
+// slots returns the 3-hour slots from 6:00 UTC with the given weather.
+func slots(ws ...slotWeather) []weather.Slot {
+	s := make([]weather.Slot, len(ws))
+	for i, w := range ws {
+		s[i].Time = time.Date(2024, 8, 10, 6+3*i, 0, 0, 0, time.UTC)
+		s[i].PrecipitationChance = w.pop
+		s[i].Rain3h = w.rain
+		s[i].WindSpeed = w.wind
+		s[i].ConditionID = w.condition
+		if s[i].ConditionID == 0 {
+			s[i].ConditionID = 800
+		}
+	}
+	return s
+}
+
+// day is a day with a calm morning outside the windows of the tests, a
+// showery noon, a windy afternoon, a dry and calm evening and a
+// thunderstorm at night.
+var day = slots(
+	slotWeather{},                                             // 8 AM
+	slotWeather{pop: 0.6, rain: 1, wind: 3},                   // 11 AM
+	slotWeather{pop: 0.1, wind: 6},                            // 2 PM
+	slotWeather{wind: 2},                                      // 5 PM
+	slotWeather{pop: 0.8, rain: 2.4, wind: 8, condition: 211}, // 8 PM
+	slotWeather{pop: 0.2, wind: 4},                            // 11 PM
+)
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  string
+		slots     []weather.Slot
+		want      string
+		wantWorst string
+	}{
+		{name: "dry and calm evening", from: "12:00", to: "21:00", slots: day, want: "17:00", wantWorst: "20:00"},
+		{name: "the wind breaks the tie of dry hours", from: "14:00", to: "19:00", slots: day, want: "17:00", wantWorst: "14:00"},
+		{name: "the earliest of equal hours", from: "17:00", to: "19:30", slots: day, want: "17:00", wantWorst: "17:00"},
+		{name: "overnight", from: "22:00", to: "01:00", slots: day, want: "23:00", wantWorst: "22:00"},
+		// the window began already, it starts at 9:30
+		{name: "from now", from: "09:00", to: "11:00", slots: day, want: "10:00", wantWorst: "11:00"},
+		{name: "no whole hour", from: "17:10", to: "17:50", slots: day, want: "17:10", wantWorst: "17:10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := Window("2024-08-10", tt.from, tt.to, local, now)
+			if err != nil {
+				t.Fatalf("Window() error = %v", err)
+			}
+			d, err := Best(tt.slots, start, end)
+			if err != nil {
+				t.Fatalf("Best() error = %v", err)
+			}
+			if got := d.Time.In(local).Format("15:04"); got != tt.want {
+				t.Errorf("Best() = %s, want %s", got, tt.want)
+			}
+			if got := d.Worst.Time.In(local).Format("15:04"); got != tt.wantWorst {
+				t.Errorf("Best().Worst = %s, want %s", got, tt.wantWorst)
+			}
+		})
+	}
+
+	// the forecast ends at 2 AM
+	start, end, _ := Window("2024-08-11", "08:00", "12:00", local, now)
+	if _, err := Best(day, start, end); err == nil {
+		t.Error("Best() of a window after the forecast should fail")
+	}
+}
+
+func TestScore(t *testing.T) {
+	dry := weather.Slot{}
+	dry.WindSpeed = 10
+	wet := weather.Slot{PrecipitationChance: 1}
+	if Score(wet) <= Score(dry) {
+		t.Errorf("a certain rain scores %v, less than a dry 10 m/s wind %v", Score(wet), Score(dry))
+	}
+	storm := day[4]
+	rain := storm
+	rain.ConditionID = 501
+	if Score(storm) <= Score(rain) {
+		t.Errorf("a thunderstorm scores %v, no more than the rain %v", Score(storm), Score(rain))
+	}
+}
+
+func TestWindow(t *testing.T) {
+	start, end, err := Window("", "22:00", "06:00", local, now)
+	if err != nil {
+		t.Fatalf("Window() error = %v", err)
+	}
+	if want := time.Date(2024, 8, 10, 22, 0, 0, 0, local); !start.Equal(want) {
+		t.Errorf("start = %v, want %v", start, want)
+	}
+	if want := time.Date(2024, 8, 11, 6, 0, 0, 0, local); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+
+	for _, w := range [][3]string{
+		{"2024-08-10", "06:00", "09:00"},
+		{"2024-08-09", "12:00", "18:00"},
+		{"10/08/2024", "12:00", "18:00"},
+		{"2024-08-10", "noon", "18:00"},
+		{"2024-08-10", "12:00", "25:00"},
+	} {
+		if _, _, err := Window(w[0], w[1], w[2], local, now); err == nil {
+			t.Errorf("Window(%q) should fail", w)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	start, end, _ := Window("2024-08-10", "12:00", "21:00", local, now)
+	d, err := Best(day, start, end)
+	if err != nil {
+		t.Fatalf("Best() error = %v", err)
+	}
+	want := "Leave around 5 PM, the driest and calmest hour between 12 PM and 9 PM: 0% chance of precipitation, wind 2.0 m/s. Avoid 8 PM: 80% chance of precipitation, rain 2.4 mm, wind 8.0 m/s."
+	if got := d.Describe(start, end, local); got != want {
+		t.Errorf("Describe() =\n%s\nwant\n%s", got, want)
+	}
+
+	calm := slots(slotWeather{wind: 1}, slotWeather{wind: 1.5})
+	start, end, _ = Window("2024-08-10", "10:00", "12:00", local, now)
+	d, _ = Best(calm, start, end)
+	if got := d.Describe(start, end, local); !strings.HasSuffix(got, "about the same over the whole window.") {
+		t.Errorf("Describe() = %s, want the same weather", got)
+	}
+}
+
+func TestBestDeparture(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/2.5/forecast" {
+			http.NotFound(w, r)
+			return
+		}
+		var list []string
+		for _, s := range day {
+			list = append(list, fmt.Sprintf(`{"dt":%d,"main":{"temp":20},"wind":{"speed":%v},"weather":[{"id":%d,"description":"sky"}],"pop":%v,"rain":{"3h":%v}}`,
+				s.Time.Unix(), s.WindSpeed, s.ConditionID, s.PrecipitationChance, s.Rain3h))
+		}
+		fmt.Fprintf(w, `{"list":[%s],"city":{"name":"Paris","country":"FR","timezone":7200}}`, strings.Join(list, ","))
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := BestDeparture(context.Background(), Parameter{Latitude: 48.8566, Longitude: 2.3522, Date: "2024-08-10", From: "12:00", To: "21:00"}, now)
+	if err != nil {
+		t.Fatalf("BestDeparture() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "Leave around 5 PM") {
+		t.Errorf("BestDeparture() = %s, want 5 PM", got)
+	}
+
+	for _, p := range []Parameter{
+		{Latitude: 91, From: "12:00", To: "21:00"},
+		{From: "noon", To: "21:00"},
+	} {
+		if _, err := BestDeparture(context.Background(), p, now); err == nil {
+			t.Errorf("BestDeparture(%+v) should fail", p)
+		}
+	}
+}
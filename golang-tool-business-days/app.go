@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Count the business days between two dates, both included, excluding Saturdays and Sundays. If a country is given, its nationwide public holidays are excluded too. The dates are in the format YYYY-MM-DD and the country is a 2-letter ISO 3166-1 code, e.g. "US" or "DE".`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Start   string `json:"start" jsonschema:"description=The first day of the span in the format YYYY-MM-DD"`
+	End     string `json:"end" jsonschema:"description=The last day of the span in the format YYYY-MM-DD"`
+	Country string `json:"country,omitempty" jsonschema:"description=The 2-letter ISO 3166-1 code of the country whose public holidays are excluded"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "business-days", Description: Description(), InputSchema: InputSchema(), Upstream: api.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xCB}
+}
+
+var api = &Nager{
+	BaseURL:    "https://date.nager.at",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "start", msg.Start, "end", msg.End, "country", msg.Country)
+
+	// a span over several years requests the holidays of each
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := BusinessDays(reqCtx, api, msg.Start, msg.End, msg.Country)
+	if err != nil {
+		slog.Warn("[sfn] BusinessDays error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not count the business days: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, result.String())
+}
+
+const dateFormat = "2006-01-02"
+
+// maxYears limits the span, and so the number of holiday requests.
+const maxYears = 5
+
+// Holiday is a public holiday.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// Result is the count of business days in a span.
+type Result struct {
+	Start, End time.Time
+	Country    string
+	Days       int
+	// Excluded are the holidays that fell on a weekday of the span.
+	Excluded []Holiday
+}
+
+func (r *Result) String() string {
+	s := fmt.Sprintf("There are %d business days from %s to %s included, excluding weekends", r.Days, r.Start.Format(dateFormat), r.End.Format(dateFormat))
+	if r.Country == "" {
+		return s + "."
+	}
+	if len(r.Excluded) == 0 {
+		return s + fmt.Sprintf(". No public holiday of %s falls on a weekday of the span.", r.Country)
+	}
+	names := make([]string, len(r.Excluded))
+	for i, h := range r.Excluded {
+		names[i] = fmt.Sprintf("%s (%s)", h.Name, h.Date.Format(dateFormat))
+	}
+	return s + fmt.Sprintf(" and %d public holidays of %s: %s.", len(r.Excluded), r.Country, strings.Join(names, ", "))
+}
+
+// BusinessDays counts the weekdays from start to end, both included. With a
+// country, its public holidays are excluded as well.
+func BusinessDays(ctx context.Context, api *Nager, start, end, country string) (*Result, error) {
+	s, err := time.Parse(dateFormat, strings.TrimSpace(start))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a date in the format YYYY-MM-DD", start)
+	}
+	e, err := time.Parse(dateFormat, strings.TrimSpace(end))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a date in the format YYYY-MM-DD", end)
+	}
+	if e.Before(s) {
+		return nil, fmt.Errorf("the end %s is before the start %s", end, start)
+	}
+	if e.Year()-s.Year() >= maxYears {
+		return nil, fmt.Errorf("the span can cover at most %d years", maxYears)
+	}
+
+	var holidays []Holiday
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country != "" {
+		if len(country) != 2 {
+			return nil, fmt.Errorf("%q is not a 2-letter ISO 3166-1 country code", country)
+		}
+		for year := s.Year(); year <= e.Year(); year++ {
+			h, err := api.Holidays(ctx, year, country)
+			if err != nil {
+				return nil, err
+			}
+			holidays = append(holidays, h...)
+		}
+	}
+
+	days, excluded := Count(s, e, holidays)
+	return &Result{Start: s, End: e, Country: country, Days: days, Excluded: excluded}, nil
+}
+
+// Count returns the number of weekdays from start to end, both included,
+// that are not holidays, and the holidays it skipped. A holiday on a weekend
+// is not skipped twice.
+func Count(start, end time.Time, holidays []Holiday) (int, []Holiday) {
+	byDate := make(map[time.Time]Holiday, len(holidays))
+	for _, h := range holidays {
+		if _, ok := byDate[h.Date]; !ok {
+			byDate[h.Date] = h
+		}
+	}
+
+	var days int
+	var excluded []Holiday
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if wd := d.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			continue
+		}
+		if h, ok := byDate[d]; ok {
+			excluded = append(excluded, h)
+			continue
+		}
+		days++
+	}
+	return days, excluded
+}
+
+// Nager is a client of the Nager.Date public holiday API.
+type Nager struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Holidays returns the nationwide public holidays of country in year.
+// Regional holidays, e.g. of a single German state, are left out.
+func (n *Nager) Holidays(ctx context.Context, year int, country string) ([]Holiday, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v3/PublicHolidays/%d/%s", n.BaseURL, year, country), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("the holidays of the country %s are not known", country)
+	default:
+		return nil, fmt.Errorf("the holiday API responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var body []struct {
+		Date   string `json:"date"`
+		Name   string `json:"name"`
+		Global bool   `json:"global"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	var holidays []Holiday
+	for _, h := range body {
+		d, err := time.Parse(dateFormat, h.Date)
+		if err != nil || !h.Global {
+			continue
+		}
+		holidays = append(holidays, Holiday{Date: d, Name: h.Name})
+	}
+	return holidays, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	d, err := time.Parse(dateFormat, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestCount(t *testing.T) {
+	christmas := []Holiday{
+		{Date: day("2024-12-25"), Name: "Christmas Day"},
+		{Date: day("2024-12-26"), Name: "Boxing Day"},
+		// on a Saturday, so not a weekday to skip
+		{Date: day("2024-12-28"), Name: "A Saturday Holiday"},
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		holidays   []Holiday
+		want       int
+		skipped    int
+	}{
+		{"one weekday", "2024-12-23", "2024-12-23", nil, 1, 0},
+		{"one saturday", "2024-12-21", "2024-12-21", nil, 0, 0},
+		{"a full week", "2024-12-16", "2024-12-22", nil, 5, 0},
+		{"christmas week without holidays", "2024-12-23", "2024-12-29", nil, 5, 0},
+		{"christmas week with holidays", "2024-12-23", "2024-12-29", christmas, 3, 2},
+		{"span without a holiday", "2024-12-02", "2024-12-13", christmas, 10, 0},
+		{"across a month", "2024-01-29", "2024-02-09", nil, 10, 0},
+	}
+	for _, tt := range tests {
+		got, skipped := Count(day(tt.start), day(tt.end), tt.holidays)
+		if got != tt.want || len(skipped) != tt.skipped {
+			t.Errorf("%s: Count() = %d skipping %v, want %d skipping %d", tt.name, got, skipped, tt.want, tt.skipped)
+		}
+	}
+}
+
+func TestBusinessDays(t *testing.T) {
+	var years []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		years = append(years, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/v3/PublicHolidays/2024/GB":
+			w.Write([]byte(`[
+				{"date":"2024-12-25","name":"Christmas Day","global":true},
+				{"date":"2024-12-26","name":"Boxing Day","global":true},
+				{"date":"2024-12-27","name":"A Regional Holiday","global":false}
+			]`))
+		case "/api/v3/PublicHolidays/2025/GB":
+			w.Write([]byte(`[{"date":"2025-01-01","name":"New Year's Day","global":true}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	api := &Nager{BaseURL: srv.URL, HTTPClient: srv.Client()}
+
+	got, err := BusinessDays(context.Background(), api, "2024-12-23", "2025-01-03", "gb")
+	if err != nil {
+		t.Fatalf("BusinessDays() error = %v", err)
+	}
+	want := "There are 7 business days from 2024-12-23 to 2025-01-03 included, excluding weekends and 3 public holidays of GB: Christmas Day (2024-12-25), Boxing Day (2024-12-26), New Year's Day (2025-01-01)."
+	if got.String() != want {
+		t.Errorf("BusinessDays() =\n%s\nwant\n%s", got, want)
+	}
+	if len(years) != 2 {
+		t.Errorf("requested %v, want the holidays of 2024 and 2025", years)
+	}
+
+	years = nil
+	got, err = BusinessDays(context.Background(), api, "2024-12-23", "2025-01-03", "")
+	if err != nil || got.Days != 10 || len(years) != 0 {
+		t.Errorf("BusinessDays() without a country = %v, %v after %d requests, want 10 days", got, err, len(years))
+	}
+
+	for _, args := range [][3]string{
+		{"2024-12-31", "2024-12-01", ""},
+		{"2024-13-01", "2024-12-31", ""},
+		{"2024-12-01", "tomorrow", ""},
+		{"2020-01-01", "2025-12-31", ""},
+		{"2024-12-01", "2024-12-31", "GBR"},
+		{"2024-12-01", "2024-12-31", "XX"},
+	} {
+		if _, err := BusinessDays(context.Background(), api, args[0], args[1], args[2]); err == nil {
+			t.Errorf("BusinessDays(%v) should fail", args)
+		}
+	}
+}
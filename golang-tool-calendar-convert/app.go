@@ -0,0 +1,313 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert a date between calendar systems: the Gregorian calendar, the Julian calendar, the ISO week date, the Unix day number and the Julian day number, e.g. "what was 4 October 1582 of the Julian calendar in the Gregorian calendar?" or "which ISO week is 2024-12-30?". The function returns the converted date and its day of the week.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Date string `json:"date" jsonschema:"description=The date to convert: YYYY-MM-DD for the Gregorian and Julian calendars or YYYY-Www-D for an ISO week date or an integer for a day number,example=2024-06-10"`
+	From string `json:"from,omitempty" jsonschema:"description=The calendar of the date. Defaults to gregorian,enum=gregorian,enum=julian,enum=iso-week,enum=unix-day,enum=julian-day"`
+	To   string `json:"to" jsonschema:"description=The calendar to convert the date to,enum=gregorian,enum=julian,enum=iso-week,enum=unix-day,enum=julian-day"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "calendar-convert", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE1}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	result, err := Convert(msg.Date, msg.From, msg.To)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert the date %q: %v", msg.Date, err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// A date is converted through its Julian day number, the days elapsed since
+// noon of 1 January 4713 BC of the Julian calendar.
+const (
+	// unixEpoch is the Julian day number of 1970-01-01.
+	unixEpoch = 2440588
+	// cutover is the Julian day number of 1582-10-15, the first day of the
+	// Gregorian calendar, which followed 1582-10-04 of the Julian calendar.
+	cutover = 2299161
+	// minDay and maxDay are 0001-01-01 and 9999-12-31 of the Gregorian
+	// calendar, the range of the dates converted.
+	minDay = 1721426
+	maxDay = 5373484
+)
+
+type calendar struct {
+	name   string
+	parse  func(string) (int, error)
+	format func(int) string
+}
+
+var calendars = map[string]calendar{
+	"gregorian": {
+		name:   "the Gregorian calendar",
+		parse:  func(s string) (int, error) { return parseDate(s, true) },
+		format: func(jdn int) string { return formatDate(jdn, true) },
+	},
+	"julian": {
+		name:   "the Julian calendar",
+		parse:  func(s string) (int, error) { return parseDate(s, false) },
+		format: func(jdn int) string { return formatDate(jdn, false) },
+	},
+	"iso-week": {
+		name:   "the ISO week date",
+		parse:  parseISOWeek,
+		format: formatISOWeek,
+	},
+	"unix-day": {
+		name:   "the Unix day number",
+		parse:  func(s string) (int, error) { return parseDayNumber(s, unixEpoch) },
+		format: func(jdn int) string { return strconv.Itoa(jdn - unixEpoch) },
+	},
+	"julian-day": {
+		name:   "the Julian day number",
+		parse:  func(s string) (int, error) { return parseDayNumber(s, 0) },
+		format: strconv.Itoa,
+	},
+}
+
+// aliases maps other common spellings to the keys of calendars.
+var aliases = map[string]string{
+	"":                  "gregorian",
+	"iso":               "iso-week",
+	"isoweek":           "iso-week",
+	"iso-8601":          "iso-week",
+	"unix":              "unix-day",
+	"jdn":               "julian-day",
+	"julian-day-number": "julian-day",
+}
+
+func lookupCalendar(name string) (string, calendar, bool) {
+	key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+	if alias, ok := aliases[key]; ok {
+		key = alias
+	}
+	c, ok := calendars[key]
+	return key, c, ok
+}
+
+// Convert converts date from one calendar to another.
+func Convert(date, from, to string) (string, error) {
+	fromKey, f, ok := lookupCalendar(from)
+	if !ok {
+		return "", fmt.Errorf("unknown calendar %q", from)
+	}
+	if strings.TrimSpace(to) == "" {
+		return "", errors.New("the calendar to convert to is missing")
+	}
+	toKey, t, ok := lookupCalendar(to)
+	if !ok {
+		return "", fmt.Errorf("unknown calendar %q", to)
+	}
+
+	jdn, err := f.parse(strings.TrimSpace(date))
+	if err != nil {
+		return "", err
+	}
+	if jdn < minDay || jdn > maxDay {
+		return "", errors.New("the date is out of the supported range, from 0001-01-01 to 9999-12-31 of the Gregorian calendar")
+	}
+
+	result := fmt.Sprintf("%s of %s is %s in %s, a %s", f.format(jdn), f.name, t.format(jdn), t.name, Weekday(jdn))
+	// most countries adopted the Gregorian calendar after 1582, some as late
+	// as the 20th century
+	if jdn < cutover && (fromKey == "gregorian" || toKey == "gregorian") {
+		result += ". The date is before 1582-10-15, when the Gregorian calendar was introduced, so it is a proleptic Gregorian date"
+	}
+	return result, nil
+}
+
+// Weekday returns the day of the week of a Julian day number.
+func Weekday(jdn int) time.Weekday {
+	return time.Weekday((jdn + 1) % 7)
+}
+
+// JulianDay returns the Julian day number of a date of the Gregorian
+// calendar, or of the Julian calendar if gregorian is false.
+func JulianDay(year, month, day int, gregorian bool) int {
+	// the algorithm counts the years from March, so that the leap day is
+	// the last day of the year
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	jdn := day + (153*m+2)/5 + 365*y + y/4
+	if gregorian {
+		return jdn - y/100 + y/400 - 32045
+	}
+	return jdn - 32083
+}
+
+// FromJulianDay returns the date of the Gregorian calendar, or of the Julian
+// calendar if gregorian is false, of a Julian day number. It is the algorithm
+// of E. G. Richards in the Explanatory Supplement to the Astronomical
+// Almanac.
+func FromJulianDay(jdn int, gregorian bool) (year, month, day int) {
+	f := jdn + 1401
+	if gregorian {
+		f += (4*jdn+274277)/146097*3/4 - 38
+	}
+	e := 4*f + 3
+	g := e % 1461 / 4
+	h := 5*g + 2
+	day = h%153/5 + 1
+	month = (h/153+2)%12 + 1
+	year = e/1461 - 4716 + (14-month)/12
+	return year, month, day
+}
+
+func isLeap(year int, gregorian bool) bool {
+	if gregorian {
+		return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+	}
+	return year%4 == 0
+}
+
+func daysIn(year, month int, gregorian bool) int {
+	switch month {
+	case 2:
+		if isLeap(year, gregorian) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	default:
+		return 31
+	}
+}
+
+var datePattern = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+
+func parseDate(s string, gregorian bool) (int, error) {
+	m := datePattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("the date %q is not in YYYY-MM-DD format", s)
+	}
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	if month < 1 || month > 12 {
+		return 0, fmt.Errorf("the month %d does not exist", month)
+	}
+	if day < 1 || day > daysIn(year, month, gregorian) {
+		name := "Julian"
+		if gregorian {
+			name = "Gregorian"
+		}
+		return 0, fmt.Errorf("the day %s does not exist in the %s calendar", s, name)
+	}
+	return JulianDay(year, month, day, gregorian), nil
+}
+
+func formatDate(jdn int, gregorian bool) string {
+	year, month, day := FromJulianDay(jdn, gregorian)
+	return fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+}
+
+var isoWeekPattern = regexp.MustCompile(`^(\d{4})-?W(\d{2})(?:-?([1-7]))?$`)
+
+// parseISOWeek parses an ISO 8601 week date, e.g. "2024-W24-1". The day of
+// the week, 1 for Monday to 7 for Sunday, defaults to Monday.
+func parseISOWeek(s string) (int, error) {
+	m := isoWeekPattern.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil {
+		return 0, fmt.Errorf("the ISO week date %q is not in YYYY-Www-D format", s)
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	day := 1
+	if m[3] != "" {
+		day, _ = strconv.Atoi(m[3])
+	}
+	if year < 1 {
+		return 0, fmt.Errorf("the year %d is out of the supported range", year)
+	}
+	// the week 1 of a year is the one with its first Thursday, and so with
+	// 4 January; the last week is the one with 28 December
+	weeks := isoWeek(JulianDay(year, 12, 28, true))
+	if week < 1 || week > weeks {
+		return 0, fmt.Errorf("the year %d has no week %d, it has %d weeks", year, week, weeks)
+	}
+	jan4 := JulianDay(year, 1, 4, true)
+	monday := jan4 - isoWeekday(jan4) + 1
+	return monday + (week-1)*7 + day - 1, nil
+}
+
+func formatISOWeek(jdn int) string {
+	year, month, day := FromJulianDay(jdn, true)
+	isoYear, week := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return fmt.Sprintf("%04d-W%02d-%d", isoYear, week, isoWeekday(jdn))
+}
+
+func isoWeek(jdn int) int {
+	year, month, day := FromJulianDay(jdn, true)
+	_, week := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// isoWeekday returns the ISO day of the week of a Julian day number, 1 for
+// Monday to 7 for Sunday.
+func isoWeekday(jdn int) int {
+	return jdn%7 + 1
+}
+
+func parseDayNumber(s string, epoch int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("the day number %q is not an integer", s)
+	}
+	return n + epoch, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJulianDay(t *testing.T) {
+	tests := []struct {
+		year, month, day int
+		gregorian        bool
+		want             int
+	}{
+		{2000, 1, 1, true, 2451545},
+		{1970, 1, 1, true, 2440588},
+		{1582, 10, 15, true, 2299161},
+		{1582, 10, 4, false, 2299160},
+		{1, 1, 1, true, 1721426},
+		{1, 1, 1, false, 1721424},
+		{9999, 12, 31, true, 5373484},
+	}
+	for _, tt := range tests {
+		got := JulianDay(tt.year, tt.month, tt.day, tt.gregorian)
+		if got != tt.want {
+			t.Errorf("JulianDay(%d, %d, %d, %v) = %d, want %d", tt.year, tt.month, tt.day, tt.gregorian, got, tt.want)
+		}
+		if y, m, d := FromJulianDay(got, tt.gregorian); y != tt.year || m != tt.month || d != tt.day {
+			t.Errorf("FromJulianDay(%d, %v) = %d-%d-%d, want %d-%d-%d", got, tt.gregorian, y, m, d, tt.year, tt.month, tt.day)
+		}
+	}
+
+	// every day of the range round trips, and agrees with the time package
+	for jdn := minDay; jdn <= maxDay; jdn += 97 {
+		y, m, d := FromJulianDay(jdn, true)
+		if got := JulianDay(y, m, d, true); got != jdn {
+			t.Fatalf("JulianDay(FromJulianDay(%d)) = %d", jdn, got)
+		}
+		date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+		if date.Weekday() != Weekday(jdn) || date.Unix()/86400 != int64(jdn-unixEpoch) {
+			t.Fatalf("the Julian day %d is not %v", jdn, date)
+		}
+		y, m, d = FromJulianDay(jdn, false)
+		if got := JulianDay(y, m, d, false); got != jdn {
+			t.Fatalf("JulianDay(FromJulianDay(%d, false)) = %d", jdn, got)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		date, from, to string
+		want           string
+	}{
+		// the day after 4 October 1582 in the Julian calendar was 15 October
+		// 1582 in the Gregorian calendar
+		{"1582-10-04", "julian", "gregorian", "1582-10-04 of the Julian calendar is 1582-10-14 in the Gregorian calendar, a Thursday"},
+		{"1582-10-05", "julian", "gregorian", "1582-10-05 of the Julian calendar is 1582-10-15 in the Gregorian calendar, a Friday"},
+		{"1582-10-15", "", "julian", "1582-10-15 of the Gregorian calendar is 1582-10-05 in the Julian calendar, a Friday"},
+		// the October Revolution and Julian leap day 1900 that the Gregorian
+		// calendar skips
+		{"1917-10-25", "julian", "gregorian", "1917-10-25 of the Julian calendar is 1917-11-07 in the Gregorian calendar, a Wednesday"},
+		{"1900-02-29", "julian", "gregorian", "1900-02-29 of the Julian calendar is 1900-03-13 in the Gregorian calendar, a Tuesday"},
+		{"2024-06-10", "gregorian", "julian", "2024-06-10 of the Gregorian calendar is 2024-05-28 in the Julian calendar, a Monday"},
+		{"2000-01-01", "gregorian", "julian-day", "2000-01-01 of the Gregorian calendar is 2451545 in the Julian day number, a Saturday"},
+		{"2451545", "JDN", "gregorian", "2451545 of the Julian day number is 2000-01-01 in the Gregorian calendar, a Saturday"},
+		{"1970-01-01", "gregorian", "unix-day", "1970-01-01 of the Gregorian calendar is 0 in the Unix day number, a Thursday"},
+		{"19884", "unix", "gregorian", "19884 of the Unix day number is 2024-06-10 in the Gregorian calendar, a Monday"},
+		{"-1", "unix-day", "iso-week", "-1 of the Unix day number is 1970-W01-3 in the ISO week date, a Wednesday"},
+		{"2008-12-29", "gregorian", "iso-week", "2008-12-29 of the Gregorian calendar is 2009-W01-1 in the ISO week date, a Monday"},
+		{"2010-01-03", "gregorian", "ISO Week", "2010-01-03 of the Gregorian calendar is 2009-W53-7 in the ISO week date, a Sunday"},
+		{"2009-W53-7", "iso-week", "gregorian", "2009-W53-7 of the ISO week date is 2010-01-03 in the Gregorian calendar, a Sunday"},
+		{"2024W01", "iso", "gregorian", "2024-W01-1 of the ISO week date is 2024-01-01 in the Gregorian calendar, a Monday"},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.date, tt.from, tt.to)
+		if err != nil {
+			t.Errorf("Convert(%q, %q, %q) error = %v", tt.date, tt.from, tt.to, err)
+			continue
+		}
+		if !strings.HasPrefix(got, tt.want) {
+			t.Errorf("Convert(%q, %q, %q) = %s, want %s", tt.date, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvertProleptic(t *testing.T) {
+	got, err := Convert("1066-10-14", "julian", "gregorian")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "1066-10-14 of the Julian calendar is 1066-10-20 in the Gregorian calendar") ||
+		!strings.Contains(got, "proleptic Gregorian date") {
+		t.Errorf("Convert() = %s", got)
+	}
+
+	got, err = Convert("1066-10-14", "julian", "unix-day")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "proleptic") {
+		t.Errorf("Convert() = %s, no Gregorian date is involved", got)
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	tests := []struct {
+		date, from, to string
+		want           string
+	}{
+		{"1900-02-29", "gregorian", "julian", "does not exist in the Gregorian calendar"},
+		{"2023-02-29", "julian", "gregorian", "does not exist in the Julian calendar"},
+		{"2024-13-01", "gregorian", "julian", "month 13"},
+		{"10/06/2024", "gregorian", "julian", "YYYY-MM-DD"},
+		{"2024-W53", "iso-week", "gregorian", "has no week 53"},
+		{"2024-W01-8", "iso-week", "gregorian", "YYYY-Www-D"},
+		{"yesterday", "unix-day", "gregorian", "not an integer"},
+		{"-719163", "unix-day", "gregorian", "out of the supported range"},
+		{"0000-06-01", "gregorian", "julian", "out of the supported range"},
+		{"2024-06-10", "hebrew", "gregorian", "unknown calendar"},
+		{"2024-06-10", "gregorian", "mayan", "unknown calendar"},
+		{"2024-06-10", "gregorian", "", "missing"},
+	}
+	for _, tt := range tests {
+		if _, err := Convert(tt.date, tt.from, tt.to); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Convert(%q, %q, %q) error = %v, want %q", tt.date, tt.from, tt.to, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Validate the check digit of an identifier, or compute the check digit to append to it, for the EAN-13 barcode, ISBN-13 book number and Luhn schemes, the Luhn algorithm being the one of credit card and IMEI numbers. Spaces and hyphens in the number are ignored. Always use this function instead of computing check digits yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Scheme string `json:"scheme" jsonschema:"description=The check digit scheme,enum=ean-13,enum=isbn-13,enum=luhn"`
+	Number string `json:"number" jsonschema:"description=The number to validate with its check digit or to compute the check digit of without it,example=978-0-306-40615-7"`
+	Action string `json:"action,omitempty" jsonschema:"description=Whether to validate the number or compute its check digit. Defaults to validate,enum=validate,enum=compute"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "check-digit", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF8}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "scheme", msg.Scheme, "number", msg.Number, "action", msg.Action)
+
+	result, err := Run(msg.Scheme, msg.Number, msg.Action)
+	if err != nil {
+		slog.Warn("[sfn] Run error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not %s the check digit: %v", action(msg.Action), err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+func action(a string) string {
+	if strings.EqualFold(strings.TrimSpace(a), "compute") {
+		return "compute"
+	}
+	return "validate"
+}
+
+// Run validates number or computes its check digit, as the action says,
+// and describes the outcome.
+func Run(scheme, number, act string) (string, error) {
+	s, err := lookup(scheme)
+	if err != nil {
+		return "", err
+	}
+	digits, err := clean(number)
+	if err != nil {
+		return "", err
+	}
+
+	switch a := strings.ToLower(strings.TrimSpace(act)); a {
+	case "", "validate":
+		want, err := s.expected(digits)
+		if err != nil {
+			return "", err
+		}
+		if got := int(digits[len(digits)-1] - '0'); got != want {
+			return fmt.Sprintf("%s is not a valid %s number, its check digit should be %d instead of %d.", digits, s.name, want, got), nil
+		}
+		return fmt.Sprintf("%s is a valid %s number.", digits, s.name), nil
+	case "compute":
+		d, err := s.compute(digits)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("The %s check digit of %s is %d, the full number is %s%d.", s.name, digits, d, digits, d), nil
+	default:
+		return "", fmt.Errorf("unknown action %q, use validate or compute", act)
+	}
+}
+
+// Scheme is a check digit scheme.
+type Scheme struct {
+	name string
+	// length is the number of digits without the check digit, 0 for any.
+	length int
+	// prefixes are the allowed starts of a number, none for any.
+	prefixes []string
+	digit    func(payload string) int
+}
+
+var schemes = map[string]Scheme{
+	"ean-13":  {name: "EAN-13", length: 12, digit: ean},
+	"isbn-13": {name: "ISBN-13", length: 12, prefixes: []string{"978", "979"}, digit: ean},
+	"luhn":    {name: "Luhn", digit: luhn},
+}
+
+func lookup(scheme string) (Scheme, error) {
+	key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(scheme)), "_", "-")
+	switch key {
+	case "ean13", "ean":
+		key = "ean-13"
+	case "isbn13", "isbn":
+		key = "isbn-13"
+	}
+	s, ok := schemes[key]
+	if !ok {
+		return Scheme{}, fmt.Errorf("unknown scheme %q, use ean-13, isbn-13 or luhn", scheme)
+	}
+	return s, nil
+}
+
+// ErrNotDigits is returned for a number with other characters than digits,
+// spaces and hyphens.
+var ErrNotDigits = errors.New("the number must only have digits")
+
+// clean drops the spaces and hyphens of number.
+func clean(number string) (string, error) {
+	var b strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-':
+		default:
+			return "", fmt.Errorf("%w, found %q in %q", ErrNotDigits, r, number)
+		}
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("%w, %q has none", ErrNotDigits, number)
+	}
+	return b.String(), nil
+}
+
+// compute returns the check digit of payload, the number without it.
+func (s Scheme) compute(payload string) (int, error) {
+	if s.length > 0 && len(payload) != s.length {
+		return 0, fmt.Errorf("an %s number has %d digits before its check digit, %s has %d", s.name, s.length, payload, len(payload))
+	}
+	if err := s.checkPrefix(payload); err != nil {
+		return 0, err
+	}
+	return s.digit(payload), nil
+}
+
+// expected returns the check digit number should end with.
+func (s Scheme) expected(number string) (int, error) {
+	if s.length > 0 && len(number) != s.length+1 {
+		return 0, fmt.Errorf("an %s number has %d digits with its check digit, %s has %d", s.name, s.length+1, number, len(number))
+	}
+	if len(number) < 2 {
+		return 0, fmt.Errorf("%s has no digits before its check digit", number)
+	}
+	if err := s.checkPrefix(number); err != nil {
+		return 0, err
+	}
+	return s.digit(number[:len(number)-1]), nil
+}
+
+func (s Scheme) checkPrefix(number string) error {
+	if len(s.prefixes) == 0 {
+		return nil
+	}
+	for _, p := range s.prefixes {
+		if strings.HasPrefix(number, p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("an %s number starts with %s, %s does not", s.name, strings.Join(s.prefixes, " or "), number)
+}
+
+// ean is the check digit of the GS1 barcodes: the digits are weighted 1
+// and 3 alternately from the left, and the check digit completes their sum
+// to a multiple of 10. The 12 digits of EAN-13 and ISBN-13 start with
+// weight 1.
+func ean(payload string) int {
+	sum := 0
+	for i, c := range payload {
+		d := int(c - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// luhn is the check digit of the Luhn algorithm: from the right of the
+// payload every other digit is doubled, starting with the last one, the
+// digits of the products are added up with the others, and the check digit
+// completes the sum to a multiple of 10.
+func luhn(payload string) int {
+	sum := 0
+	for i := 0; i < len(payload); i++ {
+		d := int(payload[len(payload)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
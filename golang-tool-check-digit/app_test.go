@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		scheme, payload string
+		want            int
+	}{
+		{"ean-13", "400638133393", 1},
+		{"ean-13", "590123412345", 7},
+		{"ean-13", "000000000000", 0},
+		{"isbn-13", "978030640615", 7},
+		{"isbn-13", "979109000000", 1},
+		{"luhn", "7992739871", 3},
+		{"luhn", "453914880343646", 7},
+		{"luhn", "0", 0},
+		{"luhn", "49015420323751", 8},
+	}
+	for _, tt := range tests {
+		s, err := lookup(tt.scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.compute(tt.payload)
+		if err != nil {
+			t.Errorf("compute(%s, %s) error: %v", tt.scheme, tt.payload, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("compute(%s, %s) = %d, want %d", tt.scheme, tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		scheme, number, action string
+		want                   string
+	}{
+		{"ean-13", "4006381333931", "", "4006381333931 is a valid EAN-13 number."},
+		{"ean-13", "4006381333932", "validate", "4006381333932 is not a valid EAN-13 number, its check digit should be 1 instead of 2."},
+		{"EAN13", "400638133393", "compute", "The EAN-13 check digit of 400638133393 is 1, the full number is 4006381333931."},
+		{"isbn-13", "978-0-306-40615-7", "", "9780306406157 is a valid ISBN-13 number."},
+		{"isbn", "978-0-306-40615-4", "", "9780306406154 is not a valid ISBN-13 number, its check digit should be 7 instead of 4."},
+		{"isbn-13", "978-0-306-40615", "Compute", "The ISBN-13 check digit of 978030640615 is 7, the full number is 9780306406157."},
+		{"luhn", "4539 1488 0343 6467", "", "4539148803436467 is a valid Luhn number."},
+		{"luhn", "79927398710", "", "79927398710 is not a valid Luhn number, its check digit should be 3 instead of 0."},
+		{"luhn", "7992739871", "compute", "The Luhn check digit of 7992739871 is 3, the full number is 79927398713."},
+	}
+	for _, tt := range tests {
+		got, err := Run(tt.scheme, tt.number, tt.action)
+		if err != nil {
+			t.Errorf("Run(%s, %s, %s) error: %v", tt.scheme, tt.number, tt.action, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Run(%s, %s, %s) = %q, want %q", tt.scheme, tt.number, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		scheme, number, action string
+		want                   string
+	}{
+		{"upc", "036000291452", "", `unknown scheme "upc"`},
+		{"ean-13", "400638133393", "", "an EAN-13 number has 13 digits with its check digit, 400638133393 has 12"},
+		{"ean-13", "4006381333931", "compute", "an EAN-13 number has 12 digits before its check digit, 4006381333931 has 13"},
+		{"isbn-13", "9770306406157", "", "an ISBN-13 number starts with 978 or 979, 9770306406157 does not"},
+		{"luhn", "7", "", "7 has no digits before its check digit"},
+		{"luhn", "4539-1488-0343-646X", "", "the number must only have digits"},
+		{"luhn", " - ", "", "the number must only have digits"},
+		{"luhn", "7992739871", "fix", `unknown action "fix"`},
+	}
+	for _, tt := range tests {
+		_, err := Run(tt.scheme, tt.number, tt.action)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Run(%s, %s, %s) error = %v, want %q", tt.scheme, tt.number, tt.action, err, tt.want)
+		}
+	}
+
+	if _, err := Run("luhn", "12a4", ""); !errors.Is(err, ErrNotDigits) {
+		t.Errorf("Run() error = %v, want ErrNotDigits", err)
+	}
+}
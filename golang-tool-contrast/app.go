@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/color"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the WCAG contrast ratio between a text color and a background color, e.g. "is grey text on white readable?" or "does #777 on #fff pass WCAG AA?". Always use this function instead of computing the ratio yourself. The function returns the ratio and whether it passes the AA and AAA levels for normal and large text.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Foreground string `json:"foreground" jsonschema:"description=The text color as a hex code or rgb() or a basic color name,example=#777777"`
+	Background string `json:"background" jsonschema:"description=The background color as a hex code or rgb() or a basic color name,example=#ffffff"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "contrast", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xEE}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "foreground", msg.Foreground, "background", msg.Background)
+
+	fg, err := color.Parse(msg.Foreground)
+	if err != nil {
+		slog.Warn("[sfn] Parse error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the contrast: %v", err))
+		return
+	}
+	bg, err := color.Parse(msg.Background)
+	if err != nil {
+		slog.Warn("[sfn] Parse error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the contrast: %v", err))
+		return
+	}
+
+	result := Check(fg, bg).String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// The minimum contrast ratios of WCAG 2 success criteria 1.4.3 (AA) and
+// 1.4.6 (AAA). Large text is at least 18pt, or 14pt bold.
+const (
+	minAA       = 4.5
+	minAALarge  = 3
+	minAAA      = 7
+	minAAALarge = 4.5
+)
+
+// Ratio returns the WCAG contrast ratio of two colors, from 1 for the same
+// luminance to 21 for black and white. The order of the colors does not
+// matter.
+func Ratio(a, b color.RGB) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// Result is the contrast of a text color on a background color.
+type Result struct {
+	Foreground, Background color.RGB
+	Ratio                  float64
+}
+
+// Check computes the contrast of fg on bg.
+func Check(fg, bg color.RGB) Result {
+	return Result{Foreground: fg, Background: bg, Ratio: Ratio(fg, bg)}
+}
+
+// AA, AALarge, AAA and AAALarge tell whether the ratio passes the level for
+// normal and large text. WCAG compares the exact ratio, 4.49 fails 4.5.
+func (r Result) AA() bool       { return r.Ratio >= minAA }
+func (r Result) AALarge() bool  { return r.Ratio >= minAALarge }
+func (r Result) AAA() bool      { return r.Ratio >= minAAA }
+func (r Result) AAALarge() bool { return r.Ratio >= minAAALarge }
+
+// String describes the result, e.g. "#777777 on #ffffff has a contrast
+// ratio of 4.47:1: AA normal text fail, AA large text pass, AAA normal text
+// fail, AAA large text fail."
+func (r Result) String() string {
+	// truncate, so that a ratio just below a threshold is not shown as
+	// reaching it
+	ratio := math.Floor(r.Ratio*100) / 100
+	return fmt.Sprintf("%s on %s has a contrast ratio of %.2f:1: AA normal text %s, AA large text %s, AAA normal text %s, AAA large text %s.",
+		r.Foreground.Hex(), r.Background.Hex(), ratio, verdict(r.AA()), verdict(r.AALarge()), verdict(r.AAA()), verdict(r.AAALarge()))
+}
+
+func verdict(pass bool) string {
+	if pass {
+		return "pass"
+	}
+	return "fail"
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/color"
+)
+
+func mustParse(t *testing.T, s string) color.RGB {
+	t.Helper()
+	c, err := color.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRatio(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"#000000", "#ffffff", 21},
+		{"#ffffff", "#000000", 21},
+		{"#ffffff", "#ffffff", 1},
+		// the well known grays around the AA threshold on white
+		{"#777777", "#ffffff", 4.48},
+		{"#767676", "#ffffff", 4.54},
+		{"#ff0000", "#ffffff", 4.00},
+		{"#0000ff", "#ffffff", 8.59},
+	}
+	for _, tt := range tests {
+		got := Ratio(mustParse(t, tt.a), mustParse(t, tt.b))
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("Ratio(%s, %s) = %.3f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		fg, bg                     string
+		aa, aaLarge, aaa, aaaLarge bool
+	}{
+		{"black", "white", true, true, true, true},
+		{"#767676", "#fff", true, true, false, true},
+		{"#777", "#fff", false, true, false, false},
+		{"#959595", "#fff", false, false, false, false},
+		{"#595959", "#fff", true, true, true, true},
+	}
+	for _, tt := range tests {
+		r := Check(mustParse(t, tt.fg), mustParse(t, tt.bg))
+		got := [4]bool{r.AA(), r.AALarge(), r.AAA(), r.AAALarge()}
+		want := [4]bool{tt.aa, tt.aaLarge, tt.aaa, tt.aaaLarge}
+		if got != want {
+			t.Errorf("Check(%s, %s) ratio %.2f = AA %v, AA large %v, AAA %v, AAA large %v, want %v",
+				tt.fg, tt.bg, r.Ratio, got[0], got[1], got[2], got[3], want)
+		}
+	}
+}
+
+func TestResultString(t *testing.T) {
+	r := Check(mustParse(t, "#777"), mustParse(t, "white"))
+	want := "#777777 on #ffffff has a contrast ratio of 4.47:1: AA normal text fail, AA large text pass, AAA normal text fail, AAA large text fail."
+	if got := r.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
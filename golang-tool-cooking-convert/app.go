@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert a cooking measurement between cups, tablespoons, teaspoons, fluid ounces, milliliters, grams and ounces, e.g. "how many grams is 2 cups of flour?" or "how many teaspoons in a tablespoon?". Converting between a volume and a weight needs the ingredient, e.g. flour, sugar, butter or water. Cups are US cups and ounces are ounces of weight. The function returns the converted measurement.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value      float64 `json:"value" jsonschema:"description=The quantity to convert,minimum=0"`
+	From       string  `json:"from" jsonschema:"description=The unit of the quantity,enum=cups,enum=tablespoons,enum=teaspoons,enum=fluid ounces,enum=milliliters,enum=grams,enum=ounces"`
+	To         string  `json:"to" jsonschema:"description=The unit to convert the quantity to,enum=cups,enum=tablespoons,enum=teaspoons,enum=fluid ounces,enum=milliliters,enum=grams,enum=ounces"`
+	Ingredient string  `json:"ingredient,omitempty" jsonschema:"description=The ingredient measured. Required to convert between a volume and a weight,example=flour"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "cooking-convert", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF1}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	converted, err := Convert(msg.Value, msg.From, msg.To, msg.Ingredient)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g %s to %s: %v", msg.Value, msg.From, msg.To, err))
+		return
+	}
+
+	from, _ := lookupUnit(msg.From)
+	to, _ := lookupUnit(msg.To)
+	result := from.format(msg.Value)
+	if ingredient, ok := lookupIngredient(msg.Ingredient); ok {
+		result += " of " + ingredient
+	}
+	result += " is " + to.format(converted)
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+type unit struct {
+	// size is the unit in milliliters for a volume, in grams for a weight.
+	size   float64
+	volume bool
+	name   string
+}
+
+// format writes the quantity to the precision of a kitchen, 3 significant
+// digits and whole numbers from 100, e.g. "1.5 cups" or "250 grams".
+func (u unit) format(v float64) string {
+	if math.Abs(v) < 100 {
+		v = roundSignificant(v, 3)
+	} else {
+		v = math.Round(v)
+	}
+	name := u.name
+	if v == 1 {
+		name = strings.TrimSuffix(name, "s")
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + name
+}
+
+func roundSignificant(v float64, digits int) float64 {
+	if v == 0 {
+		return 0
+	}
+	scale := math.Pow(10, float64(digits)-math.Ceil(math.Log10(math.Abs(v))))
+	return math.Round(v*scale) / scale
+}
+
+// The US customary kitchen units, a cup is 8 fluid ounces or 16 tablespoons,
+// a tablespoon 3 teaspoons.
+const (
+	mlPerFluidOunce = 29.5735295625
+	mlPerCup        = 8 * mlPerFluidOunce
+	mlPerTablespoon = mlPerCup / 16
+	mlPerTeaspoon   = mlPerTablespoon / 3
+	gramsPerOunce   = 28.349523125
+)
+
+var units = map[string]unit{
+	"cups":         {size: mlPerCup, volume: true, name: "cups"},
+	"tablespoons":  {size: mlPerTablespoon, volume: true, name: "tablespoons"},
+	"teaspoons":    {size: mlPerTeaspoon, volume: true, name: "teaspoons"},
+	"fluid ounces": {size: mlPerFluidOunce, volume: true, name: "fluid ounces"},
+	"milliliters":  {size: 1, volume: true, name: "milliliters"},
+	"grams":        {size: 1, name: "grams"},
+	"ounces":       {size: gramsPerOunce, name: "ounces"},
+}
+
+// unitAliases maps other common spellings to the keys of units.
+var unitAliases = map[string]string{
+	"cup":         "cups",
+	"c":           "cups",
+	"tablespoon":  "tablespoons",
+	"tbsp":        "tablespoons",
+	"tbs":         "tablespoons",
+	"teaspoon":    "teaspoons",
+	"tsp":         "teaspoons",
+	"fluid ounce": "fluid ounces",
+	"fl oz":       "fluid ounces",
+	"floz":        "fluid ounces",
+	"milliliter":  "milliliters",
+	"millilitre":  "milliliters",
+	"millilitres": "milliliters",
+	"ml":          "milliliters",
+	"gram":        "grams",
+	"g":           "grams",
+	"ounce":       "ounces",
+	"oz":          "ounces",
+}
+
+func lookupUnit(name string) (unit, bool) {
+	key := normalize(name)
+	if alias, ok := unitAliases[key]; ok {
+		key = alias
+	}
+	u, ok := units[key]
+	return u, ok
+}
+
+// gramsPerCup is the weight of a US cup of the ingredients, as measured in
+// a kitchen: flour and powdered sugar spooned and leveled, brown sugar
+// packed. It is the density of the ingredient in the unit recipes use.
+var gramsPerCup = map[string]float64{
+	"water":          236.6,
+	"milk":           244,
+	"flour":          125,
+	"bread flour":    130,
+	"sugar":          200,
+	"brown sugar":    220,
+	"powdered sugar": 120,
+	"butter":         227,
+	"oil":            218,
+	"honey":          340,
+	"salt":           288,
+	"rice":           185,
+	"rolled oats":    90,
+	"cocoa powder":   85,
+}
+
+// ingredientAliases maps other common names to the keys of gramsPerCup.
+var ingredientAliases = map[string]string{
+	"all-purpose flour":    "flour",
+	"all purpose flour":    "flour",
+	"plain flour":          "flour",
+	"wheat flour":          "flour",
+	"granulated sugar":     "sugar",
+	"white sugar":          "sugar",
+	"caster sugar":         "sugar",
+	"icing sugar":          "powdered sugar",
+	"confectioners sugar":  "powdered sugar",
+	"confectioners' sugar": "powdered sugar",
+	"vegetable oil":        "oil",
+	"olive oil":            "oil",
+	"table salt":           "salt",
+	"oats":                 "rolled oats",
+	"cocoa":                "cocoa powder",
+	"white rice":           "rice",
+}
+
+func lookupIngredient(name string) (string, bool) {
+	key := normalize(name)
+	if alias, ok := ingredientAliases[key]; ok {
+		key = alias
+	}
+	_, ok := gramsPerCup[key]
+	return key, ok
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// ErrNeedsIngredient is returned by Convert for a conversion between a
+// volume and a weight without a known ingredient, since a cup of flour and
+// a cup of sugar do not weigh the same.
+var ErrNeedsIngredient = errors.New("converting between a volume and a weight depends on the ingredient")
+
+// Convert converts a quantity between two units. A conversion between a
+// volume and a weight goes through the density of the ingredient, the
+// others ignore it.
+func Convert(value float64, from, to, ingredient string) (float64, error) {
+	f, ok := lookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q, use %s", from, unitNames())
+	}
+	t, ok := lookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q, use %s", to, unitNames())
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return 0, fmt.Errorf("%v is not a quantity", value)
+	}
+
+	base := value * f.size
+	if f.volume != t.volume {
+		key, ok := lookupIngredient(ingredient)
+		switch {
+		case strings.TrimSpace(ingredient) == "":
+			return 0, fmt.Errorf("%w, give one of %s", ErrNeedsIngredient, ingredientNames())
+		case !ok:
+			return 0, fmt.Errorf("%w and the density of %q is not known, use one of %s", ErrNeedsIngredient, ingredient, ingredientNames())
+		}
+		density := gramsPerCup[key] / mlPerCup
+		if f.volume {
+			base *= density
+		} else {
+			base /= density
+		}
+	}
+	return base / t.size, nil
+}
+
+func unitNames() string {
+	return "cups, tablespoons, teaspoons, fluid ounces, milliliters, grams or ounces"
+}
+
+func ingredientNames() string {
+	names := make([]string, 0, len(gramsPerCup))
+	for name := range gramsPerCup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestConvertVolume(t *testing.T) {
+	// the same volume in every unit, each pairing is checked
+	points := []map[string]float64{
+		{"cups": 1, "tablespoons": 16, "teaspoons": 48, "fluid ounces": 8, "milliliters": 236.5882365},
+		{"cups": 0.25, "tablespoons": 4, "teaspoons": 12, "fluid ounces": 2, "milliliters": 59.147059125},
+		{"cups": 1.0 / 48, "tablespoons": 1.0 / 3, "teaspoons": 1, "fluid ounces": 1.0 / 6, "milliliters": 4.92892159375},
+	}
+	for _, point := range points {
+		for from, value := range point {
+			for to, want := range point {
+				// the ingredient does not matter between volumes
+				got, err := Convert(value, from, to, "flour")
+				if err != nil {
+					t.Errorf("Convert(%v, %s, %s) error = %v", value, from, to, err)
+					continue
+				}
+				if math.Abs(got-want) > 1e-9*math.Max(1, want) {
+					t.Errorf("Convert(%v, %s, %s) = %v, want %v", value, from, to, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestConvertWeight(t *testing.T) {
+	got, err := Convert(16, "oz", "g", "")
+	if err != nil || math.Abs(got-453.59237) > 1e-9 {
+		t.Errorf("Convert(16 oz to g) = %v, %v, want 453.59237", got, err)
+	}
+}
+
+func TestConvertDensity(t *testing.T) {
+	tests := []struct {
+		value      float64
+		from, to   string
+		ingredient string
+		want       float64
+	}{
+		{1, "cups", "grams", "flour", 125},
+		{2, "cups", "grams", "all-purpose flour", 250},
+		{1, "cups", "grams", "sugar", 200},
+		{1, "cups", "grams", "Granulated  Sugar", 200},
+		{100, "grams", "cups", "flour", 0.8},
+		{1, "tablespoons", "grams", "butter", 227.0 / 16},
+		{8, "ounces", "cups", "butter", 8 * gramsPerOunce / 227},
+		// a milliliter of water weighs a gram
+		{500, "milliliters", "grams", "water", 500},
+		{1, "teaspoons", "grams", "salt", 6},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to, tt.ingredient)
+		if err != nil {
+			t.Errorf("Convert(%v %s of %s to %s) error = %v", tt.value, tt.from, tt.ingredient, tt.to, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 0.01*tt.want {
+			t.Errorf("Convert(%v %s of %s to %s) = %v, want %v", tt.value, tt.from, tt.ingredient, tt.to, got, tt.want)
+		}
+	}
+
+	// flour is lighter than sugar, which is lighter than water
+	flour, _ := Convert(1, "cups", "grams", "flour")
+	sugar, _ := Convert(1, "cups", "grams", "sugar")
+	water, _ := Convert(1, "cups", "grams", "water")
+	if !(flour < sugar && sugar < water) {
+		t.Errorf("a cup of flour, sugar and water weighs %v, %v and %v", flour, sugar, water)
+	}
+}
+
+func TestConvertNeedsIngredient(t *testing.T) {
+	for _, ingredient := range []string{"", "  ", "unobtainium"} {
+		_, err := Convert(1, "cups", "grams", ingredient)
+		if !errors.Is(err, ErrNeedsIngredient) {
+			t.Errorf("Convert(cups to grams of %q) error = %v, want ErrNeedsIngredient", ingredient, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "flour") {
+			t.Errorf("Convert() error = %v, want the known ingredients listed", err)
+		}
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+	}{
+		{1, "pinch", "grams"},
+		{1, "cups", "liters"},
+		{-1, "cups", "milliliters"},
+		{math.NaN(), "cups", "milliliters"},
+	}
+	for _, tt := range tests {
+		if _, err := Convert(tt.value, tt.from, tt.to, "water"); err == nil {
+			t.Errorf("Convert(%v, %s, %s) should fail", tt.value, tt.from, tt.to)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		unit string
+		v    float64
+		want string
+	}{
+		{"cups", 1, "1 cup"},
+		{"cups", 1.5, "1.5 cups"},
+		{"grams", 236.5882365, "237 grams"},
+		{"teaspoons", 1.0 / 3, "0.333 teaspoons"},
+		{"fluid ounces", 1, "1 fluid ounce"},
+		{"milliliters", 0, "0 milliliters"},
+	}
+	for _, tt := range tests {
+		u, _ := lookupUnit(tt.unit)
+		if got := u.format(tt.v); got != tt.want {
+			t.Errorf("format(%v %s) = %s, want %s", tt.v, tt.unit, got, tt.want)
+		}
+	}
+}
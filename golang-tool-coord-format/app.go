@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `if user asks to convert a geo coordinate between decimal degrees and degrees/minutes/seconds (DMS), you should call this function. Pass the coordinate exactly as the user wrote it, for example "40°42'46"N 74°0'21"W" or "40.7128,-74.0060". The function detects the input format and returns the coordinate in the other format.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Coordinate string `json:"coordinate" jsonschema:"description=The latitude and longitude pair to convert in either decimal degrees or degrees/minutes/seconds format"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "coord-format", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xAE}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "coordinate", msg.Coordinate)
+
+	result, err := ConvertCoordinate(msg.Coordinate)
+	if err != nil {
+		slog.Warn("[sfn] ConvertCoordinate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert the coordinate %q: %v", msg.Coordinate, err))
+		return
+	}
+
+	sfn.WriteText(ctx, result)
+}
+
+// ConvertCoordinate detects the format of the given coordinate and converts
+// it to the other one: decimal degrees become DMS, and DMS becomes decimal
+// degrees.
+func ConvertCoordinate(input string) (string, error) {
+	if lat, lon, ok, err := parseDecimal(input); ok {
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s in degrees/minutes/seconds is %s", FormatDecimal(lat, lon), FormatDMS(lat, lon)), nil
+	}
+
+	lat, lon, err := parseDMS(input)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s in decimal degrees is %s", FormatDMS(lat, lon), FormatDecimal(lat, lon)), nil
+}
+
+// ParseCoordinate parses a latitude/longitude pair written either in decimal
+// degrees or in degrees/minutes/seconds.
+func ParseCoordinate(input string) (lat, lon float64, err error) {
+	if lat, lon, ok, err := parseDecimal(input); ok {
+		return lat, lon, err
+	}
+	return parseDMS(input)
+}
+
+var decimalPattern = regexp.MustCompile(`^\s*([+-]?\d+(?:\.\d+)?)\s*(?:,\s*|\s+)([+-]?\d+(?:\.\d+)?)\s*$`)
+
+// parseDecimal parses "lat,lon" in decimal degrees. ok reports whether the
+// input looks like a decimal pair at all, so callers can fall back to DMS.
+func parseDecimal(input string) (lat, lon float64, ok bool, err error) {
+	m := decimalPattern.FindStringSubmatch(input)
+	if m == nil {
+		return 0, 0, false, nil
+	}
+	lat, _ = strconv.ParseFloat(m[1], 64)
+	lon, _ = strconv.ParseFloat(m[2], 64)
+	return lat, lon, true, validate(lat, lon)
+}
+
+// dmsBody matches the numeric part of one DMS component such as `40°42'46.1"`.
+// The degree sign is mandatory, minutes and seconds are optional.
+const dmsBody = `(-)?(\d+(?:\.\d+)?)\s*[°º˚]\s*(?:(\d+(?:\.\d+)?)\s*['′’]\s*)?(?:(\d+(?:\.\d+)?)\s*(?:''|["″”])\s*)?`
+
+var (
+	// prefixPattern matches components written like `N 40°42'46"`.
+	prefixPattern = regexp.MustCompile(`([NSEW])\s*` + dmsBody)
+	// suffixPattern matches components written like `40°42'46"N`.
+	suffixPattern = regexp.MustCompile(dmsBody + `([NSEW])?`)
+
+	separatorPattern = regexp.MustCompile(`^[\s,;]*$`)
+)
+
+type dmsComponent struct {
+	value      float64
+	hemisphere string
+}
+
+func parseDMS(input string) (lat, lon float64, err error) {
+	s := strings.ToUpper(strings.TrimSpace(input))
+	if s == "" {
+		return 0, 0, errors.New("coordinate is empty")
+	}
+
+	// a leading hemisphere letter means every component is written that way,
+	// otherwise a trailing letter would swallow the next component's prefix
+	pattern, hemisphereGroup, firstGroup := suffixPattern, 5, 1
+	if strings.ContainsAny(s[:1], "NSEW") {
+		pattern, hemisphereGroup, firstGroup = prefixPattern, 1, 2
+	}
+
+	matches := pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) != 2 {
+		return 0, 0, errors.New(`expected a latitude and a longitude like 40°42'46"N 74°0'21"W or 40.7128,-74.0060`)
+	}
+
+	// everything outside the two components may only be separators
+	rest := s[:matches[0][0]] + s[matches[0][1]:matches[1][0]] + s[matches[1][1]:]
+	if !separatorPattern.MatchString(rest) {
+		return 0, 0, fmt.Errorf("unexpected characters %q in coordinate", strings.TrimSpace(rest))
+	}
+
+	var parts [2]dmsComponent
+	for i, idx := range matches {
+		group := func(n int) string {
+			if idx[2*n] < 0 {
+				return ""
+			}
+			return s[idx[2*n]:idx[2*n+1]]
+		}
+		parts[i], err = parseDMSComponent(group(firstGroup), group(firstGroup+1), group(firstGroup+2), group(firstGroup+3), group(hemisphereGroup))
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// hemisphere letters decide which part is which, otherwise assume the
+	// conventional latitude-first order
+	latPart, lonPart := parts[0], parts[1]
+	if isLongitude(latPart.hemisphere) || isLatitude(lonPart.hemisphere) {
+		latPart, lonPart = lonPart, latPart
+	}
+	if isLongitude(latPart.hemisphere) || isLatitude(lonPart.hemisphere) {
+		return 0, 0, errors.New("both components refer to the same axis")
+	}
+
+	lat, lon = latPart.value, lonPart.value
+	return lat, lon, validate(lat, lon)
+}
+
+func parseDMSComponent(sign, degrees, minutes, seconds, hemisphere string) (dmsComponent, error) {
+	deg, _ := strconv.ParseFloat(degrees, 64)
+	var min, sec float64
+	if minutes != "" {
+		min, _ = strconv.ParseFloat(minutes, 64)
+		if min >= 60 {
+			return dmsComponent{}, fmt.Errorf("minutes must be less than 60, got %s", minutes)
+		}
+	}
+	if seconds != "" {
+		sec, _ = strconv.ParseFloat(seconds, 64)
+		if sec >= 60 {
+			return dmsComponent{}, fmt.Errorf("seconds must be less than 60, got %s", seconds)
+		}
+	}
+
+	value := deg + min/60 + sec/3600
+	if sign == "-" && hemisphere != "" {
+		return dmsComponent{}, errors.New("use either a minus sign or a hemisphere letter, not both")
+	}
+	if sign == "-" || hemisphere == "S" || hemisphere == "W" {
+		value = -value
+	}
+	return dmsComponent{value: value, hemisphere: hemisphere}, nil
+}
+
+func isLatitude(h string) bool  { return h == "N" || h == "S" }
+func isLongitude(h string) bool { return h == "E" || h == "W" }
+
+func validate(lat, lon float64) error {
+	if lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %g is out of range [-90, 90]", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude %g is out of range [-180, 180]", lon)
+	}
+	return nil
+}
+
+// FormatDecimal formats the coordinate in decimal degrees with 6 decimals,
+// which is roughly 0.1 meter precision.
+func FormatDecimal(lat, lon float64) string {
+	return fmt.Sprintf("%.6f,%.6f", lat, lon)
+}
+
+// FormatDMS formats the coordinate as degrees/minutes/seconds with
+// hemisphere letters, seconds rounded to one decimal.
+func FormatDMS(lat, lon float64) string {
+	return formatDMSComponent(lat, "N", "S") + " " + formatDMSComponent(lon, "E", "W")
+}
+
+func formatDMSComponent(v float64, pos, neg string) string {
+	hemisphere := pos
+	if v < 0 {
+		hemisphere = neg
+		v = -v
+	}
+
+	// work in tenths of a second so rounding carries into minutes and degrees
+	tenths := int64(math.Round(v * 36000))
+	deg := tenths / 36000
+	min := (tenths % 36000) / 600
+	sec := float64(tenths%600) / 10
+
+	return fmt.Sprintf(`%d°%d'%.1f"%s`, deg, min, sec, hemisphere)
+}
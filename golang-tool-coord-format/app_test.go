@@ -0,0 +1,176 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantLat float64
+		wantLon float64
+		wantErr bool
+	}{
+		{
+			name:    "decimal with comma",
+			input:   "40.7128,-74.0060",
+			wantLat: 40.7128,
+			wantLon: -74.0060,
+		},
+		{
+			name:    "decimal with space",
+			input:   " -33.8688 151.2093 ",
+			wantLat: -33.8688,
+			wantLon: 151.2093,
+		},
+		{
+			name:    "dms with hemisphere suffix",
+			input:   `40°42'46"N 74°0'21"W`,
+			wantLat: 40 + 42.0/60 + 46.0/3600,
+			wantLon: -(74 + 21.0/3600),
+		},
+		{
+			name:    "dms with hemisphere prefix and comma",
+			input:   `S 33°52'7.7", E 151°12'33.5"`,
+			wantLat: -(33 + 52.0/60 + 7.7/3600),
+			wantLon: 151 + 12.0/60 + 33.5/3600,
+		},
+		{
+			name:    "dms longitude first",
+			input:   `2°21'E 48°51'N`,
+			wantLat: 48 + 51.0/60,
+			wantLon: 2 + 21.0/60,
+		},
+		{
+			name:    "dms with typographic marks and minus sign",
+			input:   `-22° 54′ 30″ -43° 11′ 47″`,
+			wantLat: -(22 + 54.0/60 + 30.0/3600),
+			wantLon: -(43 + 11.0/60 + 47.0/3600),
+		},
+		{
+			name:    "latitude out of range",
+			input:   "91.5,10",
+			wantErr: true,
+		},
+		{
+			name:    "longitude out of range",
+			input:   `10°N 181°E`,
+			wantErr: true,
+		},
+		{
+			name:    "minutes out of range",
+			input:   `40°75'N 74°0'W`,
+			wantErr: true,
+		},
+		{
+			name:    "same axis twice",
+			input:   `40°N 41°S`,
+			wantErr: true,
+		},
+		{
+			name:    "only one component",
+			input:   `40°42'46"N`,
+			wantErr: true,
+		},
+		{
+			name:    "garbage between components",
+			input:   `40°42'46"N and 74°0'21"W`,
+			wantErr: true,
+		},
+		{
+			name:    "sign and hemisphere together",
+			input:   `-40°N 74°W`,
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			input:   "  ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, lon, err := ParseCoordinate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCoordinate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if math.Abs(lat-tt.wantLat) > 1e-9 || math.Abs(lon-tt.wantLon) > 1e-9 {
+				t.Errorf("ParseCoordinate(%q) = (%v, %v), want (%v, %v)", tt.input, lat, lon, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
+
+func TestFormatDMS(t *testing.T) {
+	tests := []struct {
+		lat, lon float64
+		want     string
+	}{
+		{40.7128, -74.0060, `40°42'46.1"N 74°0'21.6"W`},
+		{-33.8688, 151.2093, `33°52'7.7"S 151°12'33.5"E`},
+		{0, 0, `0°0'0.0"N 0°0'0.0"E`},
+		// 59.99999 seconds rounds up and carries into the next degree
+		{10.999999, -20.999999, `11°0'0.0"N 21°0'0.0"W`},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDMS(tt.lat, tt.lon); got != tt.want {
+			t.Errorf("FormatDMS(%v, %v) = %s, want %s", tt.lat, tt.lon, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	coords := [][2]float64{
+		{40.7128, -74.0060},
+		{-33.8688, 151.2093},
+		{51.5074, -0.1278},
+		{-89.9999, 179.9999},
+	}
+
+	for _, c := range coords {
+		// decimal -> dms -> decimal keeps the tenth-of-a-second precision
+		lat, lon, err := ParseCoordinate(FormatDMS(c[0], c[1]))
+		if err != nil {
+			t.Fatalf("ParseCoordinate(FormatDMS(%v)) error = %v", c, err)
+		}
+		if math.Abs(lat-c[0]) > 0.05/3600 || math.Abs(lon-c[1]) > 0.05/3600 {
+			t.Errorf("round trip of %v got (%v, %v)", c, lat, lon)
+		}
+
+		// and the decimal representation parses back unchanged
+		lat, lon, err = ParseCoordinate(FormatDecimal(c[0], c[1]))
+		if err != nil {
+			t.Fatalf("ParseCoordinate(FormatDecimal(%v)) error = %v", c, err)
+		}
+		if math.Abs(lat-c[0]) > 1e-6 || math.Abs(lon-c[1]) > 1e-6 {
+			t.Errorf("round trip of %v got (%v, %v)", c, lat, lon)
+		}
+	}
+}
+
+func TestConvertCoordinate(t *testing.T) {
+	got, err := ConvertCoordinate("40.7128,-74.0060")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `40.712800,-74.006000 in degrees/minutes/seconds is 40°42'46.1"N 74°0'21.6"W`
+	if got != want {
+		t.Errorf("ConvertCoordinate() = %s, want %s", got, want)
+	}
+
+	got, err = ConvertCoordinate(`40°42'46.1"N 74°0'21.6"W`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `40°42'46.1"N 74°0'21.6"W in decimal degrees is 40.712806,-74.006000`
+	if got != want {
+		t.Errorf("ConvertCoordinate() = %s, want %s", got, want)
+	}
+}
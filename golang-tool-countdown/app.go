@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/datetime"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute how long it is from now until a date and time, e.g. "3 days, 4 hours from now", with the exact duration, or how long ago a date that has already passed was. "date_time" is a date like "2026-12-31 23:59:59" or an RFC 3339 time, "tz" is the IANA Time Zone Database identifier of a date without offset, UTC by default. Always use this function instead of computing the time left yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	DateTime string `json:"date_time" jsonschema:"description=The date and time of the event,example=2026-12-31 23:59:59"`
+	TZ       string `json:"tz,omitempty" jsonschema:"description=The IANA Time Zone Database identifier of the date,example=Asia/Tokyo"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "countdown", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x106}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "date_time", msg.DateTime, "tz", msg.TZ)
+
+	result, err := Countdown(msg.DateTime, msg.TZ, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] Countdown error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the countdown: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+const dateFormat = "2006-01-02 15:04:05 MST"
+
+// Countdown describes how long it is from now until the date value in tz.
+func Countdown(value, tz string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", errors.New("the date and time is empty")
+	}
+	loc, err := datetime.LoadLocation(tz)
+	if err != nil {
+		return "", err
+	}
+	target, err := datetime.Parse(value, loc)
+	if err != nil {
+		return "", err
+	}
+
+	d := target.Sub(now).Round(time.Second)
+	date := target.Format(dateFormat)
+	switch {
+	case d == 0:
+		return fmt.Sprintf("%s is now", date), nil
+	case d < 0:
+		return fmt.Sprintf("%s has already passed, %s ago (exactly %s, %d seconds)", date, Phrase(-d), -d, int64(-d/time.Second)), nil
+	}
+	return fmt.Sprintf("%s is %s from now (exactly %s, %d seconds)", date, Phrase(d), d, int64(d/time.Second)), nil
+}
+
+// units are the units of Phrase, the largest first.
+var units = []struct {
+	name string
+	size time.Duration
+}{
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// Phrase writes a positive duration in its two largest units, e.g. "3 days,
+// 4 hours", truncating the rest. The second unit is left out when it is 0,
+// e.g. "2 hours" for 2h0m30s.
+func Phrase(d time.Duration) string {
+	for i, u := range units {
+		if d < u.size && i < len(units)-1 {
+			continue
+		}
+		parts := []string{plural(int64(d/u.size), u.name)}
+		if i+1 < len(units) {
+			next := units[i+1]
+			if n := int64(d % u.size / next.size); n > 0 {
+				parts = append(parts, plural(n, next.name))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// plural writes n of the unit, e.g. "1 day" or "3 days".
+func plural(n int64, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
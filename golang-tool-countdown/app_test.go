@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPhrase(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{time.Second, "1 second"},
+		{45 * time.Second, "45 seconds"},
+		{90 * time.Second, "1 minute, 30 seconds"},
+		{2*time.Hour + 30*time.Second, "2 hours"},
+		{3*24*time.Hour + 4*time.Hour + 59*time.Minute, "3 days, 4 hours"},
+		{24 * time.Hour, "1 day"},
+		{400 * 24 * time.Hour, "400 days"},
+	}
+	for _, tt := range tests {
+		if got := Phrase(tt.d); got != tt.want {
+			t.Errorf("Phrase(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	now := time.Date(2026, 12, 28, 19, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		tz    string
+		want  string
+	}{
+		{
+			name:  "future",
+			value: "2026-12-31 23:59:59",
+			want:  "2026-12-31 23:59:59 UTC is 3 days, 4 hours from now (exactly 76h29m59s, 275399 seconds)",
+		},
+		{
+			name:  "future in a time zone",
+			value: "2027-01-01 00:00",
+			tz:    "Asia/Tokyo",
+			want:  "2027-01-01 00:00:00 JST is 2 days, 19 hours from now (exactly 67h30m0s, 243000 seconds)",
+		},
+		{
+			name:  "with offset",
+			value: "2026-12-28T21:00:00+01:00",
+			want:  "2026-12-28 20:00:00 UTC is 30 minutes from now (exactly 30m0s, 1800 seconds)",
+		},
+		{
+			name:  "past",
+			value: "2026-12-25",
+			want:  "2026-12-25 00:00:00 UTC has already passed, 3 days, 19 hours ago (exactly 91h30m0s, 329400 seconds)",
+		},
+		{
+			name:  "now",
+			value: "2026-12-28T19:30:00Z",
+			want:  "2026-12-28 19:30:00 UTC is now",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Countdown(tt.value, tt.tz, now)
+			if err != nil {
+				t.Fatalf("Countdown() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Countdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountdownErrors(t *testing.T) {
+	now := time.Date(2026, 12, 28, 19, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		tz    string
+		want  string
+	}{
+		{"empty", " ", "", "empty"},
+		{"unknown time zone", "2027-01-01", "Mars/Olympus", "unknown time zone"},
+		{"not a date", "next friday", "", "can not understand the date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Countdown(tt.value, tt.tz, now)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Countdown() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/borders"
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Tell which country a Latitude and Longitude geo coordinate is in, e.g. "which country is at 48.85, 2.35?". The function works offline from coarse country outlines, so a point within about 50 km of a border or a coast can be attributed to the neighbor or to the sea. It returns the country name and its ISO 3166-1 alpha-2 code, or that the point is over international waters.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the point in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the point in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "country-at", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE2}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	result, err := CountryAt(msg.Latitude, msg.Longitude)
+	if err != nil {
+		slog.Warn("[sfn] CountryAt error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not find the country: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// CountryAt validates the coordinate and describes the country it is in.
+func CountryAt(lat, lon float64) (string, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return "", err
+	}
+
+	country, ok := borders.Lookup(lat, lon)
+	if !ok {
+		return fmt.Sprintf("%v,%v is over international waters", lat, lon), nil
+	}
+	return fmt.Sprintf("%v,%v is in %s (%s)", lat, lon, country.Name, country.Code), nil
+}
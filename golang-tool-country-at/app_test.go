@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestCountryAt(t *testing.T) {
+	tests := []struct {
+		lat, lon float64
+		want     string
+	}{
+		{48.8566, 2.3522, "48.8566,2.3522 is in France (FR)"},
+		{-33.8688, 151.2093, "-33.8688,151.2093 is in Australia (AU)"},
+		{39.7392, -104.9903, "39.7392,-104.9903 is in United States (US)"},
+		{-1.2921, 36.8219, "-1.2921,36.8219 is in Kenya (KE)"},
+		{30, -40, "30,-40 is over international waters"},
+		{-20, 80, "-20,80 is over international waters"},
+	}
+	for _, tt := range tests {
+		got, err := CountryAt(tt.lat, tt.lon)
+		if err != nil {
+			t.Fatalf("CountryAt(%v, %v) error = %v", tt.lat, tt.lon, err)
+		}
+		if got != tt.want {
+			t.Errorf("CountryAt(%v, %v) = %s, want %s", tt.lat, tt.lon, got, tt.want)
+		}
+	}
+
+	for _, c := range [][2]float64{{91, 0}, {0, -181}} {
+		if _, err := CountryAt(c[0], c[1]); err == nil {
+			t.Errorf("CountryAt(%v, %v) should fail", c[0], c[1])
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"hash/adler32"
+	"hash/crc32"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the CRC-32, CRC-32C, CRC-16 or Adler-32 checksum of a text, encoded as UTF-8, and return it in hexadecimal. If the user gives an expected checksum, pass it to verify that the text matches. Never compute a checksum yourself, always call this function.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Text      string `json:"text" jsonschema:"description=The text to checksum"`
+	Algorithm string `json:"algorithm" jsonschema:"description=The checksum algorithm,enum=crc32,enum=crc32c,enum=crc16,enum=crc16-ccitt,enum=crc16-modbus,enum=adler32"`
+	Expected  string `json:"expected,omitempty" jsonschema:"description=The expected checksum in hexadecimal to verify"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "crc", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD4}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "algorithm", msg.Algorithm, "text_bytes", len(msg.Text))
+
+	sum, err := Checksum(msg.Algorithm, msg.Text)
+	if err != nil {
+		slog.Warn("[sfn] Checksum error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the checksum: %v", err))
+		return
+	}
+
+	result := fmt.Sprintf("The %s checksum of the text is %s", msg.Algorithm, sum)
+	if msg.Expected != "" {
+		if Matches(sum, msg.Expected) {
+			result += ", it matches the expected checksum"
+		} else {
+			result += fmt.Sprintf(", it does NOT match the expected checksum %s", msg.Expected)
+		}
+	}
+	sfn.WriteText(ctx, result)
+}
+
+// maxTextBytes bounds the text, longer data belongs in a file.
+const maxTextBytes = 1 << 20
+
+// algorithm computes a checksum of width bits.
+type algorithm struct {
+	width int
+	sum   func([]byte) uint32
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// algorithms are the supported checksums, the CRC-16 parameters follow the
+// catalogue of https://reveng.sourceforge.io/crc-catalogue/16.htm.
+var algorithms = map[string]algorithm{
+	"crc32":  {32, crc32.ChecksumIEEE},
+	"crc32c": {32, func(b []byte) uint32 { return crc32.Checksum(b, castagnoli) }},
+	// CRC-16/ARC, the most common meaning of CRC-16
+	"crc16": {16, func(b []byte) uint32 { return uint32(crc16Reflected(b, 0)) }},
+	// CRC-16/CCITT-FALSE
+	"crc16-ccitt":  {16, func(b []byte) uint32 { return uint32(crc16CCITT(b)) }},
+	"crc16-modbus": {16, func(b []byte) uint32 { return uint32(crc16Reflected(b, 0xFFFF)) }},
+	"adler32":      {32, adler32.Checksum},
+}
+
+// normalize accepts e.g. "CRC-32" for crc32.
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Replace(name, "crc-", "crc", 1)
+	return strings.Replace(name, "adler-", "adler", 1)
+}
+
+// Checksum returns the checksum of text with the named algorithm, as
+// zero-padded lower case hexadecimal.
+func Checksum(name, text string) (string, error) {
+	a, ok := algorithms[normalize(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown algorithm %q, use crc32, crc32c, crc16, crc16-ccitt, crc16-modbus or adler32", name)
+	}
+	if len(text) > maxTextBytes {
+		return "", fmt.Errorf("the text is longer than %d bytes", maxTextBytes)
+	}
+	return fmt.Sprintf("%0*x", a.width/4, a.sum([]byte(text))), nil
+}
+
+// Matches reports whether expected, e.g. "0xCBF43926", is the checksum sum.
+func Matches(sum, expected string) bool {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	expected = strings.TrimPrefix(expected, "0x")
+	if expected == "" {
+		return false
+	}
+	// a leading zero may have been dropped
+	return strings.TrimLeft(expected, "0") == strings.TrimLeft(sum, "0")
+}
+
+// crc16Reflected is the reflected CRC-16 of polynomial 0x8005, used by
+// CRC-16/ARC with init 0 and CRC-16/MODBUS with init 0xFFFF.
+func crc16Reflected(b []byte, init uint16) uint16 {
+	crc := init
+	for _, c := range b {
+		crc ^= uint16(c)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// crc16CCITT is CRC-16/CCITT-FALSE: polynomial 0x1021, init 0xFFFF, not
+// reflected.
+func crc16CCITT(b []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, c := range b {
+		crc ^= uint16(c) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
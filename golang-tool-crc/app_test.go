@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		text      string
+		want      string
+	}{
+		// the check values of the CRC catalogue are for "123456789"
+		{"crc32", "123456789", "cbf43926"},
+		{"crc32c", "123456789", "e3069283"},
+		{"crc16", "123456789", "bb3d"},
+		{"crc16-ccitt", "123456789", "29b1"},
+		{"crc16-modbus", "123456789", "4b37"},
+		{"adler32", "123456789", "091e01de"},
+		{"adler32", "Wikipedia", "11e60398"},
+		{"crc32", "The quick brown fox jumps over the lazy dog", "414fa339"},
+		{"crc32", "", "00000000"},
+		{"crc16-ccitt", "", "ffff"},
+		{"adler32", "", "00000001"},
+		{"CRC-32", "123456789", "cbf43926"},
+		{"Adler-32", "123456789", "091e01de"},
+		{"CRC-16-MODBUS", "123456789", "4b37"},
+	}
+	for _, tt := range tests {
+		got, err := Checksum(tt.algorithm, tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("Checksum(%s, %q) = %s, %v, want %s", tt.algorithm, tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestChecksumInvalid(t *testing.T) {
+	if _, err := Checksum("md5", "123456789"); err == nil {
+		t.Error("Checksum(md5) should fail")
+	}
+	if _, err := Checksum("crc32", strings.Repeat("a", maxTextBytes+1)); err == nil {
+		t.Error("Checksum() of a too long text should fail")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		sum, expected string
+		want          bool
+	}{
+		{"cbf43926", "CBF43926", true},
+		{"cbf43926", "0xcbf43926", true},
+		{"091e01de", "91E01DE", true},
+		{"cbf43926", "cbf43927", false},
+		{"00000000", "0", true},
+		{"00000000", "0x", false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.sum, tt.expected); got != tt.want {
+			t.Errorf("Matches(%s, %s) = %v, want %v", tt.sum, tt.expected, got, tt.want)
+		}
+	}
+}
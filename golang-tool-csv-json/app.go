@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert CSV to JSON or JSON to CSV. With mode "csv2json" the first row of the CSV input is the header and the function returns a JSON array with one object per row. With mode "json2csv" the input is a JSON array of objects and the function returns a CSV with a header row of all the keys. Pass the input text unchanged, including its quotes and line breaks.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Input string `json:"input" jsonschema:"description=The CSV or JSON text to convert"`
+	Mode  string `json:"mode" jsonschema:"description=The direction of the conversion,enum=csv2json,enum=json2csv"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "csv-json", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB7}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "mode", msg.Mode, "input_bytes", len(msg.Input))
+
+	var (
+		result string
+		err    error
+	)
+	switch strings.ToLower(msg.Mode) {
+	case "csv2json":
+		result, err = CSVToJSON(msg.Input)
+	case "json2csv":
+		result, err = JSONToCSV(msg.Input)
+	default:
+		err = fmt.Errorf("unknown mode %q, it must be csv2json or json2csv", msg.Mode)
+	}
+	if err != nil {
+		slog.Warn("[sfn] convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert the input: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, result)
+}
+
+// CSVToJSON converts a CSV with a header row to a JSON array of objects, the
+// keys of each object are in the order of the header. A row shorter than the
+// header has empty strings for the missing fields, a longer row is an error.
+func CSVToJSON(input string) (string, error) {
+	r := csv.NewReader(strings.NewReader(input))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return "", errors.New("the CSV is empty")
+	}
+	if err != nil {
+		return "", csvError(err)
+	}
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if name == "" {
+			return "", fmt.Errorf("column %d of the header is empty", i+1)
+		}
+		if seen[name] {
+			return "", fmt.Errorf("the header has the column %q twice", name)
+		}
+		seen[name] = true
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for n := 0; ; n++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", csvError(err)
+		}
+		if len(record) > len(header) {
+			line, _ := r.FieldPos(0)
+			return "", fmt.Errorf("the row on line %d has %d fields but the header has %d", line, len(record), len(header))
+		}
+
+		if n > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('{')
+		for i, name := range header {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			value := ""
+			if i < len(record) {
+				value = record[i]
+			}
+			writeJSONString(&b, name)
+			b.WriteByte(':')
+			writeJSONString(&b, value)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(']')
+	return b.String(), nil
+}
+
+func writeJSONString(b *bytes.Buffer, s string) {
+	buf, _ := json.Marshal(s)
+	b.Write(buf)
+}
+
+func csvError(err error) error {
+	var pe *csv.ParseError
+	if errors.As(err, &pe) {
+		return fmt.Errorf("invalid CSV on line %d column %d: %v", pe.Line, pe.Column, pe.Err)
+	}
+	return err
+}
+
+// field is a key and value of a JSON object.
+type field struct {
+	key   string
+	value json.RawMessage
+}
+
+// JSONToCSV converts a JSON array of objects to a CSV. The header has every
+// key in the order they first appear, a missing key or a null value is an
+// empty field and nested arrays or objects are written as compact JSON.
+func JSONToCSV(input string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return "", errors.New("the JSON must be an array of objects")
+	}
+
+	var (
+		rows    []map[string]string
+		header  []string
+		columns = make(map[string]bool)
+	)
+	for dec.More() {
+		fields, err := decodeObject(dec)
+		if err != nil {
+			return "", fmt.Errorf("invalid JSON in element %d: %w", len(rows)+1, err)
+		}
+		row := make(map[string]string, len(fields))
+		for _, f := range fields {
+			if !columns[f.key] {
+				columns[f.key] = true
+				header = append(header, f.key)
+			}
+			row[f.key], err = csvValue(f.value)
+			if err != nil {
+				return "", fmt.Errorf("invalid JSON in element %d: %w", len(rows)+1, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if _, err := dec.Token(); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+	if len(header) == 0 {
+		return "", errors.New("the JSON array has no keys to make columns of")
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write(header)
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for i, name := range header {
+			record[i] = row[name]
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return b.String(), w.Error()
+}
+
+// decodeObject decodes the next JSON object of dec keeping the order of its
+// keys.
+func decodeObject(dec *json.Decoder) ([]field, error) {
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected an object, got %v", tok)
+	}
+
+	var fields []field
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field{key: tok.(string), value: value})
+	}
+	_, err := dec.Token()
+	return fields, err
+}
+
+// csvValue returns the CSV field of a JSON value.
+func csvValue(raw json.RawMessage) (string, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	default:
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+}
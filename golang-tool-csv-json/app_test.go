@@ -0,0 +1,142 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSVToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "simple",
+			input: "name,age\nAda,36\nAlan,41\n",
+			want:  `[{"name":"Ada","age":"36"},{"name":"Alan","age":"41"}]`,
+		},
+		{
+			name:  "quoted fields",
+			input: "name,quote\r\n\"Lovelace, Ada\",\"She said \"\"hi\"\"\nand left\"\r\n",
+			want:  `[{"name":"Lovelace, Ada","quote":"She said \"hi\"\nand left"}]`,
+		},
+		{
+			name:  "short row",
+			input: "a,b,c\n1,2\n",
+			want:  `[{"a":"1","b":"2","c":""}]`,
+		},
+		{
+			name:  "header only",
+			input: "a,b\n",
+			want:  `[]`,
+		},
+		{
+			name:    "long row",
+			input:   "a,b\n1,2\n1,2,3\n",
+			wantErr: "the row on line 3 has 3 fields but the header has 2",
+		},
+		{
+			name:    "bare quote",
+			input:   "a,b\n1,\"2\n",
+			wantErr: "invalid CSV on line 2",
+		},
+		{
+			name:    "quote in unquoted field",
+			input:   "a,b\n1,2\"3\n",
+			wantErr: "invalid CSV on line 2 column 4",
+		},
+		{
+			name:    "duplicate column",
+			input:   "a,a\n1,2\n",
+			wantErr: `the header has the column "a" twice`,
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: "the CSV is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CSVToJSON(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CSVToJSON() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CSVToJSON() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CSVToJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONToCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "keys in order of appearance",
+			input: `[{"name":"Ada","age":36},{"age":41,"name":"Alan","admin":true}]`,
+			want:  "name,age,admin\nAda,36,\nAlan,41,true\n",
+		},
+		{
+			name:  "null and nested values",
+			input: `[{"id":1,"tags":["a", "b"],"meta":{"x": 1},"note":null}]`,
+			want:  "id,tags,meta,note\n1,\"[\"\"a\"\",\"\"b\"\"]\",\"{\"\"x\"\":1}\",\n",
+		},
+		{
+			name:  "large numbers keep their digits",
+			input: `[{"id":12345678901234567890}]`,
+			want:  "id\n12345678901234567890\n",
+		},
+		{name: "not an array", input: `{"name":"Ada"}`, wantErr: true},
+		{name: "array of strings", input: `["Ada"]`, wantErr: true},
+		{name: "truncated", input: `[{"name":"Ada"`, wantErr: true},
+		{name: "no keys", input: `[{}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONToCSV(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JSONToCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("JSONToCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	inputs := []string{
+		"name,age\nAda,36\nAlan,41\n",
+		"name,quote\n\"Lovelace, Ada\",\"She said \"\"hi\"\"\nand left\"\n",
+		"a,b\n,\n",
+	}
+
+	for _, input := range inputs {
+		js, err := CSVToJSON(input)
+		if err != nil {
+			t.Fatalf("CSVToJSON(%q) error = %v", input, err)
+		}
+		got, err := JSONToCSV(js)
+		if err != nil {
+			t.Fatalf("JSONToCSV(%s) error = %v", js, err)
+		}
+		if got != input {
+			t.Errorf("round trip of %q = %q", input, got)
+		}
+	}
+}
@@ -6,10 +6,14 @@ import (
 	"io"
 	"log"
 	"log/slog"
-	"net/http"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/yomorun/llm-function-calling-examples/internal/currency"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
 	"github.com/yomorun/yomo/serverless"
 )
 
@@ -37,6 +41,10 @@ func InputSchema() any {
 	return &Parameter{}
 }
 
+func init() {
+	registry.Register(registry.Tool{Name: "currency-converter", Description: Description(), InputSchema: InputSchema(), Env: []string{"API_KEY"}, Upstream: "https://openexchangerates.org/api"})
+}
+
 // Init is an optional function invoked during the initialization phase of the
 // sfn instance. It's designed for setup tasks like global variable
 // initialization, establishing database connections, or loading models into
@@ -57,22 +65,36 @@ func Init() error {
 }
 
 // Handler orchestrates the core processing logic of this function.
-// - ctx.ReadLLMArguments() parses LLM Function Calling Arguments.
-// - ctx.WriteLLMResult() sends the retrieval result back to LLM.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
 func Handler(ctx serverless.Context) {
 	// parse the input data generated by llm tools_call
 	var msg Parameter
-	ctx.ReadLLMArguments(&msg)
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
 
 	// debug info
 	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
 
+	source, err := currency.ParseCode(msg.SourceCurrency)
+	if err != nil {
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert the currency: %v", err))
+		return
+	}
+	target, err := currency.ParseCode(msg.TargetCurrency)
+	if err != nil {
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert the currency: %v", err))
+		return
+	}
+	msg.SourceCurrency, msg.TargetCurrency = source, target
+
 	// if the source currency is not USD, ignore calling tools.
 	// openexchangerates.org free tier only supports USD as the base currency.
 	rate, err := fetchRate(msg.SourceCurrency, msg.TargetCurrency, msg.Amount)
 	if err != nil {
 		slog.Error("[sfn] >> fetchRate error", "err", err)
-		ctx.WriteLLMResult("can not get the target currency right now, please try later")
+		sfn.WriteText(ctx, "can not get the target currency right now, please try later")
 		return
 	}
 
@@ -84,16 +106,18 @@ func Handler(ctx serverless.Context) {
 	}
 
 	// yomo will write tools_call result back to llm automatically
-	ctx.WriteLLMResult(result)
+	sfn.WriteText(ctx, result)
 }
 
 type Rates struct {
 	Rates map[string]float64 `json:"rates"`
 }
 
+var client = httpx.NewClient(10 * time.Second)
+
 // fetchRate fetches the exchange rate from openexchangerates.org
 func fetchRate(sourceCurrency string, targetCurrency string, _ float64) (float64, error) {
-	resp, err := http.Get(fmt.Sprintf("https://openexchangerates.org/api/latest.json?app_id=%s&base=%s&symbols=%s", os.Getenv("API_KEY"), sourceCurrency, targetCurrency))
+	resp, err := client.Get(fmt.Sprintf("https://openexchangerates.org/api/latest.json?app_id=%s&base=%s&symbols=%s", os.Getenv("API_KEY"), sourceCurrency, targetCurrency))
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/currency"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert an amount of money between two currencies at the exchange rate of a past date, e.g. "how much was 100 USD in EUR on 2020-03-15?". The date must be in the past and not before 1999-01-01, and is given in the format YYYY-MM-DD. For today's rate use the currency converter instead.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Amount float64 `json:"amount" jsonschema:"description=The amount of money to convert"`
+	From   string  `json:"from" jsonschema:"description=The currency of the amount in 3-letter ISO 4217 format"`
+	To     string  `json:"to" jsonschema:"description=The currency to convert the amount to in 3-letter ISO 4217 format"`
+	Date   string  `json:"date" jsonschema:"description=The date of the exchange rate in the format YYYY-MM-DD"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "currency-historical", Description: Description(), InputSchema: InputSchema(), Env: []string{"API_KEY"}, Upstream: provider.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC6}
+}
+
+// provider serves the historical rates, API_KEY is the same
+// openexchangerates.org app id as the currency converter uses.
+var provider = &OpenExchangeRates{
+	AppID:      os.Getenv("API_KEY"),
+	BaseURL:    "https://openexchangerates.org/api",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	result, err := Convert(provider, msg.Amount, msg.From, msg.To, msg.Date, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g %s to %s on %s: %v", msg.Amount, msg.From, msg.To, msg.Date, err))
+		return
+	}
+
+	sfn.WriteText(ctx, result)
+}
+
+const dateFormat = "2006-01-02"
+
+// firstDate is the earliest date openexchangerates.org has rates for.
+var firstDate = time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ParseDate parses a YYYY-MM-DD date and checks it is a past day for which
+// rates exist. The rates of a day are published once it is over in UTC, so
+// today is not a past day yet.
+func ParseDate(date string, now time.Time) (time.Time, error) {
+	d, err := time.Parse(dateFormat, strings.TrimSpace(date))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date in the format YYYY-MM-DD", date)
+	}
+	if d.Before(firstDate) {
+		return time.Time{}, fmt.Errorf("the rates start on %s", firstDate.Format(dateFormat))
+	}
+	if today := now.UTC().Truncate(24 * time.Hour); !d.Before(today) {
+		return time.Time{}, fmt.Errorf("%s is not in the past, use the currency converter for today's rate", d.Format(dateFormat))
+	}
+	return d, nil
+}
+
+// Convert converts amount from one currency to another at the rate of date.
+func Convert(p *OpenExchangeRates, amount float64, from, to, date string, now time.Time) (string, error) {
+	from, err := currency.ParseCode(from)
+	if err != nil {
+		return "", err
+	}
+	to, err = currency.ParseCode(to)
+	if err != nil {
+		return "", err
+	}
+	d, err := ParseDate(date, now)
+	if err != nil {
+		return "", err
+	}
+
+	rate, err := p.Rate(from, to, d)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("at the exchange rate of %s: 1 %s = %.6f %s, %.2f %s was equivalent to %.2f %s", d.Format(dateFormat), from, rate, to, amount, from, amount*rate, to), nil
+}
+
+// OpenExchangeRates is a client of the openexchangerates.org API.
+type OpenExchangeRates struct {
+	AppID      string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Rate returns the rate from one currency to another on date. The free plan
+// only serves USD based rates, so other pairs are crossed through USD.
+func (o *OpenExchangeRates) Rate(from, to string, date time.Time) (float64, error) {
+	if o.AppID == "" {
+		return 0, fmt.Errorf("API_KEY is not set")
+	}
+
+	q := url.Values{"app_id": {o.AppID}, "symbols": {from + "," + to}}
+	resp, err := o.HTTPClient.Get(o.BaseURL + "/historical/" + date.Format(dateFormat) + ".json?" + q.Encode())
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("openexchangerates.org responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var body struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, err
+	}
+	// the base currency is not listed in its own rates
+	if body.Rates == nil {
+		body.Rates = map[string]float64{}
+	}
+	body.Rates["USD"] = 1
+
+	for _, code := range []string{from, to} {
+		if r, ok := body.Rates[code]; !ok || r == 0 {
+			return 0, fmt.Errorf("there is no %s rate on %s", code, date.Format(dateFormat))
+		}
+	}
+	return body.Rates[to] / body.Rates[from], nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		date    string
+		wantErr string
+	}{
+		{date: "2020-03-15"},
+		{date: "1999-01-01"},
+		{date: "2024-05-31"},
+		{date: " 2024-05-31 "},
+		{date: "2024-06-01", wantErr: "not in the past"},
+		{date: "2030-01-01", wantErr: "not in the past"},
+		{date: "1998-12-31", wantErr: "the rates start on 1999-01-01"},
+		{date: "15/03/2020", wantErr: "not a date"},
+		{date: "2020-02-30", wantErr: "not a date"},
+		{date: "", wantErr: "not a date"},
+	}
+	for _, tt := range tests {
+		_, err := ParseDate(tt.date, now)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("ParseDate(%q) error = %v", tt.date, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("ParseDate(%q) error = %v, want %q", tt.date, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/historical/2020-03-15.json" || r.URL.Query().Get("app_id") != "test" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"base":"USD","rates":{"EUR":0.9,"GBP":0.8,"JPY":108}}`))
+	}))
+	defer srv.Close()
+	p := &OpenExchangeRates{AppID: "test", BaseURL: srv.URL, HTTPClient: srv.Client()}
+
+	tests := []struct {
+		from, to string
+		wantRate float64
+	}{
+		{"USD", "EUR", 0.9},
+		{"eur", "usd", 1 / 0.9},
+		{"GBP", "JPY", 108 / 0.8},
+	}
+	for _, tt := range tests {
+		rate, err := p.Rate(strings.ToUpper(tt.from), strings.ToUpper(tt.to), time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC))
+		if err != nil || math.Abs(rate-tt.wantRate) > 1e-9 {
+			t.Errorf("Rate(%s, %s) = %v, %v, want %v", tt.from, tt.to, rate, err, tt.wantRate)
+		}
+	}
+
+	got, err := Convert(p, 100, "usd", "eur", "2020-03-15", now)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if want := "at the exchange rate of 2020-03-15: 1 USD = 0.900000 EUR, 100.00 USD was equivalent to 90.00 EUR"; got != want {
+		t.Errorf("Convert() = %q, want %q", got, want)
+	}
+
+	for _, args := range [][3]string{
+		{"USD", "XXX", "2020-03-15"},
+		{"USD", "EURO", "2020-03-15"},
+		{"USD", "EUR", "2024-06-01"},
+		{"USD", "EUR", "2020-03-16"},
+	} {
+		if _, err := Convert(p, 1, args[0], args[1], args[2], now); err == nil {
+			t.Errorf("Convert(%v) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `List the currencies supported for exchange rates with their 3-letter ISO 4217 codes and names. Call it to find the code of a currency the user names, e.g. "the Swiss franc", or to check that a code is supported before converting an amount. Pass a filter to search by code or name.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Filter string `json:"filter,omitempty" jsonschema:"description=A part of the code or name to search for; e.g. franc or CH. Leave empty to list all currencies"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "currency-list", Description: Description(), InputSchema: InputSchema(), Env: []string{"API_KEY"}, Upstream: provider.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC5}
+}
+
+// provider serves the currencies, API_KEY is the same openexchangerates.org
+// app id as the currency converter uses. The list is public, so the key is
+// optional.
+var provider = &OpenExchangeRates{
+	AppID:      os.Getenv("API_KEY"),
+	BaseURL:    "https://openexchangerates.org/api",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// currencies caches the list, currencies are added or retired a few times a
+// decade.
+var currencies = cache.New[map[string]string](24 * time.Hour)
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "filter", msg.Filter)
+
+	all, err := currencies.GetOrLoad("all", provider.Currencies)
+	if err != nil {
+		slog.Warn("[sfn] Currencies error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the list of currencies: %v", err))
+		return
+	}
+
+	found := Filter(all, msg.Filter)
+	if len(found) == 0 {
+		sfn.WriteText(ctx, fmt.Sprintf("no supported currency matches %q", msg.Filter))
+		return
+	}
+
+	lines := make([]string, len(found))
+	for i, c := range found {
+		lines[i] = c.Code + ": " + c.Name
+	}
+	sfn.WriteText(ctx, strings.Join(lines, "\n"))
+}
+
+// OpenExchangeRates is a client of the openexchangerates.org API.
+type OpenExchangeRates struct {
+	AppID      string
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// currencies revalidates the list once the cache expires, it is
+	// usually unchanged and answered with a 304.
+	currencies httpx.Conditional[map[string]string]
+}
+
+// Currencies returns the names of the supported currencies by code.
+func (o *OpenExchangeRates) Currencies() (map[string]string, error) {
+	u := o.BaseURL + "/currencies.json"
+	if o.AppID != "" {
+		u += "?app_id=" + url.QueryEscape(o.AppID)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return o.currencies.Do(o.HTTPClient, req, parseCurrencies)
+}
+
+func parseCurrencies(resp *http.Response) (map[string]string, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openexchangerates.org responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var all map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&all); err != nil {
+		return nil, err
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("openexchangerates.org returned no currencies")
+	}
+	return all, nil
+}
+
+// Currency is a supported currency.
+type Currency struct {
+	Code string
+	Name string
+}
+
+// Filter returns the currencies whose code or name contains filter, ignoring
+// case, sorted by code. An empty filter matches all currencies.
+func Filter(all map[string]string, filter string) []Currency {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+
+	var found []Currency
+	for code, name := range all {
+		if strings.Contains(strings.ToLower(code), filter) || strings.Contains(strings.ToLower(name), filter) {
+			found = append(found, Currency{Code: code, Name: name})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Code < found[j].Code })
+	return found
+}
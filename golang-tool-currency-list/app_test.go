@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+)
+
+var sample = map[string]string{
+	"CHF": "Swiss Franc",
+	"EUR": "Euro",
+	"GBP": "British Pound Sterling",
+	"USD": "United States Dollar",
+	"XAF": "CFA Franc BEAC",
+	"XOF": "CFA Franc BCEAO",
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"", []string{"CHF", "EUR", "GBP", "USD", "XAF", "XOF"}},
+		{"franc", []string{"CHF", "XAF", "XOF"}},
+		{" FRANC ", []string{"CHF", "XAF", "XOF"}},
+		{"eur", []string{"EUR"}},
+		{"ch", []string{"CHF"}},
+		{"dollar", []string{"USD"}},
+		{"yen", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range Filter(sample, tt.filter) {
+			got = append(got, c.Code)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Filter(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestCurrenciesCached(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path != "/currencies.json" || r.URL.Query().Get("app_id") != "test" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"CHF":"Swiss Franc","EUR":"Euro"}`))
+	}))
+	defer srv.Close()
+
+	o := &OpenExchangeRates{AppID: "test", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	c := cache.New[map[string]string](time.Hour)
+	for i := 0; i < 3; i++ {
+		all, err := c.GetOrLoad("all", o.Currencies)
+		if err != nil {
+			t.Fatalf("Currencies() error = %v", err)
+		}
+		if all["CHF"] != "Swiss Franc" || len(all) != 2 {
+			t.Errorf("Currencies() = %v", all)
+		}
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("the provider was requested %d times, want 1", got)
+	}
+}
+
+func TestCurrenciesNotModified(t *testing.T) {
+	var notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"list-1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"list-1"`)
+		w.Write([]byte(`{"CHF":"Swiss Franc","EUR":"Euro"}`))
+	}))
+	defer srv.Close()
+
+	o := &OpenExchangeRates{BaseURL: srv.URL, HTTPClient: srv.Client()}
+	for i := 0; i < 2; i++ {
+		all, err := o.Currencies()
+		if err != nil {
+			t.Fatalf("Currencies() error = %v", err)
+		}
+		if all["EUR"] != "Euro" || len(all) != 2 {
+			t.Errorf("Currencies() = %v", all)
+		}
+	}
+	if notModified != 1 {
+		t.Errorf("the list was revalidated %d times, want 1", notModified)
+	}
+}
+
+func TestCurrenciesError(t *testing.T) {
+	for _, body := range []string{"", "{}", "not json"} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if body == "" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			w.Write([]byte(body))
+		}))
+
+		o := &OpenExchangeRates{BaseURL: srv.URL, HTTPClient: srv.Client()}
+		if _, err := o.Currencies(); err == nil {
+			t.Errorf("Currencies() with body %q should fail", body)
+		}
+		srv.Close()
+	}
+}
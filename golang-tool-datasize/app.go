@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert a data size between units, e.g. "how many MB is 3.5 GB?" or "how many bytes is 2 GiB?". Units are bits (b), bytes (B), KB, MB, GB, TB and PB, and the binary KiB, MiB, GiB, TiB and PiB. Set binary to true when KB, MB and so on mean multiples of 1024, as operating systems and RAM sizes use them, and false for multiples of 1000 as disk vendors and network speeds use them.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value  float64 `json:"value" jsonschema:"description=The data size to convert,minimum=0"`
+	From   string  `json:"from" jsonschema:"description=The unit of the value,enum=b,enum=B,enum=KB,enum=MB,enum=GB,enum=TB,enum=PB,enum=KiB,enum=MiB,enum=GiB,enum=TiB,enum=PiB"`
+	To     string  `json:"to" jsonschema:"description=The unit to convert the value to,enum=b,enum=B,enum=KB,enum=MB,enum=GB,enum=TB,enum=PB,enum=KiB,enum=MiB,enum=GiB,enum=TiB,enum=PiB"`
+	Binary bool    `json:"binary,omitempty" jsonschema:"description=Whether KB/MB/GB/TB/PB are multiples of 1024 instead of 1000"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "datasize", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC9}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	converted, err := Convert(msg.Value, msg.From, msg.To, msg.Binary)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g %s to %s: %v", msg.Value, msg.From, msg.To, err))
+		return
+	}
+
+	base := "1000"
+	if msg.Binary {
+		base = "1024"
+	}
+	sfn.WriteText(ctx, fmt.Sprintf("%g %s is %s %s (KB, MB, GB... as multiples of %s)", msg.Value, msg.From, format(converted), msg.To, base))
+}
+
+type unit struct {
+	// power is the exponent of the multiplier: 1 for K, 2 for M and so on.
+	power int
+	// binary units are multiples of 1024 whatever the binary argument.
+	binary bool
+	// bits counts bits instead of bytes.
+	bits bool
+}
+
+// units are keyed by lower case name, except the bit "b" and the byte "B".
+var units = map[string]unit{
+	"b":   {bits: true},
+	"B":   {},
+	"kb":  {power: 1},
+	"mb":  {power: 2},
+	"gb":  {power: 3},
+	"tb":  {power: 4},
+	"pb":  {power: 5},
+	"kib": {power: 1, binary: true},
+	"mib": {power: 2, binary: true},
+	"gib": {power: 3, binary: true},
+	"tib": {power: 4, binary: true},
+	"pib": {power: 5, binary: true},
+}
+
+// aliases maps spelled out names to the keys of units.
+var aliases = map[string]string{
+	"bit": "b", "bits": "b",
+	"byte": "B", "bytes": "B",
+	"kilobyte": "kb", "kilobytes": "kb",
+	"megabyte": "mb", "megabytes": "mb",
+	"gigabyte": "gb", "gigabytes": "gb",
+	"terabyte": "tb", "terabytes": "tb",
+	"petabyte": "pb", "petabytes": "pb",
+	"kibibyte": "kib", "kibibytes": "kib",
+	"mebibyte": "mib", "mebibytes": "mib",
+	"gibibyte": "gib", "gibibytes": "gib",
+	"tebibyte": "tib", "tebibytes": "tib",
+	"pebibyte": "pib", "pebibytes": "pib",
+}
+
+func lookupUnit(name string) (unit, bool) {
+	name = strings.TrimSpace(name)
+	// "b" is a bit and "B" a byte, the other units ignore the case
+	if name == "b" || name == "B" {
+		return units[name], true
+	}
+	key := strings.ToLower(name)
+	if alias, ok := aliases[key]; ok {
+		key = alias
+	}
+	u, ok := units[key]
+	return u, ok
+}
+
+// bytes returns how many bytes one of u is.
+func (u unit) bytes(binary bool) float64 {
+	if u.bits {
+		return 1.0 / 8
+	}
+	base := 1000.0
+	if u.binary || binary {
+		base = 1024
+	}
+	return math.Pow(base, float64(u.power))
+}
+
+// Convert converts a data size from one unit to another. When binary is set,
+// the KB to PB units are multiples of 1024.
+func Convert(value float64, from, to string, binary bool) (float64, error) {
+	f, ok := lookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	t, ok := lookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return 0, fmt.Errorf("%v is not a data size", value)
+	}
+	return value * f.bytes(binary) / t.bytes(binary), nil
+}
+
+// format prints a size rounded to 6 decimals without trailing zeros, e.g.
+// 1048576 or 3.814697.
+func format(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		binary   bool
+		want     float64
+	}{
+		// SI
+		{1, "GB", "MB", false, 1000},
+		{1, "TB", "GB", false, 1000},
+		{1500, "KB", "MB", false, 1.5},
+		{1, "MB", "B", false, 1e6},
+		{1, "PB", "KB", false, 1e12},
+		// binary
+		{1, "GB", "MB", true, 1024},
+		{1, "MB", "B", true, 1 << 20},
+		{1, "TB", "KB", true, 1 << 30},
+		{512, "MB", "GB", true, 0.5},
+		// the iB units are binary whatever the flag
+		{1, "GiB", "MiB", false, 1024},
+		{1, "GiB", "B", false, 1 << 30},
+		// the "500 GB" disk shows as 465.66 GiB
+		{500, "GB", "GiB", false, 465.66128730773926},
+		{1, "GiB", "GB", false, 1.073741824},
+		// bits
+		{8, "b", "B", false, 1},
+		{1, "MB", "b", false, 8e6},
+		{100, "MB", "b", true, 100 * 8 * (1 << 20)},
+		// case and names
+		{2, "gigabytes", "megabytes", false, 2000},
+		{1, "kib", "bytes", false, 1024},
+		{16, "bits", "byte", false, 2},
+	}
+
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to, tt.binary)
+		if err != nil {
+			t.Errorf("Convert(%v, %s, %s, %v) error = %v", tt.value, tt.from, tt.to, tt.binary, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9*math.Max(1, tt.want) {
+			t.Errorf("Convert(%v, %s, %s, %v) = %v, want %v", tt.value, tt.from, tt.to, tt.binary, got, tt.want)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to string
+	}{
+		{"unknown from unit", 1, "EB", "B"},
+		{"unknown to unit", 1, "B", "nibble"},
+		{"negative", -1, "B", "KB"},
+		{"not a number", math.NaN(), "B", "KB"},
+	}
+	for _, tt := range tests {
+		if _, err := Convert(tt.value, tt.from, tt.to, false); err == nil {
+			t.Errorf("%s: Convert(%v, %s, %s) should fail", tt.name, tt.value, tt.from, tt.to)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for v, want := range map[float64]string{1048576: "1048576", 3.8146972656: "3.814697", 0.5: "0.5", 1e15: "1000000000000000"} {
+		if got := format(v); got != want {
+			t.Errorf("format(%v) = %s, want %s", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/solar"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Tell how much longer or shorter the daylight of a location is today than yesterday, e.g. "are the days getting longer yet?". If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format and give the IANA time zone of the city. The function returns the change and the length of the daylight, from sunrise to sunset.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+	Date      string  `json:"date,omitempty" jsonschema:"description=The date to compare with the day before in YYYY-MM-DD format. Defaults to today,example=2024-03-20"`
+	Timezone  string  `json:"timezone" jsonschema:"description=The time zone of the location in IANA Time Zone Database identifier format,example=Europe/Paris"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "daylight-change", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xDE}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "date", msg.Date, "timezone", msg.Timezone)
+
+	result, err := DaylightChange(msg, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] DaylightChange error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the daylight change: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// DaylightChange resolves the date of p, today in its time zone by default,
+// and compares its daylight with the day before.
+func DaylightChange(p Parameter, now time.Time) (string, error) {
+	if err := geo.ValidateCoordinate(p.Latitude, p.Longitude); err != nil {
+		return "", err
+	}
+	if p.Timezone == "" {
+		return "", errors.New("the time zone of the location is missing")
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return "", fmt.Errorf("unknown time zone %q", p.Timezone)
+	}
+
+	day := now.In(loc)
+	if p.Date != "" {
+		if day, err = time.ParseInLocation("2006-01-02", p.Date, loc); err != nil {
+			return "", fmt.Errorf("can not understand the date %q, please use YYYY-MM-DD", p.Date)
+		}
+	}
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	today := Daylight(p.Latitude, p.Longitude, midnight)
+	yesterday := Daylight(p.Latitude, p.Longitude, midnight.AddDate(0, 0, -1))
+
+	place := fmt.Sprintf("at %v,%v on %s", p.Latitude, p.Longitude, midnight.Format("2006-01-02"))
+	switch {
+	case today.Length == 0 && yesterday.Length == 0:
+		return fmt.Sprintf("The sun does not rise %s nor the day before, it is the polar night.", place), nil
+	case today.AllDay && yesterday.AllDay:
+		return fmt.Sprintf("The sun does not set %s nor the day before, it is the midnight sun.", place), nil
+	}
+	return fmt.Sprintf("%s of daylight vs yesterday %s: %s today and %s the day before.",
+		signed(today.Length-yesterday.Length), place, today, yesterday), nil
+}
+
+// Day is the daylight of a day.
+type Day struct {
+	// Length is the time the sun is up, to the second.
+	Length time.Duration
+	// AllDay is whether the sun does not set.
+	AllDay bool
+}
+
+func (d Day) String() string {
+	switch {
+	case d.AllDay:
+		return "the midnight sun"
+	case d.Length == 0:
+		return "the polar night"
+	}
+	return d.Length.String()
+}
+
+// Daylight returns the time from sunrise to sunset of the day beginning at
+// midnight, the sum of its days on the date a polar day begins or ends.
+func Daylight(lat, lon float64, midnight time.Time) Day {
+	end := midnight.AddDate(0, 0, 1)
+	var d Day
+	for _, w := range solar.Windows(lat, lon, midnight, end, func(el float64) bool { return el >= solar.SunriseElevation }) {
+		d.Length += w.End.Sub(w.Start)
+	}
+	// the day can last 23 or 25 hours on a daylight saving change
+	d.AllDay = d.Length == end.Sub(midnight)
+	return d
+}
+
+// signed formats d with its sign, e.g. "+2m14s" or "-37s".
+func signed(d time.Duration) string {
+	if d < 0 {
+		return d.String()
+	}
+	return "+" + d.String()
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaylight(t *testing.T) {
+	// The changes are checked against the sunrise equation with the
+	// declination series of Spencer (1971), within 15 seconds: about
+	// 2 tan(latitude) times the daily change of the declination, 0.39° at
+	// the equinoxes. The series drifts by a few minutes of daylight at the
+	// equinoxes, so the lengths are only checked within a minute at the
+	// solstices, e.g. London has 7h49m42s of daylight in December.
+	tests := []struct {
+		name       string
+		lat, lon   float64
+		tz         string
+		date       string
+		wantLength time.Duration
+		wantChange time.Duration
+	}{
+		{"paris spring", 48.8566, 2.3522, "Europe/Paris", "2024-03-20", 0, 217 * time.Second},
+		{"paris solstice", 48.8566, 2.3522, "Europe/Paris", "2024-06-21", 16*time.Hour + 11*time.Minute + 2*time.Second, 3 * time.Second},
+		{"paris autumn", 48.8566, 2.3522, "Europe/Paris", "2024-09-22", 0, -214 * time.Second},
+		{"sydney autumn", -33.8688, 151.2093, "Australia/Sydney", "2024-03-20", 0, -127 * time.Second},
+		{"london solstice", 51.5074, -0.1278, "Europe/London", "2024-12-21", 7*time.Hour + 49*time.Minute + 42*time.Second, -5 * time.Second},
+		{"tromsø spring", 69.65, 18.96, "Europe/Oslo", "2024-03-20", 0, 511 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.tz)
+			if err != nil {
+				t.Fatal(err)
+			}
+			day, _ := time.ParseInLocation("2006-01-02", tt.date, loc)
+			today := Daylight(tt.lat, tt.lon, day)
+			yesterday := Daylight(tt.lat, tt.lon, day.AddDate(0, 0, -1))
+			if d := today.Length - tt.wantLength; tt.wantLength != 0 && (d < -time.Minute || d > time.Minute) {
+				t.Errorf("Daylight() = %v, want %v", today.Length, tt.wantLength)
+			}
+			if d := today.Length - yesterday.Length - tt.wantChange; d < -15*time.Second || d > 15*time.Second {
+				t.Errorf("the change is %v, want %v", today.Length-yesterday.Length, tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestDaylightChange(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Parameter
+		want string
+	}{
+		{
+			name: "longer",
+			p:    Parameter{Latitude: 48.8566, Longitude: 2.3522, Date: "2024-03-20", Timezone: "Europe/Paris"},
+			// about +3m37s
+			want: "+3m",
+		},
+		{
+			name: "polar night",
+			p:    Parameter{Latitude: 78.22, Longitude: 15.65, Date: "2024-12-21", Timezone: "Arctic/Longyearbyen"},
+			want: "The sun does not rise at 78.22,15.65 on 2024-12-21 nor the day before, it is the polar night.",
+		},
+		{
+			name: "midnight sun",
+			p:    Parameter{Latitude: 78.22, Longitude: 15.65, Date: "2024-06-21", Timezone: "Arctic/Longyearbyen"},
+			want: "The sun does not set at 78.22,15.65 on 2024-06-21 nor the day before, it is the midnight sun.",
+		},
+		{
+			// the polar night of Longyearbyen ends in mid February
+			name: "first sunrise",
+			p:    Parameter{Latitude: 78.22, Longitude: 15.65, Date: "2024-02-16", Timezone: "Arctic/Longyearbyen"},
+			want: "today and the polar night the day before.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DaylightChange(tt.p, time.Now())
+			if err != nil {
+				t.Fatalf("DaylightChange() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("DaylightChange() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDaylightChangeInvalid(t *testing.T) {
+	for _, p := range []Parameter{
+		{Latitude: -91, Timezone: "UTC"},
+		{Longitude: 200, Timezone: "UTC"},
+		{Date: "2024-03-20"},
+		{Timezone: "Europe/Atlantis"},
+		{Date: "March 20", Timezone: "UTC"},
+	} {
+		if _, err := DaylightChange(p, time.Now()); err == nil {
+			t.Errorf("DaylightChange(%+v) should fail", p)
+		}
+	}
+}
+
+func TestSigned(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{2*time.Minute + 14*time.Second, "+2m14s"},
+		{-37 * time.Second, "-37s"},
+		{0, "+0s"},
+	}
+	for _, tt := range tests {
+		if got := signed(tt.d); got != tt.want {
+			t.Errorf("signed(%v) = %s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
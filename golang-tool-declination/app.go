@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the magnetic declination at a location today, the angle between the magnetic north a compass points to and the true north, e.g. to correct a compass bearing on a hike. If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns the declination in degrees east or west of the true north.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "declination", Description: Description(), InputSchema: InputSchema(), Env: []string{"NOAA_GEOMAG_API_KEY"}, Upstream: calculator.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xDA}
+}
+
+var calculator = &Geomag{
+	Key:        os.Getenv("NOAA_GEOMAG_API_KEY"),
+	BaseURL:    "https://www.ngdc.noaa.gov",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	d, err := MagneticDeclination(reqCtx, calculator, msg.Latitude, msg.Longitude)
+	if err != nil {
+		slog.Warn("[sfn] MagneticDeclination error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the magnetic declination: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, d.String())
+}
+
+// Declination is the magnetic declination at a location, positive east.
+type Declination struct {
+	Latitude, Longitude float64
+	Degrees             float64
+	// Uncertainty is the 1-sigma error of the model in degrees, it grows
+	// near the magnetic poles.
+	Uncertainty float64
+	// AnnualChange is the secular variation in degrees per year.
+	AnnualChange float64
+	Model        string
+}
+
+func (d *Declination) String() string {
+	s := fmt.Sprintf("The magnetic declination at %v,%v is %s", d.Latitude, d.Longitude, eastWest(d.Degrees))
+	if d.Uncertainty > 0 {
+		s += fmt.Sprintf(" (±%.2f°)", d.Uncertainty)
+	}
+	if d.Model != "" {
+		s += " according to the " + d.Model
+	}
+	side := "east"
+	if d.Degrees < 0 {
+		side = "west"
+	}
+	s += ", a compass points that far " + side + " of the true north"
+	if d.AnnualChange != 0 {
+		s += fmt.Sprintf(". It changes by %s per year", eastWest(d.AnnualChange))
+	}
+	return s + "."
+}
+
+// eastWest formats an angle positive east, e.g. "1.52° W".
+func eastWest(deg float64) string {
+	if deg < 0 {
+		return fmt.Sprintf("%.2f° W", -deg)
+	}
+	return fmt.Sprintf("%.2f° E", deg)
+}
+
+// MagneticDeclination validates the coordinates and looks up their
+// declination today with g.
+func MagneticDeclination(ctx context.Context, g *Geomag, lat, lon float64) (*Declination, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return nil, err
+	}
+	return g.Declination(ctx, lat, lon)
+}
+
+// Geomag is a client of the NOAA magnetic field calculators, which evaluate
+// the World Magnetic Model at sea level, see
+// https://www.ngdc.noaa.gov/geomag/CalcSurvey.shtml.
+type Geomag struct {
+	Key        string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Declination returns the declination at lat,lon today.
+func (g *Geomag) Declination(ctx context.Context, lat, lon float64) (*Declination, error) {
+	if g.Key == "" {
+		return nil, errors.New("NOAA_GEOMAG_API_KEY is not set")
+	}
+
+	q := url.Values{}
+	q.Set("lat1", fmt.Sprint(lat))
+	q.Set("lon1", fmt.Sprint(lon))
+	q.Set("model", "WMM")
+	q.Set("resultFormat", "json")
+	q.Set("key", g.Key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+"/geomag-web/calculators/calculateDeclination?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		// the error text has the URL, and the key in it
+		return nil, httpx.Redact(err, "key")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the NOAA calculator responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var r struct {
+		Model  string `json:"model"`
+		Result []struct {
+			Declination float64 `json:"declination"`
+			// sic, the API misspells the secular variation
+			AnnualChange float64 `json:"declnation_sv"`
+			Uncertainty  float64 `json:"declination_uncertainty"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode the NOAA response: %w", err)
+	}
+	if len(r.Result) == 0 {
+		return nil, errors.New("the NOAA calculator returned no result")
+	}
+	res := r.Result[0]
+	return &Declination{
+		Latitude:     lat,
+		Longitude:    lon,
+		Degrees:      res.Declination,
+		Uncertainty:  res.Uncertainty,
+		AnnualChange: res.AnnualChange,
+		Model:        r.Model,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the heating and cooling degree days of a list of daily mean temperatures, e.g. to estimate the energy needed to heat or cool a building over a month. Each day adds how far its mean temperature is below the base temperature to the heating degree days, or above it to the cooling degree days. The base defaults to 18°C or 65°F. Always use this function instead of adding up the degree days yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Temperatures []float64 `json:"temperatures" jsonschema:"description=The daily mean temperatures one per day,minItems=1,maxItems=366"`
+	Base         *float64  `json:"base,omitempty" jsonschema:"description=The base temperature in the unit of the temperatures. Defaults to 18 in Celsius and 65 in Fahrenheit"`
+	Unit         string    `json:"unit,omitempty" jsonschema:"description=The unit of the temperatures. Defaults to celsius,enum=celsius,enum=fahrenheit"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "degree-days", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF4}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "days", len(msg.Temperatures), "unit", msg.Unit)
+
+	result, err := Compute(msg.Temperatures, msg.Base, msg.Unit)
+	if err != nil {
+		slog.Warn("[sfn] Compute error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the degree days: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result.String())
+	sfn.WriteText(ctx, result.String())
+}
+
+// maxDays is the most temperatures accepted, a leap year of daily means.
+const maxDays = 366
+
+type scale struct {
+	symbol string
+	// base is the customary base temperature, 18°C in Europe and 65°F in
+	// the US.
+	base float64
+	// min and max bound a plausible daily mean temperature on earth.
+	min, max float64
+}
+
+var scales = map[string]scale{
+	"celsius":    {symbol: "°C", base: 18, min: -90, max: 60},
+	"fahrenheit": {symbol: "°F", base: 65, min: -130, max: 140},
+}
+
+// Result is the degree days of a period.
+type Result struct {
+	Days            int
+	Base            float64
+	Symbol          string
+	Heating         float64
+	Cooling         float64
+	HeatingDays     int
+	CoolingDays     int
+	MeanTemperature float64
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("Over %d days with a mean of %.1f%s and a base of %g%s: %.1f heating degree days (%d days below the base) and %.1f cooling degree days (%d days above the base).",
+		r.Days, r.MeanTemperature, r.Symbol, r.Base, r.Symbol, r.Heating, r.HeatingDays, r.Cooling, r.CoolingDays)
+}
+
+// Compute totals the heating and cooling degree days of daily mean
+// temperatures with the mean temperature method: a day at mean t adds
+// base - t to the heating and t - base to the cooling degree days, whichever
+// is positive. base defaults to the customary one of the unit when nil.
+func Compute(temperatures []float64, base *float64, unit string) (*Result, error) {
+	if unit == "" {
+		unit = "celsius"
+	}
+	s, ok := scales[strings.ToLower(unit)]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit %q, use celsius or fahrenheit", unit)
+	}
+	switch {
+	case len(temperatures) == 0:
+		return nil, errors.New("no temperatures are given")
+	case len(temperatures) > maxDays:
+		return nil, fmt.Errorf("%d temperatures are given, at most %d are supported", len(temperatures), maxDays)
+	}
+
+	r := &Result{Days: len(temperatures), Base: s.base, Symbol: s.symbol}
+	if base != nil {
+		if !plausible(s, *base) {
+			return nil, fmt.Errorf("the base %v%s is not a plausible temperature", *base, s.symbol)
+		}
+		r.Base = *base
+	}
+
+	var sum float64
+	for i, t := range temperatures {
+		if !plausible(s, t) {
+			return nil, fmt.Errorf("the temperature of day %d, %v%s, is not a plausible daily mean", i+1, t, s.symbol)
+		}
+		sum += t
+		switch d := t - r.Base; {
+		case d < 0:
+			r.Heating -= d
+			r.HeatingDays++
+		case d > 0:
+			r.Cooling += d
+			r.CoolingDays++
+		}
+	}
+	r.MeanTemperature = sum / float64(len(temperatures))
+	return r, nil
+}
+
+func plausible(s scale, t float64) bool {
+	return !math.IsNaN(t) && t >= s.min && t <= s.max
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	// a week worked out by hand against the base of 18°C:
+	//	day   mean  heating  cooling
+	//	1     10.0   8.0
+	//	2     15.5   2.5
+	//	3     18.0
+	//	4     21.0            3.0
+	//	5     25.5            7.5
+	//	6     -2.0  20.0
+	//	7     17.0   1.0
+	//	total       31.5     10.5
+	week := []float64{10, 15.5, 18, 21, 25.5, -2, 17}
+
+	r, err := Compute(week, nil, "")
+	if err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if r.Heating != 31.5 || r.Cooling != 10.5 || r.HeatingDays != 4 || r.CoolingDays != 2 || r.Days != 7 || r.Base != 18 {
+		t.Errorf("Compute() = %+v, want 31.5 heating over 4 days and 10.5 cooling over 2", r)
+	}
+	if math.Abs(r.MeanTemperature-15) > 1e-9 {
+		t.Errorf("Compute() mean = %v, want 15", r.MeanTemperature)
+	}
+
+	want := "Over 7 days with a mean of 15.0°C and a base of 18°C: 31.5 heating degree days (4 days below the base) and 10.5 cooling degree days (2 days above the base)."
+	if got := r.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
+
+func TestComputeBase(t *testing.T) {
+	// against 15.5°C the same week is 23.0 heating and 19.5 cooling
+	week := []float64{10, 15.5, 18, 21, 25.5, -2, 17}
+	base := 15.5
+	r, err := Compute(week, &base, "celsius")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Heating != 23 || r.Cooling != 19.5 || r.HeatingDays != 2 || r.CoolingDays != 4 {
+		t.Errorf("Compute(base 15.5) = %+v, want 23 heating and 19.5 cooling", r)
+	}
+
+	// a base of 0 is a base, not a missing one
+	zero := 0.0
+	r, err = Compute([]float64{-3, 2}, &zero, "")
+	if err != nil || r.Base != 0 || r.Heating != 3 || r.Cooling != 2 {
+		t.Errorf("Compute(base 0) = %+v, %v", r, err)
+	}
+}
+
+func TestComputeFahrenheit(t *testing.T) {
+	// the US default base is 65°F
+	r, err := Compute([]float64{50, 65, 80, 72.5}, nil, "Fahrenheit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Base != 65 || r.Heating != 15 || r.Cooling != 22.5 {
+		t.Errorf("Compute() = %+v, want 15 heating and 22.5 cooling against 65°F", r)
+	}
+}
+
+func TestComputeInvalid(t *testing.T) {
+	hot := 75.0
+	nan := math.NaN()
+	tests := []struct {
+		name         string
+		temperatures []float64
+		base         *float64
+		unit         string
+	}{
+		{"no days", nil, nil, ""},
+		{"too many days", make([]float64, maxDays+1), nil, ""},
+		{"unknown unit", []float64{10}, nil, "kelvin"},
+		{"implausible temperature", []float64{10, 99}, nil, "celsius"},
+		{"NaN temperature", []float64{math.NaN()}, nil, ""},
+		{"implausible base", []float64{10}, &hot, "celsius"},
+		{"NaN base", []float64{10}, &nan, ""},
+	}
+	for _, tt := range tests {
+		if _, err := Compute(tt.temperatures, tt.base, tt.unit); err == nil {
+			t.Errorf("%s: Compute() should fail", tt.name)
+		}
+	}
+
+	// 99°F is a hot day, not an error
+	if _, err := Compute([]float64{99}, nil, "fahrenheit"); err != nil {
+		t.Errorf("Compute(99°F) error = %v", err)
+	}
+}
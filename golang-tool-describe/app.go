@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/schema"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Describe another registered function by its name: what it does and the JSON schema of its arguments, e.g. to find out how to call a function before calling it. The function returns the name, the description and the parameters of the function as JSON.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Name string `json:"name" jsonschema:"description=The name of the function to describe,example=get-weather"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "describe", Description: Description(), InputSchema: InputSchema()})
+	// the other functions run in processes of their own, their definitions
+	// come from the manifest of the deployment
+	if err := registry.LoadManifest(); err != nil {
+		slog.Warn("[sfn] LoadManifest error", "err", err)
+	}
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xFE}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "name", msg.Name)
+
+	result, err := Describe(registry.Default, msg.Name)
+	if err != nil {
+		slog.Warn("[sfn] Describe error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not describe %q: %v", msg.Name, err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// ErrNotRegistered is returned for a name no function is registered with.
+var ErrNotRegistered = errors.New("no such function is registered")
+
+// Doc is the documentation of a function, in the shape of an OpenAI function
+// definition.
+type Doc struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Parameters  *schema.Parameters `json:"parameters"`
+}
+
+// Describe returns the Doc of the function registered in r with the given
+// name as indented JSON. The error of an unknown name lists the registered
+// functions, so that the LLM can pick the right one.
+func Describe(r *registry.Registry, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	t, ok := r.Lookup(name)
+	if !ok {
+		tools := r.Tools()
+		names := make([]string, len(tools))
+		for i, t := range tools {
+			names[i] = t.Name
+		}
+		return "", fmt.Errorf("%w, the registered functions are %s", ErrNotRegistered, strings.Join(names, ", "))
+	}
+
+	params, err := schema.Reflect(t.InputSchema)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(Doc{Name: t.Name, Description: t.Description, Parameters: params}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
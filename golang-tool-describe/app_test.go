@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+)
+
+type zipcodeArguments struct {
+	Code    string `json:"code" jsonschema:"description=The postal code"`
+	Country string `json:"country,omitempty" jsonschema:"description=The ISO country code,example=US"`
+}
+
+func testRegistry() *registry.Registry {
+	r := registry.New()
+	r.Register(registry.Tool{Name: "zipcode", Description: "Look up the place of a postal code.", InputSchema: &zipcodeArguments{}})
+	r.Register(registry.Tool{Name: "get-utc-time", Description: "Get the current UTC time."})
+	return r
+}
+
+func TestDescribe(t *testing.T) {
+	got, err := Describe(testRegistry(), " zipcode ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "name": "zipcode",
+  "description": "Look up the place of a postal code.",
+  "parameters": {
+    "type": "object",
+    "properties": {
+      "code": {
+        "type": "string",
+        "description": "The postal code"
+      },
+      "country": {
+        "type": "string",
+        "description": "The ISO country code",
+        "examples": [
+          "US"
+        ]
+      }
+    },
+    "required": [
+      "code"
+    ]
+  }
+}`
+	if got != want {
+		t.Errorf("Describe() = %s\nwant %s", got, want)
+	}
+}
+
+func TestDescribeNoArguments(t *testing.T) {
+	got, err := Describe(testRegistry(), "get-utc-time")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "name": "get-utc-time",
+  "description": "Get the current UTC time.",
+  "parameters": {
+    "type": "object",
+    "properties": {}
+  }
+}`
+	if got != want {
+		t.Errorf("Describe() = %s\nwant %s", got, want)
+	}
+}
+
+func TestDescribeUnknown(t *testing.T) {
+	_, err := Describe(testRegistry(), "get-weather")
+	if !errors.Is(err, ErrNotRegistered) {
+		t.Fatalf("Describe() error = %v, want ErrNotRegistered", err)
+	}
+	want := "no such function is registered, the registered functions are get-utc-time, zipcode"
+	if err.Error() != want {
+		t.Errorf("Describe() error = %q, want %q", err, want)
+	}
+}
+
+func TestDescribeFromManifest(t *testing.T) {
+	r := registry.New()
+	manifest := `[{"type":"function","function":{"name":"get-weather","description":"Get the weather of a city.","parameters":{"type":"object","properties":{"city":{"type":"string","description":"The city name"}},"required":["city"]}}}]`
+	if err := r.LoadOpenAI([]byte(manifest)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Describe(r, "get-weather")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "name": "get-weather",
+  "description": "Get the weather of a city.",
+  "parameters": {
+    "type": "object",
+    "properties": {
+      "city": {
+        "type": "string",
+        "description": "The city name"
+      }
+    },
+    "required": [
+      "city"
+    ]
+  }
+}`
+	if got != want {
+		t.Errorf("Describe() = %s\nwant %s", got, want)
+	}
+}
+
+func TestDescribeSelf(t *testing.T) {
+	if _, err := Describe(registry.Default, "describe"); err != nil {
+		t.Errorf("Describe() error = %v", err)
+	}
+}
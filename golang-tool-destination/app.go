@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute where you end up when travelling a distance from a start point in an initial compass direction along a great circle, e.g. "where am I after 500 km northeast of Berlin?". If the user gives a city name, convert it to Latitude and Longitude in decimal format, and convert a compass direction to degrees clockwise from north, e.g. NE is 45.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude   float64 `json:"latitude" jsonschema:"description=The latitude of the start in decimal format,minimum=-90,maximum=90"`
+	Longitude  float64 `json:"longitude" jsonschema:"description=The longitude of the start in decimal format,minimum=-180,maximum=180"`
+	Bearing    float64 `json:"bearing" jsonschema:"description=The initial bearing in degrees clockwise from north,minimum=0,exclusiveMaximum=360"`
+	DistanceKm float64 `json:"distanceKm" jsonschema:"description=The distance to travel in kilometers,minimum=0,maximum=20015"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "destination", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD1}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	lat, lon, err := Destination(msg.Latitude, msg.Longitude, msg.Bearing, msg.DistanceKm)
+	if err != nil {
+		slog.Warn("[sfn] Destination error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the destination: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("Travelling %v km from %v,%v with an initial bearing of %v° along a great circle leads to %.6f,%.6f", msg.DistanceKm, msg.Latitude, msg.Longitude, msg.Bearing, lat, lon))
+}
+
+// maxDistanceKm is half the circumference of the earth, the distance to the
+// antipode. A longer trip comes back towards the start.
+const maxDistanceKm = math.Pi * geo.EarthRadiusKm
+
+// Destination validates the start, bearing and distance, and returns the
+// coordinate of the destination on a spherical earth.
+func Destination(lat, lon, bearing, distanceKm float64) (float64, float64, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return 0, 0, err
+	}
+	if math.IsNaN(bearing) || bearing < 0 || bearing >= 360 {
+		return 0, 0, fmt.Errorf("bearing %v is not in degrees from 0 up to 360", bearing)
+	}
+	if math.IsNaN(distanceKm) || distanceKm < 0 || distanceKm > maxDistanceKm {
+		return 0, 0, fmt.Errorf("the distance must be from 0 to %.0f km, got %v", maxDistanceKm, distanceKm)
+	}
+
+	lat2, lon2 := geo.Destination(lat, lon, bearing, distanceKm)
+	return lat2, lon2, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+)
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		name                      string
+		lat, lon, bearing, distKm float64
+		wantLat, wantLon          float64
+	}{
+		{"no distance", 52.52, 13.405, 45, 0, 52.52, 13.405},
+		// computed independently by rotating the unit vector of the start
+		{"fiji across the antimeridian", -16.5, 179.9, 80, 50, -16.421411, -179.638339},
+		{"sydney to santiago", -33.8688, 151.2093, 145.2827, 11346.7, -33.449144, -70.66946},
+	}
+	for _, tt := range tests {
+		lat, lon, err := Destination(tt.lat, tt.lon, tt.bearing, tt.distKm)
+		if err != nil {
+			t.Errorf("%s: Destination() error = %v", tt.name, err)
+			continue
+		}
+		if math.Abs(lat-tt.wantLat) > 1e-5 || math.Abs(lon-tt.wantLon) > 1e-5 {
+			t.Errorf("%s: Destination() = %.6f,%.6f, want %.6f,%.6f", tt.name, lat, lon, tt.wantLat, tt.wantLon)
+		}
+	}
+}
+
+func TestDestinationRoundTrip(t *testing.T) {
+	// the distance and bearing back to the destination match the trip
+	lat, lon, err := Destination(48.8566, 2.3522, 300, 1500)
+	if err != nil {
+		t.Fatalf("Destination() error = %v", err)
+	}
+	if d := geo.Distance(48.8566, 2.3522, lat, lon); math.Abs(d-1500) > 1e-6 {
+		t.Errorf("Distance() to the destination = %v km, want 1500 km", d)
+	}
+	if b := geo.Bearing(48.8566, 2.3522, lat, lon); math.Abs(b-300) > 1e-6 {
+		t.Errorf("Bearing() to the destination = %v°, want 300°", b)
+	}
+}
+
+func TestDestinationInvalid(t *testing.T) {
+	tests := []struct {
+		name                      string
+		lat, lon, bearing, distKm float64
+	}{
+		{"latitude out of range", -91, 0, 0, 10},
+		{"longitude out of range", 0, 180.5, 0, 10},
+		{"negative bearing", 0, 0, -10, 10},
+		{"bearing of 360", 0, 0, 360, 10},
+		{"negative distance", 0, 0, 90, -1},
+		{"beyond the antipode", 0, 0, 90, 25000},
+		{"not a number", 0, 0, math.NaN(), 10},
+	}
+	for _, tt := range tests {
+		if _, _, err := Destination(tt.lat, tt.lon, tt.bearing, tt.distKm); err == nil {
+			t.Errorf("%s: Destination() should fail", tt.name)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Calculate the dew point, the temperature at which the air becomes saturated and dew or fog forms, from the air temperature and the relative humidity, e.g. "what is the dew point at 25°C and 60% humidity?". The function returns the dew point and how humid the air feels.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Temperature float64 `json:"temperature" jsonschema:"description=The air temperature in degrees Celsius or Fahrenheit depending on units"`
+	Humidity    float64 `json:"humidity" jsonschema:"description=The relative humidity in percent,minimum=0,maximum=100"`
+	Units       string  `json:"units,omitempty" jsonschema:"description=metric for °C or imperial for °F. Defaults to metric,enum=metric,enum=imperial"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "dew-point", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE0}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	result, err := Calculate(msg)
+	if err != nil {
+		slog.Warn("[sfn] Calculate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not calculate the dew point: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// The Magnus coefficients of Sonntag (1990) over water, within 0.35°C of
+// the saturation vapor pressure from -45°C to 60°C.
+const (
+	magnusB = 17.62
+	magnusC = 243.12
+)
+
+// DewPoint returns the dew point in °C of the air at the temperature t in
+// °C and the relative humidity rh in percent, with the Magnus formula.
+func DewPoint(t, rh float64) float64 {
+	gamma := math.Log(rh/100) + magnusB*t/(magnusC+t)
+	return magnusC * gamma / (magnusB - gamma)
+}
+
+// Calculate validates p and describes its dew point, e.g.
+// "The dew point at 25.0°C and 60% humidity is 16.7°C: humid".
+func Calculate(p Parameter) (string, error) {
+	units := strings.ToLower(strings.TrimSpace(p.Units))
+	if units == "" {
+		units = "metric"
+	}
+	if units != "metric" && units != "imperial" {
+		return "", fmt.Errorf("unknown units %q, use metric or imperial", p.Units)
+	}
+	if math.IsNaN(p.Humidity) || p.Humidity < 0 || p.Humidity > 100 {
+		return "", fmt.Errorf("humidity %v%% is not between 0 and 100", p.Humidity)
+	}
+	// the dew point of perfectly dry air is minus infinity
+	if p.Humidity == 0 {
+		return "", fmt.Errorf("air with 0%% humidity has no dew point")
+	}
+
+	t, symbol := p.Temperature, "°C"
+	if units == "imperial" {
+		t, symbol = (t-32)*5/9, "°F"
+	}
+	if math.IsNaN(t) || t < -45 || t > 60 {
+		return "", fmt.Errorf("temperature %v%s is out of the range of the formula, from -45°C to 60°C", p.Temperature, symbol)
+	}
+
+	dp := DewPoint(t, p.Humidity)
+	comfort := Comfort(dp)
+	if units == "imperial" {
+		dp = dp*9/5 + 32
+	}
+	return fmt.Sprintf("The dew point at %.1f%s and %g%% humidity is %.1f%s: %s",
+		p.Temperature, symbol, p.Humidity, dp, symbol, comfort), nil
+}
+
+// Comfort tells how humid the air feels with the dew point dp in °C.
+func Comfort(dp float64) string {
+	switch {
+	case dp < 10:
+		return "dry"
+	case dp < 16:
+		return "comfortable"
+	case dp < 21:
+		return "humid"
+	case dp < 24:
+		return "muggy"
+	default:
+		return "oppressive"
+	}
+}
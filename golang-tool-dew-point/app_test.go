@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDewPoint(t *testing.T) {
+	// at 100% humidity the dew point is the air temperature, and at 25°C and
+	// 60% the usual textbook value is 16.7°C
+	tests := []struct {
+		t, rh, want float64
+	}{
+		{25, 60, 16.69},
+		{20, 50, 9.26},
+		{30, 80, 26.17},
+		{10, 100, 10},
+		{0, 100, 0},
+		{-10, 70, -14.44},
+		{40, 20, 12.78},
+	}
+	for _, tt := range tests {
+		if got := DewPoint(tt.t, tt.rh); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("DewPoint(%v, %v) = %.3f, want %v", tt.t, tt.rh, got, tt.want)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		p    Parameter
+		want string
+	}{
+		{Parameter{Temperature: 25, Humidity: 60}, "The dew point at 25.0°C and 60% humidity is 16.7°C: humid"},
+		{Parameter{Temperature: 77, Humidity: 60, Units: "imperial"}, "The dew point at 77.0°F and 60% humidity is 62.0°F: humid"},
+		{Parameter{Temperature: 30, Humidity: 80, Units: "Metric"}, "The dew point at 30.0°C and 80% humidity is 26.2°C: oppressive"},
+		{Parameter{Temperature: 20, Humidity: 30.5}, "The dew point at 20.0°C and 30.5% humidity is 2.1°C: dry"},
+	}
+	for _, tt := range tests {
+		got, err := Calculate(tt.p)
+		if err != nil {
+			t.Fatalf("Calculate(%+v) error = %v", tt.p, err)
+		}
+		if got != tt.want {
+			t.Errorf("Calculate(%+v) = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateInvalid(t *testing.T) {
+	tests := []struct {
+		p    Parameter
+		want string
+	}{
+		{Parameter{Temperature: 20, Humidity: -1}, "not between 0 and 100"},
+		{Parameter{Temperature: 20, Humidity: 100.5}, "not between 0 and 100"},
+		{Parameter{Temperature: 20, Humidity: math.NaN()}, "not between 0 and 100"},
+		{Parameter{Temperature: 20, Humidity: 0}, "no dew point"},
+		{Parameter{Temperature: 70, Humidity: 50}, "out of the range"},
+		{Parameter{Temperature: -60, Humidity: 50, Units: "imperial"}, "out of the range"},
+		{Parameter{Temperature: 20, Humidity: 50, Units: "kelvin"}, "unknown units"},
+	}
+	for _, tt := range tests {
+		if _, err := Calculate(tt.p); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Calculate(%+v) error = %v, want %q", tt.p, err, tt.want)
+		}
+	}
+}
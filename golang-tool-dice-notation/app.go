@@ -0,0 +1,326 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Roll dice written in the standard tabletop RPG notation, e.g. "3d6+2", "d20", "2d20kh1" to keep the highest of two d20 (advantage), "2d20kl1" to keep the lowest (disadvantage) or "4d6dl1" to drop the lowest. Always use this function instead of making up a roll yourself. The function returns every die rolled and the total.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Notation string `json:"notation" jsonschema:"description=The dice to roll in dice notation,example=3d6+2"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "dice-notation", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xEC}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "notation", msg.Notation)
+
+	terms, err := Parse(msg.Notation)
+	if err != nil {
+		slog.Warn("[sfn] Parse error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not roll %q: %v", msg.Notation, err))
+		return
+	}
+	result, err := Roll(rand.Reader, terms)
+	if err != nil {
+		slog.Warn("[sfn] Roll error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not roll %q: %v", msg.Notation, err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result.String())
+}
+
+const (
+	maxTerms    = 20
+	maxDice     = 100
+	maxSides    = 1000
+	maxConstant = 1000000
+)
+
+// Selection keeps or drops the highest or lowest dice of a term.
+type Selection int
+
+const (
+	KeepAll Selection = iota
+	KeepHighest
+	KeepLowest
+	DropHighest
+	DropLowest
+)
+
+var selections = map[string]Selection{
+	"k":  KeepHighest,
+	"kh": KeepHighest,
+	"kl": KeepLowest,
+	"dh": DropHighest,
+	"d":  DropLowest,
+	"dl": DropLowest,
+}
+
+// Term is a group of dice or a constant of the notation, e.g. "-2" or
+// "4d6dl1".
+type Term struct {
+	// Sign is 1 or -1.
+	Sign int
+	// Count and Sides are zero for a constant.
+	Count, Sides int
+	Selection    Selection
+	// N is the number of dice kept or dropped.
+	N        int
+	Constant int
+	// Text is the term as written, normalized, e.g. "4d6dl1".
+	Text string
+}
+
+var (
+	termPattern = regexp.MustCompile(`[+-]?[^+-]+`)
+	dicePattern = regexp.MustCompile(`^(\d*)d(\d+|%)(?:(kh|kl|k|dh|dl|d)(\d+))?$`)
+)
+
+// Parse parses dice notation: terms added or subtracted, each dice written
+// NdS, where N defaults to 1 and "d%" is a d100, optionally followed by
+// khN or kN to keep the N highest dice, klN to keep the N lowest, dhN to
+// drop the N highest or dlN or dN to drop the N lowest, or a constant.
+// Spaces and case are ignored, e.g. "2D20 KH1 + 5".
+func Parse(notation string) ([]Term, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(notation), ""))
+	if s == "" {
+		return nil, errors.New("the notation is empty")
+	}
+	matches := termPattern.FindAllString(s, -1)
+	if strings.Join(matches, "") != s {
+		return nil, fmt.Errorf("%q is not dice notation, e.g. 3d6+2", notation)
+	}
+	if len(matches) > maxTerms {
+		return nil, fmt.Errorf("at most %d terms can be rolled at once", maxTerms)
+	}
+
+	var terms []Term
+	var dice int
+	for _, m := range matches {
+		t, err := parseTerm(m)
+		if err != nil {
+			return nil, err
+		}
+		dice += t.Count
+		if dice > maxDice {
+			return nil, fmt.Errorf("at most %d dice can be rolled at once", maxDice)
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
+
+func parseTerm(s string) (Term, error) {
+	t := Term{Sign: 1}
+	body := s
+	switch s[0] {
+	case '-':
+		t.Sign = -1
+		body = s[1:]
+	case '+':
+		body = s[1:]
+	}
+	t.Text = body
+
+	if n, err := strconv.Atoi(body); err == nil {
+		if n > maxConstant {
+			return Term{}, fmt.Errorf("the constant %d is larger than %d", n, maxConstant)
+		}
+		t.Constant = n
+		return t, nil
+	}
+
+	m := dicePattern.FindStringSubmatch(body)
+	if m == nil {
+		return Term{}, fmt.Errorf("%q is not dice notation, e.g. 3d6 or 2d20kh1", body)
+	}
+	t.Count = 1
+	if m[1] != "" {
+		t.Count, _ = strconv.Atoi(m[1])
+	}
+	if m[2] == "%" {
+		t.Sides = 100
+	} else {
+		t.Sides, _ = strconv.Atoi(m[2])
+	}
+	if t.Count < 1 || t.Count > maxDice {
+		return Term{}, fmt.Errorf("%q rolls %d dice, it must be 1 to %d", body, t.Count, maxDice)
+	}
+	if t.Sides < 2 || t.Sides > maxSides {
+		return Term{}, fmt.Errorf("%q has %d sides, a die must have 2 to %d", body, t.Sides, maxSides)
+	}
+	if m[3] != "" {
+		t.Selection = selections[m[3]]
+		t.N, _ = strconv.Atoi(m[4])
+		switch t.Selection {
+		case KeepHighest, KeepLowest:
+			if t.N < 1 || t.N > t.Count {
+				return Term{}, fmt.Errorf("%q keeps %d of %d dice", body, t.N, t.Count)
+			}
+		default:
+			if t.N >= t.Count {
+				return Term{}, fmt.Errorf("%q drops %d of %d dice, none would be left", body, t.N, t.Count)
+			}
+		}
+	}
+	return t, nil
+}
+
+// TermResult is a rolled term.
+type TermResult struct {
+	Term
+	// Rolls are the dice in the order rolled, Kept tells which of them count.
+	Rolls []int
+	Kept  []bool
+	// Value is the signed sum of the kept dice, or the constant.
+	Value int
+}
+
+// Result is a rolled notation.
+type Result struct {
+	Terms []TermResult
+	Total int
+}
+
+// String returns the roll, e.g. "3d6+2 = 13: 3d6 rolled 4, 1, 6 + 2", with
+// the dropped dice in parentheses, e.g. "2d20kh1 = 17: 2d20kh1 rolled 17, (4)".
+func (r Result) String() string {
+	var notation, detail strings.Builder
+	for i, t := range r.Terms {
+		sign := "+"
+		if t.Sign < 0 {
+			sign = "-"
+		}
+		if i > 0 || t.Sign < 0 {
+			notation.WriteString(sign)
+		}
+		notation.WriteString(t.Text)
+
+		if i > 0 {
+			detail.WriteString(" " + sign + " ")
+		} else if t.Sign < 0 {
+			detail.WriteString("-")
+		}
+		if t.Count == 0 {
+			detail.WriteString(strconv.Itoa(t.Constant))
+			continue
+		}
+		rolls := make([]string, len(t.Rolls))
+		for j, roll := range t.Rolls {
+			rolls[j] = strconv.Itoa(roll)
+			if !t.Kept[j] {
+				rolls[j] = "(" + rolls[j] + ")"
+			}
+		}
+		detail.WriteString(t.Text + " rolled " + strings.Join(rolls, ", "))
+	}
+	return fmt.Sprintf("%s = %d: %s", notation.String(), r.Total, detail.String())
+}
+
+// Roll rolls the terms with the random bytes of random, crypto/rand.Reader
+// outside of the tests.
+func Roll(random io.Reader, terms []Term) (Result, error) {
+	var r Result
+	for _, t := range terms {
+		tr := TermResult{Term: t}
+		if t.Count == 0 {
+			tr.Value = t.Sign * t.Constant
+		} else {
+			for i := 0; i < t.Count; i++ {
+				n, err := rand.Int(random, big.NewInt(int64(t.Sides)))
+				if err != nil {
+					return Result{}, fmt.Errorf("read random bytes: %w", err)
+				}
+				tr.Rolls = append(tr.Rolls, int(n.Int64())+1)
+			}
+			tr.Kept = keep(tr.Rolls, t.Selection, t.N)
+			for i, roll := range tr.Rolls {
+				if tr.Kept[i] {
+					tr.Value += t.Sign * roll
+				}
+			}
+		}
+		r.Terms = append(r.Terms, tr)
+		r.Total += tr.Value
+	}
+	return r, nil
+}
+
+// keep tells which of the rolls count with the selection of n dice. Which of
+// equal rolls is kept does not change the total.
+func keep(rolls []int, selection Selection, n int) []bool {
+	kept := make([]bool, len(rolls))
+	// the indices of the rolls from the highest to the lowest
+	order := make([]int, len(rolls))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return rolls[order[a]] > rolls[order[b]] })
+
+	var from, to int
+	switch selection {
+	case KeepHighest:
+		from, to = 0, n
+	case KeepLowest:
+		from, to = len(rolls)-n, len(rolls)
+	case DropHighest:
+		from, to = n, len(rolls)
+	case DropLowest:
+		from, to = 0, len(rolls)-n
+	default:
+		from, to = 0, len(rolls)
+	}
+	for _, i := range order[from:to] {
+		kept[i] = true
+	}
+	return kept
+}
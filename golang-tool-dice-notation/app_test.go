@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     []Term
+	}{
+		{"3d6+2", []Term{
+			{Sign: 1, Count: 3, Sides: 6, Text: "3d6"},
+			{Sign: 1, Constant: 2, Text: "2"},
+		}},
+		{"d20", []Term{{Sign: 1, Count: 1, Sides: 20, Text: "d20"}}},
+		{"2D20 KH1", []Term{{Sign: 1, Count: 2, Sides: 20, Selection: KeepHighest, N: 1, Text: "2d20kh1"}}},
+		{"2d20kl1", []Term{{Sign: 1, Count: 2, Sides: 20, Selection: KeepLowest, N: 1, Text: "2d20kl1"}}},
+		{"4d6k3", []Term{{Sign: 1, Count: 4, Sides: 6, Selection: KeepHighest, N: 3, Text: "4d6k3"}}},
+		{"4d6d1", []Term{{Sign: 1, Count: 4, Sides: 6, Selection: DropLowest, N: 1, Text: "4d6d1"}}},
+		{"4d6dh1", []Term{{Sign: 1, Count: 4, Sides: 6, Selection: DropHighest, N: 1, Text: "4d6dh1"}}},
+		{"d%", []Term{{Sign: 1, Count: 1, Sides: 100, Text: "d%"}}},
+		{"-1d4 + 1d8 - 3", []Term{
+			{Sign: -1, Count: 1, Sides: 4, Text: "1d4"},
+			{Sign: 1, Count: 1, Sides: 8, Text: "1d8"},
+			{Sign: -1, Constant: 3, Text: "3"},
+		}},
+		{"7", []Term{{Sign: 1, Constant: 7, Text: "7"}}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.notation)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.notation, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.notation, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, notation := range []string{
+		"",
+		"   ",
+		"3d",
+		"d",
+		"3x6",
+		"3d6+",
+		"3d6++2",
+		"3d6+-2",
+		"2d20kh",
+		"2d20kx1",
+		"abc",
+		"1.5d6",
+		// bounds
+		"0d6",
+		"101d6",
+		"3d1",
+		"3d1001",
+		"2d20kh3",
+		"2d20kh0",
+		"4d6dl4",
+		"60d6+50d6",
+		"1d6+1000001",
+		strings.Repeat("1+", 20) + "1",
+	} {
+		if terms, err := Parse(notation); err == nil {
+			t.Errorf("Parse(%q) = %+v, should fail", notation, terms)
+		}
+	}
+
+	if _, err := Parse("100d6+" + strings.Repeat("1+", 18) + "1"); err != nil {
+		t.Errorf("Parse() at the limits error = %v", err)
+	}
+}
+
+// dieBytes are the bytes crypto/rand.Int turns into the rolls of a d6 or a
+// d20: it masks a byte to the bits of the sides and rejects the values from
+// the sides up.
+func dieBytes(rolls ...int) *bytes.Reader {
+	b := make([]byte, len(rolls))
+	for i, r := range rolls {
+		b[i] = byte(r - 1)
+	}
+	return bytes.NewReader(b)
+}
+
+func TestRoll(t *testing.T) {
+	tests := []struct {
+		notation string
+		random   *bytes.Reader
+		want     string
+	}{
+		{"3d6+2", dieBytes(4, 1, 6), "3d6+2 = 13: 3d6 rolled 4, 1, 6 + 2"},
+		{"2d20kh1", dieBytes(4, 17), "2d20kh1 = 17: 2d20kh1 rolled (4), 17"},
+		{"2d20kl1", dieBytes(4, 17), "2d20kl1 = 4: 2d20kl1 rolled 4, (17)"},
+		{"4d6dl1", dieBytes(3, 5, 3, 6), "4d6dl1 = 14: 4d6dl1 rolled 3, 5, (3), 6"},
+		{"4d6dh2", dieBytes(3, 5, 2, 6), "4d6dh2 = 5: 4d6dh2 rolled 3, (5), 2, (6)"},
+		{"-1d6+1d6-1", dieBytes(2, 5), "-1d6+1d6-1 = 2: -1d6 rolled 2 + 1d6 rolled 5 - 1"},
+		// a masked 7 is out of a d6 and drawn again
+		{"1d6", bytes.NewReader([]byte{6, 7, 2}), "1d6 = 3: 1d6 rolled 3"},
+	}
+	for _, tt := range tests {
+		terms, err := Parse(tt.notation)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.notation, err)
+		}
+		got, err := Roll(tt.random, terms)
+		if err != nil {
+			t.Fatalf("Roll(%q) error = %v", tt.notation, err)
+		}
+		if got.String() != tt.want {
+			t.Errorf("Roll(%q) = %s, want %s", tt.notation, got, tt.want)
+		}
+	}
+
+	terms, _ := Parse("3d6")
+	if _, err := Roll(bytes.NewReader(nil), terms); err == nil {
+		t.Error("Roll() without random bytes should fail")
+	}
+}
+
+func TestRollBounds(t *testing.T) {
+	// a seeded source makes the rolls reproducible
+	random := rand.New(rand.NewSource(42))
+	terms, _ := Parse("100d20")
+	seen := map[int]bool{}
+	for i := 0; i < 20; i++ {
+		r, err := Roll(random, terms)
+		if err != nil {
+			t.Fatalf("Roll() error = %v", err)
+		}
+		for _, roll := range r.Terms[0].Rolls {
+			if roll < 1 || roll > 20 {
+				t.Fatalf("a d20 rolled %d", roll)
+			}
+			seen[roll] = true
+		}
+		if r.Total < 100 || r.Total > 2000 {
+			t.Errorf("100d20 totals %d", r.Total)
+		}
+	}
+	if len(seen) != 20 {
+		t.Errorf("2000 d20 rolled only %d distinct values", len(seen))
+	}
+
+	a, _ := Roll(rand.New(rand.NewSource(7)), terms)
+	b, _ := Roll(rand.New(rand.NewSource(7)), terms)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the rolls of the same seed differ")
+	}
+}
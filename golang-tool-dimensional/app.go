@@ -0,0 +1,589 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Evaluate an arithmetic expression of physical quantities with units and return the result with simplified units, e.g. "60 mph * 2 h" is 120 mi, "100 km / 2 h" is 50 km/h and "5 kW * 3 h in kWh" is 15 kWh. Supported operators are + - * / ^ and parentheses, "per" means /, and a trailing "in <unit>" or "to <unit>" converts the result. Units include m km cm mm mi yd ft inch nmi, g kg mg t lb oz, s ms min h day week, m/s mph kph knot, L mL gal, N J kJ cal kcal Wh kWh W kW Pa kPa bar Hz kHz MHz GHz, A V C and K. Adding quantities of different dimensions, like a length and a time, is an error.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Expression string `json:"expression" jsonschema:"description=The expression with units to evaluate,example=60 mph * 2 h"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "dimensional", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD5}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "expression", msg.Expression)
+
+	result, err := Evaluate(msg.Expression)
+	if err != nil {
+		slog.Warn("[sfn] Evaluate error", "expression", msg.Expression, "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not evaluate %q: %v", msg.Expression, err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("%s = %s", msg.Expression, result))
+}
+
+// maxExpressionLength bounds the input of the parser.
+const maxExpressionLength = 500
+
+// dim is a dimension as the exponents of the SI base quantities, in the order
+// of baseSymbols.
+type dim [6]int
+
+var baseSymbols = [len(dim{})]string{"m", "kg", "s", "A", "K", "mol"}
+
+func (d dim) String() string {
+	var u units
+	for i, e := range d {
+		if e != 0 {
+			u = append(u, term{baseSymbols[i], e})
+		}
+	}
+	if len(u) == 0 {
+		return "dimensionless"
+	}
+	return u.String()
+}
+
+// unit is a unit of measure, factor converts it to SI base units.
+type unit struct {
+	factor float64
+	dim    dim
+}
+
+var (
+	length      = dim{1, 0, 0, 0, 0, 0}
+	mass        = dim{0, 1, 0, 0, 0, 0}
+	duration    = dim{0, 0, 1, 0, 0, 0}
+	current     = dim{0, 0, 0, 1, 0, 0}
+	temperature = dim{0, 0, 0, 0, 1, 0}
+	amount      = dim{0, 0, 0, 0, 0, 1}
+	volume      = dim{3, 0, 0, 0, 0, 0}
+	force       = dim{1, 1, -2, 0, 0, 0}
+	energy      = dim{2, 1, -2, 0, 0, 0}
+	power       = dim{2, 1, -3, 0, 0, 0}
+	pressure    = dim{-1, 1, -2, 0, 0, 0}
+	frequency   = dim{0, 0, -1, 0, 0, 0}
+	charge      = dim{0, 0, 1, 1, 0, 0}
+	voltage     = dim{2, 1, -3, -1, 0, 0}
+)
+
+// unitTable maps the symbol of every unit to its definition.
+var unitTable = map[string]unit{
+	"m": {1, length}, "km": {1e3, length}, "cm": {1e-2, length}, "mm": {1e-3, length},
+	"µm": {1e-6, length}, "nm": {1e-9, length},
+	"mi": {1609.344, length}, "yd": {0.9144, length}, "ft": {0.3048, length}, "inch": {0.0254, length},
+	"nmi": {1852, length},
+
+	"kg": {1, mass}, "g": {1e-3, mass}, "mg": {1e-6, mass}, "t": {1e3, mass},
+	"lb": {0.45359237, mass}, "oz": {0.028349523125, mass},
+
+	"s": {1, duration}, "ms": {1e-3, duration}, "min": {60, duration}, "h": {3600, duration},
+	"day": {86400, duration}, "week": {604800, duration},
+
+	"A": {1, current}, "mA": {1e-3, current},
+	"K":   {1, temperature},
+	"mol": {1, amount},
+
+	"L": {1e-3, volume}, "mL": {1e-6, volume}, "gal": {3.785411784e-3, volume},
+
+	"N": {1, force}, "kN": {1e3, force},
+	"J": {1, energy}, "kJ": {1e3, energy}, "cal": {4.184, energy}, "kcal": {4184, energy},
+	"Wh": {3600, energy}, "kWh": {3.6e6, energy},
+	"W": {1, power}, "kW": {1e3, power}, "MW": {1e6, power},
+	"Pa": {1, pressure}, "kPa": {1e3, pressure}, "bar": {1e5, pressure},
+	"Hz": {1, frequency}, "kHz": {1e3, frequency}, "MHz": {1e6, frequency}, "GHz": {1e9, frequency},
+	"C": {1, charge},
+	"V": {1, voltage},
+}
+
+// aliases maps other spellings to the symbols of unitTable.
+var aliases = map[string]string{
+	"um": "µm", "meter": "m", "meters": "m", "metre": "m", "metres": "m",
+	"kilometer": "km", "kilometers": "km", "kilometre": "km", "kilometres": "km",
+	"mile": "mi", "miles": "mi", "yard": "yd", "yards": "yd",
+	"foot": "ft", "feet": "ft", "inches": "inch",
+	"gram": "g", "grams": "g", "kilogram": "kg", "kilograms": "kg", "tonne": "t", "tonnes": "t",
+	"lbs": "lb", "pound": "lb", "pounds": "lb", "ounce": "oz", "ounces": "oz",
+	"sec": "s", "second": "s", "seconds": "s", "minute": "min", "minutes": "min",
+	"hr": "h", "hour": "h", "hours": "h", "days": "day", "weeks": "week",
+	"l": "L", "liter": "L", "liters": "L", "litre": "L", "litres": "L", "ml": "mL",
+	"gallon": "gal", "gallons": "gal",
+}
+
+// shorthands are the units written as one word that stand for a compound
+// unit.
+var shorthands = map[string]units{
+	"mph":   {{"mi", 1}, {"h", -1}},
+	"kph":   {{"km", 1}, {"h", -1}},
+	"kmh":   {{"km", 1}, {"h", -1}},
+	"knot":  {{"nmi", 1}, {"h", -1}},
+	"knots": {{"nmi", 1}, {"h", -1}},
+	"kn":    {{"nmi", 1}, {"h", -1}},
+}
+
+// term is a unit raised to an exponent.
+type term struct {
+	symbol string
+	exp    int
+}
+
+// units is a product of terms in the order they appeared. A symbol appears at
+// most once and no exponent is 0.
+type units []term
+
+func (u units) dim() dim {
+	var d dim
+	for _, t := range u {
+		ud := unitTable[t.symbol].dim
+		for i := range d {
+			d[i] += ud[i] * t.exp
+		}
+	}
+	return d
+}
+
+// factor converts a value in u to SI base units.
+func (u units) factor() float64 {
+	f := 1.0
+	for _, t := range u {
+		f *= math.Pow(unitTable[t.symbol].factor, float64(t.exp))
+	}
+	return f
+}
+
+// String is e.g. "km/h", "kg·m/s^2" or "1/s".
+func (u units) String() string {
+	var num, den []string
+	for _, t := range u {
+		e := t.exp
+		list := &num
+		if e < 0 {
+			e, list = -e, &den
+		}
+		if e == 1 {
+			*list = append(*list, t.symbol)
+		} else {
+			*list = append(*list, fmt.Sprintf("%s^%d", t.symbol, e))
+		}
+	}
+
+	s := strings.Join(num, "·")
+	switch {
+	case len(den) == 0:
+		return s
+	case s == "":
+		s = "1"
+	}
+	if len(den) > 1 {
+		return s + "/(" + strings.Join(den, "·") + ")"
+	}
+	return s + "/" + den[0]
+}
+
+// Quantity is a value in units.
+type Quantity struct {
+	Value float64
+	Units units
+}
+
+func (q Quantity) String() string {
+	v := strconv.FormatFloat(q.Value, 'g', 10, 64)
+	if len(q.Units) == 0 {
+		return v
+	}
+	return v + " " + q.Units.String()
+}
+
+// mul returns q * r, or q / r with sign -1.
+func (q Quantity) mul(r Quantity, sign int) Quantity {
+	value := q.Value * r.Value
+	if sign < 0 {
+		value = q.Value / r.Value
+	}
+	u := append(units{}, q.Units...)
+	for _, t := range r.Units {
+		u = append(u, term{t.symbol, t.exp * sign})
+	}
+	return simplify(Quantity{Value: value, Units: u})
+}
+
+// simplify merges the terms of the same symbol, then the units of the same
+// dimension, e.g. km/m into a number and mi/h·min into mi. A dimensionless
+// result drops its units, e.g. J/(N·m) is 1.
+func simplify(q Quantity) Quantity {
+	var u units
+	for _, t := range q.Units {
+		merged := false
+		for i := range u {
+			if u[i].symbol == t.symbol {
+				u[i].exp += t.exp
+				merged = true
+				break
+			}
+			if unitTable[u[i].symbol].dim == unitTable[t.symbol].dim {
+				// convert t into the unit that appeared first
+				q.Value *= math.Pow(unitTable[t.symbol].factor/unitTable[u[i].symbol].factor, float64(t.exp))
+				u[i].exp += t.exp
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			u = append(u, t)
+		}
+	}
+
+	kept := u[:0]
+	for _, t := range u {
+		if t.exp != 0 {
+			kept = append(kept, t)
+		}
+	}
+	q.Units = kept
+	if q.Units.dim() == (dim{}) && len(q.Units) > 0 {
+		q.Value *= q.Units.factor()
+		q.Units = nil
+	}
+	return q
+}
+
+// add returns q + r, or q - r with sign -1, in the units of q.
+func (q Quantity) add(r Quantity, sign float64) (Quantity, error) {
+	if q.Units.dim() != r.Units.dim() {
+		return Quantity{}, fmt.Errorf("can not add or subtract %s and %s, their dimensions %s and %s differ", q, r, q.Units.dim(), r.Units.dim())
+	}
+	converted := r.Value * r.Units.factor() / q.Units.factor()
+	return Quantity{Value: q.Value + sign*converted, Units: q.Units}, nil
+}
+
+// pow returns q^n.
+func (q Quantity) pow(n int) Quantity {
+	u := make(units, len(q.Units))
+	for i, t := range q.Units {
+		u[i] = term{t.symbol, t.exp * n}
+	}
+	return simplify(Quantity{Value: math.Pow(q.Value, float64(n)), Units: u})
+}
+
+// convert expresses q in the units of target, whose value is a multiple,
+// e.g. "in 100 km".
+func (q Quantity) convert(target Quantity) (Quantity, error) {
+	if q.Units.dim() != target.Units.dim() {
+		return Quantity{}, fmt.Errorf("can not convert %s to %s, their dimensions %s and %s differ", q, target.Units, q.Units.dim(), target.Units.dim())
+	}
+	return Quantity{Value: q.Value * q.Units.factor() / (target.Value * target.Units.factor()), Units: target.Units}, nil
+}
+
+// Evaluate parses and evaluates expression, converting the result when it
+// ends with "in <unit>" or "to <unit>".
+func Evaluate(expression string) (Quantity, error) {
+	if len(expression) > maxExpressionLength {
+		return Quantity{}, fmt.Errorf("the expression is longer than %d characters", maxExpressionLength)
+	}
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if len(tokens) == 0 {
+		return Quantity{}, errors.New("the expression is empty")
+	}
+
+	p := &parser{tokens: tokens}
+	q, err := p.expr()
+	if err != nil {
+		return Quantity{}, err
+	}
+	if t := p.peek(); t.kind == tokWord && (t.text == "in" || t.text == "to") {
+		p.pos++
+		target, err := p.expr()
+		if err != nil {
+			return Quantity{}, err
+		}
+		if q, err = q.convert(target); err != nil {
+			return Quantity{}, err
+		}
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return Quantity{}, fmt.Errorf("unexpected %q", t.text)
+	}
+	if math.IsNaN(q.Value) || math.IsInf(q.Value, 0) {
+		return Quantity{}, errors.New("the result is not a finite number, is there a division by zero?")
+	}
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokWord
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	r := []rune(s)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(r) && (unicode.IsDigit(r[j]) || r[j] == '.' || r[j] == '_') {
+				j++
+			}
+			// an exponent like 1e3 or 2.5E-4, but not the e of a unit
+			if j < len(r) && (r[j] == 'e' || r[j] == 'E') {
+				k := j + 1
+				if k < len(r) && (r[k] == '+' || r[k] == '-') {
+					k++
+				}
+				if k < len(r) && unicode.IsDigit(r[k]) {
+					for k < len(r) && unicode.IsDigit(r[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+			text := string(r[i:j])
+			num, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", text)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, num: num})
+			i = j
+		case unicode.IsLetter(c) || c == 'µ':
+			j := i
+			for j < len(r) && (unicode.IsLetter(r[j]) || r[j] == 'µ') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(r[i:j])})
+			i = j
+		case strings.ContainsRune("+-*/^()×·", c):
+			op := string(c)
+			switch c {
+			case '×', '·':
+				op = "*"
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = product { ("*" | "/" | "per") product }
+//	product = factor { factor }
+//	factor  = unary [ "^" ["-"] number ]
+//	unary   = "-" unary | number | unit | "(" expr ")"
+//
+// where factors written next to each other, as in "60 mph", bind tighter than
+// * and /, so "100 km / 2 h" is 50 km/h.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return token{kind: tokEOF, text: "end of expression"}
+}
+
+func (p *parser) expr() (Quantity, error) {
+	q, err := p.term()
+	if err != nil {
+		return Quantity{}, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokOp || (t.text != "+" && t.text != "-") {
+			return q, nil
+		}
+		p.pos++
+		r, err := p.term()
+		if err != nil {
+			return Quantity{}, err
+		}
+		sign := 1.0
+		if t.text == "-" {
+			sign = -1
+		}
+		if q, err = q.add(r, sign); err != nil {
+			return Quantity{}, err
+		}
+	}
+}
+
+func (p *parser) term() (Quantity, error) {
+	q, err := p.product()
+	if err != nil {
+		return Quantity{}, err
+	}
+	for {
+		t := p.peek()
+		sign := 1
+		switch {
+		case t.kind == tokOp && t.text == "*":
+		case t.kind == tokOp && t.text == "/", t.kind == tokWord && t.text == "per":
+			sign = -1
+		default:
+			return q, nil
+		}
+		p.pos++
+		r, err := p.product()
+		if err != nil {
+			return Quantity{}, err
+		}
+		q = q.mul(r, sign)
+	}
+}
+
+func (p *parser) product() (Quantity, error) {
+	q, err := p.factor()
+	if err != nil {
+		return Quantity{}, err
+	}
+	for {
+		switch t := p.peek(); {
+		case t.kind == tokNumber, t.kind == tokOp && t.text == "(",
+			t.kind == tokWord && t.text != "in" && t.text != "to" && t.text != "per":
+		default:
+			return q, nil
+		}
+		r, err := p.factor()
+		if err != nil {
+			return Quantity{}, err
+		}
+		q = q.mul(r, 1)
+	}
+}
+
+func (p *parser) factor() (Quantity, error) {
+	q, err := p.unary()
+	if err != nil {
+		return Quantity{}, err
+	}
+	if t := p.peek(); t.kind != tokOp || t.text != "^" {
+		return q, nil
+	}
+	p.pos++
+
+	sign := 1
+	if t := p.peek(); t.kind == tokOp && t.text == "-" {
+		p.pos++
+		sign = -1
+	}
+	t := p.peek()
+	if t.kind != tokNumber || t.num != math.Trunc(t.num) || t.num > 10 {
+		return Quantity{}, fmt.Errorf("an exponent must be a whole number up to 10, got %q", t.text)
+	}
+	p.pos++
+	return q.pow(sign * int(t.num)), nil
+}
+
+func (p *parser) unary() (Quantity, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && t.text == "-":
+		p.pos++
+		q, err := p.unary()
+		q.Value = -q.Value
+		return q, err
+	case t.kind == tokNumber:
+		p.pos++
+		return Quantity{Value: t.num}, nil
+	case t.kind == tokWord:
+		p.pos++
+		return lookupUnit(t.text)
+	case t.kind == tokOp && t.text == "(":
+		p.pos++
+		q, err := p.expr()
+		if err != nil {
+			return Quantity{}, err
+		}
+		if t := p.peek(); t.kind != tokOp || t.text != ")" {
+			return Quantity{}, fmt.Errorf("expected ) but got %q", t.text)
+		}
+		p.pos++
+		return q, nil
+	}
+	return Quantity{}, fmt.Errorf("expected a number or a unit but got %q", t.text)
+}
+
+// lookupUnit returns 1 of the unit named word.
+func lookupUnit(word string) (Quantity, error) {
+	if u, ok := shorthands[word]; ok {
+		return Quantity{Value: 1, Units: append(units{}, u...)}, nil
+	}
+	symbol := word
+	if alias, ok := aliases[word]; ok {
+		symbol = alias
+	} else if alias, ok := aliases[strings.ToLower(word)]; ok {
+		symbol = alias
+	}
+	if _, ok := unitTable[symbol]; !ok {
+		return Quantity{}, fmt.Errorf("unknown unit %q", word)
+	}
+	return Quantity{Value: 1, Units: units{{symbol, 1}}}, nil
+}
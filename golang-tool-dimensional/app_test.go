@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		// multiplication
+		{"60 mph * 2 h", "120 mi"},
+		{"60 mph * 30 min", "30 mi"},
+		{"3 m * 4 m", "12 m^2"},
+		{"2 m * 50 cm", "1 m^2"},
+		{"5 kW * 3 h", "15 kW·h"},
+		{"10 N * 2 m in J", "20 J"},
+		{"2 kg * 9.81 m/s^2", "19.62 kg·m/s^2"},
+		// division
+		{"100 km / 2 h", "50 km/h"},
+		{"100 km per 2 h", "50 km/h"},
+		{"1 / (2 s)", "0.5 1/s"},
+		{"10 km / 500 m", "20"},
+		{"100 J / (10 N * 2 m)", "5"},
+		{"1 mi / 1 km", "1.609344"},
+		// addition and conversions
+		{"1 km + 500 m", "1.5 km"},
+		{"2 h - 30 min", "1.5 h"},
+		{"1 ft + 6 inch in cm", "45.72 cm"},
+		{"5 kW * 3 h in kWh", "15 kWh"},
+		{"60 mph to km/h", "96.56064 km/h"},
+		{"1 knot to m/s", "0.5144444444 m/s"},
+		{"1 gal to L", "3.785411784 L"},
+		{"1 kWh to MJ", ""},
+		// numbers and parentheses
+		{"-(2 + 3) * 4", "-20"},
+		{"1.5e3 m to km", "1.5 km"},
+		{"(3 m)^2 * 2 m", "18 m^3"},
+		{"2 m^-1 * 4 m", "8"},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expression)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Evaluate(%q) = %s, want an error", tt.expression, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Evaluate(%q) error = %v", tt.expression, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("Evaluate(%q) = %s, want %s", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateIncoherent(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"3 m + 2 s", "dimensions m and s differ"},
+		{"1 km/h - 1 m", "dimensions m/s and m differ"},
+		{"5 kg + 1", "dimensions kg and dimensionless differ"},
+		{"2 h in km", "can not convert"},
+	}
+	for _, tt := range tests {
+		_, err := Evaluate(tt.expression)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Evaluate(%q) error = %v, want %q", tt.expression, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEvaluateInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"3 furlongs",
+		"(3 m",
+		"3 m)",
+		"2 ^ 1.5",
+		"1 m / 0 s",
+		"3 $",
+		"1..2 m",
+		strings.Repeat("1 + ", 200) + "1",
+	} {
+		if got, err := Evaluate(expression); err == nil {
+			t.Errorf("Evaluate(%q) = %s, want an error", expression, got)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/mail"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Validate an email address and normalize it to lower case, e.g. "is john.doe@example.com a valid email?". The syntax is always checked. Set checkMX to also check in the DNS that the domain accepts mail, which is slower. The function can not tell whether the mailbox itself exists.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Email   string `json:"email" jsonschema:"description=The email address to validate"`
+	CheckMX bool   `json:"checkMX,omitempty" jsonschema:"description=Also check that the domain has MX records to receive mail"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "email-validate", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD3}
+}
+
+// lookupMX resolves the MX records of a domain, tests replace it.
+var lookupMX = net.DefaultResolver.LookupMX
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// an email address is personal data, it is not logged
+	slog.Info("[sfn] << receive", "check_mx", msg.CheckMX)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	verdict, err := Validate(reqCtx, msg.Email, msg.CheckMX)
+	if err != nil {
+		slog.Warn("[sfn] Validate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not validate the email address: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, verdict.String())
+}
+
+// The length limits of RFC 5321.
+const (
+	maxLocalLength   = 64
+	maxAddressLength = 254
+)
+
+// Verdict is the outcome of validating an email address.
+type Verdict struct {
+	Valid bool
+	// Normalized is the address in lower case.
+	Normalized string
+	// Reason tells why the address is not valid.
+	Reason string
+	// MX are the mail servers of the domain, when they were checked.
+	MX []string
+}
+
+func (v *Verdict) String() string {
+	if !v.Valid {
+		return "The email address is not valid: " + v.Reason
+	}
+	s := fmt.Sprintf("The email address is valid, its normalized form is %s", v.Normalized)
+	if len(v.MX) > 0 {
+		s += fmt.Sprintf(". The domain receives mail with %s", strings.Join(v.MX, ", "))
+	}
+	return s
+}
+
+// Validate checks the syntax of email and, with checkMX, that its domain has
+// mail servers. An address that fails a check gets a Verdict that is not
+// valid, the error is for a DNS lookup that could not complete.
+func Validate(ctx context.Context, email string, checkMX bool) (*Verdict, error) {
+	email = strings.TrimSpace(email)
+	if reason := checkSyntax(email); reason != "" {
+		return &Verdict{Reason: reason}, nil
+	}
+
+	v := &Verdict{Valid: true, Normalized: strings.ToLower(email)}
+	if !checkMX {
+		return v, nil
+	}
+
+	domain := v.Normalized[strings.LastIndex(v.Normalized, "@")+1:]
+	records, err := lookupMX(ctx, domain)
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return &Verdict{Normalized: v.Normalized, Reason: fmt.Sprintf("the domain %s has no MX records, it can not receive mail", domain)}, nil
+	case err != nil:
+		return nil, fmt.Errorf("can not look up the mail servers of %s: %w", domain, err)
+	}
+
+	for _, mx := range records {
+		host := strings.TrimSuffix(mx.Host, ".")
+		// a null MX, see RFC 7505
+		if host == "" {
+			return &Verdict{Normalized: v.Normalized, Reason: fmt.Sprintf("the domain %s declares that it does not accept mail", domain)}, nil
+		}
+		v.MX = append(v.MX, host)
+	}
+	if len(v.MX) == 0 {
+		return &Verdict{Normalized: v.Normalized, Reason: fmt.Sprintf("the domain %s has no MX records, it can not receive mail", domain)}, nil
+	}
+	return v, nil
+}
+
+// checkSyntax returns why email is not a valid address, or "" when it is.
+func checkSyntax(email string) string {
+	if email == "" {
+		return "it is empty"
+	}
+	if len(email) > maxAddressLength {
+		return fmt.Sprintf("it is longer than %d characters", maxAddressLength)
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return "it is not an email address"
+	}
+	// ParseAddress also accepts a display name like "Name <address>" and a
+	// comment like "address (Name)", the domain then does not end the input
+	domain := addr.Address[strings.LastIndex(addr.Address, "@")+1:]
+	if addr.Name != "" || strings.ContainsAny(email, "<>") || !strings.HasSuffix(email, "@"+domain) {
+		return "it must be a bare address like name@example.com, without a display name"
+	}
+
+	if local := strings.TrimSuffix(email, "@"+domain); len(local) > maxLocalLength {
+		return fmt.Sprintf("the part before @ is longer than %d characters", maxLocalLength)
+	}
+	// net/mail allows a dotless domain like localhost, but addresses on the
+	// internet need a domain with a top level domain
+	if strings.HasPrefix(domain, "[") || !strings.Contains(domain, ".") {
+		return "the domain must be a name like example.com"
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Sprintf("%s is not a valid domain name", domain)
+		}
+	}
+	return ""
+}
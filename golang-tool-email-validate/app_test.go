@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestValidateSyntax(t *testing.T) {
+	tests := []struct {
+		email      string
+		valid      bool
+		normalized string
+	}{
+		{"john.doe@example.com", true, "john.doe@example.com"},
+		{"  John.Doe@Example.COM ", true, "john.doe@example.com"},
+		{"first+tag@sub.example.co.uk", true, "first+tag@sub.example.co.uk"},
+		{`"quoted name"@example.com`, true, `"quoted name"@example.com`},
+		{"", false, ""},
+		{"plainaddress", false, ""},
+		{"@example.com", false, ""},
+		{"john@", false, ""},
+		{"john@@example.com", false, ""},
+		{"john doe@example.com", false, ""},
+		{"John Doe <john@example.com>", false, ""},
+		{"<john@example.com>", false, ""},
+		{"john@example.com (John)", false, ""},
+		{"john@localhost", false, ""},
+		{"john@example..com", false, ""},
+		{"john@-example.com", false, ""},
+		{"john@[192.168.0.1]", false, ""},
+		{strings.Repeat("a", 65) + "@example.com", false, ""},
+		{"a@" + strings.Repeat("b", 250) + ".com", false, ""},
+	}
+	for _, tt := range tests {
+		got, err := Validate(context.Background(), tt.email, false)
+		if err != nil {
+			t.Errorf("Validate(%q) error = %v", tt.email, err)
+			continue
+		}
+		if got.Valid != tt.valid || got.Normalized != tt.normalized {
+			t.Errorf("Validate(%q) = %+v, want valid %v normalized %q", tt.email, *got, tt.valid, tt.normalized)
+		}
+	}
+}
+
+func TestValidateMX(t *testing.T) {
+	old := lookupMX
+	t.Cleanup(func() { lookupMX = old })
+	var looked []string
+	lookupMX = func(_ context.Context, domain string) ([]*net.MX, error) {
+		looked = append(looked, domain)
+		switch domain {
+		case "example.com":
+			return []*net.MX{{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}}, nil
+		case "nomail.example":
+			return []*net.MX{{Host: ".", Pref: 0}}, nil
+		case "broken.example":
+			return nil, &net.DNSError{Err: "server misbehaving", Name: domain, IsTemporary: true}
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+	}
+
+	got, err := Validate(context.Background(), "Jane@Example.com", true)
+	want := "The email address is valid, its normalized form is jane@example.com. The domain receives mail with mx1.example.com, mx2.example.com"
+	if err != nil || got.String() != want {
+		t.Errorf("Validate() = %v, %v, want %s", got, err, want)
+	}
+
+	for _, email := range []string{"jane@nomail.example", "jane@missing.example"} {
+		if got, err := Validate(context.Background(), email, true); err != nil || got.Valid {
+			t.Errorf("Validate(%q) = %+v, %v, want not valid", email, got, err)
+		}
+	}
+
+	var dnsErr *net.DNSError
+	if _, err := Validate(context.Background(), "jane@broken.example", true); !errors.As(err, &dnsErr) {
+		t.Errorf("Validate() error = %v, want the DNS error", err)
+	}
+
+	// an invalid syntax never reaches the DNS
+	looked = nil
+	if got, _ := Validate(context.Background(), "not an email", true); got.Valid || len(looked) != 0 {
+		t.Errorf("Validate() = %+v after looking up %v, want not valid without a lookup", got, looked)
+	}
+}
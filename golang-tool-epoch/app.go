@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/datetime"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert between Unix epoch timestamps and human readable dates. If "value" is a number it is a Unix timestamp in seconds or milliseconds, detected by its magnitude, and the function returns the date and time. Otherwise "value" is a date like "2024-06-10 06:13:20" or an RFC 3339 time, and the function returns the Unix timestamp in seconds and milliseconds. "tz" is an IANA Time Zone Database identifier used to format the date and to read a date without offset, UTC by default.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value string `json:"value" jsonschema:"description=A Unix timestamp in seconds or milliseconds or a date and time to convert to a timestamp"`
+	TZ    string `json:"tz,omitempty" jsonschema:"description=The IANA Time Zone Database identifier of the date,example=Asia/Tokyo"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "epoch", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB4}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "value", msg.Value, "tz", msg.TZ)
+
+	result, err := Convert(msg.Value, msg.TZ)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %q: %v", msg.Value, err))
+		return
+	}
+
+	sfn.WriteText(ctx, result)
+}
+
+const dateFormat = "2006-01-02 15:04:05 MST (-07:00)"
+
+// millisThreshold is the magnitude from which a timestamp is read as
+// milliseconds. 1e11 seconds is in the year 5138 while 1e11 milliseconds is
+// in 1973, so real world timestamps of both units are on either side of it.
+const millisThreshold = 1e11
+
+// Convert converts a Unix timestamp to a date formatted in tz, or a date in
+// tz to a Unix timestamp.
+func Convert(value, tz string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", errors.New("the value is empty")
+	}
+	loc, err := datetime.LoadLocation(tz)
+	if err != nil {
+		return "", err
+	}
+
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		t, unit, err := FromEpoch(n)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("the Unix timestamp %s in %s is %s in %s", value, unit, t.In(loc).Format(dateFormat), loc), nil
+	}
+
+	t, err := datetime.Parse(value, loc)
+	if err != nil {
+		return "", errors.New(`the value is neither a Unix timestamp nor a date like "2024-06-10 06:13:20" or "2024-06-10T06:13:20Z"`)
+	}
+	return fmt.Sprintf("%s is the Unix timestamp %d in seconds or %d in milliseconds", t.Format(dateFormat), t.Unix(), t.UnixMilli()), nil
+}
+
+// FromEpoch returns the time of a Unix timestamp, and whether it was read as
+// "seconds" or "milliseconds".
+func FromEpoch(n float64) (time.Time, string, error) {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return time.Time{}, "", errors.New("the timestamp is not a number")
+	}
+	if math.Abs(n) >= millisThreshold {
+		if math.Abs(n) >= millisThreshold*1000 {
+			return time.Time{}, "", errors.New("the timestamp is too large, it must be in seconds or milliseconds")
+		}
+		return time.UnixMilli(int64(n)).UTC(), "milliseconds", nil
+	}
+	sec, frac := math.Modf(n)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e3))*1e6).UTC(), "seconds", nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromEpoch(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        float64
+		want     time.Time
+		wantUnit string
+		wantErr  bool
+	}{
+		{"seconds", 1718000000, time.Date(2024, 6, 10, 6, 13, 20, 0, time.UTC), "seconds", false},
+		{"milliseconds", 1718000000123, time.Date(2024, 6, 10, 6, 13, 20, 123e6, time.UTC), "milliseconds", false},
+		{"fractional seconds", 1718000000.5, time.Date(2024, 6, 10, 6, 13, 20, 500e6, time.UTC), "seconds", false},
+		{"epoch", 0, time.Unix(0, 0).UTC(), "seconds", false},
+		{"before 1970", -86400, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), "seconds", false},
+		// 99999999999 seconds is the largest value read as seconds
+		{"largest seconds", 99999999999, time.Unix(99999999999, 0).UTC(), "seconds", false},
+		{"smallest milliseconds", 1e11, time.UnixMilli(1e11).UTC(), "milliseconds", false},
+		{"microseconds", 1718000000123456, time.Time{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unit, err := FromEpoch(tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEpoch(%v) error = %v, wantErr %v", tt.n, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) || unit != tt.wantUnit {
+				t.Errorf("FromEpoch(%v) = %v %s, want %v %s", tt.n, got, unit, tt.want, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		tz      string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "seconds to date in utc",
+			value: "1718000000",
+			want:  "the Unix timestamp 1718000000 in seconds is 2024-06-10 06:13:20 UTC (+00:00) in UTC",
+		},
+		{
+			name:  "milliseconds to date in tokyo",
+			value: "1718000000000",
+			tz:    "Asia/Tokyo",
+			want:  "the Unix timestamp 1718000000000 in milliseconds is 2024-06-10 15:13:20 JST (+09:00) in Asia/Tokyo",
+		},
+		{
+			name:  "date in timezone to epoch",
+			value: "2024-06-10 15:13:20",
+			tz:    "Asia/Tokyo",
+			want:  "2024-06-10 15:13:20 JST (+09:00) is the Unix timestamp 1718000000 in seconds or 1718000000000 in milliseconds",
+		},
+		{
+			name:  "rfc 3339 keeps its offset",
+			value: "2024-06-10T08:13:20+02:00",
+			tz:    "UTC",
+			want:  "2024-06-10 06:13:20 UTC (+00:00) is the Unix timestamp 1718000000 in seconds or 1718000000000 in milliseconds",
+		},
+		{
+			name:  "date only",
+			value: "1970-01-02",
+			want:  "1970-01-02 00:00:00 UTC (+00:00) is the Unix timestamp 86400 in seconds or 86400000 in milliseconds",
+		},
+		{name: "unknown timezone", value: "1718000000", tz: "Mars/Olympus", wantErr: true},
+		{name: "not a date", value: "next tuesday", wantErr: true},
+		{name: "empty", value: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.value, tt.tz)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert(%q, %q) error = %v, wantErr %v", tt.value, tt.tz, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%q, %q) = %s, want %s", tt.value, tt.tz, got, tt.want)
+			}
+		})
+	}
+}
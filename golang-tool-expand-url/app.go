@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Expand a short URL, e.g. a bit.ly or t.co link. The function follows the redirects and returns the final destination URL and the redirect chain.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	URL string `json:"url" jsonschema:"description=The short http or https URL to expand"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "expand-url", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xBB}
+}
+
+// transport only connects to public addresses, at every redirect, and only
+// requests the hosts of TOOL_ALLOWED_HOSTS when it is set.
+var transport = netguard.NewTransport(timeout)
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "url", msg.URL)
+
+	result, err := Expand(transport, msg.URL)
+	if err != nil {
+		slog.Warn("[sfn] Expand error", "url", msg.URL, "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not expand %s: %v", msg.URL, err))
+		return
+	}
+
+	sfn.WriteText(ctx, result.String())
+}
+
+const (
+	maxRedirects = 10
+	timeout      = 10 * time.Second
+)
+
+// Hop is a redirect of the chain.
+type Hop struct {
+	URL        string
+	StatusCode int
+}
+
+// Result is the outcome of expanding a URL.
+type Result struct {
+	// Hops are the redirecting URLs, starting with the short URL.
+	Hops []Hop
+	// Final is the destination URL and StatusCode its status.
+	Final      string
+	StatusCode int
+}
+
+func (r Result) String() string {
+	if len(r.Hops) == 0 {
+		return fmt.Sprintf("%s does not redirect, it responded %d %s", r.Final, r.StatusCode, http.StatusText(r.StatusCode))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s leads to %s (%d %s) after %d redirects:", r.Hops[0].URL, r.Final, r.StatusCode, http.StatusText(r.StatusCode), len(r.Hops))
+	for _, hop := range r.Hops {
+		fmt.Fprintf(&b, "\n%s redirected with %d", hop.URL, hop.StatusCode)
+	}
+	return b.String()
+}
+
+var errTooManyRedirects = fmt.Errorf("stopped after %d redirects, the URL may be a redirect loop", maxRedirects)
+
+// Expand follows the redirects of rawURL with transport, at most
+// maxRedirects of them, and returns the chain.
+func Expand(transport http.RoundTripper, rawURL string) (Result, error) {
+	u, err := netguard.CheckURL(rawURL)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var hops []Hop
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		// CheckRedirect is called before following each redirect, req is the
+		// next request and req.Response the redirect that caused it
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(hops) == maxRedirects {
+				return errTooManyRedirects
+			}
+			hops = append(hops, Hop{URL: via[len(via)-1].URL.String(), StatusCode: req.Response.StatusCode})
+			// the dialer of the transport checks the address of the next
+			// hop, its URL must be http or https too
+			_, err := netguard.CheckURL(req.URL.String())
+			return err
+		},
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		if errors.Is(err, errTooManyRedirects) {
+			return Result{Hops: hops}, errTooManyRedirects
+		}
+		return Result{}, err
+	}
+	// only the destination is needed, not its content
+	io.CopyN(io.Discard, resp.Body, 64*1024)
+	resp.Body.Close()
+
+	return Result{Hops: hops, Final: resp.Request.URL.String(), StatusCode: resp.StatusCode}, nil
+}
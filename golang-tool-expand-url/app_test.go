@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+)
+
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/short", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/tracking?id=1", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/tracking", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/article", http.StatusFound)
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("the article"))
+	})
+	// /loop/n redirects to /loop/n+1 forever, or until n is the stop query
+	// parameter
+	mux.HandleFunc("/loop/", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/loop/"))
+		stop := r.URL.Query().Get("stop")
+		if stop == strconv.Itoa(n) {
+			w.Write([]byte("the end of the chain"))
+			return
+		}
+		next := "/loop/" + strconv.Itoa(n+1)
+		if stop != "" {
+			next += "?stop=" + stop
+		}
+		http.Redirect(w, r, next, http.StatusTemporaryRedirect)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestExpand(t *testing.T) {
+	srv := newRedirectServer(t)
+
+	got, err := Expand(srv.Client().Transport, srv.URL+"/short")
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+
+	want := Result{
+		Hops: []Hop{
+			{URL: srv.URL + "/short", StatusCode: http.StatusMovedPermanently},
+			{URL: srv.URL + "/tracking?id=1", StatusCode: http.StatusFound},
+		},
+		Final:      srv.URL + "/article",
+		StatusCode: http.StatusOK,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expand() = %+v, want %+v", got, want)
+	}
+
+	wantString := srv.URL + "/short leads to " + srv.URL + "/article (200 OK) after 2 redirects:\n" +
+		srv.URL + "/short redirected with 301\n" +
+		srv.URL + "/tracking?id=1 redirected with 302"
+	if got.String() != wantString {
+		t.Errorf("String() = %s, want %s", got.String(), wantString)
+	}
+}
+
+func TestExpandWithoutRedirect(t *testing.T) {
+	srv := newRedirectServer(t)
+
+	got, err := Expand(srv.Client().Transport, srv.URL+"/article")
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if len(got.Hops) != 0 || got.Final != srv.URL+"/article" {
+		t.Errorf("Expand() = %+v, want no hops", got)
+	}
+}
+
+func TestExpandLoop(t *testing.T) {
+	srv := newRedirectServer(t)
+
+	got, err := Expand(srv.Client().Transport, srv.URL+"/loop/0")
+	if !errors.Is(err, errTooManyRedirects) {
+		t.Fatalf("Expand() error = %v, want %v", err, errTooManyRedirects)
+	}
+	if len(got.Hops) != maxRedirects {
+		t.Errorf("Expand() recorded %d hops, want %d", len(got.Hops), maxRedirects)
+	}
+}
+
+func TestExpandMaxRedirects(t *testing.T) {
+	srv := newRedirectServer(t)
+
+	// /loop/0 goes through the 10 redirects of maxRedirects to /loop/10, which
+	// the handler answers itself
+	got, err := Expand(srv.Client().Transport, srv.URL+"/loop/0?stop=10")
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if len(got.Hops) != maxRedirects || got.Final != srv.URL+"/loop/10?stop=10" || got.StatusCode != http.StatusOK {
+		t.Errorf("Expand() = %+v, want %d hops to /loop/10", got, maxRedirects)
+	}
+}
+
+func TestExpandInvalidURL(t *testing.T) {
+	for _, u := range []string{"ftp://example.com", "bit.ly/3abcDEF", ""} {
+		if _, err := Expand(http.DefaultTransport, u); err == nil {
+			t.Errorf("Expand(%q) should fail", u)
+		}
+	}
+}
+
+// shortener answers the public short link https://sho.rt/x with a redirect
+// to location, and passes the other requests to next.
+type shortener struct {
+	location string
+	next     http.RoundTripper
+}
+
+func (s shortener) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != "sho.rt" {
+		return s.next.RoundTrip(req)
+	}
+	rec := httptest.NewRecorder()
+	http.Redirect(rec, req, s.location, http.StatusFound)
+	return rec.Result(), nil
+}
+
+func TestExpandBlocksInternalRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the transport of the Handler followed a redirect to a loopback server")
+	}))
+	defer srv.Close()
+
+	for _, location := range []string{
+		srv.URL + "/admin",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+	} {
+		_, err := Expand(shortener{location: location, next: transport}, "https://sho.rt/x")
+		if !errors.Is(err, netguard.ErrBlockedAddress) {
+			t.Errorf("Expand() redirected to %s error = %v, want %v", location, err, netguard.ErrBlockedAddress)
+		}
+	}
+
+	// a short link to the loopback server itself is refused too
+	if _, err := Expand(transport, srv.URL); !errors.Is(err, netguard.ErrBlockedAddress) {
+		t.Errorf("Expand() of a loopback URL error = %v, want %v", err, netguard.ErrBlockedAddress)
+	}
+}
+
+func TestExpandRedirectScheme(t *testing.T) {
+	_, err := Expand(shortener{location: "file:///etc/passwd", next: transport}, "https://sho.rt/x")
+	if err == nil || !strings.Contains(err.Error(), "only http and https") {
+		t.Errorf("Expand() redirected to a file URL error = %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Extract the named entities of a text: the people, places and organizations it mentions, grouped by type. Pass the text unchanged, the capitalization matters.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Text string `json:"text" jsonschema:"description=The text to extract the named entities from"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "extract-entities", Description: Description(), InputSchema: InputSchema(), Env: []string{"GOOGLE_NLP_API_KEY"}, Upstream: api.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xBE}
+}
+
+var api = &NLPClient{
+	APIKey:     os.Getenv("GOOGLE_NLP_API_KEY"),
+	BaseURL:    "https://language.googleapis.com",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "text_bytes", len(msg.Text))
+
+	entities, err := Extract(api, msg.Text)
+	if err != nil {
+		slog.Warn("[sfn] Extract error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not extract entities: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, entities.String())
+}
+
+// Entity types.
+const (
+	Person       = "people"
+	Place        = "places"
+	Organization = "organizations"
+	Other        = "other"
+)
+
+var typeOrder = []string{Person, Place, Organization, Other}
+
+// Entities are the entity names grouped by type, in order of appearance.
+type Entities map[string][]string
+
+func (e Entities) add(typ, name string) {
+	for _, n := range e[typ] {
+		if n == name {
+			return
+		}
+	}
+	e[typ] = append(e[typ], name)
+}
+
+func (e Entities) String() string {
+	var parts []string
+	for _, typ := range typeOrder {
+		if names := e[typ]; len(names) > 0 {
+			parts = append(parts, typ+": "+strings.Join(names, ", "))
+		}
+	}
+	if len(parts) == 0 {
+		return "no named entities found in the text"
+	}
+	return strings.Join(parts, "\n")
+}
+
+const (
+	minTextRunes = 3
+	maxTextRunes = 20000
+)
+
+// Extract returns the entities of text, detected by the NLP API if it has
+// an API key, else or when the API fails by the local heuristic.
+func Extract(api *NLPClient, text string) (Entities, error) {
+	text = strings.TrimSpace(text)
+	if n := utf8.RuneCountInString(text); n < minTextRunes {
+		return nil, errors.New("the text is too short to contain named entities")
+	} else if n > maxTextRunes {
+		return nil, fmt.Errorf("the text has %d characters, the limit is %d", n, maxTextRunes)
+	}
+
+	if api != nil && api.APIKey != "" {
+		entities, err := api.AnalyzeEntities(text)
+		if err == nil {
+			return entities, nil
+		}
+		slog.Warn("[sfn] NLP API failed, falling back to the heuristic", "err", err)
+	}
+	return Heuristic(text), nil
+}
+
+// NLPClient requests the Google Cloud Natural Language API.
+type NLPClient struct {
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// nlpTypes maps the entity types of the API to ours, the other types like
+// NUMBER or DATE are not named entities.
+var nlpTypes = map[string]string{
+	"PERSON":        Person,
+	"LOCATION":      Place,
+	"ADDRESS":       Place,
+	"ORGANIZATION":  Organization,
+	"EVENT":         Other,
+	"WORK_OF_ART":   Other,
+	"CONSUMER_GOOD": Other,
+}
+
+// AnalyzeEntities detects the entities of text with the analyzeEntities
+// method of the API.
+func (c *NLPClient) AnalyzeEntities(text string) (Entities, error) {
+	payload, _ := json.Marshal(map[string]any{
+		"document":     map[string]string{"type": "PLAIN_TEXT", "content": text},
+		"encodingType": "UTF8",
+	})
+	resp, err := c.HTTPClient.Post(c.BaseURL+"/v1/documents:analyzeEntities?key="+url.QueryEscape(c.APIKey), "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("natural language API responded %d: %s", resp.StatusCode, body)
+	}
+
+	var r struct {
+		Entities []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"entities"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode natural language API response: %w", err)
+	}
+
+	entities := Entities{}
+	for _, e := range r.Entities {
+		// common nouns like "the city" have no capital letter
+		if typ, ok := nlpTypes[e.Type]; ok && hasUpper(e.Name) {
+			entities.add(typ, e.Name)
+		}
+	}
+	return entities, nil
+}
+
+func hasUpper(s string) bool {
+	return strings.IndexFunc(s, unicode.IsUpper) >= 0
+}
+
+// The heuristic: a named entity is a run of capitalized words, possibly
+// joined by a connector like "of", and its type is guessed from the words
+// of the phrase and the word before it.
+var (
+	// connectors may join the capitalized words of a phrase
+	connectors = map[string]bool{"of": true, "de": true, "van": true, "von": true, "da": true, "del": true, "la": true, "&": true}
+	// personTitles before a phrase make it a person
+	personTitles = map[string]bool{"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sir": true, "dame": true, "president": true, "senator": true, "ceo": true}
+	// placeWords before a phrase make it a place
+	placeWords = map[string]bool{"in": true, "at": true, "from": true, "to": true, "near": true, "across": true, "past": true, "through": true, "into": true, "visited": true}
+	// orgWords in a phrase make it an organization
+	orgWords = map[string]bool{"inc": true, "corp": true, "corporation": true, "ltd": true, "llc": true, "gmbh": true, "co": true, "company": true, "university": true, "bank": true, "group": true, "foundation": true, "institute": true, "association": true, "agency": true, "party": true, "ministry": true, "committee": true, "council": true, "labs": true}
+	// placeSuffixes in a phrase make it a place
+	placeSuffixes = map[string]bool{"city": true, "river": true, "lake": true, "mountain": true, "mount": true, "street": true, "avenue": true, "county": true, "province": true, "island": true, "islands": true, "republic": true, "kingdom": true, "valley": true, "bay": true}
+	// stopwords are capitalized at the start of a sentence but are not
+	// entities
+	stopwords = map[string]bool{
+		"the": true, "a": true, "an": true, "this": true, "that": true, "these": true,
+		"those": true, "i": true, "we": true, "he": true, "she": true, "it": true,
+		"they": true, "you": true, "my": true, "our": true, "his": true, "her": true,
+		"their": true, "in": true, "on": true, "at": true, "after": true,
+		"before": true, "when": true, "while": true, "but": true, "and": true,
+		"or": true, "if": true, "as": true, "by": true, "for": true, "with": true,
+		"from": true, "to": true, "so": true, "then": true, "later": true, "now": true,
+		"there": true, "here": true, "however": true, "meanwhile": true, "also": true,
+		"first": true, "finally": true, "during": true, "since": true,
+		"although": true, "because": true, "what": true, "who": true, "which": true,
+		"how": true, "why": true, "where": true, "some": true, "many": true,
+		"all": true, "every": true, "each": true, "no": true, "not": true,
+		"yesterday": true, "today": true, "tomorrow": true, "monday": true,
+		"tuesday": true, "wednesday": true, "thursday": true, "friday": true,
+		"saturday": true, "sunday": true,
+	}
+)
+
+type token struct {
+	word string
+	// sentenceStart is set for the first word of a sentence
+	sentenceStart bool
+}
+
+func tokenize(text string) []token {
+	var tokens []token
+	start := true
+	for _, f := range strings.Fields(text) {
+		word := strings.TrimFunc(f, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&' })
+		if word != "" {
+			tokens = append(tokens, token{word: word, sentenceStart: start})
+		}
+		// an abbreviation like "Dr." does not end the sentence
+		trimmed := strings.TrimRight(f, `"')”’`)
+		start = strings.HasSuffix(trimmed, "!") || strings.HasSuffix(trimmed, "?") ||
+			(strings.HasSuffix(trimmed, ".") && !personTitles[strings.ToLower(word)] && utf8.RuneCountInString(word) > 1)
+	}
+	return tokens
+}
+
+func capitalized(w string) bool {
+	r, _ := utf8.DecodeRuneInString(w)
+	return unicode.IsUpper(r)
+}
+
+// Heuristic extracts the capitalized phrases of text and classifies them.
+func Heuristic(text string) Entities {
+	tokens := tokenize(text)
+	entities := Entities{}
+
+	for i := 0; i < len(tokens); {
+		if !capitalized(tokens[i].word) {
+			i++
+			continue
+		}
+
+		// a person title is part of the context, not of the name
+		if personTitles[strings.ToLower(tokens[i].word)] {
+			i++
+			continue
+		}
+		// skip a capitalized stopword at the start of a sentence
+		if stopwords[strings.ToLower(tokens[i].word)] {
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j < len(tokens) && !tokens[j].sentenceStart {
+			if capitalized(tokens[j].word) {
+				j++
+				continue
+			}
+			if connectors[tokens[j].word] && j+1 < len(tokens) && capitalized(tokens[j+1].word) && !tokens[j+1].sentenceStart {
+				j += 2
+				continue
+			}
+			break
+		}
+
+		words := make([]string, 0, j-i)
+		for _, t := range tokens[i:j] {
+			words = append(words, t.word)
+		}
+		var prev string
+		if i > 0 && !tokens[i].sentenceStart {
+			prev = strings.ToLower(tokens[i-1].word)
+		}
+		if i > 0 && personTitles[strings.ToLower(tokens[i-1].word)] {
+			prev = strings.ToLower(tokens[i-1].word)
+		}
+
+		// a single capitalized word starting a sentence is too ambiguous
+		if len(words) > 1 || !tokens[i].sentenceStart || personTitles[prev] {
+			name := strings.Join(words, " ")
+			entities.add(classify(words, prev), name)
+		}
+		i = j
+	}
+	return entities
+}
+
+func classify(words []string, prev string) string {
+	for _, w := range words {
+		lw := strings.ToLower(w)
+		if orgWords[lw] {
+			return Organization
+		}
+		if placeSuffixes[lw] {
+			return Place
+		}
+	}
+	switch {
+	case personTitles[prev]:
+		return Person
+	case placeWords[prev]:
+		return Place
+	case len(words) == 1 && isAcronym(words[0]):
+		return Organization
+	case len(words) >= 2 && len(words) <= 3:
+		return Person
+	}
+	return Other
+}
+
+func isAcronym(w string) bool {
+	n := utf8.RuneCountInString(w)
+	return n >= 2 && n <= 5 && strings.ToUpper(w) == w && strings.IndexFunc(w, unicode.IsLetter) >= 0
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHeuristic(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Entities
+	}{
+		{
+			name: "people places and organizations",
+			text: "Dr. Ada Lovelace met Charles Babbage in London before joining Acme Corp. The meeting was reported by the BBC.",
+			want: Entities{
+				Person:       {"Ada Lovelace", "Charles Babbage"},
+				Place:        {"London"},
+				Organization: {"Acme Corp", "BBC"},
+			},
+		},
+		{
+			name: "connectors join a phrase",
+			text: "She studied at the University of Cambridge and later moved to Rio de Janeiro.",
+			want: Entities{
+				Organization: {"University of Cambridge"},
+				Place:        {"Rio de Janeiro"},
+			},
+		},
+		{
+			name: "sentence starts are not entities",
+			text: "Yesterday it rained. After lunch we walked. Nothing happened.",
+			want: Entities{},
+		},
+		{
+			name: "duplicates are listed once",
+			text: "Grace Hopper wrote a compiler. Later Grace Hopper joined the Navy with Mr. Smith.",
+			want: Entities{
+				Person: {"Grace Hopper", "Smith"},
+				Other:  {"Navy"},
+			},
+		},
+		{
+			name: "place suffix",
+			text: "The boat sailed down the Mississippi River past Baton Rouge.",
+			want: Entities{
+				Place: {"Mississippi River", "Baton Rouge"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Heuristic(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Heuristic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntitiesString(t *testing.T) {
+	e := Entities{Organization: {"Acme Corp"}, Person: {"Ada Lovelace", "Charles Babbage"}}
+	want := "people: Ada Lovelace, Charles Babbage\norganizations: Acme Corp"
+	if got := e.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := (Entities{}).String(); got != "no named entities found in the text" {
+		t.Errorf("String() of no entities = %q", got)
+	}
+}
+
+func TestExtractShortText(t *testing.T) {
+	for _, text := range []string{"", "  ", "Al"} {
+		if _, err := Extract(nil, text); err == nil {
+			t.Errorf("Extract(%q) should fail", text)
+		}
+	}
+}
+
+func TestExtractWithAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/documents:analyzeEntities" || r.URL.Query().Get("key") != "test-key" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"entities":[
+			{"name":"Ada Lovelace","type":"PERSON"},
+			{"name":"London","type":"LOCATION"},
+			{"name":"city","type":"LOCATION"},
+			{"name":"Acme Corp","type":"ORGANIZATION"},
+			{"name":"1843","type":"DATE"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	api := &NLPClient{APIKey: "test-key", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	got, err := Extract(api, "Ada Lovelace lived in the city of London in 1843 and worked for Acme Corp.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Entities{Person: {"Ada Lovelace"}, Place: {"London"}, Organization: {"Acme Corp"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractFallsBackToHeuristic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	api := &NLPClient{APIKey: "bad-key", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	got, err := Extract(api, "Alan Turing was born in London.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Entities{Person: {"Alan Turing"}, Place: {"London"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Generate fake but plausible test data: person names, email addresses, postal addresses, company names or phone numbers, at most 100 of them. The email addresses use the reserved example domains and the phone numbers the fictional 555-01xx range, so they never reach anyone. Give the same "seed" to get the same records again. The function returns a JSON array of strings.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Type  string `json:"type" jsonschema:"description=The kind of records to generate,enum=name,enum=email,enum=address,enum=company,enum=phone"`
+	Count int    `json:"count" jsonschema:"description=The number of records to generate,minimum=1,maximum=100"`
+	Seed  int64  `json:"seed,omitempty" jsonschema:"description=A seed to generate the same records again. Random when omitted"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "fake-data", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xDB}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "type", msg.Type, "count", msg.Count, "seed", msg.Seed)
+
+	seed := msg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	records, err := Generate(rand.New(rand.NewSource(seed)), msg.Type, msg.Count)
+	if err != nil {
+		slog.Warn("[sfn] Generate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not generate the test data: %v", err))
+		return
+	}
+
+	buf, _ := json.Marshal(records)
+	sfn.WriteText(ctx, string(buf))
+}
+
+// maxCount caps the number of records.
+const maxCount = 100
+
+// generators make one record of each type.
+var generators = map[string]func(r *rand.Rand) string{
+	"name":    name,
+	"email":   email,
+	"address": address,
+	"company": company,
+	"phone":   phone,
+}
+
+// Generate returns count records of the type drawn from r. The count is
+// capped to maxCount.
+func Generate(r *rand.Rand, typ string, count int) ([]string, error) {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	gen, ok := generators[typ]
+	// the plural, e.g. "names" or "addresses"
+	for _, suffix := range []string{"s", "es"} {
+		if !ok {
+			gen, ok = generators[strings.TrimSuffix(typ, suffix)]
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q, it must be name, email, address, company or phone", typ)
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("the count must be at least 1, got %d", count)
+	}
+	count = min(count, maxCount)
+
+	records := make([]string, count)
+	for i := range records {
+		records[i] = gen(r)
+	}
+	return records, nil
+}
+
+var (
+	firstNames = strings.Fields(`James Mary Robert Patricia John Jennifer Michael Linda
+David Elizabeth William Barbara Richard Susan Joseph Jessica Thomas Sarah Carlos
+Maria Wei Mei Hiroshi Yuki Ahmed Fatima Liam Olivia Noah Emma Lucas Sofia Mateo
+Amara Ravi Priya Kofi Ama Elena Ivan`)
+	lastNames = strings.Fields(`Smith Johnson Williams Brown Jones Garcia Miller Davis
+Rodriguez Martinez Hernandez Lopez Wilson Anderson Thomas Taylor Moore Jackson
+Martin Lee Chen Wang Tanaka Sato Khan Ali Novak Rossi Müller Dubois Silva Okafor
+Mensah Patel Sharma Kowalski Ivanova Nguyen`)
+	streets = strings.Fields(`Oak Maple Cedar Pine Elm Washington Lake Hill Park
+Sunset River Spring Church Mill Highland Meadow Forest Lincoln Willow Chestnut`)
+	streetTypes = strings.Fields(`St Ave Rd Blvd Ln Dr Way Ct`)
+	// cities with their state and the first digits of their ZIP codes
+	cities = []struct{ city, state, zip string }{
+		{"Springfield", "IL", "627"},
+		{"Portland", "OR", "972"},
+		{"Austin", "TX", "787"},
+		{"Madison", "WI", "537"},
+		{"Burlington", "VT", "054"},
+		{"Boulder", "CO", "803"},
+		{"Raleigh", "NC", "276"},
+		{"Tucson", "AZ", "857"},
+		{"Savannah", "GA", "314"},
+		{"Albany", "NY", "122"},
+	}
+	companyWords = strings.Fields(`Acme Globex Initech Umbrella Stark Wayne Summit
+Pioneer Horizon Blue Northwind Evergreen Silverline Redwood Atlas Nimbus Quantum
+Apex Harbor Beacon`)
+	companyKinds = strings.Fields(`Systems Labs Logistics Foods Analytics Robotics
+Energy Media Health Capital Works Studios`)
+	companySuffixes = strings.Fields(`Inc. LLC Ltd. Corp. Group Co.`)
+	// the domains reserved for documentation by RFC 2606
+	emailDomains = strings.Fields(`example.com example.org example.net`)
+	areaCodes    = strings.Fields(`201 212 303 312 415 503 512 617 702 808`)
+)
+
+func pick(r *rand.Rand, pool []string) string {
+	return pool[r.Intn(len(pool))]
+}
+
+func name(r *rand.Rand) string {
+	return pick(r, firstNames) + " " + pick(r, lastNames)
+}
+
+func email(r *rand.Rand) string {
+	first, last := pick(r, firstNames), pick(r, lastNames)
+	local := strings.ToLower(first) + "." + strings.ToLower(last)
+	switch r.Intn(3) {
+	case 0:
+		local = strings.ToLower(first[:1] + last)
+	case 1:
+		local += fmt.Sprint(r.Intn(100))
+	}
+	// keep the addresses ASCII
+	local = strings.NewReplacer("ü", "u").Replace(local)
+	return local + "@" + pick(r, emailDomains)
+}
+
+func address(r *rand.Rand) string {
+	c := cities[r.Intn(len(cities))]
+	return fmt.Sprintf("%d %s %s, %s, %s %s%02d", 1+r.Intn(9999), pick(r, streets), pick(r, streetTypes), c.city, c.state, c.zip, r.Intn(100))
+}
+
+func company(r *rand.Rand) string {
+	return pick(r, companyWords) + " " + pick(r, companyKinds) + " " + pick(r, companySuffixes)
+}
+
+// phone returns a number of the 555-0100 to 555-0199 range, which the
+// North American Numbering Plan reserves for fiction.
+func phone(r *rand.Rand) string {
+	return fmt.Sprintf("+1 %s-555-01%02d", pick(r, areaCodes), r.Intn(100))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math/rand"
+	"net/mail"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	patterns := map[string]*regexp.Regexp{
+		"name":    regexp.MustCompile(`^\pL+ \pL+$`),
+		"email":   regexp.MustCompile(`^[a-z0-9.]+@example\.(com|org|net)$`),
+		"address": regexp.MustCompile(`^\d{1,4} \pL+ \pL+, \pL+, [A-Z]{2} \d{5}$`),
+		"company": regexp.MustCompile(`^\pL+ \pL+ \pL+\.?$`),
+		"phone":   regexp.MustCompile(`^\+1 \d{3}-555-01\d{2}$`),
+	}
+	r := rand.New(rand.NewSource(1))
+	for typ, pattern := range patterns {
+		records, err := Generate(r, typ, 50)
+		if err != nil {
+			t.Fatalf("Generate(%q) error = %v", typ, err)
+		}
+		if len(records) != 50 {
+			t.Errorf("Generate(%q) returned %d records, want 50", typ, len(records))
+		}
+		for _, record := range records {
+			if !pattern.MatchString(record) {
+				t.Errorf("Generate(%q) record %q does not match %s", typ, record, pattern)
+			}
+			if typ == "email" {
+				if _, err := mail.ParseAddress(record); err != nil {
+					t.Errorf("Generate(%q) record %q is not an address: %v", typ, record, err)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	want := map[string][]string{
+		"name":    {"Fatima Brown", "Noah Martin", "Yuki Wang"},
+		"email":   {"fatima.brown@example.com", "ywang@example.net", "david.sato67@example.com"},
+		"address": {"9162 Park Way, Boulder, CO 80323", "4939 Forest St, Boulder, CO 80343", "2775 Meadow Ln, Albany, NY 12252"},
+		"company": {"Wayne Studios Ltd.", "Northwind Media LLC", "Apex Health Ltd."},
+		"phone":   {"+1 503-555-0187", "+1 702-555-0150", "+1 312-555-0145"},
+	}
+	for typ, records := range want {
+		got, err := Generate(rand.New(rand.NewSource(42)), typ, 3)
+		if err != nil {
+			t.Fatalf("Generate(%q) error = %v", typ, err)
+		}
+		if !slices.Equal(got, records) {
+			t.Errorf("Generate(%q) with seed 42 = %q, want %q", typ, got, records)
+		}
+	}
+}
+
+func TestGenerateCount(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	records, err := Generate(r, "Addresses", 1000)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(records) != maxCount {
+		t.Errorf("Generate() returned %d records, want the cap %d", len(records), maxCount)
+	}
+
+	if _, err := Generate(r, "credit card", 3); err == nil {
+		t.Error("Generate() should reject an unknown type")
+	}
+	if _, err := Generate(r, "name", 0); err == nil {
+		t.Error("Generate() should reject a count of 0")
+	}
+}
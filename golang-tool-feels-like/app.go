@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Calculate the apparent temperature, how hot or cold the air feels, from the air temperature, the relative humidity and the wind speed, e.g. "how cold does -10°C with a 30 km/h wind feel?". The function returns the wind chill when it is cold and windy, the heat index when it is hot, and the air temperature otherwise, with the National Weather Service formulas.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Temperature float64 `json:"temperature" jsonschema:"description=The air temperature in degrees Celsius or Fahrenheit depending on units"`
+	Humidity    float64 `json:"humidity" jsonschema:"description=The relative humidity in percent,minimum=0,maximum=100"`
+	WindSpeed   float64 `json:"windSpeed" jsonschema:"description=The wind speed in km/h or mph depending on units,minimum=0"`
+	Units       string  `json:"units,omitempty" jsonschema:"description=metric for °C and km/h or imperial for °F and mph. Defaults to metric,enum=metric,enum=imperial"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "feels-like", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xDF}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	apparent, err := FeelsLike(msg)
+	if err != nil {
+		slog.Warn("[sfn] FeelsLike error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not calculate the apparent temperature: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", apparent)
+	sfn.WriteText(ctx, apparent.String())
+}
+
+// Apparent is the temperature the air feels like, in the units of the
+// Parameter it was calculated from.
+type Apparent struct {
+	Temperature float64
+	// Index is "wind chill", "heat index", or empty when the air feels like
+	// its actual temperature.
+	Index  string
+	Symbol string
+}
+
+// String returns the apparent temperature, e.g. "It feels like -17.9°C (wind chill)".
+func (a Apparent) String() string {
+	if a.Index == "" {
+		return fmt.Sprintf("It feels like the air temperature, %.1f%s: neither the wind chill nor the heat index applies", a.Temperature, a.Symbol)
+	}
+	return fmt.Sprintf("It feels like %.1f%s (%s)", a.Temperature, a.Symbol, a.Index)
+}
+
+// FeelsLike validates p and returns its wind chill or heat index, whichever
+// applies to its temperature.
+func FeelsLike(p Parameter) (Apparent, error) {
+	units := strings.ToLower(strings.TrimSpace(p.Units))
+	if units == "" {
+		units = "metric"
+	}
+	if units != "metric" && units != "imperial" {
+		return Apparent{}, fmt.Errorf("unknown units %q, use metric or imperial", p.Units)
+	}
+	if math.IsNaN(p.Temperature) || math.IsInf(p.Temperature, 0) {
+		return Apparent{}, fmt.Errorf("invalid temperature %v", p.Temperature)
+	}
+	if math.IsNaN(p.Humidity) || p.Humidity < 0 || p.Humidity > 100 {
+		return Apparent{}, fmt.Errorf("humidity %v%% is not between 0 and 100", p.Humidity)
+	}
+	if math.IsNaN(p.WindSpeed) || math.IsInf(p.WindSpeed, 0) || p.WindSpeed < 0 {
+		return Apparent{}, fmt.Errorf("invalid wind speed %v", p.WindSpeed)
+	}
+
+	t, wind, symbol := p.Temperature, p.WindSpeed, "°F"
+	if units == "metric" {
+		t, wind, symbol = t*9/5+32, wind/1.609344, "°C"
+	}
+
+	a := Apparent{Symbol: symbol}
+	a.Temperature, a.Index = weather.Apparent(t, p.Humidity, wind)
+	if units == "metric" {
+		a.Temperature = (a.Temperature - 32) * 5 / 9
+	}
+	return a, nil
+}
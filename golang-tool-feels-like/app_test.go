@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFeelsLike(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Parameter
+		want string
+	}{
+		{
+			name: "wind chill",
+			p:    Parameter{Temperature: 0, Humidity: 50, WindSpeed: 15, Units: "imperial"},
+			want: "It feels like -19.4°F (wind chill)",
+		},
+		{
+			// -10°C and 30 km/h is 14°F and 18.6 mph
+			name: "metric wind chill",
+			p:    Parameter{Temperature: -10, Humidity: 80, WindSpeed: 30},
+			want: "It feels like -19.5°C (wind chill)",
+		},
+		{
+			name: "heat index",
+			p:    Parameter{Temperature: 90, Humidity: 60, WindSpeed: 10, Units: "Imperial"},
+			want: "It feels like 99.7°F (heat index)",
+		},
+		{
+			name: "metric heat index",
+			p:    Parameter{Temperature: 35, Humidity: 60, Units: "metric"},
+			want: "It feels like 45.1°C (heat index)",
+		},
+		{
+			name: "mild",
+			p:    Parameter{Temperature: 20, Humidity: 50, WindSpeed: 20},
+			want: "It feels like the air temperature, 20.0°C: neither the wind chill nor the heat index applies",
+		},
+		{
+			name: "cold and calm",
+			p:    Parameter{Temperature: 30, Humidity: 50, WindSpeed: 2, Units: "imperial"},
+			want: "It feels like the air temperature, 30.0°F: neither the wind chill nor the heat index applies",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FeelsLike(tt.p)
+			if err != nil {
+				t.Fatalf("FeelsLike() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("FeelsLike() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeelsLikeInvalid(t *testing.T) {
+	for _, p := range []Parameter{
+		{Temperature: 20, Humidity: -1},
+		{Temperature: 20, Humidity: 101},
+		{Temperature: 20, Humidity: math.NaN()},
+		{Temperature: 20, Humidity: 50, WindSpeed: -5},
+		{Temperature: math.Inf(1), Humidity: 50},
+		{Temperature: 20, Humidity: 50, Units: "kelvin"},
+	} {
+		if _, err := FeelsLike(p); err == nil {
+			t.Errorf("FeelsLike(%+v) should fail", p)
+		}
+	}
+}
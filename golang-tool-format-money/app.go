@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/currency"
+	"github.com/yomorun/llm-function-calling-examples/internal/numfmt"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Format an amount of money the way it is written in its currency, with the symbol before or after the amount, the number of decimals of the currency and its digit grouping, e.g. 1234.5 USD is $1,234.50, 1234.5 EUR is 1.234,50 €, 1234.5 JPY is ¥1,235 and 1234.5 BHD is BHD 1,234.500. Always use this function instead of writing prices yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Amount   float64 `json:"amount" jsonschema:"description=The amount of money,example=1234.5"`
+	Currency string  `json:"currency" jsonschema:"description=The 3-letter ISO 4217 code of the currency e.g. USD or JPY,example=EUR"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "format-money", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x10E}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "amount", msg.Amount, "currency", msg.Currency)
+
+	result, err := Format(msg.Amount, msg.Currency)
+	if err != nil {
+		slog.Warn("[sfn] Format error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not format the amount: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Currency is how the amounts of a currency are written.
+type Currency struct {
+	Symbol string
+	// Decimals is the number of minor unit digits of ISO 4217.
+	Decimals int
+	// After puts the symbol after the amount, e.g. "12,50 €".
+	After bool
+	// Space separates the symbol from the amount.
+	Space bool
+	numfmt.Locale
+}
+
+var (
+	english     = numfmt.Locale{Group: ",", Decimal: "."}
+	continental = numfmt.Locale{Group: ".", Decimal: ","}
+	// the groups are separated by a no-break space
+	spaced = numfmt.Locale{Group: "\u00a0", Decimal: ","}
+)
+
+// currencies are the conventions of the supported currencies, as written in
+// their main country. The dollars and the yuan have the CLDR symbols that
+// tell them apart from the US dollar and the yen.
+var currencies = map[string]Currency{
+	"AUD": {Symbol: "A$", Decimals: 2, Locale: english},
+	"BHD": {Symbol: "BHD", Decimals: 3, Space: true, Locale: english},
+	"BRL": {Symbol: "R$", Decimals: 2, Space: true, Locale: continental},
+	"CAD": {Symbol: "CA$", Decimals: 2, Locale: english},
+	"CHF": {Symbol: "CHF", Decimals: 2, Space: true, Locale: numfmt.Locale{Group: "’", Decimal: "."}},
+	"CNY": {Symbol: "CN¥", Decimals: 2, Locale: english},
+	"CZK": {Symbol: "Kč", Decimals: 2, After: true, Space: true, Locale: spaced},
+	"DKK": {Symbol: "kr.", Decimals: 2, After: true, Space: true, Locale: continental},
+	"EUR": {Symbol: "€", Decimals: 2, After: true, Space: true, Locale: continental},
+	"GBP": {Symbol: "£", Decimals: 2, Locale: english},
+	"HKD": {Symbol: "HK$", Decimals: 2, Locale: english},
+	"INR": {Symbol: "₹", Decimals: 2, Locale: numfmt.Locale{Group: ",", Decimal: ".", Indian: true}},
+	"ISK": {Symbol: "kr", Decimals: 0, After: true, Space: true, Locale: continental},
+	"JOD": {Symbol: "JOD", Decimals: 3, Space: true, Locale: english},
+	"JPY": {Symbol: "¥", Decimals: 0, Locale: english},
+	"KRW": {Symbol: "₩", Decimals: 0, Locale: english},
+	"KWD": {Symbol: "KWD", Decimals: 3, Space: true, Locale: english},
+	"MXN": {Symbol: "MX$", Decimals: 2, Locale: english},
+	"NOK": {Symbol: "kr", Decimals: 2, After: true, Space: true, Locale: spaced},
+	"NZD": {Symbol: "NZ$", Decimals: 2, Locale: english},
+	"OMR": {Symbol: "OMR", Decimals: 3, Space: true, Locale: english},
+	"PLN": {Symbol: "zł", Decimals: 2, After: true, Space: true, Locale: spaced},
+	"RUB": {Symbol: "₽", Decimals: 2, After: true, Space: true, Locale: spaced},
+	"SEK": {Symbol: "kr", Decimals: 2, After: true, Space: true, Locale: spaced},
+	"SGD": {Symbol: "S$", Decimals: 2, Locale: english},
+	"TND": {Symbol: "TND", Decimals: 3, Space: true, Locale: english},
+	"TRY": {Symbol: "₺", Decimals: 2, Locale: continental},
+	"USD": {Symbol: "$", Decimals: 2, Locale: english},
+	"VND": {Symbol: "₫", Decimals: 0, After: true, Space: true, Locale: continental},
+}
+
+// Format writes amount in the currency with code, rounded to its decimals.
+func Format(amount float64, code string) (string, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return "", errors.New("the amount is not a finite number")
+	}
+	code, err := currency.ParseCode(code)
+	if err != nil {
+		return "", err
+	}
+	c, ok := currencies[code]
+	if !ok {
+		codes := make([]string, 0, len(currencies))
+		for c := range currencies {
+			codes = append(codes, c)
+		}
+		sort.Strings(codes)
+		return "", fmt.Errorf("the conventions of %s are not known, use one of %s", code, strings.Join(codes, ", "))
+	}
+
+	digits, negative := numfmt.Format(round(amount, c.Decimals), c.Decimals, c.Locale)
+	space := ""
+	if c.Space {
+		// a no-break space, the symbol does not wrap apart from the amount
+		space = "\u00a0"
+	}
+	s := c.Symbol + space + digits
+	if c.After {
+		s = digits + space + c.Symbol
+	}
+	if negative {
+		s = "-" + s
+	}
+	return s, nil
+}
+
+// round rounds x half away from zero to decimals on its shortest decimal
+// representation, so that 2.5 yen is 3 and 1.005 dollars is $1.01, not the
+// $1.00 of the binary value just below.
+func round(x float64, decimals int) float64 {
+	s := strconv.FormatFloat(math.Abs(x), 'f', -1, 64)
+	integer, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) <= decimals {
+		return x
+	}
+	r, _ := strconv.ParseFloat(integer+"."+fraction[:decimals], 64)
+	if fraction[decimals] >= '5' {
+		r += math.Pow10(-decimals)
+	}
+	return math.Copysign(r, x)
+}
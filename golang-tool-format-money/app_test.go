@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		// no decimals
+		{1234567.891, "JPY", "¥1,234,568"},
+		{2.5, "jpy", "¥3"},
+		{-1500, "JPY", "-¥1,500"},
+		// two decimals
+		{1234.5, "USD", "$1,234.50"},
+		{1.005, "USD", "$1.01"},
+		{0.1 + 0.2, "USD", "$0.30"},
+		{-0.004, "USD", "$0.00"},
+		{-42, " usd ", "-$42.00"},
+		// three decimals
+		{1234.5, "BHD", "BHD\u00a01,234.500"},
+		{0.0125, "BHD", "BHD\u00a00.013"},
+		{-7.25, "BHD", "-BHD\u00a07.250"},
+		// the symbol after the amount
+		{1234.5, "EUR", "1.234,50\u00a0€"},
+		{-1234567.5, "SEK", "-1\u00a0234\u00a0567,50\u00a0kr"},
+		{1234567.5, "INR", "₹12,34,567.50"},
+		{1234.5, "CHF", "CHF\u00a01’234.50"},
+	}
+	for _, tt := range tests {
+		got, err := Format(tt.amount, tt.currency)
+		if err != nil {
+			t.Errorf("Format(%v, %q) error = %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Format(%v, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		{1, "EURO", "not a 3-letter ISO 4217 currency code"},
+		{1, "XYZ", "the conventions of XYZ are not known, use one of AUD, BHD"},
+		{math.Inf(1), "USD", "not a finite number"},
+	}
+	for _, tt := range tests {
+		_, err := Format(tt.amount, tt.currency)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Format(%v, %q) error = %v, want it to contain %q", tt.amount, tt.currency, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/numfmt"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Format a number the way it is written in a locale, with its thousands separators and decimal mark, e.g. 1234567.5 is 1,234,567.5 in en, 1.234.567,5 in de and 12,34,567.5 in in with the Indian lakh and crore grouping. Always use this function instead of grouping the digits of large numbers yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value    float64 `json:"value" jsonschema:"description=The number to format,example=1234567.891"`
+	Locale   string  `json:"locale" jsonschema:"description=The language code of the locale,enum=en,enum=de,enum=fr,enum=es,enum=it,enum=nl,enum=pt,enum=ru,enum=ch,enum=in,enum=ja,enum=zh"`
+	Decimals *int    `json:"decimals,omitempty" jsonschema:"description=The number of decimals to round to. Defaults to the decimals of the value,minimum=0,maximum=10"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "format-number", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x105}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "value", msg.Value, "locale", msg.Locale)
+
+	decimals := -1
+	if msg.Decimals != nil {
+		decimals = *msg.Decimals
+	}
+	result, err := Format(msg.Value, msg.Locale, decimals)
+	if err != nil {
+		slog.Warn("[sfn] Format error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not format the number: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// locales are the supported locales by language code. The French and
+// Russian groups are separated by a narrow and a plain no-break space.
+var locales = map[string]numfmt.Locale{
+	"en": {Group: ",", Decimal: "."},
+	"de": {Group: ".", Decimal: ","},
+	"fr": {Group: "\u202f", Decimal: ","},
+	"es": {Group: ".", Decimal: ",", MinGrouping: 5},
+	"it": {Group: ".", Decimal: ","},
+	"nl": {Group: ".", Decimal: ","},
+	"pt": {Group: ".", Decimal: ","},
+	"ru": {Group: "\u00a0", Decimal: ","},
+	"ch": {Group: "’", Decimal: "."},
+	"in": {Group: ",", Decimal: ".", Indian: true},
+	"ja": {Group: ",", Decimal: "."},
+	"zh": {Group: ",", Decimal: "."},
+}
+
+// aliases are the other codes accepted for a locale.
+var aliases = map[string]string{
+	"en-in": "in",
+	"hi":    "in",
+	"de-ch": "ch",
+	"pt-br": "pt",
+}
+
+// Format writes value in the locale with code, rounded to decimals, or with
+// the decimals of the value when decimals is negative.
+func Format(value float64, code string, decimals int) (string, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", errors.New("the value is not a finite number")
+	}
+	if decimals > 10 {
+		return "", fmt.Errorf("%d decimals is too many, the most is 10", decimals)
+	}
+	loc, err := lookup(code)
+	if err != nil {
+		return "", err
+	}
+
+	digits, negative := numfmt.Format(value, decimals, loc)
+	if negative {
+		return "-" + digits, nil
+	}
+	return digits, nil
+}
+
+// lookup returns the locale with the case-insensitive code or one of its
+// aliases.
+func lookup(code string) (numfmt.Locale, error) {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	if alias, ok := aliases[code]; ok {
+		code = alias
+	}
+	if loc, ok := locales[code]; ok {
+		return loc, nil
+	}
+	codes := make([]string, 0, len(locales))
+	for c := range locales {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	return numfmt.Locale{}, fmt.Errorf("unknown locale %q, use one of %s", code, strings.Join(codes, ", "))
+}
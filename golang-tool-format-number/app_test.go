@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		value    float64
+		locale   string
+		decimals int
+		want     string
+	}{
+		{0, "en", -1, "0"},
+		{999, "en", -1, "999"},
+		{1000, "en", -1, "1,000"},
+		{1234567.891, "en", -1, "1,234,567.891"},
+		{1234567.891, "en", 2, "1,234,567.89"},
+		{1234567.891, "en", 0, "1,234,568"},
+		{-1234567, "en", -1, "-1,234,567"},
+		{1.5, "en", 3, "1.500"},
+		{999999.5, "en", 0, "1,000,000"},
+		{-0.001, "en", 2, "0.00"},
+
+		{1234567.891, "de", 2, "1.234.567,89"},
+		{1000, "de", -1, "1.000"},
+		{0.25, "de", -1, "0,25"},
+		{-98765.4, "DE", -1, "-98.765,4"},
+
+		{1234, "in", -1, "1,234"},
+		{12345, "in", -1, "12,345"},
+		{123456, "in", -1, "1,23,456"},
+		{1234567, "in", -1, "12,34,567"},
+		{12345678.9, "in", -1, "1,23,45,678.9"},
+		{1000000000, "in", -1, "1,00,00,00,000"},
+		{-100000, "en-IN", 2, "-1,00,000.00"},
+
+		{1234567.5, "fr", -1, "1\u202f234\u202f567,5"},
+		{1234567.5, "ch", -1, "1’234’567.5"},
+		{1234, "es", -1, "1234"},
+		{12345, "es", -1, "12.345"},
+	}
+	for _, tt := range tests {
+		got, err := Format(tt.value, tt.locale, tt.decimals)
+		if err != nil {
+			t.Errorf("Format(%v, %q, %d) error = %v", tt.value, tt.locale, tt.decimals, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Format(%v, %q, %d) = %q, want %q", tt.value, tt.locale, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		locale   string
+		decimals int
+		want     string
+	}{
+		{"unknown locale", 1, "xx", -1, `unknown locale "xx", use one of ch, de, en`},
+		{"not a number", math.NaN(), "en", -1, "not a finite number"},
+		{"infinite", math.Inf(1), "en", -1, "not a finite number"},
+		{"too many decimals", 1, "en", 11, "too many"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Format(tt.value, tt.locale, tt.decimals)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Format() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Check whether a geo point is inside a geofence, e.g. "is 48.85,2.35 inside this delivery zone?". The geofence is a polygon given by its vertices in order, in decimal degrees. The function returns whether the point is inside, outside or on the border of the polygon. Polygons crossing the 180° meridian are supported.`
+}
+
+// Vertex is a corner of the polygon.
+type Vertex struct {
+	Lat float64 `json:"lat" jsonschema:"description=The latitude of the vertex in decimal format,minimum=-90,maximum=90"`
+	Lon float64 `json:"lon" jsonschema:"description=The longitude of the vertex in decimal format,minimum=-180,maximum=180"`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64  `json:"latitude" jsonschema:"description=The latitude of the point in decimal format,minimum=-90,maximum=90"`
+	Longitude float64  `json:"longitude" jsonschema:"description=The longitude of the point in decimal format,minimum=-180,maximum=180"`
+	Polygon   []Vertex `json:"polygon" jsonschema:"description=The vertices of the geofence in order along its border,minItems=3"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "geofence", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC2}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "vertices", len(msg.Polygon))
+
+	position, err := Locate(Vertex{Lat: msg.Latitude, Lon: msg.Longitude}, msg.Polygon)
+	if err != nil {
+		slog.Warn("[sfn] Locate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not check the geofence: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("The point %v,%v is %s the geofence", msg.Latitude, msg.Longitude, position))
+}
+
+// Position is where a point lies relative to a polygon.
+type Position int
+
+const (
+	Outside Position = iota
+	Inside
+	OnBorder
+)
+
+func (p Position) String() string {
+	switch p {
+	case Inside:
+		return "inside"
+	case OnBorder:
+		return "on the border of"
+	default:
+		return "outside"
+	}
+}
+
+// epsilon is the distance in degrees, about 0.1 mm, within which a point
+// counts as on the border.
+const epsilon = 1e-9
+
+// Locate tells whether point is inside polygon with the ray casting
+// algorithm. The coordinates are treated as planar, which is accurate for
+// geofences of up to a few hundred kilometers. The polygon is closed
+// implicitly, repeating the first vertex at the end is allowed.
+func Locate(point Vertex, polygon []Vertex) (Position, error) {
+	if err := validate(point); err != nil {
+		return Outside, fmt.Errorf("invalid point: %w", err)
+	}
+	if len(polygon) < 3 {
+		return Outside, fmt.Errorf("a polygon needs at least 3 vertices, got %d", len(polygon))
+	}
+	for i, v := range polygon {
+		if err := validate(v); err != nil {
+			return Outside, fmt.Errorf("invalid vertex %d: %w", i+1, err)
+		}
+	}
+
+	vertices := unwrap(polygon)
+	point.Lon = shiftInto(point.Lon, vertices)
+
+	inside := false
+	for i := range vertices {
+		a, b := vertices[i], vertices[(i+1)%len(vertices)]
+		if onSegment(point, a, b) {
+			return OnBorder, nil
+		}
+		// count the edges crossed by a ray going east from the point, a vertex
+		// level with the point counts as below it so it is crossed only once
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) {
+			lon := a.Lon + (point.Lat-a.Lat)*(b.Lon-a.Lon)/(b.Lat-a.Lat)
+			if point.Lon < lon {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return Inside, nil
+	}
+	return Outside, nil
+}
+
+func validate(v Vertex) error {
+	if err := geo.ValidateCoordinate(v.Lat, v.Lon); err != nil {
+		return err
+	}
+	return nil
+}
+
+// unwrap returns the polygon with its longitudes made continuous: an edge
+// longer than 180° of longitude is taken to cross the antimeridian instead,
+// so the vertex after it is moved by 360°. A polygon around Fiji from 178°
+// to -178° becomes one from 178° to 182°.
+func unwrap(polygon []Vertex) []Vertex {
+	vertices := make([]Vertex, len(polygon))
+	copy(vertices, polygon)
+	for i := 1; i < len(vertices); i++ {
+		for vertices[i].Lon-vertices[i-1].Lon > 180 {
+			vertices[i].Lon -= 360
+		}
+		for vertices[i].Lon-vertices[i-1].Lon < -180 {
+			vertices[i].Lon += 360
+		}
+	}
+	return vertices
+}
+
+// shiftInto moves lon by 360° when that puts it in the longitude range of
+// the unwrapped vertices.
+func shiftInto(lon float64, vertices []Vertex) float64 {
+	lo, hi := vertices[0].Lon, vertices[0].Lon
+	for _, v := range vertices[1:] {
+		lo, hi = math.Min(lo, v.Lon), math.Max(hi, v.Lon)
+	}
+	for _, l := range []float64{lon, lon + 360, lon - 360} {
+		if l >= lo-epsilon && l <= hi+epsilon {
+			return l
+		}
+	}
+	return lon
+}
+
+// onSegment reports whether p lies on the segment from a to b.
+func onSegment(p, a, b Vertex) bool {
+	cross := (b.Lon-a.Lon)*(p.Lat-a.Lat) - (b.Lat-a.Lat)*(p.Lon-a.Lon)
+	length := math.Hypot(b.Lon-a.Lon, b.Lat-a.Lat)
+	if length == 0 {
+		return math.Hypot(p.Lon-a.Lon, p.Lat-a.Lat) <= epsilon
+	}
+	if math.Abs(cross)/length > epsilon {
+		return false
+	}
+	return p.Lon >= math.Min(a.Lon, b.Lon)-epsilon && p.Lon <= math.Max(a.Lon, b.Lon)+epsilon &&
+		p.Lat >= math.Min(a.Lat, b.Lat)-epsilon && p.Lat <= math.Max(a.Lat, b.Lat)+epsilon
+}
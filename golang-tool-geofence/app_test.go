@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+// square is a 2° square around Paris, closed with its first vertex.
+var square = []Vertex{
+	{Lat: 48, Lon: 1},
+	{Lat: 48, Lon: 3},
+	{Lat: 50, Lon: 3},
+	{Lat: 50, Lon: 1},
+	{Lat: 48, Lon: 1},
+}
+
+// notch is a concave polygon with a notch cut into its top side.
+var notch = []Vertex{
+	{Lat: 0, Lon: 0},
+	{Lat: 0, Lon: 10},
+	{Lat: 10, Lon: 10},
+	{Lat: 10, Lon: 6},
+	{Lat: 4, Lon: 5},
+	{Lat: 10, Lon: 4},
+	{Lat: 10, Lon: 0},
+}
+
+// fiji crosses the antimeridian from 178°E to 178°W.
+var fiji = []Vertex{
+	{Lat: -20, Lon: 178},
+	{Lat: -20, Lon: -178},
+	{Lat: -15, Lon: -178},
+	{Lat: -15, Lon: 178},
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		point   Vertex
+		polygon []Vertex
+		want    Position
+	}{
+		{"square inside", Vertex{Lat: 48.85, Lon: 2.35}, square, Inside},
+		{"square outside", Vertex{Lat: 51.5, Lon: -0.12}, square, Outside},
+		{"square on edge", Vertex{Lat: 48, Lon: 2}, square, OnBorder},
+		{"square on vertex", Vertex{Lat: 50, Lon: 3}, square, OnBorder},
+		{"square level with a vertex", Vertex{Lat: 50, Lon: 0}, square, Outside},
+		{"notch inside", Vertex{Lat: 2, Lon: 5}, notch, Inside},
+		{"notch in the notch", Vertex{Lat: 8, Lon: 5}, notch, Outside},
+		{"notch level with the notch tip", Vertex{Lat: 4, Lon: 1}, notch, Inside},
+		{"notch on the tip", Vertex{Lat: 4, Lon: 5}, notch, OnBorder},
+		{"fiji east of the antimeridian", Vertex{Lat: -17.7, Lon: 179}, fiji, Inside},
+		{"fiji west of the antimeridian", Vertex{Lat: -17.7, Lon: -179}, fiji, Inside},
+		{"fiji on the antimeridian", Vertex{Lat: -17.7, Lon: 180}, fiji, Inside},
+		{"fiji outside", Vertex{Lat: -17.7, Lon: 0}, fiji, Outside},
+		{"fiji on edge", Vertex{Lat: -20, Lon: -179}, fiji, OnBorder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Locate(tt.point, tt.polygon)
+			if err != nil {
+				t.Fatalf("Locate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Locate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocateInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		point   Vertex
+		polygon []Vertex
+	}{
+		{"too few vertices", Vertex{Lat: 1, Lon: 1}, square[:2]},
+		{"point out of range", Vertex{Lat: 91, Lon: 1}, square},
+		{"vertex out of range", Vertex{Lat: 1, Lon: 1}, []Vertex{{0, 0}, {0, 190}, {10, 0}}},
+	}
+	for _, tt := range tests {
+		if _, err := Locate(tt.point, tt.polygon); err == nil {
+			t.Errorf("%s: Locate() should fail", tt.name)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/go-ping/ping"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
 	"github.com/yomorun/yomo/serverless"
 )
 
@@ -33,21 +35,22 @@ func InputSchema() any {
 	return &Parameter{}
 }
 
+func init() {
+	registry.Register(registry.Tool{Name: "get-ip-and-latency", Description: Description(), InputSchema: InputSchema()})
+}
+
 // Handler orchestrates the core processing logic of this function.
-// - ctx.ReadLLMArguments() parses LLM Function Calling Arguments.
-// - ctx.WriteLLMResult() sends the retrieval result back to LLM.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
 func Handler(ctx serverless.Context) {
 	var msg Parameter
-	err := ctx.ReadLLMArguments(&msg)
-	if err != nil {
-		slog.Error("[sfn] unmarshal arguments", "err", err)
-		ctx.WriteLLMResult("can not get the domain name right now, please try again later")
+	if !sfn.ReadArgs(ctx, &msg) {
 		return
 	}
 
 	if msg.Domain == "" {
 		slog.Warn("[sfn] domain is empty")
-		ctx.WriteLLMResult("can not get the domain name right now, please try again later")
+		sfn.WriteText(ctx, "can not get the domain name right now, please try again later")
 		return
 	}
 
@@ -55,7 +58,7 @@ func Handler(ctx serverless.Context) {
 	ips, err := net.LookupIP(msg.Domain)
 	if err != nil {
 		slog.Error("[sfn] could not get IPs", "err", err)
-		ctx.WriteLLMResult("can not get the domain name right now, please try again later")
+		sfn.WriteText(ctx, "can not get the domain name right now, please try again later")
 		return
 	}
 
@@ -67,7 +70,7 @@ func Handler(ctx serverless.Context) {
 	pinger, err := ping.NewPinger(ips[0].String())
 	if err != nil {
 		slog.Error("[sfn] could not create pinger", "err", err)
-		ctx.WriteLLMResult("can not get the domain name right now, please try again later")
+		sfn.WriteText(ctx, "can not get the domain name right now, please try again later")
 		return
 	}
 
@@ -86,5 +89,5 @@ func Handler(ctx serverless.Context) {
 		res = fmt.Sprintf("domain %s has ip %s with average latency %s, make sure answer with the IP address and Latency", msg.Domain, ips[0], stats.AvgRtt)
 	}
 
-	ctx.WriteLLMResult(res)
+	sfn.WriteText(ctx, res)
 }
@@ -1,12 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/circuit"
+	"github.com/yomorun/llm-function-calling-examples/internal/errs"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/ratelimit"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/safe"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
 	"github.com/yomorun/yomo/serverless"
 )
 
@@ -30,49 +43,220 @@ func InputSchema() any {
 	return &LLMArguments{}
 }
 
+func init() {
+	config = loadConfig(os.Getenv)
+	client = newClient(config)
+	if config.CacheWarmTop > 0 && config.CacheTTL > 0 {
+		go client.KeepCurrentWarm(context.Background(), config.CacheTTL/warmChecks, config.CacheWarmTop)
+	}
+
+	registry.Register(registry.Tool{Name: "get-weather", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: config.BaseURL})
+}
+
 // LLMArguments defines the arguments for the LLM Function Calling. These
 // arguments are combined to form a prompt automatically.
 type LLMArguments struct {
 	City      string  `json:"city" jsonschema:"description=The city name to get the weather for"`
-	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the city, in decimal format, range should be in (-90, 90)"`
-	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the city, in decimal format, range should be in (-180, 180)"`
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the city in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the city in decimal format,minimum=-180,maximum=180"`
 }
 
 // Handler orchestrates the core processing logic of this function.
-// - ctx.ReadLLMArguments() parses LLM Function Calling Arguments (skip if none).
-// - ctx.WriteLLMResult() sends the retrieval result back to LLM.
-// - ctx.Tag() identifies the tag of the incoming data.
-// - ctx.Data() accesses the raw data.
-// - ctx.Write() forwards processed data downstream.
+// - sfn.ReadArgsEnvelope() parses LLM Function Calling Arguments.
+// - sfn.WriteResult() and sfn.WriteError() send the result or a coded error
+// back to LLM.
+// - safe.Wrap() turns a panic into an error result instead of a crash.
 func Handler(ctx serverless.Context) {
+	safe.Wrap(handle)(ctx)
+}
+
+func handle(ctx serverless.Context) {
 	var p LLMArguments
 	// deserilize the arguments from llm tool_call response
-	ctx.ReadLLMArguments(&p)
+	if !sfn.ReadArgsEnvelope(ctx, &p) {
+		return
+	}
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
 
 	// invoke the openweathermap api and return the result back to LLM
-	result := requestOpenWeatherMapAPI(p.Latitude, p.Longitude)
-	ctx.WriteLLMResult(result)
+	result, err := requestOpenWeatherMapAPI(reqCtx, p.Latitude, p.Longitude)
+	if err != nil {
+		sfn.WriteError(ctx, err)
+		return
+	}
+	sfn.WriteResult(ctx, result)
 
 	slog.Info("get-weather", "city", p.City, "result", result)
 }
 
-func requestOpenWeatherMapAPI(lat, lon float64) string {
-	const apiURL = "https://api.openweathermap.org/data/2.5/weather?lat=%f&lon=%f&appid=%s&units=metric"
-	apiKey := os.Getenv("OPENWEATHERMAP_API_KEY")
-	url := fmt.Sprintf(apiURL, lat, lon, apiKey)
+// Config is the configurable surface of the function. It is read from the
+// environment once, when the function starts.
+type Config struct {
+	// APIKey is OPENWEATHERMAP_API_KEY, there is no default.
+	APIKey string
+	// APIKeys is OPENWEATHERMAP_API_KEYS, several comma-separated keys
+	// rotated over the requests to stay under their per-key limits. When
+	// it is set, APIKey is its first key.
+	APIKeys []string
+	// BaseURL is OPENWEATHERMAP_BASE_URL, e.g. to go through a caching
+	// gateway. It defaults to the OpenWeatherMap API.
+	BaseURL string
+	// Timeout is OPENWEATHERMAP_TIMEOUT, how long a request to OpenWeatherMap
+	// may take, 10s by default.
+	Timeout time.Duration
+	// CacheTTL is WEATHER_CACHE_TTL, how long a weather report is reused for
+	// the same coordinates, 0 disables the cache. It defaults to 10m, since
+	// OpenWeatherMap updates the current weather about every 10 minutes.
+	CacheTTL time.Duration
+	// CacheJitter is WEATHER_CACHE_JITTER, the fraction of CacheTTL by which
+	// the lifetime of a report varies at random, so that the reports cached
+	// by a burst of requests do not expire together. It defaults to 0.1.
+	CacheJitter float64
+	// CacheWarmTop is WEATHER_CACHE_WARM_TOP, how many of the most
+	// requested coordinates are fetched again in the background shortly
+	// before their report expires, so that their requests always hit the
+	// cache. It defaults to 0, no background refresh.
+	CacheWarmTop int
+}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		fmt.Println(err)
-		return "can not get the weather information at the moment"
+// warmChecks is how many times per CacheTTL the expiring reports of the
+// popular coordinates are looked for.
+const warmChecks = 5
+
+const (
+	defaultTimeout  = 10 * time.Second
+	defaultCacheTTL = 10 * time.Minute
+)
+
+// loadConfig reads the Config with getenv, filling in the defaults of the
+// unset variables. An invalid duration is logged and replaced by its default.
+func loadConfig(getenv func(string) string) Config {
+	c := Config{
+		APIKey:   getenv("OPENWEATHERMAP_API_KEY"),
+		BaseURL:  strings.TrimRight(getenv("OPENWEATHERMAP_BASE_URL"), "/"),
+		Timeout:  duration(getenv, "OPENWEATHERMAP_TIMEOUT", defaultTimeout),
+		CacheTTL: duration(getenv, "WEATHER_CACHE_TTL", defaultCacheTTL),
+
+		CacheJitter:  fraction(getenv, "WEATHER_CACHE_JITTER", cache.DefaultJitter),
+		CacheWarmTop: count(getenv, "WEATHER_CACHE_WARM_TOP"),
+	}
+	if keys := weather.ParseKeys(getenv(weather.APIKeysEnv)); len(keys) > 0 {
+		c.APIKey, c.APIKeys = keys[0], keys
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = weather.DefaultBaseURL
 	}
-	defer resp.Body.Close()
+	if c.Timeout <= 0 {
+		slog.Warn("[sfn] OPENWEATHERMAP_TIMEOUT must be positive, using the default", "default", defaultTimeout)
+		c.Timeout = defaultTimeout
+	}
+	return c
+}
+
+// duration parses the duration in the variable key, e.g. "30s", or returns
+// fallback.
+func duration(getenv func(string) string, key string, fallback time.Duration) time.Duration {
+	v := getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		slog.Warn("[sfn] invalid duration, using the default", "key", key, "value", v, "default", fallback)
+		return fallback
+	}
+	return d
+}
 
-	body, err := io.ReadAll(resp.Body)
+// fraction parses the fraction between 0 and 1 in the variable key, e.g.
+// "0.2", or returns fallback.
+func fraction(getenv func(string) string, key string, fallback float64) float64 {
+	v := getenv(key)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || f > 1 {
+		slog.Warn("[sfn] invalid fraction, using the default", "key", key, "value", v, "default", fallback)
+		return fallback
+	}
+	return f
+}
+
+// count parses the non-negative integer in the variable key, or returns 0.
+func count(getenv func(string) string, key string) int {
+	v := getenv(key)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		slog.Warn("[sfn] invalid count, using 0", "key", key, "value", v)
+		return 0
+	}
+	return n
+}
+
+var (
+	config Config
+	client *weather.Client
+)
+
+// newClient returns an OpenWeatherMap client for the config. Its current
+// weather lookups are cached, so concurrent requests for a popular city share
+// one upstream call.
+func newClient(cfg Config) *weather.Client {
+	c := weather.NewClient(cfg.APIKey)
+	c.BaseURL = cfg.BaseURL
+	c.HTTPClient = httpx.NewClient(cfg.Timeout)
+	if len(cfg.APIKeys) > 1 {
+		c.Keys = weather.NewKeys(cfg.APIKeys, weather.KeyCooldown)
+	}
+	if cfg.CacheTTL > 0 {
+		c.CurrentCache = cache.New[*weather.Conditions](cfg.CacheTTL).WithJitter(cfg.CacheJitter)
+	}
+	return c
+}
+
+// requestOpenWeatherMapAPI returns the summary of the current weather at
+// the coordinates, or an *errs.ToolError.
+func requestOpenWeatherMapAPI(ctx context.Context, lat, lon float64) (string, error) {
+	if config.APIKey == "" {
+		return "", errs.New(errs.NotConfigured, "OPENWEATHERMAP_API_KEY is not set")
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return "", errs.New(errs.InvalidInput, fmt.Sprintf("%v,%v is not a valid coordinate, the latitude must be between -90 and 90 and the longitude between -180 and 180", lat, lon))
+	}
+
+	conditions, err := client.Current(ctx, lat, lon)
 	if err != nil {
-		fmt.Println(err)
-		return "can not get the weather information at the moment"
+		return "", upstreamError(err)
 	}
 
-	return string(body)
+	return conditions.Summary(), nil
+}
+
+// upstreamError maps an error of the OpenWeatherMap client to a coded error.
+func upstreamError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errs.Wrap(errs.UpstreamError, err, "the OpenWeatherMap API did not respond in time")
+	}
+	if errors.Is(err, ratelimit.ErrLimited) {
+		return errs.Wrap(errs.RateLimited, err, "the OpenWeatherMap calls per minute are used up, try again in a minute")
+	}
+	if errors.Is(err, circuit.ErrOpen) {
+		return errs.Wrap(errs.UpstreamError, err, "the OpenWeatherMap API is temporarily unavailable, try again in a minute")
+	}
+	var se *weather.StatusError
+	if errors.As(err, &se) {
+		switch se.StatusCode {
+		case http.StatusUnauthorized:
+			return errs.Wrap(errs.NotConfigured, err, "the OpenWeatherMap API key is invalid")
+		case http.StatusTooManyRequests:
+			return errs.Wrap(errs.RateLimited, err, "the OpenWeatherMap rate limit is exceeded, try again later")
+		}
+	}
+	return errs.Wrap(errs.UpstreamError, err, "can not get the weather information at the moment")
 }
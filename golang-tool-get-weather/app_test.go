@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/errs"
+	"github.com/yomorun/llm-function-calling-examples/internal/ratelimit"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/ai"
+	"github.com/yomorun/yomo/serverless/mock"
+)
+
+func TestRequestOpenWeatherMapAPIErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiKey   string
+		status   int
+		lat, lon float64
+		want     errs.Code
+	}{
+		{name: "missing api key", apiKey: "", status: http.StatusOK, lat: 48.85, lon: 2.35, want: errs.NotConfigured},
+		{name: "invalid api key", apiKey: "key", status: http.StatusUnauthorized, lat: 48.85, lon: 2.35, want: errs.NotConfigured},
+		{name: "latitude out of range", apiKey: "key", status: http.StatusOK, lat: 91, lon: 2.35, want: errs.InvalidInput},
+		{name: "longitude out of range", apiKey: "key", status: http.StatusOK, lat: 48.85, lon: -181, want: errs.InvalidInput},
+		{name: "rate limited", apiKey: "key", status: http.StatusTooManyRequests, lat: 48.85, lon: 2.35, want: errs.RateLimited},
+		{name: "server error", apiKey: "key", status: http.StatusInternalServerError, lat: 48.85, lon: 2.35, want: errs.UpstreamError},
+		{name: "malformed response", apiKey: "key", status: http.StatusOK, lat: 48.85, lon: 2.35, want: errs.UpstreamError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"cod":`))
+			}))
+			defer srv.Close()
+			useClient(t, tt.apiKey, srv.URL)
+
+			_, err := requestOpenWeatherMapAPI(context.Background(), tt.lat, tt.lon)
+			if got := errs.CodeOf(err); got != tt.want {
+				t.Errorf("requestOpenWeatherMapAPI() error = %v, want code %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestOpenWeatherMapAPIUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	useClient(t, "key", srv.URL)
+
+	_, err := requestOpenWeatherMapAPI(context.Background(), 48.85, 2.35)
+	if got := errs.CodeOf(err); got != errs.UpstreamError {
+		t.Errorf("requestOpenWeatherMapAPI() error = %v, want code %s", err, errs.UpstreamError)
+	}
+
+	// after a few failures in a row the calls fail without a request
+	for i := 0; i < 5; i++ {
+		_, err = requestOpenWeatherMapAPI(context.Background(), 48.85, 2.35)
+	}
+	if te := errs.As(err); te.Code != errs.UpstreamError || !strings.Contains(te.Message, "temporarily unavailable") {
+		t.Errorf("requestOpenWeatherMapAPI() with the breaker open error = %v", err)
+	}
+}
+
+func TestRequestOpenWeatherMapAPILimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Paris","weather":[{"description":"clear sky"}],"main":{"temp":20}}`))
+	}))
+	t.Cleanup(srv.Close)
+	useClient(t, "key", srv.URL)
+	client.Limiter = ratelimit.New(1, time.Hour)
+
+	if _, err := requestOpenWeatherMapAPI(context.Background(), 48.85, 2.35); err != nil {
+		t.Fatalf("requestOpenWeatherMapAPI() error = %v", err)
+	}
+	_, err := requestOpenWeatherMapAPI(context.Background(), 40.71, -74.01)
+	if got := errs.CodeOf(err); got != errs.RateLimited {
+		t.Errorf("requestOpenWeatherMapAPI() over the quota error = %v, want code %s", err, errs.RateLimited)
+	}
+}
+
+// useClient points the package config and client to baseURL for the
+// duration of the test.
+func useClient(t *testing.T, apiKey, baseURL string) {
+	t.Helper()
+	oldConfig, oldClient := config, client
+	t.Cleanup(func() { config, client = oldConfig, oldClient })
+
+	config = Config{APIKey: apiKey, BaseURL: baseURL, Timeout: defaultTimeout}
+	client = newClient(config)
+}
+
+func TestRequestOpenWeatherMapAPIKeys(t *testing.T) {
+	var used []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		used = append(used, r.URL.Query().Get("appid"))
+		w.Write([]byte(`{"name":"Paris","weather":[{"description":"clear sky"}],"main":{"temp":20}}`))
+	}))
+	t.Cleanup(srv.Close)
+	oldConfig, oldClient := config, client
+	t.Cleanup(func() { config, client = oldConfig, oldClient })
+	config = Config{APIKey: "key-a", APIKeys: []string{"key-a", "key-b"}, BaseURL: srv.URL, Timeout: defaultTimeout}
+	client = newClient(config)
+
+	for _, lat := range []float64{48.85, 40.71, 35.68} {
+		if _, err := requestOpenWeatherMapAPI(context.Background(), lat, 2.35); err != nil {
+			t.Fatalf("requestOpenWeatherMapAPI() error = %v", err)
+		}
+	}
+	if got := strings.Join(used, ","); got != "key-a,key-b,key-a" {
+		t.Errorf("keys used = %s, want key-a,key-b,key-a", got)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Config
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"OPENWEATHERMAP_API_KEY": "key"},
+			want: Config{APIKey: "key", BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheTTL: defaultCacheTTL, CacheJitter: cache.DefaultJitter},
+		},
+		{
+			name: "all set",
+			env: map[string]string{
+				"OPENWEATHERMAP_API_KEY":  "key",
+				"OPENWEATHERMAP_BASE_URL": "http://gateway.local/owm/",
+				"OPENWEATHERMAP_TIMEOUT":  "3s",
+				"WEATHER_CACHE_TTL":       "1m30s",
+				"WEATHER_CACHE_JITTER":    "0.25",
+				"WEATHER_CACHE_WARM_TOP":  "20",
+			},
+			want: Config{APIKey: "key", BaseURL: "http://gateway.local/owm", Timeout: 3 * time.Second, CacheTTL: 90 * time.Second, CacheJitter: 0.25, CacheWarmTop: 20},
+		},
+		{
+			name: "cache disabled",
+			env:  map[string]string{"WEATHER_CACHE_TTL": "0"},
+			want: Config{BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheJitter: cache.DefaultJitter},
+		},
+		{
+			name: "invalid durations",
+			env:  map[string]string{"OPENWEATHERMAP_TIMEOUT": "0s", "WEATHER_CACHE_TTL": "ten minutes"},
+			want: Config{BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheTTL: defaultCacheTTL, CacheJitter: cache.DefaultJitter},
+		},
+		{
+			name: "invalid jitters",
+			env:  map[string]string{"WEATHER_CACHE_JITTER": "10%"},
+			want: Config{BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheTTL: defaultCacheTTL, CacheJitter: cache.DefaultJitter},
+		},
+		{
+			name: "jitter out of range",
+			env:  map[string]string{"WEATHER_CACHE_JITTER": "1.5"},
+			want: Config{BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheTTL: defaultCacheTTL, CacheJitter: cache.DefaultJitter},
+		},
+		{
+			name: "several keys",
+			env:  map[string]string{"OPENWEATHERMAP_API_KEY": "single", "OPENWEATHERMAP_API_KEYS": "key-a, key-b"},
+			want: Config{APIKey: "key-a", APIKeys: []string{"key-a", "key-b"}, BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheTTL: defaultCacheTTL, CacheJitter: cache.DefaultJitter},
+		},
+		{
+			name: "invalid warm top",
+			env:  map[string]string{"WEATHER_CACHE_WARM_TOP": "-3"},
+			want: Config{BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheTTL: defaultCacheTTL, CacheJitter: cache.DefaultJitter},
+		},
+		{
+			name: "negative duration",
+			env:  map[string]string{"WEATHER_CACHE_TTL": "-1m"},
+			want: Config{BaseURL: weather.DefaultBaseURL, Timeout: defaultTimeout, CacheTTL: defaultCacheTTL, CacheJitter: cache.DefaultJitter},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := loadConfig(func(key string) string { return tt.env[key] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerPanic(t *testing.T) {
+	oldConfig, oldClient := config, client
+	t.Cleanup(func() { config, client = oldConfig, oldClient })
+	// a configured function whose client is missing panics on the lookup
+	config, client = Config{APIKey: "key"}, nil
+
+	ctx := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{\"city\":\"Paris\",\"latitude\":48.85,\"longitude\":2.35}"}`), 0x10)
+	Handler(ctx)
+
+	records := ctx.RecordsWritten()
+	if len(records) != 1 || records[0].Tag != ai.ReducerTag {
+		t.Fatalf("Handler() records = %v, want one result", records)
+	}
+	var fnCall ai.FunctionCall
+	if err := fnCall.FromBytes(records[0].Data); err != nil {
+		t.Fatal(err)
+	}
+	var r sfn.Result
+	if err := json.Unmarshal([]byte(fnCall.Result), &r); err != nil {
+		t.Fatalf("Handler() result %q is not an envelope: %v", fnCall.Result, err)
+	}
+	if r.OK || r.Error == nil || r.Error.Code != errs.Internal {
+		t.Errorf("Handler() result = %s, want an %s error", fnCall.Result, errs.Internal)
+	}
+}
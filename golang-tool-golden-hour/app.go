@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/solar"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the golden hour of a location on a date, the soft warm light after sunrise and before sunset that photographers look for. If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format and give the IANA time zone of the city. The function returns the morning and evening golden hours in the local time.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+	Date      string  `json:"date,omitempty" jsonschema:"description=The date in YYYY-MM-DD format. Defaults to today,example=2024-06-21"`
+	Timezone  string  `json:"timezone" jsonschema:"description=The time zone of the location in IANA Time Zone Database identifier format,example=Europe/Paris"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "golden-hour", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD9}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "date", msg.Date, "timezone", msg.Timezone)
+
+	result, err := GoldenHour(msg, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] GoldenHour error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the golden hour: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// GoldenHour resolves the date of p, today in its time zone by default, and
+// describes its golden hours.
+func GoldenHour(p Parameter, now time.Time) (string, error) {
+	if err := geo.ValidateCoordinate(p.Latitude, p.Longitude); err != nil {
+		return "", err
+	}
+	if p.Timezone == "" {
+		return "", errors.New("the time zone of the location is missing")
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return "", fmt.Errorf("unknown time zone %q", p.Timezone)
+	}
+
+	day := now.In(loc)
+	if p.Date != "" {
+		if day, err = time.ParseInLocation("2006-01-02", p.Date, loc); err != nil {
+			return "", fmt.Errorf("can not understand the date %q, please use YYYY-MM-DD", p.Date)
+		}
+	}
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	windows := GoldenWindows(p.Latitude, p.Longitude, midnight)
+	heading := fmt.Sprintf("golden hour at %v,%v on %s (%s)", p.Latitude, p.Longitude, midnight.Format("2006-01-02"), p.Timezone)
+	if len(windows) == 0 {
+		noon := solarNoon(p.Latitude, p.Longitude, midnight)
+		if solar.Elevation(noon, p.Latitude, p.Longitude) < solar.GoldenLow {
+			return fmt.Sprintf("There is no %s: the sun stays more than %g° below the horizon all day", heading, -solar.GoldenLow), nil
+		}
+		return fmt.Sprintf("There is no %s: the sun stays more than %g° above the horizon all day", heading, solar.GoldenHigh), nil
+	}
+
+	noon := solarNoon(p.Latitude, p.Longitude, midnight)
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		label := "evening"
+		switch {
+		case w.End.Before(noon):
+			label = "morning"
+		case !w.Start.After(noon):
+			// the sun peaks below GoldenHigh
+			label = "all day"
+		}
+		parts[i] = fmt.Sprintf("%s %s-%s", label, w.Start.Format("15:04"), w.End.Format("15:04"))
+	}
+	return fmt.Sprintf("The %s: %s", heading, strings.Join(parts, ", ")), nil
+}
+
+// GoldenWindows returns the golden hours of the day beginning at midnight,
+// clipped to the day.
+func GoldenWindows(lat, lon float64, midnight time.Time) []solar.Window {
+	return solar.GoldenHours(lat, lon, midnight, midnight.AddDate(0, 0, 1))
+}
+
+// solarNoon returns the time of the highest sun elevation of the day
+// beginning at midnight.
+func solarNoon(lat, lon float64, midnight time.Time) time.Time {
+	noon := midnight
+	for t := midnight; t.Before(midnight.AddDate(0, 0, 1)); t = t.Add(time.Minute) {
+		if solar.Elevation(t, lat, lon) > solar.Elevation(noon, lat, lon) {
+			noon = t
+		}
+	}
+	return noon
+}
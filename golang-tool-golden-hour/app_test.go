@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoldenHour(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Parameter
+		want string
+	}{
+		{
+			name: "paris",
+			p:    Parameter{Latitude: 48.8566, Longitude: 2.3522, Date: "2024-06-21", Timezone: "Europe/Paris"},
+			want: "The golden hour at 48.8566,2.3522 on 2024-06-21 (Europe/Paris): morning 05:21-06:37, evening 21:07-22:23",
+		},
+		{
+			// the sun peaks at -3°
+			name: "polar twilight",
+			p:    Parameter{Latitude: 69.65, Longitude: 18.96, Date: "2024-12-21", Timezone: "Europe/Oslo"},
+			want: "all day",
+		},
+		{
+			name: "polar night",
+			p:    Parameter{Latitude: 78.22, Longitude: 15.65, Date: "2024-12-21", Timezone: "Arctic/Longyearbyen"},
+			want: "the sun stays more than 4° below the horizon all day",
+		},
+		{
+			name: "midnight sun",
+			p:    Parameter{Latitude: 78.22, Longitude: 15.65, Date: "2024-06-21", Timezone: "Arctic/Longyearbyen"},
+			want: "the sun stays more than 6° above the horizon all day",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GoldenHour(tt.p, time.Now())
+			if err != nil {
+				t.Fatalf("GoldenHour() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("GoldenHour() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenHourInvalid(t *testing.T) {
+	for _, p := range []Parameter{
+		{Latitude: 91, Timezone: "UTC"},
+		{Longitude: 181, Timezone: "UTC"},
+		{Date: "2024-06-21"},
+		{Timezone: "Mars/Olympus"},
+		{Date: "21/06/2024", Timezone: "UTC"},
+	} {
+		if _, err := GoldenHour(p, time.Now()); err == nil {
+			t.Errorf("GoldenHour(%+v) should fail", p)
+		}
+	}
+}
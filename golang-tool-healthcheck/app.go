@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Check the health of the functions of the deployment for an operator: whether each function has the environment variables it needs, e.g. its API key, and optionally whether the API it calls is reachable. The function returns a report with the status of each function: ok, degraded when its API is unreachable, or failed when it is not configured.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Tool string `json:"tool,omitempty" jsonschema:"description=The name of a single registered function to check. Defaults to all the registered ones"`
+	Ping bool   `json:"ping,omitempty" jsonschema:"description=Also probe the APIs the functions call. Defaults to false"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "healthcheck", Description: Description(), InputSchema: InputSchema()})
+	// the other functions run in processes of their own, their variables
+	// and upstreams come from the manifest of the deployment
+	if err := registry.LoadManifest(); err != nil {
+		slog.Warn("[sfn] LoadManifest error", "err", err)
+	}
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF5}
+}
+
+// pingTimeout is how long a probe of an upstream may take, short so that a
+// health check answers quickly even with every upstream down.
+const pingTimeout = 3 * time.Second
+
+var pinger = httpx.NewClient(pingTimeout)
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "tool", msg.Tool, "ping", msg.Ping)
+
+	tools := registry.Tools()
+	if msg.Tool != "" {
+		t, ok := registry.Lookup(msg.Tool)
+		if !ok {
+			sfn.WriteText(ctx, fmt.Sprintf("can not check %q: no such function is registered", msg.Tool))
+			return
+		}
+		tools = []registry.Tool{t}
+	}
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	var probe func(context.Context, string) error
+	if msg.Ping {
+		probe = Ping
+	}
+	result := Report(Inspect(reqCtx, tools, os.Getenv, probe))
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Status is the health of a function.
+type Status int
+
+const (
+	OK Status = iota
+	// Degraded is a configured function whose upstream did not answer.
+	Degraded
+	// Failed is a function missing some of its environment variables.
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Degraded:
+		return "degraded"
+	case Failed:
+		return "failed"
+	}
+	return "ok"
+}
+
+// Check is the health of one function.
+type Check struct {
+	Tool   string
+	Status Status
+	// Missing are the unset environment variables of the function.
+	Missing  []string
+	Upstream string
+	// Reachable is whether the upstream answered, nil when it was not
+	// probed.
+	Reachable *bool
+	// Problem is why the upstream is unreachable.
+	Problem string
+}
+
+// Inspect checks the tools: their environment variables with getenv, and
+// their upstreams with probe when it is not nil. Each upstream is probed
+// once, concurrently, however many tools call it.
+func Inspect(ctx context.Context, tools []registry.Tool, getenv func(string) string, probe func(context.Context, string) error) []Check {
+	problems := make(map[string]error)
+	if probe != nil {
+		var (
+			wg sync.WaitGroup
+			// mu guards problems, written by the probes
+			mu sync.Mutex
+			// seen are the upstreams already probed, only read by the loop
+			seen = make(map[string]bool)
+		)
+		for _, t := range tools {
+			if t.Upstream == "" || seen[t.Upstream] {
+				continue
+			}
+			seen[t.Upstream] = true
+			wg.Add(1)
+			go func(upstream string) {
+				defer wg.Done()
+				err := probe(ctx, upstream)
+				mu.Lock()
+				problems[upstream] = err
+				mu.Unlock()
+			}(t.Upstream)
+		}
+		wg.Wait()
+	}
+
+	checks := make([]Check, 0, len(tools))
+	for _, t := range tools {
+		c := Check{Tool: t.Name, Upstream: t.Upstream, Missing: t.MissingEnv(getenv)}
+		if err, probed := problems[t.Upstream]; probed {
+			reachable := err == nil
+			c.Reachable = &reachable
+			if err != nil {
+				c.Problem = err.Error()
+			}
+		}
+		switch {
+		case len(c.Missing) > 0:
+			c.Status = Failed
+		case c.Reachable != nil && !*c.Reachable:
+			c.Status = Degraded
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// Ping probes an upstream with a HEAD request to its base URL. Any answer
+// but a server error means it is up, since a base URL often answers 404 or
+// 401 without a path and a key.
+func Ping(ctx context.Context, upstream string) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, upstream, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := pinger.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return nil
+}
+
+// Report writes the checks as a summary line followed by one line per
+// function, e.g.
+//
+//	2 functions: 1 ok, 0 degraded, 1 failed
+//	- get-weather: failed, OPENWEATHERMAP_API_KEY is not set, upstream https://api.openweathermap.org
+//	- zipcode: ok, upstream https://api.zippopotam.us is reachable
+//
+// The values of the environment variables are never written, only whether
+// they are set.
+func Report(checks []Check) string {
+	var counts [3]int
+	for _, c := range checks {
+		counts[c.Status]++
+	}
+
+	var b strings.Builder
+	noun := "functions"
+	if len(checks) == 1 {
+		noun = "function"
+	}
+	fmt.Fprintf(&b, "%d %s: %d ok, %d degraded, %d failed", len(checks), noun, counts[OK], counts[Degraded], counts[Failed])
+	for _, c := range checks {
+		fmt.Fprintf(&b, "\n- %s: %s", c.Tool, c.Status)
+		if len(c.Missing) > 0 {
+			verb := "is"
+			if len(c.Missing) > 1 {
+				verb = "are"
+			}
+			fmt.Fprintf(&b, ", %s %s not set", strings.Join(c.Missing, ", "), verb)
+		}
+		switch {
+		case c.Upstream == "":
+		case c.Reachable == nil:
+			b.WriteString(", upstream " + c.Upstream)
+		case *c.Reachable:
+			b.WriteString(", upstream " + c.Upstream + " is reachable")
+		default:
+			b.WriteString(", upstream " + c.Upstream + " is unreachable: " + c.Problem)
+		}
+	}
+	return b.String()
+}
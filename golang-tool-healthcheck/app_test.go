@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+var tools = []registry.Tool{
+	{Name: "get-weather", Env: []string{"OPENWEATHERMAP_API_KEY"}, Upstream: "https://api.openweathermap.org"},
+	{Name: "send-mail-smtp", Env: []string{"SMTP_HOST", "SMTP_PORT", "FROM_EMAIL"}},
+	{Name: "tides", Env: []string{"WORLDTIDES_API_KEY"}, Upstream: "https://www.worldtides.info"},
+	{Name: "uv-index", Env: []string{"OPENWEATHERMAP_API_KEY"}, Upstream: "https://api.openweathermap.org"},
+	{Name: "word-count"},
+	{Name: "zipcode", Upstream: "https://api.zippopotam.us"},
+}
+
+var env = map[string]string{
+	"OPENWEATHERMAP_API_KEY": "secret-key",
+	"SMTP_HOST":              "smtp.example.com",
+	"FROM_EMAIL":             "  ",
+	"WORLDTIDES_API_KEY":     "other-secret",
+}
+
+func getenv(name string) string { return env[name] }
+
+func TestInspectConfiguration(t *testing.T) {
+	got := Report(Inspect(context.Background(), tools, getenv, nil))
+	want := `6 functions: 5 ok, 0 degraded, 1 failed
+- get-weather: ok, upstream https://api.openweathermap.org
+- send-mail-smtp: failed, SMTP_PORT, FROM_EMAIL are not set
+- tides: ok, upstream https://www.worldtides.info
+- uv-index: ok, upstream https://api.openweathermap.org
+- word-count: ok
+- zipcode: ok, upstream https://api.zippopotam.us`
+	if got != want {
+		t.Errorf("Report() = %s\nwant %s", got, want)
+	}
+}
+
+func TestInspectProbes(t *testing.T) {
+	var probes atomic.Int32
+	probe := func(ctx context.Context, upstream string) error {
+		probes.Add(1)
+		if upstream == "https://www.worldtides.info" {
+			return errors.New("dial tcp: i/o timeout")
+		}
+		return nil
+	}
+
+	checks := Inspect(context.Background(), tools, getenv, probe)
+	// the upstream of get-weather and uv-index is probed once
+	if n := probes.Load(); n != 3 {
+		t.Errorf("Inspect() made %d probes, want 3", n)
+	}
+
+	got := Report(checks)
+	want := `6 functions: 4 ok, 1 degraded, 1 failed
+- get-weather: ok, upstream https://api.openweathermap.org is reachable
+- send-mail-smtp: failed, SMTP_PORT, FROM_EMAIL are not set
+- tides: degraded, upstream https://www.worldtides.info is unreachable: dial tcp: i/o timeout
+- uv-index: ok, upstream https://api.openweathermap.org is reachable
+- word-count: ok
+- zipcode: ok, upstream https://api.zippopotam.us is reachable`
+	if got != want {
+		t.Errorf("Report() = %s\nwant %s", got, want)
+	}
+}
+
+func TestInspectUnconfiguredAndUnreachable(t *testing.T) {
+	// a missing key fails the function even if its upstream is down
+	tool := registry.Tool{Name: "tides", Env: []string{"WORLDTIDES_API_KEY"}, Upstream: "https://www.worldtides.info"}
+	checks := Inspect(context.Background(), []registry.Tool{tool}, func(string) string { return "" }, func(context.Context, string) error {
+		return errors.New("connection refused")
+	})
+	if len(checks) != 1 || checks[0].Status != Failed {
+		t.Fatalf("Inspect() = %+v, want failed", checks)
+	}
+	want := "1 function: 0 ok, 0 degraded, 1 failed\n- tides: failed, WORLDTIDES_API_KEY is not set, upstream https://www.worldtides.info is unreachable: connection refused"
+	if got := Report(checks); got != want {
+		t.Errorf("Report() = %s\nwant %s", got, want)
+	}
+}
+
+func TestInspectRotatedKeys(t *testing.T) {
+	// the weather functions work with a list of keys to rotate and no
+	// single key
+	tool := registry.Tool{Name: "get-weather", Env: []string{weather.KeyEnv}, Upstream: "https://api.openweathermap.org"}
+	rotated := func(name string) string {
+		if name == weather.APIKeysEnv {
+			return "key-1,key-2"
+		}
+		return ""
+	}
+	want := "1 function: 1 ok, 0 degraded, 0 failed\n- get-weather: ok, upstream https://api.openweathermap.org"
+	if got := Report(Inspect(context.Background(), []registry.Tool{tool}, rotated, nil)); got != want {
+		t.Errorf("Report() = %s\nwant %s", got, want)
+	}
+
+	want = "1 function: 0 ok, 0 degraded, 1 failed\n- get-weather: failed, OPENWEATHERMAP_API_KEY or OPENWEATHERMAP_API_KEYS is not set, upstream https://api.openweathermap.org"
+	if got := Report(Inspect(context.Background(), []registry.Tool{tool}, func(string) string { return "" }, nil)); got != want {
+		t.Errorf("Report() = %s\nwant %s", got, want)
+	}
+}
+
+func TestInspectManifest(t *testing.T) {
+	// the functions of the deployment, which run in other processes
+	manifest := `[
+  {"type": "function", "function": {"name": "get-weather", "description": "Get the weather"}, "env": ["OPENWEATHERMAP_API_KEY|OPENWEATHERMAP_API_KEYS"], "upstream": "https://api.openweathermap.org"},
+  {"type": "function", "function": {"name": "tides", "description": "Get the tides"}, "env": ["WORLDTIDES_API_KEY"], "upstream": "https://www.worldtides.info"},
+  {"type": "function", "function": {"name": "word-count", "description": "Count the words"}}
+]`
+	r := registry.New()
+	r.Register(registry.Tool{Name: "healthcheck"})
+	if err := r.LoadOpenAI([]byte(manifest)); err != nil {
+		t.Fatal(err)
+	}
+
+	deployed := map[string]string{"OPENWEATHERMAP_API_KEYS": "key-1,key-2"}
+	got := Report(Inspect(context.Background(), r.Tools(), func(name string) string { return deployed[name] }, nil))
+	want := `4 functions: 3 ok, 0 degraded, 1 failed
+- get-weather: ok, upstream https://api.openweathermap.org
+- healthcheck: ok
+- tides: failed, WORLDTIDES_API_KEY is not set, upstream https://www.worldtides.info
+- word-count: ok`
+	if got != want {
+		t.Errorf("Report() = %s\nwant %s", got, want)
+	}
+}
+
+func TestReportHidesValues(t *testing.T) {
+	got := Report(Inspect(context.Background(), tools, getenv, nil))
+	for _, v := range env {
+		if v != "  " && strings.Contains(got, v) {
+			t.Errorf("Report() leaks the value %q", v)
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		status int
+		ok     bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusNotFound, true},
+		{http.StatusUnauthorized, true},
+		{http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodHead {
+				t.Errorf("Ping() sent %s, want HEAD", r.Method)
+			}
+			w.WriteHeader(tt.status)
+		}))
+		err := Ping(context.Background(), srv.URL)
+		srv.Close()
+		if (err == nil) != tt.ok {
+			t.Errorf("Ping() of a %d = %v, want ok %v", tt.status, err, tt.ok)
+		}
+	}
+
+	if err := Ping(context.Background(), "http://127.0.0.1:1"); err == nil {
+		t.Error("Ping() of a closed port should fail")
+	}
+}
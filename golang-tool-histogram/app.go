@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/stats"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Describe the distribution of a list of numbers, e.g. response times or scores: the count, minimum, maximum and mean, the 25th, 50th, 75th, 90th and 99th percentiles, and a text histogram of the counts in equal-width buckets. The function returns the statistics and the histogram.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Numbers []float64 `json:"numbers" jsonschema:"description=The numbers of the dataset"`
+	Buckets int       `json:"buckets,omitempty" jsonschema:"description=The number of buckets of the histogram. Defaults to 10,minimum=1,maximum=50"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "histogram", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x103}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "numbers", len(msg.Numbers), "buckets", msg.Buckets)
+
+	result, err := Histogram(msg.Numbers, msg.Buckets)
+	if err != nil {
+		slog.Warn("[sfn] Histogram error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not describe the numbers: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+const (
+	defaultBuckets = 10
+	maxBuckets     = 50
+	// barWidth is the length of the bar of the fullest bucket.
+	barWidth = 40
+)
+
+// percentiles are the ones reported.
+var percentiles = []float64{25, 50, 75, 90, 99}
+
+// ErrEmpty is returned for a dataset without numbers.
+var ErrEmpty = errors.New("the dataset is empty")
+
+// Histogram describes numbers with their statistics and buckets, 0 buckets
+// meaning the default.
+func Histogram(numbers []float64, buckets int) (string, error) {
+	if len(numbers) == 0 {
+		return "", ErrEmpty
+	}
+	if buckets == 0 {
+		buckets = defaultBuckets
+	}
+	if buckets < 1 || buckets > maxBuckets {
+		return "", fmt.Errorf("the histogram has 1 to %d buckets, got %d", maxBuckets, buckets)
+	}
+	for _, x := range numbers {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return "", fmt.Errorf("%v is not a finite number", x)
+		}
+	}
+
+	sorted := stats.Sorted(numbers)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d values, min %s, max %s, mean %s\n", len(sorted), stats.Format(sorted[0]), stats.Format(sorted[len(sorted)-1]), stats.Format(stats.Mean(sorted)))
+	for i, p := range percentiles {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "p%g %s", p, stats.Format(stats.Percentile(sorted, p)))
+	}
+	b.WriteString("\n")
+
+	bins := Bucket(sorted, buckets)
+	labels := make([]string, len(bins))
+	width, most := 0, 0
+	for i, bin := range bins {
+		closing := ")"
+		if i == len(bins)-1 {
+			closing = "]"
+		}
+		labels[i] = fmt.Sprintf("[%s, %s%s", stats.Format(bin.Low), stats.Format(bin.High), closing)
+		width = max(width, len(labels[i]))
+		most = max(most, bin.Count)
+	}
+	for i, bin := range bins {
+		bar := int(math.Round(float64(bin.Count) / float64(most) * barWidth))
+		if bin.Count > 0 {
+			bar = max(bar, 1)
+		}
+		fmt.Fprintf(&b, "\n%-*s ", width, labels[i])
+		if bar > 0 {
+			b.WriteString(strings.Repeat("█", bar) + " ")
+		}
+		b.WriteString(strconv.Itoa(bin.Count))
+	}
+	return b.String(), nil
+}
+
+// Bin is a bucket of the histogram, from Low included to High excluded but
+// for the last bucket which includes High.
+type Bin struct {
+	Low, High float64
+	Count     int
+}
+
+// Bucket counts sorted in n buckets of equal width from its minimum to its
+// maximum. A dataset of a single distinct value has a single bucket.
+func Bucket(sorted []float64, n int) []Bin {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if lo == hi {
+		return []Bin{{Low: lo, High: hi, Count: len(sorted)}}
+	}
+
+	// a range wider than the largest float64, e.g. from -1e308 to 1e308,
+	// is divided before the subtraction so that the width stays finite
+	scale := 1.0
+	if math.IsInf(hi-lo, 0) {
+		scale = float64(n)
+	}
+	width := (hi/scale - lo/scale) / float64(n)
+	bins := make([]Bin, n)
+	for i := range bins {
+		bins[i].Low = (lo/scale + float64(i)*width) * scale
+		bins[i].High = (lo/scale + float64(i+1)*width) * scale
+	}
+	bins[0].Low, bins[n-1].High = lo, hi
+	for _, x := range sorted {
+		i := min(int((x/scale-lo/scale)/width), n-1)
+		bins[i].Count++
+	}
+	return bins
+}
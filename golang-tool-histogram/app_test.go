@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// latencies is a dataset of 20 response times in milliseconds.
+var latencies = []float64{12, 15, 11, 18, 22, 14, 13, 95, 17, 16, 19, 21, 14, 12, 13, 30, 16, 15, 18, 45}
+
+func TestBucket(t *testing.T) {
+	got := Bucket([]float64{0, 1, 2, 2.5, 5, 9.99, 10}, 4)
+	want := []Bin{
+		{Low: 0, High: 2.5, Count: 3},
+		{Low: 2.5, High: 5, Count: 1},
+		{Low: 5, High: 7.5, Count: 1},
+		{Low: 7.5, High: 10, Count: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Bucket() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Bucket()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBucketHugeRange(t *testing.T) {
+	// the width of the range overflows a float64
+	got := Bucket([]float64{-1e308, 0, 1e308}, 5)
+	if len(got) != 5 || got[0].Low != -1e308 || got[4].High != 1e308 {
+		t.Fatalf("Bucket() = %+v", got)
+	}
+	counts := []int{1, 0, 1, 0, 1}
+	for i, bin := range got {
+		if bin.Count != counts[i] || math.IsInf(bin.Low, 0) || math.IsInf(bin.High, 0) {
+			t.Errorf("Bucket()[%d] = %+v, want a finite bucket of %d", i, bin, counts[i])
+		}
+	}
+
+	if _, err := Histogram([]float64{-1e308, 1e308}, 5); err != nil {
+		t.Errorf("Histogram() of a huge range error = %v", err)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	got, err := Histogram(latencies, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"20 values, min 11, max 95, mean 21.8",
+		"p25 13.75, p50 16, p75 19.5, p90 31.5, p99 85.5",
+		"",
+		"[11, 32) ████████████████████████████████████████ 18",
+		"[32, 53) ██ 1",
+		"[53, 74) 0",
+		"[74, 95] ██ 1",
+	}, "\n")
+	if got != want {
+		t.Errorf("Histogram() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogramSingleValue(t *testing.T) {
+	got, err := Histogram([]float64{7, 7, 7}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "3 values, min 7, max 7, mean 7\np25 7, p50 7, p75 7, p90 7, p99 7\n\n[7, 7] ████████████████████████████████████████ 3"
+	if got != want {
+		t.Errorf("Histogram() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogramErrors(t *testing.T) {
+	if _, err := Histogram(nil, 5); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Histogram(nil) error = %v, want ErrEmpty", err)
+	}
+	if _, err := Histogram(latencies, 51); err == nil {
+		t.Error("Histogram() with 51 buckets should fail")
+	}
+	if _, err := Histogram([]float64{1, math.Inf(1)}, 5); err == nil {
+		t.Error("Histogram() with an infinity should fail")
+	}
+}
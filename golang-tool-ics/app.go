@@ -0,0 +1,132 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/contentline"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Create a calendar event as an iCalendar (.ics) file that the user can import into Google Calendar, Outlook or Apple Calendar. The start and end are in RFC 3339 format with the UTC offset of the user, e.g. 2024-09-20T14:00:00+02:00. The function returns the content of the .ics file.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Title       string `json:"title" jsonschema:"description=The title of the event"`
+	Start       string `json:"start" jsonschema:"description=The start in RFC 3339 format,example=2024-09-20T14:00:00+02:00"`
+	End         string `json:"end" jsonschema:"description=The end in RFC 3339 format,example=2024-09-20T15:30:00+02:00"`
+	Location    string `json:"location,omitempty" jsonschema:"description=Where the event takes place"`
+	Description string `json:"description,omitempty" jsonschema:"description=The details of the event"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "ics", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD6}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "title", msg.Title, "start", msg.Start, "end", msg.End)
+
+	ics, err := Event(msg, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] Event error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not create the calendar event: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, ics)
+}
+
+// maxTextLength bounds the title, location and description in characters.
+const maxTextLength = 2000
+
+// utcFormat is the UTC date-time form of RFC 5545, section 3.3.5.
+const utcFormat = "20060102T150405Z"
+
+// Event returns an iCalendar object with one VEVENT. The times are in UTC, so
+// calendars show them in the time zone of the reader. now is the DTSTAMP, and
+// the UID is derived from the event so that importing it twice updates it
+// instead of duplicating it.
+func Event(p Parameter, now time.Time) (string, error) {
+	title := strings.TrimSpace(p.Title)
+	if title == "" {
+		return "", errors.New("the event needs a title")
+	}
+	for _, text := range []string{title, p.Location, p.Description} {
+		if utf8.RuneCountInString(text) > maxTextLength {
+			return "", fmt.Errorf("the title, location and description are limited to %d characters", maxTextLength)
+		}
+	}
+	start, err := time.Parse(time.RFC3339, strings.TrimSpace(p.Start))
+	if err != nil {
+		return "", fmt.Errorf("the start %q is not in RFC 3339 format like 2024-09-20T14:00:00+02:00", p.Start)
+	}
+	end, err := time.Parse(time.RFC3339, strings.TrimSpace(p.End))
+	if err != nil {
+		return "", fmt.Errorf("the end %q is not in RFC 3339 format like 2024-09-20T15:30:00+02:00", p.End)
+	}
+	if !end.After(start) {
+		return "", fmt.Errorf("the end %s is not after the start %s", p.End, p.Start)
+	}
+
+	start, end = start.UTC(), end.UTC()
+	sum := sha256.Sum256([]byte(title + "\x00" + start.Format(utcFormat) + "\x00" + end.Format(utcFormat)))
+
+	var w contentline.Writer
+	w.Line("BEGIN:VCALENDAR")
+	w.Line("VERSION:2.0")
+	w.Line("PRODID:-//YoMo//LLM Function Calling ics//EN")
+	w.Line("CALSCALE:GREGORIAN")
+	w.Line("BEGIN:VEVENT")
+	w.Line("UID:" + hex.EncodeToString(sum[:16]) + "@llm-function-calling-examples")
+	w.Line("DTSTAMP:" + now.UTC().Format(utcFormat))
+	w.Line("DTSTART:" + start.Format(utcFormat))
+	w.Line("DTEND:" + end.Format(utcFormat))
+	w.Line("SUMMARY:" + contentline.Escape(title))
+	if loc := strings.TrimSpace(p.Location); loc != "" {
+		w.Line("LOCATION:" + contentline.Escape(loc))
+	}
+	if desc := strings.TrimSpace(p.Description); desc != "" {
+		w.Line("DESCRIPTION:" + contentline.Escape(desc))
+	}
+	w.Line("END:VEVENT")
+	w.Line("END:VCALENDAR")
+	return w.String(), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 9, 1, 8, 30, 0, 0, time.UTC)
+
+func TestEvent(t *testing.T) {
+	got, err := Event(Parameter{
+		Title:       "Design review; Q4, final",
+		Start:       "2024-09-20T14:00:00+02:00",
+		End:         "2024-09-20T15:30:00+02:00",
+		Location:    "Room 4.2, Main St. 1",
+		Description: "Agenda:\n1. Mockups\n2. C:\\specs\\v2 — sign-off by the whole team before the release",
+	}, now)
+	if err != nil {
+		t.Fatalf("Event() error = %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/event.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the golden file is stored with \n line breaks
+	if strings.ReplaceAll(got, "\r\n", "\n") != string(want) {
+		t.Errorf("Event() =\n%s\nwant\n%s", got, want)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n")
+	for _, line := range lines {
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("line %q is not terminated by CRLF", line)
+		}
+		if len(line) > 75 {
+			t.Errorf("line %q is longer than 75 octets", line)
+		}
+	}
+}
+
+func TestEventInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Parameter
+	}{
+		{"no title", Parameter{Title: " ", Start: "2024-09-20T14:00:00Z", End: "2024-09-20T15:00:00Z"}},
+		{"end before start", Parameter{Title: "x", Start: "2024-09-20T14:00:00Z", End: "2024-09-20T13:00:00Z"}},
+		{"empty event", Parameter{Title: "x", Start: "2024-09-20T14:00:00Z", End: "2024-09-20T16:00:00+02:00"}},
+		{"no offset", Parameter{Title: "x", Start: "2024-09-20T14:00:00", End: "2024-09-20T15:00:00Z"}},
+		{"not a time", Parameter{Title: "x", Start: "2024-09-20T14:00:00Z", End: "tomorrow"}},
+		{"too long", Parameter{Title: "x", Start: "2024-09-20T14:00:00Z", End: "2024-09-20T15:00:00Z", Description: strings.Repeat("a", maxTextLength+1)}},
+	}
+	for _, tt := range tests {
+		if _, err := Event(tt.p, now); err == nil {
+			t.Errorf("%s: Event() should fail", tt.name)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Extract values from a JSON document with a JSONPath query, e.g. "$.store.book[0].title" or "$.items[*].price". The query supports keys after a dot or in brackets like ['first name'], array indexes like [0] or [-1] for the last element, and the wildcards .* and [*]. Pass the JSON text unchanged. The function returns the matched values as a JSON array.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	JSON  string `json:"json" jsonschema:"description=The JSON document to query"`
+	Query string `json:"query" jsonschema:"description=The JSONPath query starting with $,example=$.store.book[*].title"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "json-query", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xEF}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "query", msg.Query, "json_bytes", len(msg.JSON))
+
+	path, err := ParsePath(msg.Query)
+	if err != nil {
+		slog.Warn("[sfn] ParsePath error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not query the JSON: %v", err))
+		return
+	}
+	values, err := path.Eval([]byte(msg.JSON))
+	if err != nil {
+		slog.Warn("[sfn] Eval error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not query the JSON: %v", err))
+		return
+	}
+
+	result := fmt.Sprintf("%s matched %d value(s): %s", path, len(values), joinValues(values))
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// stepKind is the kind of a step of a path.
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+)
+
+// step selects children of a JSON value: the value of a key of an object,
+// an element of an array, or every child of either.
+type step struct {
+	kind  stepKind
+	key   string
+	index int
+}
+
+func (s step) String() string {
+	switch s.kind {
+	case stepIndex:
+		return fmt.Sprintf("[%d]", s.index)
+	case stepWildcard:
+		return "[*]"
+	}
+	if identifier(s.key) {
+		return "." + s.key
+	}
+	return "[" + strconv.Quote(s.key) + "]"
+}
+
+// Path is a parsed JSONPath query.
+type Path []step
+
+// String returns the path in its canonical form, e.g. `$.a["b c"][0][*]`.
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, s := range p {
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+// ErrSyntax is returned by ParsePath for a query it can not parse.
+var ErrSyntax = errors.New("invalid query")
+
+// ParsePath parses a JSONPath query: the root $ followed by .key, ['key']
+// or ["key"], [index] with a negative index counting from the end, and the
+// wildcards .* and [*]. A query without the leading $, e.g. "a.b[0]", is
+// relative to the root.
+func ParsePath(query string) (Path, error) {
+	q := strings.TrimSpace(query)
+	switch {
+	case q == "":
+		return nil, fmt.Errorf("%w: the query is empty", ErrSyntax)
+	case q[0] == '$':
+		q = q[1:]
+	case q[0] != '.' && q[0] != '[':
+		q = "." + q
+	}
+
+	p := &parser{query: query, rest: q, offset: len(query) - len(q)}
+	var path Path
+	for p.rest != "" {
+		s, err := p.step()
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, s)
+	}
+	return path, nil
+}
+
+// parser consumes a query one step at a time, offset is the position of
+// rest in query for the error messages.
+type parser struct {
+	query  string
+	rest   string
+	offset int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w at position %d of %q: %s", ErrSyntax, p.offset+1, p.query, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) advance(n int) {
+	p.rest = p.rest[n:]
+	p.offset += n
+}
+
+func (p *parser) step() (step, error) {
+	switch p.rest[0] {
+	case '.':
+		p.advance(1)
+		if strings.HasPrefix(p.rest, ".") {
+			return step{}, p.errorf("the recursive descent .. is not supported")
+		}
+		if strings.HasPrefix(p.rest, "*") {
+			p.advance(1)
+			return step{kind: stepWildcard}, nil
+		}
+		n := 0
+		for n < len(p.rest) && p.rest[n] != '.' && p.rest[n] != '[' && p.rest[n] != ']' {
+			n++
+		}
+		key := p.rest[:n]
+		if !identifier(key) {
+			return step{}, p.errorf("expected a key after the dot, use ['...'] for keys with spaces or symbols")
+		}
+		p.advance(n)
+		return step{kind: stepKey, key: key}, nil
+	case '[':
+		return p.bracket()
+	}
+	return step{}, p.errorf("expected . or [")
+}
+
+// bracket parses [*], [index], ['key'] or ["key"].
+func (p *parser) bracket() (step, error) {
+	p.advance(1)
+	p.skipSpaces()
+	var s step
+	switch {
+	case p.rest == "":
+		return step{}, p.errorf("unclosed [")
+	case p.rest[0] == '*':
+		p.advance(1)
+		s = step{kind: stepWildcard}
+	case p.rest[0] == '\'' || p.rest[0] == '"':
+		key, err := p.quoted()
+		if err != nil {
+			return step{}, err
+		}
+		s = step{kind: stepKey, key: key}
+	default:
+		n := 0
+		if p.rest[0] == '-' {
+			n++
+		}
+		for n < len(p.rest) && p.rest[n] >= '0' && p.rest[n] <= '9' {
+			n++
+		}
+		index, err := strconv.Atoi(p.rest[:n])
+		if err != nil {
+			return step{}, p.errorf("expected an index, * or a quoted key in brackets")
+		}
+		p.advance(n)
+		s = step{kind: stepIndex, index: index}
+	}
+	p.skipSpaces()
+	if !strings.HasPrefix(p.rest, "]") {
+		if strings.HasPrefix(p.rest, ",") || strings.HasPrefix(p.rest, ":") {
+			return step{}, p.errorf("unions and slices are not supported")
+		}
+		return step{}, p.errorf("expected ]")
+	}
+	p.advance(1)
+	return s, nil
+}
+
+// quoted parses a key in single or double quotes, a backslash escapes the
+// quote or a backslash.
+func (p *parser) quoted() (string, error) {
+	quote := p.rest[0]
+	var b strings.Builder
+	for i := 1; i < len(p.rest); i++ {
+		c := p.rest[i]
+		switch {
+		case c == quote:
+			p.advance(i + 1)
+			return b.String(), nil
+		case c == '\\' && i+1 < len(p.rest):
+			i++
+			b.WriteByte(p.rest[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated quoted key")
+}
+
+func (p *parser) skipSpaces() {
+	n := len(p.rest) - len(strings.TrimLeft(p.rest, " "))
+	p.advance(n)
+}
+
+// identifier reports whether key can follow a dot: letters, digits, _ and
+// -, not starting with a digit or -.
+func identifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r > 0x7f:
+		case i > 0 && (r == '-' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ErrNoMatch is returned by Eval when nothing matches the path.
+var ErrNoMatch = errors.New("nothing matches")
+
+// Eval returns the values of doc matching the path in document order, each
+// as compact JSON. Steps that do not apply, e.g. a key of an array or an
+// index out of range, match nothing, and a path matching nothing at all
+// fails with ErrNoMatch naming the longest prefix that matched.
+func (p Path) Eval(doc []byte) ([]json.RawMessage, error) {
+	if !json.Valid(doc) {
+		var v any
+		err := json.Unmarshal(doc, &v)
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	nodes := []json.RawMessage{bytes.TrimSpace(doc)}
+	for i, s := range p {
+		var next []json.RawMessage
+		for _, node := range nodes {
+			children, err := s.apply(node)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, children...)
+		}
+		if len(next) == 0 {
+			return nil, fmt.Errorf("%w %s, %s", ErrNoMatch, p, p[:i].missing(s, nodes))
+		}
+		nodes = next
+	}
+
+	values := make([]json.RawMessage, len(nodes))
+	for i, node := range nodes {
+		var b bytes.Buffer
+		if err := json.Compact(&b, node); err != nil {
+			return nil, err
+		}
+		values[i] = b.Bytes()
+	}
+	return values, nil
+}
+
+// missing explains why s matched nothing after the prefix p, e.g. `$.a has
+// no key "b"`.
+func (p Path) missing(s step, nodes []json.RawMessage) string {
+	at, has, is := p.String(), "has", "is"
+	if len(nodes) > 1 {
+		at, has, is = "the values of "+at, "have", "are"
+	}
+	kind := kindOf(nodes[0])
+	switch s.kind {
+	case stepKey:
+		if kind == "object" {
+			return fmt.Sprintf("%s %s no key %q", at, has, s.key)
+		}
+	case stepIndex:
+		if kind == "array" {
+			return fmt.Sprintf("%s %s no index %d", at, has, s.index)
+		}
+	case stepWildcard:
+		if kind == "object" || kind == "array" {
+			return fmt.Sprintf("%s %s empty", at, is)
+		}
+	}
+	return fmt.Sprintf("%s %s %s, which has no %s", at, is, article(kind), s)
+}
+
+// apply returns the children of node selected by s.
+func (s step) apply(node json.RawMessage) ([]json.RawMessage, error) {
+	switch kindOf(node) {
+	case "object":
+		if s.kind == stepIndex {
+			return nil, nil
+		}
+		fields, err := decodeObject(node)
+		if err != nil {
+			return nil, err
+		}
+		var children []json.RawMessage
+		for _, f := range fields {
+			if s.kind == stepWildcard {
+				children = append(children, f.value)
+			} else if f.key == s.key {
+				// like encoding/json, the last of duplicate keys wins
+				children = []json.RawMessage{f.value}
+			}
+		}
+		return children, nil
+	case "array":
+		if s.kind == stepKey {
+			return nil, nil
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(node, &elements); err != nil {
+			return nil, err
+		}
+		if s.kind == stepWildcard {
+			return elements, nil
+		}
+		i := s.index
+		if i < 0 {
+			i += len(elements)
+		}
+		if i < 0 || i >= len(elements) {
+			return nil, nil
+		}
+		return elements[i : i+1], nil
+	}
+	return nil, nil
+}
+
+// field is a key and value of a JSON object.
+type field struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeObject decodes a JSON object keeping the order of its keys.
+func decodeObject(raw json.RawMessage) ([]field, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var fields []field
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field{key: tok.(string), value: value})
+	}
+	return fields, nil
+}
+
+// kindOf returns the JSON type of a valid value from its first byte.
+func kindOf(node json.RawMessage) string {
+	switch node[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	}
+	return "number"
+}
+
+func article(kind string) string {
+	if kind == "object" || kind == "array" {
+		return "an " + kind
+	}
+	return "a " + kind
+}
+
+// joinValues returns the values as a JSON array.
+func joinValues(values []json.RawMessage) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(v)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
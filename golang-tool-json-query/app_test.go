@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const store = `{
+  "store": {
+    "book": [
+      {"title": "Sayings of the Century", "price": 8.95, "tags": ["quotes"]},
+      {"title": "Moby Dick", "price": 8.99, "isbn": "0-553-21311-3"},
+      {"title": "The Lord of the Rings", "price": 22.99}
+    ],
+    "bicycle": {"color": "red", "price": 19.95},
+    "first owner": "Ada"
+  },
+  "open": true,
+  "big": 12345678901234567890
+}`
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"$", `[{"store":{"book":[{"title":"Sayings of the Century","price":8.95,"tags":["quotes"]},{"title":"Moby Dick","price":8.99,"isbn":"0-553-21311-3"},{"title":"The Lord of the Rings","price":22.99}],"bicycle":{"color":"red","price":19.95},"first owner":"Ada"},"open":true,"big":12345678901234567890}]`},
+		{"$.store.bicycle.color", `["red"]`},
+		{"$.store.book[0].title", `["Sayings of the Century"]`},
+		{"$.store.book[-1].price", `[22.99]`},
+		{"$['store']['first owner']", `["Ada"]`},
+		{`$["store"].bicycle`, `[{"color":"red","price":19.95}]`},
+		{"$.store.book[*].title", `["Sayings of the Century","Moby Dick","The Lord of the Rings"]`},
+		{"$.store.book[*].isbn", `["0-553-21311-3"]`},
+		{"$.store.*.price", `[19.95]`},
+		{"$.store.book[ * ].tags[0]", `["quotes"]`},
+		{"$.open", `[true]`},
+		// numbers are not rounded through float64
+		{"$.big", `[12345678901234567890]`},
+		// without the root
+		{"store.bicycle.price", `[19.95]`},
+		{"[\"open\"]", `[true]`},
+	}
+	for _, tt := range tests {
+		path, err := ParsePath(tt.query)
+		if err != nil {
+			t.Errorf("ParsePath(%q) error = %v", tt.query, err)
+			continue
+		}
+		values, err := path.Eval([]byte(store))
+		if err != nil {
+			t.Errorf("Eval(%q) error = %v", tt.query, err)
+			continue
+		}
+		if got := joinValues(values); got != tt.want {
+			t.Errorf("Eval(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestEvalWildcardOrder(t *testing.T) {
+	path, _ := ParsePath("$.*")
+	values, err := path.Eval([]byte(`{"z": 1, "a": 2, "m": [3]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := joinValues(values); got != `[1,2,[3]]` {
+		t.Errorf("$.* = %s, want the document order [1,2,[3]]", got)
+	}
+}
+
+func TestEvalNoMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"$.store.car", `nothing matches $.store.car, $.store has no key "car"`},
+		{"$.store.book[3]", `nothing matches $.store.book[3], $.store.book has no index 3`},
+		{"$.store.book[-4]", `nothing matches $.store.book[-4], $.store.book has no index -4`},
+		{"$.store.book.title", `nothing matches $.store.book.title, $.store.book is an array, which has no .title`},
+		{"$.open[0]", `nothing matches $.open[0], $.open is a boolean, which has no [0]`},
+		{"$.store.book[*].author", `nothing matches $.store.book[*].author, the values of $.store.book[*] have no key "author"`},
+	}
+	for _, tt := range tests {
+		path, err := ParsePath(tt.query)
+		if err != nil {
+			t.Fatalf("ParsePath(%q) error = %v", tt.query, err)
+		}
+		_, err = path.Eval([]byte(store))
+		if !errors.Is(err, ErrNoMatch) {
+			t.Errorf("Eval(%q) error = %v, want ErrNoMatch", tt.query, err)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("Eval(%q) error = %q, want %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestEvalInvalidJSON(t *testing.T) {
+	path, _ := ParsePath("$.a")
+	for _, doc := range []string{``, `{"a": 1`, `{"a": 1} {"b": 2}`, `{'a': 1}`} {
+		if _, err := path.Eval([]byte(doc)); err == nil || !strings.HasPrefix(err.Error(), "invalid JSON") {
+			t.Errorf("Eval(%q) error = %v, want invalid JSON", doc, err)
+		}
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "the query is empty"},
+		{"$.", "position 3"},
+		{"$..title", "recursive descent"},
+		{"$.a b", "use ['...']"},
+		{"$[0", "expected ]"},
+		{"$[", "unclosed ["},
+		{"$[0,1]", "unions and slices"},
+		{"$[1:2]", "unions and slices"},
+		{"$['a]", "unterminated quoted key"},
+		{"$[a]", "expected an index"},
+		{"$.a]", "expected . or ["},
+	}
+	for _, tt := range tests {
+		_, err := ParsePath(tt.query)
+		if !errors.Is(err, ErrSyntax) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParsePath(%q) error = %v, want %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestPathString(t *testing.T) {
+	path, err := ParsePath(`store['first owner'][0][*].* . x`)
+	if err == nil {
+		t.Fatalf("ParsePath() of a space between steps should fail, got %s", path)
+	}
+	path, err = ParsePath(`store['first owner'][0][*].*['it\'s']`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := path.String(), `$.store["first owner"][0][*][*]["it's"]`; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
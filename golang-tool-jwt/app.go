@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Decode a JSON Web Token (JWT) and inspect its header and claims, e.g. to find out who a token was issued to or when it expires. The times of the exp, iat and nbf claims are returned as dates, and whether the token is expired. The signature is not checked unless the user gives the secret of an HS256 token.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Token  string `json:"token" jsonschema:"description=The JWT in its compact form: three base64url parts separated by dots"`
+	Secret string `json:"secret,omitempty" jsonschema:"description=The shared secret to verify an HS256 signature with"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "jwt", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC0}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// the token and the secret are credentials, neither is logged
+	slog.Info("[sfn] << receive", "token_length", len(msg.Token), "verify", msg.Secret != "")
+
+	inspection, err := Inspect(msg.Token, msg.Secret, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] Inspect error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not decode the token: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, inspection.String())
+}
+
+// timeClaims are the registered claims holding a NumericDate, see RFC 7519
+// section 4.1.
+var timeClaims = []struct{ name, meaning string }{
+	{"iat", "issued at"},
+	{"nbf", "not before"},
+	{"exp", "expires at"},
+}
+
+// Inspection is the decoded content of a token.
+type Inspection struct {
+	Header json.RawMessage
+	Claims json.RawMessage
+	// Times are the exp, iat and nbf claims as human readable lines.
+	Times []string
+	// Notes tell whether the token is expired or not yet valid.
+	Notes []string
+	// Signature is the outcome of the signature check.
+	Signature string
+}
+
+func (i *Inspection) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Header: %s\nClaims: %s\n", i.Header, i.Claims)
+	for _, t := range i.Times {
+		fmt.Fprintf(&b, "%s\n", t)
+	}
+	for _, n := range i.Notes {
+		fmt.Fprintf(&b, "%s\n", n)
+	}
+	b.WriteString(i.Signature)
+	return b.String()
+}
+
+// Inspect decodes token and checks its time claims against now. When secret
+// is not empty the HS256 signature is verified with it.
+func Inspect(token, secret string, now time.Time) (*Inspection, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("a JWT has 3 parts separated by dots, got %d", len(parts))
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	headerJSON, err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	var claims map[string]any
+	claimsJSON, err := decodeSegment(parts[1], &claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid claims: %w", err)
+	}
+
+	inspection := &Inspection{Header: headerJSON, Claims: claimsJSON}
+	for _, c := range timeClaims {
+		v, ok := claims[c.name]
+		if !ok {
+			continue
+		}
+		n, ok := v.(json.Number)
+		if !ok {
+			inspection.Times = append(inspection.Times, fmt.Sprintf("%s (%s): %v is not a NumericDate", c.name, c.meaning, v))
+			continue
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s claim: %w", c.name, err)
+		}
+		t := time.Unix(int64(f), 0).UTC()
+		inspection.Times = append(inspection.Times, fmt.Sprintf("%s (%s): %s", c.name, c.meaning, t.Format(time.RFC1123)))
+
+		switch {
+		case c.name == "exp" && !now.Before(t):
+			inspection.Notes = append(inspection.Notes, fmt.Sprintf("The token is expired, it expired %s ago.", now.Sub(t).Truncate(time.Second)))
+		case c.name == "exp":
+			inspection.Notes = append(inspection.Notes, fmt.Sprintf("The token expires in %s.", t.Sub(now).Truncate(time.Second)))
+		case c.name == "nbf" && now.Before(t):
+			inspection.Notes = append(inspection.Notes, fmt.Sprintf("The token is not valid yet, it becomes valid in %s.", t.Sub(now).Truncate(time.Second)))
+		}
+	}
+
+	inspection.Signature = verify(header.Alg, parts, secret)
+	return inspection, nil
+}
+
+// decodeSegment decodes a base64url part of a token into v and returns its
+// JSON. Numbers are kept as json.Number so large ids are not rounded.
+func decodeSegment(segment string, v any) (json.RawMessage, error) {
+	// RFC 7515 omits the padding, but some encoders add it anyway
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return nil, err
+	}
+	if v, ok := v.(*map[string]any); ok && *v == nil {
+		return nil, errors.New("not a JSON object")
+	}
+	return data, nil
+}
+
+// verify checks the signature of an HS256 token with secret and describes the
+// outcome.
+func verify(alg string, parts []string, secret string) string {
+	if secret == "" {
+		return "The signature is not verified."
+	}
+	if alg != "HS256" {
+		return fmt.Sprintf("The signature is not verified: only HS256 tokens can be verified, this token uses %q.", alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return "The signature is invalid: it is not base64url encoded."
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "The signature is invalid for the given secret."
+	}
+	return "The HS256 signature is valid."
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Recommend how many layers of clothing to wear and which items, from how cold or hot the air feels with the wind chill or the heat index, e.g. "what should I wear outside in Montreal right now?". Give either the coordinates of a location for its current weather, converting a place name to Latitude and Longitude geo coordinates in decimal format, or the temperature, wind speed and humidity. The function returns the apparent temperature, the number of layers and the items to wear.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude    *float64 `json:"latitude,omitempty" jsonschema:"description=The latitude of the location in decimal format for its current weather,minimum=-90,maximum=90"`
+	Longitude   *float64 `json:"longitude,omitempty" jsonschema:"description=The longitude of the location in decimal format for its current weather,minimum=-180,maximum=180"`
+	Temperature *float64 `json:"temperature,omitempty" jsonschema:"description=The air temperature in degrees Celsius when no coordinates are given"`
+	WindSpeed   float64  `json:"windSpeed,omitempty" jsonschema:"description=The wind speed in km/h when no coordinates are given. Defaults to 0,minimum=0"`
+	Humidity    float64  `json:"humidity,omitempty" jsonschema:"description=The relative humidity in percent when no coordinates are given. Defaults to 50,minimum=0,maximum=100"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "layers", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xFD}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "live", msg.Latitude != nil && msg.Longitude != nil, "wind", msg.WindSpeed, "humidity", msg.Humidity)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := Recommend(reqCtx, msg)
+	if err != nil {
+		slog.Warn("[sfn] Recommend error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not recommend the clothing layers: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Air is the weather the layers depend on.
+type Air struct {
+	// Temperature is in °C, WindSpeed in km/h and Humidity in percent.
+	Temperature float64
+	WindSpeed   float64
+	Humidity    float64
+	// Wet is whether it rains or snows.
+	Wet bool
+}
+
+// defaultHumidity is the humidity assumed when none is given, it only
+// matters for the heat index.
+const defaultHumidity = 50
+
+// Recommend reads the air of p, the current weather at its coordinates if
+// it has some, and recommends what to wear.
+func Recommend(ctx context.Context, p Parameter) (string, error) {
+	var (
+		air   Air
+		where string
+	)
+	switch {
+	case p.Latitude != nil && p.Longitude != nil:
+		if err := geo.ValidateCoordinate(*p.Latitude, *p.Longitude); err != nil {
+			return "", err
+		}
+		c, err := client.Current(ctx, *p.Latitude, *p.Longitude)
+		if err != nil {
+			return "", err
+		}
+		air = Air{
+			Temperature: c.Temperature,
+			// OpenWeatherMap reports m/s in metric units
+			WindSpeed: c.WindSpeed * 3.6,
+			Humidity:  float64(c.Humidity),
+			Wet:       c.Rain1h > 0 || c.Snow1h > 0,
+		}
+		where = fmt.Sprintf("in %s now, %.1f°C with %s and a %.0f km/h wind", place(c, *p.Latitude, *p.Longitude), c.Temperature, c.Description, air.WindSpeed)
+	case p.Temperature != nil:
+		air = Air{Temperature: *p.Temperature, WindSpeed: p.WindSpeed, Humidity: p.Humidity}
+		if air.Humidity == 0 {
+			air.Humidity = defaultHumidity
+		}
+		if math.IsNaN(air.Temperature) || math.IsInf(air.Temperature, 0) {
+			return "", fmt.Errorf("invalid temperature %v", air.Temperature)
+		}
+		if math.IsNaN(air.WindSpeed) || math.IsInf(air.WindSpeed, 0) || air.WindSpeed < 0 {
+			return "", fmt.Errorf("invalid wind speed %v", air.WindSpeed)
+		}
+		if math.IsNaN(air.Humidity) || air.Humidity < 0 || air.Humidity > 100 {
+			return "", fmt.Errorf("humidity %v%% is not between 0 and 100", air.Humidity)
+		}
+		where = fmt.Sprintf("at %.1f°C with a %.0f km/h wind", air.Temperature, air.WindSpeed)
+	default:
+		return "", errors.New("give the coordinates of a location or the temperature")
+	}
+
+	return fmt.Sprintf("Outside %s: %s", where, Dress(air)), nil
+}
+
+func place(c *weather.Conditions, lat, lon float64) string {
+	if c.City == "" {
+		return fmt.Sprintf("%v,%v", lat, lon)
+	}
+	if c.Country == "" {
+		return c.City
+	}
+	return c.City + ", " + c.Country
+}
+
+// Outfit is what to wear for an apparent temperature.
+type Outfit struct {
+	// FeelsLike is the apparent temperature in °C, and Index the wind chill
+	// or heat index it comes from, if any.
+	FeelsLike float64
+	Index     string
+	Band      string
+	Layers    int
+	Items     []string
+}
+
+func (o Outfit) String() string {
+	feel := fmt.Sprintf("it feels like %.0f°C", o.FeelsLike)
+	if o.Index != "" {
+		feel += " with the " + o.Index
+	}
+	noun := "layers"
+	if o.Layers == 1 {
+		noun = "layer"
+	}
+	return fmt.Sprintf("%s, %s. Wear %d %s: %s.", feel, o.Band, o.Layers, noun, strings.Join(o.Items, ", "))
+}
+
+// band is a range of apparent temperatures and its outfit.
+type band struct {
+	// from is the lowest apparent temperature in °C of the band.
+	from   float64
+	name   string
+	layers []string
+	extras []string
+}
+
+// bands are ordered from the warmest down, the last one has no lower bound.
+// Below -28°C exposed skin freezes within 30 minutes.
+var bands = []band{
+	{from: 30, name: "hot", layers: []string{"a loose breathable t-shirt"}, extras: []string{"shorts", "a sun hat"}},
+	{from: 20, name: "warm", layers: []string{"a t-shirt"}, extras: []string{"light trousers or shorts"}},
+	{from: 12, name: "mild", layers: []string{"a long-sleeve shirt", "a light jacket or sweater"}, extras: []string{"trousers"}},
+	{from: 5, name: "cool", layers: []string{"a long-sleeve shirt", "a sweater or fleece", "a windproof jacket"}, extras: []string{"trousers"}},
+	{from: -10, name: "cold", layers: []string{"a thermal base layer", "a fleece", "an insulated coat"}, extras: []string{"a hat", "gloves"}},
+	{from: -28, name: "very cold", layers: []string{"a thermal base layer", "a fleece", "an insulated coat", "a windproof shell"}, extras: []string{"a hat", "gloves", "a scarf", "warm socks"}},
+	{from: math.Inf(-1), name: "dangerously cold, exposed skin can get frostbite, limit the time outside", layers: []string{"a thermal base layer", "a fleece", "a down parka", "a windproof shell"}, extras: []string{"an insulated hat", "mittens", "a face mask", "insulated boots"}},
+}
+
+// Dress maps the apparent temperature of the air to layers of clothing.
+// The wind chill and the heat index are computed with the formulas of the
+// National Weather Service. Rain or snow adds a waterproof outer layer
+// when the outfit has none.
+func Dress(air Air) Outfit {
+	f, index := weather.Apparent(air.Temperature*9/5+32, air.Humidity, air.WindSpeed/1.609344)
+	feels := (f - 32) * 5 / 9
+
+	b := bands[len(bands)-1]
+	for _, candidate := range bands {
+		if math.Round(feels) >= candidate.from {
+			b = candidate
+			break
+		}
+	}
+
+	items := append([]string(nil), b.layers...)
+	if air.Wet && !strings.Contains(strings.Join(items, " "), "shell") {
+		items = append(items, "a waterproof jacket")
+	}
+	layers := len(items)
+	items = append(items, b.extras...)
+	return Outfit{FeelsLike: feels, Index: index, Band: b.name, Layers: layers, Items: items}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestDressBands(t *testing.T) {
+	tests := []struct {
+		name   string
+		air    Air
+		band   string
+		layers int
+	}{
+		{"heat wave", Air{Temperature: 34, Humidity: 40}, "hot", 1},
+		{"summer", Air{Temperature: 24, Humidity: 50}, "warm", 1},
+		{"spring", Air{Temperature: 16, Humidity: 50}, "mild", 2},
+		{"autumn", Air{Temperature: 8, Humidity: 70}, "cool", 3},
+		{"autumn wind", Air{Temperature: 8, WindSpeed: 40, Humidity: 70}, "cold", 3},
+		{"winter", Air{Temperature: -2, Humidity: 80}, "cold", 3},
+		{"winter wind", Air{Temperature: -8, WindSpeed: 20, Humidity: 80}, "very cold", 4},
+		{"deep winter", Air{Temperature: -18, WindSpeed: 10, Humidity: 80}, "very cold", 4},
+		{"arctic", Air{Temperature: -20, WindSpeed: 30, Humidity: 70}, "dangerously cold, exposed skin can get frostbite, limit the time outside", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := Dress(tt.air)
+			if o.Band != tt.band || o.Layers != tt.layers {
+				t.Errorf("Dress() = %s with %d layers (feels like %.1f°C), want %s with %d", o.Band, o.Layers, o.FeelsLike, tt.band, tt.layers)
+			}
+		})
+	}
+}
+
+func TestDressApparent(t *testing.T) {
+	// -10°C with a 30 km/h wind is about -20°C on the NWS chart
+	o := Dress(Air{Temperature: -10, WindSpeed: 30, Humidity: 60})
+	if math.Abs(o.FeelsLike+20) > 1 || o.Index != "wind chill" {
+		t.Errorf("Dress() feels like %.1f°C (%s), want -20°C with the wind chill", o.FeelsLike, o.Index)
+	}
+
+	// 32°C at 70% humidity feels like about 41°C
+	o = Dress(Air{Temperature: 32, Humidity: 70})
+	if math.Abs(o.FeelsLike-41) > 1 || o.Index != "heat index" {
+		t.Errorf("Dress() feels like %.1f°C (%s), want 41°C with the heat index", o.FeelsLike, o.Index)
+	}
+
+	o = Dress(Air{Temperature: 16, Humidity: 50})
+	if o.FeelsLike != 16 || o.Index != "" {
+		t.Errorf("Dress() feels like %.1f°C (%s), want 16°C", o.FeelsLike, o.Index)
+	}
+}
+
+func TestDressWet(t *testing.T) {
+	o := Dress(Air{Temperature: 16, Humidity: 90, Wet: true})
+	want := "it feels like 16°C, mild. Wear 3 layers: a long-sleeve shirt, a light jacket or sweater, a waterproof jacket, trousers."
+	if got := o.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+
+	// the windproof shell of the coldest bands keeps the snow out already
+	o = Dress(Air{Temperature: -18, WindSpeed: 10, Humidity: 80, Wet: true})
+	if o.Layers != 4 {
+		t.Errorf("Dress() in the snow = %d layers, want 4", o.Layers)
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	temp := 24.0
+	got, err := Recommend(context.Background(), Parameter{Temperature: &temp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Outside at 24.0°C with a 0 km/h wind: it feels like 24°C, warm. Wear 1 layer: a t-shirt, light trousers or shorts."; got != want {
+		t.Errorf("Recommend() =\n%s\nwant\n%s", got, want)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/2.5/weather" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"name":"Montreal","sys":{"country":"CA"},"coord":{"lat":45.5017,"lon":-73.5673},
+			"weather":[{"id":600,"main":"Snow","description":"light snow"}],
+			"main":{"temp":-10,"feels_like":-17,"humidity":75,"pressure":1021},"wind":{"speed":8.3},"snow":{"1h":0.4}}`)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	lat, lon := 45.5017, -73.5673
+	got, err = Recommend(context.Background(), Parameter{Latitude: &lat, Longitude: &lon})
+	if err != nil {
+		t.Fatalf("Recommend() error = %v", err)
+	}
+	want := "Outside in Montreal, CA now, -10.0°C with light snow and a 30 km/h wind: it feels like -19°C with the wind chill, very cold. Wear 4 layers: a thermal base layer, a fleece, an insulated coat, a windproof shell, a hat, gloves, a scarf, warm socks."
+	if got != want {
+		t.Errorf("Recommend() =\n%s\nwant\n%s", got, want)
+	}
+
+	bad := 100.0
+	for _, p := range []Parameter{
+		{},
+		{Latitude: &bad, Longitude: &lon},
+		{Temperature: &temp, WindSpeed: -1},
+		{Temperature: &temp, Humidity: 120},
+	} {
+		if _, err := Recommend(context.Background(), p); err == nil {
+			t.Errorf("Recommend(%+v) should fail", p)
+		}
+	}
+}
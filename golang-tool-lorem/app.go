@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Generate Lorem Ipsum placeholder text. "unit" is words, sentences or paragraphs and "count" is how many of them to generate, at most 500 words, 50 sentences or 10 paragraphs. Paragraphs are separated by a blank line.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Count int    `json:"count" jsonschema:"description=The number of words or sentences or paragraphs to generate,minimum=1"`
+	Unit  string `json:"unit" jsonschema:"description=The unit of the count,enum=words,enum=sentences,enum=paragraphs"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "lorem", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB6}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "count", msg.Count, "unit", msg.Unit)
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	text, err := Generate(r, msg.Count, msg.Unit)
+	if err != nil {
+		slog.Warn("[sfn] Generate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not generate the placeholder text: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, text)
+}
+
+// maxCounts caps the count of each unit.
+var maxCounts = map[string]int{
+	"words":      500,
+	"sentences":  50,
+	"paragraphs": 10,
+}
+
+const (
+	minSentenceWords   = 6
+	maxSentenceWords   = 14
+	minParagraphLength = 3
+	maxParagraphLength = 6
+)
+
+var words = strings.Fields(`lorem ipsum dolor sit amet consectetur adipiscing elit sed do
+eiusmod tempor incididunt ut labore et dolore magna aliqua enim ad minim veniam
+quis nostrud exercitation ullamco laboris nisi aliquip ex ea commodo consequat
+duis aute irure in reprehenderit voluptate velit esse cillum eu fugiat nulla
+pariatur excepteur sint occaecat cupidatat non proident sunt culpa qui officia
+deserunt mollit anim id est laborum`)
+
+// Generate returns count words, sentences or paragraphs of Lorem Ipsum drawn
+// from r. The count is capped to the maximum of the unit.
+func Generate(r *rand.Rand, count int, unit string) (string, error) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		unit = "paragraphs"
+	}
+	if !strings.HasSuffix(unit, "s") {
+		unit += "s"
+	}
+	limit, ok := maxCounts[unit]
+	if !ok {
+		return "", fmt.Errorf("unknown unit %q, it must be words, sentences or paragraphs", unit)
+	}
+	if count < 1 {
+		return "", fmt.Errorf("the count must be at least 1, got %d", count)
+	}
+	count = min(count, limit)
+
+	switch unit {
+	case "words":
+		return strings.Join(pickWords(r, count), " "), nil
+	case "sentences":
+		return strings.Join(sentences(r, count), " "), nil
+	default:
+		paragraphs := make([]string, count)
+		for i := range paragraphs {
+			n := minParagraphLength + r.Intn(maxParagraphLength-minParagraphLength+1)
+			paragraphs[i] = strings.Join(sentences(r, n), " ")
+		}
+		return strings.Join(paragraphs, "\n\n"), nil
+	}
+}
+
+func pickWords(r *rand.Rand, n int) []string {
+	picked := make([]string, n)
+	for i := range picked {
+		picked[i] = words[r.Intn(len(words))]
+	}
+	return picked
+}
+
+// sentences returns n capitalized sentences ending with a period.
+func sentences(r *rand.Rand, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		s := strings.Join(pickWords(r, minSentenceWords+r.Intn(maxSentenceWords-minSentenceWords+1)), " ")
+		out[i] = strings.ToUpper(s[:1]) + s[1:] + "."
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name           string
+		count          int
+		unit           string
+		wantWords      int
+		wantSentences  int
+		wantParagraphs int
+	}{
+		{name: "words", count: 7, unit: "words", wantWords: 7, wantParagraphs: 1},
+		{name: "singular unit", count: 1, unit: "Word", wantWords: 1, wantParagraphs: 1},
+		{name: "words capped", count: 10000, unit: "words", wantWords: 500, wantParagraphs: 1},
+		{name: "sentences", count: 3, unit: "sentences", wantSentences: 3, wantParagraphs: 1},
+		{name: "sentences capped", count: 51, unit: "sentences", wantSentences: 50, wantParagraphs: 1},
+		{name: "paragraphs", count: 4, unit: "paragraphs", wantParagraphs: 4},
+		{name: "paragraphs capped", count: 11, unit: "paragraphs", wantParagraphs: 10},
+		{name: "default unit", count: 2, unit: "", wantParagraphs: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := Generate(rand.New(rand.NewSource(1)), tt.count, tt.unit)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			paragraphs := strings.Split(text, "\n\n")
+			if len(paragraphs) != tt.wantParagraphs {
+				t.Errorf("Generate() has %d paragraphs, want %d", len(paragraphs), tt.wantParagraphs)
+			}
+			if tt.wantWords > 0 {
+				if n := len(strings.Fields(text)); n != tt.wantWords {
+					t.Errorf("Generate() has %d words, want %d", n, tt.wantWords)
+				}
+				if strings.ContainsAny(text, ".\n") {
+					t.Errorf("Generate() words contain punctuation: %q", text)
+				}
+			}
+			if tt.wantSentences > 0 {
+				if n := strings.Count(text, "."); n != tt.wantSentences {
+					t.Errorf("Generate() has %d sentences, want %d", n, tt.wantSentences)
+				}
+			}
+
+			for _, p := range paragraphs {
+				if tt.wantWords > 0 {
+					break
+				}
+				n := strings.Count(p, ".")
+				if tt.wantSentences == 0 && (n < minParagraphLength || n > maxParagraphLength) {
+					t.Errorf("paragraph has %d sentences, want %d to %d", n, minParagraphLength, maxParagraphLength)
+				}
+				for _, s := range strings.SplitAfter(p, ". ") {
+					if !unicode.IsUpper(rune(s[0])) || !strings.HasSuffix(strings.TrimSpace(s), ".") {
+						t.Errorf("malformed sentence %q", s)
+					}
+					if n := len(strings.Fields(s)); n < minSentenceWords || n > maxSentenceWords {
+						t.Errorf("sentence %q has %d words, want %d to %d", s, n, minSentenceWords, maxSentenceWords)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	a, _ := Generate(rand.New(rand.NewSource(42)), 3, "paragraphs")
+	b, _ := Generate(rand.New(rand.NewSource(42)), 3, "paragraphs")
+	if a != b {
+		t.Error("Generate() with the same seed returned different texts")
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	if _, err := Generate(r, 3, "chapters"); err == nil {
+		t.Error("Generate() should reject an unknown unit")
+	}
+	if _, err := Generate(r, 0, "words"); err == nil {
+		t.Error("Generate() should reject a count of 0")
+	}
+}
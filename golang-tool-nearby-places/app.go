@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Find places of a category near a location, e.g. "is there a pharmacy near the Eiffel Tower?" or "cafes within 500 meters of me". If the place name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns up to 5 of the nearest places with their name and distance, from OpenStreetMap.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+	Category  string  `json:"category" jsonschema:"description=The kind of place to find,enum=restaurant,enum=cafe,enum=bar,enum=pharmacy,enum=hospital,enum=supermarket,enum=bakery,enum=atm,enum=bank,enum=fuel,enum=parking,enum=toilets,enum=hotel,enum=museum"`
+	RadiusM   int     `json:"radius_m,omitempty" jsonschema:"description=The search radius in meters. Defaults to 1000,minimum=1,maximum=5000,example=500"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "nearby-places", Description: Description(), InputSchema: InputSchema(), Upstream: overpass.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE7}
+}
+
+// overpass is the public Overpass API instance, whose usage policy asks
+// clients to identify themselves with a User-Agent.
+var overpass = &Overpass{
+	BaseURL:    "https://overpass-api.de",
+	UserAgent:  "yomo-llm-nearby-places (https://github.com/yomorun/llm-function-calling-examples)",
+	HTTPClient: httpx.NewClient(25 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "category", msg.Category, "radius_m", msg.RadiusM)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := NearbyPlaces(reqCtx, overpass, msg)
+	if err != nil {
+		slog.Warn("[sfn] NearbyPlaces error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not find the nearby places: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+const (
+	defaultRadiusM = 1000
+	maxRadiusM     = 5000
+	// maxResults is how many of the nearest places are returned.
+	maxResults = 5
+)
+
+// categories maps the categories to their OpenStreetMap tag, see
+// https://wiki.openstreetmap.org/wiki/Map_features.
+var categories = map[string][2]string{
+	"restaurant":  {"amenity", "restaurant"},
+	"cafe":        {"amenity", "cafe"},
+	"bar":         {"amenity", "bar"},
+	"pharmacy":    {"amenity", "pharmacy"},
+	"hospital":    {"amenity", "hospital"},
+	"atm":         {"amenity", "atm"},
+	"bank":        {"amenity", "bank"},
+	"fuel":        {"amenity", "fuel"},
+	"parking":     {"amenity", "parking"},
+	"toilets":     {"amenity", "toilets"},
+	"supermarket": {"shop", "supermarket"},
+	"bakery":      {"shop", "bakery"},
+	"hotel":       {"tourism", "hotel"},
+	"museum":      {"tourism", "museum"},
+}
+
+// Place is a place found near the location.
+type Place struct {
+	Name string
+	// Distance is in meters from the location.
+	Distance float64
+}
+
+// NearbyPlaces validates p and lists the nearest places of its category
+// with o.
+func NearbyPlaces(ctx context.Context, o *Overpass, p Parameter) (string, error) {
+	if err := geo.ValidateCoordinate(p.Latitude, p.Longitude); err != nil {
+		return "", err
+	}
+	category := strings.ToLower(strings.TrimSpace(p.Category))
+	tag, ok := categories[category]
+	if !ok {
+		return "", fmt.Errorf("unknown category %q", p.Category)
+	}
+	radius := p.RadiusM
+	if radius == 0 {
+		radius = defaultRadiusM
+	}
+	if radius < 0 || radius > maxRadiusM {
+		return "", fmt.Errorf("the radius is limited to %d meters", maxRadiusM)
+	}
+
+	places, err := o.Around(ctx, tag[0], tag[1], p.Latitude, p.Longitude, radius)
+	if err != nil {
+		return "", err
+	}
+	if len(places) == 0 {
+		return fmt.Sprintf("No %s was found within %d m of %v,%v.", category, radius, p.Latitude, p.Longitude), nil
+	}
+	var list []string
+	for _, place := range places {
+		list = append(list, fmt.Sprintf("%s (%.0f m)", place.Name, place.Distance))
+	}
+	return fmt.Sprintf("Nearest %s within %d m of %v,%v: %s.", category, radius, p.Latitude, p.Longitude, strings.Join(list, ", ")), nil
+}
+
+// Overpass is a client of the OpenStreetMap Overpass API, see
+// https://wiki.openstreetmap.org/wiki/Overpass_API.
+type Overpass struct {
+	BaseURL    string
+	UserAgent  string
+	HTTPClient *http.Client
+}
+
+// Around returns the named places tagged key=value within radius meters of
+// lat,lon, at most maxResults of them, nearest first.
+func (o *Overpass) Around(ctx context.Context, key, value string, lat, lon float64, radius int) ([]Place, error) {
+	// the ways and relations, e.g. a supermarket mapped as its building,
+	// are located at their center
+	query := fmt.Sprintf(`[out:json][timeout:20];nwr[%q=%q][name](around:%d,%v,%v);out center tags 100;`, key, value, radius, lat, lon)
+	form := url.Values{}
+	form.Set("data", query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+"/api/interpreter", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", o.UserAgent)
+
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests, http.StatusGatewayTimeout:
+		return nil, errors.New("the Overpass API is busy, try again in a minute")
+	default:
+		return nil, fmt.Errorf("the Overpass API responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var r struct {
+		Elements []struct {
+			Lat    float64 `json:"lat"`
+			Lon    float64 `json:"lon"`
+			Center *struct {
+				Lat float64 `json:"lat"`
+				Lon float64 `json:"lon"`
+			} `json:"center"`
+			Tags map[string]string `json:"tags"`
+		} `json:"elements"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decode the Overpass response: %w", err)
+	}
+
+	var places []Place
+	for _, e := range r.Elements {
+		name := e.Tags["name"]
+		if name == "" {
+			continue
+		}
+		pLat, pLon := e.Lat, e.Lon
+		if e.Center != nil {
+			pLat, pLon = e.Center.Lat, e.Center.Lon
+		}
+		places = append(places, Place{Name: name, Distance: geo.Distance(lat, lon, pLat, pLon) * 1000})
+	}
+	sort.SliceStable(places, func(i, j int) bool { return places[i].Distance < places[j].Distance })
+	if len(places) > maxResults {
+		places = places[:maxResults]
+	}
+	return places, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// cafes are 8 cafes north of the origin 48.8566,2.3522, one of them without a
+// name and one mapped as a building.
+const cafes = `{"elements":[
+	{"type":"node","lat":48.8656,"lon":2.3522,"tags":{"amenity":"cafe","name":"Café 1 km"}},
+	{"type":"node","lat":48.8575,"lon":2.3522,"tags":{"amenity":"cafe","name":"Café 100 m"}},
+	{"type":"node","lat":48.8570,"lon":2.3522,"tags":{"amenity":"cafe"}},
+	{"type":"way","center":{"lat":48.8584,"lon":2.3522},"tags":{"amenity":"cafe","name":"Café 200 m"}},
+	{"type":"node","lat":48.8611,"lon":2.3522,"tags":{"amenity":"cafe","name":"Café 500 m"}},
+	{"type":"node","lat":48.8593,"lon":2.3522,"tags":{"amenity":"cafe","name":"Café 300 m"}},
+	{"type":"node","lat":48.8602,"lon":2.3522,"tags":{"amenity":"cafe","name":"Café 400 m"}},
+	{"type":"node","lat":48.8629,"lon":2.3522,"tags":{"amenity":"cafe","name":"Café 700 m"}}
+]}`
+
+func overpassServer(t *testing.T, body string, query *string) *Overpass {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/interpreter" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("the request has no User-Agent")
+		}
+		*query = r.FormValue("data")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return &Overpass{BaseURL: srv.URL, UserAgent: "test", HTTPClient: srv.Client()}
+}
+
+func TestNearbyPlaces(t *testing.T) {
+	var query string
+	o := overpassServer(t, cafes, &query)
+
+	got, err := NearbyPlaces(context.Background(), o, Parameter{Latitude: 48.8566, Longitude: 2.3522, Category: "Cafe"})
+	if err != nil {
+		t.Fatalf("NearbyPlaces() error = %v", err)
+	}
+	want := "Nearest cafe within 1000 m of 48.8566,2.3522: Café 100 m (100 m), Café 200 m (200 m), Café 300 m (300 m), Café 400 m (400 m), Café 500 m (500 m)."
+	if got != want {
+		t.Errorf("NearbyPlaces() =\n%s\nwant\n%s", got, want)
+	}
+	if want := `nwr["amenity"="cafe"][name](around:1000,48.8566,2.3522)`; !strings.Contains(query, want) {
+		t.Errorf("query = %s, want %s", query, want)
+	}
+}
+
+func TestAroundCap(t *testing.T) {
+	var query string
+	o := overpassServer(t, cafes, &query)
+
+	places, err := o.Around(context.Background(), "amenity", "cafe", 48.8566, 2.3522, 2000)
+	if err != nil {
+		t.Fatalf("Around() error = %v", err)
+	}
+	if len(places) != maxResults {
+		t.Fatalf("Around() returned %d places, want %d", len(places), maxResults)
+	}
+	for i := 1; i < len(places); i++ {
+		if places[i].Distance < places[i-1].Distance {
+			t.Errorf("the places are not sorted by distance: %v", places)
+		}
+	}
+}
+
+func TestNearbyPlacesNone(t *testing.T) {
+	var query string
+	o := overpassServer(t, `{"elements":[]}`, &query)
+
+	got, err := NearbyPlaces(context.Background(), o, Parameter{Latitude: 48.8566, Longitude: 2.3522, Category: "pharmacy", RadiusM: 300})
+	if err != nil {
+		t.Fatalf("NearbyPlaces() error = %v", err)
+	}
+	if want := "No pharmacy was found within 300 m of 48.8566,2.3522."; got != want {
+		t.Errorf("NearbyPlaces() = %s, want %s", got, want)
+	}
+	if !strings.Contains(query, `nwr["amenity"="pharmacy"][name](around:300,`) {
+		t.Errorf("query = %s", query)
+	}
+}
+
+func TestNearbyPlacesInvalid(t *testing.T) {
+	o := &Overpass{}
+	for _, p := range []Parameter{
+		{Latitude: 91, Category: "cafe"},
+		{Category: "castle"},
+		{Category: "cafe", RadiusM: 5001},
+		{Category: "cafe", RadiusM: -1},
+	} {
+		if _, err := NearbyPlaces(context.Background(), o, p); err == nil {
+			t.Errorf("NearbyPlaces(%+v) should fail", p)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the latest official observation of the weather station nearest to a location in the United States, from the National Weather Service. Use it when the user asks for measured conditions or the nearest station. If the user gives a place name, convert it to Latitude and Longitude in decimal format. The function returns the station, its distance and the observed temperature and conditions.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "nearest-observation", Description: Description(), InputSchema: InputSchema(), Upstream: api.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC8}
+}
+
+// api is the National Weather Service API, which asks clients to identify
+// themselves with a User-Agent.
+var api = &NWS{
+	BaseURL:    "https://api.weather.gov",
+	UserAgent:  "(yomo-llm-nearest-observation, https://github.com/yomorun/llm-function-calling-examples)",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	// the point, the stations and the observation are three chained calls
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	report, err := Nearest(reqCtx, api, msg.Latitude, msg.Longitude)
+	if errors.Is(err, ErrNoStation) {
+		sfn.WriteText(ctx, fmt.Sprintf("there is no National Weather Service station near %v,%v, observations are only available in the United States", msg.Latitude, msg.Longitude))
+		return
+	}
+	if err != nil {
+		slog.Warn("[sfn] Nearest error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the nearest observation: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, report.String())
+}
+
+// maxStationKm is the distance beyond which a station does not tell the
+// weather at the location anymore.
+const maxStationKm = 100
+
+// ErrNoStation is returned by Nearest when no station lies within
+// maxStationKm, e.g. outside the United States.
+var ErrNoStation = errors.New("no station nearby")
+
+// Station is an observation station.
+type Station struct {
+	ID        string
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// Observation is the latest report of a station. The measurements are nil
+// when the station did not report them.
+type Observation struct {
+	Time        time.Time
+	Description string
+	Temperature *float64
+	Humidity    *float64
+	WindSpeed   *float64
+}
+
+// Report is the latest observation of the nearest station.
+type Report struct {
+	Station     Station
+	DistanceKm  float64
+	Observation *Observation
+}
+
+func (r *Report) String() string {
+	o := r.Observation
+	var b strings.Builder
+	fmt.Fprintf(&b, "The nearest station is %s (%s), %.1f km away. Observed at %s:", r.Station.Name, r.Station.ID, r.DistanceKm, o.Time.UTC().Format("2006-01-02 15:04 UTC"))
+	if o.Description != "" {
+		fmt.Fprintf(&b, " %s,", strings.ToLower(o.Description))
+	}
+	if o.Temperature != nil {
+		fmt.Fprintf(&b, " %.1f°C,", *o.Temperature)
+	} else {
+		b.WriteString(" temperature not reported,")
+	}
+	if o.Humidity != nil {
+		fmt.Fprintf(&b, " humidity %.0f%%,", *o.Humidity)
+	}
+	if o.WindSpeed != nil {
+		fmt.Fprintf(&b, " wind %.1f km/h,", *o.WindSpeed)
+	}
+	return strings.TrimSuffix(b.String(), ",")
+}
+
+// Nearest finds the station nearest to lat,lon and returns its latest
+// observation.
+func Nearest(ctx context.Context, api *NWS, lat, lon float64) (*Report, error) {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 || math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("%v,%v is not a valid coordinate", lat, lon)
+	}
+
+	stations, err := api.Stations(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DistanceKm: math.Inf(1)}
+	for _, s := range stations {
+		if d := geo.Distance(lat, lon, s.Latitude, s.Longitude); d < report.DistanceKm {
+			report.Station, report.DistanceKm = s, d
+		}
+	}
+	if report.DistanceKm > maxStationKm {
+		return nil, ErrNoStation
+	}
+
+	report.Observation, err = api.Latest(ctx, report.Station.ID)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// NWS is a client of the National Weather Service API.
+type NWS struct {
+	BaseURL    string
+	UserAgent  string
+	HTTPClient *http.Client
+}
+
+// Stations returns the observation stations of the forecast grid covering
+// lat,lon. A point outside the area of the NWS is ErrNoStation.
+func (n *NWS) Stations(ctx context.Context, lat, lon float64) ([]Station, error) {
+	var point struct {
+		Properties struct {
+			ObservationStations string `json:"observationStations"`
+		} `json:"properties"`
+	}
+	err := n.get(ctx, fmt.Sprintf("%s/points/%.4f,%.4f", n.BaseURL, lat, lon), &point)
+	var se *statusError
+	if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+		return nil, ErrNoStation
+	}
+	if err != nil {
+		return nil, err
+	}
+	if point.Properties.ObservationStations == "" {
+		return nil, ErrNoStation
+	}
+
+	var collection struct {
+		Features []struct {
+			Geometry struct {
+				// Coordinates is a GeoJSON position, longitude first
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties struct {
+				StationIdentifier string `json:"stationIdentifier"`
+				Name              string `json:"name"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := n.get(ctx, point.Properties.ObservationStations, &collection); err != nil {
+		return nil, err
+	}
+
+	var stations []Station
+	for _, f := range collection.Features {
+		if len(f.Geometry.Coordinates) < 2 || f.Properties.StationIdentifier == "" {
+			continue
+		}
+		stations = append(stations, Station{
+			ID:        f.Properties.StationIdentifier,
+			Name:      f.Properties.Name,
+			Latitude:  f.Geometry.Coordinates[1],
+			Longitude: f.Geometry.Coordinates[0],
+		})
+	}
+	return stations, nil
+}
+
+// quantity is a measurement of the NWS API, Value is null when the station
+// did not report it.
+type quantity struct {
+	Value *float64 `json:"value"`
+}
+
+// Latest returns the latest observation of a station.
+func (n *NWS) Latest(ctx context.Context, stationID string) (*Observation, error) {
+	var body struct {
+		Properties struct {
+			Timestamp        time.Time `json:"timestamp"`
+			TextDescription  string    `json:"textDescription"`
+			Temperature      quantity  `json:"temperature"`
+			RelativeHumidity quantity  `json:"relativeHumidity"`
+			WindSpeed        quantity  `json:"windSpeed"`
+		} `json:"properties"`
+	}
+	if err := n.get(ctx, n.BaseURL+"/stations/"+url.PathEscape(stationID)+"/observations/latest", &body); err != nil {
+		return nil, err
+	}
+
+	p := body.Properties
+	return &Observation{
+		Time:        p.Timestamp,
+		Description: p.TextDescription,
+		Temperature: p.Temperature.Value,
+		Humidity:    p.RelativeHumidity.Value,
+		WindSpeed:   p.WindSpeed.Value,
+	}, nil
+}
+
+// statusError is returned when the API answers with a non-200 status.
+type statusError struct {
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("the National Weather Service responded %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// get requests the GeoJSON at rawURL and decodes it into v.
+func (n *NWS) get(ctx context.Context, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/geo+json")
+	req.Header.Set("User-Agent", n.UserAgent)
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{StatusCode: resp.StatusCode}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+)
+
+// newServer mocks the NWS API around Seattle, a point in the Pacific is
+// outside its area.
+func newServer(t *testing.T) *NWS {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			http.Error(w, "missing User-Agent", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/points/47.6062,-122.3321":
+			fmt.Fprintf(w, `{"properties":{"observationStations":"%s/gridpoints/SEW/125,68/stations"}}`, srv.URL)
+		case "/points/61.0000,-149.0000":
+			fmt.Fprintf(w, `{"properties":{"observationStations":"%s/gridpoints/AFC/1,1/stations"}}`, srv.URL)
+		case "/gridpoints/SEW/125,68/stations":
+			// the stations are not sorted by distance
+			w.Write([]byte(`{"features":[
+				{"geometry":{"coordinates":[-122.3088,47.4502]},"properties":{"stationIdentifier":"KSEA","name":"Seattle-Tacoma International Airport"}},
+				{"geometry":{"coordinates":[-122.3144,47.6531]},"properties":{"stationIdentifier":"KBFI","name":"Seattle, Boeing Field"}},
+				{"geometry":{"coordinates":[-122.2015,47.9063]},"properties":{"stationIdentifier":"KPAE","name":"Everett, Snohomish County Airport"}}
+			]}`))
+		case "/gridpoints/AFC/1,1/stations":
+			w.Write([]byte(`{"features":[{"geometry":{"coordinates":[-152,63]},"properties":{"stationIdentifier":"PAFAR","name":"Far Away"}}]}`))
+		case "/stations/KBFI/observations/latest":
+			w.Write([]byte(`{"properties":{"timestamp":"2024-06-01T17:53:00+00:00","textDescription":"Mostly Cloudy","temperature":{"value":14.4},"relativeHumidity":{"value":72.1},"windSpeed":{"value":null}}}`))
+		default:
+			http.Error(w, `{"title":"Data Unavailable For Requested Point"}`, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &NWS{BaseURL: srv.URL, UserAgent: "test", HTTPClient: srv.Client()}
+}
+
+func TestNearest(t *testing.T) {
+	api := newServer(t)
+
+	report, err := Nearest(context.Background(), api, 47.6062, -122.3321)
+	if err != nil {
+		t.Fatalf("Nearest() error = %v", err)
+	}
+	if report.Station.ID != "KBFI" {
+		t.Errorf("Nearest() station = %s, want KBFI", report.Station.ID)
+	}
+	if want := geo.Distance(47.6062, -122.3321, 47.6531, -122.3144); math.Abs(report.DistanceKm-want) > 1e-9 || math.Abs(want-5.39) > 0.01 {
+		t.Errorf("Nearest() distance = %.2f km, want %.2f km", report.DistanceKm, want)
+	}
+
+	want := "The nearest station is Seattle, Boeing Field (KBFI), 5.4 km away. Observed at 2024-06-01 17:53 UTC: mostly cloudy, 14.4°C, humidity 72%"
+	if got := report.String(); got != want {
+		t.Errorf("Report.String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNearestNoStation(t *testing.T) {
+	api := newServer(t)
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+	}{
+		{"outside the NWS area", 20, -150},
+		{"nearest station too far", 61, -149},
+	}
+	for _, tt := range tests {
+		if _, err := Nearest(context.Background(), api, tt.lat, tt.lon); !errors.Is(err, ErrNoStation) {
+			t.Errorf("%s: Nearest() error = %v, want %v", tt.name, err, ErrNoStation)
+		}
+	}
+
+	if _, err := Nearest(context.Background(), api, 91, 0); err == nil || errors.Is(err, ErrNoStation) {
+		t.Errorf("Nearest() of an invalid coordinate error = %v", err)
+	}
+}
+
+func TestReportMissingTemperature(t *testing.T) {
+	r := &Report{Station: Station{ID: "KXYZ", Name: "Somewhere"}, DistanceKm: 12.34, Observation: &Observation{}}
+	want := "The nearest station is Somewhere (KXYZ), 12.3 km away. Observed at 0001-01-01 00:00 UTC: temperature not reported"
+	if got := r.String(); got != want {
+		t.Errorf("Report.String() = %s, want %s", got, want)
+	}
+}
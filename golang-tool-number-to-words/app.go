@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Spell out a whole number in English words, e.g. 1234 is "one thousand two hundred thirty-four", or with currency an amount in dollars and cents as written on a check, e.g. 12 and 50 cents is "twelve dollars and fifty cents". Always use this function instead of spelling out large numbers yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Number   int64 `json:"number" jsonschema:"description=The whole number to spell out. In currency mode the whole dollars,example=1234"`
+	Currency bool  `json:"currency,omitempty" jsonschema:"description=Spell out the number as an amount in dollars and cents. Defaults to false"`
+	Cents    int   `json:"cents,omitempty" jsonschema:"description=The cents of the amount in currency mode,minimum=0,maximum=99"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "number-to-words", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF6}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "number", msg.Number, "currency", msg.Currency, "cents", msg.Cents)
+
+	if !msg.Currency {
+		if msg.Cents != 0 {
+			sfn.WriteText(ctx, "can not spell out the number: cents are only allowed in currency mode")
+			return
+		}
+		result := Words(msg.Number)
+		slog.Info("[sfn] >> result", "result", result)
+		sfn.WriteText(ctx, result)
+		return
+	}
+
+	result, err := Dollars(msg.Number, msg.Cents)
+	if err != nil {
+		slog.Warn("[sfn] Dollars error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not spell out the amount: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+var ones = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+}
+
+var tens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+
+// scales are the short scale names of the powers of a thousand, up to the
+// quintillions an int64 reaches.
+var scales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+
+// Words spells out n the American way, without "and" after the hundreds
+// and with hyphens between the tens and the ones, e.g. -1234 is
+// "minus one thousand two hundred thirty-four".
+func Words(n int64) string {
+	if n == 0 {
+		return ones[0]
+	}
+
+	// the magnitude of math.MinInt64 does not fit an int64
+	u := uint64(n)
+	if n < 0 {
+		u = -u
+	}
+
+	var groups []string
+	for scale := 0; u > 0; scale++ {
+		if g := int(u % 1000); g > 0 {
+			words := hundreds(g)
+			if scales[scale] != "" {
+				words += " " + scales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		u /= 1000
+	}
+
+	s := strings.Join(groups, " ")
+	if n < 0 {
+		s = "minus " + s
+	}
+	return s
+}
+
+// hundreds spells out 1 to 999.
+func hundreds(n int) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, ones[n/100]+" hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		parts = append(parts, ones[n])
+	case n%10 == 0:
+		parts = append(parts, tens[n/10])
+	default:
+		parts = append(parts, tens[n/10]+"-"+ones[n%10])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Dollars spells out an amount of dollars and cents, the sign of dollars
+// being the sign of the amount, e.g. -5 dollars and 50 cents is "minus five
+// dollars and fifty cents". The cents are left out when there are none.
+func Dollars(dollars int64, cents int) (string, error) {
+	if cents < 0 || cents > 99 {
+		return "", fmt.Errorf("cents must be between 0 and 99, got %d", cents)
+	}
+
+	s := Words(dollars) + " " + plural(dollars, "dollar")
+	if cents > 0 {
+		s += " and " + Words(int64(cents)) + " " + plural(int64(cents), "cent")
+	}
+	return s, nil
+}
+
+func plural(n int64, unit string) string {
+	if n == 1 || n == -1 {
+		return unit
+	}
+	return unit + "s"
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "zero"},
+		{7, "seven"},
+		{13, "thirteen"},
+		{40, "forty"},
+		{42, "forty-two"},
+		{100, "one hundred"},
+		{101, "one hundred one"},
+		{999, "nine hundred ninety-nine"},
+		{1000, "one thousand"},
+		{1001, "one thousand one"},
+		{1234, "one thousand two hundred thirty-four"},
+		{90017, "ninety thousand seventeen"},
+		{1000000, "one million"},
+		{1002003, "one million two thousand three"},
+		{7000000000, "seven billion"},
+		{-1, "minus one"},
+		{-1234, "minus one thousand two hundred thirty-four"},
+		{math.MaxInt64, "nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred seven"},
+		{math.MinInt64, "minus nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.n); got != tt.want {
+			t.Errorf("Words(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestDollars(t *testing.T) {
+	tests := []struct {
+		dollars int64
+		cents   int
+		want    string
+	}{
+		{12, 50, "twelve dollars and fifty cents"},
+		{1, 1, "one dollar and one cent"},
+		{0, 99, "zero dollars and ninety-nine cents"},
+		{0, 0, "zero dollars"},
+		{1500, 0, "one thousand five hundred dollars"},
+		{-1, 0, "minus one dollar"},
+		{-5, 50, "minus five dollars and fifty cents"},
+	}
+	for _, tt := range tests {
+		got, err := Dollars(tt.dollars, tt.cents)
+		if err != nil {
+			t.Errorf("Dollars(%d, %d) error: %v", tt.dollars, tt.cents, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Dollars(%d, %d) = %q, want %q", tt.dollars, tt.cents, got, tt.want)
+		}
+	}
+
+	for _, cents := range []int{-1, 100} {
+		if _, err := Dollars(1, cents); err == nil {
+			t.Errorf("Dollars(1, %d) should fail", cents)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/stats"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Find the outliers of a series of numbers, e.g. unusual sensor readings or response times, with the interquartile range method by default or the z-score method. The function returns the outliers with their positions in the series, and the bounds outside of which a number is an outlier.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Numbers   []float64 `json:"numbers" jsonschema:"description=The series of numbers"`
+	Method    string    `json:"method,omitempty" jsonschema:"description=The detection method: iqr flags the numbers beyond the quartiles by a multiple of the interquartile range and zscore the numbers a number of standard deviations from the mean. Defaults to iqr,enum=iqr,enum=zscore"`
+	Threshold float64   `json:"threshold,omitempty" jsonschema:"description=The multiple of the interquartile range or the number of standard deviations. Defaults to 1.5 for iqr and 3 for zscore,minimum=0"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "outliers", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x104}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "numbers", len(msg.Numbers), "method", msg.Method, "threshold", msg.Threshold)
+
+	r, err := Detect(msg.Numbers, msg.Method, msg.Threshold)
+	if err != nil {
+		slog.Warn("[sfn] Detect error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not find the outliers: %v", err))
+		return
+	}
+
+	result := r.String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Methods of detection.
+const (
+	IQR    = "iqr"
+	ZScore = "zscore"
+)
+
+// The default thresholds: Tukey's fences, and 3 standard deviations.
+const (
+	defaultIQRThreshold    = 1.5
+	defaultZScoreThreshold = 3.0
+)
+
+// minIQRSample is the smallest sample whose quartiles make sense.
+const minIQRSample = 4
+
+// Outlier is a number of the series out of the bounds.
+type Outlier struct {
+	// Index is the position of the number in the series, from 0.
+	Index int
+	Value float64
+}
+
+// Result is the outcome of a detection.
+type Result struct {
+	Method    string
+	Threshold float64
+	Count     int
+	// Low and High are the bounds, a number outside of them is an
+	// outlier.
+	Low, High float64
+	Outliers  []Outlier
+	// TooSmall explains why the sample is too small for the method, the
+	// other fields but Method, Threshold and Count are then unset.
+	TooSmall string
+}
+
+func (r Result) String() string {
+	if r.TooSmall != "" {
+		return fmt.Sprintf("No outliers can be found in %d numbers with the %s method: %s.", r.Count, r.Method, r.TooSmall)
+	}
+
+	s := fmt.Sprintf("With the %s method (threshold %s) the bounds of the %d numbers are %s to %s: ", r.Method, stats.Format(r.Threshold), r.Count, stats.Format(r.Low), stats.Format(r.High))
+	if len(r.Outliers) == 0 {
+		return s + "there are no outliers."
+	}
+	parts := make([]string, len(r.Outliers))
+	for i, o := range r.Outliers {
+		parts[i] = fmt.Sprintf("%s at index %d", stats.Format(o.Value), o.Index)
+	}
+	noun := "outliers"
+	if len(r.Outliers) == 1 {
+		noun = "outlier"
+	}
+	return s + fmt.Sprintf("%d %s, %s.", len(r.Outliers), noun, strings.Join(parts, ", "))
+}
+
+// Detect finds the outliers of numbers with method, the empty one meaning
+// IQR, and threshold, 0 meaning the default of the method.
+func Detect(numbers []float64, method string, threshold float64) (Result, error) {
+	if len(numbers) == 0 {
+		return Result{}, errors.New("the series is empty")
+	}
+	for _, x := range numbers {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return Result{}, fmt.Errorf("%v is not a finite number", x)
+		}
+	}
+	if threshold < 0 {
+		return Result{}, fmt.Errorf("the threshold must be positive, got %v", threshold)
+	}
+
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		method = IQR
+	}
+	r := Result{Method: method, Threshold: threshold, Count: len(numbers)}
+	switch method {
+	case IQR:
+		if r.Threshold == 0 {
+			r.Threshold = defaultIQRThreshold
+		}
+		if len(numbers) < minIQRSample {
+			r.TooSmall = fmt.Sprintf("the quartiles need at least %d numbers", minIQRSample)
+			return r, nil
+		}
+		sorted := stats.Sorted(numbers)
+		q1, q3 := stats.Percentile(sorted, 25), stats.Percentile(sorted, 75)
+		r.Low, r.High = q1-r.Threshold*(q3-q1), q3+r.Threshold*(q3-q1)
+	case ZScore:
+		if r.Threshold == 0 {
+			r.Threshold = defaultZScoreThreshold
+		}
+		// the z-score of a number of a sample of n can not exceed
+		// (n-1)/sqrt(n), whatever its distance from the others
+		n := float64(len(numbers))
+		if limit := (n - 1) / math.Sqrt(n); limit <= r.Threshold {
+			r.TooSmall = fmt.Sprintf("no z-score can exceed %s in so few numbers, give more numbers, a lower threshold or use the iqr method", stats.Format(limit))
+			return r, nil
+		}
+		mean, sd := stats.Mean(numbers), stats.StdDev(numbers)
+		r.Low, r.High = mean-r.Threshold*sd, mean+r.Threshold*sd
+	default:
+		return Result{}, fmt.Errorf("unknown method %q, use iqr or zscore", method)
+	}
+
+	for i, x := range numbers {
+		if x < r.Low || x > r.High {
+			r.Outliers = append(r.Outliers, Outlier{Index: i, Value: x})
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// readings are temperatures of a sensor in °C with one faulty reading.
+var readings = []float64{21.2, 21.5, 20.9, 21.1, 21.4, 21.0, 21.3, 35.8, 21.2, 20.8, 21.6, 21.1}
+
+func TestDetectIQR(t *testing.T) {
+	r, err := Detect(readings, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the quartiles are 21.075 and 21.425
+	if math.Abs(r.Low-20.55) > 1e-9 || math.Abs(r.High-21.95) > 1e-9 {
+		t.Errorf("bounds = %v to %v, want 20.55 to 21.95", r.Low, r.High)
+	}
+	if len(r.Outliers) != 1 || r.Outliers[0] != (Outlier{Index: 7, Value: 35.8}) {
+		t.Errorf("outliers = %v, want 35.8 at index 7", r.Outliers)
+	}
+	want := "With the iqr method (threshold 1.5) the bounds of the 12 numbers are 20.55 to 21.95: 1 outlier, 35.8 at index 7."
+	if got := r.String(); got != want {
+		t.Errorf("String() = %s\nwant %s", got, want)
+	}
+
+	// a wide fence lets the faulty reading in
+	r, err = Detect(readings, "iqr", 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Outliers) != 0 {
+		t.Errorf("outliers at 50 interquartile ranges = %v, want none", r.Outliers)
+	}
+}
+
+func TestDetectZScore(t *testing.T) {
+	r, err := Detect(readings, "ZScore", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Outliers) != 1 || r.Outliers[0].Index != 7 {
+		t.Errorf("outliers = %v, want 35.8 at index 7", r.Outliers)
+	}
+}
+
+func TestDetectSmallSamples(t *testing.T) {
+	r, err := Detect([]float64{1, 2, 100}, "iqr", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.String(), "No outliers can be found in 3 numbers with the iqr method: the quartiles need at least 4 numbers."; got != want {
+		t.Errorf("String() = %s\nwant %s", got, want)
+	}
+
+	// the z-score of 1000 among 5 numbers is only 1.79
+	r, err = Detect([]float64{1, 2, 3, 2, 1000}, "zscore", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.TooSmall == "" || len(r.Outliers) != 0 {
+		t.Errorf("Detect() = %+v, want the sample too small", r)
+	}
+	if r, _ := Detect([]float64{1, 2, 3, 2, 1000}, "zscore", 1.5); r.TooSmall != "" || len(r.Outliers) != 1 {
+		t.Errorf("Detect() at 1.5 = %+v, want 1000 flagged", r)
+	}
+
+	// identical numbers have no outliers
+	r, err = Detect([]float64{5, 5, 5, 5, 5}, "iqr", 0)
+	if err != nil || len(r.Outliers) != 0 {
+		t.Errorf("Detect() of identical numbers = %+v, %v, want no outliers", r, err)
+	}
+}
+
+func TestDetectErrors(t *testing.T) {
+	tests := []struct {
+		numbers   []float64
+		method    string
+		threshold float64
+	}{
+		{nil, "", 0},
+		{readings, "mad", 0},
+		{readings, "iqr", -1},
+		{[]float64{1, math.NaN(), 3, 4}, "iqr", 0},
+	}
+	for _, tt := range tests {
+		if _, err := Detect(tt.numbers, tt.method, tt.threshold); err == nil {
+			t.Errorf("Detect(%v, %q, %v) should fail", tt.numbers, tt.method, tt.threshold)
+		}
+	}
+}
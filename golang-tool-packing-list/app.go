@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Suggest a packing list for a trip starting today from the weather forecast of the destination, e.g. a rain jacket when rain is likely, layers when it is cold and sunscreen when the UV index is high. Give the destination as a city name with its state and country code when known, or as Latitude and Longitude geo coordinates in decimal format. The forecast covers the first 8 days of the trip. The function returns the list of items with the reason for each one.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	City      string   `json:"city,omitempty" jsonschema:"description=The destination city with its state and country code when known,example=Oslo, NO"`
+	Latitude  *float64 `json:"latitude,omitempty" jsonschema:"description=The latitude of the destination in decimal format when no city is given,minimum=-90,maximum=90"`
+	Longitude *float64 `json:"longitude,omitempty" jsonschema:"description=The longitude of the destination in decimal format when no city is given,minimum=-180,maximum=180"`
+	Days      int      `json:"days" jsonschema:"description=The length of the trip in days,minimum=1,maximum=30"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "packing-list", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xFB}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "city", msg.City, "days", msg.Days)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := PackingList(reqCtx, msg)
+	if err != nil {
+		slog.Warn("[sfn] PackingList error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not suggest a packing list: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// maxDays is the longest trip accepted.
+const maxDays = 30
+
+// PackingList locates the destination of p, fetches its daily forecast and
+// suggests what to pack.
+func PackingList(ctx context.Context, p Parameter) (string, error) {
+	if p.Days < 1 || p.Days > maxDays {
+		return "", fmt.Errorf("a trip lasts 1 to %d days, got %d", maxDays, p.Days)
+	}
+
+	var (
+		place    string
+		lat, lon float64
+	)
+	switch {
+	case strings.TrimSpace(p.City) != "":
+		l, err := client.Locate(ctx, p.City)
+		if err != nil {
+			return "", err
+		}
+		place, lat, lon = l.String(), l.Latitude, l.Longitude
+	case p.Latitude != nil && p.Longitude != nil:
+		lat, lon = *p.Latitude, *p.Longitude
+		if err := geo.ValidateCoordinate(lat, lon); err != nil {
+			return "", err
+		}
+		place = fmt.Sprintf("%v,%v", lat, lon)
+	default:
+		return "", errors.New("give the destination as a city or as coordinates")
+	}
+
+	days, err := client.Daily(ctx, lat, lon)
+	if err != nil {
+		return "", err
+	}
+	days = days[:min(p.Days, len(days))]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Packing list for %s in %s", daysOf(p.Days), place)
+	s := Summarize(days)
+	fmt.Fprintf(&b, ", forecast %s to %s: %.0f°C to %.0f°C", days[0].Date.Format("2006-01-02"), days[len(days)-1].Date.Format("2006-01-02"), s.TempMin, s.TempMax)
+	if s.WetDays > 0 {
+		fmt.Fprintf(&b, ", rain or snow likely on %d of %d days", s.WetDays, len(days))
+	}
+	if len(days) < p.Days {
+		fmt.Fprintf(&b, " (the forecast covers the first %d days)", len(days))
+	}
+	b.WriteString(":")
+	for _, item := range Pack(s, p.Days) {
+		fmt.Fprintf(&b, "\n- %s", item)
+	}
+	return b.String(), nil
+}
+
+func daysOf(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// Summary is the weather of a trip that matters for packing.
+type Summary struct {
+	Days             int
+	TempMin, TempMax float64
+	// WetDays are the days likely to have rain or snow, SnowDays those
+	// likely to have snow.
+	WetDays, SnowDays int
+	UVIndex           float64
+	WindSpeed         float64
+}
+
+// likely is the chance of precipitation from which a day counts as wet.
+const likely = 0.5
+
+// Summarize sums up the forecast days.
+func Summarize(days []weather.Day) Summary {
+	s := Summary{Days: len(days), TempMin: math.Inf(1), TempMax: math.Inf(-1)}
+	for _, d := range days {
+		s.TempMin = math.Min(s.TempMin, d.TempMin)
+		s.TempMax = math.Max(s.TempMax, d.TempMax)
+		s.UVIndex = math.Max(s.UVIndex, d.UVIndex)
+		s.WindSpeed = math.Max(s.WindSpeed, d.WindSpeed)
+		if d.PrecipitationChance >= likely && d.Rain+d.Snow >= 1 {
+			s.WetDays++
+			if d.Snow > 0 {
+				s.SnowDays++
+			}
+		}
+	}
+	return s
+}
+
+// Item is a thing to pack and why.
+type Item struct {
+	Name   string
+	Reason string
+}
+
+func (i Item) String() string {
+	return i.Name + ": " + i.Reason
+}
+
+// rule adds an item to the list if the weather calls for it.
+type rule func(s Summary, tripDays int) (Item, bool)
+
+// rules are the packing rules, in the order of the list.
+var rules = []rule{
+	func(s Summary, tripDays int) (Item, bool) {
+		return Item{"clothes and underwear", "for " + daysOf(tripDays)}, true
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"rain jacket or umbrella", fmt.Sprintf("rain is likely on %d of %d days", s.WetDays-s.SnowDays, s.Days)}, s.WetDays > s.SnowDays
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"waterproof shoes", "more than half of the days are wet"}, s.WetDays*2 > s.Days
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"snow boots", fmt.Sprintf("snow is likely on %d of %d days", s.SnowDays, s.Days)}, s.SnowDays > 0
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"warm coat", fmt.Sprintf("it gets as cold as %.0f°C", s.TempMin)}, s.TempMin < 5
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"hat, gloves and scarf", "it freezes"}, s.TempMin < 0
+	},
+	func(s Summary, _ int) (Item, bool) {
+		if s.TempMin < 12 {
+			return Item{"sweater or fleece to layer", fmt.Sprintf("it gets as cool as %.0f°C", s.TempMin)}, true
+		}
+		return Item{"sweater or fleece to layer", fmt.Sprintf("the temperature swings from %.0f°C to %.0f°C", s.TempMin, s.TempMax)}, s.TempMax-s.TempMin >= 12
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"shorts and t-shirts", fmt.Sprintf("it gets as warm as %.0f°C", s.TempMax)}, s.TempMax >= 25
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"reusable water bottle", "it gets hot"}, s.TempMax >= 30
+	},
+	func(s Summary, _ int) (Item, bool) {
+		risk, _ := weather.UVRisk(s.UVIndex)
+		return Item{"sunscreen", fmt.Sprintf("the UV index reaches %.0f, %s", s.UVIndex, risk)}, math.Round(s.UVIndex) >= 3
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"sunglasses and a sun hat", "the sun is strong"}, math.Round(s.UVIndex) >= 6
+	},
+	func(s Summary, _ int) (Item, bool) {
+		return Item{"windbreaker", fmt.Sprintf("the wind reaches %.0f m/s", s.WindSpeed)}, s.WindSpeed >= 10
+	},
+}
+
+// Pack applies the rules to the weather of a trip of tripDays.
+func Pack(s Summary, tripDays int) []Item {
+	var items []Item
+	for _, r := range rules {
+		if item, ok := r(s, tripDays); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func names(items []Item) string {
+	var n []string
+	for _, i := range items {
+		n = append(n, i.Name)
+	}
+	return strings.Join(n, "; ")
+}
+
+func TestPackColdRainy(t *testing.T) {
+	s := Summarize([]weather.Day{
+		{TempMin: 2, TempMax: 8, PrecipitationChance: 0.9, Rain: 6, UVIndex: 1, WindSpeed: 7},
+		{TempMin: -1, TempMax: 4, PrecipitationChance: 0.8, Rain: 1, Snow: 3, UVIndex: 1, WindSpeed: 11},
+		{TempMin: 1, TempMax: 7, PrecipitationChance: 0.6, Rain: 2.5, UVIndex: 2, WindSpeed: 5},
+		{TempMin: 3, TempMax: 9, PrecipitationChance: 0.2, Rain: 0.3, UVIndex: 2, WindSpeed: 4},
+	})
+	items := Pack(s, 4)
+
+	want := "clothes and underwear; rain jacket or umbrella; waterproof shoes; snow boots; warm coat; hat, gloves and scarf; sweater or fleece to layer; windbreaker"
+	if got := names(items); got != want {
+		t.Errorf("Pack() = %s\nwant %s", got, want)
+	}
+	reasons := map[string]string{
+		"rain jacket or umbrella": "rain is likely on 2 of 4 days",
+		"snow boots":              "snow is likely on 1 of 4 days",
+		"warm coat":               "it gets as cold as -1°C",
+		"windbreaker":             "the wind reaches 11 m/s",
+	}
+	for _, item := range items {
+		if want, ok := reasons[item.Name]; ok && item.Reason != want {
+			t.Errorf("reason of %s = %q, want %q", item.Name, item.Reason, want)
+		}
+	}
+}
+
+func TestPackHotDry(t *testing.T) {
+	s := Summarize([]weather.Day{
+		{TempMin: 22, TempMax: 33, UVIndex: 9.2, WindSpeed: 3},
+		{TempMin: 23, TempMax: 35, UVIndex: 9.8, WindSpeed: 4, PrecipitationChance: 0.1},
+		{TempMin: 21, TempMax: 31, UVIndex: 8.5, WindSpeed: 2},
+	})
+	items := Pack(s, 10)
+
+	want := "clothes and underwear; sweater or fleece to layer; shorts and t-shirts; reusable water bottle; sunscreen; sunglasses and a sun hat"
+	if got := names(items); got != want {
+		t.Errorf("Pack() = %s\nwant %s", got, want)
+	}
+	if items[0].Reason != "for 10 days" {
+		t.Errorf("clothes reason = %q, want for 10 days", items[0].Reason)
+	}
+	if items[1].Reason != "the temperature swings from 21°C to 35°C" {
+		t.Errorf("layers reason = %q", items[1].Reason)
+	}
+	if items[4].Reason != "the UV index reaches 10, very high" {
+		t.Errorf("sunscreen reason = %q", items[4].Reason)
+	}
+}
+
+func TestPackMild(t *testing.T) {
+	s := Summarize([]weather.Day{{TempMin: 14, TempMax: 22, UVIndex: 2, WindSpeed: 3}})
+	if got := names(Pack(s, 1)); got != "clothes and underwear" {
+		t.Errorf("Pack() = %s, want only the clothes", got)
+	}
+}
+
+func TestPackingList(t *testing.T) {
+	// noon of 2024-08-07 and 2024-08-08 in UTC+2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/geo/1.0/direct":
+			fmt.Fprint(w, `[{"name":"Oslo","lat":59.9133,"lon":10.7389,"country":"NO"}]`)
+		case "/data/3.0/onecall":
+			fmt.Fprint(w, `{"timezone_offset":7200,"daily":[
+				{"dt":1723024800,"temp":{"min":11,"max":17},"wind_speed":4,"pop":0.9,"rain":7,"uvi":3.4},
+				{"dt":1723111200,"temp":{"min":10,"max":19},"wind_speed":3,"pop":0.1,"uvi":4.1}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := PackingList(context.Background(), Parameter{City: "Oslo, NO", Days: 5})
+	if err != nil {
+		t.Fatalf("PackingList() error = %v", err)
+	}
+	want := `Packing list for 5 days in Oslo, NO, forecast 2024-08-07 to 2024-08-08: 10°C to 19°C, rain or snow likely on 1 of 2 days (the forecast covers the first 2 days):
+- clothes and underwear: for 5 days
+- rain jacket or umbrella: rain is likely on 1 of 2 days
+- sweater or fleece to layer: it gets as cool as 10°C
+- sunscreen: the UV index reaches 4, moderate`
+	if got != want {
+		t.Errorf("PackingList() =\n%s\nwant\n%s", got, want)
+	}
+
+	lat, lon := 59.9133, 10.7389
+	got, err = PackingList(context.Background(), Parameter{Latitude: &lat, Longitude: &lon, Days: 1})
+	if err != nil {
+		t.Fatalf("PackingList() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "Packing list for 1 day in 59.9133,10.7389, forecast 2024-08-07 to 2024-08-07: 11°C to 17°C") {
+		t.Errorf("PackingList() = %s", got)
+	}
+
+	bad := 95.0
+	for _, p := range []Parameter{
+		{City: "Oslo", Days: 0},
+		{City: "Oslo", Days: 31},
+		{Days: 3},
+		{Latitude: &bad, Longitude: &lon, Days: 3},
+	} {
+		if _, err := PackingList(context.Background(), p); err == nil {
+			t.Errorf("PackingList(%+v) should fail", p)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/color"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Generate a color palette from a base color with a color harmony scheme: complementary, triadic, analogous or monochromatic, e.g. "give me a triadic palette for #3366cc". Always use this function instead of computing the colors yourself. The function returns the hex codes of the palette, the base color first.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Color  string `json:"color" jsonschema:"description=The base color as a hex code or rgb() or a basic color name,example=#3366cc"`
+	Scheme string `json:"scheme" jsonschema:"description=The color harmony of the palette,enum=complementary,enum=triadic,enum=analogous,enum=monochromatic"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "palette", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xED}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "color", msg.Color, "scheme", msg.Scheme)
+
+	base, err := color.Parse(msg.Color)
+	if err != nil {
+		slog.Warn("[sfn] Parse error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not generate the palette: %v", err))
+		return
+	}
+	palette, err := Palette(base, msg.Scheme)
+	if err != nil {
+		slog.Warn("[sfn] Palette error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not generate the palette: %v", err))
+		return
+	}
+
+	hsl := base.HSL()
+	codes := make([]string, len(palette))
+	for i, c := range palette {
+		codes[i] = c.Hex()
+	}
+	result := fmt.Sprintf("%s palette of %s (hue %.0f°, saturation %.0f%%, lightness %.0f%%): %s",
+		strings.ToUpper(msg.Scheme[:1])+strings.ToLower(msg.Scheme[1:]), base.Hex(), hsl.H, hsl.S*100, hsl.L*100, strings.Join(codes, ", "))
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Palette returns the colors of the scheme for base, base first:
+//   - complementary: the opposite hue, 180° away
+//   - triadic: the hues 120° and 240° away
+//   - analogous: the neighbor hues 30° below and above
+//   - monochromatic: the same hue, two shades darker and two tints lighter,
+//     from darkest to lightest, base in the middle
+//
+// The hues are rotated in the HSL space, keeping the saturation and the
+// lightness of base.
+func Palette(base color.RGB, scheme string) ([]color.RGB, error) {
+	hsl := base.HSL()
+	rotate := func(degrees ...float64) []color.RGB {
+		palette := []color.RGB{base}
+		for _, d := range degrees {
+			palette = append(palette, color.HSL{H: hsl.H + d, S: hsl.S, L: hsl.L}.RGB())
+		}
+		return palette
+	}
+
+	switch strings.ToLower(strings.TrimSpace(scheme)) {
+	case "complementary":
+		return rotate(180), nil
+	case "triadic":
+		return rotate(120, 240), nil
+	case "analogous":
+		return rotate(-30, 30), nil
+	case "monochromatic":
+		return monochromatic(base), nil
+	}
+	return nil, fmt.Errorf("unknown scheme %q, use complementary, triadic, analogous or monochromatic", scheme)
+}
+
+// The lightness range of the monochromatic palette, so that its darkest and
+// lightest colors still show the hue.
+const (
+	minLightness = 0.05
+	maxLightness = 0.95
+)
+
+// monochromatic returns two shades and two tints of base, which divide the
+// lightness between base and the ends of the range in thirds, e.g. 20%, 35%,
+// 50%, 65% and 80% for a base of 50%.
+func monochromatic(base color.RGB) []color.RGB {
+	hsl := base.HSL()
+	shade := func(l float64) color.RGB {
+		return color.HSL{H: hsl.H, S: hsl.S, L: l}.RGB()
+	}
+	dark := (hsl.L - minLightness) / 3
+	light := (maxLightness - hsl.L) / 3
+	return []color.RGB{
+		shade(hsl.L - 2*dark),
+		shade(hsl.L - dark),
+		base,
+		shade(hsl.L + light),
+		shade(hsl.L + 2*light),
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/color"
+)
+
+func TestPalette(t *testing.T) {
+	tests := []struct {
+		base   string
+		scheme string
+		want   string
+	}{
+		{"#ff0000", "complementary", "#ff0000 #00ffff"},
+		{"#ff0000", "triadic", "#ff0000 #00ff00 #0000ff"},
+		{"#ff0000", "analogous", "#ff0000 #ff0080 #ff8000"},
+		{"#ff0000", "monochromatic", "#660000 #b30000 #ff0000 #ff4d4d #ff9999"},
+		// #3366cc is 220°, 60%, 50%
+		{"#3366cc", "complementary", "#3366cc #cc9933"},
+		{"#3366cc", "Triadic", "#3366cc #cc3366 #66cc33"},
+		{"#3366cc", "monochromatic", "#142952 #24478f #3366cc #7094db #adc2eb"},
+		// a gray has no hue to rotate
+		{"#808080", "triadic", "#808080 #808080 #808080"},
+	}
+	for _, tt := range tests {
+		base, err := color.Parse(tt.base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		palette, err := Palette(base, tt.scheme)
+		if err != nil {
+			t.Errorf("Palette(%s, %s) error = %v", tt.base, tt.scheme, err)
+			continue
+		}
+		codes := make([]string, len(palette))
+		for i, c := range palette {
+			codes[i] = c.Hex()
+		}
+		if got := strings.Join(codes, " "); got != tt.want {
+			t.Errorf("Palette(%s, %s) = %s, want %s", tt.base, tt.scheme, got, tt.want)
+		}
+	}
+
+	if _, err := Palette(color.RGB{}, "tetradic"); err == nil {
+		t.Error("Palette() of an unknown scheme should fail")
+	}
+}
+
+func TestMonochromaticNearWhite(t *testing.T) {
+	// the tints get closer to the end of the range but stay distinct
+	base := color.HSL{H: 200, S: 0.8, L: 0.9}.RGB()
+	palette := monochromatic(base)
+	for i := 1; i < len(palette); i++ {
+		if palette[i].HSL().L <= palette[i-1].HSL().L {
+			t.Fatalf("monochromatic(%s) is not from dark to light: %v", base.Hex(), palette)
+		}
+	}
+	if l := palette[len(palette)-1].HSL().L; l > maxLightness+0.01 {
+		t.Errorf("the lightest tint is %v, above %v", l, maxLightness)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Parse a free-form postal address into its components: street, city, region, postal code and country. Pass the address as the user wrote it, partial addresses like a street and a city are fine. The function returns a JSON object of the components that were found and lists the missing ones.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Address string `json:"address" jsonschema:"description=The free-form address e.g. 10 Downing St London"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "parse-address", Description: Description(), InputSchema: InputSchema(), Upstream: geocoder.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xDC}
+}
+
+// geocoder is the OpenStreetMap Nominatim API, whose usage policy asks
+// clients to identify themselves with a User-Agent.
+var geocoder = &Nominatim{
+	BaseURL:    "https://nominatim.openstreetmap.org",
+	UserAgent:  "yomo-llm-parse-address (https://github.com/yomorun/llm-function-calling-examples)",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// an address can be personal data, it is not logged
+	slog.Info("[sfn] << receive", "address_length", len(msg.Address))
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	address, err := ParseAddress(reqCtx, geocoder, msg.Address)
+	if err != nil {
+		slog.Warn("[sfn] ParseAddress error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not parse the address: %v", err))
+		return
+	}
+
+	buf, _ := json.Marshal(address)
+	sfn.WriteText(ctx, string(buf))
+}
+
+// maxAddressLength bounds the address in characters.
+const maxAddressLength = 300
+
+// ErrNoMatch is returned when the geocoder knows no place for the address.
+var ErrNoMatch = errors.New("no place matches the address")
+
+// Address holds the components found in an address.
+type Address struct {
+	Street      string `json:"street,omitempty"`
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	// Formatted is the full address of the matched place.
+	Formatted string `json:"formatted,omitempty"`
+	// Missing names the components that were not found.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// ParseAddress validates address and splits it into its components with g.
+func ParseAddress(ctx context.Context, g *Nominatim, address string) (*Address, error) {
+	address = strings.Join(strings.Fields(address), " ")
+	if address == "" {
+		return nil, errors.New("the address is empty")
+	}
+	if utf8.RuneCountInString(address) > maxAddressLength {
+		return nil, fmt.Errorf("the address is limited to %d characters", maxAddressLength)
+	}
+
+	a, err := g.Search(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range []struct {
+		name, value string
+	}{
+		{"street", a.Street},
+		{"city", a.City},
+		{"region", a.Region},
+		{"postalCode", a.PostalCode},
+		{"country", a.Country},
+	} {
+		if c.value == "" {
+			a.Missing = append(a.Missing, c.name)
+		}
+	}
+	return a, nil
+}
+
+// Nominatim is a client of the OpenStreetMap geocoder, see
+// https://nominatim.org/release-docs/latest/api/Search/.
+type Nominatim struct {
+	BaseURL    string
+	UserAgent  string
+	HTTPClient *http.Client
+}
+
+// Search returns the components of the best match of address, in English
+// where the names have a translation.
+func (n *Nominatim) Search(ctx context.Context, address string) (*Address, error) {
+	q := url.Values{}
+	q.Set("q", address)
+	q.Set("format", "jsonv2")
+	q.Set("addressdetails", "1")
+	q.Set("limit", "1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.BaseURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", n.UserAgent)
+	req.Header.Set("Accept-Language", "en")
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the geocoder responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var places []struct {
+		DisplayName string            `json:"display_name"`
+		Address     map[string]string `json:"address"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("decode the geocoder response: %w", err)
+	}
+	if len(places) == 0 {
+		return nil, ErrNoMatch
+	}
+
+	p := places[0].Address
+	// a house number alone is no street
+	var street string
+	if road := first(p, "road", "pedestrian", "footway", "square"); road != "" {
+		street = strings.TrimSpace(p["house_number"] + " " + road)
+	}
+	return &Address{
+		Street:      street,
+		City:        first(p, "city", "town", "village", "hamlet", "municipality"),
+		Region:      first(p, "state", "province", "region", "state_district", "county"),
+		PostalCode:  p["postcode"],
+		Country:     p["country"],
+		CountryCode: strings.ToUpper(p["country_code"]),
+		Formatted:   places[0].DisplayName,
+	}, nil
+}
+
+// first returns the first of keys that has a value in m.
+func first(m map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := m[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
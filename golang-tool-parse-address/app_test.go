@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// places are trimmed responses of the Nominatim search.
+var places = map[string]string{
+	"10 Downing St London": `[{"display_name":"10 Downing Street, Westminster, London, Greater London, England, SW1A 2AA, United Kingdom",
+		"address":{"house_number":"10","road":"Downing Street","quarter":"Westminster","city":"London","state_district":"Greater London","state":"England","postcode":"SW1A 2AA","country":"United Kingdom","country_code":"gb"}}]`,
+	"Hauptstrasse Heidelberg": `[{"display_name":"Hauptstraße, Altstadt, Heidelberg, Baden-Württemberg, 69117, Germany",
+		"address":{"road":"Hauptstraße","suburb":"Altstadt","city":"Heidelberg","state":"Baden-Württemberg","postcode":"69117","country":"Germany","country_code":"de"}}]`,
+	"Giverny": `[{"display_name":"Giverny, Évreux, Eure, Normandy, Metropolitan France, 27620, France",
+		"address":{"village":"Giverny","municipality":"Évreux","county":"Eure","state":"Normandy","postcode":"27620","country":"France","country_code":"fr"}}]`,
+	"Iceland": `[{"display_name":"Iceland","address":{"country":"Iceland","country_code":"is"}}]`,
+}
+
+func newGeocoder(t *testing.T) *Nominatim {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/search" || q.Get("format") != "jsonv2" || q.Get("addressdetails") != "1" || r.UserAgent() != "test-agent" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if q.Get("q") == "overloaded" {
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		body, ok := places[q.Get("q")]
+		if !ok {
+			body = "[]"
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return &Nominatim{BaseURL: srv.URL, UserAgent: "test-agent", HTTPClient: srv.Client()}
+}
+
+func TestParseAddress(t *testing.T) {
+	g := newGeocoder(t)
+	tests := []struct {
+		address string
+		want    Address
+	}{
+		{
+			address: " 10 Downing  St\nLondon ",
+			want: Address{Street: "10 Downing Street", City: "London", Region: "England", PostalCode: "SW1A 2AA", Country: "United Kingdom", CountryCode: "GB",
+				Formatted: "10 Downing Street, Westminster, London, Greater London, England, SW1A 2AA, United Kingdom"},
+		},
+		{
+			address: "Hauptstrasse Heidelberg",
+			want: Address{Street: "Hauptstraße", City: "Heidelberg", Region: "Baden-Württemberg", PostalCode: "69117", Country: "Germany", CountryCode: "DE",
+				Formatted: "Hauptstraße, Altstadt, Heidelberg, Baden-Württemberg, 69117, Germany"},
+		},
+		{
+			// a village has no street, and its county is less precise than the state
+			address: "Giverny",
+			want: Address{City: "Giverny", Region: "Normandy", PostalCode: "27620", Country: "France", CountryCode: "FR",
+				Formatted: "Giverny, Évreux, Eure, Normandy, Metropolitan France, 27620, France", Missing: []string{"street"}},
+		},
+		{
+			address: "Iceland",
+			want:    Address{Country: "Iceland", CountryCode: "IS", Formatted: "Iceland", Missing: []string{"street", "city", "region", "postalCode"}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseAddress(context.Background(), g, tt.address)
+		if err != nil {
+			t.Errorf("ParseAddress(%q) error = %v", tt.address, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("ParseAddress(%q) = %+v, want %+v", tt.address, *got, tt.want)
+		}
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	g := newGeocoder(t)
+	if _, err := ParseAddress(context.Background(), g, "nowhere at all"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("ParseAddress() of an unknown place error = %v, want ErrNoMatch", err)
+	}
+	if _, err := ParseAddress(context.Background(), g, "overloaded"); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("ParseAddress() of an error response = %v", err)
+	}
+	for _, address := range []string{"", " \n ", strings.Repeat("a", maxAddressLength+1)} {
+		if _, err := ParseAddress(context.Background(), g, address); err == nil {
+			t.Errorf("ParseAddress(%q) should fail", address)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Resolve a relative date phrase in English to an absolute date, e.g. "next Friday", "in 3 days", "2 weeks ago", "tomorrow" or "last month". The phrase is relative to today unless another current date is given. Always use this function instead of working out dates from a weekday yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Phrase string `json:"phrase" jsonschema:"description=The relative date phrase,example=next Friday"`
+	Now    string `json:"now,omitempty" jsonschema:"description=The current date the phrase is relative to in the format YYYY-MM-DD. Defaults to today in UTC"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "parse-date", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF7}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "phrase", msg.Phrase, "now", msg.Now)
+
+	now := time.Now().UTC()
+	if msg.Now != "" {
+		var err error
+		if now, err = time.Parse(dateFormat, strings.TrimSpace(msg.Now)); err != nil {
+			sfn.WriteText(ctx, fmt.Sprintf("can not parse the current date %q: use the format YYYY-MM-DD", msg.Now))
+			return
+		}
+	}
+
+	d, err := Parse(msg.Phrase, now)
+	if err != nil {
+		slog.Warn("[sfn] Parse error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not resolve the date: %v", err))
+		return
+	}
+
+	result := fmt.Sprintf("%q is %s, relative to %s", strings.TrimSpace(msg.Phrase), format(d), format(now))
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+const dateFormat = "2006-01-02"
+
+// format writes a date with its weekday, e.g. "Friday 2024-05-17".
+func format(d time.Time) string {
+	return d.Weekday().String() + " " + d.Format(dateFormat)
+}
+
+// ErrUnparseable is returned for a phrase Parse does not understand.
+var ErrUnparseable = errors.New("unrecognized date phrase")
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+type unit int
+
+const (
+	day unit = iota
+	week
+	month
+	year
+)
+
+var units = map[string]unit{
+	"day": day, "days": day,
+	"week": week, "weeks": week,
+	"month": month, "months": month,
+	"year": year, "years": year,
+}
+
+// counts are the number words accepted before a unit, besides digits.
+var counts = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+// maxCount bounds the count of units, so that a phrase can not overflow
+// the year.
+const maxCount = 10000
+
+// Parse resolves a relative date phrase against the date of now. It
+// understands:
+//
+//   - today, tomorrow, yesterday, the day after tomorrow and the day before
+//     yesterday
+//   - a weekday, alone or after "this" or "on", for the first one from today
+//     on, today included
+//   - "next" and "last" a weekday, for the first one after and before today
+//   - "next" and "last" week, month or year, for a unit after or before today
+//   - "in" a count of days, weeks, months or years, or the count followed by
+//     "from now", "from today", "later" or "ago", e.g. "in 3 days" and "two
+//     weeks ago"
+//
+// Adding months keeps the day of the month when it exists and takes the
+// last day of the month otherwise, e.g. one month after January 31 is the
+// end of February.
+func Parse(phrase string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	words := strings.Fields(strings.ToLower(strings.Trim(strings.TrimSpace(phrase), ".!?")))
+	if len(words) > 0 && words[0] == "the" {
+		words = words[1:]
+	}
+	s := strings.Join(words, " ")
+
+	switch s {
+	case "today", "now":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "day after tomorrow":
+		return today.AddDate(0, 0, 2), nil
+	case "day before yesterday":
+		return today.AddDate(0, 0, -2), nil
+	}
+
+	switch len(words) {
+	case 1:
+		if wd, ok := weekdays[words[0]]; ok {
+			return onOrAfter(today, wd), nil
+		}
+	case 2:
+		if wd, ok := weekdays[words[1]]; ok {
+			switch words[0] {
+			case "this", "on", "coming":
+				return onOrAfter(today, wd), nil
+			case "next":
+				return onOrAfter(today.AddDate(0, 0, 1), wd), nil
+			case "last", "past", "previous":
+				return onOrBefore(today.AddDate(0, 0, -1), wd), nil
+			}
+		}
+		if u, ok := units[words[1]]; ok && !strings.HasSuffix(words[1], "s") {
+			switch words[0] {
+			case "next":
+				return add(today, u, 1), nil
+			case "last", "past", "previous":
+				return add(today, u, -1), nil
+			}
+		}
+	}
+
+	if n, u, sign, ok := offset(words); ok {
+		return add(today, u, sign*n), nil
+	}
+	return time.Time{}, fmt.Errorf("%w %q, try e.g. \"next Friday\", \"in 3 days\" or \"2 weeks ago\"", ErrUnparseable, strings.TrimSpace(phrase))
+}
+
+// offset parses "in <count> <unit>" and "<count> <unit> from now|from
+// today|later|ago".
+func offset(words []string) (n int, u unit, sign int, ok bool) {
+	var rest []string
+	switch {
+	case len(words) == 3 && words[0] == "in":
+		rest, sign = words[1:], 1
+	case len(words) == 3 && (words[2] == "ago" || words[2] == "later"):
+		rest, sign = words[:2], 1
+		if words[2] == "ago" {
+			sign = -1
+		}
+	case len(words) == 4 && words[2] == "from" && (words[3] == "now" || words[3] == "today"):
+		rest, sign = words[:2], 1
+	default:
+		return 0, 0, 0, false
+	}
+
+	n, ok = count(rest[0])
+	if !ok {
+		return 0, 0, 0, false
+	}
+	u, ok = units[rest[1]]
+	return n, u, sign, ok
+}
+
+func count(word string) (int, bool) {
+	if n, ok := counts[word]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(word)
+	if err != nil || n < 0 || n > maxCount {
+		return 0, false
+	}
+	return n, true
+}
+
+func onOrAfter(d time.Time, wd time.Weekday) time.Time {
+	return d.AddDate(0, 0, (int(wd)-int(d.Weekday())+7)%7)
+}
+
+func onOrBefore(d time.Time, wd time.Weekday) time.Time {
+	return d.AddDate(0, 0, -((int(d.Weekday()) - int(wd) + 7) % 7))
+}
+
+func add(d time.Time, u unit, n int) time.Time {
+	switch u {
+	case week:
+		return d.AddDate(0, 0, 7*n)
+	case month:
+		return addMonths(d, n)
+	case year:
+		return addMonths(d, 12*n)
+	}
+	return d.AddDate(0, 0, n)
+}
+
+// addMonths adds n months to d, clamping the day to the end of the month
+// instead of spilling into the next one like time.AddDate.
+func addMonths(d time.Time, n int) time.Time {
+	first := time.Date(d.Year(), d.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d.Day(), last)-1)
+}
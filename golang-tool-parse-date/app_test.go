@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2024, 5, 15, 17, 30, 0, 0, time.UTC)
+	tests := []struct {
+		phrase string
+		want   string
+	}{
+		{"today", "2024-05-15"},
+		{"Tomorrow", "2024-05-16"},
+		{"yesterday", "2024-05-14"},
+		{"the day after tomorrow", "2024-05-17"},
+		{"day before yesterday", "2024-05-13"},
+		{"Friday", "2024-05-17"},
+		{"on friday", "2024-05-17"},
+		{"this Wednesday", "2024-05-15"},
+		{"wed", "2024-05-15"},
+		{"monday", "2024-05-20"},
+		{"next Friday", "2024-05-17"},
+		{"next wednesday", "2024-05-22"},
+		{"last Friday", "2024-05-10"},
+		{"last wednesday", "2024-05-08"},
+		{"last tuesday", "2024-05-14"},
+		{"in 3 days", "2024-05-18"},
+		{"in three days.", "2024-05-18"},
+		{"in a week", "2024-05-22"},
+		{"2 weeks ago", "2024-05-01"},
+		{"10 days from now", "2024-05-25"},
+		{"an hour ago", ""},
+		{"next week", "2024-05-22"},
+		{"last month", "2024-04-15"},
+		{"next year", "2025-05-15"},
+		{"in 0 days", "2024-05-15"},
+		{"  In  2   Months ", "2024-07-15"},
+		{"5 years later", "2029-05-15"},
+	}
+	for _, tt := range tests {
+		d, err := Parse(tt.phrase, now)
+		if tt.want == "" {
+			if !errors.Is(err, ErrUnparseable) {
+				t.Errorf("Parse(%q) = %v, %v, want ErrUnparseable", tt.phrase, d, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.phrase, err)
+			continue
+		}
+		if got := d.Format(dateFormat); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.phrase, got, tt.want)
+		}
+	}
+}
+
+func TestParseEndOfMonth(t *testing.T) {
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		phrase string
+		want   string
+	}{
+		{"next month", "2024-02-29"},
+		{"in 2 months", "2024-03-31"},
+		{"in 3 months", "2024-04-30"},
+		{"last month", "2023-12-31"},
+		{"in 1 year", "2025-01-31"},
+	}
+	for _, tt := range tests {
+		d, err := Parse(tt.phrase, now)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.phrase, err)
+			continue
+		}
+		if got := d.Format(dateFormat); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.phrase, got, tt.want)
+		}
+	}
+
+	leap := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	if d, _ := Parse("next year", leap); d.Format(dateFormat) != "2025-02-28" {
+		t.Errorf("Parse(next year) from a leap day = %s, want 2025-02-28", d.Format(dateFormat))
+	}
+}
+
+func TestParseUnparseable(t *testing.T) {
+	now := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+	for _, phrase := range []string{"", "someday", "next weeks", "in many days", "in 3 fortnights", "3 days", "in 99999 years", "next blursday"} {
+		if _, err := Parse(phrase, now); !errors.Is(err, ErrUnparseable) {
+			t.Errorf("Parse(%q) error = %v, want ErrUnparseable", phrase, err)
+		}
+	}
+}
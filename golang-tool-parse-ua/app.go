@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Parse a User-Agent header of an HTTP request, e.g. from a web server log, to find the browser and its version, the operating system and the device type: desktop, mobile, tablet or bot. Crawlers and HTTP libraries are recognized as bots. The function returns the detected browser, operating system and device.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	UserAgent string `json:"user_agent" jsonschema:"description=The User-Agent string to parse,example=Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "parse-ua", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x101}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "user_agent", msg.UserAgent)
+
+	a, err := Parse(msg.UserAgent)
+	if err != nil {
+		slog.Warn("[sfn] Parse error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not parse the user agent: %v", err))
+		return
+	}
+
+	result := a.String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// ErrEmpty is returned for a blank user agent.
+var ErrEmpty = errors.New("the user agent is empty")
+
+// Device types.
+const (
+	Desktop = "desktop"
+	Mobile  = "mobile"
+	Tablet  = "tablet"
+	Bot     = "bot"
+)
+
+// Agent is what a user agent string tells.
+type Agent struct {
+	// Browser is the browser name, or the bot name of a bot, empty when
+	// it is not recognized.
+	Browser string
+	Version string
+	// OS is the operating system with its version when known, e.g.
+	// "Android 14", empty when it is not recognized.
+	OS     string
+	Device string
+}
+
+func (a Agent) String() string {
+	if a.Browser == "" && a.OS == "" {
+		return "The user agent is not recognized, it may be a custom client or a fake one."
+	}
+	if a.Device == Bot {
+		name := a.Browser
+		if name == "" {
+			name = "an unnamed bot"
+		}
+		s := "A bot: " + name
+		if a.Version != "" {
+			s += " " + a.Version
+		}
+		return s + ", an automated client rather than a person."
+	}
+
+	browser := a.Browser
+	if browser == "" {
+		browser = "an unknown browser"
+	} else if a.Version != "" {
+		browser += " " + a.Version
+	}
+	s := "Browser: " + browser
+	if a.OS != "" {
+		s += ", OS: " + a.OS
+	}
+	return s + ", device: " + a.Device + "."
+}
+
+// bots are the crawlers and HTTP libraries by name, matched before the
+// generic bot words.
+var bots = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"Googlebot", regexp.MustCompile(`Googlebot(?:-\w+)?/([\d.]+)`)},
+	{"Bingbot", regexp.MustCompile(`bingbot/([\d.]+)`)},
+	{"Yandex", regexp.MustCompile(`YandexBot/([\d.]+)`)},
+	{"Baidu", regexp.MustCompile(`Baiduspider(?:-\w+)?/([\d.]+)`)},
+	{"DuckDuckBot", regexp.MustCompile(`DuckDuckBot(?:-\w+)?/([\d.]+)`)},
+	{"Applebot", regexp.MustCompile(`Applebot/([\d.]+)`)},
+	{"GPTBot", regexp.MustCompile(`GPTBot/([\d.]+)`)},
+	{"facebookexternalhit", regexp.MustCompile(`facebookexternalhit/([\d.]+)`)},
+	{"Twitterbot", regexp.MustCompile(`Twitterbot/([\d.]+)`)},
+	{"Slackbot", regexp.MustCompile(`Slackbot(?:-\w+)*(?: ([\d.]+))?`)},
+	{"curl", regexp.MustCompile(`^curl/([\d.]+)`)},
+	{"Wget", regexp.MustCompile(`^Wget/([\d.]+)`)},
+	{"python-requests", regexp.MustCompile(`^python-requests/([\d.]+)`)},
+	{"Go http client", regexp.MustCompile(`^Go-http-client/([\d.]+)`)},
+}
+
+// botWords flag the other automated clients, the first group is their name
+// and the second their version.
+var botWords = regexp.MustCompile(`(?i)([\w-]*(?:bot|crawl|spider|slurp|scrap|headless)[\w-]*)(?:/([\d.]+))?`)
+
+// browsers are matched in order, since most user agents name several, e.g.
+// Edge also claims to be Chrome and Safari.
+var browsers = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/([\d.]+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|Opera)/([\d.]+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/([\d.]+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)},
+	{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+	{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+}
+
+var (
+	windowsRe = regexp.MustCompile(`Windows NT ([\d.]+)`)
+	androidRe = regexp.MustCompile(`Android ([\d.]+)`)
+	iosRe     = regexp.MustCompile(`(?:iPhone|CPU) OS ([\d_]+)`)
+	macRe     = regexp.MustCompile(`Mac OS X ([\d_.]+)`)
+)
+
+// windowsVersions maps the Windows NT versions to their marketing names.
+// Windows 11 still sends 10.0.
+var windowsVersions = map[string]string{
+	"10.0": "10 or 11",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// Parse detects the browser, the operating system and the device of ua.
+func Parse(ua string) (Agent, error) {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return Agent{}, ErrEmpty
+	}
+
+	for _, b := range bots {
+		if m := b.re.FindStringSubmatch(ua); m != nil {
+			return Agent{Browser: b.name, Version: m[1], Device: Bot}, nil
+		}
+	}
+
+	if m := botWords.FindStringSubmatch(ua); m != nil {
+		return Agent{Browser: m[1], Version: m[2], Device: Bot}, nil
+	}
+
+	a := Agent{OS: operatingSystem(ua)}
+	for _, b := range browsers {
+		if m := b.re.FindStringSubmatch(ua); m != nil {
+			a.Browser, a.Version = b.name, m[1]
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "iPad") || strings.Contains(ua, "Tablet") || (strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile")):
+		a.Device = Tablet
+	case strings.Contains(ua, "Mobi") || strings.Contains(ua, "iPhone"):
+		a.Device = Mobile
+	default:
+		a.Device = Desktop
+	}
+	return a, nil
+}
+
+// operatingSystem returns the operating system of ua with its version when
+// known, empty when it is not recognized.
+func operatingSystem(ua string) string {
+	if m := windowsRe.FindStringSubmatch(ua); m != nil {
+		if name, ok := windowsVersions[m[1]]; ok {
+			return "Windows " + name
+		}
+		return "Windows"
+	}
+	if m := androidRe.FindStringSubmatch(ua); m != nil {
+		return "Android " + m[1]
+	}
+	if m := iosRe.FindStringSubmatch(ua); m != nil {
+		name := "iOS"
+		if strings.Contains(ua, "iPad") {
+			name = "iPadOS"
+		}
+		return name + " " + strings.ReplaceAll(m[1], "_", ".")
+	}
+	if m := macRe.FindStringSubmatch(ua); m != nil {
+		return "macOS " + strings.ReplaceAll(m[1], "_", ".")
+	}
+	switch {
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS"
+	case strings.Contains(ua, "Linux"):
+		return "Linux"
+	}
+	return ""
+}
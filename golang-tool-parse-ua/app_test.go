@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want Agent
+	}{
+		{
+			name: "chrome on windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			want: Agent{Browser: "Chrome", Version: "126.0.0.0", OS: "Windows 10 or 11", Device: Desktop},
+		},
+		{
+			name: "edge",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.2592.87",
+			want: Agent{Browser: "Edge", Version: "126.0.2592.87", OS: "Windows 10 or 11", Device: Desktop},
+		},
+		{
+			name: "safari on iphone",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			want: Agent{Browser: "Safari", Version: "17.5", OS: "iOS 17.5", Device: Mobile},
+		},
+		{
+			name: "firefox on linux",
+			ua:   "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
+			want: Agent{Browser: "Firefox", Version: "128.0", OS: "Linux", Device: Desktop},
+		},
+		{
+			name: "samsung tablet",
+			ua:   "Mozilla/5.0 (Linux; Android 14; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/25.0 Chrome/121.0.0.0 Safari/537.36",
+			want: Agent{Browser: "Samsung Internet", Version: "25.0", OS: "Android 14", Device: Tablet},
+		},
+		{
+			name: "chrome on ipad",
+			ua:   "Mozilla/5.0 (iPad; CPU OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/126.0.6478.54 Mobile/15E148 Safari/604.1",
+			want: Agent{Browser: "Chrome", Version: "126.0.6478.54", OS: "iPadOS 17.5", Device: Tablet},
+		},
+		{
+			name: "safari on mac",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Safari/605.1.15",
+			want: Agent{Browser: "Safari", Version: "17.4.1", OS: "macOS 10.15.7", Device: Desktop},
+		},
+		{
+			name: "internet explorer",
+			ua:   "Mozilla/5.0 (Windows NT 6.1; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			want: Agent{Browser: "Internet Explorer", Version: "11.0", OS: "Windows 7", Device: Desktop},
+		},
+		{
+			name: "googlebot",
+			ua:   "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want: Agent{Browser: "Googlebot", Version: "2.1", Device: Bot},
+		},
+		{
+			name: "curl",
+			ua:   "curl/8.7.1",
+			want: Agent{Browser: "curl", Version: "8.7.1", Device: Bot},
+		},
+		{
+			name: "unnamed crawler",
+			ua:   "Mozilla/5.0 (compatible; AcmeCrawler/0.3; +https://acme.example/crawler)",
+			want: Agent{Browser: "AcmeCrawler", Version: "0.3", Device: Bot},
+		},
+		{
+			name: "headless chrome",
+			ua:   "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/126.0.0.0 Safari/537.36",
+			want: Agent{Browser: "HeadlessChrome", Version: "126.0.0.0", Device: Bot},
+		},
+		{
+			name: "unrecognized",
+			ua:   "MyApp 3 build 27",
+			want: Agent{Device: Desktop},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.ua)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	if _, err := Parse("  "); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Parse() error = %v, want ErrEmpty", err)
+	}
+}
+
+func TestAgentString(t *testing.T) {
+	tests := []struct {
+		a    Agent
+		want string
+	}{
+		{Agent{Browser: "Safari", Version: "17.5", OS: "iOS 17.5", Device: Mobile}, "Browser: Safari 17.5, OS: iOS 17.5, device: mobile."},
+		{Agent{Browser: "Googlebot", Version: "2.1", Device: Bot}, "A bot: Googlebot 2.1, an automated client rather than a person."},
+		{Agent{OS: "Linux", Device: Desktop}, "Browser: an unknown browser, OS: Linux, device: desktop."},
+		{Agent{Device: Desktop}, "The user agent is not recognized, it may be a custom client or a fake one."},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.a, got, tt.want)
+		}
+	}
+}
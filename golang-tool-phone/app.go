@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Parse and validate a phone number, e.g. "is +44 20 7946 0958 a valid number?" or "normalize (415) 555-2671 for the US". The function returns whether the number is valid, its E.164 form like +14155552671, its international format, its country and its line type, e.g. mobile or fixed line. A number without a leading + needs the default region it is dialed from.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Number        string `json:"number" jsonschema:"description=The phone number in any common format"`
+	DefaultRegion string `json:"defaultRegion,omitempty" jsonschema:"description=The 2-letter ISO 3166-1 code of the country a national number is dialed from,example=US"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "phone", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD2}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// a phone number is personal data, it is not logged
+	slog.Info("[sfn] << receive", "default_region", msg.DefaultRegion)
+
+	phone, err := ParsePhone(msg.Number, msg.DefaultRegion)
+	if err != nil {
+		slog.Warn("[sfn] ParsePhone error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not parse the phone number: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, phone.String())
+}
+
+// maxNumberLength bounds the input, the longest numbers have 15 digits and
+// formatting or an extension add a few characters.
+const maxNumberLength = 64
+
+// Phone is a parsed phone number.
+type Phone struct {
+	Valid bool
+	// Reason tells why a number is not valid.
+	Reason        string
+	E164          string
+	International string
+	Region        string
+	Type          string
+}
+
+func (p *Phone) String() string {
+	if !p.Valid {
+		return fmt.Sprintf("%s is not a valid phone number: %s", p.E164, p.Reason)
+	}
+	return fmt.Sprintf("%s is a valid %s number of %s, its E.164 form is %s", p.International, p.Type, p.Region, p.E164)
+}
+
+// types names the line types, those missing are reported as unknown.
+var types = map[phonenumbers.PhoneNumberType]string{
+	phonenumbers.FIXED_LINE:           "fixed line",
+	phonenumbers.MOBILE:               "mobile",
+	phonenumbers.FIXED_LINE_OR_MOBILE: "fixed line or mobile",
+	phonenumbers.TOLL_FREE:            "toll free",
+	phonenumbers.PREMIUM_RATE:         "premium rate",
+	phonenumbers.SHARED_COST:          "shared cost",
+	phonenumbers.VOIP:                 "VoIP",
+	phonenumbers.PERSONAL_NUMBER:      "personal",
+	phonenumbers.PAGER:                "pager",
+	phonenumbers.UAN:                  "universal access",
+	phonenumbers.VOICEMAIL:            "voicemail",
+}
+
+// reasons explains why a number is not possible.
+var reasons = map[phonenumbers.ValidationResult]string{
+	phonenumbers.INVALID_COUNTRY_CODE:   "the country calling code is unknown",
+	phonenumbers.TOO_SHORT:              "it is too short",
+	phonenumbers.TOO_LONG:               "it is too long",
+	phonenumbers.IS_POSSIBLE_LOCAL_ONLY: "it can only be dialed locally",
+	phonenumbers.INVALID_LENGTH:         "it has an invalid length for its country",
+}
+
+// ParsePhone parses number, dialed from defaultRegion when it has no leading
+// +, and validates it against the numbering plan of its country. A number
+// that parses but does not exist in the plan is returned as not valid.
+func ParsePhone(number, defaultRegion string) (*Phone, error) {
+	number = strings.TrimSpace(number)
+	if number == "" {
+		return nil, errors.New("the number is empty")
+	}
+	if len(number) > maxNumberLength {
+		return nil, fmt.Errorf("the number is longer than %d characters", maxNumberLength)
+	}
+	defaultRegion = strings.ToUpper(strings.TrimSpace(defaultRegion))
+	if defaultRegion != "" && !phonenumbers.GetSupportedRegions()[defaultRegion] {
+		return nil, fmt.Errorf("%q is not a known region, use a 2-letter ISO 3166-1 code like US", defaultRegion)
+	}
+
+	n, err := phonenumbers.Parse(number, defaultRegion)
+	switch {
+	case errors.Is(err, phonenumbers.ErrInvalidCountryCode) && defaultRegion == "" && !strings.HasPrefix(number, "+"):
+		return nil, errors.New("the number has no country code, give it with a leading + or give the default region")
+	case err != nil:
+		return nil, err
+	}
+
+	p := &Phone{
+		E164:          phonenumbers.Format(n, phonenumbers.E164),
+		International: phonenumbers.Format(n, phonenumbers.INTERNATIONAL),
+		Region:        phonenumbers.GetRegionCodeForNumber(n),
+		Valid:         phonenumbers.IsValidNumber(n),
+	}
+	if !p.Valid {
+		p.Reason = "it is not in the numbering plan of its country"
+		if reason, ok := reasons[phonenumbers.IsPossibleNumberWithReason(n)]; ok {
+			p.Reason = reason
+		}
+		return p, nil
+	}
+	p.Type = "unknown"
+	if t, ok := types[phonenumbers.GetNumberType(n)]; ok {
+		p.Type = t
+	}
+	return p, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		name          string
+		number        string
+		defaultRegion string
+		want          Phone
+	}{
+		{
+			name: "us national", number: "(650) 253-0000", defaultRegion: "us",
+			want: Phone{Valid: true, E164: "+16502530000", International: "+1 650-253-0000", Region: "US", Type: "fixed line or mobile"},
+		},
+		{
+			name: "gb international", number: "+44 20 7031 3000",
+			want: Phone{Valid: true, E164: "+442070313000", International: "+44 20 7031 3000", Region: "GB", Type: "fixed line"},
+		},
+		{
+			name: "gb mobile", number: "07400 123456", defaultRegion: "GB",
+			want: Phone{Valid: true, E164: "+447400123456", International: "+44 7400 123456", Region: "GB", Type: "mobile"},
+		},
+		{
+			name: "swiss with the international prefix", number: "0041 44 668 18 00", defaultRegion: "DE",
+			want: Phone{Valid: true, E164: "+41446681800", International: "+41 44 668 18 00", Region: "CH", Type: "fixed line"},
+		},
+		{
+			name: "too short", number: "+1 650 253", defaultRegion: "",
+			want: Phone{Reason: "it is too short", E164: "+1650253"},
+		},
+		{
+			name: "not in the plan", number: "+1 111 253 0000",
+			want: Phone{Reason: "it is not in the numbering plan of its country", E164: "+11112530000"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePhone(tt.number, tt.defaultRegion)
+			if err != nil {
+				t.Fatalf("ParsePhone() error = %v", err)
+			}
+			if got.Valid != tt.want.Valid || got.Reason != tt.want.Reason || got.E164 != tt.want.E164 || (tt.want.Valid && (*got != tt.want)) {
+				t.Errorf("ParsePhone() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePhoneInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		number        string
+		defaultRegion string
+	}{
+		{"empty", "  ", "US"},
+		{"not a number", "call me maybe", "US"},
+		{"no country code", "650 253 0000", ""},
+		{"unknown region", "650 253 0000", "XX"},
+		{"too long input", "+1 650 253 0000 0000 0000 0000 0000 0000 0000 0000 0000 0000 0000", "US"},
+	}
+	for _, tt := range tests {
+		if _, err := ParsePhone(tt.number, tt.defaultRegion); err == nil {
+			t.Errorf("%s: ParsePhone(%q, %q) should fail", tt.name, tt.number, tt.defaultRegion)
+		}
+	}
+}
+
+func TestPhoneString(t *testing.T) {
+	p, err := ParsePhone("+44 7400 123456", "")
+	if err != nil {
+		t.Fatalf("ParsePhone() error = %v", err)
+	}
+	want := "+44 7400 123456 is a valid mobile number of GB, its E.164 form is +447400123456"
+	if got := p.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
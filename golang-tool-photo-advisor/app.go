@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/solar"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Advise on the light for photography at a location right now, from the cloud cover, the current weather and the position of the sun, e.g. soft light for portraits when it is overcast, and when the next golden hour is. Give the location as a city name with its state and country code when known, or as Latitude and Longitude geo coordinates in decimal format. The function returns the advice with the times in the local time of the location.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	City      string   `json:"city,omitempty" jsonschema:"description=The city with its state and country code when known,example=Lisbon, PT"`
+	Latitude  *float64 `json:"latitude,omitempty" jsonschema:"description=The latitude of the location in decimal format when no city is given,minimum=-90,maximum=90"`
+	Longitude *float64 `json:"longitude,omitempty" jsonschema:"description=The longitude of the location in decimal format when no city is given,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "photo-advisor", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x100}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "city", msg.City)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := PhotoAdvice(reqCtx, msg, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] PhotoAdvice error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not advise on the light: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// PhotoAdvice fetches the current weather of the location of p and advises
+// on the light at now.
+func PhotoAdvice(ctx context.Context, p Parameter, now time.Time) (string, error) {
+	var (
+		c     *weather.Conditions
+		place string
+		err   error
+	)
+	switch {
+	case strings.TrimSpace(p.City) != "":
+		var l weather.Location
+		if c, l, err = client.CurrentByCity(ctx, p.City); err != nil {
+			return "", err
+		}
+		place = l.String()
+	case p.Latitude != nil && p.Longitude != nil:
+		if err := geo.ValidateCoordinate(*p.Latitude, *p.Longitude); err != nil {
+			return "", err
+		}
+		if c, err = client.Current(ctx, *p.Latitude, *p.Longitude); err != nil {
+			return "", err
+		}
+		place = fmt.Sprintf("%v,%v", *p.Latitude, *p.Longitude)
+	default:
+		return "", errors.New("give the location as a city or as coordinates")
+	}
+
+	// the observation time carries the UTC offset of the location
+	if !c.Observed.IsZero() {
+		now = now.In(c.Observed.Location())
+	}
+	in := Inputs{
+		Now:       now,
+		Elevation: solar.Elevation(now, c.Latitude, c.Longitude),
+		Clouds:    c.Clouds,
+		Condition: c.Condition,
+		Golden:    solar.GoldenHours(c.Latitude, c.Longitude, now, now.Add(goldenLookahead)),
+	}
+	return fmt.Sprintf("Light in %s at %s: %s", place, now.Format(clock), Advise(in)), nil
+}
+
+// goldenLookahead is how far ahead the next golden hour is looked for.
+const goldenLookahead = 24 * time.Hour
+
+// clock is the format of the times in the advice.
+const clock = "3:04 PM"
+
+// Inputs are what the light depends on.
+type Inputs struct {
+	Now time.Time
+	// Elevation is the sun elevation at Now in degrees.
+	Elevation float64
+	// Clouds is the cloud cover in percent.
+	Clouds int
+	// Condition is the main OpenWeatherMap condition, e.g. "Rain" or "Fog".
+	Condition string
+	// Golden are the golden hours from Now, the first one open at Now if
+	// it is the golden hour.
+	Golden []solar.Window
+}
+
+// The cloud covers in percent from which the sky is overcast, and up to
+// which it is clear.
+const (
+	overcastSky = 80
+	clearSky    = 25
+)
+
+// highSun is the sun elevation in degrees above which its light is harsh.
+const highSun = 40.0
+
+// imminent is how soon a golden hour is worth waiting for.
+const imminent = time.Hour
+
+// Advise describes the light of in and when the next golden hour is, e.g.
+// "overcast now, soft even light good for portraits; golden hour at 6:42 PM".
+func Advise(in Inputs) string {
+	var golden *solar.Window
+	if len(in.Golden) > 0 {
+		golden = &in.Golden[0]
+	}
+	if golden != nil && !golden.Start.After(in.Now) {
+		return goldenNow(in, golden.End)
+	}
+
+	var advice string
+	switch {
+	case in.Elevation < solar.GoldenLow:
+		advice = "the sun is down, a time for night and long exposure photography with a tripod"
+	case in.Condition == "Thunderstorm":
+		advice = "thunderstorm now, keep the gear dry and stay safe, lightning shots only from shelter"
+	case in.Condition == "Rain" || in.Condition == "Drizzle":
+		advice = "raining now, flat light with reflections on the wet streets, protect the gear"
+	case in.Condition == "Snow":
+		advice = "snowing now, bright diffused light, overexpose by a stop to keep the snow white"
+	case in.Condition == "Fog" || in.Condition == "Mist" || in.Condition == "Haze":
+		advice = "foggy now, moody diffused light with depth in layers, good for landscapes and silhouettes"
+	case in.Clouds >= overcastSky:
+		advice = "overcast now, soft even light without shadows, good for portraits and close-ups"
+	case in.Clouds > clearSky:
+		advice = "partly cloudy now, changing light, wait for the sun between the clouds for contrast or for a cloud for portraits"
+	case in.Elevation > highSun:
+		advice = "clear with the sun high now, harsh light with hard shadows, look for open shade for portraits"
+	default:
+		advice = "clear with a low sun now, directional light with long shadows, good for landscapes and architecture"
+	}
+
+	if golden == nil {
+		return advice + "; no golden hour in the next 24 hours"
+	}
+	wait := golden.Start.Sub(in.Now)
+	if wait <= imminent {
+		advice += fmt.Sprintf("; golden hour in %d minutes at %s, worth waiting for", int(wait.Round(time.Minute).Minutes()), golden.Start.Format(clock))
+	} else {
+		advice += fmt.Sprintf("; next golden hour at %s", golden.Start.Format(clock))
+	}
+	if in.Clouds >= overcastSky {
+		advice += ", though the clouds may hide its colors"
+	}
+	return advice
+}
+
+// goldenNow advises in a golden hour ending at end.
+func goldenNow(in Inputs, end time.Time) string {
+	advice := fmt.Sprintf("golden hour now until %s", end.Format(clock))
+	switch {
+	case in.Condition == "Rain" || in.Condition == "Drizzle" || in.Condition == "Thunderstorm":
+		return advice + ", but the rain hides the warm light, look for a rainbow opposite the sun"
+	case in.Clouds >= overcastSky:
+		return advice + ", but the overcast mutes the warm light, a gap on the horizon can still light up the clouds"
+	case in.Clouds > clearSky:
+		return advice + ", warm low light with the clouds catching color, great for landscapes"
+	}
+	return advice + ", warm low light with long soft shadows, great for portraits and landscapes"
+}
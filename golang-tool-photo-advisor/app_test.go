@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/solar"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+var (
+	lisbon = time.FixedZone("WEST", 3600)
+	now    = time.Date(2024, 8, 7, 14, 0, 0, 0, lisbon)
+	// the evening golden hour of Lisbon that day
+	evening = solar.Window{Start: time.Date(2024, 8, 7, 20, 3, 0, 0, lisbon), End: time.Date(2024, 8, 7, 20, 52, 0, 0, lisbon)}
+)
+
+func TestAdviseClear(t *testing.T) {
+	got := Advise(Inputs{Now: now, Elevation: 62, Clouds: 0, Condition: "Clear", Golden: []solar.Window{evening}})
+	want := "clear with the sun high now, harsh light with hard shadows, look for open shade for portraits; next golden hour at 8:03 PM"
+	if got != want {
+		t.Errorf("Advise() = %s\nwant %s", got, want)
+	}
+
+	got = Advise(Inputs{Now: now.Add(4 * time.Hour), Elevation: 18, Clouds: 10, Condition: "Clear", Golden: []solar.Window{evening}})
+	want = "clear with a low sun now, directional light with long shadows, good for landscapes and architecture; next golden hour at 8:03 PM"
+	if got != want {
+		t.Errorf("Advise() = %s\nwant %s", got, want)
+	}
+}
+
+func TestAdviseOvercast(t *testing.T) {
+	got := Advise(Inputs{Now: now, Elevation: 62, Clouds: 90, Condition: "Clouds", Golden: []solar.Window{evening}})
+	want := "overcast now, soft even light without shadows, good for portraits and close-ups; next golden hour at 8:03 PM, though the clouds may hide its colors"
+	if got != want {
+		t.Errorf("Advise() = %s\nwant %s", got, want)
+	}
+}
+
+func TestAdviseGoldenHourImminent(t *testing.T) {
+	got := Advise(Inputs{Now: evening.Start.Add(-25 * time.Minute), Elevation: 9, Clouds: 40, Condition: "Clouds", Golden: []solar.Window{evening}})
+	want := "partly cloudy now, changing light, wait for the sun between the clouds for contrast or for a cloud for portraits; golden hour in 25 minutes at 8:03 PM, worth waiting for"
+	if got != want {
+		t.Errorf("Advise() = %s\nwant %s", got, want)
+	}
+}
+
+func TestAdviseGoldenHourNow(t *testing.T) {
+	in := evening
+	in.Start = evening.Start.Add(10 * time.Minute)
+	got := Advise(Inputs{Now: in.Start, Elevation: 4, Clouds: 5, Condition: "Clear", Golden: []solar.Window{in}})
+	want := "golden hour now until 8:52 PM, warm low light with long soft shadows, great for portraits and landscapes"
+	if got != want {
+		t.Errorf("Advise() = %s\nwant %s", got, want)
+	}
+
+	got = Advise(Inputs{Now: in.Start, Elevation: 4, Clouds: 100, Condition: "Clouds", Golden: []solar.Window{in}})
+	if !strings.HasPrefix(got, "golden hour now until 8:52 PM, but the overcast mutes the warm light") {
+		t.Errorf("Advise() overcast = %s", got)
+	}
+}
+
+func TestAdviseNightAndWeather(t *testing.T) {
+	tomorrow := solar.Window{Start: time.Date(2024, 8, 8, 6, 38, 0, 0, lisbon), End: time.Date(2024, 8, 8, 7, 30, 0, 0, lisbon)}
+	tests := []struct {
+		name string
+		in   Inputs
+		want string
+	}{
+		{"night", Inputs{Now: time.Date(2024, 8, 7, 23, 0, 0, 0, lisbon), Elevation: -30, Golden: []solar.Window{tomorrow}}, "the sun is down"},
+		{"rain", Inputs{Now: now, Elevation: 62, Clouds: 100, Condition: "Rain", Golden: []solar.Window{evening}}, "raining now"},
+		{"fog", Inputs{Now: now, Elevation: 62, Clouds: 100, Condition: "Fog", Golden: []solar.Window{evening}}, "foggy now"},
+		{"polar night", Inputs{Now: now, Elevation: -10}, "the sun is down, a time for night and long exposure photography with a tripod; no golden hour in the next 24 hours"},
+	}
+	for _, tt := range tests {
+		if got := Advise(tt.in); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: Advise() = %s, want it to start with %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPhotoAdvice(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"coord":{"lon":-9.1393,"lat":38.7223},"weather":[{"id":804,"main":"Clouds","description":"overcast clouds"}],"main":{"temp":24},"clouds":{"all":95},"dt":1723035600,"sys":{"country":"PT"},"timezone":3600,"name":"Lisbon"}`))
+	}))
+	t.Cleanup(srv.Close)
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	lat, lon := 38.7223, -9.1393
+	got, err := PhotoAdvice(context.Background(), Parameter{Latitude: &lat, Longitude: &lon}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPrefix := "Light in 38.7223,-9.1393 at 2:00 PM: overcast now, soft even light without shadows, good for portraits and close-ups; next golden hour at 8:03 PM"
+	if !strings.HasPrefix(got, wantPrefix) || !strings.HasSuffix(got, "though the clouds may hide its colors") {
+		t.Errorf("PhotoAdvice() = %s\nwant %s...", got, wantPrefix)
+	}
+
+	if _, err := PhotoAdvice(context.Background(), Parameter{}, now); err == nil {
+		t.Error("PhotoAdvice() without a location should fail")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Pick options at random from a list, or shuffle the whole list, with true randomness, e.g. "pick a restaurant from these three", "draw 2 winners" or "shuffle the order of the speakers". Always use this function instead of choosing yourself when the user asks for a random choice. An option is picked at most once.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Options []string `json:"options" jsonschema:"description=The options to pick from,minItems=1"`
+	Count   int      `json:"count,omitempty" jsonschema:"description=How many options to pick. Defaults to 1,minimum=1"`
+	Shuffle bool     `json:"shuffle,omitempty" jsonschema:"description=Return all the options in a random order instead of picking some"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "pick", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xCE}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "options", len(msg.Options), "count", msg.Count, "shuffle", msg.Shuffle)
+
+	count := msg.Count
+	switch {
+	case msg.Shuffle:
+		count = len(msg.Options)
+	case count == 0:
+		count = 1
+	}
+
+	picked, err := Pick(msg.Options, count)
+	if err != nil {
+		slog.Warn("[sfn] Pick error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not pick from the options: %v", err))
+		return
+	}
+
+	if msg.Shuffle {
+		sfn.WriteText(ctx, "The shuffled options are: "+strings.Join(picked, ", "))
+		return
+	}
+	sfn.WriteText(ctx, "Picked at random: "+strings.Join(picked, ", "))
+}
+
+// maxOptions bounds the list, a longer one is unlikely to come from a
+// conversation.
+const maxOptions = 1000
+
+// Pick returns count of the options in a random order, without replacement.
+// With count equal to the number of options it is a shuffle. The randomness
+// comes from crypto/rand, so the picks can not be predicted.
+func Pick(options []string, count int) ([]string, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("there are no options")
+	}
+	if len(options) > maxOptions {
+		return nil, fmt.Errorf("at most %d options are supported, got %d", maxOptions, len(options))
+	}
+	if count < 1 || count > len(options) {
+		return nil, fmt.Errorf("can pick 1 to %d options, not %d", len(options), count)
+	}
+
+	// a partial Fisher-Yates shuffle, the first count entries are the pick
+	picked := make([]string, len(options))
+	copy(picked, options)
+	for i := 0; i < count; i++ {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(len(picked)-i)))
+		if err != nil {
+			return nil, err
+		}
+		k := i + int(j.Int64())
+		picked[i], picked[k] = picked[k], picked[i]
+	}
+	return picked[:count], nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+var options = []string{"pizza", "sushi", "tacos", "ramen", "curry"}
+
+func TestPick(t *testing.T) {
+	for count := 1; count <= len(options); count++ {
+		for i := 0; i < 100; i++ {
+			got, err := Pick(options, count)
+			if err != nil {
+				t.Fatalf("Pick(%d) error = %v", count, err)
+			}
+			if len(got) != count {
+				t.Fatalf("Pick(%d) = %v, want %d options", count, got, count)
+			}
+			seen := map[string]bool{}
+			for _, o := range got {
+				if !slices.Contains(options, o) {
+					t.Fatalf("Pick(%d) = %v, %q is not an option", count, got, o)
+				}
+				if seen[o] {
+					t.Fatalf("Pick(%d) = %v, %q is picked twice", count, got, o)
+				}
+				seen[o] = true
+			}
+		}
+	}
+}
+
+func TestPickShuffle(t *testing.T) {
+	leaders := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		got, err := Pick(options, len(options))
+		if err != nil {
+			t.Fatalf("Pick() error = %v", err)
+		}
+		sorted := slices.Clone(got)
+		slices.Sort(sorted)
+		if !slices.Equal(sorted, []string{"curry", "pizza", "ramen", "sushi", "tacos"}) {
+			t.Fatalf("Pick() = %v, want all the options", got)
+		}
+		leaders[got[0]] = true
+	}
+	// every option should lead at least once in 200 shuffles, missing one
+	// has a chance of about 5 * 0.8^200
+	if len(leaders) != len(options) {
+		t.Errorf("only %v led a shuffle", leaders)
+	}
+	if options[0] != "pizza" {
+		t.Errorf("Pick() modified the options: %v", options)
+	}
+}
+
+func TestPickInvalid(t *testing.T) {
+	tests := []struct {
+		options []string
+		count   int
+	}{
+		{nil, 1},
+		{options, 0},
+		{options, -1},
+		{options, 6},
+		{make([]string, maxOptions+1), 1},
+	}
+	for _, tt := range tests {
+		if _, err := Pick(tt.options, tt.count); err == nil {
+			t.Errorf("Pick(%d options, %d) should fail", len(tt.options), tt.count)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/ratelimit"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Report the remaining quota of the rate-limited APIs the functions call, e.g. before calling a weather function many times. The function returns for each API how many calls are left right now and when its quota is full again.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Name string `json:"name,omitempty" jsonschema:"description=The name of a single rate-limited API to report. Defaults to all of them,example=openweathermap"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "quota-status", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xFF}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "name", msg.Name)
+
+	statuses := ratelimit.Statuses()
+	if msg.Name != "" {
+		statuses = filter(statuses, msg.Name)
+		if len(statuses) == 0 {
+			sfn.WriteText(ctx, fmt.Sprintf("can not report %q: its quota is unknown, %s", msg.Name, unknownQuota))
+			return
+		}
+	}
+
+	result := Report(statuses, time.Now())
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+func filter(statuses []ratelimit.Status, name string) []ratelimit.Status {
+	for _, st := range statuses {
+		if strings.EqualFold(st.Name, strings.TrimSpace(name)) {
+			return []ratelimit.Status{st}
+		}
+	}
+	return nil
+}
+
+// unknownQuota tells why the quota of an API without a limiter in this
+// process is unknown. It is not unlimited: every function runs in a process
+// of its own, with its limiters.
+const unknownQuota = "the rate limiters are kept by the processes of the functions calling the APIs and none is in this process, so the calls may be limited or not"
+
+// Report describes the statuses of the limiters at now, one line per
+// limiter.
+func Report(statuses []ratelimit.Status, now time.Time) string {
+	if len(statuses) == 0 {
+		return "The quota of the APIs is unknown: " + unknownQuota + "."
+	}
+
+	var b strings.Builder
+	if len(statuses) == 1 {
+		b.WriteString("1 rate-limited API:")
+	} else {
+		fmt.Fprintf(&b, "%d rate-limited APIs:", len(statuses))
+	}
+	for _, st := range statuses {
+		fmt.Fprintf(&b, "\n- %s: %d of %d calls per %s left", st.Name, st.Remaining, st.Capacity, period(st.Period))
+		if wait := st.Reset.Sub(now).Round(time.Second); wait > 0 {
+			fmt.Fprintf(&b, ", full again in %s at %s", wait, st.Reset.UTC().Format(time.RFC3339))
+		} else {
+			b.WriteString(", full")
+		}
+	}
+	return b.String()
+}
+
+// period formats a quota period, e.g. "minute" or "10m0s".
+func period(d time.Duration) string {
+	switch d {
+	case time.Second:
+		return "second"
+	case time.Minute:
+		return "minute"
+	case time.Hour:
+		return "hour"
+	case 24 * time.Hour:
+		return "day"
+	}
+	return d.String()
+}
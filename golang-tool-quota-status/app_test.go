@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/ratelimit"
+)
+
+func TestReport(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	statuses := []ratelimit.Status{
+		{Name: "openweathermap", Capacity: 60, Period: time.Minute, Remaining: 0, Reset: now.Add(time.Minute)},
+		{Name: "worldtides", Capacity: 100, Period: 24 * time.Hour, Remaining: 100, Reset: now},
+	}
+	got := Report(statuses, now)
+	want := `2 rate-limited APIs:
+- openweathermap: 0 of 60 calls per minute left, full again in 1m0s at 2024-08-07T12:01:00Z
+- worldtides: 100 of 100 calls per day left, full`
+	if got != want {
+		t.Errorf("Report() = %s\nwant %s", got, want)
+	}
+
+	// no limiter in this process is not the same as no limit
+	if got := Report(nil, now); !strings.HasPrefix(got, "The quota of the APIs is unknown:") || strings.Contains(got, "not limited") {
+		t.Errorf("Report(nil) = %s", got)
+	}
+}
+
+func TestReportSharedLimiter(t *testing.T) {
+	l := ratelimit.Shared("test-api", 4, time.Hour)
+	for i := 0; i < 3; i++ {
+		l.Allow()
+	}
+
+	statuses := filter(ratelimit.Statuses(), " TEST-API")
+	if len(statuses) != 1 {
+		t.Fatalf("filter() = %v, want the test-api limiter", statuses)
+	}
+	st := statuses[0]
+	if st.Remaining != 1 || st.Capacity != 4 {
+		t.Errorf("status = %d of %d left, want 1 of 4", st.Remaining, st.Capacity)
+	}
+	// 3 tokens refill in 45 minutes
+	if wait := time.Until(st.Reset); wait < 44*time.Minute || wait > 45*time.Minute {
+		t.Errorf("the limiter is full again in %s, want 45m", wait)
+	}
+	if got := Report(statuses, time.Now()); !strings.HasPrefix(got, "1 rate-limited API:\n- test-api: 1 of 4 calls per hour left, full again in 45m0s") {
+		t.Errorf("Report() = %s", got)
+	}
+
+	if filter(ratelimit.Statuses(), "unknown") != nil {
+		t.Error("filter() found an unknown limiter")
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Estimate how easy an English text is to read. The function returns the Flesch Reading Ease score, from 0 very difficult to 100 very easy, and the Flesch-Kincaid Grade Level, the US school grade needed to understand the text, with the counts of words, sentences and syllables they are computed from.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Text string `json:"text" jsonschema:"description=The English text to score"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "readability-score", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x109}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "text_length", len(msg.Text))
+
+	score, err := Score(msg.Text)
+	if err != nil {
+		slog.Warn("[sfn] Score error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not score the text: %v", err))
+		return
+	}
+
+	result := score.String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// minReliableWords is the length below which the scores are only
+// indicative, the formulas being calibrated on passages of 100 words.
+const minReliableWords = 30
+
+// Readability is the scores of a text.
+type Readability struct {
+	Words, Sentences, Syllables int
+	// ReadingEase is the Flesch Reading Ease, about 0 to 100, higher is
+	// easier.
+	ReadingEase float64
+	// GradeLevel is the Flesch-Kincaid Grade Level, not below 0.
+	GradeLevel float64
+}
+
+var (
+	wordPattern     = regexp.MustCompile(`\p{L}+(?:['’]\p{L}+)*`)
+	sentencePattern = regexp.MustCompile(`[.!?]+(?:["'”’)\]]*)(?:\s|$)`)
+)
+
+// Score counts the words, sentences and syllables of text and computes its
+// scores.
+func Score(text string) (*Readability, error) {
+	words := wordPattern.FindAllString(text, -1)
+	if len(words) == 0 {
+		return nil, errors.New("the text has no words")
+	}
+
+	r := &Readability{Words: len(words), Sentences: Sentences(text)}
+	for _, w := range words {
+		r.Syllables += Syllables(w)
+	}
+
+	wordsPerSentence := float64(r.Words) / float64(r.Sentences)
+	syllablesPerWord := float64(r.Syllables) / float64(r.Words)
+	r.ReadingEase = 206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord
+	r.GradeLevel = max(0, 0.39*wordsPerSentence+11.8*syllablesPerWord-15.59)
+	return r, nil
+}
+
+// Sentences counts the sentences of text, ended by ".", "!" or "?" before a
+// space, at least 1 for a text without end punctuation.
+func Sentences(text string) int {
+	n := 0
+	for _, part := range sentencePattern.Split(text, -1) {
+		if wordPattern.MatchString(part) {
+			n++
+		}
+	}
+	return max(n, 1)
+}
+
+// syllableKeptEndings are the endings in "es" and "ed" that are syllables,
+// e.g. "boxes" and "wanted".
+var syllableKeptEndings = []string{"ces", "ses", "zes", "ges", "xes", "ches", "shes", "ted", "ded"}
+
+// Syllables estimates the syllables of an English word: the groups of
+// vowels, without a silent final "e", "es" or "ed" and with the vowel pairs
+// that are usually two syllables, e.g. "piano", counted twice.
+func Syllables(word string) int {
+	w := strings.ToLower(word)
+	w = strings.NewReplacer("'", "", "’", "").Replace(w)
+
+	switch {
+	case strings.HasSuffix(w, "es") || strings.HasSuffix(w, "ed"):
+		kept := false
+		for _, ending := range syllableKeptEndings {
+			if strings.HasSuffix(w, ending) {
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			w = w[:len(w)-2]
+		}
+	case strings.HasSuffix(w, "e") && !strings.HasSuffix(w, "ee") && !consonantLE(w):
+		w = w[:len(w)-1]
+	}
+	w = strings.TrimPrefix(w, "y")
+
+	n := 0
+	prevVowel := false
+	for i := 0; i < len(w); i++ {
+		v := isVowel(w[i])
+		if v && !prevVowel {
+			n++
+		}
+		if v && prevVowel && splitVowels(w, i) {
+			n++
+		}
+		prevVowel = v
+	}
+	return max(n, 1)
+}
+
+// consonantLE reports whether w ends in a consonant and "le", a syllable,
+// e.g. "table".
+func consonantLE(w string) bool {
+	return len(w) >= 3 && strings.HasSuffix(w, "le") && !isVowel(w[len(w)-3])
+}
+
+// splitVowels reports whether the vowel at i and the one before it are
+// usually two syllables: "ia" and "io" but not in "-tion" or "-cial", "ua"
+// but not after "q", and "iu".
+func splitVowels(w string, i int) bool {
+	pair := w[i-1 : i+1]
+	before := byte(0)
+	if i >= 2 {
+		before = w[i-2]
+	}
+	switch pair {
+	case "ia", "io":
+		return before != 't' && before != 's' && before != 'c' && before != 'g'
+	case "ua":
+		return before != 'q' && before != 'g'
+	case "iu":
+		return true
+	}
+	return false
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiouy", c) >= 0
+}
+
+// ease describes a Flesch Reading Ease score with the school level it
+// matches.
+func ease(score float64) string {
+	switch {
+	case score >= 90:
+		return "very easy, 5th grade"
+	case score >= 80:
+		return "easy, 6th grade"
+	case score >= 70:
+		return "fairly easy, 7th grade"
+	case score >= 60:
+		return "plain English, 8th to 9th grade"
+	case score >= 50:
+		return "fairly difficult, 10th to 12th grade"
+	case score >= 30:
+		return "difficult, college"
+	}
+	return "very difficult, college graduate"
+}
+
+func (r *Readability) String() string {
+	s := fmt.Sprintf("Flesch Reading Ease %.1f (%s), Flesch-Kincaid Grade Level %.1f, from %s, %s and %s",
+		r.ReadingEase, ease(r.ReadingEase), r.GradeLevel,
+		plural(r.Words, "word"), plural(r.Sentences, "sentence"), plural(r.Syllables, "syllable"))
+	if r.Words < minReliableWords {
+		s += fmt.Sprintf(". The text is shorter than %d words, the scores are only indicative", minReliableWords)
+	}
+	return s
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
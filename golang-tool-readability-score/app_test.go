@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSyllables(t *testing.T) {
+	tests := map[string]int{
+		"the": 1, "be": 1, "are": 1, "ago": 2, "cat": 1, "free": 1,
+		"make": 1, "whole": 1, "table": 2, "simple": 2, "syllable": 3,
+		"walked": 1, "loves": 1, "agreed": 2, "wanted": 2, "seeded": 2, "boxes": 2, "glasses": 2,
+		"yellow": 2, "beautiful": 3, "education": 4, "nation": 2, "special": 2,
+		"radio": 3, "piano": 3, "actual": 3, "equal": 2, "Australian": 4, "reptilian": 4,
+		"platypus": 3, "seemingly": 3, "creature": 2, "don't": 1,
+	}
+	for word, want := range tests {
+		if got := Syllables(word); got != want {
+			t.Errorf("Syllables(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestSentences(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"One sentence", 1},
+		{"Hello there. How are you? Fine!", 3},
+		{`He said "stop." Then he left...`, 2},
+		{"The value is 3.14 today.", 1},
+		{"...", 1},
+	}
+	for _, tt := range tests {
+		if got := Sentences(tt.text); got != tt.want {
+			t.Errorf("Sentences(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+// gettysburg has 30 words, 1 sentence and 48 syllables counted by hand,
+// a Flesch Reading Ease of 41.0 and a Flesch-Kincaid Grade Level of 15.0.
+const gettysburg = `Four score and seven years ago our fathers brought forth on this continent, a new nation, conceived in Liberty, and dedicated to the proposition that all men are created equal.`
+
+func TestScore(t *testing.T) {
+	r, err := Score(gettysburg)
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+	if r.Words != 30 || r.Sentences != 1 {
+		t.Errorf("Score() counted %d words and %d sentences, want 30 and 1", r.Words, r.Sentences)
+	}
+	if math.Abs(float64(r.Syllables-48)) > 2 {
+		t.Errorf("Score() counted %d syllables, want 48 ± 2", r.Syllables)
+	}
+	if math.Abs(r.ReadingEase-41.0) > 5 {
+		t.Errorf("Score() reading ease = %.1f, want 41.0 ± 5", r.ReadingEase)
+	}
+	if math.Abs(r.GradeLevel-15.0) > 1 {
+		t.Errorf("Score() grade level = %.1f, want 15.0 ± 1", r.GradeLevel)
+	}
+	if strings.Contains(r.String(), "only indicative") {
+		t.Errorf("Readability.String() = %q, want no short text note", r.String())
+	}
+}
+
+func TestScoreShortText(t *testing.T) {
+	r, err := Score("Go home.")
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+	if r.GradeLevel != 0 {
+		t.Errorf("Score() grade level = %v, want 0", r.GradeLevel)
+	}
+	want := "Flesch Reading Ease 120.2 (very easy, 5th grade), Flesch-Kincaid Grade Level 0.0, from 2 words, 1 sentence and 2 syllables. The text is shorter than 30 words, the scores are only indicative"
+	if got := r.String(); got != want {
+		t.Errorf("Readability.String() = %q, want %q", got, want)
+	}
+
+	if _, err := Score(" 42 ... "); err == nil {
+		t.Error("Score() without words error = nil")
+	}
+}
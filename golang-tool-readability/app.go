@@ -0,0 +1,419 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Fetch a web page and return the readable text of its main article without navigation, ads and markup. Use it to read, quote or summarize an article or a blog post given its URL. Long articles are truncated.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	URL string `json:"url" jsonschema:"description=The http or https URL of the web page"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "readability", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xBC}
+}
+
+var client = netguard.NewClient(10 * time.Second)
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "url", msg.URL)
+
+	article, err := Fetch(client, msg.URL)
+	if err != nil {
+		slog.Warn("[sfn] Fetch error", "url", msg.URL, "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not read %s: %v", msg.URL, err))
+		return
+	}
+
+	sfn.WriteText(ctx, article.String())
+}
+
+const (
+	// maxPageBytes is the part of a page that is read, the rest is ignored.
+	maxPageBytes = 2 << 20
+	// maxTextRunes caps the text returned to the LLM.
+	maxTextRunes = 8000
+	// minParagraphRunes is the length from which a paragraph counts towards
+	// the score of its container.
+	minParagraphRunes = 25
+)
+
+// Article is the readable content of a page.
+type Article struct {
+	Title string
+	Text  string
+	// Truncated is set when the text was cut at maxTextRunes.
+	Truncated bool
+}
+
+func (a *Article) String() string {
+	s := a.Text
+	if a.Title != "" {
+		s = a.Title + "\n\n" + s
+	}
+	if a.Truncated {
+		s += "\n\n[the article is truncated]"
+	}
+	return s
+}
+
+// Fetch downloads the HTML page at rawURL with client and extracts its
+// article.
+func Fetch(client *http.Client, rawURL string) (*Article, error) {
+	u, err := netguard.CheckURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; yomo-llm-readability/1.0)")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the server responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("the page is %q, not HTML", mediaType)
+	}
+
+	return Extract(io.LimitReader(resp.Body, maxPageBytes))
+}
+
+// Extract parses an HTML document and returns the text of its main article.
+func Extract(r io.Reader) (*Article, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &Article{Title: title(doc)}
+	prune(doc)
+
+	root := findRoot(doc)
+	if root == nil {
+		return nil, errors.New("the page has no content")
+	}
+	text := strings.Join(paragraphs(root), "\n\n")
+	if text == "" {
+		return nil, errors.New("the page has no readable text")
+	}
+	article.Text, article.Truncated = truncate(text, maxTextRunes)
+	return article, nil
+}
+
+// boilerplateTags are removed with their content.
+var boilerplateTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Form: true, atom.Button: true, atom.Iframe: true, atom.Svg: true,
+	atom.Canvas: true, atom.Figure: true, atom.Dialog: true,
+}
+
+// boilerplateWords are the words of a class or id that mark an element as
+// boilerplate.
+var boilerplateWords = map[string]bool{
+	"ad": true, "ads": true, "advert": true, "advertisement": true, "sponsor": true, "sponsored": true,
+	"banner": true, "breadcrumb": true, "breadcrumbs": true, "comment": true, "comments": true,
+	"cookie": true, "cookies": true, "footer": true, "menu": true, "nav": true, "navbar": true,
+	"newsletter": true, "popup": true, "promo": true, "related": true, "share": true,
+	"sidebar": true, "social": true, "subscribe": true,
+}
+
+// prune removes the boilerplate and hidden elements under n.
+func prune(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type == html.ElementNode && isBoilerplate(c):
+			n.RemoveChild(c)
+		default:
+			prune(c)
+		}
+		c = next
+	}
+}
+
+func isBoilerplate(n *html.Node) bool {
+	if boilerplateTags[n.DataAtom] {
+		return true
+	}
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "hidden":
+			return true
+		case "aria-hidden":
+			if a.Val == "true" {
+				return true
+			}
+		case "style":
+			if strings.Contains(strings.ReplaceAll(a.Val, " ", ""), "display:none") {
+				return true
+			}
+		case "role":
+			if a.Val == "navigation" || a.Val == "banner" || a.Val == "complementary" || a.Val == "contentinfo" {
+				return true
+			}
+		case "class", "id":
+			words := strings.FieldsFunc(strings.ToLower(a.Val), func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			for _, w := range words {
+				if boilerplateWords[w] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// findRoot returns the element holding the article: the <article> with the
+// most text, else <main>, else the element whose paragraphs score highest,
+// else <body>.
+func findRoot(doc *html.Node) *html.Node {
+	var articles []*html.Node
+	var main, body *html.Node
+	scores := make(map[*html.Node]float64)
+
+	walk(doc, func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Article:
+			articles = append(articles, n)
+		case atom.Main:
+			if main == nil {
+				main = n
+			}
+		case atom.Body:
+			body = n
+		case atom.P, atom.Pre, atom.Blockquote:
+			length := utf8.RuneCountInString(textOf(n))
+			if length < minParagraphRunes || linkDensity(n) > 0.5 {
+				return
+			}
+			// the classic readability score: a point per paragraph, one
+			// per comma and one per 100 characters
+			score := 1 + float64(strings.Count(textOf(n), ",")) + float64(min(length/100, 3))
+			if p := n.Parent; p != nil {
+				scores[p] += score
+				if gp := p.Parent; gp != nil {
+					scores[gp] += score / 2
+				}
+			}
+		}
+	})
+
+	if len(articles) > 0 {
+		best := articles[0]
+		for _, a := range articles[1:] {
+			if len(textOf(a)) > len(textOf(best)) {
+				best = a
+			}
+		}
+		return best
+	}
+	if main != nil {
+		return main
+	}
+
+	var best *html.Node
+	for n, score := range scores {
+		if best == nil || score > scores[best] || (score == scores[best] && depth(n) > depth(best)) {
+			best = n
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return body
+}
+
+// blockTags start a new paragraph of the text.
+var blockTags = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Blockquote: true, atom.Pre: true,
+	atom.Table: true, atom.Tr: true, atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Br: true,
+}
+
+// paragraphs returns the text of n split in paragraphs at block elements,
+// with the whitespace collapsed. A list item starts with "- ", and link
+// lists, e.g. "read more" blocks, are dropped.
+func paragraphs(n *html.Node) []string {
+	var out []string
+	var cur strings.Builder
+	flush := func() {
+		if s := strings.Join(strings.Fields(cur.String()), " "); s != "" && s != "-" {
+			out = append(out, s)
+		}
+		cur.Reset()
+	}
+
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			cur.WriteString(n.Data)
+			return
+		}
+		if n.Type != html.ElementNode && n.Type != html.DocumentNode {
+			return
+		}
+		block := blockTags[n.DataAtom]
+		if block {
+			flush()
+			if (n.DataAtom == atom.Li || n.DataAtom == atom.P) && linkDensity(n) > 0.5 {
+				return
+			}
+			if n.DataAtom == atom.Li {
+				cur.WriteString("- ")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+		if block {
+			flush()
+		} else if n.DataAtom == atom.Td || n.DataAtom == atom.Th {
+			cur.WriteString(" ")
+		}
+	}
+	visit(n)
+	flush()
+	return out
+}
+
+func title(doc *html.Node) string {
+	var t, h1 string
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom == atom.Title && t == "" {
+			t = strings.Join(strings.Fields(textOf(n)), " ")
+		}
+		if n.DataAtom == atom.H1 && h1 == "" {
+			h1 = strings.Join(strings.Fields(textOf(n)), " ")
+		}
+	})
+	if t != "" {
+		return t
+	}
+	return h1
+}
+
+// walk calls fn for every element under n.
+func walk(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(n)
+	return b.String()
+}
+
+// linkDensity is the share of the text of n that is inside links.
+func linkDensity(n *html.Node) float64 {
+	total := len(strings.TrimSpace(textOf(n)))
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	walk(n, func(c *html.Node) {
+		if c.DataAtom == atom.A {
+			links += len(strings.TrimSpace(textOf(c)))
+		}
+	})
+	return float64(links) / float64(total)
+}
+
+func depth(n *html.Node) int {
+	d := 0
+	for ; n != nil; n = n.Parent {
+		d++
+	}
+	return d
+}
+
+// truncate cuts s to at most limit runes, at a word boundary when there is
+// one in the last tenth.
+func truncate(s string, limit int) (string, bool) {
+	if utf8.RuneCountInString(s) <= limit {
+		return s, false
+	}
+	runes := []rune(s)[:limit]
+	cut := string(runes)
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > len(cut)*9/10 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut), true
+}
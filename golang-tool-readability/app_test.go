@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+)
+
+func extractFile(t *testing.T, name string) *Article {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	article, err := Extract(f)
+	if err != nil {
+		t.Fatalf("Extract(%s) error = %v", name, err)
+	}
+	return article
+}
+
+func TestExtractArticle(t *testing.T) {
+	article := extractFile(t, "testdata/article.html")
+
+	if article.Title != "Go 1.22 is released - The Go Blog" {
+		t.Errorf("Title = %q", article.Title)
+	}
+	want := strings.Join([]string{
+		"Go 1.22 is released!",
+		"Eli Bendersky, on behalf of the Go team",
+		"Today the Go team is thrilled to release Go 1.22, which you can get by visiting the download page.",
+		"Go 1.22 comes with several important new features and improvements. Here are some of the notable changes; for the full list, refer to the release notes.",
+		"Language changes",
+		"The long-standing “for” loop gotcha with accidental sharing of loop variables between iterations is now resolved.",
+		"- Each iteration of a loop has its own variables.",
+		"- Range over integers is supported.",
+	}, "\n\n")
+	if article.Text != want {
+		t.Errorf("Text =\n%s\nwant\n%s", article.Text, want)
+	}
+}
+
+func TestExtractWithoutArticleElement(t *testing.T) {
+	article := extractFile(t, "testdata/blog.html")
+
+	if article.Title != "Baking sourdough at home" {
+		t.Errorf("Title = %q", article.Title)
+	}
+	for _, want := range []string{"Sourdough bread is leavened", "Feed the starter", "bake it in a very hot dutch oven."} {
+		if !strings.Contains(article.Text, want) {
+			t.Errorf("Text does not contain %q:\n%s", want, article.Text)
+		}
+	}
+	for _, boilerplate := range []string{"Home", "Recipes", "Great recipe", "Ten breads", "Subscribe", "See also"} {
+		if strings.Contains(article.Text, boilerplate) {
+			t.Errorf("Text contains the boilerplate %q:\n%s", boilerplate, article.Text)
+		}
+	}
+}
+
+func TestExtractTruncates(t *testing.T) {
+	page := "<html><body><article><p>" + strings.Repeat("lorem ipsum ", maxTextRunes) + "</p></article></body></html>"
+	article, err := Extract(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !article.Truncated || len([]rune(article.Text)) > maxTextRunes {
+		t.Errorf("Extract() truncated = %v with %d runes, want at most %d", article.Truncated, len([]rune(article.Text)), maxTextRunes)
+	}
+	if strings.HasSuffix(article.Text, "lor") || !strings.HasSuffix(article.String(), "[the article is truncated]") {
+		t.Errorf("Extract() cut a word or lost the truncation note: ...%s", article.String()[len(article.String())-40:])
+	}
+}
+
+func TestFetch(t *testing.T) {
+	page, err := os.ReadFile("testdata/article.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(page)
+		case "/data.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	article, err := Fetch(srv.Client(), srv.URL+"/article")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !strings.HasPrefix(article.Text, "Go 1.22 is released!") {
+		t.Errorf("Fetch() text = %s", article.Text)
+	}
+
+	if _, err := Fetch(srv.Client(), srv.URL+"/data.json"); err == nil {
+		t.Error("Fetch() should reject a page that is not HTML")
+	}
+	if _, err := Fetch(srv.Client(), srv.URL+"/missing"); err == nil {
+		t.Error("Fetch() should fail on a 404")
+	}
+	if _, err := Fetch(srv.Client(), "file:///etc/passwd"); err == nil {
+		t.Error("Fetch() should reject a file URL")
+	}
+
+	// the client of the Handler refuses to connect to the loopback server
+	if _, err := Fetch(client, srv.URL+"/article"); !errors.Is(err, netguard.ErrBlockedAddress) {
+		t.Errorf("Fetch() error = %v, want %v", err, netguard.ErrBlockedAddress)
+	}
+}
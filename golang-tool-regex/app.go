@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Test a regular expression against a text. The pattern uses the Go RE2 syntax, lookarounds and backreferences are not supported. The function returns every match with its start and end character positions and its capture groups, or the compile error of an invalid pattern.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Pattern string `json:"pattern" jsonschema:"description=The regular expression in Go RE2 syntax"`
+	Text    string `json:"text" jsonschema:"description=The text to match the pattern against"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "regex", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB9}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "pattern", msg.Pattern, "text_bytes", len(msg.Text))
+
+	matches, err := FindMatches(msg.Pattern, msg.Text, matchBudget)
+	if err != nil {
+		slog.Warn("[sfn] FindMatches error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not test the pattern: %v", err))
+		return
+	}
+	if len(matches) == 0 {
+		sfn.WriteText(ctx, "the pattern does not match the text")
+		return
+	}
+
+	buf, _ := json.Marshal(matches)
+	sfn.WriteText(ctx, fmt.Sprintf("the pattern matches %d times: %s", len(matches), buf))
+}
+
+const (
+	maxTextBytes = 100 * 1024
+	maxMatches   = 100
+	// matchBudget is the time matching may take. RE2 runs in linear time,
+	// the budget bounds large texts with expensive patterns.
+	matchBudget = time.Second
+)
+
+// Group is a capture group of a match. A group that did not participate in
+// the match has a start and end of -1.
+type Group struct {
+	Index int    `json:"index"`
+	Name  string `json:"name,omitempty"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Match is a match of the whole pattern, positions are character offsets
+// into the text, the end is exclusive.
+type Match struct {
+	Text   string  `json:"text"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+	Groups []Group `json:"groups,omitempty"`
+}
+
+var errTimeout = errors.New("matching took too long, try a simpler pattern or a shorter text")
+
+// FindMatches compiles pattern and returns its first maxMatches matches in
+// text. It gives up after budget.
+func FindMatches(pattern, text string, budget time.Duration) ([]Match, error) {
+	if pattern == "" {
+		return nil, errors.New("the pattern is empty")
+	}
+	if len(text) > maxTextBytes {
+		return nil, fmt.Errorf("the text is longer than %d bytes", maxTextBytes)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	// the regexp package can not be interrupted, on timeout the goroutine
+	// finishes in the background and its result is dropped
+	done := make(chan [][]int, 1)
+	go func() { done <- re.FindAllStringSubmatchIndex(text, maxMatches) }()
+
+	timer := time.NewTimer(budget)
+	defer timer.Stop()
+	select {
+	case indexes := <-done:
+		return matches(re, text, indexes), nil
+	case <-timer.C:
+		return nil, errTimeout
+	}
+}
+
+func matches(re *regexp.Regexp, text string, indexes [][]int) []Match {
+	names := re.SubexpNames()
+	// pos converts a byte offset to a character offset
+	pos := func(i int) int {
+		if i < 0 {
+			return -1
+		}
+		return utf8.RuneCountInString(text[:i])
+	}
+
+	out := make([]Match, 0, len(indexes))
+	for _, loc := range indexes {
+		m := Match{Text: text[loc[0]:loc[1]], Start: pos(loc[0]), End: pos(loc[1])}
+		for g := 1; g < len(loc)/2; g++ {
+			start, end := loc[2*g], loc[2*g+1]
+			group := Group{Index: g, Name: names[g], Start: pos(start), End: pos(end)}
+			if start >= 0 {
+				group.Text = text[start:end]
+			}
+			m.Groups = append(m.Groups, group)
+		}
+		out = append(out, m)
+	}
+	return out
+}
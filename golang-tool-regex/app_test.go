@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    []Match
+	}{
+		{
+			name:    "matches without groups",
+			pattern: `\d+`,
+			text:    "a1 b22 c333",
+			want: []Match{
+				{Text: "1", Start: 1, End: 2},
+				{Text: "22", Start: 4, End: 6},
+				{Text: "333", Start: 8, End: 11},
+			},
+		},
+		{
+			name:    "numbered and named groups",
+			pattern: `(\w+)@(?P<domain>\w+)\.com`,
+			text:    "ada@example.com, alan@test.org",
+			want: []Match{{
+				Text: "ada@example.com", Start: 0, End: 15,
+				Groups: []Group{
+					{Index: 1, Text: "ada", Start: 0, End: 3},
+					{Index: 2, Name: "domain", Text: "example", Start: 4, End: 11},
+				},
+			}},
+		},
+		{
+			name:    "optional group not matched",
+			pattern: `colou?(r)(s)?`,
+			text:    "color",
+			want: []Match{{
+				Text: "color", Start: 0, End: 5,
+				Groups: []Group{
+					{Index: 1, Text: "r", Start: 4, End: 5},
+					{Index: 2, Start: -1, End: -1},
+				},
+			}},
+		},
+		{
+			name:    "character positions of unicode text",
+			pattern: `é+`,
+			text:    "café éé",
+			want: []Match{
+				{Text: "é", Start: 3, End: 4},
+				{Text: "éé", Start: 5, End: 7},
+			},
+		},
+		{
+			name:    "no match",
+			pattern: `^\d+$`,
+			text:    "abc",
+			want:    []Match{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindMatches(tt.pattern, tt.text, time.Second)
+			if err != nil {
+				t.Fatalf("FindMatches() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindMatches() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMatchesInvalid(t *testing.T) {
+	_, err := FindMatches(`(\w+`, "text", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "invalid pattern: error parsing regexp: missing closing )") {
+		t.Errorf("FindMatches() error = %v, want the compile error", err)
+	}
+
+	if _, err := FindMatches(`(?<=a)b`, "ab", time.Second); err == nil {
+		t.Error("FindMatches() should reject a lookbehind")
+	}
+	if _, err := FindMatches(`a`, strings.Repeat("a", maxTextBytes+1), time.Second); err == nil {
+		t.Error("FindMatches() should reject a text over the size limit")
+	}
+}
+
+func TestFindMatchesCapsMatches(t *testing.T) {
+	got, err := FindMatches(`a`, strings.Repeat("a", 500), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != maxMatches {
+		t.Errorf("FindMatches() returned %d matches, want %d", len(got), maxMatches)
+	}
+}
+
+func TestFindMatchesTimeout(t *testing.T) {
+	text := strings.Repeat("ab", maxTextBytes/2)
+	_, err := FindMatches(`(a|b|ab|ba)*c`, text, time.Microsecond)
+	if !errors.Is(err, errTimeout) {
+		t.Errorf("FindMatches() error = %v, want %v", err, errTimeout)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Download a remote file and compute its checksum, e.g. to verify a download against a published SHA-256 digest. The supported algorithms are md5, sha1, sha256 and sha512, sha256 is used when the user does not name one. The function returns the hex digest and the size of the file. Files larger than 100 MB are rejected.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	URL       string `json:"url" jsonschema:"description=The http or https URL of the file"`
+	Algorithm string `json:"algorithm,omitempty" jsonschema:"description=The hash algorithm,enum=md5,enum=sha1,enum=sha256,enum=sha512,default=sha256"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "remote-checksum", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xBF}
+}
+
+const (
+	// maxFileBytes is the largest file that is hashed.
+	maxFileBytes = 100 << 20
+	// downloadTimeout bounds the whole download, not only the connection.
+	downloadTimeout = 60 * time.Second
+)
+
+var client = netguard.NewClient(downloadTimeout)
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "url", msg.URL, "algorithm", msg.Algorithm)
+
+	digest, err := Checksum(client, msg.URL, msg.Algorithm, maxFileBytes)
+	if err != nil {
+		slog.Warn("[sfn] Checksum error", "url", msg.URL, "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compute the checksum of %s: %v", msg.URL, err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("The %s checksum of %s (%d bytes) is %s", digest.Algorithm, msg.URL, digest.Size, digest.Hex))
+}
+
+// algorithms are the supported hash functions by name.
+var algorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Digest is the checksum of a downloaded file.
+type Digest struct {
+	Algorithm string
+	Hex       string
+	Size      int64
+}
+
+// Checksum downloads rawURL with client and hashes the body with algorithm
+// while it streams in. A body larger than limit bytes is an error.
+func Checksum(client *http.Client, rawURL, algorithm string, limit int64) (*Digest, error) {
+	algorithm = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(algorithm), "-", ""))
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	newHash, ok := algorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q, use md5, sha1, sha256 or sha512", algorithm)
+	}
+
+	u, err := netguard.CheckURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the server responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("the file is %d bytes, larger than the limit of %d bytes", resp.ContentLength, limit)
+	}
+
+	// read one byte past the limit to tell a file of exactly limit bytes
+	// from a larger one without a Content-Length
+	h := newHash()
+	n, err := io.Copy(h, io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > limit {
+		return nil, fmt.Errorf("the file is larger than the limit of %d bytes", limit)
+	}
+
+	return &Digest{Algorithm: algorithm, Hex: hex.EncodeToString(h.Sum(nil)), Size: n}, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+)
+
+const fox = "The quick brown fox jumps over the lazy dog"
+
+func TestChecksum(t *testing.T) {
+	// 1 MiB, written in small chunks so the body is streamed
+	large := bytes.Repeat([]byte("0123456789abcdef"), 65536)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fox.txt":
+			w.Write([]byte(fox))
+		case "/large.bin":
+			for i := 0; i < len(large); i += 4096 {
+				w.Write(large[i : i+4096])
+				w.(http.Flusher).Flush()
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		path      string
+		algorithm string
+		want      string
+		size      int64
+	}{
+		{"/fox.txt", "md5", "9e107d9d372bb6826bd81d3542a419d6", 43},
+		{"/fox.txt", "sha1", "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12", 43},
+		{"/fox.txt", "", "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592", 43},
+		{"/fox.txt", "SHA-256", "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592", 43},
+		{"/fox.txt", "sha512", "07e547d9586f6a73f73fbac0435ed76951218fb7d0c8d788a309d785436bbb642e93a252a954f23912547d1e8a3b5ed6e1bfd7097821233fa0538f3db854fee6", 43},
+		{"/large.bin", "sha256", "aca1cd027e979588d14b877b7b0cb8585ad9fec599eb45801992ee5382b3760f", 1 << 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.algorithm, func(t *testing.T) {
+			digest, err := Checksum(srv.Client(), srv.URL+tt.path, tt.algorithm, maxFileBytes)
+			if err != nil {
+				t.Fatalf("Checksum() error = %v", err)
+			}
+			if digest.Hex != tt.want || digest.Size != tt.size {
+				t.Errorf("Checksum() = %s (%d bytes), want %s (%d bytes)", digest.Hex, digest.Size, tt.want, tt.size)
+			}
+		})
+	}
+}
+
+func TestChecksumLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// no Content-Length, the limit is hit while streaming
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(fox))
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/sized", "/chunked"} {
+		_, err := Checksum(srv.Client(), srv.URL+path, "sha256", 10)
+		if err == nil || !strings.Contains(err.Error(), "limit of 10 bytes") {
+			t.Errorf("Checksum(%s) error = %v, want the size limit", path, err)
+		}
+	}
+	if _, err := Checksum(srv.Client(), srv.URL+"/sized", "sha256", int64(len(fox))); err != nil {
+		t.Errorf("Checksum() of a file of exactly the limit error = %v", err)
+	}
+}
+
+func TestChecksumErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := Checksum(srv.Client(), srv.URL+"/missing", "sha256", maxFileBytes); err == nil {
+		t.Error("Checksum() should fail on a 404")
+	}
+	if _, err := Checksum(srv.Client(), srv.URL, "crc32", maxFileBytes); err == nil {
+		t.Error("Checksum() should reject an unsupported algorithm")
+	}
+	if _, err := Checksum(srv.Client(), "ftp://example.com/file.iso", "sha256", maxFileBytes); err == nil {
+		t.Error("Checksum() should reject an ftp URL")
+	}
+
+	// the client of the Handler refuses to connect to the loopback server
+	if _, err := Checksum(client, srv.URL, "sha256", maxFileBytes); !errors.Is(err, netguard.ErrBlockedAddress) {
+		t.Errorf("Checksum() error = %v, want %v", err, netguard.ErrBlockedAddress)
+	}
+}
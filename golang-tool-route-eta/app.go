@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the travel time and the distance of the route between two places by car, on foot or by bike, e.g. "how long does it take to drive from Lyon to Geneva?". The places are city names, addresses or Latitude and Longitude geo coordinates in decimal format like "48.8566,2.3522". The function returns the distance and the estimated duration of the route, or that no route was found.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Origin      string `json:"origin" jsonschema:"description=The place to start from: a city name or an address or the latitude and longitude separated by a comma"`
+	Destination string `json:"destination" jsonschema:"description=The place to go to: a city name or an address or the latitude and longitude separated by a comma"`
+	Mode        string `json:"mode,omitempty" jsonschema:"description=How to travel. Defaults to driving,enum=driving,enum=walking,enum=cycling"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "route-eta", Description: Description(), InputSchema: InputSchema(), Env: []string{"ORS_API_KEY"}, Upstream: router.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE6}
+}
+
+var router = &OpenRouteService{
+	Key:        os.Getenv("ORS_API_KEY"),
+	BaseURL:    "https://api.openrouteservice.org",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// the places can be home addresses, they are not logged
+	slog.Info("[sfn] << receive", "mode", msg.Mode)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	route, err := RouteETA(reqCtx, router, msg)
+	if errors.Is(err, ErrNoRoute) {
+		sfn.WriteText(ctx, fmt.Sprintf("no %s route was found between %s and %s", modeOrDefault(msg.Mode), msg.Origin, msg.Destination))
+		return
+	}
+	if err != nil {
+		slog.Warn("[sfn] RouteETA error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the route: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, route.String())
+}
+
+// ErrNoRoute is returned when the places are not connected by a road or a
+// path of the mode, e.g. across an ocean.
+var ErrNoRoute = errors.New("no route found")
+
+// profiles maps the modes to the OpenRouteService profiles.
+var profiles = map[string]string{
+	"driving": "driving-car",
+	"walking": "foot-walking",
+	"cycling": "cycling-regular",
+}
+
+func modeOrDefault(mode string) string {
+	if mode == "" {
+		return "driving"
+	}
+	return strings.ToLower(mode)
+}
+
+// Place is a resolved origin or destination.
+type Place struct {
+	Label               string
+	Latitude, Longitude float64
+}
+
+// Route is the distance and the duration between two places.
+type Route struct {
+	From, To Place
+	Mode     string
+	// Distance is in meters.
+	Distance float64
+	Duration time.Duration
+}
+
+// String returns the route, e.g. "Driving from Lyon, France to Geneva,
+// Switzerland: 151 km, about 1 h 45 min."
+func (r *Route) String() string {
+	verb := map[string]string{"driving": "Driving", "walking": "Walking", "cycling": "Cycling"}[r.Mode]
+	return fmt.Sprintf("%s from %s to %s: %s, about %s.", verb, r.From.Label, r.To.Label, distance(r.Distance), duration(r.Duration))
+}
+
+// distance formats meters, e.g. "850 m", "4.2 km" or "151 km".
+func distance(m float64) string {
+	switch {
+	case m < 1000:
+		return fmt.Sprintf("%.0f m", m)
+	case m < 10000:
+		return fmt.Sprintf("%.1f km", m/1000)
+	}
+	return fmt.Sprintf("%.0f km", m/1000)
+}
+
+// duration formats d to the minute, e.g. "7 min" or "1 h 45 min".
+func duration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 1 {
+		return "1 min"
+	}
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%d h", minutes/60)
+	}
+	return fmt.Sprintf("%d h %d min", minutes/60, minutes%60)
+}
+
+// RouteETA resolves the places of p with o and returns the route between
+// them.
+func RouteETA(ctx context.Context, o *OpenRouteService, p Parameter) (*Route, error) {
+	mode := modeOrDefault(p.Mode)
+	profile, ok := profiles[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown mode %q, use driving, walking or cycling", p.Mode)
+	}
+	if strings.TrimSpace(p.Origin) == "" || strings.TrimSpace(p.Destination) == "" {
+		return nil, errors.New("both the origin and the destination are needed")
+	}
+
+	from, err := resolve(ctx, o, p.Origin)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolve(ctx, o, p.Destination)
+	if err != nil {
+		return nil, err
+	}
+	route, err := o.Directions(ctx, profile, from, to)
+	if err != nil {
+		return nil, err
+	}
+	route.Mode = mode
+	return route, nil
+}
+
+// resolve parses place as "lat,lon" coordinates, or geocodes it.
+func resolve(ctx context.Context, o *OpenRouteService, place string) (Place, error) {
+	if lat, lon, ok := parseCoordinates(place); ok {
+		if err := geo.ValidateCoordinate(lat, lon); err != nil {
+			return Place{}, err
+		}
+		return Place{Label: fmt.Sprintf("%v,%v", lat, lon), Latitude: lat, Longitude: lon}, nil
+	}
+	return o.Geocode(ctx, place)
+}
+
+// parseCoordinates parses "lat,lon", with optional spaces.
+func parseCoordinates(s string) (lat, lon float64, ok bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lon, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	return lat, lon, err1 == nil && err2 == nil
+}
+
+// OpenRouteService is a client of the OpenRouteService geocoding and
+// directions APIs, see https://openrouteservice.org/dev/#/api-docs.
+type OpenRouteService struct {
+	Key        string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Geocode returns the best match of text.
+func (o *OpenRouteService) Geocode(ctx context.Context, text string) (Place, error) {
+	q := url.Values{}
+	q.Set("text", text)
+	q.Set("size", "1")
+
+	var r struct {
+		Features []struct {
+			Geometry struct {
+				// longitude first
+				Coordinates [2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties struct {
+				Label string `json:"label"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := o.get(ctx, "/geocode/search", q, &r); err != nil {
+		return Place{}, err
+	}
+	if len(r.Features) == 0 {
+		return Place{}, fmt.Errorf("no place matches %q", text)
+	}
+	f := r.Features[0]
+	return Place{Label: f.Properties.Label, Latitude: f.Geometry.Coordinates[1], Longitude: f.Geometry.Coordinates[0]}, nil
+}
+
+// Directions returns the route of profile from one place to the other. It
+// is ErrNoRoute when the places can not be connected.
+func (o *OpenRouteService) Directions(ctx context.Context, profile string, from, to Place) (*Route, error) {
+	q := url.Values{}
+	q.Set("start", fmt.Sprintf("%v,%v", from.Longitude, from.Latitude))
+	q.Set("end", fmt.Sprintf("%v,%v", to.Longitude, to.Latitude))
+
+	var r struct {
+		Features []struct {
+			Properties struct {
+				Summary struct {
+					Distance float64 `json:"distance"`
+					Duration float64 `json:"duration"`
+				} `json:"summary"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := o.get(ctx, "/v2/directions/"+profile, q, &r); err != nil {
+		return nil, err
+	}
+	if len(r.Features) == 0 {
+		return nil, ErrNoRoute
+	}
+	s := r.Features[0].Properties.Summary
+	return &Route{
+		From:     from,
+		To:       to,
+		Distance: s.Distance,
+		Duration: time.Duration(s.Duration * float64(time.Second)),
+	}, nil
+}
+
+// The error codes of the directions API, see
+// https://giscience.github.io/openrouteservice/api-reference/error-codes.
+const (
+	// the route is longer than the limit of the profile
+	codeDistanceExceeded = 2004
+	// no road or path connects the places
+	codeRouteNotFound = 2009
+	// no road or path is near a place
+	codePointNotFound = 2010
+)
+
+func (o *OpenRouteService) get(ctx context.Context, path string, q url.Values, v any) error {
+	if o.Key == "" {
+		return errors.New("ORS_API_KEY is not set")
+	}
+	q.Set("api_key", o.Key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseURL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		// the error text has the URL, and the key in it
+		return httpx.Redact(err, "api_key")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &e)
+		switch e.Error.Code {
+		case codeRouteNotFound, codePointNotFound:
+			return ErrNoRoute
+		case codeDistanceExceeded:
+			return errors.New("the places are too far apart for this mode")
+		}
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return errors.New("the OpenRouteService API key is invalid")
+		}
+		return fmt.Errorf("OpenRouteService responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode the OpenRouteService response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// orsServer geocodes Lyon and Geneva and answers the directions with
+// directions, or with status and an error when status is not 200.
+func orsServer(t *testing.T, status int, directions string) *OpenRouteService {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("api_key") != "test-key" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.URL.Path == "/geocode/search":
+			places := map[string]string{
+				"lyon":   `{"geometry":{"coordinates":[4.8357,45.764]},"properties":{"label":"Lyon, France"}}`,
+				"geneva": `{"geometry":{"coordinates":[6.1432,46.2044]},"properties":{"label":"Geneva, Switzerland"}}`,
+			}
+			fmt.Fprintf(w, `{"features":[%s]}`, places[strings.ToLower(q.Get("text"))])
+		case strings.HasPrefix(r.URL.Path, "/v2/directions/"):
+			w.WriteHeader(status)
+			fmt.Fprint(w, directions)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &OpenRouteService{Key: "test-key", BaseURL: srv.URL, HTTPClient: srv.Client()}
+}
+
+const lyonGeneva = `{"features":[{"properties":{"summary":{"distance":151234.5,"duration":6312.8}}}]}`
+
+func TestRouteETA(t *testing.T) {
+	o := orsServer(t, http.StatusOK, lyonGeneva)
+
+	route, err := RouteETA(context.Background(), o, Parameter{Origin: "Lyon", Destination: "Geneva"})
+	if err != nil {
+		t.Fatalf("RouteETA() error = %v", err)
+	}
+	want := "Driving from Lyon, France to Geneva, Switzerland: 151 km, about 1 h 45 min."
+	if got := route.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+
+	route, err = RouteETA(context.Background(), o, Parameter{Origin: "45.764, 4.8357", Destination: "Geneva", Mode: "Cycling"})
+	if err != nil {
+		t.Fatalf("RouteETA() error = %v", err)
+	}
+	if route.From.Label != "45.764,4.8357" || route.From.Longitude != 4.8357 || route.Mode != "cycling" {
+		t.Errorf("RouteETA() = %+v", route)
+	}
+}
+
+func TestDirectionsQuery(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Path + " " + r.URL.Query().Get("start") + " " + r.URL.Query().Get("end")
+		fmt.Fprint(w, lyonGeneva)
+	}))
+	defer srv.Close()
+	o := &OpenRouteService{Key: "test-key", BaseURL: srv.URL, HTTPClient: srv.Client()}
+
+	if _, err := RouteETA(context.Background(), o, Parameter{Origin: "45.764,4.8357", Destination: "46.2044,6.1432", Mode: "walking"}); err != nil {
+		t.Fatalf("RouteETA() error = %v", err)
+	}
+	// the coordinates are longitude first
+	if want := "/v2/directions/foot-walking 4.8357,45.764 6.1432,46.2044"; got != want {
+		t.Errorf("request = %s, want %s", got, want)
+	}
+}
+
+func TestRouteETANoRoute(t *testing.T) {
+	for _, body := range []string{
+		`{"error":{"code":2009,"message":"Route could not be found - Unable to find a route between points 1 and 2."}}`,
+		`{"error":{"code":2010,"message":"Could not find routable point within a radius of 350.0 meters of specified coordinate 0."}}`,
+	} {
+		o := orsServer(t, http.StatusNotFound, body)
+		_, err := RouteETA(context.Background(), o, Parameter{Origin: "Lyon", Destination: "40.7,-74"})
+		if !errors.Is(err, ErrNoRoute) {
+			t.Errorf("RouteETA() error = %v, want %v", err, ErrNoRoute)
+		}
+	}
+}
+
+func TestRouteETAErrors(t *testing.T) {
+	o := orsServer(t, http.StatusOK, lyonGeneva)
+	for _, p := range []Parameter{
+		{Origin: "Lyon", Destination: "Geneva", Mode: "flying"},
+		{Origin: "Lyon"},
+		{Origin: "Atlantis", Destination: "Geneva"},
+		{Origin: "95,4.8357", Destination: "Geneva"},
+	} {
+		if _, err := RouteETA(context.Background(), o, p); err == nil || errors.Is(err, ErrNoRoute) {
+			t.Errorf("RouteETA(%+v) error = %v, want an invalid input", p, err)
+		}
+	}
+
+	o = orsServer(t, http.StatusBadRequest, `{"error":{"code":2004,"message":"Request parameters exceed the server configuration limits."}}`)
+	if _, err := RouteETA(context.Background(), o, Parameter{Origin: "Lyon", Destination: "Geneva", Mode: "walking"}); err == nil || !strings.Contains(err.Error(), "too far apart") {
+		t.Errorf("RouteETA() error = %v, want too far apart", err)
+	}
+
+	o.Key = "wrong"
+	if _, err := RouteETA(context.Background(), o, Parameter{Origin: "Lyon", Destination: "Geneva"}); err == nil || !strings.Contains(err.Error(), "key is invalid") {
+		t.Errorf("RouteETA() error = %v, want an invalid key", err)
+	}
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	down := &OpenRouteService{Key: "secret-key", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	if _, err := RouteETA(context.Background(), down, Parameter{Origin: "Lyon", Destination: "Geneva"}); err == nil || strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("RouteETA() of an unreachable API = %v, want an error without the key", err)
+	}
+
+	o.Key = ""
+	if _, err := RouteETA(context.Background(), o, Parameter{Origin: "Lyon", Destination: "Geneva"}); err == nil || !strings.Contains(err.Error(), "ORS_API_KEY") {
+		t.Errorf("RouteETA() error = %v, want a missing key", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for m, want := range map[float64]string{850: "850 m", 4230: "4.2 km", 151234: "151 km"} {
+		if got := distance(m); got != want {
+			t.Errorf("distance(%v) = %s, want %s", m, got, want)
+		}
+	}
+	for d, want := range map[time.Duration]string{
+		20 * time.Second: "1 min",
+		7 * time.Minute:  "7 min",
+		2 * time.Hour:    "2 h",
+		time.Hour + 44*time.Minute + 40*time.Second: "1 h 45 min",
+	} {
+		if got := duration(d); got != want {
+			t.Errorf("duration(%v) = %s, want %s", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/datetime"
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/solar"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Tell the current astronomical season of a location, which is reversed in the southern hemisphere, e.g. "is it spring in Sydney?" or "how long until the winter solstice?". If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format and give the IANA time zone of the city. The function returns the season and since when, the next equinox or solstice and in how many days, and whether the daylight is getting longer or shorter.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+	Timezone  string  `json:"timezone,omitempty" jsonschema:"description=The time zone of the dates in IANA Time Zone Database identifier format. Defaults to UTC,example=Australia/Sydney"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "season-info", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x10F}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "timezone", msg.Timezone)
+
+	info, err := SeasonInfo(msg.Latitude, msg.Longitude, msg.Timezone, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] SeasonInfo error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not tell the season: %v", err))
+		return
+	}
+
+	result := info.String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Info is the season of a location at a time.
+type Info struct {
+	Latitude, Longitude float64
+	Now                 time.Time
+	// Last and Next are the turns around Now, in the time zone of the
+	// dates.
+	Last, Next solar.At
+	// Lengthening is whether the days are getting longer. The daylight of
+	// the equator hardly changes, it is neither lengthening nor shortening.
+	Lengthening bool
+}
+
+// SeasonInfo returns the season at lat,lon at now, with its dates in the
+// time zone tz, UTC when empty.
+func SeasonInfo(lat, lon float64, tz string, now time.Time) (*Info, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return nil, err
+	}
+	loc, err := datetime.LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	last, next := solar.Around(now)
+	last.Time, next.Time = last.Time.In(loc), next.Time.In(loc)
+	// the daylight follows the declination of the sun in the northern
+	// hemisphere and goes against it in the southern one
+	rising := solar.Declination(now.Add(12*time.Hour)) > solar.Declination(now.Add(-12*time.Hour))
+	return &Info{
+		Latitude:    lat,
+		Longitude:   lon,
+		Now:         now.In(loc),
+		Last:        last,
+		Next:        next,
+		Lengthening: lat != 0 && rising == (lat > 0),
+	}, nil
+}
+
+// Hemisphere returns "northern" or "southern", or "" on the equator.
+func (i *Info) Hemisphere() string {
+	switch {
+	case i.Latitude > 0:
+		return "northern"
+	case i.Latitude < 0:
+		return "southern"
+	}
+	return ""
+}
+
+// seasons are the seasons of the northern hemisphere beginning at the turns,
+// and those of the southern hemisphere are the ones two turns later.
+var seasons = []string{"spring", "summer", "autumn", "winter"}
+
+// Season returns the season beginning at turn in the hemisphere, "" on the
+// equator.
+func Season(turn solar.Turn, hemisphere string) string {
+	i := int(turn) / 90
+	switch hemisphere {
+	case "northern":
+		return seasons[i]
+	case "southern":
+		return seasons[(i+2)%4]
+	}
+	return ""
+}
+
+// String returns the info, e.g. "At 48.85,2.35 it is autumn in the northern
+// hemisphere, since the September equinox on 2026-09-23 02:05 CEST. The
+// December solstice, the beginning of winter, is in 68 days on 2026-12-21
+// 21:50 CET. The days are getting shorter."
+func (i *Info) String() string {
+	const layout = "2006-01-02 15:04 MST"
+	hemisphere := i.Hemisphere()
+	place := fmt.Sprintf("At %v,%v", i.Latitude, i.Longitude)
+	if hemisphere == "" {
+		return fmt.Sprintf("%s on the equator there are no astronomical seasons, the last turn was the %s on %s. The %s is %s on %s. The daylight stays about 12 hours all year.",
+			place, i.Last.Turn, i.Last.Time.Format(layout), i.Next.Turn, until(i.Next.Time.Sub(i.Now)), i.Next.Time.Format(layout))
+	}
+
+	trend := "shorter"
+	if i.Lengthening {
+		trend = "longer"
+	}
+	return fmt.Sprintf("%s it is %s in the %s hemisphere, since the %s on %s. The %s, the beginning of %s, is %s on %s. The days are getting %s.",
+		place, Season(i.Last.Turn, hemisphere), hemisphere, i.Last.Turn, i.Last.Time.Format(layout),
+		i.Next.Turn, Season(i.Next.Turn, hemisphere), until(i.Next.Time.Sub(i.Now)), i.Next.Time.Format(layout), trend)
+}
+
+// until formats the time to a turn in days, e.g. "in 68 days".
+func until(d time.Duration) string {
+	switch days := int(d.Hours() / 24); days {
+	case 0:
+		return "in less than a day"
+	case 1:
+		return "in 1 day"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/solar"
+)
+
+func TestSeasonInfo(t *testing.T) {
+	now := time.Date(2024, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	paris, err := SeasonInfo(48.8566, 2.3522, "Europe/Paris", now)
+	if err != nil {
+		t.Fatalf("SeasonInfo() error = %v", err)
+	}
+	want := "At 48.8566,2.3522 it is autumn in the northern hemisphere, since the September equinox on 2024-09-22 14:37 CEST. The December solstice, the beginning of winter, is in 67 days on 2024-12-21 10:14 CET. The days are getting shorter."
+	if got := paris.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+
+	sydney, err := SeasonInfo(-33.8688, 151.2093, "Australia/Sydney", now)
+	if err != nil {
+		t.Fatalf("SeasonInfo() error = %v", err)
+	}
+	want = "At -33.8688,151.2093 it is spring in the southern hemisphere, since the September equinox on 2024-09-22 22:37 AEST. The December solstice, the beginning of summer, is in 67 days on 2024-12-21 20:14 AEDT. The days are getting longer."
+	if got := sydney.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHemispheres(t *testing.T) {
+	tests := []struct {
+		name        string
+		now         time.Time
+		north       string
+		south       string
+		northLonger bool
+	}{
+		{"after the March equinox", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), "spring", "autumn", true},
+		{"after the June solstice", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), "summer", "winter", false},
+		{"after the September equinox", time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), "autumn", "spring", false},
+		{"after the December solstice", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), "winter", "summer", true},
+		{"just before the June solstice", time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), "spring", "autumn", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			north, err := SeasonInfo(60, 0, "", tt.now)
+			if err != nil {
+				t.Fatalf("SeasonInfo() error = %v", err)
+			}
+			south, err := SeasonInfo(-60, 0, "", tt.now)
+			if err != nil {
+				t.Fatalf("SeasonInfo() error = %v", err)
+			}
+			if got := Season(north.Last.Turn, north.Hemisphere()); got != tt.north {
+				t.Errorf("northern season = %s, want %s", got, tt.north)
+			}
+			if got := Season(south.Last.Turn, south.Hemisphere()); got != tt.south {
+				t.Errorf("southern season = %s, want %s", got, tt.south)
+			}
+			if north.Lengthening != tt.northLonger || south.Lengthening == tt.northLonger {
+				t.Errorf("lengthening north %v, south %v, want north %v and the opposite south", north.Lengthening, south.Lengthening, tt.northLonger)
+			}
+			if north.Last != south.Last || north.Next != south.Next {
+				t.Errorf("the turns differ between the hemispheres: %v %v and %v %v", north.Last, north.Next, south.Last, south.Next)
+			}
+		})
+	}
+}
+
+func TestEquator(t *testing.T) {
+	info, err := SeasonInfo(0, 30, "", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("SeasonInfo() error = %v", err)
+	}
+	if info.Lengthening || info.Hemisphere() != "" || Season(info.Last.Turn, "") != "" {
+		t.Errorf("SeasonInfo() on the equator = %+v", info)
+	}
+	if info.Last.Turn != solar.MarchEquinox || info.Next.Turn != solar.JuneSolstice {
+		t.Errorf("turns = %v, %v, want the March equinox and the June solstice", info.Last.Turn, info.Next.Turn)
+	}
+}
+
+func TestSeasonInfoInvalid(t *testing.T) {
+	if _, err := SeasonInfo(91, 0, "", time.Now()); err == nil {
+		t.Error("SeasonInfo() with a latitude of 91 error = nil")
+	}
+	if _, err := SeasonInfo(10, 0, "Mars/Olympus", time.Now()); err == nil {
+		t.Error("SeasonInfo() with an unknown time zone error = nil")
+	}
+}
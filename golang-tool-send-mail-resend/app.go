@@ -1,13 +1,19 @@
 package main
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/resend/resend-go/v2"
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
 	"github.com/yomorun/yomo/serverless"
 )
 
@@ -21,6 +27,10 @@ func InputSchema() any {
 	return &Parameter{}
 }
 
+func init() {
+	registry.Register(registry.Tool{Name: "send-mail-resend", Description: Description(), InputSchema: InputSchema(), Env: []string{"RESEND_API_KEY", "FROM_EMAIL"}, Upstream: "https://api.resend.com"})
+}
+
 var client *resend.Client
 
 // Init is an optional function invoked during the initialization phase of the
@@ -47,23 +57,74 @@ type Parameter struct {
 	To      string `json:"to" jsonschema:"description=The recipient's email address"`
 	Subject string `json:"subject" jsonschema:"description=The subject of the email"`
 	Body    string `json:"body" jsonschema:"description=The content of the email"`
+	// IdempotencyKey makes a retried call return the result of the first one
+	// instead of sending the email again.
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description=A unique key of this email. Retrying with the same key does not send it twice"`
+}
+
+// idempotencyWindow is how long the result of a call with an idempotency key
+// is returned to its retries.
+const idempotencyWindow = 10 * time.Minute
+
+// sent maps the idempotency keys to the emails sent. Its entries are not
+// jittered, a retry within the window is never sent twice.
+var sent = cache.New[sentEmail](idempotencyWindow).WithJitter(0)
+
+// sentEmail is the result of an email sent with an idempotency key, and the
+// digest of the email that tells a retry from another email reusing the key.
+type sentEmail struct {
+	digest [sha256.Size]byte
+	result string
+}
+
+// ErrKeyReused is returned for an idempotency key already used within the
+// window for an email with another recipient, subject or body, which is not
+// sent.
+var ErrKeyReused = errors.New("the idempotency key was already used for another email, give this email a new key")
+
+// digest hashes the fields of the email that a retry repeats.
+func digest(to, subject, body string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(to + "\x00" + subject + "\x00" + body))
 }
 
 // Handler orchestrates the core processing logic of this function
 func Handler(ctx serverless.Context) {
 	var args Parameter
-	ctx.ReadLLMArguments(&args)
+	if !sfn.ReadArgs(ctx, &args) {
+		return
+	}
 
-	result, err := sendEmail(args)
+	result, err := sendOnce(args)
 	if err != nil {
-		ctx.WriteLLMResult(fmt.Sprintf("Failed to send email: %v", err))
+		sfn.WriteText(ctx, fmt.Sprintf("Failed to send email: %v", err))
 		return
 	}
 
-	ctx.WriteLLMResult(result)
+	sfn.WriteText(ctx, result)
 	slog.Info("send-email", "to", args.To, "result", result)
 }
 
+// sendOnce sends the email, or returns the result of the call with the same
+// idempotency key and the same email within the window. A failed send is not
+// remembered, so it can be retried.
+func sendOnce(args Parameter) (string, error) {
+	if args.IdempotencyKey == "" {
+		return sendEmail(args)
+	}
+	d := digest(args.To, args.Subject, args.Body)
+	e, err := sent.GetOrLoad(args.IdempotencyKey, func() (sentEmail, error) {
+		result, err := sendEmail(args)
+		return sentEmail{digest: d, result: result}, err
+	})
+	if err != nil {
+		return "", err
+	}
+	if e.digest != d {
+		return "", ErrKeyReused
+	}
+	return e.result, nil
+}
+
 func sendEmail(args Parameter) (string, error) {
 	if err := godotenv.Load(); err != nil {
 		slog.Warn("Error loading .env file", "error", err)
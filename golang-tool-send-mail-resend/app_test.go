@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/resend/resend-go/v2"
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+)
+
+func TestSendOnce(t *testing.T) {
+	var sends atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/emails" {
+			http.NotFound(w, r)
+			return
+		}
+		n := sends.Add(1)
+		fmt.Fprintf(w, `{"id":"email-%d"}`, n)
+	}))
+	defer srv.Close()
+
+	oldClient, oldSent := client, sent
+	t.Cleanup(func() { client, sent = oldClient, oldSent })
+	client = resend.NewClient("key")
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	sent = cache.New[sentEmail](idempotencyWindow)
+
+	args := Parameter{To: "jane@example.com", Subject: "Hi", Body: "Hello", IdempotencyKey: "greeting-1"}
+	first, err := sendOnce(args)
+	if err != nil {
+		t.Fatalf("sendOnce() error = %v", err)
+	}
+	retry, err := sendOnce(args)
+	if err != nil {
+		t.Fatalf("sendOnce() error = %v", err)
+	}
+	if n := sends.Load(); n != 1 {
+		t.Errorf("the email was sent %d times, want 1", n)
+	}
+	if retry != first {
+		t.Errorf("the retry returned %q, want the first result %q", retry, first)
+	}
+
+	// the key of another email is not a retry, the email is not dropped
+	// for the result of the first one
+	other := args
+	other.To = "john@example.com"
+	if _, err := sendOnce(other); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("sendOnce() of another email with the key error = %v, want ErrKeyReused", err)
+	}
+	if n := sends.Load(); n != 1 {
+		t.Errorf("the email was sent %d times, want 1", n)
+	}
+
+	// another key, or none, sends again
+	args.IdempotencyKey = "greeting-2"
+	if _, err := sendOnce(args); err != nil {
+		t.Fatalf("sendOnce() error = %v", err)
+	}
+	args.IdempotencyKey = ""
+	sendOnce(args)
+	sendOnce(args)
+	if n := sends.Load(); n != 4 {
+		t.Errorf("the email was sent %d times, want 4", n)
+	}
+}
+
+func TestSendOnceRetriesFailures(t *testing.T) {
+	var sends atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sends.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"try again"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"email-2"}`)
+	}))
+	defer srv.Close()
+
+	oldClient, oldSent := client, sent
+	t.Cleanup(func() { client, sent = oldClient, oldSent })
+	client = resend.NewClient("key")
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	sent = cache.New[sentEmail](idempotencyWindow)
+
+	args := Parameter{To: "jane@example.com", Subject: "Hi", Body: "Hello", IdempotencyKey: "greeting-1"}
+	if _, err := sendOnce(args); err == nil {
+		t.Fatal("sendOnce() should fail")
+	}
+	if _, err := sendOnce(args); err != nil {
+		t.Fatalf("the retry of a failed send failed: %v", err)
+	}
+	if n := sends.Load(); n != 2 {
+		t.Errorf("the email was sent %d times, want 2", n)
+	}
+}
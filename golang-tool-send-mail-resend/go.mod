@@ -1,8 +1,11 @@
-module github.com/yomo-mqtt/llm-function-calling/examples/golang-tool-send-mail-resend
+module github.com/yomorun/llm-function-calling-examples/golang-tool-send-mail-resend
 
 go 1.21
 
-require github.com/yomorun/yomo v1.18.11
+require (
+	github.com/yomorun/llm-function-calling-examples/internal v0.0.0
+	github.com/yomorun/yomo v1.18.11
+)
 
 require (
 	github.com/joho/godotenv v1.5.1
@@ -10,8 +13,17 @@ require (
 )
 
 require (
+	github.com/bahlo/generic-list-go v0.2.0 // indirect
+	github.com/buger/jsonparser v1.1.1 // indirect
 	github.com/caarlos0/env/v6 v6.10.1 // indirect
+	github.com/invopop/jsonschema v0.12.0 // indirect
 	github.com/lmittmann/tint v1.0.4 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/sashabaranov/go-openai v1.27.0 // indirect
+	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
+	golang.org/x/sync v0.7.0 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/yomorun/llm-function-calling-examples/internal => ../internal
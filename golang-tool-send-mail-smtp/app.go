@@ -1,11 +1,17 @@
 package main
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/smtp"
 	"os"
+	"time"
 
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
 	"github.com/yomorun/yomo/serverless"
 )
 
@@ -19,19 +25,92 @@ type Parameter struct {
 	To      string `json:"to" jsonschema:"description=Recipient's email address,example=example@example.com"`
 	Subject string `json:"subject" jsonschema:"description=Email subject"`
 	Body    string `json:"body" jsonschema:"description=Email content"`
+	// IdempotencyKey makes a retried call return the result of the first one
+	// instead of sending the email again.
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"description=A unique key of this email. Retrying with the same key does not send it twice"`
 }
 
+// idempotencyWindow is how long the result of a call with an idempotency key
+// is returned to its retries.
+const idempotencyWindow = 10 * time.Minute
+
+// sent maps the idempotency keys to the emails sent. Its entries are not
+// jittered, a retry within the window is never sent twice.
+var sent = cache.New[sentEmail](idempotencyWindow).WithJitter(0)
+
+// sentEmail is the result of an email sent with an idempotency key, and the
+// digest of the email that tells a retry from another email reusing the key.
+type sentEmail struct {
+	digest [sha256.Size]byte
+	result string
+}
+
+// ErrKeyReused is returned for an idempotency key already used within the
+// window for an email with another recipient, subject or body, which is not
+// sent.
+var ErrKeyReused = errors.New("the idempotency key was already used for another email, give this email a new key")
+
+// digest hashes the fields of the email that a retry repeats.
+func digest(to, subject, body string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(to + "\x00" + subject + "\x00" + body))
+}
+
+// sendMail is replaced in tests
+var sendMail = smtp.SendMail
+
 func InputSchema() any {
 	return &Parameter{}
 }
 
+func init() {
+	registry.Register(registry.Tool{Name: "send-mail-smtp", Description: Description(), InputSchema: InputSchema(), Env: []string{"SMTP_HOST", "SMTP_PORT", "FROM_EMAIL"}})
+}
+
 // Handler processes the email sending logic
 func Handler(ctx serverless.Context) {
 	var msg Parameter
-	ctx.ReadLLMArguments(&msg)
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
 
 	slog.Info("send-mail", "msg", msg)
 
+	result, err := sendOnce(msg)
+	if err != nil {
+		slog.Error("Failed to send email", "error", err)
+		if errors.Is(err, ErrKeyReused) {
+			sfn.WriteText(ctx, fmt.Sprintf("Failed to send email: %v", err))
+			return
+		}
+		sfn.WriteText(ctx, "Failed to send email, please try again later")
+		return
+	}
+
+	sfn.WriteText(ctx, result)
+}
+
+// sendOnce sends the email, or returns the result of the call with the same
+// idempotency key and the same email within the window. A failed send is not
+// remembered, so it can be retried.
+func sendOnce(msg Parameter) (string, error) {
+	if msg.IdempotencyKey == "" {
+		return send(msg)
+	}
+	d := digest(msg.To, msg.Subject, msg.Body)
+	e, err := sent.GetOrLoad(msg.IdempotencyKey, func() (sentEmail, error) {
+		result, err := send(msg)
+		return sentEmail{digest: d, result: result}, err
+	})
+	if err != nil {
+		return "", err
+	}
+	if e.digest != d {
+		return "", ErrKeyReused
+	}
+	return e.result, nil
+}
+
+func send(msg Parameter) (string, error) {
 	// Get email configuration from environment variables
 	smtpHost := os.Getenv("SMTP_HOST")
 	smtpPort := os.Getenv("SMTP_PORT")
@@ -41,19 +120,16 @@ func Handler(ctx serverless.Context) {
 	emailBody := fmt.Sprintf("Subject: %s\r\n\r\n%s", msg.Subject, msg.Body)
 
 	// Send email
-	err := smtp.SendMail(
+	err := sendMail(
 		smtpHost+":"+smtpPort,
 		nil,
 		fromEmail,
 		[]string{msg.To},
 		[]byte(emailBody),
 	)
-
 	if err != nil {
-		slog.Error("Failed to send email", "error", err)
-		ctx.WriteLLMResult("Failed to send email, please try again later")
-		return
+		return "", err
 	}
 
-	ctx.WriteLLMResult(fmt.Sprintf("Email has been successfully sent to %s", msg.To))
+	return fmt.Sprintf("Email has been successfully sent to %s", msg.To), nil
 }
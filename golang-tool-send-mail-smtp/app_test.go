@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"net/smtp"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+)
+
+func TestSendOnce(t *testing.T) {
+	var sends int
+	fail := false
+	oldSend, oldSent := sendMail, sent
+	t.Cleanup(func() { sendMail, sent = oldSend, oldSent })
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sends++
+		if fail {
+			return errors.New("421 service not available")
+		}
+		return nil
+	}
+	sent = cache.New[sentEmail](idempotencyWindow)
+
+	msg := Parameter{To: "jane@example.com", Subject: "Hi", Body: "Hello", IdempotencyKey: "greeting-1"}
+	first, err := sendOnce(msg)
+	if err != nil {
+		t.Fatalf("sendOnce() error = %v", err)
+	}
+	retry, err := sendOnce(msg)
+	if err != nil {
+		t.Fatalf("sendOnce() error = %v", err)
+	}
+	if sends != 1 {
+		t.Errorf("the email was sent %d times, want 1", sends)
+	}
+	if retry != first {
+		t.Errorf("the retry returned %q, want the first result %q", retry, first)
+	}
+
+	// the key of another email is not a retry, it is refused rather than
+	// answered with the result of the first email
+	other := msg
+	other.Body = "Hello again"
+	if _, err := sendOnce(other); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("sendOnce() of another email with the key error = %v, want ErrKeyReused", err)
+	}
+	if sends != 1 {
+		t.Errorf("the email was sent %d times, want 1", sends)
+	}
+
+	// without a key every call sends
+	msg.IdempotencyKey = ""
+	sendOnce(msg)
+	sendOnce(msg)
+	if sends != 3 {
+		t.Errorf("the email was sent %d times, want 3", sends)
+	}
+
+	// a failed send is retried
+	fail = true
+	msg.IdempotencyKey = "greeting-2"
+	if _, err := sendOnce(msg); err == nil {
+		t.Fatal("sendOnce() should fail")
+	}
+	fail = false
+	if _, err := sendOnce(msg); err != nil {
+		t.Fatalf("sendOnce() error = %v", err)
+	}
+	if sends != 5 {
+		t.Errorf("the email was sent %d times, want 5", sends)
+	}
+}
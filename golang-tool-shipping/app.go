@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Estimate the cost and the transit time of sending a parcel with a carrier, e.g. "how much is it to ship 2 kg from 94117 to 10001 with UPS?". Give the full postal codes of the origin and the destination with their 2-letter ISO country codes, US when the user gives no hint, and the weight in kilograms. The function returns the services of the carrier on that lane from the cheapest, with their price and their estimated days in transit, or that the carrier does not serve the lane.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Origin             string  `json:"origin" jsonschema:"description=The postal code to ship from e.g. 94117 or SW1A 2AA"`
+	OriginCountry      string  `json:"origin_country,omitempty" jsonschema:"description=The ISO 3166-1 alpha-2 country code of the origin. Defaults to US,example=US"`
+	Destination        string  `json:"destination" jsonschema:"description=The postal code to ship to e.g. 10001 or 10117"`
+	DestinationCountry string  `json:"destination_country,omitempty" jsonschema:"description=The ISO 3166-1 alpha-2 country code of the destination. Defaults to US,example=DE"`
+	WeightKg           float64 `json:"weight_kg" jsonschema:"description=The weight of the parcel in kilograms,minimum=0.01,maximum=68"`
+	Carrier            string  `json:"carrier" jsonschema:"description=The carrier to ship with,enum=usps,enum=ups,enum=fedex,enum=dhl_express"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "shipping", Description: Description(), InputSchema: InputSchema(), Env: []string{"SHIPPO_API_KEY"}, Upstream: shippo.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x10D}
+}
+
+var shippo = &Shippo{
+	Token:      os.Getenv("SHIPPO_API_KEY"),
+	BaseURL:    "https://api.goshippo.com",
+	HTTPClient: httpx.NewClient(15 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// the postal codes can be home addresses, only their countries are logged
+	slog.Info("[sfn] << receive", "carrier", msg.Carrier, "from", msg.OriginCountry, "to", msg.DestinationCountry, "weight_kg", msg.WeightKg)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	quote, err := Estimate(reqCtx, shippo, msg)
+	if errors.Is(err, ErrUnsupportedLane) {
+		sfn.WriteText(ctx, err.Error())
+		return
+	}
+	if err != nil {
+		slog.Warn("[sfn] Estimate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not estimate the shipping: %v", err))
+		return
+	}
+
+	result := quote.String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// ErrUnsupportedLane is returned when the carrier has no service from the
+// origin to the destination.
+var ErrUnsupportedLane = errors.New("unsupported lane")
+
+// maxWeightKg is the heaviest parcel the carriers take, 150 lb.
+const maxWeightKg = 68
+
+// The size of the parcel in centimeters, the rates of a parcel this small
+// depend on its weight only.
+const (
+	boxLength = "30"
+	boxWidth  = "20"
+	boxHeight = "15"
+)
+
+// carriers maps the carriers to their Shippo provider names.
+var carriers = map[string]string{
+	"usps":        "USPS",
+	"ups":         "UPS",
+	"fedex":       "FedEx",
+	"dhl_express": "DHL Express",
+}
+
+// formats are the shapes of the full postal codes of some countries, after
+// normalize. The codes of the other countries are only checked for their
+// characters.
+var formats = map[string]*regexp.Regexp{
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-\d{3}$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] \d[A-Z]\d$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? \d[A-Z]{2}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-\d{4}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} [A-Z]{2}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+}
+
+var anyCode = regexp.MustCompile(`^[A-Z\d][A-Z\d -]{1,9}$`)
+
+// normalize upper-cases code and puts the space of the British, Canadian
+// and Dutch codes in, e.g. "sw1a2aa" is "SW1A 2AA".
+func normalize(code, country string) string {
+	code = strings.ToUpper(strings.Join(strings.Fields(code), " "))
+	switch country {
+	case "GB", "CA":
+		if c := strings.ReplaceAll(code, " ", ""); len(c) >= 5 {
+			code = c[:len(c)-3] + " " + c[len(c)-3:]
+		}
+	case "NL":
+		if c := strings.ReplaceAll(code, " ", ""); len(c) == 6 {
+			code = c[:4] + " " + c[4:]
+		}
+	}
+	return code
+}
+
+// Address is a validated postal code of a country.
+type Address struct {
+	Code    string
+	Country string
+}
+
+func (a Address) String() string {
+	return a.Code + ", " + a.Country
+}
+
+// parseAddress checks the postal code for its country, US when empty.
+func parseAddress(code, country string) (Address, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		country = "US"
+	}
+	if len(country) != 2 {
+		return Address{}, fmt.Errorf("%q is not a 2-letter ISO 3166-1 country code", country)
+	}
+	normalized := normalize(code, country)
+	if format, ok := formats[country]; ok && !format.MatchString(normalized) {
+		return Address{}, fmt.Errorf("%q is not a full postal code of %s", code, country)
+	}
+	if !anyCode.MatchString(normalized) {
+		return Address{}, fmt.Errorf("%q is not a postal code", code)
+	}
+	return Address{Code: normalized, Country: country}, nil
+}
+
+// Estimate checks p and returns the services of its carrier between the
+// postal codes, from the cheapest.
+func Estimate(ctx context.Context, s *Shippo, p Parameter) (*Quote, error) {
+	carrier, ok := carriers[strings.ToLower(strings.TrimSpace(p.Carrier))]
+	if !ok {
+		return nil, fmt.Errorf("unknown carrier %q, use usps, ups, fedex or dhl_express", p.Carrier)
+	}
+	if p.WeightKg <= 0 || p.WeightKg > maxWeightKg {
+		return nil, fmt.Errorf("the weight must be more than 0 and at most %d kg, not %v", maxWeightKg, p.WeightKg)
+	}
+	from, err := parseAddress(p.Origin, p.OriginCountry)
+	if err != nil {
+		return nil, fmt.Errorf("origin: %w", err)
+	}
+	to, err := parseAddress(p.Destination, p.DestinationCountry)
+	if err != nil {
+		return nil, fmt.Errorf("destination: %w", err)
+	}
+	// USPS only ships from the US, no need to ask
+	if carrier == "USPS" && from.Country != "US" {
+		return nil, fmt.Errorf("%w: USPS only ships from the US, not from %s", ErrUnsupportedLane, from.Country)
+	}
+
+	rates, err := s.Rates(ctx, from, to, p.WeightKg)
+	if err != nil {
+		return nil, err
+	}
+	q := &Quote{Carrier: carrier, From: from, To: to, WeightKg: p.WeightKg}
+	for _, r := range rates {
+		if strings.EqualFold(r.Provider, carrier) {
+			q.Services = append(q.Services, r)
+		}
+	}
+	if len(q.Services) == 0 {
+		return nil, fmt.Errorf("%w: %s does not ship from %s to %s", ErrUnsupportedLane, carrier, from, to)
+	}
+	sort.SliceStable(q.Services, func(i, j int) bool { return q.Services[i].Amount < q.Services[j].Amount })
+	return q, nil
+}
+
+// Rate is the price of a service of a carrier.
+type Rate struct {
+	Provider string
+	Service  string
+	Amount   float64
+	Currency string
+	// Days is the estimated transit time in business days, 0 when the
+	// carrier gives none.
+	Days int
+}
+
+// Quote is the services of a carrier for a parcel.
+type Quote struct {
+	Carrier  string
+	From, To Address
+	WeightKg float64
+	Services []Rate
+}
+
+// String returns the quote, e.g. "UPS from 94117, US to 10001, US for 2 kg:
+// Ground 12.34 USD in 4 days; ...".
+func (q *Quote) String() string {
+	services := make([]string, len(q.Services))
+	for i, r := range q.Services {
+		services[i] = fmt.Sprintf("%s %.2f %s", r.Service, r.Amount, r.Currency)
+		switch {
+		case r.Days == 1:
+			services[i] += " in 1 day"
+		case r.Days > 1:
+			services[i] += fmt.Sprintf(" in %d days", r.Days)
+		default:
+			services[i] += " with no transit time estimate"
+		}
+	}
+	return fmt.Sprintf("%s from %s to %s for %v kg, from the cheapest: %s", q.Carrier, q.From, q.To, q.WeightKg, strings.Join(services, "; "))
+}
+
+// Shippo is a client of the Shippo shipments API, see
+// https://docs.goshippo.com/shippoapi/public-api/shipments.
+type Shippo struct {
+	Token      string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Rates returns the rates of all the carriers of the account for a parcel
+// of weightKg in a box from one postal code to the other.
+func (s *Shippo) Rates(ctx context.Context, from, to Address, weightKg float64) ([]Rate, error) {
+	if s.Token == "" {
+		return nil, errors.New("SHIPPO_API_KEY is not set")
+	}
+	type address struct {
+		Zip     string `json:"zip"`
+		Country string `json:"country"`
+	}
+	body, err := json.Marshal(map[string]any{
+		"address_from": address{from.Code, from.Country},
+		"address_to":   address{to.Code, to.Country},
+		"parcels": []map[string]string{{
+			"length":        boxLength,
+			"width":         boxWidth,
+			"height":        boxHeight,
+			"distance_unit": "cm",
+			"weight":        strconv.FormatFloat(weightKg, 'f', -1, 64),
+			"mass_unit":     "kg",
+		}},
+		"async": false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+"/shipments/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "ShippoToken "+s.Token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, errors.New("the Shippo API token is invalid")
+	case http.StatusBadRequest:
+		// e.g. a postal code the carriers do not know
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("Shippo rejected the shipment: %s", strings.TrimSpace(string(msg)))
+	default:
+		return nil, fmt.Errorf("Shippo responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var shipment struct {
+		Rates []struct {
+			// a decimal string
+			Amount       string `json:"amount"`
+			Currency     string `json:"currency"`
+			Provider     string `json:"provider"`
+			ServiceLevel struct {
+				Name string `json:"name"`
+			} `json:"servicelevel"`
+			EstimatedDays int `json:"estimated_days"`
+		} `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&shipment); err != nil {
+		return nil, fmt.Errorf("decode the Shippo response: %w", err)
+	}
+	rates := make([]Rate, 0, len(shipment.Rates))
+	for _, r := range shipment.Rates {
+		amount, err := strconv.ParseFloat(r.Amount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("decode the amount of %s %s: %w", r.Provider, r.ServiceLevel.Name, err)
+		}
+		rates = append(rates, Rate{
+			Provider: r.Provider,
+			Service:  r.ServiceLevel.Name,
+			Amount:   amount,
+			Currency: r.Currency,
+			Days:     r.EstimatedDays,
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// shipment is the shipment a mock Shippo was asked the rates of.
+type shipment struct {
+	From    map[string]string   `json:"address_from"`
+	To      map[string]string   `json:"address_to"`
+	Parcels []map[string]string `json:"parcels"`
+}
+
+// shippoServer answers the shipments with rates, or with status when it is
+// not 200, and records the shipments it was asked.
+func shippoServer(t *testing.T, status int, rates string) (*Shippo, *[]shipment) {
+	t.Helper()
+	var asked []shipment
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "ShippoToken test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/shipments/" {
+			http.NotFound(w, r)
+			return
+		}
+		var s shipment
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			t.Errorf("decode the shipment: %v", err)
+		}
+		asked = append(asked, s)
+		w.WriteHeader(status)
+		w.Write([]byte(rates))
+	}))
+	t.Cleanup(srv.Close)
+	return &Shippo{Token: "test-token", BaseURL: srv.URL, HTTPClient: srv.Client()}, &asked
+}
+
+const domesticRates = `{"status":"SUCCESS","rates":[
+	{"amount":"24.10","currency":"USD","provider":"UPS","servicelevel":{"name":"2nd Day Air"},"estimated_days":2},
+	{"amount":"12.34","currency":"USD","provider":"UPS","servicelevel":{"name":"Ground"},"estimated_days":4},
+	{"amount":"9.85","currency":"USD","provider":"USPS","servicelevel":{"name":"Priority Mail"},"estimated_days":3},
+	{"amount":"61.00","currency":"USD","provider":"UPS","servicelevel":{"name":"Next Day Air"},"estimated_days":1}
+]}`
+
+func TestEstimate(t *testing.T) {
+	s, asked := shippoServer(t, http.StatusCreated, domesticRates)
+
+	quote, err := Estimate(context.Background(), s, Parameter{Origin: "94117", Destination: "10001-2345", WeightKg: 2.5, Carrier: "UPS"})
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	want := "UPS from 94117, US to 10001-2345, US for 2.5 kg, from the cheapest: Ground 12.34 USD in 4 days; 2nd Day Air 24.10 USD in 2 days; Next Day Air 61.00 USD in 1 day"
+	if got := quote.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+
+	if len(*asked) != 1 {
+		t.Fatalf("%d shipments asked, want 1", len(*asked))
+	}
+	got := (*asked)[0]
+	if got.From["zip"] != "94117" || got.From["country"] != "US" || got.To["zip"] != "10001-2345" {
+		t.Errorf("shipment addresses = %v to %v", got.From, got.To)
+	}
+	if p := got.Parcels[0]; p["weight"] != "2.5" || p["mass_unit"] != "kg" || p["distance_unit"] != "cm" {
+		t.Errorf("shipment parcel = %v", p)
+	}
+}
+
+func TestEstimateInternational(t *testing.T) {
+	s, asked := shippoServer(t, http.StatusCreated, `{"rates":[{"amount":"88.5","currency":"GBP","provider":"DHL Express","servicelevel":{"name":"Express Worldwide"},"estimated_days":0}]}`)
+
+	quote, err := Estimate(context.Background(), s, Parameter{Origin: "sw1a2aa", OriginCountry: "gb", Destination: "10117", DestinationCountry: "DE", WeightKg: 1, Carrier: "dhl_express"})
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	want := "DHL Express from SW1A 2AA, GB to 10117, DE for 1 kg, from the cheapest: Express Worldwide 88.50 GBP with no transit time estimate"
+	if got := quote.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if zip := (*asked)[0].From["zip"]; zip != "SW1A 2AA" {
+		t.Errorf("origin zip = %q, want SW1A 2AA", zip)
+	}
+}
+
+func TestEstimateUnsupportedLane(t *testing.T) {
+	s, asked := shippoServer(t, http.StatusCreated, domesticRates)
+
+	_, err := Estimate(context.Background(), s, Parameter{Origin: "94117", Destination: "10001", WeightKg: 1, Carrier: "fedex"})
+	if !errors.Is(err, ErrUnsupportedLane) || !strings.Contains(err.Error(), "FedEx does not ship from 94117, US to 10001, US") {
+		t.Errorf("Estimate() without FedEx rates error = %v, want ErrUnsupportedLane", err)
+	}
+
+	// USPS is not asked from abroad
+	_, err = Estimate(context.Background(), s, Parameter{Origin: "75008", OriginCountry: "FR", Destination: "10001", WeightKg: 1, Carrier: "usps"})
+	if !errors.Is(err, ErrUnsupportedLane) {
+		t.Errorf("Estimate() of USPS from France error = %v, want ErrUnsupportedLane", err)
+	}
+	if len(*asked) != 1 {
+		t.Errorf("%d shipments asked, want 1", len(*asked))
+	}
+}
+
+func TestEstimateInvalid(t *testing.T) {
+	s, asked := shippoServer(t, http.StatusCreated, domesticRates)
+	tests := []struct {
+		name string
+		p    Parameter
+		want string
+	}{
+		{"no weight", Parameter{Origin: "94117", Destination: "10001", Carrier: "ups"}, "the weight must be more than 0"},
+		{"too heavy", Parameter{Origin: "94117", Destination: "10001", WeightKg: 80, Carrier: "ups"}, "at most 68 kg"},
+		{"unknown carrier", Parameter{Origin: "94117", Destination: "10001", WeightKg: 1, Carrier: "pony express"}, "unknown carrier"},
+		{"short zip", Parameter{Origin: "9411", Destination: "10001", WeightKg: 1, Carrier: "ups"}, `origin: "9411" is not a full postal code of US`},
+		{"outward code only", Parameter{Origin: "94117", Destination: "SW1A", DestinationCountry: "GB", WeightKg: 1, Carrier: "ups"}, "destination:"},
+		{"bad country", Parameter{Origin: "94117", Destination: "10001", DestinationCountry: "USA", WeightKg: 1, Carrier: "ups"}, "not a 2-letter"},
+		{"bad characters", Parameter{Origin: "94117", Destination: "!!", DestinationCountry: "PT", WeightKg: 1, Carrier: "ups"}, "is not a postal code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Estimate(context.Background(), s, tt.p)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Estimate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+	if len(*asked) != 0 {
+		t.Errorf("invalid parcels asked %v", *asked)
+	}
+}
+
+func TestRatesErrors(t *testing.T) {
+	p := Parameter{Origin: "94117", Destination: "10001", WeightKg: 1, Carrier: "ups"}
+
+	s, _ := shippoServer(t, http.StatusBadRequest, `{"address_to":["Zip code is invalid"]}`)
+	if _, err := Estimate(context.Background(), s, p); err == nil || !strings.Contains(err.Error(), "Zip code is invalid") {
+		t.Errorf("Estimate() of a rejected shipment error = %v", err)
+	}
+
+	s.Token = "wrong"
+	if _, err := Estimate(context.Background(), s, p); err == nil || !strings.Contains(err.Error(), "token is invalid") {
+		t.Errorf("Estimate() with a wrong token error = %v", err)
+	}
+
+	s.Token = ""
+	if _, err := Estimate(context.Background(), s, p); err == nil || !strings.Contains(err.Error(), "SHIPPO_API_KEY") {
+		t.Errorf("Estimate() without a token error = %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Shorten a long http or https URL and return the short link. Pass the complete URL including its scheme and query string.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	URL string `json:"url" jsonschema:"description=The http or https URL to shorten"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "shorten-url", Description: Description(), InputSchema: InputSchema(), Env: []string{"BITLY_ACCESS_TOKEN"}, Upstream: shortener.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xBA}
+}
+
+var shortener = &Bitly{
+	Token:      os.Getenv("BITLY_ACCESS_TOKEN"),
+	BaseURL:    "https://api-ssl.bitly.com",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "url", msg.URL)
+
+	link, err := Shorten(shortener, msg.URL)
+	if err != nil {
+		slog.Warn("[sfn] Shorten error", "url", msg.URL, "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not shorten the URL: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("the short link of %s is %s", msg.URL, link))
+}
+
+// ValidateURL checks that rawURL is an absolute http or https URL.
+func ValidateURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("only http and https URLs can be shortened, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("the URL has no host")
+	}
+	return u, nil
+}
+
+// Shorten validates rawURL and shortens it with s.
+func Shorten(s *Bitly, rawURL string) (string, error) {
+	u, err := ValidateURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return s.Shorten(u.String())
+}
+
+// Bitly shortens URLs with the Bitly API v4.
+type Bitly struct {
+	Token      string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Shorten returns the Bitly short link of longURL.
+func (b *Bitly) Shorten(longURL string) (string, error) {
+	if b.Token == "" {
+		return "", errors.New("BITLY_ACCESS_TOKEN is not set")
+	}
+
+	payload, _ := json.Marshal(map[string]string{"long_url": longURL})
+	req, err := http.NewRequest(http.MethodPost, b.BaseURL+"/v4/shorten", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("bitly is not reachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// 200 means the link already existed, 201 that it was created
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var e struct {
+			Message     string `json:"message"`
+			Description string `json:"description"`
+		}
+		json.Unmarshal(body, &e)
+		switch {
+		case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized:
+			return "", errors.New("the Bitly access token is invalid")
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return "", errors.New("the Bitly rate limit is exceeded, try again later")
+		case e.Description != "":
+			return "", fmt.Errorf("bitly responded %d: %s", resp.StatusCode, e.Description)
+		case e.Message != "":
+			return "", fmt.Errorf("bitly responded %d: %s", resp.StatusCode, e.Message)
+		default:
+			return "", fmt.Errorf("bitly responded %d", resp.StatusCode)
+		}
+	}
+
+	var r struct {
+		Link string `json:"link"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil || r.Link == "" {
+		return "", errors.New("bitly returned no link")
+	}
+	return r.Link, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://example.com/a/long/path?utm_source=chat", false},
+		{"http://example.com", false},
+		{"  https://example.com  ", false},
+		{"ftp://example.com/file", true},
+		{"javascript:alert(1)", true},
+		{"mailto:ada@example.com", true},
+		{"example.com/path", true},
+		{"https://", true},
+	}
+
+	for _, tt := range tests {
+		if _, err := ValidateURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func newTestBitly(t *testing.T, handler http.HandlerFunc) *Bitly {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Bitly{Token: "test-token", BaseURL: srv.URL, HTTPClient: srv.Client()}
+}
+
+func TestShorten(t *testing.T) {
+	b := newTestBitly(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v4/shorten" || r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var body struct {
+			LongURL string `json:"long_url"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.LongURL != "https://example.com/a/long/path" {
+			t.Errorf("long_url = %s", body.LongURL)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"link":"https://bit.ly/3abcDEF","long_url":"https://example.com/a/long/path"}`))
+	})
+
+	got, err := Shorten(b, " https://example.com/a/long/path ")
+	if err != nil {
+		t.Fatalf("Shorten() error = %v", err)
+	}
+	if got != "https://bit.ly/3abcDEF" {
+		t.Errorf("Shorten() = %s, want https://bit.ly/3abcDEF", got)
+	}
+}
+
+func TestShortenRejectsScheme(t *testing.T) {
+	b := newTestBitly(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the provider should not be called for an invalid URL")
+	})
+
+	if _, err := Shorten(b, "ftp://example.com/file"); err == nil || !strings.Contains(err.Error(), "only http and https") {
+		t.Errorf("Shorten() error = %v, want a scheme error", err)
+	}
+}
+
+func TestShortenProviderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"invalid token", http.StatusForbidden, `{"message":"FORBIDDEN"}`, "access token is invalid"},
+		{"rate limited", http.StatusTooManyRequests, `{"message":"RATE_LIMIT_EXCEEDED"}`, "rate limit"},
+		{"bad request", http.StatusBadRequest, `{"message":"INVALID_ARG_LONG_URL","description":"The value provided is invalid."}`, "bitly responded 400: The value provided is invalid."},
+		{"server error", http.StatusInternalServerError, ``, "bitly responded 500"},
+		{"no link", http.StatusOK, `{}`, "bitly returned no link"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBitly(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := Shorten(b, "https://example.com")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Shorten() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShortenWithoutToken(t *testing.T) {
+	b := &Bitly{BaseURL: "http://127.0.0.1:0", HTTPClient: http.DefaultClient}
+	if _, err := Shorten(b, "https://example.com"); err == nil || !strings.Contains(err.Error(), "BITLY_ACCESS_TOKEN") {
+		t.Errorf("Shorten() error = %v, want a configuration error", err)
+	}
+}
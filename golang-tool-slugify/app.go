@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Generate a URL-safe slug from a text, e.g. a blog post title or a product name. The slug is lowercase ASCII letters and digits separated by hyphens, accents are removed from the letters.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Text string `json:"text" jsonschema:"description=The text to turn into a slug"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "slugify", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB5}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "text", msg.Text)
+
+	slug := Slugify(msg.Text)
+	if slug == "" {
+		sfn.WriteText(ctx, fmt.Sprintf("can not build a slug from %q, it has no latin letters or digits", msg.Text))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("the slug of %q is %s", msg.Text, slug))
+}
+
+// transliterations are the latin letters that have no NFD decomposition into
+// an ASCII letter and a combining mark.
+var transliterations = map[rune]string{
+	'ß': "ss",
+	'æ': "ae",
+	'Æ': "ae",
+	'œ': "oe",
+	'Œ': "oe",
+	'ø': "o",
+	'Ø': "o",
+	'đ': "d",
+	'Đ': "d",
+	'ð': "d",
+	'Ð': "d",
+	'þ': "th",
+	'Þ': "th",
+	'ł': "l",
+	'Ł': "l",
+	'ı': "i",
+}
+
+// Slugify returns the URL-safe slug of text: the text is decomposed to NFD
+// and stripped of its combining marks, lowercased, whitespace, hyphens and
+// underscores become a single hyphen and any other character is dropped.
+func Slugify(text string) string {
+	var b strings.Builder
+	// pending is set after a separator, the hyphen is only written before
+	// the next letter so that there is none at the start, the end or twice
+	pending := false
+	emit := func(s string) {
+		if pending && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pending = false
+		b.WriteString(s)
+	}
+
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			emit(string(unicode.ToLower(r)))
+		case transliterations[r] != "":
+			emit(transliterations[r])
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			pending = true
+		}
+		// combining marks, punctuation and non latin letters are dropped
+	}
+	return b.String()
+}
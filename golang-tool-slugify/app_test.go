@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"simple", "Hello World", "hello-world"},
+		{"accented", "Crème Brûlée à la Française", "creme-brulee-a-la-francaise"},
+		{"precomposed and combining accents", "Caf\u00e9 Cafe\u0301", "cafe-cafe"},
+		{"letters without decomposition", "Straße Øresund Łódź", "strasse-oresund-lodz"},
+		{"punctuation", "Hello, World! What's new?", "hello-world-whats-new"},
+		{"collapsed whitespace", "  many \t spaces\n\nhere  ", "many-spaces-here"},
+		{"collapsed separators", "snake_case -- and - dashes", "snake-case-and-dashes"},
+		{"digits", "Top 10 Go Tips (2024)", "top-10-go-tips-2024"},
+		{"non latin letters dropped", "東京 Tokyo", "tokyo"},
+		{"only punctuation", "?!...", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.text); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/solar"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Estimate the energy that solar panels produce today and tomorrow at a location from the forecast solar irradiance and cloud cover, e.g. "how much will my 10 square meter panels produce in Madrid today?". If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns the estimated output in kWh per day.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude          float64 `json:"latitude" jsonschema:"description=The latitude of the panels in decimal format,minimum=-90,maximum=90"`
+	Longitude         float64 `json:"longitude" jsonschema:"description=The longitude of the panels in decimal format,minimum=-180,maximum=180"`
+	AreaM2            float64 `json:"area_m2" jsonschema:"description=The total area of the panels in square meters,minimum=0,maximum=10000,example=10"`
+	EfficiencyPercent float64 `json:"efficiency_percent,omitempty" jsonschema:"description=The efficiency of the panels in percent. Defaults to 20,minimum=1,maximum=50,example=21.5"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "solar-estimate", Description: Description(), InputSchema: InputSchema(), Upstream: forecaster.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xEA}
+}
+
+var forecaster = &OpenMeteo{
+	BaseURL:    "https://api.open-meteo.com",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "area_m2", msg.AreaM2, "efficiency_percent", msg.EfficiencyPercent)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := SolarEstimate(reqCtx, forecaster, msg)
+	if err != nil {
+		slog.Warn("[sfn] SolarEstimate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not estimate the solar output: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+const (
+	defaultEfficiencyPercent = 20
+	maxAreaM2                = 10000
+	// performanceRatio is the share of the energy of the cells that reaches
+	// the grid after the inverter, wiring, heat and dirt losses, a common
+	// planning value for fixed panels.
+	performanceRatio = 0.75
+)
+
+// SolarEstimate validates p and estimates the output of its panels today and
+// tomorrow from the forecast of o.
+func SolarEstimate(ctx context.Context, o *OpenMeteo, p Parameter) (string, error) {
+	if err := geo.ValidateCoordinate(p.Latitude, p.Longitude); err != nil {
+		return "", err
+	}
+	if !(p.AreaM2 > 0 && p.AreaM2 <= maxAreaM2) {
+		return "", fmt.Errorf("the area must be more than 0 and up to %d m²", maxAreaM2)
+	}
+	efficiency := p.EfficiencyPercent
+	if efficiency == 0 {
+		efficiency = defaultEfficiencyPercent
+	}
+	if !(efficiency >= 1 && efficiency <= 50) {
+		return "", errors.New("the efficiency must be between 1 and 50 percent")
+	}
+
+	hours, err := o.Hourly(ctx, p.Latitude, p.Longitude)
+	if err != nil {
+		return "", err
+	}
+
+	s := fmt.Sprintf("Estimated output of %v m² of panels at %v%% efficiency at %v,%v:", p.AreaM2, efficiency, p.Latitude, p.Longitude)
+	var estimated int
+	days := splitDays(hours)
+	if len(days) > 2 {
+		days = days[:2]
+	}
+	for i, day := range days {
+		irradiance, n, err := Irradiance(day, p.Latitude, p.Longitude)
+		if err != nil {
+			return "", err
+		}
+		estimated += n
+		if i > 0 {
+			s += ","
+		}
+		s += fmt.Sprintf(" %.1f kWh %s (%s)", Energy(irradiance, p.AreaM2, efficiency/100), [...]string{"today", "tomorrow"}[i], day[0].Time.Format("2006-01-02"))
+	}
+	s += "."
+	if estimated > 0 {
+		s += fmt.Sprintf(" The irradiance of %d hours was missing and estimated from the cloud cover.", estimated)
+	}
+	return s, nil
+}
+
+// Energy returns the kWh that panels of areaM2 with the efficiency, 0 to 1,
+// produce from the mean irradiance in W/m² of consecutive hours: the Wh/m²
+// reaching the panels, times their area, their efficiency and the
+// performanceRatio. It assumes panels lying flat, a tilt towards the sun
+// produces more.
+func Energy(irradiance []float64, areaM2, efficiency float64) float64 {
+	var wh float64
+	for _, g := range irradiance {
+		wh += math.Max(g, 0)
+	}
+	return wh / 1000 * areaM2 * efficiency * performanceRatio
+}
+
+// Hour is an hour of the forecast.
+type Hour struct {
+	// Time is the end of the hour, in the time zone of the location.
+	Time time.Time
+	// Irradiance is the mean global horizontal irradiance of the hour in
+	// W/m², nil when it is missing.
+	Irradiance *float64
+	// CloudCover is the total cloud cover in percent, nil when it is
+	// missing.
+	CloudCover *float64
+}
+
+// Irradiance returns the irradiance of the hours, with the missing ones
+// estimated from their cloud cover, and how many were estimated. It fails
+// when an hour has neither.
+func Irradiance(hours []Hour, lat, lon float64) ([]float64, int, error) {
+	values := make([]float64, len(hours))
+	var estimated int
+	for i, h := range hours {
+		switch {
+		case h.Irradiance != nil:
+			values[i] = *h.Irradiance
+		case h.CloudCover != nil:
+			values[i] = CloudyIrradiance(h.Time.Add(-30*time.Minute), lat, lon, *h.CloudCover)
+			estimated++
+		default:
+			return nil, 0, fmt.Errorf("the forecast has no irradiance and no cloud cover at %s", h.Time.Format("2006-01-02 15:04"))
+		}
+	}
+	return values, estimated, nil
+}
+
+// CloudyIrradiance estimates the global horizontal irradiance in W/m² at t
+// under a cloud cover in percent, with the clear sky irradiance
+// 910·sin(elevation)-30 reduced by 1-0.75·cover^3.4 after Kasten and
+// Czeplak (1980).
+func CloudyIrradiance(t time.Time, lat, lon, cloudCover float64) float64 {
+	clear := 910*math.Sin(solar.Elevation(t, lat, lon)*math.Pi/180) - 30
+	if clear <= 0 {
+		return 0
+	}
+	return clear * (1 - 0.75*math.Pow(cloudCover/100, 3.4))
+}
+
+// splitDays groups the hours by the local date of their time, like
+// Open-Meteo does: the hour ending at midnight is the first of the next day,
+// the sun is down then anyway.
+func splitDays(hours []Hour) [][]Hour {
+	var days [][]Hour
+	var last string
+	for _, h := range hours {
+		date := h.Time.Format("2006-01-02")
+		if len(days) == 0 || date != last {
+			days = append(days, nil)
+			last = date
+		}
+		days[len(days)-1] = append(days[len(days)-1], h)
+	}
+	return days
+}
+
+// OpenMeteo is a client of the Open-Meteo forecast API, which needs no key,
+// see https://open-meteo.com/en/docs.
+type OpenMeteo struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Hourly returns the hours of today and tomorrow at lat,lon in its local
+// time.
+func (o *OpenMeteo) Hourly(ctx context.Context, lat, lon float64) ([]Hour, error) {
+	q := url.Values{}
+	q.Set("latitude", fmt.Sprint(lat))
+	q.Set("longitude", fmt.Sprint(lon))
+	q.Set("hourly", "shortwave_radiation,cloud_cover")
+	q.Set("forecast_days", "2")
+	q.Set("timezone", "auto")
+	q.Set("timeformat", "unixtime")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.BaseURL+"/v1/forecast?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r struct {
+		Reason           string `json:"reason"`
+		UTCOffsetSeconds int    `json:"utc_offset_seconds"`
+		Hourly           struct {
+			Time               []int64    `json:"time"`
+			ShortwaveRadiation []*float64 `json:"shortwave_radiation"`
+			CloudCover         []*float64 `json:"cloud_cover"`
+		} `json:"hourly"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decode the Open-Meteo response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Open-Meteo responded %d: %s", resp.StatusCode, r.Reason)
+	}
+	if len(r.Hourly.Time) == 0 {
+		return nil, errors.New("the Open-Meteo forecast has no hours")
+	}
+
+	loc := time.FixedZone("", r.UTCOffsetSeconds)
+	hours := make([]Hour, len(r.Hourly.Time))
+	for i, t := range r.Hourly.Time {
+		hours[i].Time = time.Unix(t, 0).In(loc)
+		if i < len(r.Hourly.ShortwaveRadiation) {
+			hours[i].Irradiance = r.Hourly.ShortwaveRadiation[i]
+		}
+		if i < len(r.Hourly.CloudCover) {
+			hours[i].CloudCover = r.Hourly.CloudCover[i]
+		}
+	}
+	return hours, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnergy(t *testing.T) {
+	// 5 kWh/m² over the day, the negative night value of a sensor offset
+	// counts as 0
+	irradiance := []float64{-5, 0, 500, 1000, 1500, 1000, 1000, 0}
+	// 5 kWh/m² × 10 m² × 20% × 0.75
+	if got := Energy(irradiance, 10, 0.2); math.Abs(got-7.5) > 1e-9 {
+		t.Errorf("Energy() = %v, want 7.5", got)
+	}
+	if got := Energy(nil, 10, 0.2); got != 0 {
+		t.Errorf("Energy() of no hours = %v, want 0", got)
+	}
+	// the output is proportional to the area and the efficiency
+	if a, b := Energy(irradiance, 20, 0.2), Energy(irradiance, 10, 0.4); math.Abs(a-15) > 1e-9 || math.Abs(b-15) > 1e-9 {
+		t.Errorf("Energy() = %v and %v, want 15", a, b)
+	}
+}
+
+func TestCloudyIrradiance(t *testing.T) {
+	// the sun is at the zenith of 0,0 at the solar noon of the equinox
+	noon := time.Date(2024, 3, 20, 12, 7, 0, 0, time.UTC)
+	if got := CloudyIrradiance(noon, 0, 0, 0); math.Abs(got-880) > 1 {
+		t.Errorf("the clear sky irradiance = %v, want 880", got)
+	}
+	if got := CloudyIrradiance(noon, 0, 0, 100); math.Abs(got-220) > 1 {
+		t.Errorf("the overcast irradiance = %v, want 220", got)
+	}
+	if got := CloudyIrradiance(noon.Add(12*time.Hour), 0, 0, 0); got != 0 {
+		t.Errorf("the irradiance at night = %v, want 0", got)
+	}
+}
+
+func TestIrradiance(t *testing.T) {
+	noon := time.Date(2024, 3, 20, 12, 37, 0, 0, time.UTC)
+	measured, cover := 600.0, 0.0
+	hours := []Hour{
+		{Time: noon.Add(-time.Hour), Irradiance: &measured, CloudCover: &cover},
+		{Time: noon, CloudCover: &cover},
+	}
+	values, estimated, err := Irradiance(hours, 0, 0)
+	if err != nil {
+		t.Fatalf("Irradiance() error = %v", err)
+	}
+	if values[0] != 600 || math.Abs(values[1]-880) > 1 || estimated != 1 {
+		t.Errorf("Irradiance() = %v, %d, want [600 880], 1", values, estimated)
+	}
+
+	hours = append(hours, Hour{Time: noon.Add(time.Hour)})
+	if _, _, err := Irradiance(hours, 0, 0); err == nil {
+		t.Error("Irradiance() of an hour without data should fail")
+	}
+}
+
+// openMeteoServer answers 48 hours from midnight of 2024-06-21 in UTC+2,
+// with 400 W/m² from 6 AM to 8 PM the first day and only an overcast sky
+// the second.
+func openMeteoServer(t *testing.T) *OpenMeteo {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/forecast" || !strings.Contains(r.URL.Query().Get("hourly"), "shortwave_radiation") {
+			http.NotFound(w, r)
+			return
+		}
+		midnight := time.Date(2024, 6, 21, 0, 0, 0, 0, time.FixedZone("", 7200))
+		var times []int64
+		var radiation, clouds []*float64
+		for i := 0; i < 48; i++ {
+			times = append(times, midnight.Add(time.Duration(i)*time.Hour).Unix())
+			cover := 100.0
+			clouds = append(clouds, &cover)
+			if i >= 24 {
+				radiation = append(radiation, nil)
+				continue
+			}
+			g := 0.0
+			if i > 5 && i <= 20 {
+				g = 400
+			}
+			radiation = append(radiation, &g)
+		}
+		var body struct {
+			UTCOffsetSeconds int `json:"utc_offset_seconds"`
+			Hourly           struct {
+				Time               []int64    `json:"time"`
+				ShortwaveRadiation []*float64 `json:"shortwave_radiation"`
+				CloudCover         []*float64 `json:"cloud_cover"`
+			} `json:"hourly"`
+		}
+		body.UTCOffsetSeconds = 7200
+		body.Hourly.Time, body.Hourly.ShortwaveRadiation, body.Hourly.CloudCover = times, radiation, clouds
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+	return &OpenMeteo{BaseURL: srv.URL, HTTPClient: srv.Client()}
+}
+
+func TestSolarEstimate(t *testing.T) {
+	o := openMeteoServer(t)
+
+	got, err := SolarEstimate(context.Background(), o, Parameter{Latitude: 48.8566, Longitude: 2.3522, AreaM2: 10})
+	if err != nil {
+		t.Fatalf("SolarEstimate() error = %v", err)
+	}
+	// 15 hours × 400 W/m² = 6 kWh/m², × 10 m² × 20% × 0.75
+	for _, want := range []string{
+		"Estimated output of 10 m² of panels at 20% efficiency at 48.8566,2.3522: 9.0 kWh today (2024-06-21),",
+		"kWh tomorrow (2024-06-22).",
+		"The irradiance of 24 hours was missing and estimated from the cloud cover.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SolarEstimate() = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestSolarEstimateInvalid(t *testing.T) {
+	o := &OpenMeteo{}
+	for _, p := range []Parameter{
+		{Latitude: 91, AreaM2: 10},
+		{AreaM2: 0},
+		{AreaM2: -3},
+		{AreaM2: 20000},
+		{AreaM2: 10, EfficiencyPercent: 80},
+		{AreaM2: 10, EfficiencyPercent: -5},
+	} {
+		if _, err := SolarEstimate(context.Background(), o, p); err == nil {
+			t.Errorf("SolarEstimate(%+v) should fail", p)
+		}
+	}
+}
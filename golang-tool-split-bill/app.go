@@ -0,0 +1,259 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/bits"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Split an itemized bill among people. Each item is shared equally by the people in its "shared_by", or by everyone when it is empty. The tax and the tip, both percentages of the items, are divided in proportion to the items of each person. The function returns what each person owes, to the cent, adding up exactly to the bill. Always use this function instead of splitting a bill yourself.`
+}
+
+// Item is an entry of the bill.
+type Item struct {
+	Name     string   `json:"name" jsonschema:"description=The name of the item,example=Pizza"`
+	Amount   float64  `json:"amount" jsonschema:"description=The price of the item before tax and tip,minimum=0,example=24"`
+	SharedBy []string `json:"shared_by,omitempty" jsonschema:"description=The names of the people sharing the item. Defaults to everyone"`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	People     []string `json:"people" jsonschema:"description=The names of the people splitting the bill,example=Alice"`
+	Items      []Item   `json:"items" jsonschema:"description=The items of the bill"`
+	TaxPercent float64  `json:"tax_percent,omitempty" jsonschema:"description=The tax as a percentage of the items,minimum=0,example=8"`
+	TipPercent float64  `json:"tip_percent,omitempty" jsonschema:"description=The tip as a percentage of the items before tax,minimum=0,example=15"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "split-bill", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x107}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "people", msg.People, "items", len(msg.Items), "tax_percent", msg.TaxPercent, "tip_percent", msg.TipPercent)
+
+	bill, err := Split(msg)
+	if err != nil {
+		slog.Warn("[sfn] Split error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not split the bill: %v", err))
+		return
+	}
+
+	result := bill.String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Share is what a person owes, in cents.
+type Share struct {
+	Person   string
+	Subtotal int64
+	Tax      int64
+	Tip      int64
+}
+
+// Total returns the cents the person owes.
+func (s Share) Total() int64 {
+	return s.Subtotal + s.Tax + s.Tip
+}
+
+// Bill is a split bill.
+type Bill struct {
+	TaxPercent, TipPercent float64
+	Shares                 []Share
+}
+
+const (
+	// maxBill bounds the total of the items, far above any shared bill,
+	// so that the cents stay exact in a float64 and in an int64.
+	maxBill = 1e12
+	// maxPercent bounds the tax and the tip.
+	maxPercent = 1000
+)
+
+// Split validates p and splits its bill. The cents of an item or of the tax
+// and tip that do not divide evenly go to the people with the largest
+// remainders, so that the shares add up exactly to the bill.
+func Split(p Parameter) (*Bill, error) {
+	if len(p.People) == 0 {
+		return nil, errors.New("there is no one to split the bill among")
+	}
+	if len(p.Items) == 0 {
+		return nil, errors.New("the bill has no items")
+	}
+	if p.TaxPercent < 0 || p.TipPercent < 0 {
+		return nil, errors.New("the tax and the tip can not be negative")
+	}
+	if p.TaxPercent > maxPercent || p.TipPercent > maxPercent {
+		return nil, fmt.Errorf("the tax and the tip can not be more than %d%%", maxPercent)
+	}
+
+	index := make(map[string]int, len(p.People))
+	for i, name := range p.People {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			return nil, errors.New("a person has no name")
+		}
+		if _, ok := index[key]; ok {
+			return nil, fmt.Errorf("%q is listed twice", name)
+		}
+		index[key] = i
+	}
+
+	subtotals := make([]int64, len(p.People))
+	var amounts float64
+	for _, item := range p.Items {
+		if item.Amount < 0 || math.IsNaN(item.Amount) || math.IsInf(item.Amount, 0) {
+			return nil, fmt.Errorf("the amount of %q must be a number that is not negative", item.Name)
+		}
+		if amounts += item.Amount; amounts > maxBill {
+			return nil, fmt.Errorf("the items add up to more than %.0f, the largest bill that can be split", float64(maxBill))
+		}
+		shared, err := sharers(item, index, len(p.People))
+		if err != nil {
+			return nil, err
+		}
+		weights := make([]int64, len(p.People))
+		for _, i := range shared {
+			weights[i] = 1
+		}
+		for i, cents := range Allocate(toCents(item.Amount), weights) {
+			subtotals[i] += cents
+		}
+	}
+
+	var subtotal int64
+	for _, s := range subtotals {
+		subtotal += s
+	}
+	taxes := Allocate(int64(math.Round(float64(subtotal)*p.TaxPercent/100)), subtotals)
+	tips := Allocate(int64(math.Round(float64(subtotal)*p.TipPercent/100)), subtotals)
+
+	bill := &Bill{TaxPercent: p.TaxPercent, TipPercent: p.TipPercent}
+	for i, name := range p.People {
+		bill.Shares = append(bill.Shares, Share{Person: strings.TrimSpace(name), Subtotal: subtotals[i], Tax: taxes[i], Tip: tips[i]})
+	}
+	return bill, nil
+}
+
+// sharers returns the indexes of the people sharing item, everyone when
+// its SharedBy is empty.
+func sharers(item Item, index map[string]int, people int) ([]int, error) {
+	if len(item.SharedBy) == 0 {
+		all := make([]int, people)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+	seen := make(map[int]bool, len(item.SharedBy))
+	var indexes []int
+	for _, name := range item.SharedBy {
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("%q shares %q but is not one of the people", name, item.Name)
+		}
+		if !seen[i] {
+			seen[i] = true
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// Allocate divides total cents in proportion to weights, none of them
+// negative. Each share is rounded down and the cents left go one each to the
+// largest remainders, the first ones on a tie. All the shares are 0 when the
+// weights are. The products of total and the weights are computed on 128
+// bits, so that they do not overflow.
+func Allocate(total int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var sum int64
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 {
+		return shares
+	}
+
+	remainders := make([]int64, len(weights))
+	left := total
+	for i, w := range weights {
+		// the share is at most total since w is at most sum, so the
+		// quotient fits
+		hi, lo := bits.Mul64(uint64(total), uint64(w))
+		q, r := bits.Div64(hi, lo, uint64(sum))
+		shares[i], remainders[i] = int64(q), int64(r)
+		left -= shares[i]
+	}
+	for ; left > 0; left-- {
+		best := -1
+		for i, r := range remainders {
+			if weights[i] > 0 && (best < 0 || r > remainders[best]) {
+				best = i
+			}
+		}
+		shares[best]++
+		remainders[best] = -1
+	}
+	return shares
+}
+
+// toCents rounds an amount to cents.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// money writes cents as an amount with two decimals.
+func money(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+// String describes the share of each person and the total of the bill.
+func (b *Bill) String() string {
+	var total Share
+	lines := make([]string, len(b.Shares))
+	for i, s := range b.Shares {
+		lines[i] = fmt.Sprintf("- %s: %s + %s tax + %s tip = %s", s.Person, money(s.Subtotal), money(s.Tax), money(s.Tip), money(s.Total()))
+		total.Subtotal += s.Subtotal
+		total.Tax += s.Tax
+		total.Tip += s.Tip
+	}
+	heading := fmt.Sprintf("The bill of %s + %s tax (%g%%) + %s tip (%g%%) = %s is split as:", money(total.Subtotal), money(total.Tax), b.TaxPercent, money(total.Tip), b.TipPercent, money(total.Total()))
+	return heading + "\n" + strings.Join(lines, "\n")
+}
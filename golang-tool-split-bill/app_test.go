@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		total   int64
+		weights []int64
+		want    []int64
+	}{
+		{100, []int64{1, 1}, []int64{50, 50}},
+		{100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{10, []int64{1, 2, 2}, []int64{2, 4, 4}},
+		{352, []int64{1901, 1200, 1300}, []int64{152, 96, 104}},
+		{5, []int64{0, 1, 0, 1}, []int64{0, 3, 0, 2}},
+		{7, []int64{0, 0}, []int64{0, 0}},
+		// the products overflow an int64
+		{8_000_000_000_000, []int64{50_000_000_000_000, 50_000_000_000_000}, []int64{4_000_000_000_000, 4_000_000_000_000}},
+		{10_000_000_000_001, []int64{3_000_000_000_000_000, 1_000_000_000_000_000}, []int64{7_500_000_000_001, 2_500_000_000_000}},
+	}
+	for _, tt := range tests {
+		if got := Allocate(tt.total, tt.weights); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Allocate(%d, %v) = %v, want %v", tt.total, tt.weights, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	bill, err := Split(Parameter{
+		People: []string{"Alice", "Bob", "Carol"},
+		Items: []Item{
+			{Name: "Pizza", Amount: 24},
+			{Name: "Beer", Amount: 6, SharedBy: []string{"Alice"}},
+			{Name: "Soda", Amount: 4, SharedBy: []string{"bob"}},
+			{Name: "Salad", Amount: 10.01, SharedBy: []string{"Alice", "Carol"}},
+		},
+		TaxPercent: 8,
+		TipPercent: 15,
+	})
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+
+	want := []Share{
+		{Person: "Alice", Subtotal: 1901, Tax: 152, Tip: 285},
+		{Person: "Bob", Subtotal: 1200, Tax: 96, Tip: 180},
+		{Person: "Carol", Subtotal: 1300, Tax: 104, Tip: 195},
+	}
+	if !reflect.DeepEqual(bill.Shares, want) {
+		t.Errorf("Split() shares = %+v, want %+v", bill.Shares, want)
+	}
+
+	wantText := `The bill of 44.01 + 3.52 tax (8%) + 6.60 tip (15%) = 54.13 is split as:
+- Alice: 19.01 + 1.52 tax + 2.85 tip = 23.38
+- Bob: 12.00 + 0.96 tax + 1.80 tip = 14.76
+- Carol: 13.00 + 1.04 tax + 1.95 tip = 15.99`
+	if got := bill.String(); got != wantText {
+		t.Errorf("Bill.String() = %s, want %s", got, wantText)
+	}
+}
+
+func TestSplitLargeBill(t *testing.T) {
+	// the tax of a bill of the maximum overflowed the products of cents
+	bill, err := Split(Parameter{People: []string{"Alice", "Bob", "Carol"}, Items: []Item{{Name: "Building", Amount: 1e12}}, TaxPercent: 8})
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+	var total int64
+	for _, s := range bill.Shares {
+		total += s.Total()
+	}
+	if total != 108_000_000_000_000 {
+		t.Errorf("Split() shares add up to %d cents, want 108000000000000\n%s", total, bill)
+	}
+	if want := "- Alice: 333333333333.34 + 26666666666.67 tax + 0.00 tip = 360000000000.01"; !strings.Contains(bill.String(), want) {
+		t.Errorf("Split() =\n%s\nwant a line %s", bill, want)
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	pizza := []Item{{Name: "Pizza", Amount: 24}}
+	tests := []struct {
+		name string
+		p    Parameter
+		want string
+	}{
+		{"no people", Parameter{Items: pizza}, "no one"},
+		{"no items", Parameter{People: []string{"Alice"}}, "no items"},
+		{"listed twice", Parameter{People: []string{"Alice", "alice"}, Items: pizza}, "listed twice"},
+		{"unknown sharer", Parameter{People: []string{"Alice"}, Items: []Item{{Name: "Beer", Amount: 6, SharedBy: []string{"Dave"}}}}, `"Dave" shares "Beer" but is not one of the people`},
+		{"negative amount", Parameter{People: []string{"Alice"}, Items: []Item{{Name: "Refund", Amount: -5}}}, "not negative"},
+		{"negative tip", Parameter{People: []string{"Alice"}, Items: pizza, TipPercent: -10}, "can not be negative"},
+		{"huge tax", Parameter{People: []string{"Alice"}, Items: pizza, TaxPercent: 1e300}, "can not be more than 1000%"},
+		{"huge amount", Parameter{People: []string{"Alice"}, Items: []Item{{Name: "Yacht", Amount: 1e17}}}, "more than 1000000000000"},
+		{"huge total", Parameter{People: []string{"Alice"}, Items: []Item{{Name: "Yacht", Amount: 6e11}, {Name: "Jet", Amount: 6e11}}}, "more than 1000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Split(tt.p)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Split() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
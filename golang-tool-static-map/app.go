@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the URL of a static map image centered on a location, optionally with a marker on it, to show the user where a place is. Convert a place name to Latitude and Longitude geo coordinates in decimal format. Use a zoom level between 0 (the whole world) and 22 (a building), around 5 shows a country, 12 a city and 15 a neighborhood. The function returns the URL of a PNG image.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the center of the map in decimal format,minimum=-85.0511,maximum=85.0511"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the center of the map in decimal format,minimum=-180,maximum=180"`
+	Zoom      float64 `json:"zoom" jsonschema:"description=The zoom level of the map,minimum=0,maximum=22"`
+	Width     int     `json:"width,omitempty" jsonschema:"description=The width of the image in pixels. Defaults to 600,minimum=1,maximum=1280"`
+	Height    int     `json:"height,omitempty" jsonschema:"description=The height of the image in pixels. Defaults to 400,minimum=1,maximum=1280"`
+	Marker    bool    `json:"marker,omitempty" jsonschema:"description=Put a marker at the center of the map. Defaults to false"`
+	Style     string  `json:"style,omitempty" jsonschema:"description=The style of the map. Defaults to streets,enum=streets,enum=outdoors,enum=light,enum=dark,enum=satellite"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "static-map", Description: Description(), InputSchema: InputSchema(), Env: []string{"MAPBOX_ACCESS_TOKEN"}})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xFA}
+}
+
+var provider = &Mapbox{
+	Token:   os.Getenv("MAPBOX_ACCESS_TOKEN"),
+	BaseURL: "https://api.mapbox.com",
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "zoom", msg.Zoom, "width", msg.Width, "height", msg.Height, "marker", msg.Marker, "style", msg.Style)
+
+	mapURL, err := provider.URL(msg)
+	if err != nil {
+		slog.Warn("[sfn] URL error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the map: %v", err))
+		return
+	}
+
+	// the URL is not logged, it has the access token
+	sfn.WriteText(ctx, fmt.Sprintf("the map of %.4f,%.4f at zoom %v is %s", msg.Latitude, msg.Longitude, msg.Zoom, mapURL))
+}
+
+const (
+	maxZoom = 22
+	// maxSize is the largest width and height of a Mapbox static image.
+	maxSize = 1280
+	// maxLatitude is the latitude where the Web Mercator projection is cut so
+	// that the world map is a square.
+	maxLatitude = 85.05112878
+
+	defaultWidth  = 600
+	defaultHeight = 400
+	// markerColor is the hex color of the marker pin, without the #.
+	markerColor = "e74c3c"
+)
+
+// styles are the Mapbox styles, keyed by a short name.
+var styles = map[string]string{
+	"streets":   "mapbox/streets-v12",
+	"outdoors":  "mapbox/outdoors-v12",
+	"light":     "mapbox/light-v11",
+	"dark":      "mapbox/dark-v11",
+	"satellite": "mapbox/satellite-streets-v12",
+}
+
+// Mapbox builds the URLs of the Mapbox Static Images API, see
+// https://docs.mapbox.com/api/maps/static-images/.
+type Mapbox struct {
+	// Token is the access token, put in the URLs. It should be a public
+	// token restricted to the static images, since the URLs are shown to
+	// the user.
+	Token   string
+	BaseURL string
+}
+
+// URL validates p, fills in its defaults and returns the URL of its map,
+// e.g.
+//
+//	https://api.mapbox.com/styles/v1/mapbox/streets-v12/static/pin-s+e74c3c(2.3522,48.8566)/2.3522,48.8566,12/600x400?access_token=pk.x
+func (m *Mapbox) URL(p Parameter) (string, error) {
+	if m.Token == "" {
+		return "", errors.New("MAPBOX_ACCESS_TOKEN is not set")
+	}
+	if math.IsNaN(p.Latitude) || p.Latitude < -maxLatitude || p.Latitude > maxLatitude {
+		return "", fmt.Errorf("latitude %v is out of range, the map covers -%.4f to %.4f", p.Latitude, maxLatitude, maxLatitude)
+	}
+	if math.IsNaN(p.Longitude) || p.Longitude < -180 || p.Longitude > 180 {
+		return "", fmt.Errorf("longitude %v is out of range, it must be between -180 and 180", p.Longitude)
+	}
+	if math.IsNaN(p.Zoom) || p.Zoom < 0 || p.Zoom > maxZoom {
+		return "", fmt.Errorf("zoom level %v is out of range, it must be between 0 and %d", p.Zoom, maxZoom)
+	}
+
+	width, height := p.Width, p.Height
+	if width == 0 {
+		width = defaultWidth
+	}
+	if height == 0 {
+		height = defaultHeight
+	}
+	if width < 1 || width > maxSize || height < 1 || height > maxSize {
+		return "", fmt.Errorf("image size %dx%d is out of range, the width and height must be between 1 and %d pixels", width, height, maxSize)
+	}
+
+	name := strings.ToLower(strings.TrimSpace(p.Style))
+	if name == "" {
+		name = "streets"
+	}
+	style, ok := styles[name]
+	if !ok {
+		return "", fmt.Errorf("unknown map style %q, use streets, outdoors, light, dark or satellite", p.Style)
+	}
+
+	// the path only has numbers and the fixed names of the styles and the
+	// marker, the commas and parentheses of which must not be escaped
+	center := number(p.Longitude) + "," + number(p.Latitude)
+	segments := []string{"styles", "v1", style, "static"}
+	if p.Marker {
+		segments = append(segments, fmt.Sprintf("pin-s+%s(%s)", markerColor, center))
+	}
+	segments = append(segments, center+","+number(p.Zoom), fmt.Sprintf("%dx%d", width, height))
+
+	q := url.Values{}
+	q.Set("access_token", m.Token)
+	return strings.TrimRight(m.BaseURL, "/") + "/" + strings.Join(segments, "/") + "?" + q.Encode(), nil
+}
+
+// number formats a coordinate or a zoom level with at most 6 decimals,
+// about 10 cm, and without trailing zeros.
+func number(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64)
+}
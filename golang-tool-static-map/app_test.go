@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	m := &Mapbox{Token: "pk.test+token/1", BaseURL: "https://api.mapbox.com/"}
+	tests := []struct {
+		name string
+		p    Parameter
+		want string
+	}{
+		{
+			name: "defaults",
+			p:    Parameter{Latitude: 48.8566, Longitude: 2.3522, Zoom: 12},
+			want: "https://api.mapbox.com/styles/v1/mapbox/streets-v12/static/2.3522,48.8566,12/600x400?access_token=pk.test%2Btoken%2F1",
+		},
+		{
+			name: "marker",
+			p:    Parameter{Latitude: 48.8566, Longitude: 2.3522, Zoom: 12, Marker: true},
+			want: "https://api.mapbox.com/styles/v1/mapbox/streets-v12/static/pin-s+e74c3c(2.3522,48.8566)/2.3522,48.8566,12/600x400?access_token=pk.test%2Btoken%2F1",
+		},
+		{
+			name: "style and size",
+			p:    Parameter{Latitude: -33.868820, Longitude: 151.2092955, Zoom: 9.5, Width: 1280, Height: 1, Style: " Satellite "},
+			want: "https://api.mapbox.com/styles/v1/mapbox/satellite-streets-v12/static/151.209296,-33.86882,9.5/1280x1?access_token=pk.test%2Btoken%2F1",
+		},
+		{
+			name: "world",
+			p:    Parameter{Latitude: 0, Longitude: -180, Zoom: 0, Width: 512, Height: 512, Style: "dark"},
+			want: "https://api.mapbox.com/styles/v1/mapbox/dark-v11/static/-180,0,0/512x512?access_token=pk.test%2Btoken%2F1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.URL(tt.p)
+			if err != nil {
+				t.Fatalf("URL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("URL() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLErrors(t *testing.T) {
+	m := &Mapbox{Token: "pk.test", BaseURL: "https://api.mapbox.com"}
+	tests := []struct {
+		p    Parameter
+		want string
+	}{
+		{Parameter{Latitude: 86, Zoom: 3}, "latitude 86 is out of range"},
+		{Parameter{Longitude: -181, Zoom: 3}, "longitude -181 is out of range"},
+		{Parameter{Zoom: 23}, "zoom level 23 is out of range"},
+		{Parameter{Zoom: -1}, "zoom level -1 is out of range"},
+		{Parameter{Zoom: 3, Width: 1281}, "image size 1281x400 is out of range"},
+		{Parameter{Zoom: 3, Height: -5}, "image size 600x-5 is out of range"},
+		{Parameter{Zoom: 3, Style: "terrain"}, `unknown map style "terrain"`},
+	}
+	for _, tt := range tests {
+		if _, err := m.URL(tt.p); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("URL(%+v) error = %v, want %q", tt.p, err, tt.want)
+		}
+	}
+
+	if _, err := (&Mapbox{BaseURL: "https://api.mapbox.com"}).URL(Parameter{Zoom: 3}); err == nil || !strings.Contains(err.Error(), "MAPBOX_ACCESS_TOKEN is not set") {
+		t.Errorf("URL() without a token error = %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Summarize a long text in at most "maxWords" words, 100 by default. Use it for texts that are too long to work with directly, e.g. an article, a transcript or a long email thread. The text is limited to 20000 characters.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Text     string `json:"text" jsonschema:"description=The text to summarize"`
+	MaxWords int    `json:"maxWords,omitempty" jsonschema:"description=The maximum number of words of the summary,minimum=10,maximum=500"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "summarize", Description: Description(), InputSchema: InputSchema(), Env: []string{"SUMMARIZE_API_KEY"}, Upstream: summarizer.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xBD}
+}
+
+var summarizer = &Summarizer{
+	APIKey:     os.Getenv("SUMMARIZE_API_KEY"),
+	BaseURL:    getenv("SUMMARIZE_BASE_URL", "https://api.openai.com/v1"),
+	Model:      getenv("SUMMARIZE_MODEL", "gpt-4o-mini"),
+	HTTPClient: httpx.NewClient(60 * time.Second),
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "text_runes", utf8.RuneCountInString(msg.Text), "max_words", msg.MaxWords)
+
+	summary, err := summarizer.Summarize(msg.Text, msg.MaxWords)
+	if err != nil {
+		slog.Warn("[sfn] Summarize error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not summarize the text: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, summary)
+}
+
+const (
+	maxInputRunes   = 20000
+	defaultMaxWords = 100
+	minWords        = 10
+	maxWords        = 500
+)
+
+// Summarizer summarizes texts with an OpenAI compatible chat completions
+// endpoint.
+type Summarizer struct {
+	APIKey     string
+	BaseURL    string
+	Model      string
+	HTTPClient *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Prompt returns the system prompt asking for a summary of at most words
+// words.
+func Prompt(words int) string {
+	return fmt.Sprintf("You summarize texts. Write a summary of the text given by the user in at most %d words. Keep the key facts, names and numbers, do not add anything that is not in the text, and answer with the summary only.", words)
+}
+
+// Summarize returns a summary of text in at most words words, defaultMaxWords
+// if words is 0.
+func (s *Summarizer) Summarize(text string, words int) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("the text is empty")
+	}
+	if n := utf8.RuneCountInString(text); n > maxInputRunes {
+		return "", fmt.Errorf("the text has %d characters, the limit is %d", n, maxInputRunes)
+	}
+	if words == 0 {
+		words = defaultMaxWords
+	}
+	words = min(max(words, minWords), maxWords)
+	if s.APIKey == "" {
+		return "", errors.New("SUMMARIZE_API_KEY is not set")
+	}
+
+	payload, err := json.Marshal(chatRequest{
+		Model: s.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: Prompt(words)},
+			{Role: "user", Content: text},
+		},
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(s.BaseURL, "/")+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("the LLM provider is not reachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	var r chatResponse
+	decodeErr := json.Unmarshal(body, &r)
+	if resp.StatusCode != http.StatusOK {
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			return "", errors.New("the LLM provider rejected SUMMARIZE_API_KEY")
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return "", errors.New("the LLM provider rate limit is exceeded, try again later")
+		case decodeErr == nil && r.Error != nil && r.Error.Message != "":
+			return "", fmt.Errorf("the LLM provider responded %d: %s", resp.StatusCode, r.Error.Message)
+		default:
+			return "", fmt.Errorf("the LLM provider responded %d", resp.StatusCode)
+		}
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("decode LLM response: %w", decodeErr)
+	}
+	if len(r.Choices) == 0 || strings.TrimSpace(r.Choices[0].Message.Content) == "" {
+		return "", errors.New("the LLM provider returned an empty summary")
+	}
+	return strings.TrimSpace(r.Choices[0].Message.Content), nil
+}
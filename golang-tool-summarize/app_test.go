@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestSummarizer(t *testing.T, handler http.HandlerFunc) *Summarizer {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Summarizer{APIKey: "test-key", BaseURL: srv.URL + "/v1/", Model: "test-model", HTTPClient: srv.Client()}
+}
+
+func TestSummarize(t *testing.T) {
+	var got chatRequest
+	s := newTestSummarizer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  YoMo is a framework for geo-distributed AI agents.\n"}}]}`))
+	})
+
+	summary, err := s.Summarize("YoMo is an open-source LLM Function Calling Framework for building Geo-distributed AI agents.", 30)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if summary != "YoMo is a framework for geo-distributed AI agents." {
+		t.Errorf("Summarize() = %q", summary)
+	}
+
+	if got.Model != "test-model" || len(got.Messages) != 2 {
+		t.Fatalf("request = %+v", got)
+	}
+	if got.Messages[0].Role != "system" || got.Messages[0].Content != Prompt(30) {
+		t.Errorf("system message = %+v, want the prompt for 30 words", got.Messages[0])
+	}
+	if got.Messages[1].Role != "user" || !strings.HasPrefix(got.Messages[1].Content, "YoMo is") {
+		t.Errorf("user message = %+v", got.Messages[1])
+	}
+}
+
+func TestSummarizeWordBudget(t *testing.T) {
+	tests := []struct {
+		words int
+		want  int
+	}{
+		{0, defaultMaxWords},
+		{3, minWords},
+		{50, 50},
+		{10000, maxWords},
+	}
+
+	for _, tt := range tests {
+		var got chatRequest
+		s := newTestSummarizer(t, func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{"choices":[{"message":{"content":"summary"}}]}`))
+		})
+		if _, err := s.Summarize("some text", tt.words); err != nil {
+			t.Fatal(err)
+		}
+		if got.Messages[0].Content != Prompt(tt.want) {
+			t.Errorf("Summarize(%d words) prompt = %q, want %d words", tt.words, got.Messages[0].Content, tt.want)
+		}
+	}
+}
+
+func TestSummarizeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"invalid key", http.StatusUnauthorized, `{"error":{"message":"Incorrect API key provided"}}`, "rejected SUMMARIZE_API_KEY"},
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"message":"Rate limit reached"}}`, "rate limit"},
+		{"provider message", http.StatusBadRequest, `{"error":{"message":"The model does not exist"}}`, "responded 400: The model does not exist"},
+		{"server error", http.StatusBadGateway, `<html>bad gateway</html>`, "responded 502"},
+		{"no choices", http.StatusOK, `{"choices":[]}`, "empty summary"},
+		{"malformed", http.StatusOK, `{"choices":`, "decode LLM response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSummarizer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			_, err := s.Summarize("some text", 50)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Summarize() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSummarizeInput(t *testing.T) {
+	s := newTestSummarizer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the provider should not be called")
+	})
+
+	if _, err := s.Summarize("   ", 50); err == nil {
+		t.Error("Summarize() should reject an empty text")
+	}
+	if _, err := s.Summarize(strings.Repeat("é", maxInputRunes+1), 50); err == nil {
+		t.Error("Summarize() should reject a text over the size cap")
+	}
+
+	s.APIKey = ""
+	if _, err := s.Summarize("some text", 50); err == nil || !strings.Contains(err.Error(), "SUMMARIZE_API_KEY") {
+		t.Errorf("Summarize() error = %v, want a configuration error", err)
+	}
+}
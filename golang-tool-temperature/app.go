@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert a temperature between the Celsius, Fahrenheit, Kelvin and Rankine scales, e.g. "what is 100°F in Celsius?". The function returns the converted temperature, and rejects a temperature below absolute zero.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value float64 `json:"value" jsonschema:"description=The temperature to convert"`
+	From  string  `json:"from" jsonschema:"description=The scale of the temperature,enum=celsius,enum=fahrenheit,enum=kelvin,enum=rankine"`
+	To    string  `json:"to" jsonschema:"description=The scale to convert the temperature to,enum=celsius,enum=fahrenheit,enum=kelvin,enum=rankine"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "temperature", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC4}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	converted, err := Convert(msg.Value, msg.From, msg.To)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g %s to %s: %v", msg.Value, msg.From, msg.To, err))
+		return
+	}
+
+	from, _ := lookupScale(msg.From)
+	to, _ := lookupScale(msg.To)
+	sfn.WriteText(ctx, fmt.Sprintf("%g%s is %.2f%s", msg.Value, from.symbol, converted, to.symbol))
+}
+
+type scale struct {
+	symbol string
+	// toKelvin and fromKelvin convert a temperature of this scale to Kelvin
+	// and back.
+	toKelvin   func(float64) float64
+	fromKelvin func(float64) float64
+}
+
+var scales = map[string]scale{
+	"celsius": {
+		symbol:     "°C",
+		toKelvin:   func(v float64) float64 { return v + 273.15 },
+		fromKelvin: func(k float64) float64 { return k - 273.15 },
+	},
+	"fahrenheit": {
+		symbol:     "°F",
+		toKelvin:   func(v float64) float64 { return (v + 459.67) * 5 / 9 },
+		fromKelvin: func(k float64) float64 { return k*9/5 - 459.67 },
+	},
+	"kelvin": {
+		symbol:     " K",
+		toKelvin:   func(v float64) float64 { return v },
+		fromKelvin: func(k float64) float64 { return k },
+	},
+	"rankine": {
+		symbol:     "°R",
+		toKelvin:   func(v float64) float64 { return v * 5 / 9 },
+		fromKelvin: func(k float64) float64 { return k * 9 / 5 },
+	},
+}
+
+// aliases maps other common spellings to the keys of scales.
+var aliases = map[string]string{
+	"c":          "celsius",
+	"°c":         "celsius",
+	"centigrade": "celsius",
+	"f":          "fahrenheit",
+	"°f":         "fahrenheit",
+	"k":          "kelvin",
+	"kelvins":    "kelvin",
+	"r":          "rankine",
+	"°r":         "rankine",
+	"ra":         "rankine",
+}
+
+func lookupScale(name string) (scale, bool) {
+	key := strings.ToLower(strings.Join(strings.Fields(name), ""))
+	if alias, ok := aliases[key]; ok {
+		key = alias
+	}
+	s, ok := scales[key]
+	return s, ok
+}
+
+// absoluteZeroTolerance absorbs the rounding of e.g. -459.67°F, which is 0 K
+// but converts to a tiny negative Kelvin value.
+const absoluteZeroTolerance = 1e-9
+
+// Convert converts a temperature from one scale to another.
+func Convert(value float64, from, to string) (float64, error) {
+	f, ok := lookupScale(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown temperature scale %q, use celsius, fahrenheit, kelvin or rankine", from)
+	}
+	t, ok := lookupScale(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown temperature scale %q, use celsius, fahrenheit, kelvin or rankine", to)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%v is not a temperature", value)
+	}
+
+	kelvin := f.toKelvin(value)
+	if kelvin < -absoluteZeroTolerance {
+		return 0, fmt.Errorf("%g%s is below absolute zero", value, f.symbol)
+	}
+	return t.fromKelvin(math.Max(kelvin, 0)), nil
+}
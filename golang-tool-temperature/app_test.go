@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	// the same temperature in every scale, each pairing is checked
+	points := []map[string]float64{
+		{"celsius": 100, "fahrenheit": 212, "kelvin": 373.15, "rankine": 671.67},
+		{"celsius": 0, "fahrenheit": 32, "kelvin": 273.15, "rankine": 491.67},
+		{"celsius": -40, "fahrenheit": -40, "kelvin": 233.15, "rankine": 419.67},
+		{"celsius": -273.15, "fahrenheit": -459.67, "kelvin": 0, "rankine": 0},
+	}
+
+	for _, point := range points {
+		for from, value := range point {
+			for to, want := range point {
+				got, err := Convert(value, from, to)
+				if err != nil {
+					t.Errorf("Convert(%v, %s, %s) error = %v", value, from, to, err)
+					continue
+				}
+				if math.Abs(got-want) > 1e-9 {
+					t.Errorf("Convert(%v, %s, %s) = %v, want %v", value, from, to, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestConvertAliases(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{37, "C", "°F", 98.6},
+		{98.6, "Fahrenheit", "centigrade", 37},
+		{300, "K", "R", 540},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to)
+		if err != nil || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v, %s, %s) = %v, %v, want %v", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to string
+	}{
+		{"unknown from scale", 1, "reaumur", "celsius"},
+		{"unknown to scale", 1, "celsius", "delisle"},
+		{"below absolute zero celsius", -274, "celsius", "kelvin"},
+		{"below absolute zero fahrenheit", -460, "fahrenheit", "celsius"},
+		{"below absolute zero kelvin", -1, "kelvin", "celsius"},
+		{"below absolute zero rankine", -0.5, "rankine", "fahrenheit"},
+		{"not a number", math.NaN(), "celsius", "kelvin"},
+		{"infinite", math.Inf(1), "celsius", "kelvin"},
+	}
+	for _, tt := range tests {
+		if _, err := Convert(tt.value, tt.from, tt.to); err == nil {
+			t.Errorf("%s: Convert(%v, %s, %s) should fail", tt.name, tt.value, tt.from, tt.to)
+		}
+	}
+}
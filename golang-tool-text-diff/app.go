@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compare two versions of a text line by line and return a unified diff. "a" is the original text and "b" the new one. In the diff lines starting with "-" are only in a, lines starting with "+" are only in b and lines starting with a space are unchanged context. Use it to find what changed between two versions of a document, a configuration or code.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	A string `json:"a" jsonschema:"description=The original text"`
+	B string `json:"b" jsonschema:"description=The new text"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "text-diff", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB8}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "a_bytes", len(msg.A), "b_bytes", len(msg.B))
+
+	diff, err := Diff(msg.A, msg.B)
+	if err != nil {
+		slog.Warn("[sfn] Diff error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not compare the texts: %v", err))
+		return
+	}
+	if diff == "" {
+		sfn.WriteText(ctx, "no differences, the two texts are identical")
+		return
+	}
+
+	sfn.WriteText(ctx, diff)
+}
+
+const (
+	// contextLines is the number of unchanged lines shown around a change.
+	contextLines = 3
+	// maxLines bounds the lines of each text.
+	maxLines = 5000
+	// maxCells bounds the LCS table, which has a cell per pair of lines of
+	// a and b, to about 16 MB.
+	maxCells = 4_000_000
+)
+
+// op is a line of the diff.
+type op struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// Diff returns the unified diff of the lines of a and b with contextLines
+// lines of context, or "" if they are identical. A missing newline at the
+// end of a text is not reported as a difference.
+func Diff(a, b string) (string, error) {
+	linesA, linesB := splitLines(a), splitLines(b)
+	if len(linesA) > maxLines || len(linesB) > maxLines {
+		return "", fmt.Errorf("the texts must have at most %d lines each", maxLines)
+	}
+	if (len(linesA)+1)*(len(linesB)+1) > maxCells {
+		return "", fmt.Errorf("the texts are too long to compare, %d by %d lines is more than %d line pairs", len(linesA), len(linesB), maxCells)
+	}
+
+	ops := diffLines(linesA, linesB)
+	var sb strings.Builder
+	for _, h := range hunks(ops) {
+		sb.WriteString(h)
+	}
+	if sb.Len() == 0 {
+		return "", nil
+	}
+	return "--- a\n+++ b\n" + sb.String(), nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edit script from a to b based on their longest
+// common subsequence, removals before additions within a change.
+func diffLines(a, b []string) []op {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+// hunks groups the changes of ops with their context into unified diff
+// hunks. Changes closer than twice the context share a hunk.
+func hunks(ops []op) []string {
+	// posA[k] and posB[k] are the lines of a and b before ops[k]
+	posA := make([]int, len(ops)+1)
+	posB := make([]int, len(ops)+1)
+	for k, o := range ops {
+		posA[k+1], posB[k+1] = posA[k], posB[k]
+		if o.kind != '+' {
+			posA[k+1]++
+		}
+		if o.kind != '-' {
+			posB[k+1]++
+		}
+	}
+
+	var out []string
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			k++
+			continue
+		}
+
+		start := max(0, k-contextLines)
+		end := k // one past the last change of the hunk
+		for next := k; next < len(ops); next++ {
+			if ops[next].kind == ' ' {
+				continue
+			}
+			if next-end > 2*contextLines {
+				break
+			}
+			end = next + 1
+		}
+		stop := min(len(ops), end+contextLines)
+
+		countA, countB := posA[stop]-posA[start], posB[stop]-posB[start]
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(posA[start], countA), hunkRange(posB[start], countB))
+		for _, o := range ops[start:stop] {
+			sb.WriteByte(o.kind)
+			sb.WriteString(o.text)
+			sb.WriteByte('\n')
+		}
+		out = append(out, sb.String())
+		k = stop
+	}
+	return out
+}
+
+// hunkRange formats the range of a hunk header, the start is 1-based, or
+// the line before the hunk if it is empty.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    "apples\nbananas\n",
+			b:    "apples\nbananas",
+			want: "",
+		},
+		{
+			name: "insert",
+			a:    "apples\ncherries",
+			b:    "apples\nbananas\ncherries",
+			want: "--- a\n+++ b\n@@ -1,2 +1,3 @@\n apples\n+bananas\n cherries\n",
+		},
+		{
+			name: "delete",
+			a:    "apples\nbananas\ncherries",
+			b:    "apples\ncherries",
+			want: "--- a\n+++ b\n@@ -1,3 +1,2 @@\n apples\n-bananas\n cherries\n",
+		},
+		{
+			name: "change",
+			a:    "apples\nbananas\ncherries",
+			b:    "apples\nblueberries\ncherries",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n apples\n-bananas\n+blueberries\n cherries\n",
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "apples\nbananas",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+apples\n+bananas\n",
+		},
+		{
+			name: "to empty",
+			a:    "apples",
+			b:    "",
+			want: "--- a\n+++ b\n@@ -1 +0,0 @@\n-apples\n",
+		},
+		{
+			name: "distant changes make two hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name: "close changes share a hunk",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8",
+			b:    "one\n2\n3\n4\n5\n6\n7\neight",
+			want: "--- a\n+++ b\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Diff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffTooLarge(t *testing.T) {
+	large := strings.Repeat("line\n", maxLines+1)
+	if _, err := Diff(large, "line"); err == nil {
+		t.Error("Diff() should reject texts with too many lines")
+	}
+}
+
+func TestDiffCellLimit(t *testing.T) {
+	// 1999 by 1999 lines fill the LCS table of 2000x2000 cells
+	a := strings.Repeat("a\n", 1999)
+	b := strings.Repeat("b\n", 1999)
+	if _, err := Diff(a, b); err != nil {
+		t.Errorf("Diff() at the limit error = %v", err)
+	}
+	if _, err := Diff(a, b+"b\n"); err == nil || !strings.Contains(err.Error(), "too long to compare") {
+		t.Errorf("Diff() over the limit error = %v", err)
+	}
+}
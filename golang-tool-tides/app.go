@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get today's high and low tide times and heights at a coastal location, e.g. "when is high tide in Plymouth today?". If the place name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns the tides in the local time of the location, or that it is not a coastal location.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "tides", Description: Description(), InputSchema: InputSchema(), Env: []string{"WORLDTIDES_API_KEY"}, Upstream: tides.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE4}
+}
+
+var tides = &WorldTides{
+	Key:        os.Getenv("WORLDTIDES_API_KEY"),
+	BaseURL:    "https://www.worldtides.info",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	day, err := Today(reqCtx, tides, msg.Latitude, msg.Longitude)
+	if errors.Is(err, ErrNotCoastal) {
+		sfn.WriteText(ctx, fmt.Sprintf("%v,%v is not a coastal location, there is no tide there", msg.Latitude, msg.Longitude))
+		return
+	}
+	if err != nil {
+		slog.Warn("[sfn] Today error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the tides: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", day)
+	sfn.WriteText(ctx, day.String())
+}
+
+// ErrNotCoastal is returned when there is no tidal data at a location.
+var ErrNotCoastal = errors.New("not a coastal location")
+
+// maxOffsetKm is how far the point the tides are predicted at may be from the
+// requested one. WorldTides falls back to the nearest sea, which can be
+// hundreds of kilometers away from an inland point.
+const maxOffsetKm = 50
+
+// Extreme is a high or a low tide.
+type Extreme struct {
+	Time time.Time
+	High bool
+	// Height is in meters above the datum of the prediction.
+	Height float64
+}
+
+// Day is the tides of a location today.
+type Day struct {
+	Latitude, Longitude float64
+	Station             string
+	Datum               string
+	Extremes            []Extreme
+}
+
+// String returns the tides, e.g. "Tides today at 50.37,-4.14: high tide
+// at 05:04 (4.82 m), low tide at 11:20 (0.93 m), ... Times are local,
+// heights above LAT."
+func (d *Day) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tides today at %v,%v", d.Latitude, d.Longitude)
+	if d.Station != "" {
+		fmt.Fprintf(&b, " (station %s)", d.Station)
+	}
+	b.WriteString(": ")
+	for i, e := range d.Extremes {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		kind := "low"
+		if e.High {
+			kind = "high"
+		}
+		fmt.Fprintf(&b, "%s tide at %s (%.2f m)", kind, e.Time.Format("15:04"), e.Height)
+	}
+	fmt.Fprintf(&b, ". Times are in %s", d.Extremes[0].Time.Location())
+	if d.Datum != "" {
+		fmt.Fprintf(&b, ", heights above %s", d.Datum)
+	}
+	return b.String() + "."
+}
+
+// Today validates the coordinates and returns their tides today with w.
+func Today(ctx context.Context, w *WorldTides, lat, lon float64) (*Day, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return nil, err
+	}
+	return w.Today(ctx, lat, lon)
+}
+
+// WorldTides is a client of the WorldTides API v3, see
+// https://www.worldtides.info/apidocs.
+type WorldTides struct {
+	Key        string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Today returns the high and low tides at lat,lon today, in the local time of
+// the location. It is ErrNotCoastal when WorldTides has no tidal data there.
+func (w *WorldTides) Today(ctx context.Context, lat, lon float64) (*Day, error) {
+	if w.Key == "" {
+		return nil, errors.New("WORLDTIDES_API_KEY is not set")
+	}
+
+	q := url.Values{}
+	q.Set("lat", fmt.Sprint(lat))
+	q.Set("lon", fmt.Sprint(lon))
+	q.Set("date", "today")
+	q.Set("days", "1")
+	q.Set("key", w.Key)
+	// the flags have no value
+	rawQuery := "extremes&localtime&" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.BaseURL+"/api/v3?"+rawQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		// the error text has the URL, and the key in it
+		return nil, httpx.Redact(err, "key")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		Status      int     `json:"status"`
+		Error       string  `json:"error"`
+		ResponseLat float64 `json:"responseLat"`
+		ResponseLon float64 `json:"responseLon"`
+		Station     string  `json:"station"`
+		Datum       string  `json:"responseDatum"`
+		Timezone    string  `json:"timezone"`
+		Extremes    []struct {
+			Dt     int64   `json:"dt"`
+			Height float64 `json:"height"`
+			Type   string  `json:"type"`
+		} `json:"extremes"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("WorldTides responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || strings.Contains(strings.ToLower(r.Error), "key"):
+		return nil, errors.New("the WorldTides API key is invalid")
+	case resp.StatusCode != http.StatusOK || r.Status != http.StatusOK:
+		if strings.Contains(strings.ToLower(r.Error), "no location") {
+			return nil, ErrNotCoastal
+		}
+		return nil, fmt.Errorf("WorldTides responded %d: %s", resp.StatusCode, r.Error)
+	case len(r.Extremes) == 0:
+		return nil, ErrNotCoastal
+	case geo.Distance(lat, lon, r.ResponseLat, r.ResponseLon) > maxOffsetKm:
+		return nil, ErrNotCoastal
+	}
+
+	loc := time.UTC
+	if r.Timezone != "" {
+		if l, err := time.LoadLocation(r.Timezone); err == nil {
+			loc = l
+		}
+	}
+	day := &Day{Latitude: lat, Longitude: lon, Station: r.Station, Datum: r.Datum}
+	for _, e := range r.Extremes {
+		day.Extremes = append(day.Extremes, Extreme{
+			Time:   time.Unix(e.Dt, 0).In(loc),
+			High:   strings.EqualFold(e.Type, "high"),
+			Height: e.Height,
+		})
+	}
+	return day, nil
+}
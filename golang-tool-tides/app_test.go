@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func worldTidesServer(t *testing.T, status int, body string) *WorldTides {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3" {
+			t.Errorf("path = %s, want /api/v3", r.URL.Path)
+		}
+		q := r.URL.Query()
+		for _, flag := range []string{"extremes", "localtime"} {
+			if _, ok := q[flag]; !ok {
+				t.Errorf("the query %s has no %s flag", r.URL.RawQuery, flag)
+			}
+		}
+		if q.Get("key") != "test-key" || q.Get("date") != "today" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return &WorldTides{Key: "test-key", BaseURL: srv.URL, HTTPClient: srv.Client()}
+}
+
+func TestToday(t *testing.T) {
+	// 2024-06-21 in Plymouth, BST
+	w := worldTidesServer(t, http.StatusOK, `{
+		"status": 200,
+		"responseLat": 50.35, "responseLon": -4.15,
+		"responseDatum": "LAT",
+		"station": "PLYMOUTH (DEVONPORT)",
+		"timezone": "Europe/London",
+		"extremes": [
+			{"dt": 1718942640, "height": 4.82, "type": "High"},
+			{"dt": 1718965200, "height": 0.93, "type": "Low"},
+			{"dt": 1718987940, "height": 5.01, "type": "High"}
+		]
+	}`)
+	day, err := Today(context.Background(), w, 50.37, -4.14)
+	if err != nil {
+		t.Fatalf("Today() error = %v", err)
+	}
+	want := "Tides today at 50.37,-4.14 (station PLYMOUTH (DEVONPORT)): high tide at 05:04 (4.82 m), low tide at 11:20 (0.93 m), high tide at 17:39 (5.01 m). Times are in Europe/London, heights above LAT."
+	if got := day.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
+
+func TestTodayNotCoastal(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"no data", http.StatusBadRequest, `{"status": 400, "error": "No location found"}`},
+		{"no extremes", http.StatusOK, `{"status": 200, "responseLat": 48.85, "responseLon": 2.35, "extremes": []}`},
+		// the nearest sea of Paris is the Channel
+		{"far away", http.StatusOK, `{"status": 200, "responseLat": 49.5, "responseLon": 0.1, "extremes": [{"dt": 1718942640, "height": 7.1, "type": "High"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := worldTidesServer(t, tt.status, tt.body)
+			if _, err := Today(context.Background(), w, 48.8566, 2.3522); !errors.Is(err, ErrNotCoastal) {
+				t.Errorf("Today() error = %v, want %v", err, ErrNotCoastal)
+			}
+		})
+	}
+}
+
+func TestTodayErrors(t *testing.T) {
+	w := worldTidesServer(t, http.StatusUnauthorized, `{"status": 401, "error": "Invalid key"}`)
+	if _, err := Today(context.Background(), w, 50.37, -4.14); err == nil || !strings.Contains(err.Error(), "key is invalid") {
+		t.Errorf("Today() error = %v, want an invalid key", err)
+	}
+
+	w = worldTidesServer(t, http.StatusInternalServerError, `oops`)
+	if _, err := Today(context.Background(), w, 50.37, -4.14); err == nil || errors.Is(err, ErrNotCoastal) {
+		t.Errorf("Today() error = %v, want an upstream error", err)
+	}
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	down := &WorldTides{Key: "secret-key", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	if _, err := Today(context.Background(), down, 50.37, -4.14); err == nil || strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("Today() of an unreachable API = %v, want an error without the key", err)
+	}
+
+	if _, err := Today(context.Background(), &WorldTides{}, 50.37, -4.14); err == nil || !strings.Contains(err.Error(), "WORLDTIDES_API_KEY") {
+		t.Errorf("Today() error = %v, want a missing key", err)
+	}
+
+	for _, c := range [][2]float64{{91, 0}, {0, -181}} {
+		if _, err := Today(context.Background(), &WorldTides{}, c[0], c[1]); err == nil || !strings.Contains(err.Error(), "between") {
+			t.Errorf("Today(%v) error = %v, want an invalid coordinate", c, err)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	_ "time/tzdata"
 
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
 	"github.com/yomorun/yomo/serverless"
 )
 
@@ -35,15 +37,21 @@ func InputSchema() any {
 	return &Parameter{}
 }
 
+func init() {
+	registry.Register(registry.Tool{Name: "timezone-calculator", Description: Description(), InputSchema: InputSchema()})
+}
+
 const timeFormat = "2006-01-02 15:04:05"
 
 // Handler orchestrates the core processing logic of this function.
-// - ctx.ReadLLMArguments() parses LLM Function Calling Arguments.
-// - ctx.WriteLLMResult() sends the retrieval result back to LLM.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
 func Handler(ctx serverless.Context) {
 	// parse the input data generated by llm tools_call
 	var msg Parameter
-	ctx.ReadLLMArguments(&msg)
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
 
 	slog.Info("parse arguments", "source", msg.SourceTimezone, "target", msg.TargetTimezone, "time", msg.TimeString)
 
@@ -59,11 +67,11 @@ func Handler(ctx serverless.Context) {
 	targetTime, err := ConvertTimezone(msg.TimeString, msg.SourceTimezone, msg.TargetTimezone)
 	if err != nil {
 		slog.Error("[sfn] ConvertTimezone error", "err", err)
-		ctx.WriteLLMResult("can not convert the time right now, please try later")
+		sfn.WriteText(ctx, "can not convert the time right now, please try later")
 		return
 	}
 
-	ctx.WriteLLMResult(fmt.Sprintf("This time in timezone %s is %s when %s in %s", msg.TargetTimezone, targetTime, msg.TimeString, msg.SourceTimezone))
+	sfn.WriteText(ctx, fmt.Sprintf("This time in timezone %s is %s when %s in %s", msg.TargetTimezone, targetTime, msg.TimeString, msg.SourceTimezone))
 }
 
 // ConvertTimezone converts the current time from the source timezone to the target timezone.
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Generate a secure random token, e.g. an API key, a password or an invitation code, from a preset alphabet or the characters the user gives. Always use this function instead of making up a token yourself. The function returns the token and its entropy in bits.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Length   int    `json:"length,omitempty" jsonschema:"description=The number of characters of the token. Defaults to 32,minimum=1,maximum=1024"`
+	Preset   string `json:"preset,omitempty" jsonschema:"description=The alphabet of the token when alphabet is not given. Defaults to alphanumeric,enum=alphanumeric,enum=hex,enum=base58,enum=base64url,enum=numeric"`
+	Alphabet string `json:"alphabet,omitempty" jsonschema:"description=The distinct characters to build the token from instead of a preset,example=ABCDEFGHJKLMNPQRSTUVWXYZ23456789"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "token", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE3}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// the token is a secret, it is never logged
+	slog.Info("[sfn] << receive", "length", msg.Length, "preset", msg.Preset, "alphabet", utf8.RuneCountInString(msg.Alphabet))
+
+	alphabet, err := Alphabet(msg.Preset, msg.Alphabet)
+	if err != nil {
+		slog.Warn("[sfn] Alphabet error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not generate the token: %v", err))
+		return
+	}
+	length := msg.Length
+	if length == 0 {
+		length = defaultLength
+	}
+	token, err := Generate(rand.Reader, alphabet, length)
+	if err != nil {
+		slog.Warn("[sfn] Generate error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not generate the token: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("Token of %d characters from a %d character alphabet, %.0f bits of entropy: %s",
+		length, len(alphabet), Entropy(len(alphabet), length), token))
+}
+
+const (
+	defaultLength = 32
+	maxLength     = 1024
+	// maxAlphabet is the most characters a random byte can pick from.
+	maxAlphabet = 256
+)
+
+var presets = map[string]string{
+	"alphanumeric": "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+	"hex":          "0123456789abcdef",
+	// base58 leaves out 0, O, I and l, which are easily confused
+	"base58":    "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz",
+	"base64url": "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_",
+	"numeric":   "0123456789",
+}
+
+// Alphabet returns the characters of the custom alphabet, or of the preset
+// when it is empty. The characters of a custom alphabet must be distinct, a
+// repeated one would be picked more often than the others.
+func Alphabet(preset, custom string) ([]rune, error) {
+	if custom == "" {
+		name := strings.ToLower(strings.TrimSpace(preset))
+		if name == "" {
+			name = "alphanumeric"
+		}
+		chars, ok := presets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown preset %q, use alphanumeric, hex, base58, base64url or numeric", preset)
+		}
+		return []rune(chars), nil
+	}
+
+	if !utf8.ValidString(custom) {
+		return nil, errors.New("the alphabet is not valid UTF-8")
+	}
+	alphabet := []rune(custom)
+	if len(alphabet) < 2 {
+		return nil, errors.New("the alphabet needs at least 2 characters")
+	}
+	if len(alphabet) > maxAlphabet {
+		return nil, fmt.Errorf("the alphabet has %d characters, at most %d are supported", len(alphabet), maxAlphabet)
+	}
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if seen[r] {
+			return nil, fmt.Errorf("the character %q is repeated in the alphabet", r)
+		}
+		seen[r] = true
+	}
+	return alphabet, nil
+}
+
+// Generate returns length characters of alphabet picked uniformly with the
+// random bytes of random, crypto/rand.Reader outside of the tests.
+//
+// A byte modulo the size n of the alphabet would favor the first 256 % n
+// characters, so the bytes from the largest multiple of n up are rejected
+// and drawn again.
+func Generate(random io.Reader, alphabet []rune, length int) (string, error) {
+	if len(alphabet) < 2 || len(alphabet) > maxAlphabet {
+		return "", fmt.Errorf("the alphabet must have 2 to %d characters", maxAlphabet)
+	}
+	if length < 1 || length > maxLength {
+		return "", fmt.Errorf("the length must be between 1 and %d, not %d", maxLength, length)
+	}
+
+	n := len(alphabet)
+	limit := maxAlphabet - maxAlphabet%n
+	token := make([]rune, 0, length)
+	buf := make([]byte, length)
+	for len(token) < length {
+		if _, err := io.ReadFull(random, buf[:length-len(token)]); err != nil {
+			return "", fmt.Errorf("read random bytes: %w", err)
+		}
+		for _, b := range buf[:length-len(token)] {
+			if int(b) < limit {
+				token = append(token, alphabet[int(b)%n])
+			}
+		}
+	}
+	return string(token), nil
+}
+
+// Entropy returns the bits of entropy of a token of length characters picked
+// uniformly from size characters.
+func Entropy(size, length int) float64 {
+	return float64(length) * math.Log2(float64(size))
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, tt := range []struct {
+		preset, custom string
+		length         int
+	}{
+		{"", "", 32},
+		{"hex", "", 64},
+		{"base58", "", 1},
+		{"Base64URL", "", 1024},
+		{"numeric", "", 6},
+		{"", "ÄÖÜ✓", 20},
+	} {
+		alphabet, err := Alphabet(tt.preset, tt.custom)
+		if err != nil {
+			t.Fatalf("Alphabet(%q, %q) error = %v", tt.preset, tt.custom, err)
+		}
+		token, err := Generate(rand.Reader, alphabet, tt.length)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if got := utf8.RuneCountInString(token); got != tt.length {
+			t.Errorf("Generate(%q) has %d characters, want %d", tt.preset+tt.custom, got, tt.length)
+		}
+		for _, r := range token {
+			if !strings.ContainsRune(string(alphabet), r) {
+				t.Errorf("Generate(%q) = %q, %q is not in the alphabet", tt.preset+tt.custom, token, r)
+			}
+		}
+	}
+}
+
+func TestGenerateRejectsBiasedBytes(t *testing.T) {
+	// with 10 characters the bytes from 250 up would favor 0 to 5
+	random := bytes.NewReader([]byte{255, 250, 3, 249, 251, 10, 0})
+	token, err := Generate(random, []rune("0123456789"), 4)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if token != "3900" {
+		t.Errorf("Generate() = %q, want %q", token, "3900")
+	}
+
+	// running out of random bytes is an error, not a short token
+	if _, err := Generate(bytes.NewReader([]byte{1, 255}), []rune("0123456789"), 2); err == nil {
+		t.Error("Generate() should fail when the random bytes run out")
+	}
+}
+
+func TestGenerateUniform(t *testing.T) {
+	// 256 % 62 = 8: a plain modulo would pick the first 8 characters 25% more
+	// often than the others
+	alphabet := []rune(presets["alphanumeric"])
+	const samples = 620000
+	counts := make(map[rune]int, len(alphabet))
+	for i := 0; i < samples/1000; i++ {
+		token, err := Generate(rand.Reader, alphabet, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range token {
+			counts[r]++
+		}
+	}
+
+	expected := float64(samples) / float64(len(alphabet))
+	var chi2 float64
+	for _, r := range alphabet {
+		d := float64(counts[r]) - expected
+		chi2 += d * d / expected
+	}
+	// the critical value of 61 degrees of freedom at p = 1e-6 is about 130,
+	// the modulo bias would be in the thousands
+	if chi2 > 130 {
+		t.Errorf("the characters are not uniform, chi-square = %.1f: %v", chi2, counts)
+	}
+}
+
+func TestAlphabetInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		preset, custom string
+	}{
+		{"emoji", ""},
+		{"", "a"},
+		{"", "abca"},
+		{"", "\xff\xfe"},
+		{"", strings.Repeat("x", 300)},
+	} {
+		if _, err := Alphabet(tt.preset, tt.custom); err == nil {
+			t.Errorf("Alphabet(%q, %q) should fail", tt.preset, tt.custom)
+		}
+	}
+
+	for _, length := range []int{-1, 0, 1025} {
+		if _, err := Generate(rand.Reader, []rune("ab"), length); err == nil {
+			t.Errorf("Generate(%d) should fail", length)
+		}
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	if got := Entropy(16, 32); got != 128 {
+		t.Errorf("Entropy(16, 32) = %v, want 128", got)
+	}
+}
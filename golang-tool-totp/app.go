@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Generate the current 6 digit time-based one-time password (TOTP) of an authenticator app secret, as used for two factor authentication. The secret is the base32 key shown when the authenticator was set up, e.g. "JBSWY3DPEHPK3PXP". The function returns the code and how many seconds it remains valid.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Secret string `json:"secret" jsonschema:"description=The base32 encoded TOTP secret,example=JBSWY3DPEHPK3PXP"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "totp", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xC1}
+}
+
+const (
+	// period is the time step of the code, 30 seconds is the default of
+	// RFC 6238 and of every authenticator app.
+	period = 30 * time.Second
+	// digits is the length of the code.
+	digits = 6
+)
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// the secret is a credential and is not logged
+	slog.Info("[sfn] << receive", "secret_length", len(msg.Secret))
+
+	key, err := DecodeSecret(msg.Secret)
+	if err != nil {
+		slog.Warn("[sfn] DecodeSecret error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not generate the code: %v", err))
+		return
+	}
+
+	now := time.Now()
+	sfn.WriteText(ctx, fmt.Sprintf("The current code is %s, it is valid for %d more seconds", Code(key, now, digits), Remaining(now)/time.Second))
+}
+
+// DecodeSecret decodes a base32 secret. Spaces and hyphens, which apps insert
+// to group the secret for reading, and the padding are optional, and the case
+// is ignored.
+func DecodeSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(secret))
+	if s == "" {
+		return nil, errors.New("the secret is empty")
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("the secret is not valid base32: %w", err)
+	}
+	return key, nil
+}
+
+// Code returns the TOTP of key at t with HMAC-SHA1, see RFC 6238 and the
+// dynamic truncation of RFC 4226 section 5.3.
+func Code(key []byte, t time.Time, digits int) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(period/time.Second)))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+// Remaining returns how long the code at t stays valid.
+func Remaining(t time.Time) time.Duration {
+	return period - time.Duration(t.Unix()%int64(period/time.Second))*time.Second
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890"
+// in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	key, err := DecodeSecret(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 6238 appendix B, SHA1
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		at := time.Unix(tt.unix, 0)
+		if got := Code(key, at, 8); got != tt.want {
+			t.Errorf("Code(%d, 8) = %s, want %s", tt.unix, got, tt.want)
+		}
+		if got := Code(key, at, digits); got != tt.want[2:] {
+			t.Errorf("Code(%d, 6) = %s, want %s", tt.unix, got, tt.want[2:])
+		}
+	}
+}
+
+func TestDecodeSecret(t *testing.T) {
+	for _, secret := range []string{rfcSecret, "gezd gnbv gy3t qojq gezd gnbv gy3t qojq", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ===="} {
+		key, err := DecodeSecret(secret)
+		if err != nil || string(key) != "12345678901234567890" {
+			t.Errorf("DecodeSecret(%q) = %q, %v", secret, key, err)
+		}
+	}
+	for _, secret := range []string{"", "   ", "GEZDGNBV1", "not base32!"} {
+		if _, err := DecodeSecret(secret); err == nil {
+			t.Errorf("DecodeSecret(%q) should fail", secret)
+		}
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want time.Duration
+	}{
+		{0, 30 * time.Second},
+		{59, time.Second},
+		{1111111111, 29 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := Remaining(time.Unix(tt.unix, 0)); got != tt.want {
+			t.Errorf("Remaining(%d) = %v, want %v", tt.unix, got, tt.want)
+		}
+	}
+}
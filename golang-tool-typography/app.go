@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert a CSS length between the typographic units px, pt, em and rem, e.g. 12pt is 16px and 24px is 1.5rem with a 16px root font size. Converting from or to em or rem needs the base font size in px: the font size of the parent element for em, of the root element for rem, 16px by default in browsers. Always use this function instead of converting typographic units yourself.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value      float64  `json:"value" jsonschema:"description=The length to convert"`
+	From       string   `json:"from" jsonschema:"description=The unit of the length,enum=px,enum=pt,enum=em,enum=rem"`
+	To         string   `json:"to" jsonschema:"description=The unit to convert the length to,enum=px,enum=pt,enum=em,enum=rem"`
+	BaseFontPx *float64 `json:"baseFontPx,omitempty" jsonschema:"description=The base font size in px that em and rem are relative to. Required when converting from or to em or rem,exclusiveMinimum=0,example=16"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "typography", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xFC}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "value", msg.Value, "from", msg.From, "to", msg.To)
+
+	converted, err := Convert(msg.Value, msg.From, msg.To, msg.BaseFontPx)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g %s to %s: %v", msg.Value, msg.From, msg.To, err))
+		return
+	}
+
+	from, _ := lookupUnit(msg.From)
+	to, _ := lookupUnit(msg.To)
+	result := fmt.Sprintf("%s is %s", from.format(msg.Value), to.format(converted))
+	if from.relative || to.relative {
+		result += fmt.Sprintf(" with a base font size of %spx", number(*msg.BaseFontPx))
+	}
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+type unit struct {
+	// px is the size of the unit in CSS pixels, 0 for the units relative to
+	// the base font size.
+	px       float64
+	relative bool
+	symbol   string
+}
+
+// number writes a length with up to 4 decimals and no trailing zeros,
+// e.g. "13.3333".
+func number(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+func (u unit) format(v float64) string {
+	return number(v) + u.symbol
+}
+
+// units are the CSS units, a CSS inch being 96px and 72pt.
+var units = map[string]unit{
+	"px":  {px: 1, symbol: "px"},
+	"pt":  {px: 96.0 / 72, symbol: "pt"},
+	"em":  {relative: true, symbol: "em"},
+	"rem": {relative: true, symbol: "rem"},
+}
+
+// aliases maps other common spellings to the keys of units.
+var aliases = map[string]string{
+	"pixel":  "px",
+	"pixels": "px",
+	"point":  "pt",
+	"points": "pt",
+	"ems":    "em",
+	"rems":   "rem",
+}
+
+func lookupUnit(name string) (unit, bool) {
+	key := strings.ToLower(strings.Join(strings.Fields(name), ""))
+	if alias, ok := aliases[key]; ok {
+		key = alias
+	}
+	u, ok := units[key]
+	return u, ok
+}
+
+// ErrNeedsBase is returned when converting from or to em or rem without a
+// base font size.
+var ErrNeedsBase = errors.New("em and rem are relative to a font size, give the base font size in px, e.g. 16")
+
+// Convert converts a length from one unit to another, em and rem being
+// multiples of basePx.
+func Convert(value float64, from, to string, basePx *float64) (float64, error) {
+	f, ok := lookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q, use px, pt, em or rem", from)
+	}
+	t, ok := lookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q, use px, pt, em or rem", to)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%v is not a length", value)
+	}
+
+	if f.relative || t.relative {
+		if basePx == nil {
+			return 0, ErrNeedsBase
+		}
+		if b := *basePx; math.IsNaN(b) || math.IsInf(b, 0) || b <= 0 {
+			return 0, fmt.Errorf("the base font size must be a positive number of px, got %v", b)
+		}
+		f, t = f.resolve(*basePx), t.resolve(*basePx)
+	}
+	return value * f.px / t.px, nil
+}
+
+// resolve returns u with the size of a relative unit set to basePx.
+func (u unit) resolve(basePx float64) unit {
+	if u.relative {
+		u.px = basePx
+	}
+	return u
+}
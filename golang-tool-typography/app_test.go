@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		base     *float64
+		want     float64
+	}{
+		{16, "px", "pt", nil, 12},
+		{12, "pt", "px", nil, 16},
+		{10, "pt", "px", nil, 13.333333333},
+		{72, "points", "PX", nil, 96},
+		{16, "px", "px", nil, 16},
+		{24, "px", "rem", ptr(16), 1.5},
+		{1.5, "rem", "px", ptr(16), 24},
+		{2, "em", "px", ptr(14), 28},
+		{1, "em", "pt", ptr(16), 12},
+		{18, "pt", "rem", ptr(16), 1.5},
+		{0.875, "rem", "em", ptr(16), 0.875},
+		{-8, "px", "em", ptr(20), -0.4},
+		// the base is not needed between absolute units
+		{16, "px", "pt", ptr(10), 12},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to, tt.base)
+		if err != nil {
+			t.Errorf("Convert(%v, %s, %s) error = %v", tt.value, tt.from, tt.to, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	for _, tt := range []struct {
+		from, to string
+		base     *float64
+	}{
+		{"px", "rem", nil},
+		{"em", "pt", nil},
+	} {
+		if _, err := Convert(1, tt.from, tt.to, tt.base); !errors.Is(err, ErrNeedsBase) {
+			t.Errorf("Convert(%s, %s) without a base error = %v, want ErrNeedsBase", tt.from, tt.to, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		value    float64
+		from, to string
+		base     *float64
+	}{
+		{1, "px", "em", ptr(0)},
+		{1, "px", "em", ptr(-16)},
+		{1, "px", "em", ptr(math.NaN())},
+		{1, "px", "vw", nil},
+		{1, "inch", "px", nil},
+		{math.Inf(1), "px", "pt", nil},
+	} {
+		if _, err := Convert(tt.value, tt.from, tt.to, tt.base); err == nil || errors.Is(err, ErrNeedsBase) {
+			t.Errorf("Convert(%v, %s, %s) error = %v", tt.value, tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got, _ := Convert(10, "pt", "px", nil)
+	if s := units["px"].format(got); s != "13.3333px" {
+		t.Errorf("format() = %s, want 13.3333px", s)
+	}
+	if s := units["rem"].format(1.5); s != "1.5rem" {
+		t.Errorf("format() = %s, want 1.5rem", s)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Check if a website or an HTTP endpoint is up. The function requests the URL and returns its HTTP status and response time. Use the method HEAD when only the status matters, if the server does not allow HEAD the function retries with GET.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	URL    string `json:"url" jsonschema:"description=The http or https URL to check"`
+	Method string `json:"method,omitempty" jsonschema:"description=The HTTP method of the check,enum=GET,enum=HEAD"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "url-ping", Description: Description(), InputSchema: InputSchema()})
+}
+
+// client only connects to public addresses, and only requests the hosts of
+// TOOL_ALLOWED_HOSTS when it is set.
+var client = netguard.NewClient(10 * time.Second)
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "url", msg.URL, "method", msg.Method)
+
+	result, err := Ping(client, msg.URL, msg.Method)
+	if err != nil {
+		slog.Warn("[sfn] Ping error", "url", msg.URL, "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("%s is not reachable: %v", msg.URL, err))
+		return
+	}
+
+	sfn.WriteText(ctx, result.String())
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	URL        string
+	Method     string
+	StatusCode int
+	Latency    time.Duration
+	// FellBack is set when a HEAD request was rejected and the check was
+	// retried with GET.
+	FellBack bool
+}
+
+func (r Result) String() string {
+	s := fmt.Sprintf("%s responded %d %s to %s in %dms", r.URL, r.StatusCode, http.StatusText(r.StatusCode), r.Method, r.Latency.Milliseconds())
+	if r.FellBack {
+		s += ", the server does not allow HEAD so GET was used"
+	}
+	return s
+}
+
+// Ping requests rawURL with method, GET by default. A HEAD request answered
+// with 405 Method Not Allowed is retried with GET.
+func Ping(client *http.Client, rawURL, method string) (Result, error) {
+	u, err := netguard.CheckURL(rawURL)
+	if err != nil {
+		return Result{}, err
+	}
+
+	method = strings.ToUpper(strings.TrimSpace(method))
+	switch method {
+	case "":
+		method = http.MethodGet
+	case http.MethodGet, http.MethodHead:
+	default:
+		return Result{}, fmt.Errorf("unsupported method %q, use GET or HEAD", method)
+	}
+
+	result, err := request(client, u.String(), method)
+	if err != nil {
+		return Result{}, err
+	}
+	if method == http.MethodHead && result.StatusCode == http.StatusMethodNotAllowed {
+		result, err = request(client, u.String(), http.MethodGet)
+		if err != nil {
+			return Result{}, err
+		}
+		result.FellBack = true
+	}
+	return result, nil
+}
+
+func request(client *http.Client, target, method string) (Result, error) {
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return Result{}, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	latency := time.Since(start)
+	// the status is all that is needed, drain a little of the body so the
+	// connection can be reused
+	io.CopyN(io.Discard, resp.Body, 64*1024)
+	resp.Body.Close()
+
+	return Result{URL: target, Method: method, StatusCode: resp.StatusCode, Latency: latency}, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/netguard"
+)
+
+func TestPing(t *testing.T) {
+	// the server rejects HEAD on /no-head and records the methods it got
+	var (
+		mu      sync.Mutex
+		methods []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+
+		if r.URL.Path == "/no-head" && r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		method       string
+		wantMethod   string
+		wantStatus   int
+		wantFellBack bool
+		wantRequests []string
+	}{
+		{name: "default get", path: "/", wantMethod: "GET", wantStatus: 200, wantRequests: []string{"GET"}},
+		{name: "head", path: "/", method: "head", wantMethod: "HEAD", wantStatus: 200, wantRequests: []string{"HEAD"}},
+		{name: "head falls back to get", path: "/no-head", method: "HEAD", wantMethod: "GET", wantStatus: 200, wantFellBack: true, wantRequests: []string{"HEAD", "GET"}},
+		{name: "get is not retried", path: "/missing", method: "GET", wantMethod: "GET", wantStatus: 404, wantRequests: []string{"GET"}},
+		{name: "head without 405 is not retried", path: "/missing", method: "HEAD", wantMethod: "HEAD", wantStatus: 404, wantRequests: []string{"HEAD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			methods = nil
+
+			got, err := Ping(srv.Client(), srv.URL+tt.path, tt.method)
+			if err != nil {
+				t.Fatalf("Ping() error = %v", err)
+			}
+			if got.Method != tt.wantMethod || got.StatusCode != tt.wantStatus || got.FellBack != tt.wantFellBack {
+				t.Errorf("Ping() = %+v, want method %s status %d fell back %v", got, tt.wantMethod, tt.wantStatus, tt.wantFellBack)
+			}
+			if !reflect.DeepEqual(methods, tt.wantRequests) {
+				t.Errorf("server got %v, want %v", methods, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestPingInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		method string
+	}{
+		{"ftp scheme", "ftp://example.com", ""},
+		{"relative url", "example.com", ""},
+		{"unsupported method", "https://example.com", "DELETE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Ping(http.DefaultClient, tt.url, tt.method); err == nil {
+				t.Errorf("Ping(%q, %q) should fail", tt.url, tt.method)
+			}
+		})
+	}
+}
+
+func TestPingBlocksInternal(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the client of the Handler reached a loopback server")
+	}))
+	defer srv.Close()
+
+	for _, target := range []string{
+		srv.URL,
+		"http://localhost:" + strconv.Itoa(srv.Listener.Addr().(*net.TCPAddr).Port),
+		"http://10.0.0.1/",
+		"http://169.254.169.254/latest/meta-data/",
+	} {
+		if _, err := Ping(client, target, "HEAD"); !errors.Is(err, netguard.ErrBlockedAddress) {
+			t.Errorf("Ping(%q) error = %v, want %v", target, err, netguard.ErrBlockedAddress)
+		}
+	}
+}
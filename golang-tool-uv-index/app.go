@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the current UV index at a location with its risk level and the sun protection advice, e.g. "do I need sunscreen in Sydney right now?". If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns the UV index, the risk from low to extreme and what to do about it.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "uv-index", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xE9}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := UVIndex(reqCtx, msg.Latitude, msg.Longitude)
+	if err != nil {
+		slog.Warn("[sfn] UVIndex error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the UV index: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// UVIndex validates the coordinates and describes their current UV index.
+func UVIndex(ctx context.Context, lat, lon float64) (string, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return "", err
+	}
+	uvi, err := client.UVIndex(ctx, lat, lon)
+	if err != nil {
+		return "", err
+	}
+	risk, advice := weather.UVRisk(uvi)
+	return fmt.Sprintf("The UV index at %v,%v is %.1f, %s risk: %s.", lat, lon, uvi, risk, advice), nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestUVIndex(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/3.0/onecall" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"lat":-33.8688,"lon":151.2093,"current":{"dt":1723024800,"uvi":8.31}}`)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := UVIndex(context.Background(), -33.8688, 151.2093)
+	if err != nil {
+		t.Fatalf("UVIndex() error = %v", err)
+	}
+	want := "The UV index at -33.8688,151.2093 is 8.3, very high risk: avoid the sun between 11 AM and 3 PM, seek shade and wear protective clothing, a hat, sunglasses and SPF 50+ sunscreen."
+	if got != want {
+		t.Errorf("UVIndex() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := UVIndex(context.Background(), -91, 0); err == nil {
+		t.Error("UVIndex() of an invalid latitude should fail")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/schema"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Validate the arguments of a function call against the JSON schema of the function before calling it. Give the name of the function and its arguments as the JSON text of the call. The function returns "valid", or the list of the problems of the arguments, e.g. a missing required parameter or a number out of range, to fix before calling the function.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Name string `json:"name" jsonschema:"description=The name of the function to call,example=get-weather"`
+	Args string `json:"args" jsonschema:"description=The arguments of the call as a JSON object in a string"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "validate-args", Description: Description(), InputSchema: InputSchema()})
+	// the other functions run in processes of their own, their schemas come
+	// from the manifest of the deployment
+	if err := registry.LoadManifest(); err != nil {
+		slog.Warn("[sfn] LoadManifest error", "err", err)
+	}
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x110}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// the arguments can hold anything the user said, only their size is
+	// logged
+	slog.Info("[sfn] << receive", "name", msg.Name, "args_bytes", len(msg.Args))
+
+	result, err := ValidateArgs(registry.Default, msg.Name, msg.Args)
+	if err != nil {
+		slog.Warn("[sfn] ValidateArgs error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not validate the arguments: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// ValidateArgs validates the JSON args against the schema of the function
+// registered in r with name. It returns "valid", or the problems of args one
+// per line.
+func ValidateArgs(r *registry.Registry, name, args string) (string, error) {
+	t, ok := r.Lookup(strings.TrimSpace(name))
+	if !ok {
+		return "", fmt.Errorf("%q: no such function is registered", name)
+	}
+	params, err := schema.Reflect(t.InputSchema)
+	if err != nil {
+		return "", fmt.Errorf("the schema of %s: %w", t.Name, err)
+	}
+
+	problems := params.Validate([]byte(args))
+	if len(problems) == 0 {
+		return "valid", nil
+	}
+	noun := "problems"
+	if len(problems) == 1 {
+		noun = "problem"
+	}
+	return fmt.Sprintf("invalid, %d %s with the arguments of %s:\n- %s", len(problems), noun, t.Name, strings.Join(problems, "\n- ")), nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+)
+
+// weatherParameter mirrors the Parameter of golang-tool-get-weather.
+type weatherParameter struct {
+	City      string  `json:"city" jsonschema:"description=The city name to get the weather for"`
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the city in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the city in decimal format,minimum=-180,maximum=180"`
+}
+
+func weatherRegistry() *registry.Registry {
+	r := registry.New()
+	r.Register(registry.Tool{Name: "get-weather", Description: "Get the weather", InputSchema: &weatherParameter{}})
+	r.Register(registry.Tool{Name: "get-utc-time", Description: "Get the UTC time"})
+	return r
+}
+
+func TestValidateArgs(t *testing.T) {
+	r := weatherRegistry()
+	tests := []struct {
+		name string
+		tool string
+		args string
+		want string
+	}{
+		{"valid", "get-weather", `{"city":"Paris","latitude":48.8566,"longitude":2.3522}`, "valid"},
+		{"missing required", "get-weather", `{"city":"Paris","latitude":48.8566}`, "invalid, 1 problem with the arguments of get-weather:\n- longitude: is required"},
+		{"out of range", "get-weather", `{"city":"Paris","latitude":148.8566,"longitude":2.3522}`, "invalid, 1 problem with the arguments of get-weather:\n- latitude: 148.8566 is more than the maximum 90"},
+		{"several", "get-weather", `{"latitude":"north","longitude":-200}`, "invalid, 3 problems with the arguments of get-weather:\n- city: is required\n- latitude: must be a number, not a string\n- longitude: -200 is less than the minimum -180"},
+		{"no parameters", " get-utc-time ", "{}", "valid"},
+		{"unexpected parameter", "get-utc-time", `{"zone":"UTC"}`, "invalid, 1 problem with the arguments of get-utc-time:\n- zone: is not a parameter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateArgs(r, tt.tool, tt.args)
+			if err != nil {
+				t.Fatalf("ValidateArgs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateArgs() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateArgsFromManifest(t *testing.T) {
+	// the schemas of a manifest are the JSON the LLM was given
+	manifest, err := weatherRegistry().MarshalOpenAI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := registry.New()
+	if err := r.LoadOpenAI(manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ValidateArgs(r, "get-weather", `{"city":"Paris","latitude":148.8566}`)
+	if err != nil {
+		t.Fatalf("ValidateArgs() error = %v", err)
+	}
+	if want := "invalid, 2 problems with the arguments of get-weather:\n- longitude: is required\n- latitude: 148.8566 is more than the maximum 90"; got != want {
+		t.Errorf("ValidateArgs() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestValidateArgsUnknownTool(t *testing.T) {
+	_, err := ValidateArgs(weatherRegistry(), "get-wether", `{}`)
+	if err == nil || !strings.Contains(err.Error(), "no such function is registered") {
+		t.Errorf("ValidateArgs() of an unknown function error = %v", err)
+	}
+}
+
+func TestValidateArgsItself(t *testing.T) {
+	// the function is registered in the default registry of its process
+	got, err := ValidateArgs(registry.Default, "validate-args", `{"name":"get-weather"}`)
+	if err != nil {
+		t.Fatalf("ValidateArgs() error = %v", err)
+	}
+	if want := "invalid, 1 problem with the arguments of validate-args:\n- args: is required"; got != want {
+		t.Errorf("ValidateArgs() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/contentline"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Create a contact card in vCard 3.0 format (.vcf) that the user can import into a phone or an address book. Give at least a name, a phone number, an email address or an organization, the fields not given are left out. The function returns the content of the .vcf file.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Name  string `json:"name,omitempty" jsonschema:"description=The full name of the person e.g. Jane Doe"`
+	Phone string `json:"phone,omitempty" jsonschema:"description=The phone number preferably in international format e.g. +1 650 253 0000"`
+	Email string `json:"email,omitempty" jsonschema:"description=The email address"`
+	Org   string `json:"org,omitempty" jsonschema:"description=The organization or company"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "vcard", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD7}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	// the contact details are personal data, they are not logged
+	slog.Info("[sfn] << receive", "has_name", msg.Name != "", "has_phone", msg.Phone != "", "has_email", msg.Email != "", "has_org", msg.Org != "")
+
+	card, err := VCard(msg)
+	if err != nil {
+		slog.Warn("[sfn] VCard error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not create the contact card: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, card)
+}
+
+// maxFieldLength bounds every field in characters.
+const maxFieldLength = 256
+
+// VCard returns a vCard 3.0 (RFC 2426) with the given fields. FN and N are
+// mandatory: without a name, FN is the organization, the email address or
+// the phone number, and N is empty.
+func VCard(p Parameter) (string, error) {
+	name := strings.Join(strings.Fields(p.Name), " ")
+	phone := strings.TrimSpace(p.Phone)
+	email := strings.TrimSpace(p.Email)
+	org := strings.TrimSpace(p.Org)
+	if name == "" && phone == "" && email == "" && org == "" {
+		return "", errors.New("give at least a name, a phone number, an email address or an organization")
+	}
+	for _, field := range []string{name, phone, email, org} {
+		if utf8.RuneCountInString(field) > maxFieldLength {
+			return "", fmt.Errorf("the fields are limited to %d characters", maxFieldLength)
+		}
+	}
+	if phone != "" && !validPhone(phone) {
+		return "", fmt.Errorf("%q is not a phone number", phone)
+	}
+	if email != "" && (strings.Count(email, "@") != 1 || strings.HasPrefix(email, "@") || strings.HasSuffix(email, "@") || strings.ContainsAny(email, " ,;<>")) {
+		return "", fmt.Errorf("%q is not an email address", email)
+	}
+
+	fn := name
+	for _, fallback := range []string{org, email, phone} {
+		if fn == "" {
+			fn = fallback
+		}
+	}
+
+	var w contentline.Writer
+	w.Line("BEGIN:VCARD")
+	w.Line("VERSION:3.0")
+	w.Line("FN:" + contentline.Escape(fn))
+	w.Line("N:" + structuredName(name))
+	if org != "" {
+		w.Line("ORG:" + contentline.Escape(org))
+	}
+	if phone != "" {
+		w.Line("TEL;TYPE=VOICE:" + contentline.Escape(phone))
+	}
+	if email != "" {
+		w.Line("EMAIL;TYPE=INTERNET:" + contentline.Escape(email))
+	}
+	w.Line("END:VCARD")
+	return w.String(), nil
+}
+
+// structuredName is the N value "family;given;additional;prefix;suffix",
+// taking the last word of name as the family name.
+func structuredName(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ";;;;"
+	}
+	if len(words) == 1 {
+		return ";" + contentline.Escape(words[0]) + ";;;"
+	}
+	family := words[len(words)-1]
+	given := words[0]
+	additional := strings.Join(words[1:len(words)-1], " ")
+	return contentline.Escape(family) + ";" + contentline.Escape(given) + ";" + contentline.Escape(additional) + ";;"
+}
+
+// validPhone accepts digits with the usual separators and a leading +.
+func validPhone(phone string) bool {
+	digits := 0
+	for i, r := range phone {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" -.()/", r):
+		default:
+			return false
+		}
+	}
+	return digits >= 3 && digits <= 15
+}
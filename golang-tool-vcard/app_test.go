@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVCard(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Parameter
+		want []string
+	}{
+		{
+			name: "all fields",
+			p:    Parameter{Name: " Jane  Q. Doe ", Phone: "+1 (650) 253-0000", Email: "jane@example.com", Org: "Acme, Inc; R&D"},
+			want: []string{
+				"BEGIN:VCARD",
+				"VERSION:3.0",
+				"FN:Jane Q. Doe",
+				"N:Doe;Jane;Q.;;",
+				`ORG:Acme\, Inc\; R&D`,
+				"TEL;TYPE=VOICE:+1 (650) 253-0000",
+				"EMAIL;TYPE=INTERNET:jane@example.com",
+				"END:VCARD",
+			},
+		},
+		{
+			name: "name only",
+			p:    Parameter{Name: "Cher"},
+			want: []string{"BEGIN:VCARD", "VERSION:3.0", "FN:Cher", "N:;Cher;;;", "END:VCARD"},
+		},
+		{
+			name: "organization only",
+			p:    Parameter{Org: "Acme"},
+			want: []string{"BEGIN:VCARD", "VERSION:3.0", "FN:Acme", "N:;;;;", "ORG:Acme", "END:VCARD"},
+		},
+		{
+			name: "email only",
+			p:    Parameter{Email: "support@example.com"},
+			want: []string{"BEGIN:VCARD", "VERSION:3.0", "FN:support@example.com", "N:;;;;", "EMAIL;TYPE=INTERNET:support@example.com", "END:VCARD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VCard(tt.p)
+			if err != nil {
+				t.Fatalf("VCard() error = %v", err)
+			}
+			want := strings.Join(tt.want, "\r\n") + "\r\n"
+			if got != want {
+				t.Errorf("VCard() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestVCardInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Parameter
+	}{
+		{"no field", Parameter{Name: "  "}},
+		{"letters in the phone", Parameter{Name: "Jane", Phone: "call me"}},
+		{"too few digits", Parameter{Name: "Jane", Phone: "12"}},
+		{"plus in the middle", Parameter{Name: "Jane", Phone: "1+2345"}},
+		{"no at sign", Parameter{Name: "Jane", Email: "jane.example.com"}},
+		{"two emails", Parameter{Name: "Jane", Email: "a@example.com, b@example.com"}},
+		{"too long", Parameter{Name: strings.Repeat("a", maxFieldLength+1)}},
+	}
+	for _, tt := range tests {
+		if _, err := VCard(tt.p); err == nil {
+			t.Errorf("%s: VCard() should fail", tt.name)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Recommend whether to water a garden today at a location and how much, from the rain of the past 3 days, the rain forecast for today and tomorrow and today's temperature, e.g. "should I water my garden in Lyon today?". If the place name is given, convert it to Latitude and Longitude geo coordinates in decimal format. The function returns the recommendation with the amount of water in liters per square meter and its reasons.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the garden in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the garden in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "watering", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF9}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := Watering(reqCtx, msg.Latitude, msg.Longitude)
+	if err != nil {
+		slog.Warn("[sfn] Watering error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not recommend the watering: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// lookbackDays is how many past days of rain are taken into account, about
+// how long the soil of a garden bed holds water.
+const lookbackDays = 3
+
+// Watering fetches the daily forecast and the rain of the past days at the
+// coordinates, and recommends the watering of today.
+func Watering(ctx context.Context, lat, lon float64) (string, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return "", err
+	}
+
+	days, err := client.Daily(ctx, lat, lon)
+	if err != nil {
+		return "", err
+	}
+	today := days[0]
+
+	var (
+		wg     sync.WaitGroup
+		rain   [lookbackDays]float64
+		errs   [lookbackDays]error
+		recent float64
+	)
+	for i := range rain {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rain[i], errs[i] = client.Precipitation(ctx, lat, lon, today.Date.AddDate(0, 0, -1-i))
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return "", fmt.Errorf("the rain of %s: %w", today.Date.AddDate(0, 0, -1-i).Format("2006-01-02"), err)
+		}
+		recent += rain[i]
+	}
+
+	in := Inputs{RecentRain: recent, TempMax: today.TempMax}
+	for _, d := range days[:min(2, len(days))] {
+		in.ForecastRain += (d.Rain + d.Snow) * d.PrecipitationChance
+	}
+	return fmt.Sprintf("%v,%v on %s: %s", lat, lon, today.Date.Format("2006-01-02"), Recommend(in)), nil
+}
+
+// Inputs are the precipitation and temperature a watering depends on.
+type Inputs struct {
+	// RecentRain is the rain in mm of the past lookbackDays days.
+	RecentRain float64
+	// ForecastRain is the expected rain in mm of today and tomorrow, the
+	// forecast amounts weighted by their chance.
+	ForecastRain float64
+	// TempMax is the maximum temperature of today in °C.
+	TempMax float64
+}
+
+// Recommendation is whether and how much to water a garden today.
+type Recommendation struct {
+	Water bool
+	// Amount is the water to give in mm, i.e. liters per square meter.
+	Amount float64
+	Reason string
+}
+
+func (r Recommendation) String() string {
+	if !r.Water {
+		return "no need to water today, " + r.Reason
+	}
+	return fmt.Sprintf("water the garden today with about %.0f liters per square meter, %s", r.Amount, r.Reason)
+}
+
+// minWatering is the least amount worth watering, less only wets the
+// surface.
+const minWatering = 2.0
+
+// dailyNeed is the water in mm a garden loses a day at a maximum
+// temperature, a rough evapotranspiration of about 25 mm a week in a
+// temperate summer.
+func dailyNeed(tempMax float64) float64 {
+	switch {
+	case tempMax < 15:
+		return 2
+	case tempMax < 25:
+		return 3.5
+	case tempMax < 30:
+		return 4.5
+	}
+	return 6
+}
+
+// Recommend weighs the need of a garden over the past lookbackDays against
+// the rain that fell and the rain expected by tomorrow: the garden is
+// watered with the deficit the forecast rain does not cover, and not at all
+// when that is less than minWatering.
+func Recommend(in Inputs) Recommendation {
+	need := dailyNeed(in.TempMax) * lookbackDays
+	deficit := need - in.RecentRain
+
+	var reasons []string
+	reasons = append(reasons, fmt.Sprintf("%s of rain fell over the past %d days", mm(in.RecentRain), lookbackDays))
+	if in.ForecastRain >= 0.1 {
+		reasons = append(reasons, fmt.Sprintf("%s is expected by tomorrow", mm(in.ForecastRain)))
+	} else {
+		reasons = append(reasons, "no rain is expected by tomorrow")
+	}
+	reason := fmt.Sprintf("%s against the %s a garden needs in %d days at %.0f°C", strings.Join(reasons, " and "), mm(need), lookbackDays, in.TempMax)
+
+	switch {
+	case deficit < minWatering:
+		return Recommendation{Reason: "the soil is still moist: " + reason}
+	case deficit-in.ForecastRain < minWatering:
+		return Recommendation{Reason: "the rain of the forecast will do: " + reason}
+	}
+	return Recommendation{Water: true, Amount: math.Round(deficit - in.ForecastRain), Reason: reason}
+}
+
+// mm formats a precipitation, e.g. "1.2 mm".
+func mm(v float64) string {
+	return fmt.Sprintf("%.1f mm", v)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestRecommend(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         Inputs
+		wantWater  bool
+		wantAmount float64
+		wantReason string
+	}{
+		{
+			name:       "dry and hot",
+			in:         Inputs{TempMax: 32},
+			wantWater:  true,
+			wantAmount: 18,
+			wantReason: "0.0 mm of rain fell over the past 3 days and no rain is expected by tomorrow against the 18.0 mm a garden needs in 3 days at 32°C",
+		},
+		{
+			name:       "dry and mild",
+			in:         Inputs{RecentRain: 1.5, TempMax: 21},
+			wantWater:  true,
+			wantAmount: 9,
+		},
+		{
+			name:       "a shower expected",
+			in:         Inputs{RecentRain: 1, ForecastRain: 4, TempMax: 27},
+			wantWater:  true,
+			wantAmount: 9,
+			wantReason: "4.0 mm is expected by tomorrow",
+		},
+		{
+			name:       "rained a lot",
+			in:         Inputs{RecentRain: 22, TempMax: 26},
+			wantReason: "the soil is still moist",
+		},
+		{
+			name:       "rain coming",
+			in:         Inputs{RecentRain: 2, ForecastRain: 9.5, TempMax: 23},
+			wantReason: "the rain of the forecast will do",
+		},
+		{
+			name:       "cool and damp",
+			in:         Inputs{RecentRain: 4.5, TempMax: 12},
+			wantReason: "the soil is still moist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Recommend(tt.in)
+			if r.Water != tt.wantWater || r.Amount != tt.wantAmount {
+				t.Errorf("Recommend() = %v %v, want %v %v", r.Water, r.Amount, tt.wantWater, tt.wantAmount)
+			}
+			if !strings.Contains(r.Reason, tt.wantReason) {
+				t.Errorf("Recommend() reason = %q, want %q", r.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRecommendString(t *testing.T) {
+	got := Recommend(Inputs{TempMax: 32}).String()
+	want := "water the garden today with about 18 liters per square meter, 0.0 mm of rain fell over the past 3 days and no rain is expected by tomorrow against the 18.0 mm a garden needs in 3 days at 32°C"
+	if got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if got := Recommend(Inputs{RecentRain: 30, TempMax: 20}).String(); !strings.HasPrefix(got, "no need to water today, the soil is still moist") {
+		t.Errorf("String() = %s", got)
+	}
+}
+
+func TestWatering(t *testing.T) {
+	// noon of 2024-08-07 and 2024-08-08 in UTC+2
+	rain := map[string]string{"2024-08-06": "0.4", "2024-08-05": "1.1", "2024-08-04": "0"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/3.0/onecall":
+			fmt.Fprint(w, `{"timezone_offset":7200,"daily":[
+				{"dt":1723024800,"temp":{"min":18,"max":28},"weather":[{"id":800,"description":"clear sky"}],"pop":0},
+				{"dt":1723111200,"temp":{"min":17,"max":24},"weather":[{"id":501,"description":"moderate rain"}],"pop":0.5,"rain":4}
+			]}`)
+		case "/data/3.0/onecall/day_summary":
+			total, ok := rain[r.URL.Query().Get("date")]
+			if !ok {
+				http.Error(w, `{"cod":400,"message":"bad date"}`, http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"date":%q,"precipitation":{"total":%s}}`, r.URL.Query().Get("date"), total)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := Watering(context.Background(), 45.764, 4.8357)
+	if err != nil {
+		t.Fatalf("Watering() error = %v", err)
+	}
+	want := "45.764,4.8357 on 2024-08-07: water the garden today with about 10 liters per square meter, 1.5 mm of rain fell over the past 3 days and 2.0 mm is expected by tomorrow against the 13.5 mm a garden needs in 3 days at 28°C"
+	if got != want {
+		t.Errorf("Watering() =\n%s\nwant\n%s", got, want)
+	}
+
+	delete(rain, "2024-08-05")
+	if _, err := Watering(context.Background(), 45.764, 4.8357); err == nil || !strings.Contains(err.Error(), "the rain of 2024-08-05") {
+		t.Errorf("Watering() error = %v, want the failed day", err)
+	}
+	if _, err := Watering(context.Background(), 95, 0); err == nil {
+		t.Error("Watering() with an invalid latitude should fail")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert an electromagnetic wave between its frequency in hertz and its wavelength in meters in a vacuum, or between its frequency in hertz and the energy of its photons in electronvolts, e.g. "what is the wavelength of 2.4 GHz?" or "what is the frequency of green light at 532 nm?". Convert the value to hertz, meters or electronvolts before calling the function.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Mode  string  `json:"mode" jsonschema:"description=The conversion to do,enum=frequency-to-wavelength,enum=wavelength-to-frequency,enum=frequency-to-energy,enum=energy-to-frequency"`
+	Value float64 `json:"value" jsonschema:"description=The frequency in Hz or the wavelength in m or the energy in eV to convert,exclusiveMinimum=0"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "wave", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xCD}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	result, err := Convert(msg.Mode, msg.Value)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g: %v", msg.Value, err))
+		return
+	}
+
+	sfn.WriteText(ctx, result)
+}
+
+// The exact SI values of the defining constants.
+const (
+	// speedOfLight in m/s.
+	speedOfLight = 299792458
+	// planck is the Planck constant in J·s.
+	planck = 6.62607015e-34
+	// electronvolt in J.
+	electronvolt = 1.602176634e-19
+)
+
+// conversion turns a quantity into another one.
+type conversion struct {
+	from, to string
+	fromUnit string
+	toUnit   string
+	convert  func(float64) float64
+}
+
+var conversions = map[string]conversion{
+	"frequency-to-wavelength": {"frequency", "wavelength", "Hz", "m", func(hz float64) float64 { return speedOfLight / hz }},
+	"wavelength-to-frequency": {"wavelength", "frequency", "m", "Hz", func(m float64) float64 { return speedOfLight / m }},
+	"frequency-to-energy":     {"frequency", "photon energy", "Hz", "eV", func(hz float64) float64 { return planck * hz / electronvolt }},
+	"energy-to-frequency":     {"photon energy", "frequency", "eV", "Hz", func(ev float64) float64 { return ev * electronvolt / planck }},
+}
+
+// Wave converts value with the conversion named mode.
+func Wave(mode string, value float64) (float64, error) {
+	c, ok := conversions[strings.ToLower(strings.TrimSpace(mode))]
+	if !ok {
+		return 0, fmt.Errorf("unknown mode %q, use frequency-to-wavelength, wavelength-to-frequency, frequency-to-energy or energy-to-frequency", mode)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) || value <= 0 {
+		return 0, fmt.Errorf("the %s must be a positive number of %s", c.from, c.fromUnit)
+	}
+	return c.convert(value), nil
+}
+
+// Convert converts value with the conversion named mode and describes the
+// result.
+func Convert(mode string, value float64) (string, error) {
+	converted, err := Wave(mode, value)
+	if err != nil {
+		return "", err
+	}
+	c := conversions[strings.ToLower(strings.TrimSpace(mode))]
+	return fmt.Sprintf("A %s of %g %s is a %s of %.6g %s", c.from, value, c.fromUnit, c.to, converted, c.toUnit), nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWave(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		value float64
+		want  float64
+	}{
+		// 299792458 / 5.635e14 = 532.0185 nm
+		{"green laser wavelength", "frequency-to-wavelength", 5.635e14, 532.0185e-9},
+		{"1 m in a vacuum", "wavelength-to-frequency", 1, 299792458},
+		// the 21 cm hydrogen line is at 1420.405751768 MHz
+		{"hydrogen line", "wavelength-to-frequency", 0.21106114, 1420.405751768e6},
+		{"wifi wavelength", "Frequency-To-Wavelength", 2.4e9, 0.124913524},
+		// hc is 1239.84198 eV·nm, so a red photon of 700 nm carries 1.7712028 eV
+		{"red photon energy", "frequency-to-energy", 299792458 / 700e-9, 1.7712028},
+		// 1 eV is 2.417989242e14 Hz
+		{"1 eV", "energy-to-frequency", 1, 2.417989242e14},
+	}
+	for _, tt := range tests {
+		got, err := Wave(tt.mode, tt.value)
+		if err != nil {
+			t.Errorf("%s: Wave() error = %v", tt.name, err)
+			continue
+		}
+		if math.Abs(got-tt.want)/tt.want > 1e-6 {
+			t.Errorf("%s: Wave(%s, %g) = %g, want %g", tt.name, tt.mode, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWaveRoundTrip(t *testing.T) {
+	for _, value := range []float64{1, 5.45e14, 3e18} {
+		m, _ := Wave("frequency-to-wavelength", value)
+		hz, _ := Wave("wavelength-to-frequency", m)
+		ev, _ := Wave("frequency-to-energy", hz)
+		back, _ := Wave("energy-to-frequency", ev)
+		if math.Abs(back-value)/value > 1e-12 {
+			t.Errorf("round trip of %g Hz = %g Hz", value, back)
+		}
+	}
+}
+
+func TestWaveInvalid(t *testing.T) {
+	tests := []struct {
+		mode  string
+		value float64
+	}{
+		{"frequency-to-wavelength", 0},
+		{"wavelength-to-frequency", -1},
+		{"frequency-to-energy", math.NaN()},
+		{"energy-to-frequency", math.Inf(1)},
+		{"wavelength-to-energy", 1},
+		{"", 1},
+	}
+	for _, tt := range tests {
+		if _, err := Wave(tt.mode, tt.value); err == nil {
+			t.Errorf("Wave(%q, %v) should fail", tt.mode, tt.value)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	got, err := Convert("frequency-to-wavelength", 2.4e9)
+	want := "A frequency of 2.4e+09 Hz is a wavelength of 0.124914 m"
+	if err != nil || got != want {
+		t.Errorf("Convert() = %q, %v, want %q", got, err, want)
+	}
+}
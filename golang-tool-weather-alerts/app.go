@@ -0,0 +1,257 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the active weather alerts of the National Weather Service for a location in the United States, e.g. warnings of storms, floods or heat. Set "minSeverity" to only get the alerts at or above a severity, e.g. severe when the user only cares about dangerous weather. If the user gives a place name, convert it to Latitude and Longitude in decimal format. The function returns the number of matching alerts and their event, severity, headline and end.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude    float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude   float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+	MinSeverity string  `json:"minSeverity,omitempty" jsonschema:"description=The lowest severity of the alerts to return. All alerts when omitted,enum=minor,enum=moderate,enum=severe,enum=extreme"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-alerts", Description: Description(), InputSchema: InputSchema(), Upstream: api.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x111}
+}
+
+// api is the National Weather Service API, which asks clients to identify
+// themselves with a User-Agent.
+var api = &NWS{
+	BaseURL:    "https://api.weather.gov",
+	UserAgent:  "(yomo-llm-weather-alerts, https://github.com/yomorun/llm-function-calling-examples)",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude, "min_severity", msg.MinSeverity)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := ActiveAlerts(reqCtx, api, msg.Latitude, msg.Longitude, msg.MinSeverity)
+	if err != nil {
+		slog.Warn("[sfn] ActiveAlerts error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather alerts: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, result.String())
+}
+
+// Severity is the ordered severity scale of the Common Alerting Protocol,
+// which the NWS alerts use, see
+// https://docs.oasis-open.org/emergency/cap/v1.2/CAP-v1.2.html.
+type Severity int
+
+const (
+	// Unknown is below every threshold, an alert of unknown severity is
+	// only returned when no minimum is given.
+	Unknown Severity = iota
+	Minor
+	Moderate
+	Severe
+	Extreme
+)
+
+var severityNames = []string{"unknown", "minor", "moderate", "severe", "extreme"}
+
+func (s Severity) String() string {
+	if s < Unknown || s > Extreme {
+		return severityNames[Unknown]
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity maps a severity of the provider, e.g. "Severe", to the
+// scale. It returns false for a string that is not on the scale.
+func ParseSeverity(s string) (Severity, bool) {
+	i := slices.Index(severityNames, strings.ToLower(strings.TrimSpace(s)))
+	if i < 0 {
+		return Unknown, false
+	}
+	return Severity(i), true
+}
+
+// Alert is an active alert.
+type Alert struct {
+	Event    string
+	Severity Severity
+	Headline string
+	// Ends is when the hazard is expected to end, zero when not known.
+	Ends time.Time
+}
+
+// Result is the alerts at or above a severity.
+type Result struct {
+	Latitude, Longitude float64
+	Min                 Severity
+	Alerts              []Alert
+}
+
+func (r *Result) String() string {
+	scope := ""
+	if r.Min > Unknown {
+		scope = " at or above " + r.Min.String()
+	}
+	if len(r.Alerts) == 0 {
+		return fmt.Sprintf("There is no active weather alert%s at %v,%v.", scope, r.Latitude, r.Longitude)
+	}
+
+	parts := make([]string, len(r.Alerts))
+	for i, a := range r.Alerts {
+		parts[i] = fmt.Sprintf("%s (%s)", a.Event, a.Severity)
+		if !a.Ends.IsZero() {
+			parts[i] += " until " + a.Ends.Format("2006-01-02 15:04 MST")
+		}
+		if a.Headline != "" {
+			parts[i] += ": " + a.Headline
+		}
+	}
+	noun := "alerts"
+	if len(r.Alerts) == 1 {
+		noun = "alert"
+	}
+	return fmt.Sprintf("%d active weather %s%s at %v,%v: %s", len(r.Alerts), noun, scope, r.Latitude, r.Longitude, strings.Join(parts, "; "))
+}
+
+// Filter returns the alerts at or above threshold, the most severe first. Alerts
+// of the same severity keep their order.
+func Filter(alerts []Alert, threshold Severity) []Alert {
+	var matching []Alert
+	for _, a := range alerts {
+		if a.Severity >= threshold {
+			matching = append(matching, a)
+		}
+	}
+	slices.SortStableFunc(matching, func(a, b Alert) int {
+		return cmp.Compare(b.Severity, a.Severity)
+	})
+	return matching
+}
+
+// ActiveAlerts validates the arguments and returns the active alerts at
+// lat,lon at or above minSeverity, all of them when it is empty.
+func ActiveAlerts(ctx context.Context, n *NWS, lat, lon float64, minSeverity string) (*Result, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return nil, err
+	}
+	threshold := Unknown
+	if strings.TrimSpace(minSeverity) != "" {
+		var ok bool
+		if threshold, ok = ParseSeverity(minSeverity); !ok || threshold == Unknown {
+			return nil, fmt.Errorf("unknown severity %q, it must be minor, moderate, severe or extreme", minSeverity)
+		}
+	}
+
+	alerts, err := n.Alerts(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Latitude: lat, Longitude: lon, Min: threshold, Alerts: Filter(alerts, threshold)}, nil
+}
+
+// NWS is a client of the National Weather Service API.
+type NWS struct {
+	BaseURL    string
+	UserAgent  string
+	HTTPClient *http.Client
+}
+
+// Alerts returns the active alerts whose area covers lat,lon.
+func (n *NWS) Alerts(ctx context.Context, lat, lon float64) ([]Alert, error) {
+	q := url.Values{}
+	q.Set("point", fmt.Sprintf("%.4f,%.4f", lat, lon))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.BaseURL+"/alerts/active?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/geo+json")
+	req.Header.Set("User-Agent", n.UserAgent)
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// a point outside the United States is answered with 400
+		return nil, fmt.Errorf("the National Weather Service responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var body struct {
+		Features []struct {
+			Properties struct {
+				Event    string    `json:"event"`
+				Severity string    `json:"severity"`
+				Headline string    `json:"headline"`
+				Expires  time.Time `json:"expires"`
+				Ends     time.Time `json:"ends"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode the alerts: %w", err)
+	}
+
+	alerts := make([]Alert, 0, len(body.Features))
+	for _, f := range body.Features {
+		p := f.Properties
+		// an unexpected severity counts as unknown
+		severity, _ := ParseSeverity(p.Severity)
+		ends := p.Ends
+		if ends.IsZero() {
+			ends = p.Expires
+		}
+		alerts = append(alerts, Alert{Event: p.Event, Severity: severity, Headline: p.Headline, Ends: ends})
+	}
+	return alerts, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Severity
+		wantOK bool
+	}{
+		{"Extreme", Extreme, true},
+		{"severe", Severe, true},
+		{" MODERATE ", Moderate, true},
+		{"Minor", Minor, true},
+		{"Unknown", Unknown, true},
+		{"catastrophic", Unknown, false},
+		{"", Unknown, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSeverity(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseSeverity(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+	if !(Unknown < Minor && Minor < Moderate && Moderate < Severe && Severe < Extreme) {
+		t.Error("the severities are not ordered")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	alerts := []Alert{
+		{Event: "Special Weather Statement", Severity: Minor},
+		{Event: "Tornado Warning", Severity: Extreme},
+		{Event: "Test Message", Severity: Unknown},
+		{Event: "Flood Watch", Severity: Moderate},
+		{Event: "Severe Thunderstorm Warning", Severity: Severe},
+		{Event: "Heat Advisory", Severity: Moderate},
+	}
+	tests := []struct {
+		threshold Severity
+		want      []string
+	}{
+		{Unknown, []string{"Tornado Warning", "Severe Thunderstorm Warning", "Flood Watch", "Heat Advisory", "Special Weather Statement", "Test Message"}},
+		{Minor, []string{"Tornado Warning", "Severe Thunderstorm Warning", "Flood Watch", "Heat Advisory", "Special Weather Statement"}},
+		{Moderate, []string{"Tornado Warning", "Severe Thunderstorm Warning", "Flood Watch", "Heat Advisory"}},
+		{Severe, []string{"Tornado Warning", "Severe Thunderstorm Warning"}},
+		{Extreme, []string{"Tornado Warning"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, a := range Filter(alerts, tt.threshold) {
+			got = append(got, a.Event)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Filter(%v) = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestActiveAlerts(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("point"))
+		if r.URL.Path != "/alerts/active" || r.UserAgent() != "test-agent" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"type":"FeatureCollection","features":[
+			{"properties":{"event":"Flood Watch","severity":"Moderate","headline":"Flood Watch issued May 6 at 3:05PM CDT","expires":"2024-05-07T04:00:00-05:00","ends":null}},
+			{"properties":{"event":"Tornado Warning","severity":"Extreme","headline":"Tornado Warning issued May 6 at 8:51PM CDT","expires":"2024-05-06T21:15:00-05:00","ends":"2024-05-06T21:30:00-05:00"}},
+			{"properties":{"event":"Air Quality Alert","severity":"Unknown","headline":"","expires":"2024-05-07T23:00:00-05:00"}}
+		]}`)
+	}))
+	defer srv.Close()
+	api := &NWS{BaseURL: srv.URL, UserAgent: "test-agent", HTTPClient: srv.Client()}
+
+	got, err := ActiveAlerts(context.Background(), api, 35.4676, -97.5164, "Severe")
+	if err != nil {
+		t.Fatalf("ActiveAlerts() error = %v", err)
+	}
+	want := "1 active weather alert at or above severe at 35.4676,-97.5164: Tornado Warning (extreme) until 2024-05-06 21:30 -0500: Tornado Warning issued May 6 at 8:51PM CDT"
+	if got.String() != want {
+		t.Errorf("ActiveAlerts() =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = ActiveAlerts(context.Background(), api, 35.4676, -97.5164, "")
+	if err != nil {
+		t.Fatalf("ActiveAlerts() error = %v", err)
+	}
+	if len(got.Alerts) != 3 || !strings.HasPrefix(got.String(), "3 active weather alerts at 35.4676,-97.5164: Tornado Warning") ||
+		!strings.Contains(got.String(), "Flood Watch (moderate) until 2024-05-07 04:00 -0500") {
+		t.Errorf("ActiveAlerts() without a minimum = %s", got)
+	}
+
+	got, err = ActiveAlerts(context.Background(), api, 35.4676, -97.5164, "extreme")
+	if err != nil || len(got.Alerts) != 1 {
+		t.Fatalf("ActiveAlerts(extreme) = %v, %v", got, err)
+	}
+
+	for _, tt := range []struct {
+		lat, lon float64
+		severity string
+	}{
+		{91, 0, ""},
+		{0, 181, ""},
+		{35, -97, "catastrophic"},
+		{35, -97, "unknown"},
+	} {
+		if _, err := ActiveAlerts(context.Background(), api, tt.lat, tt.lon, tt.severity); err == nil {
+			t.Errorf("ActiveAlerts(%v, %v, %q) should fail", tt.lat, tt.lon, tt.severity)
+		}
+	}
+	if len(requested) != 3 || requested[0] != "35.4676,-97.5164" {
+		t.Errorf("requested %v, want 3 requests of the point", requested)
+	}
+}
+
+func TestResultEmpty(t *testing.T) {
+	r := &Result{Latitude: 40, Longitude: -105, Min: Moderate}
+	if got, want := r.String(), "There is no active weather alert at or above moderate at 40,-105."; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compare the current weather of two cities, e.g. "is it warmer in London or Oslo?". Both city names are required, if one is missing you should ask to clarify it. A city name may be qualified with its state and country code, e.g. "Paris, Texas, US". The function returns which city is warmer and windier right now, together with the current conditions of each city.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	FirstCity  string `json:"firstCity" jsonschema:"description=The name of the first city to compare with its state and country code when known"`
+	SecondCity string `json:"secondCity" jsonschema:"description=The name of the second city to compare with its state and country code when known"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-compare", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xAF}
+}
+
+var client = newClient()
+
+// newClient returns an OpenWeatherMap client that caches the coordinates of
+// the cities for a day and their weather for 10 minutes, so that a city
+// compared again is answered without any request.
+func newClient() *weather.Client {
+	c := weather.NewClient("")
+	c.CurrentCache = cache.New[*weather.Conditions](10 * time.Minute)
+	c.CityCache = cache.New[weather.Location](24 * time.Hour)
+	return c
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "first", msg.FirstCity, "second", msg.SecondCity)
+
+	// the four upstream calls share one deadline
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result := Compare(reqCtx, msg.FirstCity, msg.SecondCity)
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Compare fetches the weather of both cities within the deadline of ctx and
+// compares them.
+func Compare(ctx context.Context, firstCity, secondCity string) string {
+	first, firstErr := fetchConditions(ctx, firstCity)
+	second, secondErr := fetchConditions(ctx, secondCity)
+
+	if question := disambiguate(firstErr, secondErr); question != "" {
+		return question
+	}
+
+	switch {
+	case errors.Is(firstErr, context.DeadlineExceeded) || errors.Is(secondErr, context.DeadlineExceeded):
+		return fmt.Sprintf("the weather service timed out before the weather of %s and %s was known, please try again later", firstCity, secondCity)
+	case firstErr != nil && secondErr != nil:
+		return fmt.Sprintf("can not get the weather of %s and %s at the moment", firstCity, secondCity)
+	case firstErr != nil:
+		return fmt.Sprintf("can not get the weather of %s, so no comparison is possible. The current weather in %s is %s", firstCity, secondCity, second.Summary())
+	case secondErr != nil:
+		return fmt.Sprintf("can not get the weather of %s, so no comparison is possible. The current weather in %s is %s", secondCity, firstCity, first.Summary())
+	default:
+		return CompareConditions(firstCity, first, secondCity, second)
+	}
+}
+
+// disambiguate asks to pick among the places of the ambiguous city names of
+// errs, or returns "" when no name is ambiguous.
+func disambiguate(errs ...error) string {
+	var questions []string
+	for _, err := range errs {
+		var ambiguous *weather.AmbiguousCityError
+		if errors.As(err, &ambiguous) {
+			questions = append(questions, ambiguous.Error())
+		}
+	}
+	if len(questions) == 0 {
+		return ""
+	}
+	return strings.Join(questions, ". ") + ". Ask the user which place they mean, then compare again with the name of that place as listed, e.g. \"Paris, Texas, US\"."
+}
+
+// fetchConditions geocodes the city and fetches its current weather.
+func fetchConditions(ctx context.Context, city string) (*weather.Conditions, error) {
+	if strings.TrimSpace(city) == "" {
+		return nil, errors.New("city name is empty")
+	}
+
+	conditions, _, err := client.CurrentByCity(ctx, city)
+	if err != nil {
+		slog.Error("[sfn] current weather", "city", city, "err", err)
+		return nil, err
+	}
+	return conditions, nil
+}
+
+// windThreshold is the difference in m/s below which two wind speeds are
+// reported as similar.
+const windThreshold = 1.0
+
+// CompareConditions phrases how the weather in city a compares to city b,
+// e.g. "London is 6°C warmer and less windy than Oslo right now.", followed
+// by the conditions of both cities.
+func CompareConditions(nameA string, a *weather.Conditions, nameB string, b *weather.Conditions) string {
+	var facts []string
+
+	diff := math.Round(a.Temperature - b.Temperature)
+	switch {
+	case diff >= 1:
+		facts = append(facts, fmt.Sprintf("%.0f°C warmer", diff))
+	case diff <= -1:
+		facts = append(facts, fmt.Sprintf("%.0f°C colder", -diff))
+	}
+
+	wind := a.WindSpeed - b.WindSpeed
+	switch {
+	case wind >= windThreshold:
+		facts = append(facts, "windier")
+	case wind <= -windThreshold:
+		facts = append(facts, "less windy")
+	}
+
+	var comparison string
+	if len(facts) == 0 {
+		comparison = fmt.Sprintf("%s and %s have about the same temperature and wind right now.", nameA, nameB)
+	} else {
+		comparison = fmt.Sprintf("%s is %s than %s right now.", nameA, strings.Join(facts, " and "), nameB)
+		if diff == 0 {
+			comparison = fmt.Sprintf("%s has about the same temperature as %s but is %s right now.", nameA, nameB, facts[0])
+		}
+	}
+
+	return fmt.Sprintf("%s %s. %s.", comparison, a.Summary(), b.Summary())
+}
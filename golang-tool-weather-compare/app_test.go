@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestCompareConditions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b weather.Conditions
+		want string
+	}{
+		{
+			name: "warmer and less windy",
+			a:    weather.Conditions{Temperature: 12.2, WindSpeed: 2},
+			b:    weather.Conditions{Temperature: 6.1, WindSpeed: 7.5},
+			want: "London is 6°C warmer and less windy than Oslo right now.",
+		},
+		{
+			name: "colder and windier",
+			a:    weather.Conditions{Temperature: -3, WindSpeed: 9},
+			b:    weather.Conditions{Temperature: 4.6, WindSpeed: 3},
+			want: "London is 8°C colder and windier than Oslo right now.",
+		},
+		{
+			name: "only temperature differs",
+			a:    weather.Conditions{Temperature: 20, WindSpeed: 3},
+			b:    weather.Conditions{Temperature: 15, WindSpeed: 3.5},
+			want: "London is 5°C warmer than Oslo right now.",
+		},
+		{
+			name: "only wind differs",
+			a:    weather.Conditions{Temperature: 10.2, WindSpeed: 8},
+			b:    weather.Conditions{Temperature: 10, WindSpeed: 2},
+			want: "London has about the same temperature as Oslo but is windier right now.",
+		},
+		{
+			name: "same weather",
+			a:    weather.Conditions{Temperature: 10.2, WindSpeed: 3},
+			b:    weather.Conditions{Temperature: 10, WindSpeed: 3.4},
+			want: "London and Oslo have about the same temperature and wind right now.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareConditions("London", &tt.a, "Oslo", &tt.b)
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("CompareConditions() = %s, want prefix %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareConditionsIncludesSummaries(t *testing.T) {
+	a := &weather.Conditions{City: "London", Country: "GB", Description: "light rain", Temperature: 12}
+	b := &weather.Conditions{City: "Oslo", Country: "NO", Description: "clear sky", Temperature: 6}
+
+	got := CompareConditions("London", a, "Oslo", b)
+	for _, want := range []string{a.Summary(), b.Summary()} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CompareConditions() = %s, want it to contain %s", got, want)
+		}
+	}
+}
+
+func TestCompareBudget(t *testing.T) {
+	var weatherCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/geo/1.0/direct":
+			// slower than the whole budget
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			w.Write([]byte(`[{"name":"London","lat":51.5,"lon":-0.12}]`))
+		case "/data/2.5/weather":
+			weatherCalls.Add(1)
+			w.Write([]byte(`{"name":"London","main":{"temp":12}}`))
+		}
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	got := Compare(ctx, "London", "Oslo")
+	if !strings.Contains(got, "timed out") {
+		t.Errorf("Compare() = %s, want a timeout message", got)
+	}
+	if n := weatherCalls.Load(); n != 0 {
+		t.Errorf("the weather was requested %d times after the budget was spent", n)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Compare() took %v, want it to give up with the budget", elapsed)
+	}
+}
+
+func TestCompareAmbiguousCity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "Paris":
+			w.Write([]byte(`[{"name":"Paris","lat":48.8589,"lon":2.32,"country":"FR","state":"Ile-de-France"},{"name":"Paris","lat":33.6618,"lon":-95.5555,"country":"US","state":"Texas"}]`))
+		case "Paris,TX,US":
+			w.Write([]byte(`[{"name":"Paris","lat":33.6618,"lon":-95.5555,"country":"US","state":"Texas"}]`))
+		case "Oslo":
+			w.Write([]byte(`[{"name":"Oslo","lat":59.9133,"lon":10.739,"country":"NO"}]`))
+		default:
+			w.Write([]byte(`{"name":"Somewhere","main":{"temp":12}}`))
+		}
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = newClient()
+	client.APIKey = "key"
+	client.BaseURL = srv.URL
+
+	got := Compare(context.Background(), "Paris", "Oslo")
+	want := `"Paris" matches 2 places: Paris, Ile-de-France, FR (48.8589,2.3200); Paris, Texas, US (33.6618,-95.5555). Ask the user which place they mean`
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("Compare() = %s, want %s...", got, want)
+	}
+
+	// the place picked from the list is a single match
+	if got := Compare(context.Background(), "Paris, Texas, US", "Oslo"); strings.Contains(got, "matches") || !strings.Contains(got, "Paris, Texas, US and Oslo") {
+		t.Errorf("Compare() = %s, want a comparison", got)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get a compact emoji summary of the current weather at a location, e.g. "🌧️ 12°C light rain", for chat messages or status lines. If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-emoji", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xCF}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := EmojiWeather(reqCtx, msg.Latitude, msg.Longitude)
+	if err != nil {
+		slog.Warn("[sfn] EmojiWeather error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// EmojiWeather fetches the current weather at lat,lon and summarizes it.
+func EmojiWeather(ctx context.Context, lat, lon float64) (string, error) {
+	conditions, err := client.Current(ctx, lat, lon)
+	if err != nil {
+		return "", err
+	}
+	return emojiSummary(conditions), nil
+}
+
+// emojiSummary is e.g. "Paris, FR: 🌥️ 20°C broken clouds".
+func emojiSummary(c *weather.Conditions) string {
+	// adding 0 turns the -0 that math.Round(-0.4) returns into 0
+	s := fmt.Sprintf("%s %.0f°C", weather.Emoji(c.ConditionID), math.Round(c.Temperature)+0)
+	if c.Description != "" {
+		s += " " + c.Description
+	}
+	if c.City != "" {
+		place := c.City
+		if c.Country != "" {
+			place += ", " + c.Country
+		}
+		s = place + ": " + s
+	}
+	return s
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestEmojiSummary(t *testing.T) {
+	tests := []struct {
+		conditions weather.Conditions
+		want       string
+	}{
+		{weather.Conditions{ConditionID: 500, Temperature: 12.3, Description: "light rain"}, "🌧️ 12°C light rain"},
+		{weather.Conditions{City: "Oslo", Country: "NO", ConditionID: 600, Temperature: -0.4, Description: "light snow"}, "Oslo, NO: ❄️ 0°C light snow"},
+		{weather.Conditions{City: "Cairo", ConditionID: 800, Temperature: 35.5}, "Cairo: ☀️ 36°C"},
+	}
+	for _, tt := range tests {
+		if got := emojiSummary(&tt.conditions); got != tt.want {
+			t.Errorf("emojiSummary() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestEmojiWeather(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Paris","sys":{"country":"FR"},"weather":[{"id":803,"description":"broken clouds"}],"main":{"temp":19.8}}`)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := EmojiWeather(context.Background(), 48.86, 2.35)
+	if want := "Paris, FR: 🌥️ 20°C broken clouds"; err != nil || got != want {
+		t.Errorf("EmojiWeather() = %s, %v, want %s", got, err, want)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the historical weather of a city for a given past date. If no city is provided, you should ask to clarify the city. If the city name is given, you should convert the city name to Latitude and Longitude geo coordinates, keeping Latitude and Longitude in decimal format. The date must be in the past, formatted as YYYY-MM-DD.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	City      string  `json:"city" jsonschema:"description=The city name to get the historical weather for"`
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the city in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the city in decimal format,minimum=-180,maximum=180"`
+	Date      string  `json:"date" jsonschema:"description=The past date to get the weather for in YYYY-MM-DD format,example=2024-03-01"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-history", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB0}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var p Parameter
+	if !sfn.ReadArgs(ctx, &p) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "city", p.City, "lat", p.Latitude, "lon", p.Longitude, "date", p.Date)
+
+	day, err := ValidateRequest(p, time.Now())
+	if err != nil {
+		sfn.WriteText(ctx, err.Error())
+		return
+	}
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	// ask for midday, which represents the day better than midnight
+	conditions, err := client.History(reqCtx, p.Latitude, p.Longitude, day.Add(12*time.Hour))
+	if err != nil {
+		slog.Error("[sfn] history", "err", err)
+		sfn.WriteText(ctx, "can not get the historical weather information at the moment")
+		return
+	}
+
+	result := fmt.Sprintf("the weather in %s on %s was: %s", p.City, day.Format(dateFormat), conditions.Summary())
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+const dateFormat = "2006-01-02"
+
+// earliestDate is the first day covered by the OpenWeatherMap time machine.
+var earliestDate = time.Date(1979, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ValidateRequest checks the coordinate and the date of p before the API is
+// called, and returns the day of p.Date.
+func ValidateRequest(p Parameter, now time.Time) (time.Time, error) {
+	if err := geo.ValidateCoordinate(p.Latitude, p.Longitude); err != nil {
+		return time.Time{}, fmt.Errorf("invalid location: %w", err)
+	}
+	return ValidateDate(p.Date, now)
+}
+
+// ValidateDate parses a YYYY-MM-DD date and checks it lies between
+// earliestDate and yesterday relative to now. The returned time is midnight
+// UTC of that day.
+func ValidateDate(date string, now time.Time) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, errors.New("the date is missing, please provide a past date in YYYY-MM-DD format")
+	}
+	day, err := time.Parse(dateFormat, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can not understand the date %q, please provide it in YYYY-MM-DD format", date)
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	if !day.Before(today) {
+		return time.Time{}, fmt.Errorf("%s is not in the past, this function only reports historical weather", date)
+	}
+	if day.Before(earliestDate) {
+		return time.Time{}, fmt.Errorf("%s is too far in the past, historical weather is only available since %s", date, earliestDate.Format(dateFormat))
+	}
+	return day, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateDate(t *testing.T) {
+	now := time.Date(2024, time.August, 7, 17, 23, 58, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		date    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "yesterday",
+			date: "2024-08-06",
+			want: time.Date(2024, time.August, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "first available day",
+			date: "1979-01-01",
+			want: earliestDate,
+		},
+		{
+			name: "leap day",
+			date: "2024-02-29",
+			want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "today",
+			date:    "2024-08-07",
+			wantErr: true,
+		},
+		{
+			name:    "future",
+			date:    "2025-01-01",
+			wantErr: true,
+		},
+		{
+			name:    "before the time machine range",
+			date:    "1978-12-31",
+			wantErr: true,
+		},
+		{
+			name:    "wrong format",
+			date:    "07/08/2024",
+			wantErr: true,
+		},
+		{
+			name:    "not a real date",
+			date:    "2023-02-29",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			date:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateDate(tt.date, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateDate(%q) error = %v, wantErr %v", tt.date, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ValidateDate(%q) = %v, want %v", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	now := time.Date(2024, time.August, 7, 17, 23, 58, 0, time.UTC)
+
+	day, err := ValidateRequest(Parameter{City: "Paris", Latitude: 48.8566, Longitude: 2.3522, Date: "2024-08-06"}, now)
+	if err != nil || !day.Equal(time.Date(2024, time.August, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ValidateRequest() = %v, %v", day, err)
+	}
+
+	for _, p := range []Parameter{
+		{City: "Paris", Latitude: 148.8566, Longitude: 2.3522, Date: "2024-08-06"},
+		{City: "Paris", Latitude: 48.8566, Longitude: -200, Date: "2024-08-06"},
+	} {
+		_, err := ValidateRequest(p, now)
+		if err == nil || !strings.Contains(err.Error(), "invalid location") {
+			t.Errorf("ValidateRequest(%v,%v) error = %v, want an invalid location", p.Latitude, p.Longitude, err)
+		}
+	}
+}
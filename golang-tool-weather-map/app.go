@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the URL of a weather map tile image for a location. The layer can be precipitation (rain radar), clouds, temperature, wind or pressure. Convert a city name to Latitude and Longitude geo coordinates in decimal format. Use a zoom level between 0 (the whole world) and 18 (a street), around 6 shows a region and 10 a city. The function returns the URL of a 256x256 PNG overlay, without an API key: the application showing the map adds its own OpenWeatherMap key as the appid query parameter.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-85.0511,maximum=85.0511"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+	Zoom      int     `json:"zoom" jsonschema:"description=The zoom level of the map,minimum=0,maximum=18"`
+	Layer     string  `json:"layer" jsonschema:"description=The weather layer of the map,enum=precipitation,enum=clouds,enum=temperature,enum=wind,enum=pressure"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-map", Description: Description(), InputSchema: InputSchema(), Upstream: weather.DefaultTileURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB3}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	layer := msg.Layer
+	if layer == "" {
+		layer = "precipitation"
+	}
+
+	x, y, err := TileIndex(msg.Latitude, msg.Longitude, msg.Zoom)
+	if err != nil {
+		slog.Warn("[sfn] TileIndex error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather map: %v", err))
+		return
+	}
+
+	tileURL, err := weather.TileURL(layer, msg.Zoom, x, y)
+	if err != nil {
+		slog.Warn("[sfn] TileURL error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather map: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, fmt.Sprintf("the %s map tile %d/%d/%d covering %.4f,%.4f is %s", layer, msg.Zoom, x, y, msg.Latitude, msg.Longitude, tileURL))
+}
+
+const (
+	maxZoom = 18
+	// maxLatitude is the latitude where the Web Mercator projection is cut so
+	// that the world map is a square.
+	maxLatitude = 85.05112878
+)
+
+// TileIndex returns the x and y indices of the slippy map tile containing
+// the coordinate at the given zoom level. The world is 2^zoom tiles wide,
+// x grows eastwards from longitude -180 and y grows southwards from
+// latitude 85.0511.
+func TileIndex(lat, lon float64, zoom int) (x, y int, err error) {
+	if zoom < 0 || zoom > maxZoom {
+		return 0, 0, fmt.Errorf("zoom level %d is out of range, it must be between 0 and %d", zoom, maxZoom)
+	}
+	if math.IsNaN(lat) || lat < -maxLatitude || lat > maxLatitude {
+		return 0, 0, fmt.Errorf("latitude %v is out of range, map tiles cover -%.4f to %.4f", lat, maxLatitude, maxLatitude)
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("longitude %v is out of range, it must be between -180 and 180", lon)
+	}
+
+	n := math.Exp2(float64(zoom))
+	latRad := lat * math.Pi / 180
+	x = int(math.Floor((lon + 180) / 360 * n))
+	y = int(math.Floor((1 - math.Asinh(math.Tan(latRad))/math.Pi) / 2 * n))
+
+	// longitude 180 and latitude -85.0511 are on the far edge of the last
+	// tile
+	last := int(n) - 1
+	return min(max(x, 0), last), min(max(y, 0), last), nil
+}
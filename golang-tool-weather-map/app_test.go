@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestTileIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		zoom     int
+		wantX    int
+		wantY    int
+	}{
+		{"whole world", 51.5074, -0.1278, 0, 0, 0},
+		{"origin", 0, 0, 1, 1, 1},
+		{"london", 51.5074, -0.1278, 10, 511, 340},
+		{"new york", 40.7128, -74.0060, 12, 1205, 1540},
+		{"sydney", -33.8688, 151.2093, 8, 235, 153},
+		{"tokyo", 35.6762, 139.6503, 15, 29095, 12903},
+		{"north west corner", 85.0511, -180, 3, 0, 0},
+		{"south east corner", -85.0511, 180, 3, 7, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, err := TileIndex(tt.lat, tt.lon, tt.zoom)
+			if err != nil {
+				t.Fatalf("TileIndex() error = %v", err)
+			}
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("TileIndex(%v, %v, %d) = %d/%d, want %d/%d", tt.lat, tt.lon, tt.zoom, x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestTileIndexInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		zoom     int
+	}{
+		{"negative zoom", 0, 0, -1},
+		{"zoom too large", 0, 0, 19},
+		{"latitude beyond the projection", 86, 0, 5},
+		{"longitude out of range", 0, 181, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := TileIndex(tt.lat, tt.lon, tt.zoom); err == nil {
+				t.Errorf("TileIndex(%v, %v, %d) should fail", tt.lat, tt.lon, tt.zoom)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get a short push notification message about the current weather at a location, at most 120 characters with an emoji, the temperature and the most important fact, e.g. "🌧️ Paris: 12°C, rain 2.1 mm/h, take an umbrella". Use it to notify a user of the weather. If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format. Send the message as is.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-notify", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF0}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := Notify(reqCtx, msg.Latitude, msg.Longitude)
+	if err != nil {
+		slog.Warn("[sfn] Notify error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// Notify fetches the current weather at lat,lon and formats the
+// notification.
+func Notify(ctx context.Context, lat, lon float64) (string, error) {
+	if err := geo.ValidateCoordinate(lat, lon); err != nil {
+		return "", err
+	}
+	conditions, err := client.Current(ctx, lat, lon)
+	if err != nil {
+		return "", err
+	}
+	return Message(conditions), nil
+}
+
+// maxLength is the length cap of a notification in characters, which fits
+// the collapsed push notification of the common mobile platforms.
+const maxLength = 120
+
+// The thresholds of the facts worth a notification.
+const (
+	// strongWind is 7 Beaufort, a near gale.
+	strongWind  = 13.9
+	extremeHeat = 35
+	extremeCold = -10
+	// feelsLikeGap is how far the apparent temperature is from the
+	// temperature before it is worth telling.
+	feelsLikeGap = 3
+)
+
+// Message formats the conditions as a notification of at most maxLength
+// characters, e.g. "🌧️ Paris: 12°C, rain 2.1 mm/h, take an umbrella". A
+// place name too long for the cap is shortened with an ellipsis.
+func Message(c *weather.Conditions) string {
+	temperature := fmt.Sprintf("%.0f°C", math.Round(c.Temperature)+0)
+	if math.Abs(c.FeelsLike-c.Temperature) >= feelsLikeGap {
+		temperature += fmt.Sprintf(" (feels %.0f°C)", math.Round(c.FeelsLike)+0)
+	}
+	body := temperature
+	if fact := keyFact(c); fact != "" {
+		body += ", " + fact
+	}
+
+	prefix := weather.Emoji(c.ConditionID) + " "
+	if c.City == "" {
+		return truncate(prefix+body, maxLength)
+	}
+	// the place gives way first, the weather is the point of the message
+	room := maxLength - utf8.RuneCountInString(prefix) - utf8.RuneCountInString(": "+body)
+	if room < 4 {
+		return truncate(prefix+body, maxLength)
+	}
+	return prefix + truncate(c.City, room) + ": " + body
+}
+
+// keyFact returns the most important fact of the conditions, the hazards
+// first, or the description of the sky.
+func keyFact(c *weather.Conditions) string {
+	group := c.ConditionID / 100
+	switch {
+	case group == 2:
+		return "thunderstorms, stay indoors"
+	case group == 6 || c.Snow1h > 0:
+		if c.Snow1h > 0 {
+			return fmt.Sprintf("snow %.1f mm/h, roads may be slippery", c.Snow1h)
+		}
+		return "snow, roads may be slippery"
+	case group == 5 || group == 3 || c.Rain1h > 0:
+		if c.Rain1h > 0 {
+			return fmt.Sprintf("rain %.1f mm/h, take an umbrella", c.Rain1h)
+		}
+		return "rain, take an umbrella"
+	case c.WindSpeed >= strongWind:
+		return fmt.Sprintf("strong wind %.0f m/s, secure loose objects", c.WindSpeed)
+	case c.Temperature >= extremeHeat:
+		return "extreme heat, stay hydrated"
+	case c.Temperature <= extremeCold:
+		return "extreme cold, dress warmly"
+	case c.ConditionID == 741:
+		return "fog, drive carefully"
+	}
+	return c.Description
+}
+
+// truncate shortens s to at most n characters, ending it with an ellipsis
+// when it is cut.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
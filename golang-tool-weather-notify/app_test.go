@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		conditions weather.Conditions
+		want       string
+	}{
+		{weather.Conditions{City: "Paris", ConditionID: 500, Temperature: 12.3, FeelsLike: 11.5, Rain1h: 2.12, Description: "light rain"}, "🌧️ Paris: 12°C, rain 2.1 mm/h, take an umbrella"},
+		{weather.Conditions{City: "Miami", ConditionID: 211, Temperature: 29, FeelsLike: 34}, "⛈️ Miami: 29°C (feels 34°C), thunderstorms, stay indoors"},
+		{weather.Conditions{City: "Oslo", ConditionID: 601, Temperature: -0.4, FeelsLike: -1}, "❄️ Oslo: 0°C, snow, roads may be slippery"},
+		{weather.Conditions{City: "Wellington", ConditionID: 803, Temperature: 14, FeelsLike: 13, WindSpeed: 16.4, Description: "broken clouds"}, "🌥️ Wellington: 14°C, strong wind 16 m/s, secure loose objects"},
+		{weather.Conditions{City: "Phoenix", ConditionID: 800, Temperature: 43, FeelsLike: 41, Description: "clear sky"}, "☀️ Phoenix: 43°C, extreme heat, stay hydrated"},
+		{weather.Conditions{City: "Yakutsk", ConditionID: 800, Temperature: -38, FeelsLike: -45, Description: "clear sky"}, "☀️ Yakutsk: -38°C (feels -45°C), extreme cold, dress warmly"},
+		{weather.Conditions{ConditionID: 741, Temperature: 8, FeelsLike: 7, Description: "fog"}, "🌫️ 8°C, fog, drive carefully"},
+		{weather.Conditions{City: "Rome", ConditionID: 801, Temperature: 24.6, FeelsLike: 24.8, Description: "few clouds"}, "🌤️ Rome: 25°C, few clouds"},
+	}
+	for _, tt := range tests {
+		if got := Message(&tt.conditions); got != tt.want {
+			t.Errorf("Message() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestMessageLengthCap(t *testing.T) {
+	long := strings.Repeat("Llanfairpwllgwyngyll", 8)
+	tests := []weather.Conditions{
+		{City: long, ConditionID: 211, Temperature: 21.4, FeelsLike: 30},
+		{City: long, ConditionID: 500, Temperature: -5, FeelsLike: -12, Rain1h: 123.4},
+		{City: "Nice", ConditionID: 804, Temperature: 18, FeelsLike: 18, Description: strings.Repeat("overcast clouds ", 12)},
+		{ConditionID: 804, Temperature: 18, FeelsLike: 18, Description: strings.Repeat("overcast clouds ", 12)},
+	}
+	for _, c := range tests {
+		got := Message(&c)
+		if n := utf8.RuneCountInString(got); n > maxLength {
+			t.Errorf("Message() is %d characters long, above %d: %s", n, maxLength, got)
+		}
+		want := fmt.Sprintf("%.0f°C", c.Temperature)
+		if !strings.Contains(got, want) {
+			t.Errorf("Message() = %s, missing the temperature %s", got, want)
+		}
+	}
+
+	got := Message(&tests[0])
+	if !strings.HasPrefix(got, "⛈️ Llanfair") || !strings.Contains(got, "…: 21°C (feels 30°C), thunderstorms, stay indoors") {
+		t.Errorf("Message() = %s, want the place shortened and the weather kept", got)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Paris","sys":{"country":"FR"},"weather":[{"id":803,"description":"broken clouds"}],"main":{"temp":19.8,"feels_like":19.6}}`)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := Notify(context.Background(), 48.86, 2.35)
+	if want := "🌥️ Paris: 20°C, broken clouds"; err != nil || got != want {
+		t.Errorf("Notify() = %s, %v, want %s", got, err, want)
+	}
+	if _, err := Notify(context.Background(), 91, 0); err == nil {
+		t.Error("Notify() of an invalid latitude should fail")
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get the weather forecast of a city for a planned date in the next 5 days, e.g. "will it rain in Paris on Saturday?". If no city is provided, you should ask to clarify the city. If the city name is given, you should convert the city name to Latitude and Longitude geo coordinates, keeping Latitude and Longitude in decimal format. Convert relative dates like "Saturday" to YYYY-MM-DD.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	City      string  `json:"city" jsonschema:"description=The city name to get the forecast for"`
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the city in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the city in decimal format,minimum=-180,maximum=180"`
+	Date      string  `json:"date" jsonschema:"description=The planned date in YYYY-MM-DD format,example=2024-08-10"`
+	Time      string  `json:"time,omitempty" jsonschema:"description=The planned local time of day in HH:MM format. Defaults to 12:00,example=18:30"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-on-date", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB1}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var p Parameter
+	if !sfn.ReadArgs(ctx, &p) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "city", p.City, "lat", p.Latitude, "lon", p.Longitude, "date", p.Date, "time", p.Time)
+
+	// reject invalid and past dates before spending an API call. The exact
+	// local time needs the timezone of the forecast response, so check in
+	// the westernmost timezone where the date is in the past last.
+	now := time.Now()
+	if _, err := TargetTime(p.Date, p.Time, time.FixedZone("", -12*3600), now); err != nil && !errors.Is(err, errBeyondHorizon) {
+		sfn.WriteText(ctx, err.Error())
+		return
+	}
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	forecast, err := client.Forecast(reqCtx, p.Latitude, p.Longitude)
+	if err != nil {
+		slog.Error("[sfn] forecast", "err", err)
+		sfn.WriteText(ctx, "can not get the weather forecast at the moment")
+		return
+	}
+
+	target, err := TargetTime(p.Date, p.Time, forecast.Location(), now)
+	if err != nil {
+		sfn.WriteText(ctx, err.Error())
+		return
+	}
+
+	slot, err := SelectSlot(forecast.Slots, target)
+	if err != nil {
+		sfn.WriteText(ctx, fmt.Sprintf("the weather of %s on %s can not be told: %v", p.City, p.Date, err))
+		return
+	}
+
+	local := slot.Time.In(forecast.Location())
+	result := fmt.Sprintf("the forecast for %s around %s local time is: %s (%s)", p.City, local.Format("2006-01-02 15:04"), slot.Summary(),
+		confidenceFor(DayOffset(local, now)))
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// horizon is how far ahead the 5 day / 3 hour forecast reaches.
+const horizon = 5 * 24 * time.Hour
+
+// slotTolerance is how far a target may lie outside the forecast range and
+// still be matched to its first or last slot, half of the 3 hour step.
+const slotTolerance = 90 * time.Minute
+
+var errBeyondHorizon = errors.New("too far out, the forecast only covers the next 5 days")
+
+// TargetTime resolves the planned date and optional HH:MM time in loc. A
+// time earlier today is moved to now, while past days and days beyond the
+// forecast horizon are rejected.
+func TargetTime(date, clock string, loc *time.Location, now time.Time) (time.Time, error) {
+	if clock == "" {
+		clock = "12:00"
+	}
+	target, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can not understand the date %q and time %q, please use YYYY-MM-DD and HH:MM", date, clock)
+	}
+
+	now = now.In(loc)
+	if target.Before(now) {
+		if target.Format("2006-01-02") != now.Format("2006-01-02") {
+			return time.Time{}, fmt.Errorf("%s is in the past, this function only reports forecasts", date)
+		}
+		target = now
+	}
+	if target.Sub(now) > horizon {
+		return time.Time{}, fmt.Errorf("%s is %w", date, errBeyondHorizon)
+	}
+	return target, nil
+}
+
+// SelectSlot returns the forecast slot closest to target. Targets beyond the
+// last slot are too far out to be answered.
+func SelectSlot(slots []weather.Slot, target time.Time) (weather.Slot, error) {
+	if len(slots) == 0 {
+		return weather.Slot{}, errors.New("the forecast has no data")
+	}
+	if target.After(slots[len(slots)-1].Time.Add(slotTolerance)) {
+		return weather.Slot{}, errBeyondHorizon
+	}
+	if target.Before(slots[0].Time.Add(-slotTolerance)) {
+		return weather.Slot{}, errors.New("the time is before the first forecast slot")
+	}
+
+	best := slots[0]
+	for _, s := range slots[1:] {
+		if absDuration(s.Time.Sub(target)) < absDuration(best.Time.Sub(target)) {
+			best = s
+		}
+	}
+	return best, nil
+}
+
+// DayOffset returns how many calendar days t is after now, in the time zone
+// of t: 0 for today, 1 for tomorrow.
+func DayOffset(t, now time.Time) int {
+	y, m, d := t.Date()
+	ny, nm, nd := now.In(t.Location()).Date()
+	// noon to noon, so that a DST change does not round the days
+	day := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+	today := time.Date(ny, nm, nd, 12, 0, 0, 0, time.UTC)
+	return int(day.Sub(today).Hours() / 24)
+}
+
+// confidenceFor qualifies a forecast dayOffset days out, so that the LLM
+// can tell how much to trust it: the skill of a forecast drops steadily
+// after the first couple of days.
+func confidenceFor(dayOffset int) string {
+	switch {
+	case dayOffset <= 1:
+		return "high confidence"
+	case dayOffset <= 3:
+		return "medium confidence"
+	default:
+		return "low confidence, the forecast may still change"
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
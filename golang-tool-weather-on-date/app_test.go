@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+// slotsFrom returns n 3-hourly slots starting at start.
+func slotsFrom(start time.Time, n int) []weather.Slot {
+	slots := make([]weather.Slot, n)
+	for i := range slots {
+		slots[i].Time = start.Add(time.Duration(i) * 3 * time.Hour)
+		slots[i].Temperature = float64(i)
+	}
+	return slots
+}
+
+func TestSelectSlot(t *testing.T) {
+	start := time.Date(2024, time.August, 7, 12, 0, 0, 0, time.UTC)
+	slots := slotsFrom(start, 40)
+
+	tests := []struct {
+		name    string
+		target  time.Time
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name:   "exact slot",
+			target: time.Date(2024, time.August, 9, 15, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, time.August, 9, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "rounds down to the closest slot",
+			target: time.Date(2024, time.August, 8, 13, 10, 0, 0, time.UTC),
+			want:   time.Date(2024, time.August, 8, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "rounds up to the closest slot",
+			target: time.Date(2024, time.August, 8, 14, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, time.August, 8, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "just after the last slot",
+			target: slots[39].Time.Add(time.Hour),
+			want:   slots[39].Time,
+		},
+		{
+			name:    "beyond the horizon",
+			target:  slots[39].Time.Add(3 * time.Hour),
+			wantErr: errBeyondHorizon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectSlot(slots, tt.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SelectSlot() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !got.Time.Equal(tt.want) {
+				t.Errorf("SelectSlot() = %v, want %v", got.Time, tt.want)
+			}
+		})
+	}
+
+	if _, err := SelectSlot(nil, start); err == nil {
+		t.Error("SelectSlot() without slots should fail")
+	}
+}
+
+func TestTargetTime(t *testing.T) {
+	paris := time.FixedZone("", 7200)
+	now := time.Date(2024, time.August, 7, 15, 30, 0, 0, paris)
+
+	tests := []struct {
+		name    string
+		date    string
+		clock   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "defaults to midday",
+			date: "2024-08-09",
+			want: time.Date(2024, time.August, 9, 12, 0, 0, 0, paris),
+		},
+		{
+			name:  "with time of day",
+			date:  "2024-08-08",
+			clock: "18:30",
+			want:  time.Date(2024, time.August, 8, 18, 30, 0, 0, paris),
+		},
+		{
+			name: "earlier today is moved to now",
+			date: "2024-08-07",
+			want: now,
+		},
+		{
+			name:    "yesterday",
+			date:    "2024-08-06",
+			wantErr: true,
+		},
+		{
+			name:    "too far out",
+			date:    "2024-08-13",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			date:    "next saturday",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TargetTime(tt.date, tt.clock, paris, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TargetTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("TargetTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfidenceFor(t *testing.T) {
+	want := []string{
+		"high confidence",
+		"high confidence",
+		"medium confidence",
+		"medium confidence",
+		"low confidence, the forecast may still change",
+		"low confidence, the forecast may still change",
+		"low confidence, the forecast may still change",
+		"low confidence, the forecast may still change",
+	}
+	for offset, w := range want {
+		if got := confidenceFor(offset); got != w {
+			t.Errorf("confidenceFor(%d) = %q, want %q", offset, got, w)
+		}
+	}
+}
+
+func TestDayOffset(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// now is 23:30 in Paris, 45 minutes later is already tomorrow
+	now := time.Date(2024, 10, 25, 21, 30, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want int
+	}{
+		{time.Date(2024, 10, 25, 23, 45, 0, 0, paris), 0},
+		{time.Date(2024, 10, 26, 0, 15, 0, 0, paris), 1},
+		// across the end of the DST on 2024-10-27
+		{time.Date(2024, 10, 28, 0, 30, 0, 0, paris), 3},
+		{time.Date(2024, 10, 30, 23, 0, 0, 0, paris), 5},
+	}
+	for _, tt := range tests {
+		if got := DayOffset(tt.t, now); got != tt.want {
+			t.Errorf("DayOffset(%v) = %d, want %d", tt.t, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Get a complete weather report of a location in one call: the current conditions, the UV index, the air quality and the active weather alerts. Use it for a general question like "what is it like outside in Paris?" instead of calling several weather functions. If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format. A part that is unavailable is reported as such, the others are still returned.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-report", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF3}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	if err := geo.ValidateCoordinate(msg.Latitude, msg.Longitude); err != nil {
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather report: %v", err))
+		return
+	}
+
+	// the four upstream calls share one deadline
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	report := Fetch(reqCtx, msg.Latitude, msg.Longitude)
+	for section, err := range report.Failures {
+		slog.Warn("[sfn] report section error", "section", section, "err", err)
+	}
+	result := report.String()
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// The sections of a report, in the order they are written.
+const (
+	sectionCurrent    = "current conditions"
+	sectionUVIndex    = "UV index"
+	sectionAirQuality = "air quality"
+	sectionAlerts     = "weather alerts"
+)
+
+var sections = []string{sectionCurrent, sectionUVIndex, sectionAirQuality, sectionAlerts}
+
+// Report is the weather of a location gathered from several endpoints. A
+// section whose request failed is nil and its error is in Failures.
+type Report struct {
+	Latitude, Longitude float64
+	Current             *weather.Conditions
+	UVIndex             *float64
+	AirQuality          *weather.AirQuality
+	// Alerts is empty when the location is not under a warning, and nil
+	// when they could not be fetched.
+	Alerts   []weather.Alert
+	Failures map[string]error
+}
+
+// Fetch requests the sections of the report at lat,lon concurrently. It
+// always returns a report, a failed request only leaves its section out.
+func Fetch(ctx context.Context, lat, lon float64) *Report {
+	r := &Report{Latitude: lat, Longitude: lon, Failures: make(map[string]error)}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	run := func(section string, fetch func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fetch(); err != nil {
+				mu.Lock()
+				r.Failures[section] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// each request sets its own field of r, only Failures is shared
+	run(sectionCurrent, func() (err error) {
+		r.Current, err = client.Current(ctx, lat, lon)
+		return err
+	})
+	run(sectionUVIndex, func() error {
+		uvi, err := client.UVIndex(ctx, lat, lon)
+		if err == nil {
+			r.UVIndex = &uvi
+		}
+		return err
+	})
+	run(sectionAirQuality, func() (err error) {
+		r.AirQuality, err = client.AirQuality(ctx, lat, lon)
+		return err
+	})
+	run(sectionAlerts, func() (err error) {
+		r.Alerts, err = client.Alerts(ctx, lat, lon)
+		return err
+	})
+	wg.Wait()
+	return r
+}
+
+// String writes the report one section per line, and the failed sections
+// last, e.g.
+//
+//	Weather report for 48.8566,2.3522:
+//	Current conditions: Paris, FR: broken clouds, 19.8°C ...
+//	UV index: 6.2, high risk
+//	Air quality: moderate (index 3 of 5), PM2.5 14.3 μg/m³, PM10 19.8 μg/m³, O3 98.7 μg/m³, NO2 21.4 μg/m³
+//	Weather alerts: none
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Weather report for %v,%v:", r.Latitude, r.Longitude)
+	if r.Current != nil {
+		b.WriteString("\nCurrent conditions: " + r.Current.Summary())
+	}
+	if r.UVIndex != nil {
+		risk, _ := weather.UVRisk(*r.UVIndex)
+		fmt.Fprintf(&b, "\nUV index: %.1f, %s risk", *r.UVIndex, risk)
+	}
+	if aq := r.AirQuality; aq != nil {
+		fmt.Fprintf(&b, "\nAir quality: %s (index %d of 5), PM2.5 %.1f μg/m³, PM10 %.1f μg/m³, O3 %.1f μg/m³, NO2 %.1f μg/m³",
+			aq.Level(), aq.Index, aq.PM25, aq.PM10, aq.O3, aq.NO2)
+	}
+	if r.Failures[sectionAlerts] == nil {
+		b.WriteString("\nWeather alerts: ")
+		if len(r.Alerts) == 0 {
+			b.WriteString("none")
+		}
+		for i, a := range r.Alerts {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			fmt.Fprintf(&b, "%s from %s to %s", a.Event, a.Start.Format("2006-01-02 15:04"), a.End.Format("2006-01-02 15:04"))
+			if a.Sender != "" {
+				b.WriteString(" issued by " + a.Sender)
+			}
+		}
+	}
+
+	var unavailable []string
+	for _, section := range sections {
+		if err := r.Failures[section]; err != nil {
+			unavailable = append(unavailable, fmt.Sprintf("%s (%v)", section, err))
+		}
+	}
+	if len(unavailable) > 0 {
+		b.WriteString("\nUnavailable: " + strings.Join(unavailable, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+const (
+	currentBody = `{"coord":{"lon":2.3522,"lat":48.8566},"weather":[{"id":803,"description":"broken clouds"}],"main":{"temp":19.8,"feels_like":19.56,"pressure":1015,"humidity":66},"wind":{"speed":5.14,"deg":300},"clouds":{"all":75},"sys":{"country":"FR"},"name":"Paris"}`
+	uvBody      = `{"lat":48.8566,"lon":2.3522,"timezone_offset":7200,"current":{"dt":1723024800,"uvi":6.2}}`
+	airBody     = `{"list":[{"main":{"aqi":3},"components":{"no2":21.42,"o3":98.71,"pm2_5":14.35,"pm10":19.84}}]}`
+	alertsBody  = `{"lat":48.8566,"lon":2.3522,"timezone_offset":7200,"alerts":[{"sender_name":"METEO-FRANCE","event":"Moderate thunderstorm warning","start":1723039200,"end":1723082400}]}`
+)
+
+// route answers the requests of a report with the bodies of the sections,
+// a 500 for the sections in failing.
+func route(failing ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		section, body := "", ""
+		switch {
+		case r.URL.Path == "/data/2.5/weather":
+			section, body = sectionCurrent, currentBody
+		case r.URL.Path == "/data/2.5/air_pollution":
+			section, body = sectionAirQuality, airBody
+		case r.URL.Path == "/data/3.0/onecall" && strings.Contains(r.URL.Query().Get("exclude"), "daily"):
+			if strings.Contains(r.URL.Query().Get("exclude"), "current") {
+				section, body = sectionAlerts, alertsBody
+			} else {
+				section, body = sectionUVIndex, uvBody
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		for _, f := range failing {
+			if f == section {
+				http.Error(w, `{"cod":500,"message":"Internal error"}`, http.StatusInternalServerError)
+				return
+			}
+		}
+		w.Write([]byte(body))
+	}
+}
+
+func useServer(t *testing.T, handler http.Handler) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+}
+
+func TestFetch(t *testing.T) {
+	// every request waits for the others, so that a sequential Fetch
+	// would not get past the first one
+	var (
+		mu      sync.Mutex
+		arrived int
+		all     = make(chan struct{})
+	)
+	next := route()
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if arrived++; arrived == len(sections) {
+			close(all)
+		}
+		mu.Unlock()
+		select {
+		case <-all:
+		case <-time.After(2 * time.Second):
+			t.Errorf("%s waited for the other requests, they are not concurrent", r.URL.Path)
+		}
+		next(w, r)
+	}))
+
+	report := Fetch(context.Background(), 48.8566, 2.3522)
+	if len(report.Failures) != 0 {
+		t.Fatalf("Fetch() failures = %v", report.Failures)
+	}
+	if report.Current == nil || report.Current.City != "Paris" {
+		t.Errorf("Fetch() current = %+v", report.Current)
+	}
+	if report.UVIndex == nil || *report.UVIndex != 6.2 {
+		t.Errorf("Fetch() UV index = %v", report.UVIndex)
+	}
+	if report.AirQuality == nil || report.AirQuality.Index != 3 {
+		t.Errorf("Fetch() air quality = %+v", report.AirQuality)
+	}
+	if len(report.Alerts) != 1 {
+		t.Errorf("Fetch() alerts = %+v", report.Alerts)
+	}
+
+	want := `Weather report for 48.8566,2.3522:
+Current conditions: Paris, FR: broken clouds, 19.8°C (feels like 19.6°C), humidity 66%, wind 5.1 m/s, clouds 75%
+UV index: 6.2, high risk
+Air quality: moderate (index 3 of 5), PM2.5 14.3 μg/m³, PM10 19.8 μg/m³, O3 98.7 μg/m³, NO2 21.4 μg/m³
+Weather alerts: Moderate thunderstorm warning from 2024-08-07 16:00 to 2024-08-08 04:00 issued by METEO-FRANCE`
+	if got := report.String(); got != want {
+		t.Errorf("String() = %s\nwant %s", got, want)
+	}
+}
+
+func TestFetchPartialFailure(t *testing.T) {
+	useServer(t, route(sectionUVIndex, sectionAirQuality))
+
+	report := Fetch(context.Background(), 48.8566, 2.3522)
+	if report.Current == nil || report.Alerts == nil {
+		t.Fatalf("Fetch() dropped the sections that succeeded: %+v", report)
+	}
+	if report.UVIndex != nil || report.AirQuality != nil {
+		t.Errorf("Fetch() kept the failed sections: %+v", report)
+	}
+	if len(report.Failures) != 2 || report.Failures[sectionUVIndex] == nil || report.Failures[sectionAirQuality] == nil {
+		t.Errorf("Fetch() failures = %v, want the UV index and the air quality", report.Failures)
+	}
+
+	got := report.String()
+	for _, want := range []string{"Current conditions: Paris, FR", "Weather alerts: Moderate thunderstorm warning", "\nUnavailable: UV index (openweathermap responded 500", ", air quality (openweathermap responded 500"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %s\nmissing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "UV index:") || strings.Contains(got, "Air quality:") {
+		t.Errorf("String() = %s, has a failed section", got)
+	}
+}
+
+func TestFetchNoAlerts(t *testing.T) {
+	useServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("exclude"), "current") {
+			w.Write([]byte(`{"lat":48.8566,"lon":2.3522,"timezone_offset":7200}`))
+			return
+		}
+		route()(w, r)
+	}))
+
+	report := Fetch(context.Background(), 48.8566, 2.3522)
+	if got := report.String(); !strings.HasSuffix(got, "\nWeather alerts: none") {
+		t.Errorf("String() = %s, want no alerts", got)
+	}
+}
+
+func TestFetchAllFailed(t *testing.T) {
+	useServer(t, route(sections...))
+
+	got := Fetch(context.Background(), 48.8566, 2.3522).String()
+	if !strings.HasPrefix(got, "Weather report for 48.8566,2.3522:\nUnavailable: current conditions (") || strings.Count(got, "openweathermap responded 500") != 4 {
+		t.Errorf("String() = %s, want every section unavailable", got)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Tell how the weather of a location develops over the next 12 hours: whether it gets warmer or colder and when rain or snow is expected, e.g. "will it rain later today?" or "does it cool down tonight?". If the city name is given, convert it to Latitude and Longitude geo coordinates in decimal format.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the location in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the location in decimal format,minimum=-180,maximum=180"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-trend", Description: Description(), InputSchema: InputSchema(), Env: []string{weather.KeyEnv}, Upstream: client.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xD8}
+}
+
+var client = weather.NewClient("")
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "lat", msg.Latitude, "lon", msg.Longitude)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := WeatherTrend(reqCtx, msg.Latitude, msg.Longitude, time.Now())
+	if err != nil {
+		slog.Warn("[sfn] WeatherTrend error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not get the weather trend: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// WeatherTrend fetches the forecast at lat,lon and describes the next
+// hours after now.
+func WeatherTrend(ctx context.Context, lat, lon float64, now time.Time) (string, error) {
+	forecast, err := client.Forecast(ctx, lat, lon)
+	if err != nil {
+		return "", err
+	}
+	trend, err := Trend(forecast.Slots, now, forecast.Location())
+	if err != nil {
+		return "", err
+	}
+	if forecast.City == "" {
+		return trend, nil
+	}
+	place := forecast.City
+	if forecast.Country != "" {
+		place += ", " + forecast.Country
+	}
+	return fmt.Sprintf("The next %d hours in %s: %s", int(window.Hours()), place, trend), nil
+}
+
+const (
+	// window is how far ahead the trend looks.
+	window = 12 * time.Hour
+	// slotLength is the step of the forecast, the slot that began up to
+	// slotLength ago is the current one.
+	slotLength = 3 * time.Hour
+	// minChange is the temperature change in °C that counts as a trend.
+	minChange = 2.0
+	// likelyChance is the probability of precipitation from which it is
+	// reported as likely.
+	likelyChance = 0.5
+)
+
+// Trend describes the temperature and the precipitation in the slots
+// covering the window after now, with the times in loc, e.g.
+// "Warming to 24°C around 3 PM, then cooling to 18°C; rain likely around 6 PM."
+func Trend(slots []weather.Slot, now time.Time, loc *time.Location) (string, error) {
+	var next []weather.Slot
+	for _, s := range slots {
+		if s.Time.After(now.Add(-slotLength)) && !s.Time.After(now.Add(window)) {
+			next = append(next, s)
+		}
+	}
+	if len(next) < 2 {
+		return "", errors.New("the forecast does not cover the next hours")
+	}
+	return temperatureTrend(next, loc) + "; " + precipitationTrend(next, loc) + ".", nil
+}
+
+// temperatureTrend reports the first change of at least minChange from the
+// current slot, and a turn of at least minChange after it.
+func temperatureTrend(slots []weather.Slot, loc *time.Location) string {
+	first := slots[0].Temperature
+	hi, lo := 0, 0
+	for i, s := range slots {
+		if s.Temperature > slots[hi].Temperature {
+			hi = i
+		}
+		if s.Temperature < slots[lo].Temperature {
+			lo = i
+		}
+	}
+	rise := slots[hi].Temperature - first
+	fall := first - slots[lo].Temperature
+	if rise < minChange && fall < minChange {
+		return fmt.Sprintf("Temperatures steady around %s", celsius(first))
+	}
+
+	// the extreme reached first sets the direction
+	warming := rise >= minChange && (fall < minChange || hi < lo)
+	turn, verb, back := hi, "Warming", "cooling"
+	if !warming {
+		turn, verb, back = lo, "Cooling", "warming"
+	}
+	peak := slots[turn].Temperature
+	s := fmt.Sprintf("%s to %s around %s", verb, celsius(peak), clock(slots[turn].Time, loc))
+
+	if turn == len(slots)-1 {
+		return s
+	}
+	// the opposite extreme after the turn
+	end := turn + 1
+	for i := turn + 1; i < len(slots); i++ {
+		if (warming && slots[i].Temperature < slots[end].Temperature) || (!warming && slots[i].Temperature > slots[end].Temperature) {
+			end = i
+		}
+	}
+	if math.Abs(slots[end].Temperature-peak) >= minChange {
+		s += fmt.Sprintf(", then %s to %s", back, celsius(slots[end].Temperature))
+	}
+	return s
+}
+
+// precipitationTrend tells when precipitation is expected to start, or to
+// stop when it is already expected in the current slot.
+func precipitationTrend(slots []weather.Slot, loc *time.Location) string {
+	if wet(slots[0]) {
+		kind := precipitation(slots[0])
+		for _, s := range slots[1:] {
+			if !wet(s) {
+				return fmt.Sprintf("%s likely until around %s", kind, clock(s.Time, loc))
+			}
+		}
+		return fmt.Sprintf("%s likely for the next %d hours", kind, int(window.Hours()))
+	}
+	for _, s := range slots[1:] {
+		if wet(s) {
+			return fmt.Sprintf("%s likely around %s", precipitation(s), clock(s.Time, loc))
+		}
+	}
+	return "no rain expected"
+}
+
+// wet reports whether precipitation is likely in the slot.
+func wet(s weather.Slot) bool {
+	return s.PrecipitationChance >= likelyChance || s.Rain3h > 0 || s.Snow3h > 0
+}
+
+// precipitation names the precipitation of a wet slot from its condition
+// code, see https://openweathermap.org/weather-conditions.
+func precipitation(s weather.Slot) string {
+	switch {
+	case s.ConditionID >= 200 && s.ConditionID < 300:
+		return "thunderstorms"
+	case s.ConditionID >= 600 && s.ConditionID < 700, s.Snow3h > s.Rain3h:
+		return "snow"
+	}
+	return "rain"
+}
+
+// clock is the local time of day, e.g. "3 PM", or "5:30 AM" in a time zone
+// with a half hour offset.
+func clock(t time.Time, loc *time.Location) string {
+	t = t.In(loc)
+	if t.Minute() != 0 {
+		return t.Format("3:04 PM")
+	}
+	return t.Format("3 PM")
+}
+
+func celsius(temp float64) string {
+	// adding 0 turns the -0 that math.Round(-0.4) returns into 0
+	return fmt.Sprintf("%.0f°C", math.Round(temp)+0)
+}
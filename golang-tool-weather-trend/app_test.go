@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/weather"
+)
+
+// now is 9:30 in UTC+2, the current slot begins at 8 AM local.
+var (
+	now   = time.Date(2024, 8, 10, 7, 30, 0, 0, time.UTC)
+	local = time.FixedZone("", 2*3600)
+)
+
+// slots returns the 3-hour slots from 6:00 UTC with the given temperatures.
+func slots(temps ...float64) []weather.Slot {
+	s := make([]weather.Slot, len(temps))
+	for i, temp := range temps {
+		s[i].Time = time.Date(2024, 8, 10, 6+3*i, 0, 0, 0, time.UTC)
+		s[i].Temperature = temp
+		s[i].ConditionID = 800
+	}
+	return s
+}
+
+func TestTrend(t *testing.T) {
+	tests := []struct {
+		name  string
+		slots []weather.Slot
+		want  string
+	}{
+		{
+			name:  "rising",
+			slots: slots(15, 18, 22, 24, 23),
+			want:  "Warming to 24°C around 5 PM; no rain expected.",
+		},
+		{
+			name:  "rising then falling",
+			slots: slots(16, 21, 24, 19, 15),
+			want:  "Warming to 24°C around 2 PM, then cooling to 15°C; no rain expected.",
+		},
+		{
+			name:  "falling",
+			slots: slots(12, 9, 5, 3, 2),
+			want:  "Cooling to 2°C around 8 PM; no rain expected.",
+		},
+		{
+			name:  "steady",
+			slots: slots(20, 20.5, 21.4, 20, 19),
+			want:  "Temperatures steady around 20°C; no rain expected.",
+		},
+		{
+			name: "rain onset",
+			slots: func() []weather.Slot {
+				s := slots(18, 20, 19, 17, 16)
+				s[2].PrecipitationChance = 0.8
+				s[2].ConditionID = 500
+				s[3].Rain3h = 1.2
+				return s
+			}(),
+			want: "Warming to 20°C around 11 AM, then cooling to 16°C; rain likely around 2 PM.",
+		},
+		{
+			name: "raining until the evening",
+			slots: func() []weather.Slot {
+				s := slots(10, 10, 11, 10, 10)
+				for i := range s[:3] {
+					s[i].Rain3h = 2
+				}
+				return s
+			}(),
+			want: "Temperatures steady around 10°C; rain likely until around 5 PM.",
+		},
+		{
+			name: "snow all day",
+			slots: func() []weather.Slot {
+				s := slots(-5, -3, -1, -4, -6)
+				for i := range s {
+					s[i].ConditionID = 601
+					s[i].PrecipitationChance = 1
+				}
+				return s
+			}(),
+			want: "Warming to -1°C around 2 PM, then cooling to -6°C; snow likely for the next 12 hours.",
+		},
+		{
+			name: "low chance",
+			slots: func() []weather.Slot {
+				s := slots(20, 20, 20, 20, 20)
+				s[1].PrecipitationChance = 0.3
+				return s
+			}(),
+			want: "Temperatures steady around 20°C; no rain expected.",
+		},
+		{
+			name: "slots outside the window are ignored",
+			// the first slot ended before now, the last one is more
+			// than 12 hours after now
+			slots: func() []weather.Slot {
+				s := slots(0, 15, 15, 15, 15, 15, 30)
+				s[0].Time = s[0].Time.Add(-3 * time.Hour)
+				s[6].PrecipitationChance = 1
+				return s
+			}(),
+			want: "Temperatures steady around 15°C; no rain expected.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Trend(tt.slots, now, local)
+			if err != nil {
+				t.Fatalf("Trend() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Trend() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Trend(slots(20), now, local); err == nil {
+		t.Error("Trend() of a single slot should fail")
+	}
+}
+
+func TestClock(t *testing.T) {
+	at := time.Date(2024, 8, 10, 12, 0, 0, 0, time.UTC)
+	if got := clock(at, time.FixedZone("", 5*3600+1800)); got != "5:30 PM" {
+		t.Errorf("clock() = %s, want 5:30 PM", got)
+	}
+	if got := clock(at, time.UTC); got != "12 PM" {
+		t.Errorf("clock() = %s, want 12 PM", got)
+	}
+}
+
+func TestWeatherTrend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/2.5/forecast" {
+			http.NotFound(w, r)
+			return
+		}
+		var list string
+		for i, temp := range []float64{14, 17, 21, 18, 13} {
+			if i > 0 {
+				list += ","
+			}
+			list += fmt.Sprintf(`{"dt":%d,"main":{"temp":%v},"weather":[{"id":800}],"pop":0}`, now.Add(time.Duration(3*i)*time.Hour).Unix(), temp)
+		}
+		fmt.Fprintf(w, `{"list":[%s],"city":{"name":"Paris","country":"FR","timezone":7200}}`, list)
+	}))
+	defer srv.Close()
+
+	old := client
+	t.Cleanup(func() { client = old })
+	client = weather.NewClient("key")
+	client.BaseURL = srv.URL
+
+	got, err := WeatherTrend(context.Background(), 48.8566, 2.3522, now)
+	if err != nil {
+		t.Fatalf("WeatherTrend() error = %v", err)
+	}
+	want := "The next 12 hours in Paris, FR: Warming to 21°C around 3:30 PM, then cooling to 13°C; no rain expected."
+	if got != want {
+		t.Errorf("WeatherTrend() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert meteorological units. Wind speed can be converted between m/s, km/h, mph and knots. Atmospheric pressure can be converted between hPa, inHg and mmHg. Use it when the user wants the wind or pressure of a weather report in another unit.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Value    float64 `json:"value" jsonschema:"description=The value to convert"`
+	From     string  `json:"from" jsonschema:"description=The unit of the value,enum=m/s,enum=km/h,enum=mph,enum=knots,enum=hPa,enum=inHg,enum=mmHg"`
+	To       string  `json:"to" jsonschema:"description=The unit to convert the value to,enum=m/s,enum=km/h,enum=mph,enum=knots,enum=hPa,enum=inHg,enum=mmHg"`
+	Category string  `json:"category" jsonschema:"description=Whether the value is a wind speed or a pressure,enum=speed,enum=pressure"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weather-units", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xB2}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "data", fmt.Sprintf("%+v", msg))
+
+	converted, err := Convert(msg.Value, msg.From, msg.To, msg.Category)
+	if err != nil {
+		slog.Warn("[sfn] Convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert %g %s to %s: %v", msg.Value, msg.From, msg.To, err))
+		return
+	}
+
+	from, _ := lookupUnit(msg.From)
+	to, _ := lookupUnit(msg.To)
+	sfn.WriteText(ctx, fmt.Sprintf("%g %s is %.2f %s", msg.Value, from.name, converted, to.name))
+}
+
+type unit struct {
+	name     string
+	category string
+	// factor converts one of this unit to the base unit of its category,
+	// m/s for speed and hPa for pressure.
+	factor float64
+}
+
+var units = map[string]unit{
+	"m/s":   {"m/s", "speed", 1},
+	"km/h":  {"km/h", "speed", 1 / 3.6},
+	"mph":   {"mph", "speed", 0.44704},
+	"knots": {"knots", "speed", 1852.0 / 3600},
+	"hpa":   {"hPa", "pressure", 1},
+	"inhg":  {"inHg", "pressure", 33.8638866667},
+	"mmhg":  {"mmHg", "pressure", 1.33322387415},
+}
+
+// aliases maps other common spellings to the keys of units.
+var aliases = map[string]string{
+	"mps":                  "m/s",
+	"meterspersecond":      "m/s",
+	"metrespersecond":      "m/s",
+	"kmh":                  "km/h",
+	"kph":                  "km/h",
+	"kilometersperhour":    "km/h",
+	"kilometresperhour":    "km/h",
+	"milesperhour":         "mph",
+	"kn":                   "knots",
+	"kt":                   "knots",
+	"kts":                  "knots",
+	"knot":                 "knots",
+	"mbar":                 "hpa",
+	"mb":                   "hpa",
+	"millibar":             "hpa",
+	"hectopascal":          "hpa",
+	"inchesofmercury":      "inhg",
+	"millimetersofmercury": "mmhg",
+}
+
+func lookupUnit(name string) (unit, bool) {
+	key := strings.ToLower(strings.Join(strings.Fields(name), ""))
+	if alias, ok := aliases[key]; ok {
+		key = alias
+	}
+	u, ok := units[key]
+	return u, ok
+}
+
+// Convert converts value from one unit to another. If category is given,
+// both units must belong to it.
+func Convert(value float64, from, to, category string) (float64, error) {
+	f, ok := lookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	t, ok := lookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if f.category != t.category {
+		return 0, fmt.Errorf("%s is a %s unit but %s is a %s unit", f.name, f.category, t.name, t.category)
+	}
+	if category != "" && !strings.EqualFold(category, f.category) {
+		return 0, fmt.Errorf("%s and %s are %s units, not %s", f.name, t.name, f.category, category)
+	}
+	if f.category == "speed" && value < 0 {
+		return 0, errors.New("wind speed can not be negative")
+	}
+	if f.category == "pressure" && value <= 0 {
+		return 0, errors.New("pressure must be positive")
+	}
+
+	return value * f.factor / t.factor, nil
+}
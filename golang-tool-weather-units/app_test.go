@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		// wind speed
+		{10, "m/s", "km/h", 36},
+		{10, "m/s", "mph", 22.369363},
+		{10, "m/s", "knots", 19.438445},
+		{36, "km/h", "m/s", 10},
+		{100, "km/h", "mph", 62.137119},
+		{100, "km/h", "knots", 53.995680},
+		{60, "mph", "m/s", 26.8224},
+		{60, "mph", "km/h", 96.56064},
+		{60, "mph", "knots", 52.138877},
+		{20, "knots", "m/s", 10.288889},
+		{20, "knots", "km/h", 37.04},
+		{20, "knots", "mph", 23.015589},
+		// pressure
+		{1013.25, "hPa", "inHg", 29.921252},
+		{1013.25, "hPa", "mmHg", 760},
+		{29.92, "inHg", "hPa", 1013.207489},
+		{29.92, "inHg", "mmHg", 759.968},
+		{760, "mmHg", "hPa", 1013.25},
+		{760, "mmHg", "inHg", 29.921252},
+		// aliases and case
+		{1, "KT", "kph", 1.852},
+		{1000, "mbar", "HPA", 1000},
+		{5, "meters per second", "m/s", 5},
+	}
+
+	for _, tt := range tests {
+		got, err := Convert(tt.value, tt.from, tt.to, "")
+		if err != nil {
+			t.Errorf("Convert(%v, %s, %s) error = %v", tt.value, tt.from, tt.to, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-5*math.Max(1, tt.want) {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name               string
+		value              float64
+		from, to, category string
+	}{
+		{"unknown from unit", 1, "furlongs", "m/s", ""},
+		{"unknown to unit", 1, "m/s", "beaufort", ""},
+		{"mixed categories", 1, "m/s", "hPa", ""},
+		{"wrong category", 1, "m/s", "mph", "pressure"},
+		{"negative speed", -3, "m/s", "mph", "speed"},
+		{"zero pressure", 0, "hPa", "inHg", "pressure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Convert(tt.value, tt.from, tt.to, tt.category); err == nil {
+				t.Errorf("Convert(%v, %s, %s, %s) should fail", tt.value, tt.from, tt.to, tt.category)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/llm-function-calling-examples/internal/stats"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Compute the weighted average of values, e.g. of exam scores weighted by their coefficient. With "gpa" set, compute the grade point average of letter grades from A+ to F weighted by their credits on the 4.0 scale instead. Always use this function instead of computing averages yourself.`
+}
+
+// Entry is a value or a grade and its weight.
+type Entry struct {
+	Value  float64 `json:"value,omitempty" jsonschema:"description=The value to average. Ignored in GPA mode,example=85"`
+	Grade  string  `json:"grade,omitempty" jsonschema:"description=The letter grade in GPA mode,example=B+"`
+	Weight float64 `json:"weight" jsonschema:"description=The weight of the value or the credits of the grade,minimum=0,example=3"`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Entries []Entry `json:"entries" jsonschema:"description=The values or grades with their weights"`
+	GPA     bool    `json:"gpa,omitempty" jsonschema:"description=Average letter grades on the 4.0 scale. Defaults to false"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "weighted-average", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x108}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "entries", len(msg.Entries), "gpa", msg.GPA)
+
+	var result string
+	if msg.GPA {
+		gpa, credits, err := GPA(msg.Entries)
+		if err != nil {
+			slog.Warn("[sfn] GPA error", "err", err)
+			sfn.WriteText(ctx, fmt.Sprintf("can not compute the GPA: %v", err))
+			return
+		}
+		result = fmt.Sprintf("The GPA of the %d grades is %.2f over %g credits", len(msg.Entries), gpa, credits)
+	} else {
+		avg, total, err := WeightedAverage(msg.Entries)
+		if err != nil {
+			slog.Warn("[sfn] WeightedAverage error", "err", err)
+			sfn.WriteText(ctx, fmt.Sprintf("can not compute the weighted average: %v", err))
+			return
+		}
+		result = fmt.Sprintf("The weighted average of the %d values is %s, with a total weight of %g", len(msg.Entries), stats.Format(avg), total)
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// WeightedAverage returns the average of the entry values weighted by their
+// weights, and the total weight.
+func WeightedAverage(entries []Entry) (avg, total float64, err error) {
+	if len(entries) == 0 {
+		return 0, 0, errors.New("there are no values")
+	}
+	var sum float64
+	for i, e := range entries {
+		if math.IsNaN(e.Value) || math.IsInf(e.Value, 0) {
+			return 0, 0, fmt.Errorf("the value of entry %d is not a number", i+1)
+		}
+		if e.Weight < 0 || math.IsNaN(e.Weight) || math.IsInf(e.Weight, 0) {
+			return 0, 0, fmt.Errorf("the weight %v of entry %d must be a number that is not negative", e.Weight, i+1)
+		}
+		sum += e.Value * e.Weight
+		total += e.Weight
+	}
+	if total == 0 {
+		return 0, 0, errors.New("the weights are all 0")
+	}
+	return sum / total, total, nil
+}
+
+// gradePoints are the points of the letter grades on the 4.0 scale.
+var gradePoints = map[string]float64{
+	"A+": 4.0, "A": 4.0, "A-": 3.7,
+	"B+": 3.3, "B": 3.0, "B-": 2.7,
+	"C+": 2.3, "C": 2.0, "C-": 1.7,
+	"D+": 1.3, "D": 1.0, "D-": 0.7,
+	"F": 0,
+}
+
+// Points returns the points of a case-insensitive letter grade.
+func Points(grade string) (float64, error) {
+	g := strings.ToUpper(strings.ReplaceAll(grade, " ", ""))
+	if p, ok := gradePoints[g]; ok {
+		return p, nil
+	}
+	return 0, fmt.Errorf("unknown grade %q, use a letter grade from A+ to F", grade)
+}
+
+// GPA returns the average points of the entry grades weighted by their
+// credits, and the total credits.
+func GPA(entries []Entry) (gpa, credits float64, err error) {
+	points := make([]Entry, len(entries))
+	for i, e := range entries {
+		p, err := Points(e.Grade)
+		if err != nil {
+			return 0, 0, err
+		}
+		points[i] = Entry{Value: p, Weight: e.Weight}
+	}
+	return WeightedAverage(points)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWeightedAverage(t *testing.T) {
+	tests := []struct {
+		name      string
+		entries   []Entry
+		want      float64
+		wantTotal float64
+	}{
+		{"equal weights", []Entry{{Value: 10, Weight: 1}, {Value: 20, Weight: 1}}, 15, 2},
+		{"weighted", []Entry{{Value: 80, Weight: 0.3}, {Value: 90, Weight: 0.5}, {Value: 70, Weight: 0.2}}, 83, 1},
+		{"zero weight ignored", []Entry{{Value: 50, Weight: 2}, {Value: 1000, Weight: 0}}, 50, 2},
+		{"negative values", []Entry{{Value: -4, Weight: 3}, {Value: 2, Weight: 1}}, -2.5, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := WeightedAverage(tt.entries)
+			if err != nil {
+				t.Fatalf("WeightedAverage() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 || math.Abs(total-tt.wantTotal) > 1e-9 {
+				t.Errorf("WeightedAverage() = %v, %v, want %v, %v", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestWeightedAverageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		want    string
+	}{
+		{"empty", nil, "no values"},
+		{"negative weight", []Entry{{Value: 1, Weight: 1}, {Value: 2, Weight: -1}}, "weight -1 of entry 2"},
+		{"all zero", []Entry{{Value: 1}, {Value: 2}}, "all 0"},
+		{"not a number", []Entry{{Value: math.NaN(), Weight: 1}}, "not a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := WeightedAverage(tt.entries)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("WeightedAverage() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoints(t *testing.T) {
+	tests := map[string]float64{"A+": 4, "a": 4, "A-": 3.7, "b +": 3.3, "B": 3, "C-": 1.7, "D+": 1.3, "f": 0}
+	for grade, want := range tests {
+		got, err := Points(grade)
+		if err != nil || got != want {
+			t.Errorf("Points(%q) = %v, %v, want %v", grade, got, err, want)
+		}
+	}
+	if _, err := Points("E"); err == nil {
+		t.Error(`Points("E") error = nil`)
+	}
+}
+
+func TestGPA(t *testing.T) {
+	gpa, credits, err := GPA([]Entry{{Grade: "A-", Weight: 4}, {Grade: "B+", Weight: 3}, {Grade: "C", Weight: 2}})
+	if err != nil {
+		t.Fatalf("GPA() error = %v", err)
+	}
+	// (3.7*4 + 3.3*3 + 2*2) / 9
+	if math.Abs(gpa-28.7/9) > 1e-9 || credits != 9 {
+		t.Errorf("GPA() = %v, %v, want %v, 9", gpa, credits, 28.7/9)
+	}
+
+	if _, _, err := GPA([]Entry{{Grade: "A", Weight: 3}, {Grade: "Z", Weight: 3}}); err == nil || !strings.Contains(err.Error(), `unknown grade "Z"`) {
+		t.Errorf("GPA() with an unknown grade error = %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert a wind direction or a bearing in degrees to the nearest of the 16 compass points, e.g. "a wind from 200°" is SSW, south-southwest. A wind direction in degrees is where the wind blows from. The function returns the abbreviation and the name of the compass point.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Degrees float64 `json:"degrees" jsonschema:"description=The direction in degrees clockwise from north. Values outside 0 to 360 wrap around,example=200"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "wind-direction", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xF2}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "degrees", msg.Degrees)
+
+	result, err := Describe(msg.Degrees)
+	if err != nil {
+		slog.Warn("[sfn] Describe error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert the direction: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result", result)
+	sfn.WriteText(ctx, result)
+}
+
+// names are the names of geo.CompassPoints.
+var names = []string{
+	"north", "north-northeast", "northeast", "east-northeast",
+	"east", "east-southeast", "southeast", "south-southeast",
+	"south", "south-southwest", "southwest", "west-southwest",
+	"west", "west-northwest", "northwest", "north-northwest",
+}
+
+// Direction returns the compass point nearest to degrees and its name, e.g.
+// "SSW" and "south-southwest" for 200°.
+func Direction(degrees float64) (point, name string) {
+	i := geo.CompassPoint(degrees)
+	return geo.CompassPoints[i], names[i]
+}
+
+// Describe phrases the compass point of degrees, e.g. "200° is SSW
+// (south-southwest)". A direction outside 0 to 360 is shown normalized too.
+func Describe(degrees float64) (string, error) {
+	if math.IsNaN(degrees) || math.IsInf(degrees, 0) {
+		return "", fmt.Errorf("%v is not a direction", degrees)
+	}
+	point, name := Direction(degrees)
+	s := format(degrees)
+	if normalized := math.Mod(math.Mod(degrees, 360)+360, 360); normalized != degrees {
+		s += " (" + format(normalized) + ")"
+	}
+	return fmt.Sprintf("%s is %s (%s)", s, point, name), nil
+}
+
+func format(degrees float64) string {
+	return strconv.FormatFloat(degrees, 'f', -1, 64) + "°"
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDirectionBoundaries(t *testing.T) {
+	// each sector spans 22.5° centered on its point, a boundary belongs to
+	// the clockwise sector
+	want := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	for i, point := range want {
+		center := float64(i) * 22.5
+		start := center - 11.25
+		for _, degrees := range []float64{center, start, start + 0.01, center + 11.24} {
+			if got, _ := Direction(degrees); got != point {
+				t.Errorf("Direction(%v) = %s, want %s", degrees, got, point)
+			}
+		}
+		if got, _ := Direction(start - 0.01); got != want[(i+15)%16] {
+			t.Errorf("Direction(%v) = %s, want %s", start-0.01, got, want[(i+15)%16])
+		}
+	}
+}
+
+func TestDirectionWraps(t *testing.T) {
+	tests := []struct {
+		degrees float64
+		want    string
+	}{
+		{360, "N"}, {359.99, "N"}, {370, "N"}, {450, "E"}, {-45, "NW"}, {-180, "S"}, {-360, "N"}, {1e6, "W"},
+	}
+	for _, tt := range tests {
+		if got, _ := Direction(tt.degrees); got != tt.want {
+			t.Errorf("Direction(%v) = %s, want %s", tt.degrees, got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		degrees float64
+		want    string
+	}{
+		{200, "200° is SSW (south-southwest)"},
+		{0, "0° is N (north)"},
+		{292.5, "292.5° is WNW (west-northwest)"},
+		{-90, "-90° (270°) is W (west)"},
+		{405, "405° (45°) is NE (northeast)"},
+	}
+	for _, tt := range tests {
+		got, err := Describe(tt.degrees)
+		if err != nil || got != tt.want {
+			t.Errorf("Describe(%v) = %s, %v, want %s", tt.degrees, got, err, tt.want)
+		}
+	}
+
+	for _, degrees := range []float64{math.NaN(), math.Inf(1)} {
+		if _, err := Describe(degrees); err == nil {
+			t.Errorf("Describe(%v) should fail", degrees)
+		}
+	}
+}
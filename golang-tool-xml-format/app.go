@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Check that an XML document is well-formed and pretty-print it with an indentation of two spaces, minify it by removing the whitespace between its tags, or only validate it. The attributes and namespace prefixes are kept as written. An invalid document is reported with the line and column of the error.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	XML  string `json:"xml" jsonschema:"description=The XML document"`
+	Mode string `json:"mode,omitempty" jsonschema:"description=What to do with the document. Defaults to pretty,enum=pretty,enum=minify,enum=validate"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "xml-format", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x10A}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "xml_length", len(msg.XML), "mode", msg.Mode)
+
+	result, err := Format(msg.XML, msg.Mode)
+	if err != nil {
+		slog.Warn("[sfn] Format error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not format the XML: %v", err))
+		return
+	}
+
+	slog.Info("[sfn] >> result", "result_length", len(result))
+	sfn.WriteText(ctx, result)
+}
+
+// Format parses doc and writes it in mode: "pretty", the default, "minify" or
+// "validate".
+func Format(doc, mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	switch mode {
+	case "", "pretty", "minify", "validate":
+	default:
+		return "", fmt.Errorf("unknown mode %q, use pretty, minify or validate", mode)
+	}
+
+	nodes, err := Parse(doc)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	switch mode {
+	case "validate":
+		root := rootElement(nodes)
+		fmt.Fprintf(&b, "The XML is well-formed: the root element is <%s> with %d elements in all", root.Name, root.count())
+	case "minify":
+		for _, n := range nodes {
+			n.compact(&b)
+		}
+	default:
+		for _, n := range nodes {
+			n.pretty(&b, 0)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// Node is a node of a parsed document.
+type Node struct {
+	// Kind is an xml.StartElement for an element, or the other token the
+	// node is: xml.CharData, xml.Comment, xml.ProcInst or xml.Directive.
+	Kind any
+	// Name is the qualified name of an element, with its prefix as
+	// written, e.g. "soap:Envelope".
+	Name     string
+	Attrs    []xml.Attr
+	Children []*Node
+}
+
+// Parse reads the nodes of doc without resolving its namespaces, so that
+// the prefixes are kept as written. The document must have one root
+// element and no text outside of it.
+func Parse(doc string) ([]*Node, error) {
+	d := xml.NewDecoder(strings.NewReader(doc))
+	d.Strict = true
+
+	var top []*Node
+	var stack []*Node
+	add := func(n *Node) {
+		if len(stack) == 0 {
+			top = append(top, n)
+			return
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, n)
+	}
+	// the structural errors are reported at the start of their token, the
+	// syntax errors where the decoder stopped
+	var line, col int
+	fail := func(msg string) error {
+		return fmt.Errorf("line %d, column %d: %s", line, col, msg)
+	}
+
+	roots := 0
+	for {
+		line, col = d.InputPos()
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line, col = d.InputPos()
+			var se *xml.SyntaxError
+			if errors.As(err, &se) {
+				return nil, fail(se.Msg)
+			}
+			return nil, fail(err.Error())
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 {
+				if roots++; roots > 1 {
+					return nil, fail("the document has more than one root element")
+				}
+			}
+			n := &Node{Kind: t, Name: qualified(t.Name), Attrs: t.Attr}
+			add(n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			name := qualified(t.Name)
+			if len(stack) == 0 {
+				return nil, fail(fmt.Sprintf("unexpected end element </%s>", name))
+			}
+			if open := stack[len(stack)-1].Name; open != name {
+				return nil, fail(fmt.Sprintf("element <%s> closed by </%s>", open, name))
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 0 {
+				if len(bytes.TrimSpace(t)) > 0 {
+					return nil, fail("text outside of the root element")
+				}
+				continue
+			}
+			add(&Node{Kind: t.Copy()})
+		case xml.Comment:
+			add(&Node{Kind: t.Copy()})
+		case xml.ProcInst:
+			add(&Node{Kind: t.Copy()})
+		case xml.Directive:
+			add(&Node{Kind: t.Copy()})
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fail(fmt.Sprintf("element <%s> is not closed", stack[len(stack)-1].Name))
+	}
+	if roots == 0 {
+		return nil, errors.New("the document has no root element")
+	}
+	return top, nil
+}
+
+func qualified(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// rootElement returns the element among the top nodes.
+func rootElement(nodes []*Node) *Node {
+	for _, n := range nodes {
+		if _, ok := n.Kind.(xml.StartElement); ok {
+			return n
+		}
+	}
+	return nil
+}
+
+// count returns the number of elements of the subtree of n, n included.
+func (n *Node) count() int {
+	if _, ok := n.Kind.(xml.StartElement); !ok {
+		return 0
+	}
+	total := 1
+	for _, c := range n.Children {
+		total += c.count()
+	}
+	return total
+}
+
+// isBlank reports whether n is whitespace-only text.
+func (n *Node) isBlank() bool {
+	text, ok := n.Kind.(xml.CharData)
+	return ok && len(bytes.TrimSpace(text)) == 0
+}
+
+// hasText reports whether the children of n include text that is not
+// whitespace, whose whitespace may matter.
+func (n *Node) hasText() bool {
+	for _, c := range n.Children {
+		if _, ok := c.Kind.(xml.CharData); ok && !c.isBlank() {
+			return true
+		}
+	}
+	return false
+}
+
+// pretty writes n indented by depth, one child per line. An element with
+// text, e.g. mixed content, is written compactly on its line, with its text
+// as is.
+func (n *Node) pretty(b *strings.Builder, depth int) {
+	if n.isBlank() {
+		return
+	}
+	indent := strings.Repeat("  ", depth)
+	b.WriteString(indent)
+
+	children := 0
+	for _, c := range n.Children {
+		if !c.isBlank() {
+			children++
+		}
+	}
+	if _, ok := n.Kind.(xml.StartElement); !ok || children == 0 || n.hasText() {
+		n.compact(b)
+		b.WriteString("\n")
+		return
+	}
+
+	n.writeStart(b, false)
+	b.WriteString("\n")
+	for _, c := range n.Children {
+		c.pretty(b, depth+1)
+	}
+	b.WriteString(indent + "</" + n.Name + ">\n")
+}
+
+// compact writes n without the whitespace-only text between the tags of
+// its element children.
+func (n *Node) compact(b *strings.Builder) {
+	switch t := n.Kind.(type) {
+	case xml.CharData:
+		escape(b, string(t), false)
+	case xml.Comment:
+		b.WriteString("<!--" + string(t) + "-->")
+	case xml.ProcInst:
+		b.WriteString("<?" + t.Target)
+		if len(t.Inst) > 0 {
+			b.WriteString(" " + string(t.Inst))
+		}
+		b.WriteString("?>")
+	case xml.Directive:
+		b.WriteString("<!" + string(t) + ">")
+	case xml.StartElement:
+		if len(n.Children) == 0 {
+			n.writeStart(b, true)
+			return
+		}
+		n.writeStart(b, false)
+		text := n.hasText()
+		for _, c := range n.Children {
+			if c.isBlank() && !text {
+				continue
+			}
+			c.compact(b)
+		}
+		b.WriteString("</" + n.Name + ">")
+	}
+}
+
+func (n *Node) writeStart(b *strings.Builder, empty bool) {
+	b.WriteString("<" + n.Name)
+	for _, a := range n.Attrs {
+		b.WriteString(" " + qualified(a.Name) + `="`)
+		escape(b, a.Value, true)
+		b.WriteString(`"`)
+	}
+	if empty {
+		b.WriteString("/>")
+		return
+	}
+	b.WriteString(">")
+}
+
+// escape writes s with the characters that are markup escaped, and in an
+// attribute value the quote and the whitespace the parser would normalize.
+func escape(b *strings.Builder, s string, attr bool) {
+	for _, r := range s {
+		switch {
+		case r == '&':
+			b.WriteString("&amp;")
+		case r == '<':
+			b.WriteString("&lt;")
+		case r == '>':
+			b.WriteString("&gt;")
+		case attr && r == '"':
+			b.WriteString("&quot;")
+		case attr && r == '\n':
+			b.WriteString("&#xA;")
+		case attr && r == '\t':
+			b.WriteString("&#x9;")
+		case r == '\r':
+			b.WriteString("&#xD;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const envelope = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:m="https://example.com/stock">
+      <soap:Body><m:GetPrice  m:currency="EUR" note="a &quot;quoted&quot; &amp; escaped value"><m:Item>Apple &amp; Pear</m:Item>
+<m:Empty></m:Empty>
+      <!-- the quantity -->
+<m:Qty>3</m:Qty></m:GetPrice></soap:Body>
+</soap:Envelope>
+`
+
+func TestFormatPretty(t *testing.T) {
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:m="https://example.com/stock">
+  <soap:Body>
+    <m:GetPrice m:currency="EUR" note="a &quot;quoted&quot; &amp; escaped value">
+      <m:Item>Apple &amp; Pear</m:Item>
+      <m:Empty/>
+      <!-- the quantity -->
+      <m:Qty>3</m:Qty>
+    </m:GetPrice>
+  </soap:Body>
+</soap:Envelope>`
+	got, err := Format(envelope, "pretty")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Format(pretty) =\n%s\nwant\n%s", got, want)
+	}
+
+	// the default mode, and formatting again changes nothing
+	if again, err := Format(got, ""); err != nil || again != want {
+		t.Errorf("Format(Format(pretty)) =\n%s\n%v", again, err)
+	}
+}
+
+func TestFormatMinify(t *testing.T) {
+	want := `<?xml version="1.0" encoding="UTF-8"?><soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:m="https://example.com/stock"><soap:Body><m:GetPrice m:currency="EUR" note="a &quot;quoted&quot; &amp; escaped value"><m:Item>Apple &amp; Pear</m:Item><m:Empty/><!-- the quantity --><m:Qty>3</m:Qty></m:GetPrice></soap:Body></soap:Envelope>`
+	got, err := Format(envelope, "minify")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Format(minify) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatMixedContent(t *testing.T) {
+	doc := "<doc>\n  <p>Some <b>bold</b> text</p>\n</doc>"
+	want := "<doc>\n  <p>Some <b>bold</b> text</p>\n</doc>"
+	if got, err := Format(doc, "pretty"); err != nil || got != want {
+		t.Errorf("Format(pretty) = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestFormatValidate(t *testing.T) {
+	got, err := Format(envelope, "validate")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "The XML is well-formed: the root element is <soap:Envelope> with 6 elements in all"; got != want {
+		t.Errorf("Format(validate) = %q, want %q", got, want)
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"mismatched", "<a>\n  <b>text</c>\n</a>", "line 2, column 10: element <b> closed by </c>"},
+		{"not closed", "<a><b></b>", "element <a> is not closed"},
+		{"two roots", "<a/><b/>", "more than one root element"},
+		{"text outside", "<a/>trailing", "text outside of the root element"},
+		{"bad attribute", `<a x=1/>`, "line 1"},
+		{"bad entity", "<a>&nbsp;</a>", "line 1"},
+		{"empty", "  ", "no root element"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Format(tt.doc, "validate")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Format() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Format("<a/>", "beautify"); err == nil {
+		t.Error("Format() with an unknown mode error = nil")
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+	"gopkg.in/yaml.v3"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Convert YAML to JSON or JSON to YAML, keeping the order of the keys. With mode "yaml2json" the function returns indented JSON, a YAML stream of several documents becomes a JSON array of them. With mode "json2yaml" it returns YAML indented by two spaces. Pass the input text unchanged, including its indentation and line breaks.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Input string `json:"input" jsonschema:"description=The YAML or JSON text to convert"`
+	Mode  string `json:"mode" jsonschema:"description=The direction of the conversion,enum=yaml2json,enum=json2yaml"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "yaml-json", Description: Description(), InputSchema: InputSchema()})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0x10B}
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "mode", msg.Mode, "input_bytes", len(msg.Input))
+
+	var (
+		result string
+		err    error
+	)
+	switch strings.ToLower(msg.Mode) {
+	case "yaml2json":
+		result, err = YAMLToJSON(msg.Input)
+	case "json2yaml":
+		result, err = JSONToYAML(msg.Input)
+	default:
+		err = fmt.Errorf("unknown mode %q, it must be yaml2json or json2yaml", msg.Mode)
+	}
+	if err != nil {
+		slog.Warn("[sfn] convert error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not convert the input: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, result)
+}
+
+// YAMLToJSON converts the documents of a YAML stream to indented JSON, the
+// keys of the mappings in their order. The aliases are expanded and the merge
+// keys "<<" applied. Several documents are converted to a JSON array.
+func YAMLToJSON(input string) (string, error) {
+	d := yaml.NewDecoder(strings.NewReader(input))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		err := d.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid YAML: %s", strings.TrimPrefix(err.Error(), "yaml: "))
+		}
+		docs = append(docs, &doc)
+	}
+	if len(docs) == 0 {
+		return "", errors.New("the YAML has no document")
+	}
+
+	var b bytes.Buffer
+	e := &expander{active: map[*yaml.Node]bool{}}
+	if len(docs) > 1 {
+		b.WriteByte('[')
+	}
+	for i, doc := range docs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := e.writeJSON(&b, doc); err != nil {
+			return "", err
+		}
+	}
+	if len(docs) > 1 {
+		b.WriteByte(']')
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, b.Bytes(), "", "  "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+const (
+	// maxNodes bounds the nodes written or merged once the aliases are
+	// expanded, which a few nested aliases make exponential.
+	maxNodes = 1_000_000
+	// maxJSONBytes bounds the JSON written before its indentation.
+	maxJSONBytes = 4 << 20
+)
+
+// expander expands the aliases of a YAML stream to JSON. It rejects an
+// anchored node that contains an alias of itself, which would expand
+// forever, and stops past maxNodes or maxJSONBytes.
+type expander struct {
+	// active are the anchored nodes being expanded.
+	active map[*yaml.Node]bool
+	nodes  int
+}
+
+// enter marks the anchored node n as being expanded, until leave is called.
+func (e *expander) enter(n *yaml.Node) (leave func(), err error) {
+	if n.Anchor == "" {
+		return func() {}, nil
+	}
+	if e.active[n] {
+		return nil, fmt.Errorf("line %d: the anchor &%s contains an alias of itself", n.Line, n.Anchor)
+	}
+	e.active[n] = true
+	return func() { delete(e.active, n) }, nil
+}
+
+// count counts a node of the expansion against maxNodes.
+func (e *expander) count() error {
+	if e.nodes++; e.nodes > maxNodes {
+		return fmt.Errorf("the YAML expands to more than %d nodes, its aliases are repeated too much", maxNodes)
+	}
+	return nil
+}
+
+// writeJSON writes the JSON of the YAML node n to b.
+func (e *expander) writeJSON(b *bytes.Buffer, n *yaml.Node) error {
+	if err := e.count(); err != nil {
+		return err
+	}
+	if b.Len() > maxJSONBytes {
+		return fmt.Errorf("the YAML expands to more than %d bytes of JSON", maxJSONBytes)
+	}
+	leave, err := e.enter(n)
+	if err != nil {
+		return err
+	}
+	defer leave()
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			b.WriteString("null")
+			return nil
+		}
+		return e.writeJSON(b, n.Content[0])
+	case yaml.AliasNode:
+		return e.writeJSON(b, n.Alias)
+	case yaml.SequenceNode:
+		b.WriteByte('[')
+		for i, c := range n.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := e.writeJSON(b, c); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		pairs, err := e.mappingPairs(n)
+		if err != nil {
+			return err
+		}
+		b.WriteByte('{')
+		for i, p := range pairs {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(p.key)
+			b.Write(key)
+			b.WriteByte(':')
+			if err := e.writeJSON(b, p.value); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+		return nil
+	case yaml.ScalarNode:
+		return writeScalar(b, n)
+	}
+	return fmt.Errorf("line %d: unsupported YAML node", n.Line)
+}
+
+type pair struct {
+	key   string
+	value *yaml.Node
+}
+
+// mappingPairs returns the pairs of the mapping n in their order, with the
+// pairs of its merge keys that it does not define itself.
+func (e *expander) mappingPairs(n *yaml.Node) ([]pair, error) {
+	var pairs, merged []pair
+	seen := map[string]int{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if err := e.count(); err != nil {
+			return nil, err
+		}
+		k, v := resolve(n.Content[i]), n.Content[i+1]
+		if k.Kind == yaml.ScalarNode && k.ShortTag() == "!!merge" {
+			for _, m := range mergeSources(v) {
+				more, err := e.mergedPairs(m)
+				if err != nil {
+					return nil, err
+				}
+				merged = append(merged, more...)
+			}
+			continue
+		}
+		if k.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: a JSON key must be a string, not a YAML collection", k.Line)
+		}
+		if j, ok := seen[k.Value]; ok {
+			pairs[j].value = v
+			continue
+		}
+		seen[k.Value] = len(pairs)
+		pairs = append(pairs, pair{key: k.Value, value: v})
+	}
+	for _, p := range merged {
+		if _, ok := seen[p.key]; !ok {
+			seen[p.key] = len(pairs)
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs, nil
+}
+
+// mergedPairs returns the pairs of the mapping m merged by a "<<" key.
+func (e *expander) mergedPairs(m *yaml.Node) ([]pair, error) {
+	leave, err := e.enter(m)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	return e.mappingPairs(m)
+}
+
+// mergeSources returns the mappings merged by a "<<" key with value v, a
+// mapping or a sequence of mappings.
+func mergeSources(v *yaml.Node) []*yaml.Node {
+	v = resolve(v)
+	if v.Kind == yaml.MappingNode {
+		return []*yaml.Node{v}
+	}
+	var sources []*yaml.Node
+	if v.Kind == yaml.SequenceNode {
+		for _, c := range v.Content {
+			if c = resolve(c); c.Kind == yaml.MappingNode {
+				sources = append(sources, c)
+			}
+		}
+	}
+	return sources
+}
+
+// resolve follows the aliases of n.
+func resolve(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// writeScalar writes a scalar as the JSON value of its resolved YAML tag,
+// e.g. 0x1F as 31. The timestamps stay strings, as written.
+func writeScalar(b *bytes.Buffer, n *yaml.Node) error {
+	switch n.ShortTag() {
+	case "!!null":
+		b.WriteString("null")
+		return nil
+	case "!!bool":
+		var v bool
+		if err := n.Decode(&v); err != nil {
+			return fmt.Errorf("line %d: %v", n.Line, err)
+		}
+		b.WriteString(strconv.FormatBool(v))
+		return nil
+	case "!!int":
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return fmt.Errorf("line %d: %v", n.Line, err)
+		}
+		fmt.Fprint(b, v)
+		return nil
+	case "!!float":
+		var v float64
+		if err := n.Decode(&v); err != nil {
+			return fmt.Errorf("line %d: %v", n.Line, err)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("line %d: %s has no JSON equivalent", n.Line, n.Value)
+		}
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		return nil
+	}
+	s, _ := json.Marshal(n.Value)
+	b.Write(s)
+	return nil
+}
+
+// JSONToYAML converts a JSON value to YAML indented by two spaces, the keys
+// of the objects in their order.
+func JSONToYAML(input string) (string, error) {
+	d := json.NewDecoder(strings.NewReader(input))
+	d.UseNumber()
+	n, err := yamlNode(d)
+	if err != nil {
+		return "", jsonError(input, d, err)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return "", jsonError(input, d, errors.New("unexpected data after the JSON value"))
+	}
+
+	var b strings.Builder
+	e := yaml.NewEncoder(&b)
+	e.SetIndent(2)
+	if err := e.Encode(n); err != nil {
+		return "", err
+	}
+	if err := e.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// yaml11Bools are the strings that YAML 1.1 parsers read as booleans. They
+// are plain strings in YAML 1.2, quoted so that both versions agree.
+var yaml11Bools = map[string]bool{"y": true, "yes": true, "n": true, "no": true, "on": true, "off": true}
+
+// yamlNode reads the next JSON value of d as a YAML node.
+func yamlNode(d *json.Decoder) (*yaml.Node, error) {
+	tok, err := d.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("the JSON is empty or incomplete")
+		}
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for d.More() {
+				key, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := yamlNode(d)
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)}, value)
+			}
+			_, err := d.Token()
+			return n, err
+		case '[':
+			n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for d.More() {
+				value, err := yamlNode(d)
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, value)
+			}
+			_, err := d.Token()
+			return n, err
+		}
+		return nil, fmt.Errorf("unexpected %q", t)
+	case string:
+		n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+		if yaml11Bools[strings.ToLower(t)] {
+			n.Style = yaml.DoubleQuotedStyle
+		}
+		return n, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", tok)
+}
+
+// jsonError prefixes err with the line and column of input the decoder d
+// stopped at.
+func jsonError(input string, d *json.Decoder, err error) error {
+	offset := d.InputOffset()
+	var se *json.SyntaxError
+	if errors.As(err, &se) {
+		offset = se.Offset
+	}
+	offset = min(offset, int64(len(input)))
+	before := input[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := offset - int64(strings.LastIndex(before, "\n"))
+	return fmt.Errorf("invalid JSON at line %d, column %d: %v", line, col, err)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const config = `# service configuration
+name: demo
+version: 1.10
+replicas: 3
+enabled: true
+owner: ~
+ports: [80, 443]
+zone: "yes"
+released: 2024-06-10
+env:
+  - name: MODE
+    value: production
+  - name: EMPTY
+    value: ""
+limits:
+  memory: 512Mi
+  cpu: 0.5
+`
+
+func TestYAMLToJSON(t *testing.T) {
+	want := `{
+  "name": "demo",
+  "version": 1.1,
+  "replicas": 3,
+  "enabled": true,
+  "owner": null,
+  "ports": [
+    80,
+    443
+  ],
+  "zone": "yes",
+  "released": "2024-06-10",
+  "env": [
+    {
+      "name": "MODE",
+      "value": "production"
+    },
+    {
+      "name": "EMPTY",
+      "value": ""
+    }
+  ],
+  "limits": {
+    "memory": "512Mi",
+    "cpu": 0.5
+  }
+}`
+	got, err := YAMLToJSON(config)
+	if err != nil {
+		t.Fatalf("YAMLToJSON() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("YAMLToJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestYAMLToJSONAliasesAndDocuments(t *testing.T) {
+	input := `defaults: &defaults
+  adapter: postgres
+  host: localhost
+development:
+  <<: *defaults
+  host: dev.local
+  database: dev
+---
+second: document
+`
+	want := `[
+  {
+    "defaults": {
+      "adapter": "postgres",
+      "host": "localhost"
+    },
+    "development": {
+      "host": "dev.local",
+      "database": "dev",
+      "adapter": "postgres"
+    }
+  },
+  {
+    "second": "document"
+  }
+]`
+	got, err := YAMLToJSON(input)
+	if err != nil {
+		t.Fatalf("YAMLToJSON() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("YAMLToJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	input := `{"name":"demo","zone":"yes","switch":"Off","count":"12","replicas":3,"ratio":0.25,"tags":["a","b"],"nested":{"empty":{},"none":[],"null":null,"multi":"line one\nline two"}}`
+	want := `name: demo
+zone: "yes"
+switch: "Off"
+count: "12"
+replicas: 3
+ratio: 0.25
+tags:
+  - a
+  - b
+nested:
+  empty: {}
+  none: []
+  "null": null
+  multi: |-
+    line one
+    line two
+`
+	got, err := JSONToYAML(input)
+	if err != nil {
+		t.Fatalf("JSONToYAML() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("JSONToYAML() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	asJSON, err := YAMLToJSON(config)
+	if err != nil {
+		t.Fatalf("YAMLToJSON() error = %v", err)
+	}
+	asYAML, err := JSONToYAML(asJSON)
+	if err != nil {
+		t.Fatalf("JSONToYAML() error = %v", err)
+	}
+	again, err := YAMLToJSON(asYAML)
+	if err != nil {
+		t.Fatalf("YAMLToJSON() of the converted YAML error = %v", err)
+	}
+	if again != asJSON {
+		t.Errorf("the round trip changed the JSON:\n%s\nwant\n%s", again, asJSON)
+	}
+}
+
+// billionLaughs expands to 10^7 strings in 7 levels of 10 aliases.
+var billionLaughs = func() string {
+	var b strings.Builder
+	b.WriteString("l0: &l0 lol\n")
+	for i := 1; i <= 7; i++ {
+		fmt.Fprintf(&b, "l%d: &l%d [%s]\n", i, i, strings.TrimSuffix(strings.Repeat(fmt.Sprintf("*l%d, ", i-1), 10), ", "))
+	}
+	return b.String()
+}()
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func(string) (string, error)
+		input   string
+		want    string
+	}{
+		{"bad indentation", YAMLToJSON, "a:\n  b: 1\n c: 2\n", "invalid YAML: line 2"},
+		{"unclosed flow", YAMLToJSON, "ports: [80, 443\n", "invalid YAML"},
+		{"collection key", YAMLToJSON, "? [a, b]\n: value\n", "line 1: a JSON key must be a string"},
+		{"infinity", YAMLToJSON, "x: .inf\n", "has no JSON equivalent"},
+		{"empty yaml", YAMLToJSON, "", "no document"},
+		{"recursive alias", YAMLToJSON, "a: &a [*a]\n", "line 1: the anchor &a contains an alias of itself"},
+		{"recursive merge", YAMLToJSON, "a: &a {b: 1, <<: *a}\n", "line 1: the anchor &a contains an alias of itself"},
+		{"billion laughs", YAMLToJSON, billionLaughs, "aliases are repeated too much"},
+		{"large expansion", YAMLToJSON, "a: &a " + strings.Repeat("x", 64<<10) + "\nb: [" + strings.Repeat("*a, ", 100) + "*a]\n", "more than 4194304 bytes of JSON"},
+		{"missing comma", JSONToYAML, "{\n  \"a\": 1\n  \"b\": 2\n}", "invalid JSON at line 3"},
+		{"trailing data", JSONToYAML, `{"a":1} {"b":2}`, "unexpected data after the JSON value"},
+		{"incomplete", JSONToYAML, `{"a":`, "incomplete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.convert(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Description outlines the functionality for the LLM Function Calling feature.
+// It provides a detailed description of the function's purpose, essential for
+// integration with LLM Function Calling. The presence of this function and its
+// return value make the function discoverable and callable within the LLM
+// ecosystem. For more information on Function Calling, refer to the OpenAI
+// documentation at: https://platform.openai.com/docs/guides/function-calling
+func Description() string {
+	return `Look up a postal code or ZIP code, e.g. "where is 90210?". Give the code and the 2-letter ISO country code of the country it belongs to, US when the user gives no hint. The function returns the places of the code with their region and coordinates.`
+}
+
+// Parameter defines the arguments for the LLM Function Calling.
+type Parameter struct {
+	Code    string `json:"code" jsonschema:"description=The postal code e.g. 90210 or 10117"`
+	Country string `json:"country" jsonschema:"description=The ISO 3166-1 alpha-2 country code e.g. US or DE,example=US"`
+}
+
+// InputSchema defines the argument structure for LLM Function Calling. It
+// utilizes jsonschema tags to detail the definition. For jsonschema in Go,
+// see https://github.com/invopop/jsonschema.
+func InputSchema() any {
+	return &Parameter{}
+}
+
+func init() {
+	registry.Register(registry.Tool{Name: "zipcode", Description: Description(), InputSchema: InputSchema(), Upstream: api.BaseURL})
+}
+
+// DataTags specifies the data tags to which this serverless function
+// subscribes, essential for data reception. Upon receiving data with these
+// tags, the Handler function is triggered.
+func DataTags() []uint32 {
+	return []uint32{0xDD}
+}
+
+var api = &Zippopotam{
+	BaseURL:    "https://api.zippopotam.us",
+	HTTPClient: httpx.NewClient(10 * time.Second),
+}
+
+// Handler orchestrates the core processing logic of this function.
+// - sfn.ReadArgs() parses LLM Function Calling Arguments.
+// - sfn.WriteText() sends the retrieval result back to LLM.
+func Handler(ctx serverless.Context) {
+	var msg Parameter
+	if !sfn.ReadArgs(ctx, &msg) {
+		return
+	}
+
+	slog.Info("[sfn] << receive", "code", msg.Code, "country", msg.Country)
+
+	reqCtx, cancel := sfn.WithBudget()
+	defer cancel()
+
+	result, err := Lookup(reqCtx, api, msg.Code, msg.Country)
+	if err != nil {
+		slog.Warn("[sfn] Lookup error", "err", err)
+		sfn.WriteText(ctx, fmt.Sprintf("can not look up the postal code: %v", err))
+		return
+	}
+
+	sfn.WriteText(ctx, result.String())
+}
+
+// ErrNotFound is returned for a well-formed code that has no places.
+var ErrNotFound = errors.New("the postal code is not known")
+
+// formats are the shapes of the codes of some countries, after normalize.
+// The codes of the other countries are only checked for their characters.
+var formats = map[string]*regexp.Regexp{
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-\d{3}$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z]$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]?$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-\d{4}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4}$`),
+	"US": regexp.MustCompile(`^\d{5}$`),
+}
+
+var anyCode = regexp.MustCompile(`^[A-Z\d][A-Z\d -]{1,9}$`)
+
+// normalize upper-cases code and brings it to the form the API knows: the
+// US ZIP+4 suffix is dropped, and Zippopotam only has the outward part of the
+// British and the first half of the Canadian codes, e.g. "SW1A" of
+// "SW1A 2AA". The Dutch letters are dropped too.
+func normalize(code, country string) string {
+	code = strings.ToUpper(strings.Join(strings.Fields(code), " "))
+	switch country {
+	case "US":
+		code, _, _ = strings.Cut(code, "-")
+	case "GB":
+		// a full code has an outward part of 2 to 4 characters and an
+		// inward part of 3
+		if c := strings.ReplaceAll(code, " ", ""); len(c) >= 5 {
+			code = c[:len(c)-3]
+		}
+	case "CA":
+		if c := strings.ReplaceAll(code, " ", ""); len(c) == 6 {
+			code = c[:3]
+		}
+	case "NL":
+		if len(code) > 4 {
+			code = strings.TrimSpace(code[:4])
+		}
+	}
+	return code
+}
+
+// Lookup checks the code for the country and returns its places.
+func Lookup(ctx context.Context, z *Zippopotam, code, country string) (*Result, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		country = "US"
+	}
+	if len(country) != 2 {
+		return nil, fmt.Errorf("%q is not a 2-letter ISO 3166-1 country code", country)
+	}
+	normalized := normalize(code, country)
+	if format, ok := formats[country]; ok && !format.MatchString(normalized) {
+		return nil, fmt.Errorf("%q is not a postal code of %s", code, country)
+	}
+	if !anyCode.MatchString(normalized) {
+		return nil, fmt.Errorf("%q is not a postal code", code)
+	}
+	return z.Lookup(ctx, normalized, country)
+}
+
+// Place is a place of a postal code.
+type Place struct {
+	Name                string
+	Region              string
+	Latitude, Longitude float64
+}
+
+// Result is the places of a postal code.
+type Result struct {
+	Code    string
+	Country string
+	Places  []Place
+}
+
+func (r *Result) String() string {
+	places := make([]string, len(r.Places))
+	for i, p := range r.Places {
+		places[i] = p.Name
+		if p.Region != "" {
+			places[i] += ", " + p.Region
+		}
+		places[i] += fmt.Sprintf(" (%.4f,%.4f)", p.Latitude, p.Longitude)
+	}
+	return fmt.Sprintf("The postal code %s of %s covers: %s", r.Code, r.Country, strings.Join(places, "; "))
+}
+
+// Zippopotam is a client of the Zippopotam.us postal code API.
+type Zippopotam struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Lookup returns the places of code in the country.
+func (z *Zippopotam) Lookup(ctx context.Context, code, country string) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, z.BaseURL+"/"+strings.ToLower(country)+"/"+url.PathEscape(code), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := z.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s in %s", ErrNotFound, code, country)
+	default:
+		return nil, fmt.Errorf("the postal code API responded %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var body struct {
+		PostCode string `json:"post code"`
+		Country  string `json:"country"`
+		Places   []struct {
+			Name      string `json:"place name"`
+			State     string `json:"state"`
+			Latitude  string `json:"latitude"`
+			Longitude string `json:"longitude"`
+		} `json:"places"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode the postal code response: %w", err)
+	}
+	if len(body.Places) == 0 {
+		return nil, fmt.Errorf("%w: %s in %s", ErrNotFound, code, country)
+	}
+
+	r := &Result{Code: body.PostCode, Country: body.Country}
+	for _, p := range body.Places {
+		// the coordinates are decimal strings
+		lat, err := strconv.ParseFloat(p.Latitude, 64)
+		if err != nil {
+			return nil, fmt.Errorf("decode the latitude of %s: %w", p.Name, err)
+		}
+		lon, err := strconv.ParseFloat(p.Longitude, 64)
+		if err != nil {
+			return nil, fmt.Errorf("decode the longitude of %s: %w", p.Name, err)
+		}
+		r.Places = append(r.Places, Place{Name: p.Name, Region: p.State, Latitude: lat, Longitude: lon})
+	}
+	return r, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// codes are responses of the Zippopotam API by path.
+var codes = map[string]string{
+	"/us/90210": `{"post code":"90210","country":"United States","country abbreviation":"US","places":[{"place name":"Beverly Hills","longitude":"-118.4065","state":"California","state abbreviation":"CA","latitude":"34.0901"}]}`,
+	"/de/10117": `{"post code":"10117","country":"Germany","country abbreviation":"DE","places":[{"place name":"Berlin Mitte","longitude":"13.3888","state":"Berlin","state abbreviation":"BE","latitude":"52.5170"},{"place name":"Berlin","longitude":"13.3833","state":"Berlin","state abbreviation":"BE","latitude":"52.5167"}]}`,
+	"/gb/SW1A":  `{"post code":"SW1A","country":"Great Britain","country abbreviation":"GB","places":[{"place name":"London","longitude":"-0.1293","state":"England","state abbreviation":"ENG","latitude":"51.5019"}]}`,
+}
+
+func newAPI(t *testing.T) (*Zippopotam, *[]string) {
+	t.Helper()
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		body, ok := codes[r.URL.Path]
+		if !ok {
+			// the API answers an unknown code with an empty object
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "{}")
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return &Zippopotam{BaseURL: srv.URL, HTTPClient: srv.Client()}, &requested
+}
+
+func TestLookup(t *testing.T) {
+	api, _ := newAPI(t)
+	tests := []struct {
+		code, country string
+		want          string
+	}{
+		{"90210", "us", "The postal code 90210 of United States covers: Beverly Hills, California (34.0901,-118.4065)"},
+		{"90210-1234", "", "The postal code 90210 of United States covers: Beverly Hills, California (34.0901,-118.4065)"},
+		{" 10117 ", "DE", "The postal code 10117 of Germany covers: Berlin Mitte, Berlin (52.5170,13.3888); Berlin, Berlin (52.5167,13.3833)"},
+		{"sw1a 2aa", "GB", "The postal code SW1A of Great Britain covers: London, England (51.5019,-0.1293)"},
+	}
+	for _, tt := range tests {
+		got, err := Lookup(context.Background(), api, tt.code, tt.country)
+		if err != nil {
+			t.Errorf("Lookup(%q, %q) error = %v", tt.code, tt.country, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("Lookup(%q, %q) =\n%s\nwant\n%s", tt.code, tt.country, got, tt.want)
+		}
+	}
+}
+
+func TestLookupInvalid(t *testing.T) {
+	api, requested := newAPI(t)
+	for _, tt := range []struct{ code, country string }{
+		{"9021", "US"},
+		{"ABCDE", "US"},
+		{"1011", "DE"},
+		{"123456", "FR"},
+		{"1000000", "JP"},
+		{"K1A", "USA"},
+		{"!!", "PT"},
+		{"", "PT"},
+	} {
+		if _, err := Lookup(context.Background(), api, tt.code, tt.country); err == nil {
+			t.Errorf("Lookup(%q, %q) should fail", tt.code, tt.country)
+		}
+	}
+	if len(*requested) != 0 {
+		t.Errorf("invalid codes requested %v", *requested)
+	}
+
+	if _, err := Lookup(context.Background(), api, "99999", "US"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() of an unknown code error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		code, country, want string
+	}{
+		{"sw1a 2aa", "GB", "SW1A"},
+		{"M11AE", "GB", "M1"},
+		{"EC1A", "GB", "EC1A"},
+		{"k1a 0b1", "CA", "K1A"},
+		{"1012 ab", "NL", "1012"},
+		{"02134-0001", "US", "02134"},
+		{" 100-0001 ", "JP", "100-0001"},
+	}
+	for _, tt := range tests {
+		if got := normalize(tt.code, tt.country); got != tt.want {
+			t.Errorf("normalize(%q, %q) = %q, want %q", tt.code, tt.country, got, tt.want)
+		}
+	}
+}
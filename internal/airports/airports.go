@@ -0,0 +1,66 @@
+// Package airports resolves IATA airport codes to the location of the
+// airport from a bundled list of major airports. Add a line to airports.csv
+// to support another airport.
+package airports
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//go:embed airports.csv
+var data string
+
+// Airport is an airport of the bundled list.
+type Airport struct {
+	IATA      string
+	Name      string
+	City      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+// String returns the airport as "Name (IATA), City, Country".
+func (a Airport) String() string {
+	return fmt.Sprintf("%s (%s), %s, %s", a.Name, a.IATA, a.City, a.Country)
+}
+
+var byCode = mustParse(data)
+
+// ParseCode normalizes a 3-letter IATA airport code, e.g. " lhr " to "LHR".
+func ParseCode(code string) (string, error) {
+	c := strings.ToUpper(strings.TrimSpace(code))
+	if len(c) != 3 || strings.IndexFunc(c, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0 {
+		return "", fmt.Errorf("%q is not a 3-letter IATA airport code", code)
+	}
+	return c, nil
+}
+
+// Lookup returns the airport of a normalized IATA code.
+func Lookup(code string) (Airport, bool) {
+	a, ok := byCode[code]
+	return a, ok
+}
+
+// mustParse parses the bundled list, a malformed line is a bug and panics.
+func mustParse(data string) map[string]Airport {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("airports: %v", err))
+	}
+
+	airports := make(map[string]Airport, len(records))
+	for i, r := range records[1:] {
+		lat, latErr := strconv.ParseFloat(r[4], 64)
+		lon, lonErr := strconv.ParseFloat(r[5], 64)
+		if latErr != nil || lonErr != nil {
+			panic(fmt.Sprintf("airports: invalid coordinates on line %d", i+2))
+		}
+		airports[r[0]] = Airport{IATA: r[0], Name: r[1], City: r[2], Country: r[3], Latitude: lat, Longitude: lon}
+	}
+	return airports
+}
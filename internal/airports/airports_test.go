@@ -0,0 +1,58 @@
+package airports
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code     string
+		city     string
+		lat, lon float64
+	}{
+		{"LHR", "London", 51.47, -0.45},
+		{"JFK", "New York", 40.64, -73.78},
+		{"HND", "Tokyo", 35.55, 139.78},
+		{"SYD", "Sydney", -33.94, 151.18},
+		{"GRU", "Sao Paulo", -23.44, -46.47},
+	}
+	for _, tt := range tests {
+		a, ok := Lookup(tt.code)
+		if !ok {
+			t.Errorf("Lookup(%s) found no airport", tt.code)
+			continue
+		}
+		if a.City != tt.city || math.Abs(a.Latitude-tt.lat) > 0.01 || math.Abs(a.Longitude-tt.lon) > 0.01 {
+			t.Errorf("Lookup(%s) = %+v, want %s at %v,%v", tt.code, a, tt.city, tt.lat, tt.lon)
+		}
+	}
+
+	if a, ok := Lookup("XYZ"); ok {
+		t.Errorf("Lookup(XYZ) = %+v, want no airport", a)
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	for code, want := range map[string]string{"LHR": "LHR", " cdg ": "CDG", "Jfk": "JFK"} {
+		if got, err := ParseCode(code); err != nil || got != want {
+			t.Errorf("ParseCode(%q) = %q, %v, want %q", code, got, err, want)
+		}
+	}
+	for _, code := range []string{"", "LH", "EGLL", "L1R", "ÄBC"} {
+		if _, err := ParseCode(code); err == nil {
+			t.Errorf("ParseCode(%q) should fail", code)
+		}
+	}
+}
+
+func TestBundledList(t *testing.T) {
+	for code, a := range byCode {
+		if _, err := ParseCode(code); err != nil {
+			t.Errorf("invalid code in the list: %v", err)
+		}
+		if a.Latitude < -90 || a.Latitude > 90 || a.Longitude < -180 || a.Longitude > 180 || a.Name == "" {
+			t.Errorf("invalid airport in the list: %+v", a)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package borders finds the country at a coordinate offline, from the coarse
+// outlines of countries.txt. The outlines are a few dozen points each: a
+// point within about 50 km of a border or a coast can be attributed to the
+// neighbor or to the sea, and small islands are missing.
+package borders
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//go:embed countries.txt
+var data string
+
+// Country is a country of the bundled outlines.
+type Country struct {
+	// Code is the ISO 3166-1 alpha-2 code, e.g. "FR".
+	Code string
+	Name string
+}
+
+type point struct {
+	lon, lat float64
+}
+
+type polygon struct {
+	country Country
+	points  []point
+	// the bounding box, to skip most polygons cheaply
+	minLon, minLat, maxLon, maxLat float64
+}
+
+var polygons = mustParse(data)
+
+// Lookup returns the country whose outline contains the coordinate, or false
+// over the sea and the places the outlines miss.
+func Lookup(lat, lon float64) (Country, bool) {
+	for _, p := range polygons {
+		if p.contains(point{lon: lon, lat: lat}) {
+			return p.country, true
+		}
+	}
+	return Country{}, false
+}
+
+// contains tells whether pt is inside p, by counting how many of its edges
+// a ray going east from pt crosses.
+func (p *polygon) contains(pt point) bool {
+	if pt.lon < p.minLon || pt.lon > p.maxLon || pt.lat < p.minLat || pt.lat > p.maxLat {
+		return false
+	}
+	inside := false
+	for i, j := 0, len(p.points)-1; i < len(p.points); j, i = i, i+1 {
+		a, b := p.points[i], p.points[j]
+		if (a.lat > pt.lat) != (b.lat > pt.lat) &&
+			pt.lon < a.lon+(pt.lat-a.lat)*(b.lon-a.lon)/(b.lat-a.lat) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func mustParse(data string) []*polygon {
+	polygons, err := parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return polygons
+}
+
+func parse(data string) ([]*polygon, error) {
+	var polygons []*polygon
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) != 3 || len(fields[0]) != 2 {
+			return nil, fmt.Errorf("countries.txt:%d: want CODE|name|lon lat,...", n+1)
+		}
+		p := &polygon{
+			country: Country{Code: fields[0], Name: fields[1]},
+			minLon:  180, minLat: 90, maxLon: -180, maxLat: -90,
+		}
+		for _, pair := range strings.Split(fields[2], ",") {
+			lonLat := strings.Fields(pair)
+			if len(lonLat) != 2 {
+				return nil, fmt.Errorf("countries.txt:%d: invalid point %q", n+1, pair)
+			}
+			lon, err1 := strconv.ParseFloat(lonLat[0], 64)
+			lat, err2 := strconv.ParseFloat(lonLat[1], 64)
+			if err1 != nil || err2 != nil || lon < -180 || lon > 180 || lat < -90 || lat > 90 {
+				return nil, fmt.Errorf("countries.txt:%d: invalid point %q", n+1, pair)
+			}
+			p.points = append(p.points, point{lon: lon, lat: lat})
+			p.minLon, p.maxLon = min(p.minLon, lon), max(p.maxLon, lon)
+			p.minLat, p.maxLat = min(p.minLat, lat), max(p.maxLat, lat)
+		}
+		if len(p.points) < 3 {
+			return nil, fmt.Errorf("countries.txt:%d: a polygon needs 3 points", n+1)
+		}
+		polygons = append(polygons, p)
+	}
+	return polygons, nil
+}
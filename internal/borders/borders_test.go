@@ -0,0 +1,160 @@
+package borders
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		place    string
+		lat, lon float64
+		want     string
+	}{
+		{"Paris", 48.8566, 2.3522, "FR"},
+		{"Madrid", 40.4168, -3.7038, "ES"},
+		{"Lisbon", 38.7223, -9.1393, "PT"},
+		{"Andorra la Vella", 42.5063, 1.5218, "AD"},
+		{"Vaduz", 47.141, 9.5209, "LI"},
+		{"Pristina", 42.6629, 21.1655, "XK"},
+		{"Thimphu", 27.4728, 89.639, "BT"},
+		{"London", 51.5072, -0.1276, "GB"},
+		{"Belfast", 54.597, -5.930, "GB"},
+		{"Dublin", 53.3498, -6.2603, "IE"},
+		{"Brussels", 50.8503, 4.3517, "BE"},
+		{"Amsterdam", 52.3676, 4.9041, "NL"},
+		{"Berlin", 52.52, 13.405, "DE"},
+		{"Munich", 48.1351, 11.582, "DE"},
+		{"Zurich", 47.3769, 8.5417, "CH"},
+		{"Vienna", 48.2082, 16.3738, "AT"},
+		{"Rome", 41.9028, 12.4964, "IT"},
+		{"Vatican City", 41.9029, 12.4534, "VA"},
+		{"Palermo", 38.1157, 13.3615, "IT"},
+		{"Warsaw", 52.2297, 21.0122, "PL"},
+		{"Prague", 50.0755, 14.4378, "CZ"},
+		{"Budapest", 47.4979, 19.0402, "HU"},
+		{"Bucharest", 44.4268, 26.1025, "RO"},
+		{"Athens", 37.9838, 23.7275, "GR"},
+		{"Kyiv", 50.4501, 30.5234, "UA"},
+		{"Minsk", 53.9006, 27.559, "BY"},
+		{"Moscow", 55.7558, 37.6173, "RU"},
+		{"Novosibirsk", 55.0084, 82.9357, "RU"},
+		{"Vladivostok", 43.1155, 131.8855, "RU"},
+		{"Stockholm", 59.3293, 18.0686, "SE"},
+		{"Oslo", 59.9139, 10.7522, "NO"},
+		{"Helsinki", 60.1699, 24.9384, "FI"},
+		{"Reykjavik", 64.1466, -21.9426, "IS"},
+		{"Istanbul", 41.0082, 28.9784, "TR"},
+		{"Ankara", 39.9334, 32.8597, "TR"},
+		{"Tehran", 35.6892, 51.389, "IR"},
+		{"Baghdad", 33.3152, 44.3661, "IQ"},
+		{"Riyadh", 24.7136, 46.6753, "SA"},
+		{"Dubai", 25.2048, 55.2708, "AE"},
+		{"Jerusalem", 31.7683, 35.2137, "IL"},
+		{"Almaty", 43.222, 76.8512, "KZ"},
+		{"Tashkent", 41.2995, 69.2401, "UZ"},
+		{"Kabul", 34.5553, 69.2075, "AF"},
+		{"Karachi", 24.8607, 67.0011, "PK"},
+		{"Delhi", 28.6139, 77.209, "IN"},
+		{"Mumbai", 19.076, 72.8777, "IN"},
+		{"Kathmandu", 27.7172, 85.324, "NP"},
+		{"Dhaka", 23.8103, 90.4125, "BD"},
+		{"Beijing", 39.9042, 116.4074, "CN"},
+		{"Shanghai", 31.2304, 121.4737, "CN"},
+		{"Ulaanbaatar", 47.8864, 106.9057, "MN"},
+		{"Seoul", 37.5665, 126.978, "KR"},
+		{"Pyongyang", 39.0392, 125.7625, "KP"},
+		{"Tokyo", 35.6762, 139.6503, "JP"},
+		{"Sapporo", 43.0618, 141.3545, "JP"},
+		{"Taipei", 25.033, 121.5654, "TW"},
+		{"Bangkok", 13.7563, 100.5018, "TH"},
+		{"Hanoi", 21.0278, 105.8342, "VN"},
+		{"Kuala Lumpur", 3.139, 101.6869, "MY"},
+		{"Singapore", 1.3521, 103.8198, "SG"},
+		{"Jakarta", -6.2088, 106.8456, "ID"},
+		{"Manila", 14.5995, 120.9842, "PH"},
+		{"Cairo", 30.0444, 31.2357, "EG"},
+		{"Algiers", 36.7538, 3.0588, "DZ"},
+		{"Casablanca", 33.5731, -7.5898, "MA"},
+		{"Lagos", 6.5244, 3.3792, "NG"},
+		{"Accra", 5.6037, -0.187, "GH"},
+		{"Dakar", 14.7167, -17.4677, "SN"},
+		{"Banjul", 13.4549, -16.579, "GM"},
+		{"Addis Ababa", 9.03, 38.74, "ET"},
+		{"Nairobi", -1.2921, 36.8219, "KE"},
+		{"Kinshasa", -4.4419, 15.2663, "CD"},
+		{"Luanda", -8.839, 13.2894, "AO"},
+		{"Johannesburg", -26.2041, 28.0473, "ZA"},
+		{"Cape Town", -33.9249, 18.4241, "ZA"},
+		{"Maseru", -29.31, 27.48, "LS"},
+		{"Antananarivo", -18.8792, 47.5079, "MG"},
+		{"New York", 40.7128, -74.006, "US"},
+		{"Denver", 39.7392, -104.9903, "US"},
+		{"Los Angeles", 34.0522, -118.2437, "US"},
+		{"Miami", 25.7617, -80.1918, "US"},
+		{"Anchorage", 61.2181, -149.9003, "US"},
+		{"Honolulu", 21.3069, -157.8583, "US"},
+		{"Toronto", 43.6532, -79.3832, "CA"},
+		{"Montreal", 45.5017, -73.5673, "CA"},
+		{"Vancouver", 49.2827, -123.1207, "CA"},
+		{"Nuuk", 64.1814, -51.6941, "GL"},
+		{"Mexico City", 19.4326, -99.1332, "MX"},
+		{"Havana", 23.1136, -82.3666, "CU"},
+		{"Panama City", 8.9824, -79.5199, "PA"},
+		{"Bogota", 4.711, -74.0721, "CO"},
+		{"Caracas", 10.4806, -66.9036, "VE"},
+		{"Quito", -0.1807, -78.4678, "EC"},
+		{"Lima", -12.0464, -77.0428, "PE"},
+		{"La Paz", -16.4897, -68.1193, "BO"},
+		{"Sao Paulo", -23.5505, -46.6333, "BR"},
+		{"Manaus", -3.119, -60.0217, "BR"},
+		{"Asuncion", -25.2637, -57.5759, "PY"},
+		{"Montevideo", -34.9011, -56.1645, "UY"},
+		{"Buenos Aires", -34.6037, -58.3816, "AR"},
+		{"Ushuaia", -54.8019, -68.303, "AR"},
+		{"Santiago", -33.4489, -70.6693, "CL"},
+		{"Sydney", -33.8688, 151.2093, "AU"},
+		{"Perth", -31.9505, 115.8605, "AU"},
+		{"Hobart", -42.8821, 147.3272, "AU"},
+		{"Auckland", -36.8485, 174.7633, "NZ"},
+		{"Christchurch", -43.5321, 172.6362, "NZ"},
+		{"Port Moresby", -9.4438, 147.1803, "PG"},
+	}
+	for _, tt := range tests {
+		got, ok := Lookup(tt.lat, tt.lon)
+		if !ok || got.Code != tt.want {
+			t.Errorf("Lookup(%s) = %v, %v, want %s", tt.place, got, ok, tt.want)
+		}
+	}
+}
+
+func TestLookupSea(t *testing.T) {
+	for _, tt := range []struct {
+		place    string
+		lat, lon float64
+	}{
+		{"the North Atlantic", 30, -40},
+		{"the Pacific", 0, -150},
+		{"the Indian Ocean", -20, 80},
+		{"the Mediterranean", 35, 18},
+		{"the North Sea", 56, 3},
+		{"the Gulf of Mexico", 25, -90},
+		{"the Southern Ocean", -60, 0},
+		{"Null Island", 0, 0},
+	} {
+		if got, ok := Lookup(tt.lat, tt.lon); ok {
+			t.Errorf("Lookup(%s) = %v, want no country", tt.place, got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, data := range []string{
+		"FR|France",
+		"FRA|France|0 0,1 0,1 1",
+		"FR|France|0 0,1 0",
+		"FR|France|0 0,1 x,1 1",
+		"FR|France|0 0,1 95,1 1",
+	} {
+		if _, err := parse(data); err == nil {
+			t.Errorf("parse(%q) should fail", data)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package cache is a small in-memory cache with a time to live, used by the
+// functions to avoid requesting an upstream API again for the same
+// question.
+package cache
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache maps string keys to values of type V that expire after a TTL. It is
+// safe for concurrent use.
+type Cache[V any] struct {
+	ttl    time.Duration
+	jitter float64
+	// now and random are replaced in tests
+	now    func() time.Time
+	random func() float64
+
+	mu      sync.Mutex
+	entries map[string]entry[V]
+	// requests counts the requests of each key while the cache is kept
+	// warm, it is nil otherwise.
+	requests map[string]int
+	group    singleflight.Group
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// DefaultJitter is the fraction of the TTL by which the lifetime of an entry
+// varies at random, see WithJitter.
+const DefaultJitter = 0.1
+
+// New returns an empty Cache whose entries live for ttl, give or take
+// DefaultJitter of it.
+func New[V any](ttl time.Duration) *Cache[V] {
+	return &Cache[V]{ttl: ttl, jitter: DefaultJitter, now: time.Now, random: rand.Float64, entries: make(map[string]entry[V])}
+}
+
+// WithJitter sets the fraction of the TTL by which the lifetime of an entry
+// varies at random and returns c, e.g. with 0.1 an entry of a 10 minute
+// cache lives between 9 and 11 minutes. Entries stored together, like the
+// burst of requests of a function starting, then do not all expire at once
+// and hit the upstream again together. The fraction is clamped to [0, 1], 0
+// gives every entry exactly the TTL.
+func (c *Cache[V]) WithJitter(fraction float64) *Cache[V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.jitter = min(max(fraction, 0), 1)
+	return c
+}
+
+// Get returns the value of key if it is cached and not expired.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.count(key)
+	return c.lookup(key)
+}
+
+// count records a request of key for KeepWarm.
+func (c *Cache[V]) count(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.requests != nil {
+		c.requests[key]++
+	}
+}
+
+// lookup is Get without counting the request.
+func (c *Cache[V]) lookup(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set caches value for key.
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry[V]{value: value, expires: c.now().Add(c.lifetime())}
+	c.evictExpired()
+}
+
+// lifetime returns the TTL of a new entry, randomly within the jitter
+// around c.ttl. c.mu must be held.
+func (c *Cache[V]) lifetime() time.Duration {
+	if c.jitter == 0 {
+		return c.ttl
+	}
+	// random is in [0, 1), the factor in [1-jitter, 1+jitter)
+	factor := 1 + c.jitter*(2*c.random()-1)
+	return time.Duration(float64(c.ttl) * factor)
+}
+
+// evictExpired drops the expired entries once the cache has grown, so that
+// keys that are never requested again do not pile up. c.mu must be held.
+func (c *Cache[V]) evictExpired() {
+	if len(c.entries) < 1024 {
+		return
+	}
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// GetOrLoad returns the cached value of key, or calls load and caches its
+// result. Concurrent calls for a key that is missing wait for a single call
+// of load and share its result, so an expired popular key does not send a
+// burst of requests upstream. Errors are not cached.
+func (c *Cache[V]) GetOrLoad(key string, load func() (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		// a call that was waiting on the lock may find the value loaded
+		if v, ok := c.lookup(key); ok {
+			return v, nil
+		}
+		v, err := load()
+		if err != nil {
+			return v, err
+		}
+		c.Set(key, v)
+		return v, nil
+	})
+	return v.(V), err
+}
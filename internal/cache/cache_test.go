@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	c := New[string](time.Minute).WithJitter(0)
+	c.now = func() time.Time { return now }
+
+	c.Set("paris", "sunny")
+	if v, ok := c.Get("paris"); !ok || v != "sunny" {
+		t.Errorf("Get() = %q, %v, want sunny", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("paris"); ok {
+		t.Error("Get() returned an expired entry")
+	}
+	if _, ok := c.Get("oslo"); ok {
+		t.Error("Get() returned a missing entry")
+	}
+}
+
+func TestJitter(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	c := New[string](10 * time.Minute)
+	c.now = func() time.Time { return now }
+
+	// a burst of entries stored at the same time
+	const n = 1000
+	earliest, latest := now.Add(9*time.Minute), now.Add(11*time.Minute)
+	expiries := make(map[time.Time]bool)
+	for i := 0; i < n; i++ {
+		key := fmt.Sprint(i)
+		c.Set(key, "sunny")
+		e := c.entries[key].expires
+		if e.Before(earliest) || !e.Before(latest) {
+			t.Fatalf("entry %d expires at %s, want within [%s, %s)", i, e.Format(time.TimeOnly), earliest.Format(time.TimeOnly), latest.Format(time.TimeOnly))
+		}
+		expiries[e] = true
+	}
+	if len(expiries) < n/2 {
+		t.Errorf("%d entries share %d expiries, the TTLs are not jittered", n, len(expiries))
+	}
+
+	c.random = func() float64 { return 0 }
+	c.Set("low", "sunny")
+	if got := c.entries["low"].expires; !got.Equal(earliest) {
+		t.Errorf("expiry with the lowest jitter = %s, want %s", got, earliest)
+	}
+
+	c.WithJitter(0.25)
+	c.random = func() float64 { return 0.5 }
+	c.Set("middle", "sunny")
+	if got, want := c.entries["middle"].expires, now.Add(10*time.Minute); !got.Equal(want) {
+		t.Errorf("expiry with the middle jitter = %s, want %s", got, want)
+	}
+	c.random = func() float64 { return 0 }
+	c.Set("quarter", "sunny")
+	if got, want := c.entries["quarter"].expires, now.Add(7*time.Minute+30*time.Second); !got.Equal(want) {
+		t.Errorf("expiry with a 25%% jitter = %s, want %s", got, want)
+	}
+
+	c.WithJitter(0)
+	c.random = func() float64 {
+		t.Error("random called without jitter")
+		return 0
+	}
+	c.Set("exact", "sunny")
+	if got, want := c.entries["exact"].expires, now.Add(10*time.Minute); !got.Equal(want) {
+		t.Errorf("expiry without jitter = %s, want %s", got, want)
+	}
+}
+
+func TestGetOrLoadSingleFlight(t *testing.T) {
+	c := New[string](time.Minute)
+
+	const n = 50
+	var (
+		calls   atomic.Int32
+		start   sync.WaitGroup
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	load := func() (string, error) {
+		calls.Add(1)
+		<-release
+		return "sunny", nil
+	}
+
+	start.Add(n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start.Done()
+			v, err := c.GetOrLoad("paris", load)
+			if err != nil || v != "sunny" {
+				t.Errorf("GetOrLoad() = %q, %v", v, err)
+			}
+		}()
+	}
+	// let every goroutine reach GetOrLoad before the load returns
+	start.Wait()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("load was called %d times, want 1", got)
+	}
+
+	// the value is cached now
+	if _, err := c.GetOrLoad("paris", func() (string, error) {
+		t.Error("load called for a cached key")
+		return "", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetOrLoadErrorNotCached(t *testing.T) {
+	c := New[string](time.Minute)
+
+	boom := errors.New("boom")
+	if _, err := c.GetOrLoad("paris", func() (string, error) { return "", boom }); !errors.Is(err, boom) {
+		t.Errorf("GetOrLoad() error = %v, want %v", err, boom)
+	}
+	v, err := c.GetOrLoad("paris", func() (string, error) { return "sunny", nil })
+	if err != nil || v != "sunny" {
+		t.Errorf("GetOrLoad() after an error = %q, %v, want sunny", v, err)
+	}
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// KeepWarm reloads the popular entries of c before they expire, so that
+// their requests keep hitting the cache: every interval, the top most
+// requested keys expiring within two intervals are loaded again with load.
+// The request counts are halved at each pass, so that the popularity
+// follows the recent requests. It blocks until ctx is done, run it in a
+// goroutine. The interval must be well below the TTL, e.g. a fifth of it.
+func (c *Cache[V]) KeepWarm(ctx context.Context, interval time.Duration, top int, load func(key string) (V, error)) {
+	c.mu.Lock()
+	if c.requests == nil {
+		c.requests = make(map[string]int)
+	}
+	c.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Refresh(top, 2*interval, load)
+		}
+	}
+}
+
+// Refresh loads again with load the top most requested keys that are
+// cached and expire within ahead, and returns how many it refreshed. A
+// failed load keeps the entry until it expires. The keys requested while
+// missing are refreshed once they are cached.
+func (c *Cache[V]) Refresh(top int, ahead time.Duration, load func(key string) (V, error)) int {
+	refreshed := 0
+	for _, key := range c.expiring(top, ahead) {
+		_, err, _ := c.group.Do(key, func() (any, error) {
+			v, err := load(key)
+			if err != nil {
+				return v, err
+			}
+			c.Set(key, v)
+			return v, nil
+		})
+		if err == nil {
+			refreshed++
+		}
+	}
+	return refreshed
+}
+
+// expiring returns the top most requested keys that are cached and expire
+// within ahead, and decays the request counts.
+func (c *Cache[V]) expiring(top int, ahead time.Duration) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type popular struct {
+		key      string
+		requests int
+	}
+	var keys []popular
+	for key, n := range c.requests {
+		keys = append(keys, popular{key, n})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].requests != keys[j].requests {
+			return keys[i].requests > keys[j].requests
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	deadline := c.now().Add(ahead)
+	var due []string
+	for _, p := range keys[:min(top, len(keys))] {
+		if e, ok := c.entries[p.key]; ok && e.expires.Before(deadline) {
+			due = append(due, p.key)
+		}
+	}
+
+	for key, n := range c.requests {
+		if n /= 2; n == 0 {
+			delete(c.requests, key)
+		} else {
+			c.requests[key] = n
+		}
+	}
+	return due
+}
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefresh(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	c := New[string](10 * time.Minute).WithJitter(0)
+	c.now = func() time.Time { return now }
+	c.requests = make(map[string]int)
+
+	c.Set("paris", "sunny")
+	c.Set("oslo", "rain")
+	for i := 0; i < 5; i++ {
+		c.Get("paris")
+	}
+	c.Get("oslo")
+
+	var loads []string
+	load := func(key string) (string, error) {
+		loads = append(loads, key)
+		return key + " refreshed", nil
+	}
+
+	// nothing expires within 2 minutes yet
+	if n := c.Refresh(1, 2*time.Minute, load); n != 0 || len(loads) != 0 {
+		t.Fatalf("Refresh() = %d, loads %v, want none", n, loads)
+	}
+
+	// the popular key is refreshed before it expires, the cold one is not
+	now = now.Add(9 * time.Minute)
+	if n := c.Refresh(1, 2*time.Minute, load); n != 1 || len(loads) != 1 || loads[0] != "paris" {
+		t.Fatalf("Refresh() = %d, loads %v, want paris", n, loads)
+	}
+	now = now.Add(2 * time.Minute)
+	if v, ok := c.lookup("paris"); !ok || v != "paris refreshed" {
+		t.Errorf("paris after its TTL = %q, %v, want the refreshed value", v, ok)
+	}
+	if _, ok := c.lookup("oslo"); ok {
+		t.Error("the cold key was kept past its TTL")
+	}
+}
+
+func TestRefreshDecay(t *testing.T) {
+	c := New[string](time.Minute)
+	c.requests = make(map[string]int)
+	for i := 0; i < 4; i++ {
+		c.Get("paris")
+	}
+	c.Get("oslo")
+
+	c.Refresh(10, time.Minute, func(string) (string, error) { return "", nil })
+	if c.requests["paris"] != 2 {
+		t.Errorf("paris count after a pass = %d, want 2", c.requests["paris"])
+	}
+	if _, ok := c.requests["oslo"]; ok {
+		t.Error("the key requested once is still counted after a pass")
+	}
+}
+
+func TestRefreshError(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	c := New[string](time.Minute).WithJitter(0)
+	c.now = func() time.Time { return now }
+	c.requests = make(map[string]int)
+	c.Set("paris", "sunny")
+	c.Get("paris")
+
+	n := c.Refresh(1, 2*time.Minute, func(string) (string, error) { return "", errors.New("upstream down") })
+	if n != 0 {
+		t.Errorf("Refresh() = %d, want 0", n)
+	}
+	if v, ok := c.lookup("paris"); !ok || v != "sunny" {
+		t.Errorf("paris after a failed refresh = %q, %v, want the cached value", v, ok)
+	}
+}
+
+func TestKeepWarm(t *testing.T) {
+	// it returns once its context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	New[string](time.Hour).KeepWarm(ctx, time.Minute, 1, nil)
+
+	// with short ticks a requested key is refreshed
+	var loads atomic.Int32
+	c := New[string](50 * time.Millisecond).WithJitter(0)
+	ctx, cancel = context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go c.KeepWarm(ctx, 10*time.Millisecond, 1, func(key string) (string, error) {
+		loads.Add(1)
+		return "fresh", nil
+	})
+	for !c.requestsTracked() {
+		time.Sleep(time.Millisecond)
+	}
+	c.Set("paris", "sunny")
+	deadline := time.Now().Add(time.Second)
+	for loads.Load() == 0 && time.Now().Before(deadline) {
+		c.Get("paris")
+		time.Sleep(5 * time.Millisecond)
+	}
+	if loads.Load() == 0 {
+		t.Error("KeepWarm() did not refresh the requested key")
+	}
+}
+
+// requestsTracked reports whether KeepWarm started counting the requests.
+func (c *Cache[V]) requestsTracked() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests != nil
+}
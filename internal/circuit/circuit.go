@@ -0,0 +1,137 @@
+// Package circuit is a circuit breaker for the upstream APIs of the
+// functions. Once an upstream failed several times in a row, the calls fail
+// at once for a while instead of each one waiting for a timeout.
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned instead of calling the upstream while the breaker is
+// open.
+var ErrOpen = errors.New("service temporarily unavailable")
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	// Closed lets the calls through, it is the normal state.
+	Closed State = iota
+	// Open fails the calls with ErrOpen until the cooldown is over.
+	Open
+	// HalfOpen lets a single call through to probe whether the upstream
+	// has recovered, and fails the others with ErrOpen.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// Breaker trips open after a number of consecutive failures, and half-opens
+// after a cooldown to probe the upstream: a successful probe closes it, a
+// failed one opens it again for another cooldown. It is safe for concurrent
+// use.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	// IsFailure tells the errors caused by the upstream being down, which
+	// count towards tripping the breaker, from those that are answers,
+	// e.g. a city not found. The default counts every error.
+	IsFailure func(error) bool
+	// now is replaced in tests
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+}
+
+// New returns a closed Breaker that opens after threshold consecutive
+// failures, for cooldown.
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: max(threshold, 1), cooldown: cooldown, now: time.Now}
+}
+
+// State returns the state of b, Open turning HalfOpen once the cooldown is
+// over.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open && !b.now().Before(b.openedAt.Add(b.cooldown)) {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Do calls fn unless b is open, and records its outcome. A panic of fn is
+// recorded as a failure, so that a panicking probe does not leave b
+// half-open, before the panic goes on to the caller.
+func (b *Breaker) Do(fn func() error) error {
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+	failed := true
+	defer func() { b.record(probe, failed) }()
+	err = fn()
+	failed = err != nil && (b.IsFailure == nil || b.IsFailure(err))
+	return err
+}
+
+// allow tells whether a call may go through, and whether it is the probe
+// of a half-open breaker.
+func (b *Breaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if b.now().Before(b.openedAt.Add(b.cooldown)) {
+			return false, ErrOpen
+		}
+		b.state = HalfOpen
+		return true, nil
+	case HalfOpen:
+		// a probe is already in flight
+		return false, ErrOpen
+	}
+	return false, nil
+}
+
+func (b *Breaker) record(probe, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case !failed:
+		b.failures = 0
+		if probe {
+			b.state = Closed
+		}
+	case probe:
+		b.trip()
+	case b.state == Closed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.trip()
+		}
+	}
+}
+
+// trip opens b. b.mu must be held.
+func (b *Breaker) trip() {
+	b.state = Open
+	b.openedAt = b.now()
+	b.failures = 0
+}
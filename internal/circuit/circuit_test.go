@@ -0,0 +1,184 @@
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// upstream is a fake upstream, down until it is fixed.
+type upstream struct {
+	down  bool
+	calls int
+}
+
+var errDown = errors.New("connection refused")
+
+func (u *upstream) call() error {
+	u.calls++
+	if u.down {
+		return errDown
+	}
+	return nil
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	b := New(3, 30*time.Second)
+	b.now = func() time.Time { return now }
+	u := &upstream{down: true}
+
+	// closed: the failures go through until the threshold
+	for i := 0; i < 3; i++ {
+		if b.State() != Closed {
+			t.Fatalf("state after %d failures = %s, want closed", i, b.State())
+		}
+		if err := b.Do(u.call); !errors.Is(err, errDown) {
+			t.Fatalf("Do() error = %v, want the upstream error", err)
+		}
+	}
+
+	// open: the calls fail at once
+	if b.State() != Open {
+		t.Fatalf("state after 3 failures = %s, want open", b.State())
+	}
+	now = now.Add(29 * time.Second)
+	if err := b.Do(u.call); !errors.Is(err, ErrOpen) {
+		t.Errorf("Do() error = %v, want ErrOpen", err)
+	}
+	if u.calls != 3 {
+		t.Errorf("the upstream was called %d times, want 3", u.calls)
+	}
+
+	// half-open: a failed probe opens the breaker again
+	now = now.Add(time.Second)
+	if b.State() != HalfOpen {
+		t.Fatalf("state after the cooldown = %s, want half-open", b.State())
+	}
+	if err := b.Do(u.call); !errors.Is(err, errDown) {
+		t.Errorf("probe error = %v, want the upstream error", err)
+	}
+	if b.State() != Open {
+		t.Fatalf("state after a failed probe = %s, want open", b.State())
+	}
+	now = now.Add(10 * time.Second)
+	if err := b.Do(u.call); !errors.Is(err, ErrOpen) {
+		t.Errorf("Do() error = %v, want ErrOpen for a new cooldown", err)
+	}
+
+	// the upstream recovers, a successful probe closes the breaker
+	u.down = false
+	now = now.Add(20 * time.Second)
+	if err := b.Do(u.call); err != nil {
+		t.Errorf("probe error = %v", err)
+	}
+	if b.State() != Closed {
+		t.Fatalf("state after a successful probe = %s, want closed", b.State())
+	}
+	if u.calls != 5 {
+		t.Errorf("the upstream was called %d times, want 5", u.calls)
+	}
+
+	// the failures counted before the recovery are forgotten
+	u.down = true
+	for i := 0; i < 2; i++ {
+		b.Do(u.call)
+	}
+	if b.State() != Closed {
+		t.Errorf("state after 2 new failures = %s, want closed", b.State())
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	b := New(2, time.Minute)
+	u := &upstream{}
+	for i := 0; i < 5; i++ {
+		u.down = i%2 == 0
+		b.Do(u.call)
+	}
+	if b.State() != Closed {
+		t.Errorf("state after alternating failures = %s, want closed", b.State())
+	}
+}
+
+func TestBreakerIsFailure(t *testing.T) {
+	notFound := errors.New("city not found")
+	b := New(1, time.Minute)
+	b.IsFailure = func(err error) bool { return !errors.Is(err, notFound) }
+
+	for i := 0; i < 3; i++ {
+		if err := b.Do(func() error { return notFound }); !errors.Is(err, notFound) {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if b.State() != Closed {
+		t.Fatalf("state after answers = %s, want closed", b.State())
+	}
+	b.Do(func() error { return errDown })
+	if b.State() != Open {
+		t.Errorf("state after a failure = %s, want open", b.State())
+	}
+}
+
+func TestBreakerSingleProbe(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	b := New(1, time.Second)
+	b.now = func() time.Time { return now }
+	b.Do(func() error { return errDown })
+	now = now.Add(time.Second)
+
+	release := make(chan struct{})
+	probing := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.Do(func() error {
+			close(probing)
+			<-release
+			return nil
+		})
+	}()
+	<-probing
+
+	// the other calls fail while the probe is in flight
+	if err := b.Do(func() error { return nil }); !errors.Is(err, ErrOpen) {
+		t.Errorf("Do() during the probe error = %v, want ErrOpen", err)
+	}
+	close(release)
+	wg.Wait()
+	if err := b.Do(func() error { return nil }); err != nil {
+		t.Errorf("Do() after the probe error = %v", err)
+	}
+}
+
+func TestBreakerPanickingProbe(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	b := New(1, time.Second)
+	b.now = func() time.Time { return now }
+	b.Do(func() error { return errDown })
+	now = now.Add(time.Second)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Do() recovered %v, want the panic of the probe", r)
+			}
+		}()
+		b.Do(func() error { panic("boom") })
+	}()
+
+	// the panic failed the probe, the breaker is open for another cooldown
+	// instead of half-open forever
+	if b.State() != Open {
+		t.Errorf("state after a panicking probe = %s, want open", b.State())
+	}
+	now = now.Add(time.Second)
+	if err := b.Do(func() error { return nil }); err != nil {
+		t.Errorf("Do() after the cooldown error = %v", err)
+	}
+	if b.State() != Closed {
+		t.Errorf("state after a successful probe = %s, want closed", b.State())
+	}
+}
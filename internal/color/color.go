@@ -0,0 +1,164 @@
+// Package color parses the colors given to the color functions and converts
+// them between the RGB and HSL spaces.
+package color
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RGB is a color of the sRGB space, 8 bits per channel.
+type RGB struct {
+	R, G, B uint8
+}
+
+// Hex returns the color in lowercase hex notation, e.g. "#ff8800".
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// names are the 16 basic colors of HTML and orange, which CSS 2.1 added.
+var names = map[string]RGB{
+	"black":   {0x00, 0x00, 0x00},
+	"silver":  {0xc0, 0xc0, 0xc0},
+	"gray":    {0x80, 0x80, 0x80},
+	"grey":    {0x80, 0x80, 0x80},
+	"white":   {0xff, 0xff, 0xff},
+	"maroon":  {0x80, 0x00, 0x00},
+	"red":     {0xff, 0x00, 0x00},
+	"purple":  {0x80, 0x00, 0x80},
+	"fuchsia": {0xff, 0x00, 0xff},
+	"magenta": {0xff, 0x00, 0xff},
+	"green":   {0x00, 0x80, 0x00},
+	"lime":    {0x00, 0xff, 0x00},
+	"olive":   {0x80, 0x80, 0x00},
+	"yellow":  {0xff, 0xff, 0x00},
+	"navy":    {0x00, 0x00, 0x80},
+	"blue":    {0x00, 0x00, 0xff},
+	"teal":    {0x00, 0x80, 0x80},
+	"aqua":    {0x00, 0xff, 0xff},
+	"cyan":    {0x00, 0xff, 0xff},
+	"orange":  {0xff, 0xa5, 0x00},
+}
+
+var (
+	hexPattern = regexp.MustCompile(`^#?([0-9a-f]{3}|[0-9a-f]{6})$`)
+	rgbPattern = regexp.MustCompile(`^rgb\((\d{1,3}),(\d{1,3}),(\d{1,3})\)$`)
+)
+
+// Parse parses a color in hex notation, e.g. "#ff8800", "ff8800" or
+// "#f80", in the CSS rgb() notation, e.g. "rgb(255, 136, 0)", or one of the
+// basic color names, e.g. "orange". Case and spaces are ignored.
+func Parse(s string) (RGB, error) {
+	v := strings.ToLower(strings.Join(strings.Fields(s), ""))
+	if c, ok := names[v]; ok {
+		return c, nil
+	}
+	if m := hexPattern.FindStringSubmatch(v); m != nil {
+		h := m[1]
+		if len(h) == 3 {
+			h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+		}
+		n, _ := strconv.ParseUint(h, 16, 32)
+		return RGB{uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+	}
+	if m := rgbPattern.FindStringSubmatch(v); m != nil {
+		var channels [3]uint8
+		for i, part := range m[1:] {
+			n, _ := strconv.Atoi(part)
+			if n > 255 {
+				return RGB{}, fmt.Errorf("%q has a channel above 255", s)
+			}
+			channels[i] = uint8(n)
+		}
+		return RGB{channels[0], channels[1], channels[2]}, nil
+	}
+	return RGB{}, fmt.Errorf("%q is not a color, use a hex code like #ff8800, rgb(255, 136, 0) or a basic color name", s)
+}
+
+// HSL is a color of the HSL space: the hue in degrees from 0 to 360, and the
+// saturation and the lightness from 0 to 1.
+type HSL struct {
+	H, S, L float64
+}
+
+// HSL converts the color to the HSL space.
+func (c RGB) HSL() HSL {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (hi + lo) / 2
+	if hi == lo {
+		return HSL{0, 0, l}
+	}
+
+	d := hi - lo
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return HSL{h, s, l}
+}
+
+// RGB converts the color to the RGB space, rounding the channels. The hue
+// wraps around, the saturation and the lightness are clamped to 0 to 1.
+func (c HSL) RGB() RGB {
+	h := math.Mod(c.H, 360)
+	if h < 0 {
+		h += 360
+	}
+	s, l := clamp(c.S), clamp(c.L)
+
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	m := l - chroma/2
+	return RGB{channel(r + m), channel(g + m), channel(b + m)}
+}
+
+func channel(v float64) uint8 {
+	return uint8(math.Round(clamp(v) * 255))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// Luminance returns the relative luminance of the color as defined by WCAG
+// 2, from 0 for black to 1 for white.
+func (c RGB) Luminance() float64 {
+	linear := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
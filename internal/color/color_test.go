@@ -0,0 +1,108 @@
+package color
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want RGB
+	}{
+		{"#ff8800", RGB{0xff, 0x88, 0x00}},
+		{"FF8800", RGB{0xff, 0x88, 0x00}},
+		{"#f80", RGB{0xff, 0x88, 0x00}},
+		{" #3366CC ", RGB{0x33, 0x66, 0xcc}},
+		{"rgb(255, 136, 0)", RGB{0xff, 0x88, 0x00}},
+		{"RGB(0,0,0)", RGB{}},
+		{"Orange", RGB{0xff, 0xa5, 0x00}},
+		{"navy", RGB{0x00, 0x00, 0x80}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "#ff88", "#ff88001", "#gg8800", "rgb(256, 0, 0)", "rgb(1, 2)", "hsl(0, 100%, 50%)", "rebeccapurple"} {
+		if c, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) = %v, should fail", s, c)
+		}
+	}
+}
+
+func TestHex(t *testing.T) {
+	if got := (RGB{0xff, 0x08, 0x00}).Hex(); got != "#ff0800" {
+		t.Errorf("Hex() = %s, want #ff0800", got)
+	}
+}
+
+func TestHSL(t *testing.T) {
+	tests := []struct {
+		rgb RGB
+		hsl HSL
+	}{
+		{RGB{0xff, 0x00, 0x00}, HSL{0, 1, 0.5}},
+		{RGB{0x00, 0xff, 0x00}, HSL{120, 1, 0.5}},
+		{RGB{0x00, 0x00, 0xff}, HSL{240, 1, 0.5}},
+		{RGB{0xff, 0x00, 0xff}, HSL{300, 1, 0.5}},
+		{RGB{0x00, 0x00, 0x00}, HSL{0, 0, 0}},
+		{RGB{0xff, 0xff, 0xff}, HSL{0, 0, 1}},
+		{RGB{0x80, 0x80, 0x80}, HSL{0, 0, 128.0 / 255}},
+		// #3366cc is 220°, 60%, 50%
+		{RGB{0x33, 0x66, 0xcc}, HSL{220, 0.6, 0.5}},
+	}
+	for _, tt := range tests {
+		got := tt.rgb.HSL()
+		if math.Abs(got.H-tt.hsl.H) > 1e-9 || math.Abs(got.S-tt.hsl.S) > 1e-9 || math.Abs(got.L-tt.hsl.L) > 1e-9 {
+			t.Errorf("%v.HSL() = %v, want %v", tt.rgb, got, tt.hsl)
+		}
+		if back := tt.hsl.RGB(); back != tt.rgb {
+			t.Errorf("%v.RGB() = %v, want %v", tt.hsl, back, tt.rgb)
+		}
+	}
+
+	// every color survives the round trip
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 15 {
+			for b := 0; b < 256; b += 15 {
+				c := RGB{uint8(r), uint8(g), uint8(b)}
+				if back := c.HSL().RGB(); back != c {
+					t.Fatalf("%v round trips to %v", c, back)
+				}
+			}
+		}
+	}
+}
+
+func TestHSLWraps(t *testing.T) {
+	if got := (HSL{H: 480, S: 1, L: 0.5}).RGB(); got != (RGB{0x00, 0xff, 0x00}) {
+		t.Errorf("480° = %v, want lime", got)
+	}
+	if got := (HSL{H: -120, S: 1, L: 0.5}).RGB(); got != (RGB{0x00, 0x00, 0xff}) {
+		t.Errorf("-120° = %v, want blue", got)
+	}
+	if got := (HSL{H: 0, S: 2, L: 1.5}).RGB(); got != (RGB{0xff, 0xff, 0xff}) {
+		t.Errorf("the clamped color = %v, want white", got)
+	}
+}
+
+func TestLuminance(t *testing.T) {
+	tests := []struct {
+		c    RGB
+		want float64
+	}{
+		{RGB{0x00, 0x00, 0x00}, 0},
+		{RGB{0xff, 0xff, 0xff}, 1},
+		{RGB{0xff, 0x00, 0x00}, 0.2126},
+		{RGB{0x00, 0xff, 0x00}, 0.7152},
+		{RGB{0x80, 0x80, 0x80}, 0.2159},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Luminance(); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("%s.Luminance() = %v, want %v", tt.c.Hex(), got, tt.want)
+		}
+	}
+}
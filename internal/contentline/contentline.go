@@ -0,0 +1,67 @@
+// Package contentline writes the content lines shared by iCalendar (RFC 5545)
+// and vCard (RFC 2425 and RFC 2426): "NAME;PARAM=VALUE:value" lines ending with
+// CRLF, folded at 75 octets, with escaped text values.
+package contentline
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxOctets is the longest line, without the line break.
+const MaxOctets = 75
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// Escape escapes a text value: backslashes, semicolons, commas and line
+// breaks.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// Fold splits a line longer than MaxOctets into lines continued with a
+// leading space, without splitting a UTF-8 character.
+func Fold(line string) string {
+	if len(line) <= MaxOctets {
+		return line
+	}
+
+	var b strings.Builder
+	limit := MaxOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation counts
+		limit = MaxOctets - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// Writer collects content lines.
+type Writer struct {
+	b strings.Builder
+}
+
+// Line folds and appends a content line.
+func (w *Writer) Line(line string) {
+	w.b.WriteString(Fold(line))
+	w.b.WriteString("\r\n")
+}
+
+// String returns the lines written so far.
+func (w *Writer) String() string {
+	return w.b.String()
+}
@@ -0,0 +1,46 @@
+package contentline
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	got := Escape("a;b,c\\d\ne\r\nf")
+	want := `a\;b\,c\\d\ne\nf`
+	if got != want {
+		t.Errorf("Escape() = %s, want %s", got, want)
+	}
+}
+
+func TestFold(t *testing.T) {
+	if got := Fold("SUMMARY:short"); got != "SUMMARY:short" {
+		t.Errorf("Fold() = %q, want it unchanged", got)
+	}
+
+	line := "DESCRIPTION:" + strings.Repeat("é", 100)
+	folded := Fold(line)
+	parts := strings.Split(folded, "\r\n ")
+	if strings.Join(parts, "") != line {
+		t.Errorf("Fold() lost content: %q", folded)
+	}
+	for i, part := range parts {
+		octets := len(part)
+		if i > 0 {
+			octets++
+		}
+		if octets > MaxOctets || !utf8.ValidString(part) {
+			t.Errorf("Fold() part %d = %q has %d octets", i, part, octets)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var w Writer
+	w.Line("BEGIN:VCARD")
+	w.Line("END:VCARD")
+	if got := w.String(); got != "BEGIN:VCARD\r\nEND:VCARD\r\n" {
+		t.Errorf("String() = %q", got)
+	}
+}
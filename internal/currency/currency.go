@@ -0,0 +1,23 @@
+// Package currency holds the helpers shared by the currency functions.
+package currency
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseCode normalizes a 3-letter ISO 4217 currency code, e.g. " eur " to
+// "EUR". It checks the format only, whether the provider supports the code
+// is up to the provider.
+func ParseCode(code string) (string, error) {
+	c := strings.ToUpper(strings.TrimSpace(code))
+	if len(c) != 3 {
+		return "", fmt.Errorf("%q is not a 3-letter ISO 4217 currency code", code)
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("%q is not a 3-letter ISO 4217 currency code", code)
+		}
+	}
+	return c, nil
+}
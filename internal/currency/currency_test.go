@@ -0,0 +1,26 @@
+package currency
+
+import "testing"
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{code: "USD", want: "USD"},
+		{code: " eur ", want: "EUR"},
+		{code: "Jpy", want: "JPY"},
+		{code: "", wantErr: true},
+		{code: "US", wantErr: true},
+		{code: "EURO", wantErr: true},
+		{code: "U$D", wantErr: true},
+		{code: "€", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCode(tt.code)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCode(%q) = %q, %v, want %q, error %v", tt.code, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Package datetime reads the dates and time zones given by the LLM to the
+// functions about time, e.g. the epoch conversion and the countdown.
+package datetime
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoadLocation returns the location of the IANA Time Zone Database
+// identifier name, UTC when it is empty.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// Layouts are the date formats accepted by Parse, the ones with an offset
+// first.
+var Layouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Parse parses a date in one of Layouts and returns it in loc. A date
+// without offset is in loc.
+func Parse(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range Layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`can not understand the date %q, use a date like "2024-06-10 06:13:20" or "2024-06-10T06:13:20Z"`, value)
+}
@@ -0,0 +1,52 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("")
+	if err != nil || loc != time.UTC {
+		t.Errorf("LoadLocation(\"\") = %v, %v, want UTC", loc, err)
+	}
+	loc, err = LoadLocation("Asia/Tokyo")
+	if err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("LoadLocation(Asia/Tokyo) = %v, %v", loc, err)
+	}
+	if _, err := LoadLocation("Mars/Olympus"); err == nil || err.Error() != `unknown time zone "Mars/Olympus"` {
+		t.Errorf("LoadLocation(Mars/Olympus) error = %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 6, 10, 15, 13, 20, 0, tokyo)
+	for _, value := range []string{
+		"2024-06-10T06:13:20Z",
+		"2024-06-10T15:13:20+09:00",
+		"Mon, 10 Jun 2024 06:13:20 +0000",
+		"2024-06-10 15:13:20",
+		"2024-06-10T15:13:20",
+	} {
+		got, err := Parse(value, tokyo)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", value, err)
+			continue
+		}
+		if !got.Equal(want) || got.Location() != tokyo {
+			t.Errorf("Parse(%q) = %v, want %v", value, got, want)
+		}
+	}
+
+	got, err := Parse("2024-06-10", tokyo)
+	if err != nil || !got.Equal(time.Date(2024, 6, 10, 0, 0, 0, 0, tokyo)) {
+		t.Errorf("Parse(2024-06-10) = %v, %v, want midnight in Tokyo", got, err)
+	}
+	if _, err := Parse("next tuesday", tokyo); err == nil {
+		t.Error("Parse(next tuesday) error = nil")
+	}
+}
@@ -0,0 +1,74 @@
+// Package errs defines the errors a function reports to its caller. A
+// ToolError carries a stable Code the caller can branch on, and a message
+// for the LLM to relay to the user.
+package errs
+
+import "errors"
+
+// Code identifies the kind of failure of a function call.
+type Code string
+
+const (
+	// NotConfigured means the function is missing configuration, e.g. an
+	// API key, or the configuration is rejected by the upstream service.
+	NotConfigured Code = "not_configured"
+	// InvalidInput means the arguments of the call are not valid, calling
+	// again with the same arguments fails again.
+	InvalidInput Code = "invalid_input"
+	// UpstreamError means a service the function depends on failed or is
+	// unreachable.
+	UpstreamError Code = "upstream_error"
+	// RateLimited means the function or its upstream service refused the
+	// call because of too many requests, it may succeed later.
+	RateLimited Code = "rate_limited"
+	// Internal is any other failure.
+	Internal Code = "internal"
+)
+
+// ToolError is an error with a Code.
+type ToolError struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	// Err is the underlying error, it is logged but not sent to the LLM.
+	Err error `json:"-"`
+}
+
+// New returns a ToolError with the given code and message.
+func New(code Code, message string) *ToolError {
+	return &ToolError{Code: code, Message: message}
+}
+
+// Wrap returns a ToolError with the given code and message caused by err.
+func Wrap(code Code, err error, message string) *ToolError {
+	return &ToolError{Code: code, Message: message, Err: err}
+}
+
+func (e *ToolError) Error() string {
+	if e.Err != nil {
+		return string(e.Code) + ": " + e.Message + ": " + e.Err.Error()
+	}
+	return string(e.Code) + ": " + e.Message
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// As returns the ToolError in the chain of err. An error without one becomes
+// an Internal ToolError.
+func As(err error) *ToolError {
+	var te *ToolError
+	if errors.As(err, &te) {
+		return te
+	}
+	return Wrap(Internal, err, "the function failed unexpectedly")
+}
+
+// CodeOf returns the Code of err, Internal if err is not a ToolError, or ""
+// if err is nil.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	return As(err).Code
+}
@@ -0,0 +1,41 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCodeOf(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"tool error", New(InvalidInput, "latitude is out of range"), InvalidInput},
+		{"wrapped tool error", fmt.Errorf("fetch: %w", Wrap(UpstreamError, cause, "openweathermap is unreachable")), UpstreamError},
+		{"plain error", cause, Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolErrorUnwrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := Wrap(UpstreamError, cause, "openweathermap is unreachable")
+
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is() does not find the cause of a ToolError")
+	}
+	if want := "upstream_error: openweathermap is unreachable: connection refused"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
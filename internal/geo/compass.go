@@ -0,0 +1,18 @@
+package geo
+
+import "math"
+
+// CompassPoints are the 16 points of the compass clockwise from north.
+var CompassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// CompassPoint returns the index in CompassPoints of the point nearest to
+// a bearing in degrees, which is taken modulo 360. Each point covers 22.5°,
+// and a bearing on the boundary of two belongs to the clockwise one, e.g.
+// 11.25° is NNE.
+func CompassPoint(degrees float64) int {
+	d := math.Mod(degrees, 360)
+	if d < 0 {
+		d += 360
+	}
+	return int(math.Floor(d/22.5+0.5)) % len(CompassPoints)
+}
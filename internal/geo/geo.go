@@ -0,0 +1,51 @@
+// Package geo holds the spherical earth helpers shared by the location
+// functions.
+package geo
+
+import "math"
+
+// EarthRadiusKm is the mean radius of the earth.
+const EarthRadiusKm = 6371.0088
+
+// Distance returns the great circle distance in kilometers between two
+// coordinates in decimal degrees, with the haversine formula.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat, dLon := radians(lat2-lat1), radians(lon2-lon1)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Bearing returns the initial bearing in degrees, 0 to 360 clockwise from
+// north, of the great circle from the first coordinate to the second. The
+// bearing changes along the way, except on a meridian or the equator.
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	lat1R, lat2R, dLon := radians(lat1), radians(lat2), radians(lon2-lon1)
+
+	y := math.Sin(dLon) * math.Cos(lat2R)
+	x := math.Cos(lat1R)*math.Sin(lat2R) - math.Sin(lat1R)*math.Cos(lat2R)*math.Cos(dLon)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the coordinate reached from lat,lon after distanceKm
+// along the great circle of the initial bearing, in degrees clockwise from
+// north. The longitude is normalized to -180..180, so the antimeridian can be
+// crossed.
+func Destination(lat, lon, bearing, distanceKm float64) (float64, float64) {
+	latR, theta := radians(lat), radians(bearing)
+	// the distance as an angle at the center of the earth
+	d := distanceKm / EarthRadiusKm
+
+	lat2R := math.Asin(math.Sin(latR)*math.Cos(d) + math.Cos(latR)*math.Sin(d)*math.Cos(theta))
+	dLon := math.Atan2(math.Sin(theta)*math.Sin(d)*math.Cos(latR), math.Cos(d)-math.Sin(latR)*math.Sin(lat2R))
+	return degrees(lat2R), math.Mod(lon+degrees(dLon)+540, 360) - 180
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
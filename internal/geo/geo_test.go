@@ -0,0 +1,103 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"same point", 48.8566, 2.3522, 48.8566, 2.3522, 0},
+		{"one degree of latitude", 0, 0, 1, 0, 111.195},
+		{"paris to london", 48.8566, 2.3522, 51.5074, -0.1278, 343.56},
+		{"across the antimeridian", 0, 179.5, 0, -179.5, 111.195},
+		{"antipodes", 0, 0, 0, 180, math.Pi * EarthRadiusKm},
+	}
+	for _, tt := range tests {
+		got := Distance(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("%s: Distance() = %.3f km, want %.3f km", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"due north", 10, 20, 30, 20, 0},
+		{"due south", 10, 20, -30, 20, 180},
+		{"due east on the equator", 0, 10, 0, 20, 90},
+		{"due west across the antimeridian", 0, -179, 0, 179, 270},
+		// the example of https://en.wikipedia.org/wiki/Great-circle_navigation
+		{"valparaiso to shanghai", -33, -71.6, 31.4, 121.8, 265.587},
+		{"london to paris", 51.5074, -0.1278, 48.8566, 2.3522, 148.116},
+		{"new york jfk to london heathrow", 40.6413, -73.7781, 51.47, -0.4543, 51.353},
+	}
+	for _, tt := range tests {
+		got := Bearing(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+		if math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("%s: Bearing() = %.4f°, want %.4f°", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		name                      string
+		lat, lon, bearing, distKm float64
+		wantLat, wantLon          float64
+	}{
+		{"due east on the equator", 0, 0, 90, 2 * math.Pi * EarthRadiusKm / 360, 0, 1},
+		{"due north", 10, 20, 0, 2 * math.Pi * EarthRadiusKm / 360, 11, 20},
+		{"across the antimeridian", 0, 179.5, 90, 2 * math.Pi * EarthRadiusKm / 360, 0, -179.5},
+		{"across the antimeridian westwards", -16.5, -179.9, 280, 50, -16.421411, 179.638339},
+		// the expected values were computed independently by rotating the
+		// unit vector of the start towards the bearing
+		{"peak district to the north sea", 53.3206, -1.7297, 96.0217, 124.8, 53.188314, 0.133298},
+		{"new york jfk to london heathrow", 40.6413, -73.7781, 51.3525, 5540.0, 51.470068, -0.454553},
+	}
+	for _, tt := range tests {
+		lat, lon := Destination(tt.lat, tt.lon, tt.bearing, tt.distKm)
+		if math.Abs(lat-tt.wantLat) > 1e-5 || math.Abs(lon-tt.wantLon) > 1e-5 {
+			t.Errorf("%s: Destination() = %.6f,%.6f, want %.6f,%.6f", tt.name, lat, lon, tt.wantLat, tt.wantLon)
+		}
+	}
+}
+
+func TestValidateCoordinate(t *testing.T) {
+	for _, c := range [][2]float64{{0, 0}, {90, 180}, {-90, -180}, {48.8566, 2.3522}} {
+		if err := ValidateCoordinate(c[0], c[1]); err != nil {
+			t.Errorf("ValidateCoordinate(%v, %v) error = %v", c[0], c[1], err)
+		}
+	}
+	for _, c := range [][2]float64{{90.1, 0}, {-91, 0}, {0, 180.5}, {0, -181}, {math.NaN(), 0}, {0, math.NaN()}} {
+		if err := ValidateCoordinate(c[0], c[1]); err == nil {
+			t.Errorf("ValidateCoordinate(%v, %v) should fail", c[0], c[1])
+		}
+	}
+}
+
+func TestCompassPoint(t *testing.T) {
+	tests := []struct {
+		degrees float64
+		want    string
+	}{
+		{0, "N"}, {11.2, "N"}, {11.25, "NNE"}, {33.74, "NNE"}, {33.75, "NE"},
+		{45, "NE"}, {90, "E"}, {135, "SE"}, {180, "S"}, {202.5, "SSW"},
+		{270, "W"}, {315, "NW"}, {348.74, "NNW"}, {348.75, "N"}, {359.9, "N"},
+		// out of range bearings wrap around
+		{360, "N"}, {405, "NE"}, {-90, "W"}, {-11.25, "N"}, {-11.26, "NNW"}, {720 + 180, "S"},
+	}
+	for _, tt := range tests {
+		if got := CompassPoints[CompassPoint(tt.degrees)]; got != tt.want {
+			t.Errorf("CompassPoint(%v) = %s, want %s", tt.degrees, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// ValidateCoordinate checks that lat and lon are a coordinate in decimal
+// degrees, and tells which one is out of range.
+func ValidateCoordinate(lat, lon float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %v is not between -90 and 90", lat)
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude %v is not between -180 and 180", lon)
+	}
+	return nil
+}
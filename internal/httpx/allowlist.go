@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// AllowedHostsEnv is the environment variable that restricts the hosts the
+// functions fetching a URL chosen by the LLM may request.
+const AllowedHostsEnv = "TOOL_ALLOWED_HOSTS"
+
+// ErrHostNotAllowed is returned for a request to a host that is not on the
+// allow-list.
+var ErrHostNotAllowed = errors.New("the host is not on the allow-list of " + AllowedHostsEnv)
+
+// AllowList is a set of host names. An entry starting with "*." or "."
+// matches the subdomains of the name, e.g. "*.example.com" matches
+// "docs.example.com" but not "example.com" itself.
+type AllowList struct {
+	hosts    map[string]bool
+	suffixes []string
+}
+
+// ParseAllowList parses a comma separated list of host names. It returns nil,
+// allowing every host, when value has no entry.
+func ParseAllowList(value string) *AllowList {
+	a := &AllowList{hosts: map[string]bool{}}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "*."):
+			a.suffixes = append(a.suffixes, entry[1:])
+		case strings.HasPrefix(entry, "."):
+			a.suffixes = append(a.suffixes, entry)
+		default:
+			a.hosts[strings.TrimSuffix(entry, ".")] = true
+		}
+	}
+	if len(a.hosts) == 0 && len(a.suffixes) == 0 {
+		return nil
+	}
+	return a
+}
+
+// Allows reports whether host, without a port, is on the list. A nil list
+// allows every host.
+func (a *AllowList) Allows(host string) bool {
+	if a == nil {
+		return true
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if a.hosts[host] {
+		return true
+	}
+	for _, suffix := range a.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictHosts wraps next so that it only sends the requests to the hosts of
+// TOOL_ALLOWED_HOSTS, and fails the others with ErrHostNotAllowed. The check
+// runs before every request, the redirects too. When the variable is unset,
+// next is returned as is.
+func RestrictHosts(next http.RoundTripper) http.RoundTripper {
+	list := ParseAllowList(os.Getenv(AllowedHostsEnv))
+	if list == nil {
+		return next
+	}
+	slog.Info("[httpx] restricting the fetched hosts to " + AllowedHostsEnv)
+	return &allowListTransport{list: list, next: next}
+}
+
+type allowListTransport struct {
+	list *AllowList
+	next http.RoundTripper
+}
+
+func (t *allowListTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if host := req.URL.Hostname(); !t.list.Allows(host) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,77 @@
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAllowList(t *testing.T) {
+	list := ParseAllowList(" Example.com, *.docs.example.org ,.cdn.example.net,, ")
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.COM.", true},
+		{"www.example.com", false},
+		{"api.docs.example.org", true},
+		{"docs.example.org", false},
+		{"img.cdn.example.net", true},
+		{"evilexample.com", false},
+		{"example.com.evil.io", false},
+		{"169.254.169.254", false},
+	}
+	for _, tt := range tests {
+		if got := list.Allows(tt.host); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", " , "} {
+		if list := ParseAllowList(value); list != nil || !list.Allows("anything.example") {
+			t.Errorf("ParseAllowList(%q) should allow every host", value)
+		}
+	}
+}
+
+func TestRestrictHosts(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/redirect" {
+			// 127.0.0.2 is not on the list
+			http.Redirect(w, r, "http://127.0.0.2/", http.StatusFound)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	// the test server listens on 127.0.0.1
+	t.Setenv(AllowedHostsEnv, "127.0.0.1")
+	client := &http.Client{Transport: RestrictHosts(http.DefaultTransport)}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() of an allowed host error = %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := client.Get("http://localhost:1/"); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Get() of a host off the list error = %v, want ErrHostNotAllowed", err)
+	}
+	if _, err := client.Get(srv.URL + "/redirect"); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Get() redirected off the list error = %v, want ErrHostNotAllowed", err)
+	}
+	if requests != 2 {
+		t.Errorf("the server got %d requests, want 2", requests)
+	}
+
+	t.Setenv(AllowedHostsEnv, "")
+	if rt := RestrictHosts(http.DefaultTransport); rt != http.DefaultTransport {
+		t.Errorf("RestrictHosts() without a list = %T, want the transport unchanged", rt)
+	}
+}
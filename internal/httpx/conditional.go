@@ -0,0 +1,71 @@
+package httpx
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Conditional fetches resources that rarely change, e.g. a list of
+// currencies, with conditional requests. It keeps the value parsed from the
+// last response of each URL with its ETag and Last-Modified validators,
+// sends them back in If-None-Match and If-Modified-Since, and answers a 304
+// Not Modified with the kept value, without reading nor parsing a body.
+//
+// The kept values are shared by the callers and must not be modified. The
+// zero value is ready to use.
+type Conditional[V any] struct {
+	mu      sync.Mutex
+	entries map[string]validated[V]
+}
+
+type validated[V any] struct {
+	value        V
+	etag         string
+	lastModified string
+}
+
+// Do sends the GET request req with client, adding the validators kept for
+// its URL. A response other than a 304 is read by parse, and its value kept
+// when it is a 200 with a validator. The error of parse, e.g. for an
+// unexpected status, is returned as is.
+func (c *Conditional[V]) Do(client *http.Client, req *http.Request, parse func(*http.Response) (V, error)) (V, error) {
+	key := req.URL.String()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok {
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	defer resp.Body.Close()
+	if ok && resp.StatusCode == http.StatusNotModified {
+		return entry.value, nil
+	}
+
+	v, err := parse(resp)
+	if err != nil {
+		return v, err
+	}
+	next := validated[V]{value: v, etag: resp.Header.Get("ETag"), lastModified: resp.Header.Get("Last-Modified")}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resp.StatusCode != http.StatusOK || (next.etag == "" && next.lastModified == "") {
+		delete(c.entries, key)
+		return v, nil
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]validated[V])
+	}
+	c.entries[key] = next
+	return v, nil
+}
@@ -0,0 +1,121 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditional(t *testing.T) {
+	version := "v1"
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		etag := `"` + version + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, `{"version":%q}`, version)
+	}))
+	defer srv.Close()
+
+	var c Conditional[map[string]string]
+	parses := 0
+	parse := func(resp *http.Response) (map[string]string, error) {
+		parses++
+		var v map[string]string
+		err := json.NewDecoder(resp.Body).Decode(&v)
+		return v, err
+	}
+	get := func() map[string]string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/list.json", nil)
+		v, err := c.Do(srv.Client(), req, parse)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		return v
+	}
+
+	first := get()
+	second := get()
+	if second["version"] != "v1" || requests != 2 || notModified != 1 {
+		t.Errorf("Do() = %v after %d requests, %d not modified, want v1 after 2, 1", second, requests, notModified)
+	}
+	if parses != 1 {
+		t.Errorf("parse called %d times, want the 304 to reuse the parsed value", parses)
+	}
+	if fmt.Sprintf("%p", first) != fmt.Sprintf("%p", second) {
+		t.Error("Do() on a 304 returned a new value, want the kept one")
+	}
+
+	version = "v2"
+	if got := get(); got["version"] != "v2" || parses != 2 {
+		t.Errorf("Do() after a change = %v with %d parses, want v2 parsed again", got, parses)
+	}
+}
+
+func TestConditionalLastModified(t *testing.T) {
+	const modified = "Mon, 02 Jan 2006 15:04:05 GMT"
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get("If-Modified-Since"))
+		if r.Header.Get("If-Modified-Since") == modified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", modified)
+		w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	var c Conditional[string]
+	parse := func(resp *http.Response) (string, error) {
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return "parsed", nil
+	}
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if v, err := c.Do(srv.Client(), req, parse); err != nil || v != "parsed" {
+			t.Fatalf("Do() = %q, %v", v, err)
+		}
+	}
+	if len(sent) != 2 || sent[0] != "" || sent[1] != modified {
+		t.Errorf("If-Modified-Since sent = %q, want none then %q", sent, modified)
+	}
+}
+
+func TestConditionalWithoutValidators(t *testing.T) {
+	var conditional int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			conditional++
+		}
+		if r.URL.Path == "/broken" {
+			w.Header().Set("ETag", `"x"`)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	var c Conditional[int]
+	parse := func(resp *http.Response) (int, error) { return resp.StatusCode, nil }
+	for _, path := range []string{"/plain", "/plain", "/broken", "/broken"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		if _, err := c.Do(srv.Client(), req, parse); err != nil {
+			t.Fatalf("Do(%s) error = %v", path, err)
+		}
+	}
+	if conditional != 0 {
+		t.Errorf("%d conditional requests, want none without a 200 with validators", conditional)
+	}
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Decompress wraps next so that the gzip encoded bodies are decoded. The
+// http.Transport only decodes the responses to the requests it added
+// "Accept-Encoding: gzip" to itself: not when the caller set the header, nor
+// when a server compresses a response it was not asked to.
+//
+// A decoded response has no Content-Encoding and an unknown ContentLength,
+// and its Uncompressed is true, like the ones decoded by http.Transport.
+func Decompress(next http.RoundTripper) http.RoundTripper {
+	return &gzipTransport{next: next}
+}
+
+type gzipTransport struct {
+	next http.RoundTripper
+}
+
+func (t *gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Uncompressed || req.Method == http.MethodHead {
+		return resp, err
+	}
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+	default:
+		return resp, nil
+	}
+
+	resp.Body = &gzipBody{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// gzipBody decodes body on the first Read, so that an empty body, e.g. of a
+// 204 or 304 response, is only an error if it is read.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.zr.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,103 @@
+package httpx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	io.WriteString(zw, s)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewClientDecompress(t *testing.T) {
+	const payload = `{"name":"Paris","coord":{"lat":48.8566,"lon":2.3522}}`
+	body := gzipped(t, payload)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/empty":
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusNoContent)
+		case "/plain":
+			io.WriteString(w, payload)
+		default:
+			// compressed whatever the Accept-Encoding of the request
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(body)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(ProxyEnv, "off")
+	client := NewClient(5 * time.Second)
+
+	get := func(path string, header http.Header) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		got, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Get(%s) read error = %v", path, err)
+		}
+		return resp, string(got)
+	}
+
+	for name, header := range map[string]http.Header{
+		"set by the transport": nil,
+		"set by the caller":    {"Accept-Encoding": {"gzip"}, "User-Agent": {"test"}},
+		"not accepted":         {"Accept-Encoding": {"identity"}},
+	} {
+		resp, got := get("/json", header)
+		if got != payload {
+			t.Errorf("%s: body = %q, want %q", name, got, payload)
+		}
+		if resp.Header.Get("Content-Encoding") != "" || !resp.Uncompressed || resp.ContentLength != -1 {
+			t.Errorf("%s: the response is still marked as encoded: %v, %d", name, resp.Header, resp.ContentLength)
+		}
+	}
+
+	if resp, got := get("/empty", http.Header{"Accept-Encoding": {"gzip"}}); resp.StatusCode != http.StatusNoContent || got != "" {
+		t.Errorf("an empty gzip response = %d %q", resp.StatusCode, got)
+	}
+	if _, got := get("/plain", http.Header{"Accept-Encoding": {"gzip"}}); got != payload {
+		t.Errorf("an identity response = %q, want %q", got, payload)
+	}
+}
+
+func TestDecompressInvalid(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		io.WriteString(w, "not gzip")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Decompress(http.DefaultTransport)}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); err == nil {
+		t.Error("reading a corrupt gzip body should fail")
+	}
+}
@@ -0,0 +1,75 @@
+// Package httpx builds the HTTP clients of the functions that call a fixed
+// upstream API, so that all of them can be routed through a proxy.
+//
+// By default a client honors the standard HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables. TOOL_HTTP_PROXY overrides them for the
+// functions only, without affecting other programs of the host: it is either
+// the URL of the proxy, or "off" to connect directly.
+//
+// The netguard clients, which fetch URLs chosen by the LLM, never use a proxy:
+// the proxy would connect on their behalf and bypass the address checks.
+// Those clients, and the others fetching such URLs, restrict the hosts they
+// request to TOOL_ALLOWED_HOSTS when it is set, see RestrictHosts.
+package httpx
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ProxyEnv is the environment variable that overrides the standard proxy
+// variables.
+const ProxyEnv = "TOOL_HTTP_PROXY"
+
+// NewClient returns an http.Client that uses the proxy configured in the
+// environment, decodes the gzip encoded responses and gives up after
+// timeout.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: Decompress(NewTransport()), Timeout: timeout}
+}
+
+// NewTransport returns a clone of http.DefaultTransport that uses the proxy
+// configured in the environment. An invalid TOOL_HTTP_PROXY is logged and
+// ignored.
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxy, err := Proxy(os.Getenv(ProxyEnv))
+	if err != nil {
+		slog.Warn("[httpx] ignoring "+ProxyEnv, "err", err)
+		proxy = http.ProxyFromEnvironment
+	}
+	transport.Proxy = proxy
+	return transport
+}
+
+// Proxy returns the Transport.Proxy function for the value of
+// TOOL_HTTP_PROXY: the standard environment variables when it is empty, no
+// proxy when it is "off", and the given proxy URL otherwise.
+func Proxy(value string) (func(*http.Request) (*url.URL, error), error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case "off":
+		return nil, nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("the proxy URL %q must start with http://, https:// or socks5://", value)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("the proxy URL %q has no host", value)
+	}
+	return http.ProxyURL(u), nil
+}
@@ -0,0 +1,31 @@
+package httpx
+
+import (
+	"errors"
+	"net/url"
+)
+
+// Redact returns err with the query parameters params of the URL of its
+// *url.Error replaced by "REDACTED", so that an API key sent in the query
+// does not end up in the error text, which the functions log and write back
+// to the LLM. The cause is kept for errors.Is. Any other error is returned
+// as it is.
+func Redact(err error, params ...string) error {
+	var ue *url.Error
+	if !errors.As(err, &ue) {
+		return err
+	}
+	u, perr := url.Parse(ue.URL)
+	if perr != nil {
+		// the URL is unreadable, drop it with whatever it holds
+		return &url.Error{Op: ue.Op, URL: "(redacted)", Err: ue.Err}
+	}
+	q := u.Query()
+	for _, p := range params {
+		if q.Has(p) {
+			q.Set(p, "REDACTED")
+		}
+	}
+	u.RawQuery = q.Encode()
+	return &url.Error{Op: ue.Op, URL: u.String(), Err: ue.Err}
+}
@@ -0,0 +1,39 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedact(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/data?q=paris&appid=secret-key", nil)
+	_, err := NewClient(5 * time.Second).Do(req)
+	if err == nil || !strings.Contains(err.Error(), "secret-key") {
+		t.Fatalf("Do() error = %v, want the URL with the key", err)
+	}
+
+	err = Redact(err, "appid")
+	if strings.Contains(err.Error(), "secret-key") || !strings.Contains(err.Error(), "appid=REDACTED") || !strings.Contains(err.Error(), "q=paris") {
+		t.Errorf("Redact() = %v, want the key replaced", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Redact() = %v lost its cause", err)
+	}
+
+	other := errors.New("appid=secret-key")
+	if got := Redact(other, "appid"); got != other {
+		t.Errorf("Redact() of an error without a URL = %v", got)
+	}
+}
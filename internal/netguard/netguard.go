@@ -0,0 +1,126 @@
+// Package netguard guards the functions that fetch a URL chosen by the LLM
+// against server side request forgery: the LLM, or a prompt injected into
+// what it reads, must not make a function request the internal network of
+// the host it runs on.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+)
+
+// ErrBlockedAddress is returned when a request would connect to an address
+// that is not public.
+var ErrBlockedAddress = errors.New("the address is not a public internet address")
+
+// CheckURL parses rawURL and checks that it is an absolute http or https URL.
+func CheckURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("only http and https URLs are allowed, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("the URL has no host")
+	}
+	return u, nil
+}
+
+// IsPublic reports whether ip is a public unicast address, and not e.g. a
+// loopback, private, link local or carrier grade NAT address.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return false
+	}
+	for _, p := range reserved {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	if v4, ok := embeddedIPv4(ip); ok {
+		return IsPublic(v4)
+	}
+	return true
+}
+
+var (
+	// nat64 is the well-known NAT64 prefix, 64:ff9b::7f00:1 is 127.0.0.1
+	// through the translator of the network
+	nat64 = netip.MustParsePrefix("64:ff9b::/96")
+	// sixToFour is the 6to4 prefix, 2002:7f00:1:: is relayed to 127.0.0.1
+	sixToFour = netip.MustParsePrefix("2002::/16")
+)
+
+// embeddedIPv4 returns the IPv4 address that a NAT64 or 6to4 address reaches.
+func embeddedIPv4(ip netip.Addr) (netip.Addr, bool) {
+	b := ip.As16()
+	switch {
+	case nat64.Contains(ip):
+		return netip.AddrFrom4([4]byte(b[12:16])), true
+	case sixToFour.Contains(ip):
+		return netip.AddrFrom4([4]byte(b[2:6])), true
+	}
+	return netip.Addr{}, false
+}
+
+// reserved are the non public ranges netip has no method for.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// control rejects a connection to an address that is not public. It runs
+// after name resolution for every connection, including those of redirects,
+// so a host name resolving to an internal address is blocked as well.
+func control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// NewClient returns an http.Client that only connects to public addresses
+// and gives up after timeout. When TOOL_ALLOWED_HOSTS is set, it only
+// requests the hosts on that list too, see httpx.RestrictHosts. Like the
+// httpx clients, it decodes the gzip encoded responses.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: NewTransport(timeout), Timeout: timeout}
+}
+
+// NewTransport returns the transport of NewClient, dialing within timeout,
+// for a function that builds its own client, e.g. to follow the redirects
+// itself.
+func NewTransport(timeout time.Duration) http.RoundTripper {
+	dialer := &net.Dialer{Timeout: timeout, Control: control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return httpx.RestrictHosts(httpx.Decompress(transport))
+}
@@ -0,0 +1,64 @@
+// Package numfmt writes numbers with the digit grouping and the decimal
+// separator of a locale, e.g. for the number and the money formatting
+// functions.
+package numfmt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Locale is how the numbers are written in a language.
+type Locale struct {
+	// Group separates the groups of the integer digits.
+	Group string
+	// Decimal separates the integer digits from the decimals.
+	Decimal string
+	// Indian groups the integer digits by three, then by two: lakh and
+	// crore instead of hundred thousand and ten million.
+	Indian bool
+	// MinGrouping is how many digits the integer part needs before it is
+	// grouped at all, e.g. 5 in Spanish, where 1234 is not grouped.
+	MinGrouping int
+}
+
+// Format writes the absolute value of the finite value in loc, rounded to
+// decimals, or with the decimals of the value when decimals is negative.
+// negative reports whether a minus sign goes with it, which is not the case
+// of a negative value rounded to zero.
+func Format(value float64, decimals int, loc Locale) (s string, negative bool) {
+	digits := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+
+	s = strings.Join(Group(integer, loc), loc.Group)
+	if fraction != "" {
+		s += loc.Decimal + fraction
+	}
+	return s, value < 0 && strings.Trim(digits, "0.") != ""
+}
+
+// Group splits the integer digits into the groups of the locale, the most
+// significant first. The last group has three digits and the others three
+// too, or two in the Indian grouping.
+func Group(integer string, loc Locale) []string {
+	if len(integer) <= 3 || len(integer) < loc.MinGrouping {
+		return []string{integer}
+	}
+	size := 3
+	if loc.Indian {
+		size = 2
+	}
+
+	head, last := integer[:len(integer)-3], integer[len(integer)-3:]
+	var groups []string
+	for len(head) > size {
+		groups = append(groups, head[len(head)-size:])
+		head = head[:len(head)-size]
+	}
+	groups = append(groups, head)
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	return append(groups, last)
+}
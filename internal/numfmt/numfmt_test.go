@@ -0,0 +1,50 @@
+package numfmt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	en := Locale{Group: ",", Decimal: "."}
+	tests := []struct {
+		value    float64
+		decimals int
+		loc      Locale
+		want     string
+		negative bool
+	}{
+		{1234567.891, 2, en, "1,234,567.89", false},
+		{-1234.5, -1, Locale{Group: ".", Decimal: ","}, "1.234,5", true},
+		{-0.001, 2, en, "0.00", false},
+		{1234, 0, Locale{Group: ".", Decimal: ",", MinGrouping: 5}, "1234", false},
+		{12345678, 0, Locale{Group: ",", Decimal: ".", Indian: true}, "1,23,45,678", false},
+	}
+	for _, tt := range tests {
+		got, negative := Format(tt.value, tt.decimals, tt.loc)
+		if got != tt.want || negative != tt.negative {
+			t.Errorf("Format(%v, %d) = %q, %v, want %q, %v", tt.value, tt.decimals, got, negative, tt.want, tt.negative)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		integer string
+		indian  bool
+		want    string
+	}{
+		{"1", false, "1"},
+		{"123", false, "123"},
+		{"1234", false, "1 234"},
+		{"123456789", false, "123 456 789"},
+		{"1234", true, "1 234"},
+		{"123456", true, "1 23 456"},
+		{"1234567", true, "12 34 567"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(Group(tt.integer, Locale{Indian: tt.indian}), " "); got != tt.want {
+			t.Errorf("Group(%q, indian %v) = %q, want %q", tt.integer, tt.indian, got, tt.want)
+		}
+	}
+}
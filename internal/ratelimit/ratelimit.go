@@ -0,0 +1,122 @@
+// Package ratelimit is a token bucket limiting the calls of the functions
+// to a rate-limited upstream API, and the set of the limiters shared in the
+// process, so that the remaining quota can be reported.
+package ratelimit
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrLimited is returned instead of calling the upstream when its quota is
+// used up.
+var ErrLimited = errors.New("rate limit exceeded")
+
+// Limiter is a token bucket of capacity calls, refilled evenly over a
+// period: a full bucket allows a burst of capacity calls, then one call
+// every period/capacity. It is safe for concurrent use.
+type Limiter struct {
+	capacity int
+	period   time.Duration
+	// now is replaced in tests
+	now func() time.Time
+
+	mu      sync.Mutex
+	tokens  float64
+	updated time.Time
+}
+
+// New returns a full Limiter allowing capacity calls per period.
+func New(capacity int, period time.Duration) *Limiter {
+	l := &Limiter{capacity: max(capacity, 1), period: period, now: time.Now}
+	l.tokens = float64(l.capacity)
+	l.updated = l.now()
+	return l
+}
+
+// Allow takes a token from l, it returns false when none is left.
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// refill adds the tokens accrued since the last update. l.mu must be held.
+func (l *Limiter) refill() {
+	now := l.now()
+	if elapsed := now.Sub(l.updated); elapsed > 0 {
+		l.tokens = min(float64(l.capacity), l.tokens+float64(l.capacity)*elapsed.Seconds()/l.period.Seconds())
+	}
+	l.updated = now
+}
+
+// Status is a snapshot of a Limiter.
+type Status struct {
+	// Name is the name the limiter is shared under, empty for a Limiter
+	// that is not.
+	Name     string
+	Capacity int
+	Period   time.Duration
+	// Remaining is the number of calls allowed right now.
+	Remaining int
+	// Reset is when the bucket is full again, now if it is.
+	Reset time.Time
+}
+
+// Status returns the current state of l.
+func (l *Limiter) Status() Status {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	missing := float64(l.capacity) - l.tokens
+	return Status{
+		Capacity:  l.capacity,
+		Period:    l.period,
+		Remaining: int(l.tokens),
+		Reset:     l.updated.Add(time.Duration(missing / float64(l.capacity) * float64(l.period))),
+	}
+}
+
+var (
+	mu     sync.Mutex
+	shared = make(map[string]*Limiter)
+)
+
+// Shared returns the Limiter shared under name, e.g. the name of the
+// upstream, creating it with New(capacity, period) on the first call. The
+// later calls return the same Limiter whatever their capacity and period.
+func Shared(name string, capacity int, period time.Duration) *Limiter {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l, ok := shared[name]
+	if !ok {
+		l = New(capacity, period)
+		shared[name] = l
+	}
+	return l
+}
+
+// Statuses returns the status of the shared limiters sorted by name.
+func Statuses() []Status {
+	mu.Lock()
+	statuses := make([]Status, 0, len(shared))
+	for name, l := range shared {
+		st := l.Status()
+		st.Name = name
+		statuses = append(statuses, st)
+	}
+	mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
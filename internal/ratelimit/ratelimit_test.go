@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock pins the time of l, which starts full at start.
+func fakeClock(l *Limiter, start time.Time) *time.Time {
+	now := start
+	l.now = func() time.Time { return now }
+	l.updated = now
+	return &now
+}
+
+func TestLimiter(t *testing.T) {
+	start := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	l := New(3, time.Minute)
+	now := fakeClock(l, start)
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("call %d was limited, want a burst of 3", i+1)
+		}
+	}
+	if l.Allow() {
+		t.Fatal("the 4th call was allowed, want it limited")
+	}
+
+	st := l.Status()
+	if st.Remaining != 0 || !st.Reset.Equal(start.Add(time.Minute)) {
+		t.Errorf("Status() = %d left, reset at %s, want 0, %s", st.Remaining, st.Reset, start.Add(time.Minute))
+	}
+
+	// a token every 20 seconds
+	*now = start.Add(19 * time.Second)
+	if l.Allow() {
+		t.Error("a call after 19s was allowed, want it limited")
+	}
+	*now = start.Add(20 * time.Second)
+	if !l.Allow() {
+		t.Error("a call after 20s was limited, want it allowed")
+	}
+
+	// the bucket does not fill above its capacity
+	*now = start.Add(time.Hour)
+	st = l.Status()
+	if st.Remaining != 3 || !st.Reset.Equal(*now) {
+		t.Errorf("Status() = %d left, reset at %s, want 3, %s", st.Remaining, st.Reset, *now)
+	}
+}
+
+func TestShared(t *testing.T) {
+	l := Shared("test-upstream", 2, time.Second)
+	if Shared("test-upstream", 10, time.Hour) != l {
+		t.Fatal("Shared() returned another Limiter for the same name")
+	}
+	l.Allow()
+
+	var found bool
+	for _, st := range Statuses() {
+		if st.Name == "test-upstream" {
+			found = true
+			if st.Capacity != 2 || st.Period != time.Second || st.Remaining != 1 {
+				t.Errorf("Statuses() = %+v, want 1 of 2 per second left", st)
+			}
+		}
+	}
+	if !found {
+		t.Error("Statuses() is missing the shared limiter")
+	}
+}
@@ -0,0 +1,257 @@
+// Package registry is a catalog of the LLM functions. Each function
+// registers its name, description and input schema from an init function,
+// and the catalog can be serialized to the OpenAI "tools" format to build a
+// function calling manifest.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/schema"
+)
+
+// Tool describes a registered LLM function.
+type Tool struct {
+	// Name is the function name, as passed to `yomo run -n`.
+	Name string
+	// Description is the value returned by the function's Description().
+	Description string
+	// InputSchema is the value returned by the function's InputSchema(), a
+	// pointer to a struct with jsonschema tags, or nil if the function takes
+	// no arguments.
+	InputSchema any
+	// Env lists the environment variables the function can not work
+	// without, e.g. its API key. An entry made with AnyOf is set when any of
+	// its variables is.
+	Env []string
+	// Upstream is the base URL of the API the function calls, if it calls
+	// one, for a health check to probe.
+	Upstream string
+}
+
+// envSeparator separates the alternative variables of an Env entry.
+const envSeparator = "|"
+
+// AnyOf returns an Env entry that is set when any of the environment
+// variables names is, e.g. a single API key or a list of keys to rotate.
+func AnyOf(names ...string) string {
+	return strings.Join(names, envSeparator)
+}
+
+// MissingEnv returns the entries of t.Env that getenv finds empty, the
+// alternatives of an entry joined with "or", e.g. "API_KEY or API_KEYS".
+func (t Tool) MissingEnv(getenv func(string) string) []string {
+	var missing []string
+	for _, entry := range t.Env {
+		names := strings.Split(entry, envSeparator)
+		set := false
+		for _, name := range names {
+			if strings.TrimSpace(getenv(name)) != "" {
+				set = true
+				break
+			}
+		}
+		if !set {
+			missing = append(missing, strings.Join(names, " or "))
+		}
+	}
+	return missing
+}
+
+// Registry is a set of tools keyed by name. The zero value is not usable,
+// create one with New.
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+}
+
+// New returns an empty Registry.
+func New() *Registry {
+	return &Registry{tools: make(map[string]Tool)}
+}
+
+// Register adds a tool to the registry. It panics if the name is empty or
+// already registered, as that is a programming error.
+func (r *Registry) Register(t Tool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if t.Name == "" {
+		panic("registry: tool name is empty")
+	}
+	if _, dup := r.tools[t.Name]; dup {
+		panic("registry: tool " + t.Name + " is registered twice")
+	}
+	r.tools[t.Name] = t
+}
+
+// Lookup returns the tool registered with the given name.
+func (r *Registry) Lookup(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// Tools returns the registered tools sorted by name.
+func (r *Registry) Tools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.tools))
+	for _, t := range r.tools {
+		tools = append(tools, t)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// openAITool is an entry of the "tools" array of the OpenAI chat completions
+// API.
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Parameters  *schema.Parameters `json:"parameters"`
+}
+
+// manifestTool is an entry of a manifest: an OpenAI tool with the Env and
+// the Upstream of the function, which the OpenAI API does not take.
+type manifestTool struct {
+	openAITool
+	Env      []string `json:"env,omitempty"`
+	Upstream string   `json:"upstream,omitempty"`
+}
+
+// manifest returns the entries of the registered tools sorted by name.
+func (r *Registry) manifest() ([]manifestTool, error) {
+	tools := r.Tools()
+	out := make([]manifestTool, 0, len(tools))
+	for _, t := range tools {
+		params, err := schema.Reflect(t.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("registry: tool %s: %w", t.Name, err)
+		}
+		out = append(out, manifestTool{
+			openAITool: openAITool{
+				Type: "function",
+				Function: openAIFunction{
+					Name:        t.Name,
+					Description: t.Description,
+					Parameters:  params,
+				},
+			},
+			Env:      t.Env,
+			Upstream: t.Upstream,
+		})
+	}
+	return out, nil
+}
+
+// MarshalOpenAI serializes the registered tools to the OpenAI "tools" JSON
+// format.
+func (r *Registry) MarshalOpenAI() ([]byte, error) {
+	entries, err := r.manifest()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]openAITool, len(entries))
+	for i, e := range entries {
+		out[i] = e.openAITool
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// MarshalManifest serializes the registered tools to a manifest: the OpenAI
+// "tools" JSON array with the "env" and "upstream" of each tool next to its
+// "type" and "function", for the health check of a deployment.
+func (r *Registry) MarshalManifest() ([]byte, error) {
+	out, err := r.manifest()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// ManifestEnv is the environment variable of the path of the manifest of a
+// deployment, a JSON file with its tools as MarshalManifest writes them, or
+// just the OpenAI "tools" array the LLM bridge is given. Every function of
+// this repository runs in its own process, the manifest is how a function
+// learns about the others.
+const ManifestEnv = "LLM_TOOLS_MANIFEST"
+
+// LoadOpenAI registers the tools of data, an OpenAI "tools" JSON array, with
+// their parameters as the InputSchema, and their "env" and "upstream" when
+// data is a manifest. A tool whose name is already registered keeps its own
+// registration.
+func (r *Registry) LoadOpenAI(data []byte) error {
+	var tools []manifestTool
+	if err := json.Unmarshal(data, &tools); err != nil {
+		return fmt.Errorf("registry: the tools are not valid JSON: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, t := range tools {
+		if t.Type != "function" || t.Function.Name == "" {
+			return fmt.Errorf("registry: tool %d is not a named function", i)
+		}
+		if _, dup := r.tools[t.Function.Name]; dup {
+			continue
+		}
+		tool := Tool{Name: t.Function.Name, Description: t.Function.Description, Env: t.Env, Upstream: t.Upstream}
+		if t.Function.Parameters != nil {
+			tool.InputSchema = t.Function.Parameters
+		}
+		r.tools[tool.Name] = tool
+	}
+	return nil
+}
+
+// LoadManifest registers into r the tools of the manifest file at the path
+// of ManifestEnv, if it is set.
+func (r *Registry) LoadManifest() error {
+	path := os.Getenv(ManifestEnv)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("registry: %w", err)
+	}
+	return r.LoadOpenAI(data)
+}
+
+// Default is the registry the functions register themselves into.
+var Default = New()
+
+// Register adds a tool to the Default registry.
+func Register(t Tool) { Default.Register(t) }
+
+// Lookup returns a tool of the Default registry.
+func Lookup(name string) (Tool, bool) { return Default.Lookup(name) }
+
+// Tools returns the tools of the Default registry sorted by name.
+func Tools() []Tool { return Default.Tools() }
+
+// MarshalOpenAI serializes the Default registry to the OpenAI "tools" JSON
+// format.
+func MarshalOpenAI() ([]byte, error) { return Default.MarshalOpenAI() }
+
+// MarshalManifest serializes the Default registry to a manifest.
+func MarshalManifest() ([]byte, error) { return Default.MarshalManifest() }
+
+// LoadManifest registers into the Default registry the tools of the manifest
+// file at the path of ManifestEnv, if it is set.
+func LoadManifest() error { return Default.LoadManifest() }
@@ -0,0 +1,230 @@
+package registry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type weatherArguments struct {
+	City      string  `json:"city" jsonschema:"description=The city name to get the weather for"`
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the city"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the city"`
+}
+
+type currencyArguments struct {
+	Source string  `json:"source" jsonschema:"description=The source currency"`
+	Target string  `json:"target" jsonschema:"description=The target currency"`
+	Amount float64 `json:"amount,omitempty" jsonschema:"description=The amount to convert"`
+}
+
+func TestMarshalOpenAI(t *testing.T) {
+	r := New()
+	r.Register(Tool{Name: "get-weather", Description: "Get current weather for a given city", InputSchema: &weatherArguments{}})
+	r.Register(Tool{Name: "currency-converter", Description: "Convert currencies", InputSchema: &currencyArguments{}})
+	r.Register(Tool{Name: "get-utc-time", Description: "Get current UTC time"})
+
+	buf, err := r.MarshalOpenAI()
+	if err != nil {
+		t.Fatalf("MarshalOpenAI() error = %v", err)
+	}
+
+	var tools []struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Parameters  struct {
+				Type       string                     `json:"type"`
+				Properties map[string]json.RawMessage `json:"properties"`
+				Required   []string                   `json:"required"`
+			} `json:"parameters"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(buf, &tools); err != nil {
+		t.Fatalf("MarshalOpenAI() produced invalid JSON: %v\n%s", err, buf)
+	}
+	if len(tools) != 3 {
+		t.Fatalf("MarshalOpenAI() returned %d tools, want 3", len(tools))
+	}
+
+	// tools are sorted by name
+	wantNames := []string{"currency-converter", "get-utc-time", "get-weather"}
+	for i, tool := range tools {
+		if tool.Type != "function" || tool.Function.Name != wantNames[i] {
+			t.Errorf("tool %d = %s %s, want function %s", i, tool.Type, tool.Function.Name, wantNames[i])
+		}
+		if tool.Function.Parameters.Type != "object" {
+			t.Errorf("tool %s parameters type = %s, want object", tool.Function.Name, tool.Function.Parameters.Type)
+		}
+	}
+
+	currency := tools[0].Function.Parameters
+	if len(currency.Properties) != 3 {
+		t.Errorf("currency-converter has %d properties, want 3", len(currency.Properties))
+	}
+	if len(currency.Required) != 2 || currency.Required[0] != "source" || currency.Required[1] != "target" {
+		t.Errorf("currency-converter required = %v, want [source target]", currency.Required)
+	}
+
+	if n := len(tools[1].Function.Parameters.Properties); n != 0 {
+		t.Errorf("get-utc-time has %d properties, want none", n)
+	}
+
+	var city struct {
+		Type        string `json:"type"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(tools[2].Function.Parameters.Properties["city"], &city); err != nil {
+		t.Fatal(err)
+	}
+	if city.Type != "string" || city.Description != "The city name to get the weather for" {
+		t.Errorf("get-weather city property = %+v", city)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	r := New()
+	r.Register(Tool{Name: "get-weather"})
+
+	if _, ok := r.Lookup("get-weather"); !ok {
+		t.Error("Lookup() did not find a registered tool")
+	}
+	if _, ok := r.Lookup("unknown"); ok {
+		t.Error("Lookup() found an unregistered tool")
+	}
+
+	assertPanics(t, "duplicate name", func() { r.Register(Tool{Name: "get-weather"}) })
+	assertPanics(t, "empty name", func() { r.Register(Tool{}) })
+}
+
+func TestMarshalOpenAIRejectsNonStruct(t *testing.T) {
+	r := New()
+	r.Register(Tool{Name: "bad", InputSchema: "not a struct"})
+	if _, err := r.MarshalOpenAI(); err == nil {
+		t.Error("MarshalOpenAI() should reject a non struct input schema")
+	}
+}
+
+func TestLoadOpenAI(t *testing.T) {
+	r := New()
+	r.Register(Tool{Name: "get-weather", Description: "Get current weather for a given city", InputSchema: &weatherArguments{}})
+	r.Register(Tool{Name: "get-utc-time", Description: "Get current UTC time"})
+	want, err := r.MarshalOpenAI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := New()
+	loaded.Register(Tool{Name: "get-utc-time", Description: "Get current UTC time", Env: []string{"TZ"}})
+	if err := loaded.LoadOpenAI(want); err != nil {
+		t.Fatalf("LoadOpenAI() error = %v", err)
+	}
+	got, err := loaded.MarshalOpenAI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("MarshalOpenAI() after LoadOpenAI() =\n%s\nwant\n%s", got, want)
+	}
+	// the own registration of a tool wins over the manifest
+	if tool, _ := loaded.Lookup("get-utc-time"); len(tool.Env) != 1 {
+		t.Errorf("LoadOpenAI() replaced the registered get-utc-time: %+v", tool)
+	}
+
+	for _, bad := range []string{`{"type":"function"}`, `[{"type":"function","function":{"description":"no name"}}]`} {
+		if err := New().LoadOpenAI([]byte(bad)); err == nil {
+			t.Errorf("LoadOpenAI(%s) should fail", bad)
+		}
+	}
+}
+
+func TestMarshalManifest(t *testing.T) {
+	r := New()
+	r.Register(Tool{Name: "get-weather", Description: "Get current weather for a given city", InputSchema: &weatherArguments{}, Env: []string{AnyOf("API_KEY", "API_KEYS")}, Upstream: "https://api.openweathermap.org"})
+	r.Register(Tool{Name: "get-utc-time", Description: "Get current UTC time"})
+	manifest, err := r.MarshalManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the OpenAI tools have no env nor upstream
+	tools, err := r.MarshalOpenAI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(tools), "upstream") || strings.Contains(string(tools), "API_KEY") {
+		t.Errorf("MarshalOpenAI() = %s, want no manifest fields", tools)
+	}
+
+	loaded := New()
+	if err := loaded.LoadOpenAI(manifest); err != nil {
+		t.Fatalf("LoadOpenAI() error = %v", err)
+	}
+	tool, ok := loaded.Lookup("get-weather")
+	if !ok || tool.Upstream != "https://api.openweathermap.org" || len(tool.Env) != 1 || tool.Env[0] != "API_KEY|API_KEYS" {
+		t.Errorf("Lookup() of a manifest tool = %+v", tool)
+	}
+	if again, _ := loaded.MarshalManifest(); string(again) != string(manifest) {
+		t.Errorf("MarshalManifest() after LoadOpenAI() =\n%s\nwant\n%s", again, manifest)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.json")
+	manifest := `[{"type":"function","function":{"name":"zipcode","description":"Look up a postal code","parameters":{"type":"object","properties":{"code":{"type":"string"}},"required":["code"]}}}]`
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := New()
+	t.Setenv(ManifestEnv, "")
+	if err := r.LoadManifest(); err != nil || len(r.Tools()) != 0 {
+		t.Fatalf("LoadManifest() without a manifest = %v, %d tools", err, len(r.Tools()))
+	}
+	t.Setenv(ManifestEnv, path)
+	if err := r.LoadManifest(); err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if tool, ok := r.Lookup("zipcode"); !ok || tool.Description != "Look up a postal code" {
+		t.Errorf("Lookup() of a manifest tool = %+v, %v", tool, ok)
+	}
+	t.Setenv(ManifestEnv, path+".missing")
+	if err := New().LoadManifest(); err == nil {
+		t.Error("LoadManifest() of a missing file should fail")
+	}
+}
+
+func assertPanics(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s: expected a panic", name)
+		}
+	}()
+	fn()
+}
+
+func TestMissingEnv(t *testing.T) {
+	tool := Tool{Name: "get-weather", Env: []string{AnyOf("API_KEY", "API_KEYS"), "FROM_EMAIL"}}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"single key", map[string]string{"API_KEY": "k", "FROM_EMAIL": "a@b.c"}, ""},
+		{"rotated keys", map[string]string{"API_KEYS": "k1,k2", "FROM_EMAIL": "a@b.c"}, ""},
+		{"blank", map[string]string{"API_KEY": " ", "FROM_EMAIL": "a@b.c"}, "API_KEY or API_KEYS"},
+		{"none", nil, "API_KEY or API_KEYS, FROM_EMAIL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(tool.MissingEnv(func(name string) string { return tt.env[name] }), ", ")
+			if got != tt.want {
+				t.Errorf("MissingEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
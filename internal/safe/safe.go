@@ -0,0 +1,37 @@
+// Package safe keeps a panicking Handler from crashing the serverless
+// instance.
+package safe
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/errs"
+	"github.com/yomorun/llm-function-calling-examples/internal/sfn"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// ErrorMessage is the message of the errs.Internal error written back to the
+// LLM when a Handler panics.
+const ErrorMessage = "internal tool error"
+
+// Wrap returns a Handler that runs fn and recovers from its panics, e.g. on
+// an unexpected nil in an API response. The panic and its stack are logged,
+// and an errs.Internal error is written back with sfn.WriteError so the LLM
+// gets an answer to its call:
+//
+//	func Handler(ctx serverless.Context) {
+//		safe.Wrap(handle)(ctx)
+//	}
+func Wrap(fn func(serverless.Context)) func(serverless.Context) {
+	return func(ctx serverless.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("[sfn] handler panic", "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+				sfn.WriteError(ctx, errs.New(errs.Internal, ErrorMessage))
+			}
+		}()
+		fn(ctx)
+	}
+}
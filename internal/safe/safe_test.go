@@ -0,0 +1,55 @@
+package safe
+
+import (
+	"testing"
+
+	"github.com/yomorun/yomo/ai"
+	"github.com/yomorun/yomo/serverless"
+	"github.com/yomorun/yomo/serverless/mock"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(serverless.Context)
+		want    string
+	}{
+		{
+			name:    "panic",
+			handler: func(serverless.Context) { panic("boom") },
+			want:    `{"ok":false,"error":{"code":"internal","message":"internal tool error"}}`,
+		},
+		{
+			name: "nil pointer",
+			handler: func(ctx serverless.Context) {
+				var conditions *struct{ Summary string }
+				ctx.WriteLLMResult(conditions.Summary)
+			},
+			want: `{"ok":false,"error":{"code":"internal","message":"internal tool error"}}`,
+		},
+		{
+			name:    "no panic",
+			handler: func(ctx serverless.Context) { ctx.WriteLLMResult("sunny") },
+			want:    "sunny",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{}"}`), 0x33)
+			Wrap(tt.handler)(ctx)
+
+			records := ctx.RecordsWritten()
+			if len(records) != 1 || records[0].Tag != ai.ReducerTag {
+				t.Fatalf("records = %v, want one result", records)
+			}
+			var fnCall ai.FunctionCall
+			if err := fnCall.FromBytes(records[0].Data); err != nil {
+				t.Fatal(err)
+			}
+			if fnCall.Result != tt.want {
+				t.Errorf("result = %q, want %q", fnCall.Result, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package schema turns the InputSchema of a function into the "parameters"
+// JSON schema of an OpenAI function definition.
+package schema
+
+import (
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// Parameters is the "parameters" object of an OpenAI function definition,
+// e.g. {"type":"object","properties":{...},"required":[...]}.
+type Parameters struct {
+	Type       string                                             `json:"type"`
+	Properties *orderedmap.OrderedMap[string, *jsonschema.Schema] `json:"properties"`
+	Required   []string                                           `json:"required,omitempty"`
+}
+
+// Reflect runs the jsonschema reflector over the value returned by a
+// function's InputSchema(), a pointer to a struct with jsonschema tags. The
+// properties keep the order of the struct fields, and a field is required
+// unless its json tag has omitempty. A nil input schema gives an object
+// without properties. Parameters already decoded from JSON, e.g. those of a
+// function loaded from a manifest, are returned as they are.
+func Reflect(inputSchema any) (*Parameters, error) {
+	if p, ok := inputSchema.(*Parameters); ok && p != nil {
+		return p, nil
+	}
+	if inputSchema == nil {
+		return &Parameters{
+			Type:       "object",
+			Properties: orderedmap.New[string, *jsonschema.Schema](),
+		}, nil
+	}
+
+	reflector := jsonschema.Reflector{DoNotReference: true, Anonymous: true}
+	s := reflector.Reflect(inputSchema)
+	if s.Type != "object" {
+		return nil, fmt.Errorf("input schema must be a struct, got %T", inputSchema)
+	}
+
+	properties := s.Properties
+	if properties == nil {
+		properties = orderedmap.New[string, *jsonschema.Schema]()
+	}
+	return &Parameters{
+		Type:       "object",
+		Properties: properties,
+		Required:   s.Required,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// weatherArguments mirrors the InputSchema of golang-tool-get-weather.
+type weatherArguments struct {
+	City      string  `json:"city" jsonschema:"description=The city name to get the weather for"`
+	Latitude  float64 `json:"latitude" jsonschema:"description=The latitude of the city in decimal format,minimum=-90,maximum=90"`
+	Longitude float64 `json:"longitude" jsonschema:"description=The longitude of the city in decimal format,minimum=-180,maximum=180"`
+}
+
+// optionalArguments has an optional field and a description with an escaped
+// comma.
+type optionalArguments struct {
+	Query string `json:"query" jsonschema:"description=The text to search\\, e.g. a city name"`
+	Limit int    `json:"limit,omitempty" jsonschema:"description=The number of results,minimum=1,maximum=5"`
+}
+
+func TestReflectGolden(t *testing.T) {
+	params, err := Reflect(&weatherArguments{})
+	if err != nil {
+		t.Fatalf("Reflect() error = %v", err)
+	}
+	got, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	golden := filepath.Join("testdata", "get-weather.golden.json")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Reflect() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReflectOptional(t *testing.T) {
+	params, err := Reflect(&optionalArguments{})
+	if err != nil {
+		t.Fatalf("Reflect() error = %v", err)
+	}
+	if len(params.Required) != 1 || params.Required[0] != "query" {
+		t.Errorf("Required = %v, want [query]", params.Required)
+	}
+	query, _ := params.Properties.Get("query")
+	if want := "The text to search, e.g. a city name"; query == nil || query.Description != want {
+		t.Errorf("query description = %+v, want %q", query, want)
+	}
+}
+
+func TestReflectNil(t *testing.T) {
+	params, err := Reflect(nil)
+	if err != nil {
+		t.Fatalf("Reflect(nil) error = %v", err)
+	}
+	got, _ := json.Marshal(params)
+	if want := `{"type":"object","properties":{}}`; string(got) != want {
+		t.Errorf("Reflect(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestReflectNotStruct(t *testing.T) {
+	if _, err := Reflect("city"); err == nil {
+		t.Error("Reflect() should reject a non struct input schema")
+	}
+}
@@ -0,0 +1,214 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/invopop/jsonschema"
+)
+
+// Validate checks the JSON arguments of a function call against p, e.g.
+// before they are sent to the function. It knows the keywords the reflector
+// emits from the jsonschema tags: the types, the required and the unknown
+// properties, the enums, the bounds of the numbers and the lengths of the
+// strings and the arrays. It returns a problem per invalid value, with its
+// path, and none when args are valid. Empty args are an empty object, as the
+// LLMs send for a function without parameters.
+func (p *Parameters) Validate(args []byte) []string {
+	if len(bytes.TrimSpace(args)) == 0 {
+		args = []byte("{}")
+	}
+	d := json.NewDecoder(bytes.NewReader(args))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return []string{fmt.Sprintf("the arguments are not valid JSON: %v", err)}
+	}
+	if d.More() {
+		return []string{"the arguments are more than one JSON value"}
+	}
+
+	var problems []string
+	validate(&jsonschema.Schema{Type: "object", Properties: p.Properties, Required: p.Required}, "", v, &problems)
+	return problems
+}
+
+// validate appends the problems of the value v at path to problems.
+func validate(s *jsonschema.Schema, path string, v any, problems *[]string) {
+	report := func(format string, a ...any) {
+		msg := fmt.Sprintf(format, a...)
+		if path != "" {
+			msg = path + ": " + msg
+		}
+		*problems = append(*problems, msg)
+	}
+
+	if s.Type != "" && !hasType(v, s.Type) {
+		report("must be %s %s, not %s", article(s.Type), s.Type, kind(v))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		values := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			values[i] = fmt.Sprint(e)
+		}
+		report("must be one of %s, not %v", strings.Join(values, ", "), v)
+	}
+
+	switch v := v.(type) {
+	case json.Number:
+		n, _ := v.Float64()
+		if limit, ok := bound(s.Minimum); ok && n < limit {
+			report("%v is less than the minimum %v", v, s.Minimum)
+		}
+		if limit, ok := bound(s.Maximum); ok && n > limit {
+			report("%v is more than the maximum %v", v, s.Maximum)
+		}
+		if limit, ok := bound(s.ExclusiveMinimum); ok && n <= limit {
+			report("%v must be more than %v", v, s.ExclusiveMinimum)
+		}
+		if limit, ok := bound(s.ExclusiveMaximum); ok && n >= limit {
+			report("%v must be less than %v", v, s.ExclusiveMaximum)
+		}
+	case string:
+		length := uint64(utf8.RuneCountInString(v))
+		if s.MinLength != nil && length < *s.MinLength {
+			report("must be at least %d characters long, not %d", *s.MinLength, length)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			report("must be at most %d characters long, not %d", *s.MaxLength, length)
+		}
+	case []any:
+		if s.MinItems != nil && uint64(len(v)) < *s.MinItems {
+			report("must have at least %d items, not %d", *s.MinItems, len(v))
+		}
+		if s.MaxItems != nil && uint64(len(v)) > *s.MaxItems {
+			report("must have at most %d items, not %d", *s.MaxItems, len(v))
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				validate(s.Items, fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case map[string]any:
+		validateObject(s, path, v, problems)
+	}
+}
+
+// validateObject appends the problems of the properties of the object v,
+// in the order of the schema and then of the unknown properties.
+func validateObject(s *jsonschema.Schema, path string, v map[string]any, problems *[]string) {
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			*problems = append(*problems, prefix+name+": is required")
+		}
+	}
+	if s.Properties == nil {
+		// a map or an interface, any property goes
+		return
+	}
+	for p := s.Properties.Oldest(); p != nil; p = p.Next() {
+		if value, ok := v[p.Key]; ok {
+			validate(p.Value, prefix+p.Key, value, problems)
+		}
+	}
+	var unknown []string
+	for name := range v {
+		if _, ok := s.Properties.Get(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		*problems = append(*problems, prefix+name+": is not a parameter")
+	}
+}
+
+// hasType reports whether the decoded JSON value v has the JSON schema
+// type t. An integer is a number without a fraction.
+func hasType(v any, t string) bool {
+	switch v := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		n, err := v.Float64()
+		return t == "integer" && err == nil && n == math.Trunc(n)
+	}
+	return false
+}
+
+// kind names the type of the decoded JSON value v for a problem.
+func kind(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	case json.Number:
+		return v.String()
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func article(t string) string {
+	if strings.IndexByte("aeiou", t[0]) >= 0 {
+		return "an"
+	}
+	return "a"
+}
+
+// inEnum reports whether v is one of enum, the enum values of a tag being
+// compared with the numbers by their text.
+func inEnum(v any, enum []any) bool {
+	for _, e := range enum {
+		switch v := v.(type) {
+		case json.Number:
+			if n, err := strconv.ParseFloat(fmt.Sprint(e), 64); err == nil {
+				if f, _ := v.Float64(); f == n {
+					return true
+				}
+			}
+		default:
+			if v == e {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bound returns the number of a minimum or maximum keyword, if it is set.
+func bound(n json.Number) (float64, bool) {
+	if n == "" {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+// orderArguments has the other keywords of the jsonschema tags.
+type orderArguments struct {
+	Size  string   `json:"size" jsonschema:"enum=small,enum=large"`
+	Count int      `json:"count" jsonschema:"minimum=1"`
+	Note  string   `json:"note,omitempty" jsonschema:"maxLength=5"`
+	Tags  []string `json:"tags,omitempty" jsonschema:"maxItems=2"`
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items,omitempty"`
+	Extra map[string]any `json:"extra,omitempty"`
+}
+
+func TestValidate(t *testing.T) {
+	weather, err := Reflect(&weatherArguments{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := Reflect(&orderArguments{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	none, err := Reflect(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		params *Parameters
+		args   string
+		want   []string
+	}{
+		{"valid", weather, `{"city":"Paris","latitude":48.85,"longitude":2.35}`, nil},
+		{"missing", weather, `{"city":"Paris","latitude":48.85}`, []string{"longitude: is required"}},
+		{"out of range", weather, `{"city":"Paris","latitude":91,"longitude":-180.5}`, []string{
+			"latitude: 91 is more than the maximum 90",
+			"longitude: -180.5 is less than the minimum -180",
+		}},
+		{"wrong type", weather, `{"city":75001,"latitude":"48.85","longitude":null}`, []string{
+			"city: must be a string, not 75001",
+			"latitude: must be a number, not a string",
+			"longitude: must be a number, not null",
+		}},
+		{"unknown", weather, `{"city":"Paris","latitude":1,"longitude":2,"units":"metric"}`, []string{"units: is not a parameter"}},
+		{"not an object", weather, `["Paris"]`, []string{"must be an object, not an array"}},
+		{"not JSON", weather, `{"city":`, []string{"the arguments are not valid JSON: unexpected EOF"}},
+		{"two values", weather, `{} {}`, []string{"the arguments are more than one JSON value"}},
+		{"no parameters", none, ``, nil},
+		{"valid order", order, `{"size":"small","count":2,"note":"héllo","tags":["a"],"items":[{"name":"x"}],"extra":{"any":1}}`, nil},
+		{"invalid order", order, `{"size":"medium","count":1.5,"note":"too long","tags":["a","b","c"],"items":[{"name":"x"},{}]}`, []string{
+			"size: must be one of small, large, not medium",
+			"count: must be an integer, not 1.5",
+			"note: must be at most 5 characters long, not 8",
+			"tags: must have at most 2 items, not 3",
+			"items[1].name: is required",
+		}},
+		{"below the minimum", order, `{"size":"large","count":0}`, []string{"count: 0 is less than the minimum 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.params.Validate([]byte(tt.args))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%s) =\n%q\nwant\n%q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
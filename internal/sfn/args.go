@@ -0,0 +1,46 @@
+// Package sfn holds helpers for writing the Handler of a YoMo serverless
+// LLM function.
+package sfn
+
+import (
+	"log/slog"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/errs"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// InvalidArgumentsResult is written back to the LLM when the arguments of a
+// function call can not be parsed.
+const InvalidArgumentsResult = "the function arguments could not be parsed, please call the function again with valid JSON arguments"
+
+// ReadArgs reads the LLM function calling arguments into v. A malformed
+// payload would otherwise leave v zeroed and silently produce a nonsense
+// request, so on failure ReadArgs writes InvalidArgumentsResult, logs the
+// raw data at debug level and returns false. The Handler should return then:
+//
+//	var p Parameter
+//	if !sfn.ReadArgs(ctx, &p) {
+//		return
+//	}
+func ReadArgs(ctx serverless.Context, v any) bool {
+	if err := ctx.ReadLLMArguments(v); err != nil {
+		slog.Error("[sfn] read arguments", "err", err)
+		slog.Debug("[sfn] read arguments", "data", string(ctx.Data()))
+		ctx.WriteLLMResult(InvalidArgumentsResult)
+		return false
+	}
+	return true
+}
+
+// ReadArgsEnvelope is ReadArgs for the functions answering with WriteResult
+// and WriteError: on failure it writes an errs.InvalidInput error with
+// InvalidArgumentsResult as its message, so that every answer of the
+// function is an envelope.
+func ReadArgsEnvelope(ctx serverless.Context, v any) bool {
+	if err := ctx.ReadLLMArguments(v); err != nil {
+		slog.Debug("[sfn] read arguments", "data", string(ctx.Data()))
+		WriteError(ctx, errs.Wrap(errs.InvalidInput, err, InvalidArgumentsResult))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package sfn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/errs"
+	"github.com/yomorun/yomo/ai"
+	"github.com/yomorun/yomo/serverless/mock"
+)
+
+type parameter struct {
+	City      string  `json:"city"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func TestReadArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantOK     bool
+		wantArgs   parameter
+		wantResult string
+	}{
+		{
+			name:     "valid arguments",
+			data:     `{"tool_call_id":"call_1","arguments":"{\"city\":\"Paris\",\"latitude\":48.8566,\"longitude\":2.3522}"}`,
+			wantOK:   true,
+			wantArgs: parameter{City: "Paris", Latitude: 48.8566, Longitude: 2.3522},
+		},
+		{
+			name:       "truncated json",
+			data:       `{"tool_call_id":"call_1","arguments":"{\"city\":\"Paris\",\"latitude\":48."}`,
+			wantResult: InvalidArgumentsResult,
+		},
+		{
+			name:       "wrong type",
+			data:       `{"tool_call_id":"call_1","arguments":"{\"city\":\"Paris\",\"latitude\":\"north\"}"}`,
+			wantResult: InvalidArgumentsResult,
+		},
+		{
+			name:       "empty arguments",
+			data:       `{"tool_call_id":"call_1","arguments":""}`,
+			wantResult: InvalidArgumentsResult,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := mock.NewMockContext([]byte(tt.data), 0x33)
+
+			var p parameter
+			if ok := ReadArgs(ctx, &p); ok != tt.wantOK {
+				t.Fatalf("ReadArgs() = %v, want %v", ok, tt.wantOK)
+			}
+			if tt.wantOK {
+				if p != tt.wantArgs {
+					t.Errorf("ReadArgs() args = %+v, want %+v", p, tt.wantArgs)
+				}
+				if records := ctx.RecordsWritten(); len(records) != 0 {
+					t.Errorf("ReadArgs() wrote %d records, want none", len(records))
+				}
+				return
+			}
+
+			records := ctx.RecordsWritten()
+			if len(records) != 1 || records[0].Tag != ai.ReducerTag {
+				t.Fatalf("ReadArgs() records = %v, want one result", records)
+			}
+			var fnCall ai.FunctionCall
+			if err := fnCall.FromBytes(records[0].Data); err != nil {
+				t.Fatal(err)
+			}
+			if fnCall.Result != tt.wantResult {
+				t.Errorf("ReadArgs() result = %q, want %q", fnCall.Result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestReadArgsEnvelope(t *testing.T) {
+	valid := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{\"city\":\"Paris\"}"}`), 0x33)
+	var p parameter
+	if !ReadArgsEnvelope(valid, &p) || p.City != "Paris" || len(valid.RecordsWritten()) != 0 {
+		t.Errorf("ReadArgsEnvelope() of valid arguments = %+v, records %v", p, valid.RecordsWritten())
+	}
+
+	ctx := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{\"city\":\"Paris\",\"latitude\":48."}`), 0x33)
+	if ReadArgsEnvelope(ctx, &p) {
+		t.Fatal("ReadArgsEnvelope() of truncated arguments = true, want false")
+	}
+	records := ctx.RecordsWritten()
+	if len(records) != 1 || records[0].Tag != ai.ReducerTag {
+		t.Fatalf("ReadArgsEnvelope() records = %v, want one result", records)
+	}
+	var fnCall ai.FunctionCall
+	if err := fnCall.FromBytes(records[0].Data); err != nil {
+		t.Fatal(err)
+	}
+	var r Result
+	if err := json.Unmarshal([]byte(fnCall.Result), &r); err != nil {
+		t.Fatalf("ReadArgsEnvelope() result %q is not an envelope: %v", fnCall.Result, err)
+	}
+	if r.OK || r.Error == nil || r.Error.Code != errs.InvalidInput || r.Error.Message != InvalidArgumentsResult {
+		t.Errorf("ReadArgsEnvelope() result = %s, want an %s error", fnCall.Result, errs.InvalidInput)
+	}
+}
@@ -0,0 +1,26 @@
+package sfn
+
+import (
+	"context"
+	"time"
+)
+
+// Budget is the total time a Handler may spend on its upstream calls. Each
+// HTTP client has its own timeout, but a Handler chaining requests, e.g.
+// geocoding a city and then fetching its weather, could otherwise add them
+// up far past what the LLM waits for.
+const Budget = 8 * time.Second
+
+// WithBudget returns a context that is done once Budget is spent. The Handler
+// derives it at the top and passes it to every upstream call, so that a slow
+// call leaves no time to the following ones and the function fails fast:
+//
+//	reqCtx, cancel := sfn.WithBudget()
+//	defer cancel()
+//
+// The call is in flight until cancel is called, see Shutdown. After Shutdown
+// began, the returned context is already done with the cause
+// ErrShuttingDown.
+func WithBudget() (context.Context, context.CancelFunc) {
+	return work.begin(Budget)
+}
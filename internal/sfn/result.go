@@ -0,0 +1,127 @@
+package sfn
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/errs"
+	"github.com/yomorun/yomo/serverless"
+)
+
+// Result is the envelope of a function result, either
+// {"ok":true,"data":...} or {"ok":false,"error":{"code":...,"message":...}},
+// so the caller can tell a failure from data without parsing free text.
+// Truncated is set when the data was cut to fit MAX_RESULT_BYTES.
+type Result struct {
+	OK        bool            `json:"ok"`
+	Data      any             `json:"data,omitempty"`
+	Truncated bool            `json:"truncated,omitempty"`
+	Error     *errs.ToolError `json:"error,omitempty"`
+}
+
+// maxResultBytes is MAX_RESULT_BYTES, the size limit of an encoded Result,
+// so that a large payload does not fill the context of the LLM. 0, the
+// default, is no limit.
+var maxResultBytes = parseMaxResultBytes(os.Getenv("MAX_RESULT_BYTES"))
+
+func parseMaxResultBytes(v string) int {
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		slog.Warn("[sfn] invalid MAX_RESULT_BYTES, the results are not limited", "value", v)
+		return 0
+	}
+	return n
+}
+
+// WriteResult sends data back to the LLM in a successful Result.
+func WriteResult(ctx serverless.Context, data any) {
+	write(ctx, Result{OK: true, Data: data})
+}
+
+// WriteError logs err and sends it back to the LLM in a failed Result. An
+// error that is not an errs.ToolError is reported with the errs.Internal
+// code.
+func WriteError(ctx serverless.Context, err error) {
+	te := errs.As(err)
+	slog.Error("[sfn] function failed", "code", te.Code, "err", err)
+	write(ctx, Result{Error: te})
+}
+
+func write(ctx serverless.Context, r Result) {
+	buf, err := json.Marshal(r)
+	if err != nil {
+		slog.Error("[sfn] encode result", "err", err)
+		buf, _ = json.Marshal(Result{Error: errs.New(errs.Internal, "the function result could not be encoded")})
+	}
+	if maxResultBytes > 0 && len(buf) > maxResultBytes && r.OK {
+		size := len(buf)
+		buf = truncate(r, maxResultBytes)
+		slog.Warn("[sfn] result truncated", "size", size, "max", maxResultBytes)
+	}
+	ctx.WriteLLMResult(string(buf))
+}
+
+// TruncatedText ends a text result of WriteText that was cut to fit
+// MAX_RESULT_BYTES, so the LLM knows that it is incomplete.
+const TruncatedText = "\n[truncated]"
+
+// WriteText sends the plain-text result of a function back to the LLM, cut
+// to MAX_RESULT_BYTES like the data of WriteResult. The functions that do not
+// answer with an envelope write their results with it, so that the limit
+// applies to all of them.
+func WriteText(ctx serverless.Context, text string) {
+	if maxResultBytes > 0 && len(text) > maxResultBytes {
+		size := len(text)
+		text = cut(text, max(maxResultBytes-len(TruncatedText), 0)) + TruncatedText
+		slog.Warn("[sfn] result truncated", "size", size, "max", maxResultBytes)
+	}
+	ctx.WriteLLMResult(text)
+}
+
+// truncate encodes r with its data cut so that it fits in max bytes, and
+// flagged. Data that is not a string is cut as its JSON text, which the
+// flag tells is incomplete.
+func truncate(r Result, max int) []byte {
+	data, ok := r.Data.(string)
+	if !ok {
+		b, _ := json.Marshal(r.Data)
+		data = string(b)
+	}
+	r.Truncated = true
+
+	// escaping makes the encoded data longer than the string, so search
+	// the longest prefix that fits
+	encode := func(n int) []byte {
+		r.Data = cut(data, n)
+		buf, _ := json.Marshal(r)
+		return buf
+	}
+	lo, hi := 0, len(data)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if len(encode(mid)) <= max {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return encode(lo)
+}
+
+// cut returns the longest prefix of s of at most n bytes that does not
+// split a UTF-8 sequence.
+func cut(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,183 @@
+package sfn
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/errs"
+	"github.com/yomorun/yomo/ai"
+	"github.com/yomorun/yomo/serverless/mock"
+)
+
+func TestWriteResult(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx *mock.MockContext)
+		want  string
+	}{
+		{
+			name:  "data",
+			write: func(ctx *mock.MockContext) { WriteResult(ctx, "Paris, FR: clear sky") },
+			want:  `{"ok":true,"data":"Paris, FR: clear sky"}`,
+		},
+		{
+			name:  "tool error",
+			write: func(ctx *mock.MockContext) { WriteError(ctx, errs.New(errs.RateLimited, "too many requests")) },
+			want:  `{"ok":false,"error":{"code":"rate_limited","message":"too many requests"}}`,
+		},
+		{
+			name:  "plain error",
+			write: func(ctx *mock.MockContext) { WriteError(ctx, errors.New("boom")) },
+			want:  `{"ok":false,"error":{"code":"internal","message":"the function failed unexpectedly"}}`,
+		},
+		{
+			name:  "unencodable data",
+			write: func(ctx *mock.MockContext) { WriteResult(ctx, math.Inf(1)) },
+			want:  `{"ok":false,"error":{"code":"internal","message":"the function result could not be encoded"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{}"}`), 0x33)
+			tt.write(ctx)
+
+			if got := resultOf(t, ctx); got != tt.want {
+				t.Errorf("result = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteResultTruncated(t *testing.T) {
+	old := maxResultBytes
+	t.Cleanup(func() { maxResultBytes = old })
+
+	tests := []struct {
+		name string
+		max  int
+		data any
+		want string
+	}{
+		{
+			name: "fits",
+			max:  64,
+			data: "Paris, FR: clear sky",
+			want: `{"ok":true,"data":"Paris, FR: clear sky"}`,
+		},
+		{
+			name: "string",
+			max:  50,
+			data: strings.Repeat("abcdefghij", 10),
+			want: `{"ok":true,"data":"abcdefghijab","truncated":true}`,
+		},
+		{
+			name: "escaped string",
+			max:  50,
+			data: strings.Repeat("\"\n", 20),
+			want: `{"ok":true,"data":"\"\n\"\n\"\n","truncated":true}`,
+		},
+		{
+			name: "multibyte runes",
+			max:  45,
+			data: strings.Repeat("日本", 10),
+			want: `{"ok":true,"data":"日本","truncated":true}`,
+		},
+		{
+			name: "structured data",
+			max:  63,
+			data: map[string][]int{"temperatures": {21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}},
+			want: `{"ok":true,"data":"{\"temperatures\":[21,22,","truncated":true}`,
+		},
+		{
+			name: "limit below the envelope",
+			max:  10,
+			data: "Paris, FR: clear sky",
+			want: `{"ok":true,"data":"","truncated":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxResultBytes = tt.max
+			ctx := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{}"}`), 0x33)
+			WriteResult(ctx, tt.data)
+
+			got := resultOf(t, ctx)
+			if got != tt.want {
+				t.Errorf("result = %s, want %s", got, tt.want)
+			}
+			if len(got) > tt.max && tt.max >= len(`{"ok":true,"data":"","truncated":true}`) {
+				t.Errorf("result has %d bytes, more than %d", len(got), tt.max)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("result %q is not valid UTF-8", got)
+			}
+		})
+	}
+
+	// errors are never cut
+	maxResultBytes = 10
+	ctx := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{}"}`), 0x33)
+	WriteError(ctx, errs.New(errs.RateLimited, "too many requests"))
+	if got, want := resultOf(t, ctx), `{"ok":false,"error":{"code":"rate_limited","message":"too many requests"}}`; got != want {
+		t.Errorf("result = %s, want %s", got, want)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	old := maxResultBytes
+	t.Cleanup(func() { maxResultBytes = old })
+
+	tests := []struct {
+		name string
+		max  int
+		text string
+		want string
+	}{
+		{name: "no limit", max: 0, text: strings.Repeat("abcdefghij", 10), want: strings.Repeat("abcdefghij", 10)},
+		{name: "fits", max: 20, text: "Paris, FR: clear sky", want: "Paris, FR: clear sky"},
+		{name: "cut", max: 30, text: strings.Repeat("abcdefghij", 10), want: "abcdefghijabcdefgh" + TruncatedText},
+		{name: "multibyte runes", max: 16, text: strings.Repeat("日本", 10), want: "日" + TruncatedText},
+		{name: "limit below the marker", max: 5, text: "Paris, FR: clear sky", want: TruncatedText},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxResultBytes = tt.max
+			ctx := mock.NewMockContext([]byte(`{"tool_call_id":"call_1","arguments":"{}"}`), 0x33)
+			WriteText(ctx, tt.text)
+
+			got := resultOf(t, ctx)
+			if got != tt.want {
+				t.Errorf("result = %q, want %q", got, tt.want)
+			}
+			if tt.max >= len(TruncatedText) && len(got) > tt.max {
+				t.Errorf("result has %d bytes, more than %d", len(got), tt.max)
+			}
+		})
+	}
+}
+
+func TestParseMaxResultBytes(t *testing.T) {
+	for v, want := range map[string]int{"": 0, "4096": 4096, "0": 0, "-1": 0, "4k": 0} {
+		if got := parseMaxResultBytes(v); got != want {
+			t.Errorf("parseMaxResultBytes(%q) = %d, want %d", v, got, want)
+		}
+	}
+}
+
+// resultOf returns the single LLM result written to ctx.
+func resultOf(t *testing.T, ctx *mock.MockContext) string {
+	t.Helper()
+	records := ctx.RecordsWritten()
+	if len(records) != 1 || records[0].Tag != ai.ReducerTag {
+		t.Fatalf("records = %v, want one result", records)
+	}
+	var fnCall ai.FunctionCall
+	if err := fnCall.FromBytes(records[0].Data); err != nil {
+		t.Fatal(err)
+	}
+	return fnCall.Result
+}
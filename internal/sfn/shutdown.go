@@ -0,0 +1,87 @@
+package sfn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrShuttingDown is the cause of the contexts of WithBudget once Shutdown
+// began, see context.Cause.
+var ErrShuttingDown = errors.New("the function is shutting down")
+
+// work tracks the calls in flight of the process.
+var work = newDrain()
+
+// drain counts the contexts given out by begin until their cancel is
+// called, and refuses new ones once closing.
+type drain struct {
+	mu       sync.Mutex
+	closing  bool
+	inflight sync.WaitGroup
+	// base is the parent of every context, aborted when the grace period
+	// of Shutdown is over.
+	base  context.Context
+	abort context.CancelCauseFunc
+}
+
+func newDrain() *drain {
+	base, abort := context.WithCancelCause(context.Background())
+	return &drain{base: base, abort: abort}
+}
+
+func (d *drain) begin(timeout time.Duration) (context.Context, context.CancelFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closing {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(ErrShuttingDown)
+		return ctx, func() {}
+	}
+
+	// Add is only called before closing, so it never races with the Wait
+	// of shutdown
+	d.inflight.Add(1)
+	ctx, cancel := context.WithTimeout(d.base, timeout)
+	var once sync.Once
+	return ctx, func() {
+		cancel()
+		once.Do(d.inflight.Done)
+	}
+}
+
+func (d *drain) shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	d.closing = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.abort(ErrShuttingDown)
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops the function from starting new work and waits for the
+// calls in flight, the contexts of WithBudget not canceled yet, so that their
+// HTTP requests and cache writes complete. Once ctx is done, the calls still
+// in flight are canceled with the cause ErrShuttingDown and Shutdown returns
+// the error of ctx. YoMo does not call it, run it from a signal handler with
+// a grace period:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	sfn.Shutdown(ctx)
+//
+// Shutdown is final, calling it again only waits for the calls in flight.
+func Shutdown(ctx context.Context) error {
+	return work.shutdown(ctx)
+}
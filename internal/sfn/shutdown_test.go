@@ -0,0 +1,115 @@
+package sfn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// withDrain gives the test a drain of its own, since Shutdown is final.
+func withDrain(t *testing.T) {
+	old := work
+	t.Cleanup(func() { work = old })
+	work = newDrain()
+}
+
+// closing reports whether Shutdown began.
+func closing() bool {
+	work.mu.Lock()
+	defer work.mu.Unlock()
+	return work.closing
+}
+
+func TestShutdown(t *testing.T) {
+	withDrain(t)
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	fetch := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// a fetch in flight when the shutdown begins
+	inflight := make(chan error)
+	go func() {
+		ctx, cancel := WithBudget()
+		defer cancel()
+		inflight <- fetch(ctx)
+	}()
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	stopped := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- Shutdown(ctx)
+	}()
+
+	for !closing() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// a new fetch is refused without a request
+	ctx, cancel := WithBudget()
+	defer cancel()
+	if err := context.Cause(ctx); !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("WithBudget() after Shutdown cause = %v, want ErrShuttingDown", err)
+	}
+	if err := fetch(ctx); err == nil {
+		t.Error("fetch after Shutdown should fail")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("the server got %d requests, want only the one in flight", n)
+	}
+
+	select {
+	case err := <-stopped:
+		t.Fatalf("Shutdown() = %v before the fetch in flight completed", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-inflight; err != nil {
+		t.Errorf("the fetch in flight failed: %v", err)
+	}
+	if err := <-stopped; err != nil {
+		t.Errorf("Shutdown() = %v", err)
+	}
+}
+
+func TestShutdownGracePeriod(t *testing.T) {
+	withDrain(t)
+
+	ctx, cancel := WithBudget()
+	defer cancel()
+
+	grace, cancelGrace := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelGrace()
+	if err := Shutdown(grace); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want context.DeadlineExceeded", err)
+	}
+	if err := context.Cause(ctx); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("the call in flight cause = %v, want ErrShuttingDown", err)
+	}
+}
@@ -0,0 +1,82 @@
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// Turn is an equinox or a solstice, named by the apparent longitude of the
+// sun in degrees at which it happens.
+type Turn int
+
+// The equinoxes and the solstices in the order of the year.
+const (
+	MarchEquinox     Turn = 0
+	JuneSolstice     Turn = 90
+	SeptemberEquinox Turn = 180
+	DecemberSolstice Turn = 270
+)
+
+// Turns are the equinoxes and the solstices in the order of the year.
+var Turns = []Turn{MarchEquinox, JuneSolstice, SeptemberEquinox, DecemberSolstice}
+
+func (t Turn) String() string {
+	switch t {
+	case MarchEquinox:
+		return "March equinox"
+	case JuneSolstice:
+		return "June solstice"
+	case SeptemberEquinox:
+		return "September equinox"
+	case DecemberSolstice:
+		return "December solstice"
+	}
+	return "unknown turn"
+}
+
+// meanMotion is how far the sun moves along the ecliptic in a day, in
+// degrees.
+const meanMotion = 360 / 365.2422
+
+// Next returns the first time after t of the turn, to the second. It is
+// found where the apparent longitude of the sun reaches the longitude of
+// the turn, within ten minutes of the published times.
+func (turn Turn) Next(t time.Time) time.Time {
+	ahead := math.Mod(float64(turn)-position(t).apparentLon+360, 360)
+	if ahead == 0 {
+		ahead = 360
+	}
+	guess := t.Add(days(ahead / meanMotion))
+	// the sun moves 0.95° to 1.02° a day, a few steps of the mean motion
+	// converge
+	for i := 0; i < 10; i++ {
+		diff := math.Mod(float64(turn)-position(guess).apparentLon+540, 360) - 180
+		guess = guess.Add(days(diff / meanMotion))
+		if math.Abs(diff) < 1e-6 {
+			break
+		}
+	}
+	return guess.Truncate(time.Second)
+}
+
+// At is a turn at a time.
+type At struct {
+	Turn Turn
+	Time time.Time
+}
+
+// Around returns the last turn at or before t and the first turn after it.
+func Around(t time.Time) (last, next At) {
+	for i, turn := range Turns {
+		if at := turn.Next(t); i == 0 || at.Before(next.Time) {
+			next = At{turn, at}
+		}
+	}
+	// the seasons last 89 to 94 days, the last turn is within 100 days
+	before := Turns[(int(next.Turn)/90+3)%4]
+	return At{before, before.Next(t.AddDate(0, 0, -100))}, next
+}
+
+func days(d float64) time.Duration {
+	return time.Duration(d * 24 * float64(time.Hour))
+}
@@ -0,0 +1,62 @@
+package solar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTurnNext(t *testing.T) {
+	// the published times of the US Naval Observatory, the low accuracy
+	// equations of the NOAA are a few minutes early
+	tests := []struct {
+		turn Turn
+		want time.Time
+	}{
+		{MarchEquinox, time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC)},
+		{JuneSolstice, time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC)},
+		{SeptemberEquinox, time.Date(2024, 9, 22, 12, 44, 0, 0, time.UTC)},
+		{DecemberSolstice, time.Date(2024, 12, 21, 9, 20, 0, 0, time.UTC)},
+		{MarchEquinox, time.Date(2026, 3, 20, 14, 46, 0, 0, time.UTC)},
+		{DecemberSolstice, time.Date(2030, 12, 21, 20, 9, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		from := time.Date(tt.want.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		got := tt.turn.Next(from)
+		if d := got.Sub(tt.want).Abs(); d > 10*time.Minute {
+			t.Errorf("%v.Next(%d) = %v, want %v", tt.turn, from.Year(), got, tt.want)
+		}
+	}
+
+	// right at a turn the next one is a year later
+	at := JuneSolstice.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if next := JuneSolstice.Next(at); next.Year() != 2025 {
+		t.Errorf("JuneSolstice.Next(the solstice) = %v, want in 2025", next)
+	}
+}
+
+func TestAround(t *testing.T) {
+	last, next := Around(time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC))
+	if last.Turn != SeptemberEquinox || last.Time.Month() != time.September || last.Time.Year() != 2024 {
+		t.Errorf("Around() last = %v %v, want the September equinox of 2024", last.Turn, last.Time)
+	}
+	if next.Turn != DecemberSolstice || next.Time.Month() != time.December || next.Time.Year() != 2024 {
+		t.Errorf("Around() next = %v %v, want the December solstice of 2024", next.Turn, next.Time)
+	}
+
+	// across the new year
+	last, next = Around(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+	if last.Turn != DecemberSolstice || last.Time.Year() != 2024 || next.Turn != MarchEquinox || next.Time.Year() != 2025 {
+		t.Errorf("Around() = %v %v, %v %v", last.Turn, last.Time, next.Turn, next.Time)
+	}
+}
+
+func TestDeclination(t *testing.T) {
+	solstice := JuneSolstice.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if got := Declination(solstice); got < 23.4 || got > 23.5 {
+		t.Errorf("Declination() at the June solstice = %v, want 23.44", got)
+	}
+	equinox := SeptemberEquinox.Next(solstice)
+	if got := Declination(equinox); got < -0.01 || got > 0.01 {
+		t.Errorf("Declination() at the September equinox = %v, want 0", got)
+	}
+}
@@ -0,0 +1,151 @@
+// Package solar computes the position of the sun in the sky, for the
+// functions about daylight, e.g. the golden hour or the length of the day.
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// SunriseElevation is the elevation of the sun center at sunrise and sunset
+// in degrees: the upper limb touches the horizon, lifted by 0.567° of
+// atmospheric refraction.
+const SunriseElevation = -0.833
+
+// The golden hour is commonly defined as the time the sun is between these
+// elevations above the horizon, in degrees.
+const (
+	GoldenLow  = -4.0
+	GoldenHigh = 6.0
+)
+
+// Elevation returns the geometric elevation of the sun center in degrees
+// at t, without atmospheric refraction, following the NOAA solar
+// calculator, see https://gml.noaa.gov/grad/solcalc/calcdetails.html.
+func Elevation(t time.Time, lat, lon float64) float64 {
+	p := position(t)
+
+	u := t.UTC()
+	minutes := float64(u.Hour()*60+u.Minute()) + float64(u.Second())/60
+	trueSolarTime := math.Mod(minutes+p.eqTime+4*lon, 1440)
+	hourAngle := trueSolarTime/4 - 180
+
+	cosZenith := sin(lat)*sin(p.declination) + cos(lat)*cos(p.declination)*cos(hourAngle)
+	return 90 - degrees(math.Acos(math.Max(-1, math.Min(1, cosZenith))))
+}
+
+// Declination returns the declination of the sun in degrees at t, positive
+// when the sun is north of the celestial equator.
+func Declination(t time.Time) float64 {
+	return position(t).declination
+}
+
+// sunPosition is where the sun is at a time, in degrees, with the equation
+// of time in minutes.
+type sunPosition struct {
+	apparentLon, declination, eqTime float64
+}
+
+// position returns the position of the sun at t with the NOAA equations.
+func position(t time.Time) sunPosition {
+	jd := float64(t.Unix())/86400 + 2440587.5
+	// Julian centuries since J2000.0
+	c := (jd - 2451545) / 36525
+
+	meanLon := math.Mod(280.46646+c*(36000.76983+c*0.0003032), 360)
+	meanAnomaly := 357.52911 + c*(35999.05029-0.0001537*c)
+	eccentricity := 0.016708634 - c*(0.000042037+0.0000001267*c)
+	center := sin(meanAnomaly)*(1.914602-c*(0.004817+0.000014*c)) +
+		sin(2*meanAnomaly)*(0.019993-0.000101*c) +
+		sin(3*meanAnomaly)*0.000289
+	omega := 125.04 - 1934.136*c
+	apparentLon := meanLon + center - 0.00569 - 0.00478*sin(omega)
+
+	meanObliquity := 23 + (26+(21.448-c*(46.815+c*(0.00059-c*0.001813)))/60)/60
+	obliquity := meanObliquity + 0.00256*cos(omega)
+	declination := degrees(math.Asin(sin(obliquity) * sin(apparentLon)))
+
+	y := math.Pow(math.Tan(radians(obliquity/2)), 2)
+	// the equation of time in minutes
+	eqTime := 4 * degrees(y*sin(2*meanLon)-
+		2*eccentricity*sin(meanAnomaly)+
+		4*eccentricity*y*sin(meanAnomaly)*cos(2*meanLon)-
+		0.5*y*y*sin(4*meanLon)-
+		1.25*eccentricity*eccentricity*sin(2*meanAnomaly))
+
+	return sunPosition{apparentLon: math.Mod(apparentLon+360, 360), declination: declination, eqTime: eqTime}
+}
+
+// Window is a time range.
+type Window struct {
+	Start, End time.Time
+}
+
+// step is the interval at which Windows scans the elevation. The sun moves
+// at most 1.25° in it, so a window between limits more than that apart is
+// not missed.
+const step = 5 * time.Minute
+
+// Windows returns the time ranges from from to to in which in reports true
+// for the sun elevation at lat,lon, to the second. A window open at from or
+// to is clipped to them.
+func Windows(lat, lon float64, from, to time.Time, in func(elevation float64) bool) []Window {
+	inside := func(t time.Time) bool { return in(Elevation(t, lat, lon)) }
+
+	var windows []Window
+	var start time.Time
+	open := inside(from)
+	if open {
+		start = from
+	}
+	for t := from; t.Before(to); t = t.Add(step) {
+		next := t.Add(step)
+		if next.After(to) {
+			next = to
+		}
+		if inside(next) == open {
+			continue
+		}
+		edge := bisect(t, next, inside)
+		if open {
+			windows = append(windows, Window{Start: start, End: edge})
+		} else {
+			start = edge
+		}
+		open = !open
+	}
+	if open {
+		windows = append(windows, Window{Start: start, End: to})
+	}
+	return windows
+}
+
+// bisect finds to the second the time between a and b at which inside
+// changes, given that it differs at a and b.
+func bisect(a, b time.Time, inside func(time.Time) bool) time.Time {
+	at := inside(a)
+	for b.Sub(a) > time.Second {
+		mid := a.Add(b.Sub(a) / 2)
+		if inside(mid) == at {
+			a = mid
+		} else {
+			b = mid
+		}
+	}
+	return b.Truncate(time.Second)
+}
+
+func sin(deg float64) float64 { return math.Sin(radians(deg)) }
+func cos(deg float64) float64 { return math.Cos(radians(deg)) }
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+// GoldenHours returns the golden hours at lat,lon from from to to, the
+// Windows in which the sun is between GoldenLow and GoldenHigh. Near the
+// poles they can be absent or last all day.
+func GoldenHours(lat, lon float64, from, to time.Time) []Window {
+	return Windows(lat, lon, from, to, func(el float64) bool {
+		return el >= GoldenLow && el <= GoldenHigh
+	})
+}
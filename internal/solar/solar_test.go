@@ -0,0 +1,124 @@
+package solar
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSunElevation(t *testing.T) {
+	// the equation of time peaks at about +16.4 minutes on November 3 and
+	// -14.2 minutes on February 11, so the sun culminates over the prime
+	// meridian at about 11:43:35 and 12:14:12 UTC
+	tests := []struct {
+		name     string
+		noon     time.Time
+		lat      float64
+		wantNoon float64
+	}{
+		// the declination is -15.2° on November 3
+		{"november", time.Date(2024, 11, 3, 11, 43, 35, 0, time.UTC), 51.48, 90 - 51.48 - 15.2},
+		// and -14.0° on February 11
+		{"february", time.Date(2024, 2, 11, 12, 14, 12, 0, time.UTC), 0, 90 - 14.0},
+	}
+	for _, tt := range tests {
+		got := Elevation(tt.noon, tt.lat, 0)
+		if math.Abs(got-tt.wantNoon) > 0.2 {
+			t.Errorf("%s: Elevation() = %v, want %v", tt.name, got, tt.wantNoon)
+		}
+		// the sun is lower a few minutes before and after
+		for _, d := range []time.Duration{-5 * time.Minute, 5 * time.Minute} {
+			if Elevation(tt.noon.Add(d), tt.lat, 0) >= got {
+				t.Errorf("%s: the sun is higher %v from the noon", tt.name, d)
+			}
+		}
+	}
+
+	// on the June solstice the sun is overhead on the tropic of cancer
+	if got := Elevation(time.Date(2024, 6, 20, 12, 2, 0, 0, time.UTC), 23.44, 0); math.Abs(got-90) > 0.2 {
+		t.Errorf("Elevation() on the tropic of cancer = %v, want 90", got)
+	}
+}
+
+func TestSunrise(t *testing.T) {
+	// at the published sunrise the upper limb of the sun appears on the
+	// horizon, the center is 0.833° below it after refraction
+	paris, _ := time.LoadLocation("Europe/Paris")
+	newYork, _ := time.LoadLocation("America/New_York")
+	tests := []struct {
+		name     string
+		sunrise  time.Time
+		lat, lon float64
+	}{
+		{"paris", time.Date(2024, 6, 21, 5, 47, 0, 0, paris), 48.8566, 2.3522},
+		{"new york", time.Date(2024, 12, 21, 7, 17, 0, 0, newYork), 40.7128, -74.006},
+	}
+	for _, tt := range tests {
+		if got := Elevation(tt.sunrise, tt.lat, tt.lon); math.Abs(got+0.833) > 0.15 {
+			t.Errorf("%s: Elevation() at sunrise = %v, want -0.833", tt.name, got)
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	// on the equator at the equinox the day lasts 12 hours and about 7
+	// minutes, the refraction lifts the sun earlier and holds it later
+	midnight := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	up := func(el float64) bool { return el >= SunriseElevation }
+	got := Windows(0, 0, midnight, midnight.AddDate(0, 0, 1), up)
+	if len(got) != 1 {
+		t.Fatalf("Windows() = %v, want one day", got)
+	}
+	if d := got[0].End.Sub(got[0].Start); d < 12*time.Hour+5*time.Minute || d > 12*time.Hour+9*time.Minute {
+		t.Errorf("the day lasts %v, want about 12h07m", d)
+	}
+
+	// a window open at the limits is clipped to them
+	noon := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	if got := Windows(0, 0, noon, noon.Add(time.Hour), up); len(got) != 1 || !got[0].Start.Equal(noon) || !got[0].End.Equal(noon.Add(time.Hour)) {
+		t.Errorf("Windows() around noon = %v, want the hour", got)
+	}
+	if got := Windows(0, 0, midnight, midnight.Add(time.Hour), up); len(got) != 0 {
+		t.Errorf("Windows() at night = %v, want none", got)
+	}
+}
+
+func TestGoldenHours(t *testing.T) {
+	// On the equator at the equinox the sun rises and sets vertically, at
+	// 15° per hour: the golden hours last 40 minutes, from 6h16m to 5h36m
+	// before the solar noon, at about 12:07:20 UTC on the prime meridian.
+	midnight := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	noon := time.Date(2024, 3, 20, 12, 7, 20, 0, time.UTC)
+	want := []Window{
+		{Start: noon.Add(-(6*time.Hour + 16*time.Minute)), End: noon.Add(-(5*time.Hour + 36*time.Minute))},
+		{Start: noon.Add(5*time.Hour + 36*time.Minute), End: noon.Add(6*time.Hour + 16*time.Minute)},
+	}
+	got := GoldenHours(0, 0, midnight, midnight.AddDate(0, 0, 1))
+	if len(got) != len(want) {
+		t.Fatalf("GoldenHours() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if absDuration(got[i].Start.Sub(want[i].Start)) > time.Minute || absDuration(got[i].End.Sub(want[i].End)) > time.Minute {
+			t.Errorf("GoldenHours()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// the limits of a window are where the sun crosses -4° and 6°
+	paris, _ := time.LoadLocation("Europe/Paris")
+	day := time.Date(2024, 6, 21, 0, 0, 0, 0, paris)
+	for _, w := range GoldenHours(48.8566, 2.3522, day, day.AddDate(0, 0, 1)) {
+		for _, edge := range []time.Time{w.Start, w.End} {
+			el := Elevation(edge, 48.8566, 2.3522)
+			if math.Abs(el-GoldenLow) > 0.05 && math.Abs(el-GoldenHigh) > 0.05 {
+				t.Errorf("the sun is at %v° at the edge %v", el, edge)
+			}
+		}
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
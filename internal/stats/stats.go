@@ -0,0 +1,74 @@
+// Package stats has the descriptive statistics of the functions about
+// datasets, e.g. the histogram and the outlier detection.
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Sorted returns a sorted copy of numbers.
+func Sorted(numbers []float64) []float64 {
+	sorted := append([]float64(nil), numbers...)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// Mean returns the arithmetic mean of numbers, which must not be empty.
+func Mean(numbers []float64) float64 {
+	sum := 0.0
+	for _, x := range numbers {
+		sum += x
+	}
+	if math.IsInf(sum, 0) {
+		// finite numbers near the largest float64 overflow their sum,
+		// not the sum of their fractions
+		n := float64(len(numbers))
+		sum = 0
+		for _, x := range numbers {
+			sum += x / n
+		}
+		return sum
+	}
+	return sum / float64(len(numbers))
+}
+
+// StdDev returns the sample standard deviation of numbers, with n-1 degrees
+// of freedom, 0 for fewer than 2 numbers.
+func StdDev(numbers []float64) float64 {
+	if len(numbers) < 2 {
+		return 0
+	}
+	mean := Mean(numbers)
+	sum := 0.0
+	for _, x := range numbers {
+		sum += (x - mean) * (x - mean)
+	}
+	return math.Sqrt(sum / float64(len(numbers)-1))
+}
+
+// Percentile returns the p-th percentile of sorted, p from 0 to 100, by
+// linear interpolation between the closest ranks: the rank of p is
+// p/100*(n-1), counted from 0.
+func Percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	f := rank - float64(lo)
+	if d := sorted[hi] - sorted[lo]; !math.IsInf(d, 0) {
+		return sorted[lo] + d*f
+	}
+	// the distance of the ranks overflows, e.g. from -1e308 to 1e308
+	return sorted[lo]*(1-f) + sorted[hi]*f
+}
+
+// Format writes x with up to 4 decimals, without the trailing zeros.
+func Format(x float64) string {
+	if math.Abs(x) < 1e15 {
+		// larger numbers have no decimals, and could overflow the
+		// rounding
+		x = math.Round(x*1e4) / 1e4
+	}
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		p, want float64
+	}{
+		{0, 1},
+		{25, 3.25},
+		{50, 5.5},
+		{90, 9.1},
+		{99, 9.91},
+		{100, 10},
+	}
+	for _, tt := range tests {
+		if got := Percentile(sorted, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile([]float64{-1e308, 1e308}, 75); got != 5e307 {
+		t.Errorf("Percentile() of a huge range = %v, want 5e307", got)
+	}
+	if got := Percentile([]float64{42}, 75); got != 42 {
+		t.Errorf("Percentile() of a single value = %v, want 42", got)
+	}
+}
+
+func TestMeanStdDev(t *testing.T) {
+	numbers := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	if got := Mean(numbers); got != 5 {
+		t.Errorf("Mean() = %v, want 5", got)
+	}
+	// the population deviation is 2, the sample one sqrt(32/7)
+	if got := StdDev(numbers); math.Abs(got-math.Sqrt(32.0/7)) > 1e-9 {
+		t.Errorf("StdDev() = %v, want %v", got, math.Sqrt(32.0/7))
+	}
+	if got := Mean([]float64{1e308, 1e308}); got != 1e308 {
+		t.Errorf("Mean() of huge numbers = %v, want 1e308", got)
+	}
+	if got := StdDev([]float64{3}); got != 0 {
+		t.Errorf("StdDev() of a single value = %v, want 0", got)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	numbers := []float64{3, 1, 2}
+	if got := Sorted(numbers); got[0] != 1 || got[2] != 3 || numbers[0] != 3 {
+		t.Errorf("Sorted() = %v, input %v, want a sorted copy", got, numbers)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[float64]string{0: "0", 12: "12", 2.5: "2.5", 1.0 / 3: "0.3333", -21.80004: "-21.8", 1e6: "1000000", -1e20: "-100000000000000000000"}
+	for x, want := range tests {
+		if got := Format(x); got != want {
+			t.Errorf("Format(%v) = %q, want %q", x, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// AirQuality is the current air pollution of a location.
+type AirQuality struct {
+	// Index is the OpenWeatherMap air quality index, from 1 for good to 5
+	// for very poor.
+	Index int
+	// The concentrations of the main pollutants in μg/m³.
+	PM25, PM10, O3, NO2 float64
+}
+
+var airQualityLevels = []string{"good", "fair", "moderate", "poor", "very poor"}
+
+// Level names the index, e.g. "moderate".
+func (a AirQuality) Level() string {
+	if a.Index < 1 || a.Index > len(airQualityLevels) {
+		return "unknown"
+	}
+	return airQualityLevels[a.Index-1]
+}
+
+// AirQuality fetches the current air pollution at the given coordinates,
+// see https://openweathermap.org/api/air-pollution.
+func (c *Client) AirQuality(ctx context.Context, lat, lon float64) (*AirQuality, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+
+	body, err := c.get(ctx, "/data/2.5/air_pollution", q)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAirQuality(body)
+}
+
+// ParseAirQuality parses a /data/2.5/air_pollution response body.
+func ParseAirQuality(body []byte) (*AirQuality, error) {
+	var r struct {
+		List []struct {
+			Main struct {
+				AQI int `json:"aqi"`
+			} `json:"main"`
+			Components struct {
+				PM25 float64 `json:"pm2_5"`
+				PM10 float64 `json:"pm10"`
+				O3   float64 `json:"o3"`
+				NO2  float64 `json:"no2"`
+			} `json:"components"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode air pollution response: %w", err)
+	}
+	if len(r.List) == 0 {
+		return nil, errors.New("air pollution response has no data")
+	}
+	p := r.List[0]
+	return &AirQuality{
+		Index: p.Main.AQI,
+		PM25:  p.Components.PM25,
+		PM10:  p.Components.PM10,
+		O3:    p.Components.O3,
+		NO2:   p.Components.NO2,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package weather
+
+import "math"
+
+// The NWS formulas are defined in °F and mph: the wind chill applies from
+// 50°F down with a wind of at least 3 mph, the heat index from 80°F up.
+const (
+	windChillMax = 50.0
+	windChillMin = 3.0
+	heatIndexMin = 80.0
+)
+
+// Apparent returns the temperature in °F the air feels like at the
+// temperature t in °F, the relative humidity rh in percent and a wind of
+// mph: the wind chill when it is cold and windy, the heat index when it is
+// hot, and t otherwise. index is "wind chill", "heat index" or empty.
+func Apparent(t, rh, mph float64) (feelsLike float64, index string) {
+	switch {
+	case t <= windChillMax && mph >= windChillMin:
+		return WindChill(t, mph), "wind chill"
+	case t >= heatIndexMin:
+		return HeatIndex(t, rh), "heat index"
+	}
+	return t, ""
+}
+
+// WindChill returns the NWS wind chill temperature in °F of the temperature
+// t in °F with a wind of mph, see
+// https://www.weather.gov/media/epz/wxcalc/windChill.pdf.
+func WindChill(t, mph float64) float64 {
+	v := math.Pow(mph, 0.16)
+	return 35.74 + 0.6215*t - 35.75*v + 0.4275*t*v
+}
+
+// HeatIndex returns the NWS heat index in °F of the temperature t in °F at
+// the relative humidity rh in percent, see
+// https://www.wpc.ncep.noaa.gov/html/heatindex_equation.shtml.
+func HeatIndex(t, rh float64) float64 {
+	// the simple formula is close enough below 80°F, where the regression
+	// of Rothfusz does not hold
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 < 80 {
+		return hi
+	}
+
+	hi = -42.379 + 2.04901523*t + 10.14333127*rh -
+		0.22475541*t*rh - 0.00683783*t*t - 0.05481717*rh*rh +
+		0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+	switch {
+	case rh < 13 && t >= 80 && t <= 112:
+		hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	case rh > 85 && t >= 80 && t <= 87:
+		hi += (rh - 85) / 10 * (87 - t) / 5
+	}
+	return hi
+}
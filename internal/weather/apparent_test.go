@@ -0,0 +1,64 @@
+package weather
+
+import (
+	"math"
+	"testing"
+)
+
+// The references are the rounded values of the NWS wind chill chart,
+// https://www.weather.gov/safety/cold-wind-chill-chart, and heat index
+// chart, https://www.weather.gov/safety/heat-index.
+func TestWindChill(t *testing.T) {
+	tests := []struct {
+		t, mph, want float64
+	}{
+		{40, 5, 36},
+		{30, 10, 21},
+		{20, 20, 4},
+		{0, 15, -19},
+		{-10, 30, -39},
+		{-40, 60, -91},
+	}
+	for _, tt := range tests {
+		if got := WindChill(tt.t, tt.mph); math.Round(got) != tt.want {
+			t.Errorf("WindChill(%v, %v) = %.2f, want %v", tt.t, tt.mph, got, tt.want)
+		}
+	}
+}
+
+func TestHeatIndex(t *testing.T) {
+	tests := []struct {
+		t, rh, want float64
+	}{
+		{80, 40, 80},
+		{90, 60, 100},
+		{96, 65, 121},
+		{100, 40, 109},
+		{86, 90, 105},
+		{104, 55, 137},
+	}
+	for _, tt := range tests {
+		if got := HeatIndex(tt.t, tt.rh); math.Round(got) != tt.want {
+			t.Errorf("HeatIndex(%v, %v) = %.2f, want %v", tt.t, tt.rh, got, tt.want)
+		}
+	}
+}
+
+func TestApparent(t *testing.T) {
+	tests := []struct {
+		t, rh, mph float64
+		want       float64
+		index      string
+	}{
+		{30, 50, 10, 21, "wind chill"},
+		{30, 50, 2, 30, ""},
+		{65, 50, 20, 65, ""},
+		{90, 60, 5, 100, "heat index"},
+	}
+	for _, tt := range tests {
+		got, index := Apparent(tt.t, tt.rh, tt.mph)
+		if math.Round(got) != tt.want || index != tt.index {
+			t.Errorf("Apparent(%v, %v, %v) = %.2f %q, want %v %q", tt.t, tt.rh, tt.mph, got, index, tt.want, tt.index)
+		}
+	}
+}
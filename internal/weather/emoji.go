@@ -0,0 +1,48 @@
+package weather
+
+// Emoji maps an OpenWeatherMap condition code to an emoji, see
+// https://openweathermap.org/weather-conditions. Unknown codes are a
+// thermometer.
+func Emoji(code int) string {
+	switch {
+	case code >= 200 && code < 300:
+		return "⛈️"
+	case code >= 300 && code < 400:
+		return "🌦️"
+	case code == 511:
+		// freezing rain
+		return "🌨️"
+	case code >= 520 && code < 600:
+		// shower rain
+		return "🌦️"
+	case code >= 500 && code < 600:
+		return "🌧️"
+	case code >= 600 && code < 700:
+		return "❄️"
+	case code == 711:
+		return "💨"
+	case code == 731 || code == 751 || code == 761:
+		// sand and dust whirls
+		return "🏜️"
+	case code == 762:
+		return "🌋"
+	case code == 771:
+		return "🌬️"
+	case code == 781:
+		return "🌪️"
+	case code >= 700 && code < 800:
+		// mist, haze and fog
+		return "🌫️"
+	case code == 800:
+		return "☀️"
+	case code == 801:
+		return "🌤️"
+	case code == 802:
+		return "⛅"
+	case code == 803:
+		return "🌥️"
+	case code == 804:
+		return "☁️"
+	}
+	return "🌡️"
+}
@@ -0,0 +1,35 @@
+package weather
+
+import "testing"
+
+func TestEmoji(t *testing.T) {
+	tests := []struct {
+		codes []int
+		want  string
+	}{
+		{[]int{200, 211, 232}, "⛈️"},
+		{[]int{300, 311, 321, 520, 522, 531}, "🌦️"},
+		{[]int{500, 502, 504}, "🌧️"},
+		{[]int{511}, "🌨️"},
+		{[]int{600, 611, 622}, "❄️"},
+		{[]int{701, 721, 741}, "🌫️"},
+		{[]int{711}, "💨"},
+		{[]int{731, 751, 761}, "🏜️"},
+		{[]int{762}, "🌋"},
+		{[]int{771}, "🌬️"},
+		{[]int{781}, "🌪️"},
+		{[]int{800}, "☀️"},
+		{[]int{801}, "🌤️"},
+		{[]int{802}, "⛅"},
+		{[]int{803}, "🌥️"},
+		{[]int{804}, "☁️"},
+		{[]int{0, 100, 805, 900}, "🌡️"},
+	}
+	for _, tt := range tests {
+		for _, code := range tt.codes {
+			if got := Emoji(code); got != tt.want {
+				t.Errorf("Emoji(%d) = %s, want %s", code, got, tt.want)
+			}
+		}
+	}
+}
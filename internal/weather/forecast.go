@@ -0,0 +1,115 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Slot is one 3-hour step of the 5 day forecast.
+type Slot struct {
+	Time time.Time
+	Conditions
+	// PrecipitationChance is the probability of precipitation, 0 to 1.
+	PrecipitationChance float64
+	Rain3h              float64
+	Snow3h              float64
+}
+
+// Summary describes the slot like Conditions.Summary, plus the chance and
+// amount of precipitation.
+func (s Slot) Summary() string {
+	summary := s.Conditions.Summary()
+	if s.PrecipitationChance > 0 {
+		summary += fmt.Sprintf(", %.0f%% chance of precipitation", s.PrecipitationChance*100)
+	}
+	if s.Rain3h > 0 {
+		summary += fmt.Sprintf(", rain %.1f mm", s.Rain3h)
+	}
+	if s.Snow3h > 0 {
+		summary += fmt.Sprintf(", snow %.1f mm", s.Snow3h)
+	}
+	return summary
+}
+
+// Forecast is the 5 day / 3 hour forecast of a location.
+type Forecast struct {
+	City    string
+	Country string
+	// TimezoneOffset is the shift in seconds from UTC of the location.
+	TimezoneOffset int
+	Slots          []Slot
+}
+
+// Location returns a fixed time zone for the forecast's UTC offset.
+func (f *Forecast) Location() *time.Location {
+	return time.FixedZone("", f.TimezoneOffset)
+}
+
+// Forecast fetches the 5 day / 3 hour forecast at the given coordinates in
+// metric units.
+func (c *Client) Forecast(ctx context.Context, lat, lon float64) (*Forecast, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+	q.Set("units", "metric")
+
+	body, err := c.get(ctx, "/data/2.5/forecast", q)
+	if err != nil {
+		return nil, err
+	}
+	return ParseForecast(body)
+}
+
+type forecastResponse struct {
+	List []struct {
+		Dt int64 `json:"dt"`
+		currentResponse
+		Pop  float64 `json:"pop"`
+		Rain struct {
+			ThreeHours float64 `json:"3h"`
+		} `json:"rain"`
+		Snow struct {
+			ThreeHours float64 `json:"3h"`
+		} `json:"snow"`
+	} `json:"list"`
+	City struct {
+		Name  string `json:"name"`
+		Coord struct {
+			Lat float64 `json:"lat"`
+			Lon float64 `json:"lon"`
+		} `json:"coord"`
+		Country  string `json:"country"`
+		Timezone int    `json:"timezone"`
+	} `json:"city"`
+}
+
+// ParseForecast parses a /data/2.5/forecast response body.
+func ParseForecast(body []byte) (*Forecast, error) {
+	var r forecastResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode forecast response: %w", err)
+	}
+
+	f := &Forecast{
+		City:           r.City.Name,
+		Country:        r.City.Country,
+		TimezoneOffset: r.City.Timezone,
+		Slots:          make([]Slot, 0, len(r.List)),
+	}
+	for _, item := range r.List {
+		c := item.currentResponse.conditions()
+		c.City, c.Country = r.City.Name, r.City.Country
+		c.Latitude, c.Longitude = r.City.Coord.Lat, r.City.Coord.Lon
+		f.Slots = append(f.Slots, Slot{
+			Time:                time.Unix(item.Dt, 0).UTC(),
+			Conditions:          *c,
+			PrecipitationChance: item.Pop,
+			Rain3h:              item.Rain.ThreeHours,
+			Snow3h:              item.Snow.ThreeHours,
+		})
+	}
+	return f, nil
+}
@@ -0,0 +1,162 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/geo"
+)
+
+// geocodeLimit is how many geocoding matches Locate weighs, the most the
+// API returns.
+const geocodeLimit = 5
+
+// samePlace is the distance in km below which two matches of a city name are
+// taken for the same place, e.g. a city and one of its districts.
+const samePlace = 50
+
+// AmbiguousCityError is returned by CurrentByCity when the city name matches
+// several places and nothing in it tells which one is meant, e.g. "Paris"
+// for Paris, FR and Paris, Texas, US.
+type AmbiguousCityError struct {
+	City       string
+	Candidates []Location
+}
+
+func (e *AmbiguousCityError) Error() string {
+	options := make([]string, len(e.Candidates))
+	for i, l := range e.Candidates {
+		options[i] = fmt.Sprintf("%s (%.4f,%.4f)", l, l.Latitude, l.Longitude)
+	}
+	return fmt.Sprintf("%q matches %d places: %s", e.City, len(e.Candidates), strings.Join(options, "; "))
+}
+
+// cityQuery is a city name split into the name and the qualifiers after it,
+// e.g. "Paris, TX" into "Paris" and ["TX"].
+type cityQuery struct {
+	name       string
+	qualifiers []string
+}
+
+func parseCityQuery(city string) cityQuery {
+	parts := strings.Split(city, ",")
+	q := cityQuery{name: strings.TrimSpace(parts[0])}
+	for _, p := range parts[1:] {
+		if p = strings.TrimSpace(p); p != "" {
+			q.qualifiers = append(q.qualifiers, p)
+		}
+	}
+	return q
+}
+
+// geocodeQuery returns the q parameter of the geocoding API, which only
+// understands "name,state code,country code" with a state code of the US,
+// or "name,country code". Other qualifiers, e.g. a region or a country name,
+// are left out and filter the matches instead.
+func (q cityQuery) geocodeQuery() string {
+	for _, qualifier := range q.qualifiers {
+		if code, ok := usStateCode(qualifier); ok {
+			return q.name + "," + code + ",US"
+		}
+	}
+	if n := len(q.qualifiers); n > 0 && len(q.qualifiers[n-1]) == 2 {
+		return q.name + "," + strings.ToUpper(q.qualifiers[n-1])
+	}
+	return q.name
+}
+
+// matches reports whether every qualifier names the country, the state or
+// the state code of l.
+func (q cityQuery) matches(l Location) bool {
+	for _, qualifier := range q.qualifiers {
+		code, _ := usStateCode(l.State)
+		if !strings.EqualFold(qualifier, l.Country) && !strings.EqualFold(qualifier, l.State) && !strings.EqualFold(qualifier, code) {
+			return false
+		}
+	}
+	return true
+}
+
+// candidates returns the distinct places of the geocoding matches that city
+// may mean, best match first: the matches of the qualifiers named exactly
+// like the city. Without such a match, the best one is the only candidate,
+// e.g. "Munich" for "München".
+func (q cityQuery) candidates(locations []Location) []Location {
+	var qualified []Location
+	for _, l := range locations {
+		if q.matches(l) {
+			qualified = append(qualified, l)
+		}
+	}
+	// a qualifier the API did not report, e.g. a country name, is ignored
+	if len(qualified) == 0 {
+		qualified = locations
+	}
+
+	var strong []Location
+	for _, l := range qualified {
+		if strings.EqualFold(l.Name, q.name) && !near(strong, l) {
+			strong = append(strong, l)
+		}
+	}
+	if len(strong) == 0 {
+		return qualified[:1]
+	}
+	return strong
+}
+
+func near(locations []Location, l Location) bool {
+	for _, other := range locations {
+		if geo.Distance(other.Latitude, other.Longitude, l.Latitude, l.Longitude) < samePlace {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve geocodes city to a single place, or fails with an
+// *AmbiguousCityError listing the places it may mean.
+func (c *Client) resolve(ctx context.Context, city string) (Location, error) {
+	q := parseCityQuery(city)
+	locations, err := c.Geocode(ctx, q.geocodeQuery(), geocodeLimit)
+	if err != nil {
+		return Location{}, err
+	}
+	candidates := q.candidates(locations)
+	if len(candidates) > 1 {
+		return Location{}, &AmbiguousCityError{City: city, Candidates: candidates}
+	}
+	return candidates[0], nil
+}
+
+// usStates are the codes of the US states and DC by name.
+var usStates = map[string]string{
+	"alabama": "AL", "alaska": "AK", "arizona": "AZ", "arkansas": "AR",
+	"california": "CA", "colorado": "CO", "connecticut": "CT", "delaware": "DE",
+	"district of columbia": "DC", "florida": "FL", "georgia": "GA", "hawaii": "HI",
+	"idaho": "ID", "illinois": "IL", "indiana": "IN", "iowa": "IA",
+	"kansas": "KS", "kentucky": "KY", "louisiana": "LA", "maine": "ME",
+	"maryland": "MD", "massachusetts": "MA", "michigan": "MI", "minnesota": "MN",
+	"mississippi": "MS", "missouri": "MO", "montana": "MT", "nebraska": "NE",
+	"nevada": "NV", "new hampshire": "NH", "new jersey": "NJ", "new mexico": "NM",
+	"new york": "NY", "north carolina": "NC", "north dakota": "ND", "ohio": "OH",
+	"oklahoma": "OK", "oregon": "OR", "pennsylvania": "PA", "rhode island": "RI",
+	"south carolina": "SC", "south dakota": "SD", "tennessee": "TN", "texas": "TX",
+	"utah": "UT", "vermont": "VT", "virginia": "VA", "washington": "WA",
+	"west virginia": "WV", "wisconsin": "WI", "wyoming": "WY",
+}
+
+// usStateCode returns the code of a US state given by name or code.
+func usStateCode(s string) (string, bool) {
+	if code, ok := usStates[strings.ToLower(s)]; ok {
+		return code, true
+	}
+	upper := strings.ToUpper(s)
+	for _, code := range usStates {
+		if code == upper {
+			return code, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+package weather
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/registry"
+)
+
+// APIKeysEnv is the environment variable of several comma-separated
+// OpenWeatherMap API keys, to spread the requests of a high-volume
+// deployment over their per-key limits. It takes precedence over APIKeyEnv.
+const APIKeysEnv = "OPENWEATHERMAP_API_KEYS"
+
+// KeyEnv is the registry Env entry of the functions calling OpenWeatherMap,
+// set with either a single key or keys to rotate.
+var KeyEnv = registry.AnyOf(APIKeyEnv, APIKeysEnv)
+
+// KeyCooldown is how long a key answered with a 401 is skipped.
+const KeyCooldown = 10 * time.Minute
+
+// ParseKeys splits the comma-separated keys of v, dropping the empty ones.
+func ParseKeys(v string) []string {
+	var keys []string
+	for _, k := range strings.Split(v, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Keys rotates round-robin over several API keys, one per request, and
+// skips for a cooldown the keys OpenWeatherMap rejected. It is safe for
+// concurrent use.
+type Keys struct {
+	keys     []string
+	cooldown time.Duration
+	// now is replaced in tests
+	now  func() time.Time
+	next atomic.Uint64
+
+	mu sync.Mutex
+	// suspended are the rejected keys, until when they are skipped
+	suspended map[string]time.Time
+}
+
+// NewKeys returns a Keys over keys, which must not be empty, skipping a
+// rejected key for cooldown.
+func NewKeys(keys []string, cooldown time.Duration) *Keys {
+	return &Keys{keys: keys, cooldown: cooldown, now: time.Now, suspended: make(map[string]time.Time)}
+}
+
+// Next returns the key of the next request: the following key in the
+// rotation that is not suspended, or the following key if all of them are,
+// since a request with a rejected key beats no request.
+func (k *Keys) Next() string {
+	n := uint64(len(k.keys))
+	start := k.next.Add(1) - 1
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now()
+	for i := uint64(0); i < n; i++ {
+		key := k.keys[(start+i)%n]
+		if until, ok := k.suspended[key]; !ok || !now.Before(until) {
+			return key
+		}
+	}
+	return k.keys[start%n]
+}
+
+// Suspend skips key for the cooldown, e.g. after a 401.
+func (k *Keys) Suspend(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.suspended[key] = k.now().Add(k.cooldown)
+}
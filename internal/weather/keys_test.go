@@ -0,0 +1,99 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseKeys(t *testing.T) {
+	got := ParseKeys(" key-a, key-b,,key-c ,")
+	if strings.Join(got, "|") != "key-a|key-b|key-c" {
+		t.Errorf("ParseKeys() = %q, want key-a, key-b, key-c", got)
+	}
+	if got := ParseKeys(""); got != nil {
+		t.Errorf("ParseKeys(\"\") = %q, want none", got)
+	}
+}
+
+func TestKeysRotation(t *testing.T) {
+	now := time.Date(2024, 8, 7, 12, 0, 0, 0, time.UTC)
+	k := NewKeys([]string{"a", "b", "c"}, 10*time.Minute)
+	k.now = func() time.Time { return now }
+
+	var got []string
+	for i := 0; i < 5; i++ {
+		got = append(got, k.Next())
+	}
+	if strings.Join(got, "") != "abcab" {
+		t.Errorf("Next() rotation = %q, want abcab", got)
+	}
+
+	// a suspended key is skipped until the cooldown is over
+	k.Suspend("a")
+	got = got[:0]
+	for i := 0; i < 4; i++ {
+		got = append(got, k.Next())
+	}
+	if strings.Join(got, "") != "cbbc" {
+		t.Errorf("Next() with a suspended = %q, want cbbc", got)
+	}
+	now = now.Add(10 * time.Minute)
+	if key := k.Next(); key != "a" {
+		t.Errorf("Next() after the cooldown = %q, want a", key)
+	}
+
+	// with every key suspended, they are still rotated
+	k.Suspend("a")
+	k.Suspend("b")
+	k.Suspend("c")
+	if key := k.Next(); key != "b" {
+		t.Errorf("Next() with all suspended = %q, want b", key)
+	}
+}
+
+func TestNewClientKeys(t *testing.T) {
+	t.Setenv(APIKeyEnv, "single")
+	t.Setenv(APIKeysEnv, "")
+	if c := NewClient(""); c.APIKey != "single" || c.Keys != nil {
+		t.Errorf("NewClient() = key %q, keys %v, want the single key", c.APIKey, c.Keys)
+	}
+
+	t.Setenv(APIKeysEnv, "first,second")
+	c := NewClient("")
+	if c.APIKey != "first" || c.Keys == nil {
+		t.Fatalf("NewClient() = key %q, keys %v, want the keys rotated", c.APIKey, c.Keys)
+	}
+	if c := NewClient("explicit"); c.APIKey != "explicit" || c.Keys != nil {
+		t.Errorf("NewClient(explicit) = key %q, keys %v, want the explicit key", c.APIKey, c.Keys)
+	}
+}
+
+func TestClientKeys(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		used []string
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("appid")
+		mu.Lock()
+		used = append(used, key)
+		mu.Unlock()
+		if key == "revoked" {
+			http.Error(w, `{"cod":401,"message":"Invalid API key"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	c.Keys = NewKeys([]string{"good", "revoked", "other"}, time.Hour)
+
+	for i := 0; i < 6; i++ {
+		c.Geocode(context.Background(), "Paris", 1)
+	}
+	if got := strings.Join(used, ","); got != "good,revoked,other,good,other,other" {
+		t.Errorf("keys used = %s, want the revoked key skipped after its 401", got)
+	}
+}
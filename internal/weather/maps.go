@@ -0,0 +1,27 @@
+package weather
+
+import "fmt"
+
+// DefaultTileURL is the OpenWeatherMap weather maps 1.0 endpoint.
+const DefaultTileURL = "https://tile.openweathermap.org"
+
+// MapLayers are the weather map overlays, keyed by a short name.
+var MapLayers = map[string]string{
+	"precipitation": "precipitation_new",
+	"clouds":        "clouds_new",
+	"temperature":   "temp_new",
+	"wind":          "wind_new",
+	"pressure":      "pressure_new",
+}
+
+// TileURL returns the URL of the PNG tile z/x/y of a weather map layer, one
+// of the keys of MapLayers. The URL has no API key: it is shown to the user,
+// and the application displaying the tile appends its own key as the appid
+// query parameter.
+func TileURL(layer string, z, x, y int) (string, error) {
+	name, ok := MapLayers[layer]
+	if !ok {
+		return "", fmt.Errorf("unknown map layer %q", layer)
+	}
+	return fmt.Sprintf("%s/map/%s/%d/%d/%d.png", DefaultTileURL, name, z, x, y), nil
+}
@@ -0,0 +1,314 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"time"
+)
+
+// History fetches the weather observed at the given coordinates and time
+// from the One Call 3.0 time machine endpoint. OpenWeatherMap keeps data
+// back to 1979-01-01.
+func (c *Client) History(ctx context.Context, lat, lon float64, t time.Time) (*Conditions, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+	q.Set("dt", fmt.Sprint(t.Unix()))
+	q.Set("units", "metric")
+
+	body, err := c.get(ctx, "/data/3.0/onecall/timemachine", q)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTimeMachine(body)
+}
+
+// oneCallData is a data point as returned by the One Call 3.0 API, shared by
+// the current, hourly and time machine sections of its responses.
+type oneCallData struct {
+	Dt        int64   `json:"dt"`
+	Temp      float64 `json:"temp"`
+	FeelsLike float64 `json:"feels_like"`
+	Pressure  int     `json:"pressure"`
+	Humidity  int     `json:"humidity"`
+	Clouds    int     `json:"clouds"`
+	WindSpeed float64 `json:"wind_speed"`
+	WindDeg   int     `json:"wind_deg"`
+	Weather   []struct {
+		ID          int    `json:"id"`
+		Main        string `json:"main"`
+		Description string `json:"description"`
+	} `json:"weather"`
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Snow struct {
+		OneHour float64 `json:"1h"`
+	} `json:"snow"`
+}
+
+func (d oneCallData) conditions(lat, lon float64) *Conditions {
+	c := &Conditions{
+		Latitude:    lat,
+		Longitude:   lon,
+		Temperature: d.Temp,
+		FeelsLike:   d.FeelsLike,
+		Humidity:    d.Humidity,
+		Pressure:    d.Pressure,
+		WindSpeed:   d.WindSpeed,
+		WindDeg:     d.WindDeg,
+		Clouds:      d.Clouds,
+		Rain1h:      d.Rain.OneHour,
+		Snow1h:      d.Snow.OneHour,
+	}
+	if len(d.Weather) > 0 {
+		c.ConditionID = d.Weather[0].ID
+		c.Condition = d.Weather[0].Main
+		c.Description = d.Weather[0].Description
+	}
+	return c
+}
+
+// ParseTimeMachine parses a /data/3.0/onecall/timemachine response body.
+func ParseTimeMachine(body []byte) (*Conditions, error) {
+	var r struct {
+		Lat  float64       `json:"lat"`
+		Lon  float64       `json:"lon"`
+		Data []oneCallData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode time machine response: %w", err)
+	}
+	if len(r.Data) == 0 {
+		return nil, errors.New("time machine response has no data")
+	}
+	return r.Data[0].conditions(r.Lat, r.Lon), nil
+}
+
+// Day is a day of the One Call 3.0 daily forecast.
+type Day struct {
+	// Date is noon of the day in the time zone of the location.
+	Date        time.Time
+	TempMin     float64
+	TempMax     float64
+	WindSpeed   float64
+	ConditionID int
+	Description string
+	// UVIndex is the maximum UV index of the day.
+	UVIndex float64
+	// PrecipitationChance is the probability of precipitation, 0 to 1.
+	PrecipitationChance float64
+	// Rain and Snow are the amounts in mm over the day.
+	Rain float64
+	Snow float64
+}
+
+// Daily fetches the 8 day daily forecast at the given coordinates from the
+// One Call 3.0 API in metric units, today first.
+func (c *Client) Daily(ctx context.Context, lat, lon float64) ([]Day, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+	q.Set("exclude", "current,minutely,hourly,alerts")
+	q.Set("units", "metric")
+
+	body, err := c.get(ctx, "/data/3.0/onecall", q)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDaily(body)
+}
+
+// ParseDaily parses the daily section of a /data/3.0/onecall response body.
+func ParseDaily(body []byte) ([]Day, error) {
+	var r struct {
+		TimezoneOffset int `json:"timezone_offset"`
+		Daily          []struct {
+			Dt   int64 `json:"dt"`
+			Temp struct {
+				Min float64 `json:"min"`
+				Max float64 `json:"max"`
+			} `json:"temp"`
+			WindSpeed float64 `json:"wind_speed"`
+			Weather   []struct {
+				ID          int    `json:"id"`
+				Description string `json:"description"`
+			} `json:"weather"`
+			UVI  float64 `json:"uvi"`
+			Pop  float64 `json:"pop"`
+			Rain float64 `json:"rain"`
+			Snow float64 `json:"snow"`
+		} `json:"daily"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode one call response: %w", err)
+	}
+	if len(r.Daily) == 0 {
+		return nil, errors.New("one call response has no daily forecast")
+	}
+
+	loc := time.FixedZone("", r.TimezoneOffset)
+	days := make([]Day, 0, len(r.Daily))
+	for _, d := range r.Daily {
+		day := Day{
+			Date:                time.Unix(d.Dt, 0).In(loc),
+			TempMin:             d.Temp.Min,
+			TempMax:             d.Temp.Max,
+			WindSpeed:           d.WindSpeed,
+			UVIndex:             d.UVI,
+			PrecipitationChance: d.Pop,
+			Rain:                d.Rain,
+			Snow:                d.Snow,
+		}
+		if len(d.Weather) > 0 {
+			day.ConditionID = d.Weather[0].ID
+			day.Description = d.Weather[0].Description
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// UVIndex fetches the current UV index at the given coordinates from the One
+// Call 3.0 API.
+func (c *Client) UVIndex(ctx context.Context, lat, lon float64) (float64, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+	q.Set("exclude", "minutely,hourly,daily,alerts")
+
+	body, err := c.get(ctx, "/data/3.0/onecall", q)
+	if err != nil {
+		return 0, err
+	}
+	return ParseUVIndex(body)
+}
+
+// ParseUVIndex parses the current UV index of a /data/3.0/onecall response
+// body.
+func ParseUVIndex(body []byte) (float64, error) {
+	var r struct {
+		Current *struct {
+			UVI float64 `json:"uvi"`
+		} `json:"current"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return 0, fmt.Errorf("decode one call response: %w", err)
+	}
+	if r.Current == nil {
+		return 0, errors.New("one call response has no current weather")
+	}
+	return r.Current.UVI, nil
+}
+
+// UVRisk returns the exposure category of a UV index and its sun protection
+// advice, after the Global Solar UV Index practical guide of the WHO. The
+// categories are defined on the whole index, so uvi is rounded first:
+// 2.4 is low and 2.5 moderate.
+func UVRisk(uvi float64) (risk, advice string) {
+	switch i := math.Round(uvi); {
+	case i < 3:
+		return "low", "no protection is needed, sunglasses on bright days"
+	case i < 6:
+		return "moderate", "seek shade around midday, wear a hat and sunglasses and apply SPF 30+ sunscreen"
+	case i < 8:
+		return "high", "reduce the time in the sun between 11 AM and 3 PM, wear a hat, sunglasses and SPF 30+ sunscreen"
+	case i < 11:
+		return "very high", "avoid the sun between 11 AM and 3 PM, seek shade and wear protective clothing, a hat, sunglasses and SPF 50+ sunscreen"
+	}
+	return "extreme", "stay out of the sun between 11 AM and 3 PM, unprotected skin can burn in minutes"
+}
+
+// Alert is a weather warning of a national weather service, as relayed by
+// the One Call 3.0 API.
+type Alert struct {
+	Sender      string
+	Event       string
+	Start, End  time.Time
+	Description string
+}
+
+// Alerts fetches the active weather alerts at the given coordinates from the
+// One Call 3.0 API, none when the area is not under a warning.
+func (c *Client) Alerts(ctx context.Context, lat, lon float64) ([]Alert, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+	q.Set("exclude", "current,minutely,hourly,daily")
+
+	body, err := c.get(ctx, "/data/3.0/onecall", q)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAlerts(body)
+}
+
+// ParseAlerts parses the alerts of a /data/3.0/onecall response body, which
+// has no alerts section when there are none. The times are in the local
+// time of the location.
+func ParseAlerts(body []byte) ([]Alert, error) {
+	var r struct {
+		TimezoneOffset int `json:"timezone_offset"`
+		Alerts         []struct {
+			SenderName  string `json:"sender_name"`
+			Event       string `json:"event"`
+			Start       int64  `json:"start"`
+			End         int64  `json:"end"`
+			Description string `json:"description"`
+		} `json:"alerts"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode one call response: %w", err)
+	}
+
+	loc := time.FixedZone("", r.TimezoneOffset)
+	alerts := make([]Alert, 0, len(r.Alerts))
+	for _, a := range r.Alerts {
+		alerts = append(alerts, Alert{
+			Sender:      a.SenderName,
+			Event:       a.Event,
+			Start:       time.Unix(a.Start, 0).In(loc),
+			End:         time.Unix(a.End, 0).In(loc),
+			Description: a.Description,
+		})
+	}
+	return alerts, nil
+}
+
+// Precipitation fetches the total precipitation in mm that fell at the
+// given coordinates on a past day, rain and snow, from the One Call 3.0 day
+// summary endpoint. The date is the day in the time zone of the location.
+func (c *Client) Precipitation(ctx context.Context, lat, lon float64, date time.Time) (float64, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+	q.Set("date", date.Format("2006-01-02"))
+	q.Set("units", "metric")
+
+	body, err := c.get(ctx, "/data/3.0/onecall/day_summary", q)
+	if err != nil {
+		return 0, err
+	}
+	return ParseDaySummaryPrecipitation(body)
+}
+
+// ParseDaySummaryPrecipitation parses the total precipitation of a
+// /data/3.0/onecall/day_summary response body.
+func ParseDaySummaryPrecipitation(body []byte) (float64, error) {
+	var r struct {
+		Precipitation *struct {
+			Total float64 `json:"total"`
+		} `json:"precipitation"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return 0, fmt.Errorf("decode day summary response: %w", err)
+	}
+	if r.Precipitation == nil {
+		return 0, errors.New("day summary response has no precipitation")
+	}
+	return r.Precipitation.Total, nil
+}
@@ -0,0 +1,451 @@
+// Package weather is a small OpenWeatherMap client shared by the weather
+// related functions. It resolves city names to coordinates and turns the
+// current, historical and forecast responses into short summaries for the
+// LLM.
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/circuit"
+	"github.com/yomorun/llm-function-calling-examples/internal/httpx"
+	"github.com/yomorun/llm-function-calling-examples/internal/ratelimit"
+)
+
+// DefaultBaseURL is the OpenWeatherMap API endpoint.
+const DefaultBaseURL = "https://api.openweathermap.org"
+
+// APIKeyEnv is the environment variable of the OpenWeatherMap API key.
+const APIKeyEnv = "OPENWEATHERMAP_API_KEY"
+
+// RateLimitEnv is the environment variable of the number of calls per minute
+// the API key is allowed, e.g. 60 on the free plan. Unset, the calls are not
+// limited.
+const RateLimitEnv = "OPENWEATHERMAP_CALLS_PER_MINUTE"
+
+// Client requests the OpenWeatherMap API.
+type Client struct {
+	APIKey string
+	// Keys, if set, rotates several API keys over the requests instead of
+	// using APIKey.
+	Keys       *Keys
+	BaseURL    string
+	HTTPClient *http.Client
+	// CurrentCache, if set, caches the results of Current by coordinates.
+	// The cached Conditions are shared and must not be modified.
+	CurrentCache *cache.Cache[*Conditions]
+	// CityCache, if set, caches the best geocoding match of CurrentByCity
+	// by city name.
+	CityCache *cache.Cache[Location]
+	// Breaker, if set, fails the requests at once with circuit.ErrOpen
+	// while OpenWeatherMap is down.
+	Breaker *circuit.Breaker
+	// Limiter, if set, fails the requests at once with
+	// ratelimit.ErrLimited when its quota is used up.
+	Limiter *ratelimit.Limiter
+}
+
+const (
+	// breakerThreshold is how many requests in a row must fail for the
+	// breaker of NewClient to open.
+	breakerThreshold = 5
+	// breakerCooldown is how long it stays open before a probe.
+	breakerCooldown = 30 * time.Second
+)
+
+// NewClient returns a Client using the given API key. If apiKey is empty,
+// the keys of APIKeysEnv are rotated, or the one of APIKeyEnv is used.
+func NewClient(apiKey string) *Client {
+	var keys *Keys
+	if apiKey == "" {
+		if all := ParseKeys(os.Getenv(APIKeysEnv)); len(all) > 0 {
+			keys = NewKeys(all, KeyCooldown)
+			apiKey = all[0]
+		} else {
+			apiKey = os.Getenv(APIKeyEnv)
+		}
+	}
+	return &Client{
+		APIKey:     apiKey,
+		Keys:       keys,
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: httpx.NewClient(10 * time.Second),
+		Breaker:    NewBreaker(breakerThreshold, breakerCooldown),
+		Limiter:    sharedLimiter(os.Getenv(RateLimitEnv)),
+	}
+}
+
+// sharedLimiter returns the limiter of the calls per minute v, shared by the
+// clients of the process since they use the same API key, or nil if v is
+// not a positive number.
+func sharedLimiter(v string) *ratelimit.Limiter {
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		slog.Warn("[weather] invalid "+RateLimitEnv+", the calls are not limited", "value", v)
+		return nil
+	}
+	return ratelimit.Shared("openweathermap", n, time.Minute)
+}
+
+// NewBreaker returns a circuit breaker opening after threshold outages of
+// OpenWeatherMap in a row: the requests that fail to connect or time out,
+// and the server errors. Its other answers, e.g. a 401 for an invalid API
+// key, show that it is up.
+func NewBreaker(threshold int, cooldown time.Duration) *circuit.Breaker {
+	b := circuit.New(threshold, cooldown)
+	b.IsFailure = isOutage
+	return b
+}
+
+func isOutage(err error) bool {
+	// the caller gave up, it says nothing about the API
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// Location is a geocoding match for a city name.
+type Location struct {
+	Name      string  `json:"name"`
+	State     string  `json:"state"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// String returns the location as "Name, State, Country".
+func (l Location) String() string {
+	parts := []string{l.Name}
+	if l.State != "" {
+		parts = append(parts, l.State)
+	}
+	if l.Country != "" {
+		parts = append(parts, l.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ErrCityNotFound is returned by Geocode when no location matches the name.
+var ErrCityNotFound = errors.New("city not found")
+
+// Geocode resolves a city name to at most limit locations, best match first.
+func (c *Client) Geocode(ctx context.Context, city string, limit int) ([]Location, error) {
+	q := url.Values{}
+	q.Set("q", city)
+	q.Set("limit", fmt.Sprint(limit))
+
+	body, err := c.get(ctx, "/geo/1.0/direct", q)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []Location
+	if err := json.Unmarshal(body, &locations); err != nil {
+		return nil, fmt.Errorf("decode geocoding response: %w", err)
+	}
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCityNotFound, city)
+	}
+	return locations, nil
+}
+
+// Conditions is the parsed current weather of a location.
+type Conditions struct {
+	City        string
+	Country     string
+	Latitude    float64
+	Longitude   float64
+	ConditionID int
+	Condition   string
+	Description string
+	Temperature float64
+	FeelsLike   float64
+	Humidity    int
+	Pressure    int
+	WindSpeed   float64
+	WindDeg     int
+	Clouds      int
+	Rain1h      float64
+	Snow1h      float64
+	// Observed is when the weather was measured, in the local time of the
+	// location. It is zero when the response has no observation time.
+	Observed time.Time
+}
+
+// Current fetches the current weather at the given coordinates in metric
+// units, from CurrentCache if it is set. Concurrent callers missing the
+// cache share the request made with the ctx of the first one.
+func (c *Client) Current(ctx context.Context, lat, lon float64) (*Conditions, error) {
+	if c.CurrentCache == nil {
+		return c.fetchCurrent(ctx, lat, lon)
+	}
+	return c.CurrentCache.GetOrLoad(coordinateKey(lat, lon), func() (*Conditions, error) {
+		return c.fetchCurrent(ctx, lat, lon)
+	})
+}
+
+// CurrentByCity geocodes city and fetches its current weather, see Current.
+// With CityCache and CurrentCache set, a city asked again is answered
+// without any request.
+//
+// The city may be qualified with its state and country, e.g. "Paris, TX" or
+// "Paris, Texas, US". When it still matches several places it fails with an
+// *AmbiguousCityError rather than guessing.
+//
+// CityCache only holds the coordinates of the city, the conditions always
+// come from CurrentCache: a city and its coordinates share one entry, and
+// never disagree on the weather.
+func (c *Client) CurrentByCity(ctx context.Context, city string) (*Conditions, Location, error) {
+	location, err := c.Locate(ctx, city)
+	if err != nil {
+		return nil, Location{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, Location{}, err
+	}
+	conditions, err := c.Current(ctx, location.Latitude, location.Longitude)
+	if err != nil {
+		return nil, Location{}, err
+	}
+	return conditions, location, nil
+}
+
+// Locate returns the place city names, disambiguated like in CurrentByCity,
+// from CityCache if it is set. An ambiguous city is not cached, the same
+// name may be resolved once more qualifiers are known.
+func (c *Client) Locate(ctx context.Context, city string) (Location, error) {
+	load := func() (Location, error) {
+		return c.resolve(ctx, city)
+	}
+	if c.CityCache == nil {
+		return load()
+	}
+	return c.CityCache.GetOrLoad(cityKey(city), load)
+}
+
+// cityKey is the cache key of a city name, trimmed and lowercased so that
+// " Paris" and "paris" share an entry.
+func cityKey(city string) string {
+	return strings.ToLower(strings.Join(strings.Fields(city), " "))
+}
+
+// coordinateKey is the cache key of a coordinate, rounded to about 10 m so
+// that the same place asked with a different precision shares an entry.
+func coordinateKey(lat, lon float64) string {
+	return fmt.Sprintf("%.4f,%.4f", lat, lon)
+}
+
+// KeepCurrentWarm fetches again the current weather of the top most
+// requested coordinates of CurrentCache before it expires, checking every
+// interval, until ctx is done. It returns at once without a CurrentCache.
+// Run it in a goroutine.
+func (c *Client) KeepCurrentWarm(ctx context.Context, interval time.Duration, top int) {
+	if c.CurrentCache == nil {
+		return
+	}
+	c.CurrentCache.KeepWarm(ctx, interval, top, func(key string) (*Conditions, error) {
+		var lat, lon float64
+		if _, err := fmt.Sscanf(key, "%f,%f", &lat, &lon); err != nil {
+			return nil, err
+		}
+		return c.fetchCurrent(ctx, lat, lon)
+	})
+}
+
+func (c *Client) fetchCurrent(ctx context.Context, lat, lon float64) (*Conditions, error) {
+	q := url.Values{}
+	q.Set("lat", fmt.Sprintf("%f", lat))
+	q.Set("lon", fmt.Sprintf("%f", lon))
+	q.Set("units", "metric")
+
+	body, err := c.get(ctx, "/data/2.5/weather", q)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCurrent(body)
+}
+
+type currentResponse struct {
+	Coord struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+	Weather []struct {
+		ID          int    `json:"id"`
+		Main        string `json:"main"`
+		Description string `json:"description"`
+	} `json:"weather"`
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Pressure  int     `json:"pressure"`
+		Humidity  int     `json:"humidity"`
+	} `json:"main"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+		Deg   int     `json:"deg"`
+	} `json:"wind"`
+	Clouds struct {
+		All int `json:"all"`
+	} `json:"clouds"`
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Snow struct {
+		OneHour float64 `json:"1h"`
+	} `json:"snow"`
+	Sys struct {
+		Country string `json:"country"`
+	} `json:"sys"`
+	Name string `json:"name"`
+	// Dt is the observation time in Unix seconds, and Timezone the offset
+	// of the location from UTC in seconds.
+	Dt       int64 `json:"dt"`
+	Timezone int   `json:"timezone"`
+}
+
+// ParseCurrent parses a /data/2.5/weather response body.
+func ParseCurrent(body []byte) (*Conditions, error) {
+	var r currentResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("decode weather response: %w", err)
+	}
+
+	return r.conditions(), nil
+}
+
+func (r *currentResponse) conditions() *Conditions {
+	c := &Conditions{
+		City:        r.Name,
+		Country:     r.Sys.Country,
+		Latitude:    r.Coord.Lat,
+		Longitude:   r.Coord.Lon,
+		Temperature: r.Main.Temp,
+		FeelsLike:   r.Main.FeelsLike,
+		Humidity:    r.Main.Humidity,
+		Pressure:    r.Main.Pressure,
+		WindSpeed:   r.Wind.Speed,
+		WindDeg:     r.Wind.Deg,
+		Clouds:      r.Clouds.All,
+		Rain1h:      r.Rain.OneHour,
+		Snow1h:      r.Snow.OneHour,
+	}
+	if len(r.Weather) > 0 {
+		c.ConditionID = r.Weather[0].ID
+		c.Condition = r.Weather[0].Main
+		c.Description = r.Weather[0].Description
+	}
+	if r.Dt > 0 {
+		c.Observed = time.Unix(r.Dt, 0).In(time.FixedZone("", r.Timezone))
+	}
+	return c
+}
+
+// Summary describes the conditions in one line, e.g.
+// "Paris, FR: broken clouds, 19.8°C (feels like 19.6°C), humidity 66%, wind 5.1 m/s, clouds 75%".
+func (c *Conditions) Summary() string {
+	var b strings.Builder
+	if c.City != "" {
+		b.WriteString(c.City)
+		if c.Country != "" {
+			b.WriteString(", " + c.Country)
+		}
+		b.WriteString(": ")
+	}
+	if c.Description != "" {
+		b.WriteString(c.Description + ", ")
+	}
+	fmt.Fprintf(&b, "%.1f°C (feels like %.1f°C), humidity %d%%, wind %.1f m/s, clouds %d%%",
+		c.Temperature, c.FeelsLike, c.Humidity, c.WindSpeed, c.Clouds)
+	if c.Rain1h > 0 {
+		fmt.Fprintf(&b, ", rain %.1f mm in the last hour", c.Rain1h)
+	}
+	if c.Snow1h > 0 {
+		fmt.Fprintf(&b, ", snow %.1f mm in the last hour", c.Snow1h)
+	}
+	// a cached result can be minutes old, tell how fresh it is
+	if !c.Observed.IsZero() {
+		fmt.Fprintf(&b, ", as of %s local", c.Observed.Format("15:04"))
+	}
+	return b.String()
+}
+
+// get requests path on the API with the given query and the API key, and
+// returns the body of a successful response. The request is abandoned when
+// ctx is done, and not sent while Breaker is open.
+func (c *Client) get(ctx context.Context, path string, q url.Values) ([]byte, error) {
+	if c.Limiter != nil && !c.Limiter.Allow() {
+		return nil, ratelimit.ErrLimited
+	}
+	if c.Breaker == nil {
+		return c.fetch(ctx, path, q)
+	}
+	var body []byte
+	err := c.Breaker.Do(func() error {
+		var err error
+		body, err = c.fetch(ctx, path, q)
+		return err
+	})
+	return body, err
+}
+
+func (c *Client) fetch(ctx context.Context, path string, q url.Values) ([]byte, error) {
+	key := c.APIKey
+	if c.Keys != nil {
+		key = c.Keys.Next()
+	}
+	q.Set("appid", key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		// the error text has the URL, and the key in it
+		return nil, httpx.Redact(err, "appid")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, httpx.Redact(err, "appid")
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.Keys != nil {
+		c.Keys.Suspend(key)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
+
+// StatusError is returned when OpenWeatherMap answers with a non-200 status,
+// e.g. 401 for an invalid API key.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("openweathermap responded %d: %s", e.StatusCode, e.Body)
+}
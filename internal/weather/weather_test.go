@@ -0,0 +1,633 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yomorun/llm-function-calling-examples/internal/cache"
+	"github.com/yomorun/llm-function-calling-examples/internal/circuit"
+	"github.com/yomorun/llm-function-calling-examples/internal/ratelimit"
+)
+
+func TestParseCurrent(t *testing.T) {
+	body, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ParseCurrent(body)
+	if err != nil {
+		t.Fatalf("ParseCurrent() error = %v", err)
+	}
+	if c.City != "Paris" || c.Country != "FR" || c.ConditionID != 803 || c.Humidity != 66 {
+		t.Errorf("ParseCurrent() = %+v", c)
+	}
+
+	// dt 1723022471 is 09:21 UTC, the timezone of Paris is 2 hours ahead
+	if _, offset := c.Observed.Zone(); !c.Observed.Equal(time.Unix(1723022471, 0)) || offset != 7200 {
+		t.Errorf("ParseCurrent() Observed = %v, want 2024-08-07 11:21:11 +0200", c.Observed)
+	}
+	want := "Paris, FR: broken clouds, 19.8°C (feels like 19.6°C), humidity 66%, wind 5.1 m/s, clouds 75%, as of 11:21 local"
+	if got := c.Summary(); got != want {
+		t.Errorf("Summary() = %s, want %s", got, want)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c := NewClient("test-key")
+	c.BaseURL = srv.URL
+	return c
+}
+
+func TestClientErrorHidesKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+
+	for name, baseURL := range map[string]string{"timeout": srv.URL, "refused": refused.URL} {
+		c := NewClient("secret-key")
+		c.BaseURL = baseURL
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := c.Current(ctx, 48.85, 2.35)
+		cancel()
+		if err == nil || strings.Contains(err.Error(), "secret-key") {
+			t.Errorf("%s: Current() error = %v, want it without the key", name, err)
+		}
+	}
+}
+
+func TestClientCurrent(t *testing.T) {
+	body, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/2.5/weather" || r.URL.Query().Get("appid") != "test-key" || r.URL.Query().Get("units") != "metric" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write(body)
+	})
+
+	got, err := c.Current(context.Background(), 48.8566, 2.3522)
+	if err != nil {
+		t.Fatalf("Current() error = %v", err)
+	}
+	if got.City != "Paris" {
+		t.Errorf("Current() city = %s, want Paris", got.City)
+	}
+}
+
+func TestClientBreaker(t *testing.T) {
+	body, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		status   atomic.Int32
+		requests atomic.Int32
+	)
+	status.Store(http.StatusServiceUnavailable)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if code := int(status.Load()); code != http.StatusOK {
+			http.Error(w, `{"cod":"`+http.StatusText(code)+`"}`, code)
+			return
+		}
+		w.Write(body)
+	})
+	c.Breaker = NewBreaker(2, 50*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		var se *StatusError
+		if _, err := c.Current(context.Background(), 48.8566, 2.3522); !errors.As(err, &se) {
+			t.Fatalf("Current() error = %v, want a StatusError", err)
+		}
+	}
+	if _, err := c.Current(context.Background(), 48.8566, 2.3522); !errors.Is(err, circuit.ErrOpen) {
+		t.Fatalf("Current() with the breaker open error = %v, want ErrOpen", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests sent, want 2", n)
+	}
+
+	// the API recovers, the probe after the cooldown closes the breaker
+	status.Store(http.StatusOK)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.Current(context.Background(), 48.8566, 2.3522); err != nil {
+		t.Fatalf("Current() after the cooldown error = %v", err)
+	}
+	if s := c.Breaker.State(); s != circuit.Closed {
+		t.Errorf("breaker state = %s, want closed", s)
+	}
+
+	// client errors are answers, they do not open the breaker
+	status.Store(http.StatusUnauthorized)
+	for i := 0; i < 3; i++ {
+		c.Current(context.Background(), 48.8566, 2.3522)
+	}
+	if s := c.Breaker.State(); s != circuit.Closed {
+		t.Errorf("breaker state after 401s = %s, want closed", s)
+	}
+}
+
+func TestClientLimiter(t *testing.T) {
+	body, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(body)
+	})
+	c.Limiter = ratelimit.New(2, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Current(context.Background(), 48.8566, 2.3522); err != nil {
+			t.Fatalf("Current() error = %v", err)
+		}
+	}
+	if _, err := c.Current(context.Background(), 48.8566, 2.3522); !errors.Is(err, ratelimit.ErrLimited) {
+		t.Fatalf("Current() over the quota error = %v, want ErrLimited", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests sent, want 2", n)
+	}
+}
+
+func TestSharedLimiter(t *testing.T) {
+	for _, v := range []string{"", "0", "-5", "sixty"} {
+		if l := sharedLimiter(v); l != nil {
+			t.Errorf("sharedLimiter(%q) = %+v, want nil", v, l.Status())
+		}
+	}
+	l := sharedLimiter("60")
+	if l == nil || l != sharedLimiter("60") {
+		t.Fatal("sharedLimiter(60) did not return a shared Limiter")
+	}
+	if st := l.Status(); st.Capacity != 60 || st.Period != time.Minute {
+		t.Errorf("sharedLimiter(60) = %d per %s, want 60 per minute", st.Capacity, st.Period)
+	}
+}
+
+func TestClientCurrentCached(t *testing.T) {
+	body, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write(body)
+	})
+	c.CurrentCache = cache.New[*Conditions](10 * time.Minute)
+
+	// concurrent misses of the same coordinates wait for one request
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := c.Current(context.Background(), 48.8566, 2.3522); err != nil || got.City != "Paris" {
+				t.Errorf("Current() = %v, %v", got, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// and a more precise coordinate of the same place is a hit
+	if _, err := c.Current(context.Background(), 48.85661, 2.35222); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("OpenWeatherMap got %d requests, want 1", got)
+	}
+}
+
+func TestClientKeepCurrentWarm(t *testing.T) {
+	body, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		requests atomic.Int32
+		lastLat  atomic.Value
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		lastLat.Store(r.URL.Query().Get("lat"))
+		w.Write(body)
+	})
+	c.CurrentCache = cache.New[*Conditions](60 * time.Millisecond).WithJitter(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go c.KeepCurrentWarm(ctx, 15*time.Millisecond, 1)
+
+	if _, err := c.Current(context.Background(), 48.8566, 2.3522); err != nil {
+		t.Fatal(err)
+	}
+	// the cache hits never request the API, only the refresher does
+	deadline := time.Now().Add(time.Second)
+	for requests.Load() < 2 && time.Now().Before(deadline) {
+		c.CurrentCache.Get(coordinateKey(48.8566, 2.3522))
+		time.Sleep(5 * time.Millisecond)
+	}
+	if requests.Load() < 2 {
+		t.Fatal("the popular coordinates were not refreshed")
+	}
+	if lat := lastLat.Load(); lat != "48.856600" {
+		t.Errorf("refreshed latitude = %v, want 48.856600", lat)
+	}
+}
+
+func TestClientCurrentByCityCached(t *testing.T) {
+	body, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var geocodes, currents atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/geo/1.0/direct":
+			geocodes.Add(1)
+			w.Write([]byte(`[{"name":"Paris","lat":48.8566,"lon":2.3522,"country":"FR"}]`))
+		case "/data/2.5/weather":
+			currents.Add(1)
+			w.Write(body)
+		}
+	})
+	c.CurrentCache = cache.New[*Conditions](10 * time.Minute)
+	c.CityCache = cache.New[Location](24 * time.Hour)
+
+	got, location, err := c.CurrentByCity(context.Background(), "Paris")
+	if err != nil {
+		t.Fatalf("CurrentByCity() error = %v", err)
+	}
+	if got.City != "Paris" || location.Country != "FR" {
+		t.Errorf("CurrentByCity() = %v, %v", got, location)
+	}
+	if geocodes.Load() != 1 || currents.Load() != 1 {
+		t.Fatalf("the first lookup made %d geocoding and %d weather requests, want 1 and 1", geocodes.Load(), currents.Load())
+	}
+
+	// the same city spelled differently, and its coordinates, are hits
+	for _, city := range []string{"Paris", "  paris ", "PARIS"} {
+		again, _, err := c.CurrentByCity(context.Background(), city)
+		if err != nil || again != got {
+			t.Errorf("CurrentByCity(%q) = %v, %v, want the cached conditions", city, again, err)
+		}
+	}
+	if again, err := c.Current(context.Background(), 48.8566, 2.3522); err != nil || again != got {
+		t.Errorf("Current() = %v, %v, want the conditions cached by city", again, err)
+	}
+	if geocodes.Load() != 1 || currents.Load() != 1 {
+		t.Errorf("the cached lookups made %d geocoding and %d weather requests, want none", geocodes.Load()-1, currents.Load()-1)
+	}
+}
+
+func TestClientGeocode(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "London":
+			w.Write([]byte(`[{"name":"London","lat":51.5073219,"lon":-0.1276474,"country":"GB","state":"England"}]`))
+		case "Invalid":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"cod":401,"message":"Invalid API key"}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	})
+
+	locations, err := c.Geocode(context.Background(), "London", 1)
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if got := locations[0].String(); got != "London, England, GB" {
+		t.Errorf("Geocode() = %s, want London, England, GB", got)
+	}
+
+	if _, err := c.Geocode(context.Background(), "Atlantis", 1); !errors.Is(err, ErrCityNotFound) {
+		t.Errorf("Geocode() error = %v, want ErrCityNotFound", err)
+	}
+
+	var statusErr *StatusError
+	if _, err := c.Geocode(context.Background(), "Invalid", 1); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Geocode() error = %v, want 401 StatusError", err)
+	}
+}
+
+func TestClientCurrentByCityDisambiguation(t *testing.T) {
+	current, err := os.ReadFile("testdata/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures := map[string]string{
+		"Paris":       "testdata/geocode_paris.json",
+		"Paris,TX,US": "testdata/geocode_paris_tx.json",
+		"Paris,FR":    "testdata/geocode_paris_fr.json",
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data/2.5/weather" {
+			w.Write(current)
+			return
+		}
+		if got := r.URL.Query().Get("limit"); got != "5" {
+			t.Errorf("geocoding limit = %s, want 5", got)
+		}
+		if name, ok := fixtures[r.URL.Query().Get("q")]; ok {
+			http.ServeFile(w, r, name)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		for _, city := range []string{"Paris", " Paris ", "Paris, France"} {
+			_, _, err := c.CurrentByCity(context.Background(), city)
+			var ambiguous *AmbiguousCityError
+			if !errors.As(err, &ambiguous) {
+				t.Fatalf("CurrentByCity(%q) error = %v, want AmbiguousCityError", city, err)
+			}
+			// the second match in Paris, FR and the district are the same place
+			var got []string
+			for _, l := range ambiguous.Candidates {
+				got = append(got, l.String())
+			}
+			if want := "Paris, Ile-de-France, FR|Paris, Texas, US|Paris, Tennessee, US"; strings.Join(got, "|") != want {
+				t.Errorf("CurrentByCity(%q) candidates = %v, want %s", city, got, want)
+			}
+		}
+
+		_, _, err := c.CurrentByCity(context.Background(), "Paris")
+		want := `"Paris" matches 3 places: Paris, Ile-de-France, FR (48.8589,2.3200); Paris, Texas, US (33.6618,-95.5555); Paris, Tennessee, US (36.3020,-88.3267)`
+		if err == nil || err.Error() != want {
+			t.Errorf("CurrentByCity() error = %v, want %s", err, want)
+		}
+	})
+
+	t.Run("single match", func(t *testing.T) {
+		tests := []struct {
+			city string
+			want string
+		}{
+			{"Paris, TX", "Paris, Texas, US"},
+			{"Paris, Texas, US", "Paris, Texas, US"},
+			{"Paris, fr", "Paris, Ile-de-France, FR"},
+			{"Paris, Ile-de-France, FR", "Paris, Ile-de-France, FR"},
+			// the qualifier picks among the matches of the name alone
+			{"Paris, Ile-de-France", "Paris, Ile-de-France, FR"},
+		}
+		for _, tt := range tests {
+			conditions, location, err := c.CurrentByCity(context.Background(), tt.city)
+			if err != nil {
+				t.Errorf("CurrentByCity(%q) error = %v", tt.city, err)
+				continue
+			}
+			if location.String() != tt.want || conditions.City != "Paris" {
+				t.Errorf("CurrentByCity(%q) = %s, want %s", tt.city, location, tt.want)
+			}
+		}
+	})
+}
+
+func TestCandidatesWithoutExactName(t *testing.T) {
+	// the API matches local names, e.g. München for Munich
+	locations := []Location{
+		{Name: "Munich", Country: "DE", State: "Bavaria", Latitude: 48.1371, Longitude: 11.5754},
+		{Name: "Munich", Country: "US", State: "North Dakota", Latitude: 48.6689, Longitude: -98.8271},
+	}
+	got := parseCityQuery("München").candidates(locations)
+	if len(got) != 1 || got[0].Country != "DE" {
+		t.Errorf("candidates() = %v, want the best match only", got)
+	}
+}
+
+func TestParseTimeMachine(t *testing.T) {
+	body, err := os.ReadFile("testdata/timemachine.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ParseTimeMachine(body)
+	if err != nil {
+		t.Fatalf("ParseTimeMachine() error = %v", err)
+	}
+	want := "light rain, 6.1°C (feels like 3.4°C), humidity 64%, wind 3.6 m/s, clouds 75%, rain 0.2 mm in the last hour"
+	if got := c.Summary(); got != want {
+		t.Errorf("Summary() = %s, want %s", got, want)
+	}
+
+	if _, err := ParseTimeMachine([]byte(`{"lat":1,"lon":2,"data":[]}`)); err == nil {
+		t.Error("ParseTimeMachine() with no data should fail")
+	}
+}
+
+func TestParseDaily(t *testing.T) {
+	body, err := os.ReadFile("testdata/daily.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	days, err := ParseDaily(body)
+	if err != nil {
+		t.Fatalf("ParseDaily() error = %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("ParseDaily() returned %d days, want 2", len(days))
+	}
+	if got := days[0].Date.Format("2006-01-02 15:04"); got != "2024-08-07 12:00" {
+		t.Errorf("date = %s, want 2024-08-07 12:00", got)
+	}
+	if d := days[0]; d.TempMax != 30.1 || d.UVIndex != 8.1 || d.WindSpeed != 4.2 || d.Description != "clear sky" {
+		t.Errorf("days[0] = %+v", d)
+	}
+	if d := days[1]; d.PrecipitationChance != 0.87 || d.Rain != 6.4 || d.ConditionID != 501 {
+		t.Errorf("days[1] = %+v", d)
+	}
+
+	if _, err := ParseDaily([]byte(`{"lat":1,"lon":2,"daily":[]}`)); err == nil {
+		t.Error("ParseDaily() with no days should fail")
+	}
+}
+
+func TestParseDaySummaryPrecipitation(t *testing.T) {
+	body, err := os.ReadFile("testdata/day_summary.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseDaySummaryPrecipitation(body)
+	if err != nil {
+		t.Fatalf("ParseDaySummaryPrecipitation() error = %v", err)
+	}
+	if got != 4.2 {
+		t.Errorf("ParseDaySummaryPrecipitation() = %v, want 4.2", got)
+	}
+
+	if _, err := ParseDaySummaryPrecipitation([]byte(`{"lat":1,"lon":2,"date":"2024-08-05"}`)); err == nil {
+		t.Error("ParseDaySummaryPrecipitation() with no precipitation should fail")
+	}
+}
+
+func TestParseUVIndex(t *testing.T) {
+	uvi, err := ParseUVIndex([]byte(`{"lat":43.2965,"lon":5.3698,"current":{"dt":1723024800,"temp":29.1,"uvi":7.42,"clouds":3}}`))
+	if err != nil {
+		t.Fatalf("ParseUVIndex() error = %v", err)
+	}
+	if uvi != 7.42 {
+		t.Errorf("ParseUVIndex() = %v, want 7.42", uvi)
+	}
+
+	if _, err := ParseUVIndex([]byte(`{"lat":1,"lon":2}`)); err == nil {
+		t.Error("ParseUVIndex() with no current weather should fail")
+	}
+}
+
+func TestUVRisk(t *testing.T) {
+	tests := []struct {
+		uvi  float64
+		want string
+	}{
+		{0, "low"},
+		{2, "low"},
+		{2.49, "low"},
+		{2.5, "moderate"},
+		{3, "moderate"},
+		{5, "moderate"},
+		{5.49, "moderate"},
+		{5.5, "high"},
+		{6, "high"},
+		{7, "high"},
+		{7.49, "high"},
+		{7.5, "very high"},
+		{8, "very high"},
+		{10, "very high"},
+		{10.49, "very high"},
+		{10.5, "extreme"},
+		{11, "extreme"},
+		{16.2, "extreme"},
+	}
+	for _, tt := range tests {
+		if got, advice := UVRisk(tt.uvi); got != tt.want || advice == "" {
+			t.Errorf("UVRisk(%v) = %s, %q, want %s", tt.uvi, got, advice, tt.want)
+		}
+	}
+}
+
+func TestParseAlerts(t *testing.T) {
+	body, err := os.ReadFile("testdata/alerts.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts, err := ParseAlerts(body)
+	if err != nil {
+		t.Fatalf("ParseAlerts() error = %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("ParseAlerts() = %d alerts, want 1", len(alerts))
+	}
+	a := alerts[0]
+	if a.Sender != "METEO-FRANCE" || a.Event != "Moderate thunderstorm warning" {
+		t.Errorf("ParseAlerts() = %+v", a)
+	}
+	if got := a.Start.Format("2006-01-02 15:04"); got != "2024-08-07 16:00" {
+		t.Errorf("the alert starts at %s local, want 2024-08-07 16:00", got)
+	}
+
+	// the section is left out when there are no alerts
+	alerts, err = ParseAlerts([]byte(`{"lat":43.3,"lon":5.37,"timezone_offset":7200}`))
+	if err != nil || len(alerts) != 0 {
+		t.Errorf("ParseAlerts() without alerts = %v, %v, want none", alerts, err)
+	}
+}
+
+func TestParseAirQuality(t *testing.T) {
+	body, err := os.ReadFile("testdata/air_pollution.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aq, err := ParseAirQuality(body)
+	if err != nil {
+		t.Fatalf("ParseAirQuality() error = %v", err)
+	}
+	if aq.Index != 3 || aq.Level() != "moderate" || aq.PM25 != 14.35 || aq.NO2 != 21.42 {
+		t.Errorf("ParseAirQuality() = %+v, %s", aq, aq.Level())
+	}
+	if _, err := ParseAirQuality([]byte(`{"list":[]}`)); err == nil {
+		t.Error("ParseAirQuality() of an empty list should fail")
+	}
+	if got := (AirQuality{Index: 9}).Level(); got != "unknown" {
+		t.Errorf("Level() of index 9 = %s, want unknown", got)
+	}
+}
+
+func TestParseForecast(t *testing.T) {
+	body, err := os.ReadFile("testdata/forecast.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ParseForecast(body)
+	if err != nil {
+		t.Fatalf("ParseForecast() error = %v", err)
+	}
+	if f.City != "Paris" || f.TimezoneOffset != 7200 || len(f.Slots) != 4 {
+		t.Fatalf("ParseForecast() = %+v", f)
+	}
+
+	slot := f.Slots[2]
+	if got := slot.Time.In(f.Location()).Format("2006-01-02 15:04"); got != "2024-08-07 20:00" {
+		t.Errorf("slot time = %s, want 2024-08-07 20:00", got)
+	}
+	want := "Paris, FR: light rain, 20.2°C (feels like 20.4°C), humidity 78%, wind 5.6 m/s, clouds 90%, 64% chance of precipitation, rain 1.4 mm"
+	if got := slot.Summary(); got != want {
+		t.Errorf("Summary() = %s, want %s", got, want)
+	}
+}
+
+func TestTileURL(t *testing.T) {
+	t.Setenv(APIKeyEnv, "test-key")
+
+	got, err := TileURL("precipitation", 10, 511, 340)
+	if err != nil {
+		t.Fatalf("TileURL() error = %v", err)
+	}
+	want := "https://tile.openweathermap.org/map/precipitation_new/10/511/340.png"
+	if got != want {
+		t.Errorf("TileURL() = %s, want %s", got, want)
+	}
+	// the URL is shown to the user, the key of the deployment stays secret
+	if strings.Contains(got, "appid") || strings.Contains(got, "test-key") {
+		t.Errorf("TileURL() = %s has the API key", got)
+	}
+
+	if _, err := TileURL("radar", 10, 511, 340); err == nil {
+		t.Error("TileURL() should reject an unknown layer")
+	}
+}